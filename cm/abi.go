@@ -1,6 +1,12 @@
 package cm
 
-import "unsafe"
+import (
+	"errors"
+	"math"
+	"strings"
+	"unicode/utf8"
+	"unsafe"
+)
 
 // AnyInteger is a type constraint for any integer type.
 type AnyInteger interface {
@@ -9,43 +15,159 @@ type AnyInteger interface {
 
 // Reinterpret reinterprets the bits of type From into type T.
 // Will panic if the size of From is smaller than the size of To.
+//
+// If the alignment of T is greater than the alignment of From, the bits are copied
+// rather than loaded through a pointer to from, which may not be suitably aligned for T
+// on architectures that require aligned memory access.
 func Reinterpret[T, From any](from From) (to T) {
 	if unsafe.Sizeof(to) > unsafe.Sizeof(from) {
 		panic("reinterpret: size of to > from")
 	}
-	return *(*T)(unsafe.Pointer(&from))
+	if unsafe.Alignof(to) <= unsafe.Alignof(from) {
+		return *(*T)(unsafe.Pointer(&from))
+	}
+	n := unsafe.Sizeof(to)
+	copy(unsafe.Slice((*byte)(unsafe.Pointer(&to)), n), unsafe.Slice((*byte)(unsafe.Pointer(&from)), n))
+	return to
 }
 
 // LowerString lowers a [string] into a pair of Core WebAssembly types.
 //
 // [string]: https://pkg.go.dev/builtin#string
 func LowerString[S ~string](s S) (*byte, uint32) {
-	return unsafe.StringData(string(s)), uint32(len(s))
+	data := unsafe.StringData(string(s))
+	if traceEnabled && Tracing() {
+		traceMemory("lower", "", unsafe.Pointer(data), uintptr(len(s)), 1)
+	}
+	return data, uint32(len(s))
 }
 
 // LiftString lifts Core WebAssembly types into a [string].
 func LiftString[T ~string, Data unsafe.Pointer | uintptr | *uint8, Len AnyInteger](data Data, len Len) T {
+	if traceEnabled && Tracing() {
+		traceMemory("lift", "", unsafe.Pointer(data), uintptr(len), 1)
+	}
 	return T(unsafe.String((*uint8)(unsafe.Pointer(data)), int(len)))
 }
 
+// ErrInvalidUTF8 is returned when checking a string that is not valid UTF-8.
+// Component Model strings are sequences of Unicode scalar values, while a Go string
+// may contain arbitrary bytes.
+var ErrInvalidUTF8 = errors.New("cm: string is not valid UTF-8")
+
+// LowerStringChecked lowers a [string] into a pair of Core WebAssembly types, like [LowerString].
+// It returns [ErrInvalidUTF8] if s is not valid UTF-8, rather than passing it to the host,
+// which may trap on an invalid string.
+func LowerStringChecked[S ~string](s S) (*byte, uint32, error) {
+	if !utf8.ValidString(string(s)) {
+		return nil, 0, ErrInvalidUTF8
+	}
+	data, n := LowerString(s)
+	return data, n, nil
+}
+
+// LowerStringStrict lowers a [string] into a pair of Core WebAssembly types, like [LowerString].
+// It panics with [ErrInvalidUTF8] if s is not valid UTF-8.
+func LowerStringStrict[S ~string](s S) (*byte, uint32) {
+	if !utf8.ValidString(string(s)) {
+		panic(ErrInvalidUTF8)
+	}
+	return LowerString(s)
+}
+
+// LowerStringLossy lowers a [string] into a pair of Core WebAssembly types, like [LowerString].
+// If s is not valid UTF-8, it lowers a copy of s with each run of invalid bytes replaced
+// with the Unicode replacement character U+FFFD.
+func LowerStringLossy[S ~string](s S) (*byte, uint32) {
+	if !utf8.ValidString(string(s)) {
+		return LowerString(strings.ToValidUTF8(string(s), string(utf8.RuneError)))
+	}
+	return LowerString(s)
+}
+
+// LiftStringChecked lifts Core WebAssembly types into a [string], like [LiftString].
+// It returns [ErrInvalidUTF8] if the string is not valid UTF-8.
+func LiftStringChecked[T ~string, Data unsafe.Pointer | uintptr | *uint8, Len AnyInteger](data Data, len Len) (T, error) {
+	s := LiftString[T](data, len)
+	if !utf8.ValidString(string(s)) {
+		return "", ErrInvalidUTF8
+	}
+	return s, nil
+}
+
 // LowerList lowers a [List] into a pair of Core WebAssembly types.
 func LowerList[L AnyList[T], T any](list L) (*T, uint32) {
 	l := (*List[T])(unsafe.Pointer(&list))
+	if traceEnabled && Tracing() {
+		var zero T
+		traceMemory("lower", *l, unsafe.Pointer(l.data), l.len, unsafe.Sizeof(zero))
+	}
 	return l.data, uint32(l.len)
 }
 
 // LiftList lifts Core WebAssembly types into a [List].
 func LiftList[L AnyList[T], T any, Data unsafe.Pointer | uintptr | *T, Len AnyInteger](data Data, len Len) L {
+	if traceEnabled && Tracing() {
+		var zero T
+		traceMemory("lift", List[T]{}, unsafe.Pointer(data), uintptr(len), unsafe.Sizeof(zero))
+	}
 	return L(NewList((*T)(unsafe.Pointer(data)), len))
 }
 
+// LiftEnum lifts a Core WebAssembly i32 discriminant into enum type T with cases cases.
+// It panics if v is out of range, which traps as specified in the [Canonical ABI],
+// rather than returning an enum value that is not a case of T.
+//
+// [Canonical ABI]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md
+func LiftEnum[T ~uint8 | ~uint16 | ~uint32, V AnyInteger](v V, cases uint32) T {
+	if uint64(v) >= uint64(cases) {
+		panic("lift enum: discriminant out of range")
+	}
+	return T(v)
+}
+
+// IsValidChar returns true if r is a Unicode scalar value, which is a valid WIT char:
+// a code point in the range [0, 0x10FFFF], excluding the surrogate code points [0xD800, 0xDFFF].
+func IsValidChar[T ~int32](r T) bool {
+	return utf8.ValidRune(rune(r))
+}
+
+// LowerChar lowers a WIT char into a Core WebAssembly i32.
+// It panics if r is not a Unicode scalar value (see [IsValidChar]), rather than passing
+// an invalid char to the host, which traps as specified in the [Canonical ABI].
+//
+// [Canonical ABI]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md
+func LowerChar[T ~int32](r T) uint32 {
+	if !IsValidChar(r) {
+		panic("lower char: invalid Unicode scalar value")
+	}
+	return uint32(r)
+}
+
+// LiftChar lifts a Core WebAssembly i32 into WIT char type T.
+// It panics if v is not a Unicode scalar value (see [IsValidChar]), which traps
+// as specified in the [Canonical ABI].
+//
+// [Canonical ABI]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md
+func LiftChar[T ~int32, V AnyInteger](v V) T {
+	if uint64(v) > utf8.MaxRune || !IsValidChar(rune(v)) {
+		panic("lift char: invalid Unicode scalar value")
+	}
+	return T(v)
+}
+
 // BoolToU32 converts a value whose underlying type is [bool] into a [uint32].
 // Used to lower a [bool] into a Core WebAssembly i32 as specified in the [Canonical ABI].
 //
 // [bool]: https://pkg.go.dev/builtin#bool
 // [uint32]: https://pkg.go.dev/builtin#uint32
 // [Canonical ABI]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md
-func BoolToU32[B ~bool](v B) uint32 { return uint32(*(*uint8)(unsafe.Pointer(&v))) }
+func BoolToU32[B ~bool](v B) uint32 {
+	if v {
+		return 1
+	}
+	return 0
+}
 
 // U32ToBool converts a [uint32] into a [bool].
 // Used to lift a Core WebAssembly i32 into a [bool] as specified in the [Canonical ABI].
@@ -53,7 +175,7 @@ func BoolToU32[B ~bool](v B) uint32 { return uint32(*(*uint8)(unsafe.Pointer(&v)
 // [uint32]: https://pkg.go.dev/builtin#uint32
 // [bool]: https://pkg.go.dev/builtin#bool
 // [Canonical ABI]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md
-func U32ToBool(v uint32) bool { tmp := uint8(v); return *(*bool)(unsafe.Pointer(&tmp)) }
+func U32ToBool(v uint32) bool { return uint8(v) != 0 }
 
 // F32ToU32 maps the bits of a [float32] into a [uint32].
 // Used to lower a [float32] into a Core WebAssembly i32 as specified in the [Canonical ABI].
@@ -61,7 +183,7 @@ func U32ToBool(v uint32) bool { tmp := uint8(v); return *(*bool)(unsafe.Pointer(
 // [Canonical ABI]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md
 // [float32]: https://pkg.go.dev/builtin#float32
 // [uint32]: https://pkg.go.dev/builtin#uint32
-func F32ToU32(v float32) uint32 { return *(*uint32)(unsafe.Pointer(&v)) }
+func F32ToU32(v float32) uint32 { return math.Float32bits(v) }
 
 // U32ToF32 maps the bits of a [uint32] into a [float32].
 // Used to lift a Core WebAssembly i32 into a [float32] as specified in the [Canonical ABI].
@@ -69,7 +191,7 @@ func F32ToU32(v float32) uint32 { return *(*uint32)(unsafe.Pointer(&v)) }
 // [uint32]: https://pkg.go.dev/builtin#uint32
 // [float32]: https://pkg.go.dev/builtin#float32
 // [Canonical ABI]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md
-func U32ToF32(v uint32) float32 { return *(*float32)(unsafe.Pointer(&v)) }
+func U32ToF32(v uint32) float32 { return math.Float32frombits(v) }
 
 // F64ToU64 maps the bits of a [float64] into a [uint64].
 // Used to lower a [float64] into a Core WebAssembly i64 as specified in the [Canonical ABI].
@@ -79,7 +201,7 @@ func U32ToF32(v uint32) float32 { return *(*float32)(unsafe.Pointer(&v)) }
 // [Canonical ABI]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md
 //
 // [uint32]: https://pkg.go.dev/builtin#uint32
-func F64ToU64(v float64) uint64 { return *(*uint64)(unsafe.Pointer(&v)) }
+func F64ToU64(v float64) uint64 { return math.Float64bits(v) }
 
 // U64ToF64 maps the bits of a [uint64] into a [float64].
 // Used to lift a Core WebAssembly i64 into a [float64] as specified in the [Canonical ABI].
@@ -87,7 +209,7 @@ func F64ToU64(v float64) uint64 { return *(*uint64)(unsafe.Pointer(&v)) }
 // [uint64]: https://pkg.go.dev/builtin#uint64
 // [float64]: https://pkg.go.dev/builtin#float64
 // [Canonical ABI]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md
-func U64ToF64(v uint64) float64 { return *(*float64)(unsafe.Pointer(&v)) }
+func U64ToF64(v uint64) float64 { return math.Float64frombits(v) }
 
 // F32ToU64 maps the bits of a [float32] into a [uint64].
 // Used to lower a [float32] into a Core WebAssembly i64 when required by the [Canonical ABI].
@@ -95,7 +217,7 @@ func U64ToF64(v uint64) float64 { return *(*float64)(unsafe.Pointer(&v)) }
 // [float32]: https://pkg.go.dev/builtin#float32
 // [uint64]: https://pkg.go.dev/builtin#uint64
 // [Canonical ABI]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md
-func F32ToU64(v float32) uint64 { return uint64(*(*uint32)(unsafe.Pointer(&v))) }
+func F32ToU64(v float32) uint64 { return uint64(math.Float32bits(v)) }
 
 // U64ToF32 maps the bits of a [uint64] into a [float32].
 // Used to lift a Core WebAssembly i64 into a [float32] when required by the [Canonical ABI].
@@ -103,24 +225,7 @@ func F32ToU64(v float32) uint64 { return uint64(*(*uint32)(unsafe.Pointer(&v)))
 // [uint64]: https://pkg.go.dev/builtin#uint64
 // [float32]: https://pkg.go.dev/builtin#float32
 // [Canonical ABI]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md
-func U64ToF32(v uint64) float32 {
-	truncated := uint32(v)
-	return *(*float32)(unsafe.Pointer(&truncated))
-}
-
-// PointerToU32 converts a pointer of type *T into a [uint32].
-// Used to lower a pointer into a Core WebAssembly i32 as specified in the [Canonical ABI].
-//
-// [uint32]: https://pkg.go.dev/builtin#uint32
-// [Canonical ABI]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md
-func PointerToU32[T any](v *T) uint32 { return uint32(uintptr(unsafe.Pointer(v))) }
-
-// U32ToPointer converts a [uint32] into a pointer of type *T.
-// Used to lift a Core WebAssembly i32 into a pointer as specified in the [Canonical ABI].
-//
-// [uint32]: https://pkg.go.dev/builtin#uint32
-// [Canonical ABI]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md
-func U32ToPointer[T any](v uint32) *T { return (*T)(unsafePointer(uintptr(v))) }
+func U64ToF32(v uint64) float32 { return math.Float32frombits(uint32(v)) }
 
 // PointerToU64 converts a pointer of type *T into a [uint64].
 // Used to lower a pointer into a Core WebAssembly i64 as specified in the [Canonical ABI].
@@ -0,0 +1,63 @@
+//go:build !wasm
+
+package cm
+
+import (
+	"sync"
+	"unsafe"
+)
+
+// On hosts other than WebAssembly, such as when testing generated bindings with go test,
+// native pointers may not fit in a Core WebAssembly i32. PointerToU32 stores pointers
+// in a table and returns their index, which U32ToPointer maps back to the pointer.
+// Pointers in the table are retained for the lifetime of the program.
+var hostPointers struct {
+	sync.Mutex
+	index    map[unsafe.Pointer]uint32
+	pointers []unsafe.Pointer
+}
+
+// PointerToU32 converts a pointer of type *T into a [uint32].
+// Used to lower a pointer into a Core WebAssembly i32 as specified in the [Canonical ABI].
+// On hosts other than WebAssembly, the result is an index into a table of pointers,
+// and can only be converted back into a pointer with [U32ToPointer].
+//
+// [uint32]: https://pkg.go.dev/builtin#uint32
+// [Canonical ABI]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md
+func PointerToU32[T any](v *T) uint32 {
+	if v == nil {
+		return 0
+	}
+	p := unsafe.Pointer(v)
+	hostPointers.Lock()
+	defer hostPointers.Unlock()
+	if i, ok := hostPointers.index[p]; ok {
+		return i
+	}
+	if hostPointers.index == nil {
+		hostPointers.index = make(map[unsafe.Pointer]uint32)
+		hostPointers.pointers = []unsafe.Pointer{nil} // 0 is the nil pointer
+	}
+	i := uint32(len(hostPointers.pointers))
+	hostPointers.pointers = append(hostPointers.pointers, p)
+	hostPointers.index[p] = i
+	return i
+}
+
+// U32ToPointer converts a [uint32] into a pointer of type *T.
+// Used to lift a Core WebAssembly i32 into a pointer as specified in the [Canonical ABI].
+// On hosts other than WebAssembly, v must be a value returned by [PointerToU32].
+//
+// [uint32]: https://pkg.go.dev/builtin#uint32
+// [Canonical ABI]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md
+func U32ToPointer[T any](v uint32) *T {
+	if v == 0 {
+		return nil
+	}
+	hostPointers.Lock()
+	defer hostPointers.Unlock()
+	if int(v) >= len(hostPointers.pointers) {
+		panic("cm: U32ToPointer: invalid host pointer")
+	}
+	return (*T)(hostPointers.pointers[v])
+}
@@ -0,0 +1,29 @@
+//go:build !wasm
+
+package cm
+
+import "testing"
+
+func TestHostPointers(t *testing.T) {
+	if got, want := PointerToU32[string](nil), uint32(0); got != want {
+		t.Errorf("PointerToU32(nil): %d, expected %d", got, want)
+	}
+	if got := U32ToPointer[string](0); got != nil {
+		t.Errorf("U32ToPointer(0): %p, expected nil", got)
+	}
+
+	a, b := "a", "b"
+	pa, pb := PointerToU32(&a), PointerToU32(&b)
+	if pa == 0 || pa == pb {
+		t.Errorf("PointerToU32: %d, %d, expected distinct non-zero values", pa, pb)
+	}
+	if got, want := PointerToU32(&a), pa; got != want {
+		t.Errorf("PointerToU32(&a): %d, expected %d", got, want)
+	}
+	if got, want := U32ToPointer[string](pa), &a; got != want {
+		t.Errorf("U32ToPointer(%d): %p, expected %p", pa, got, want)
+	}
+	if got, want := *U32ToPointer[string](pb), b; got != want {
+		t.Errorf("*U32ToPointer(%d): %q, expected %q", pb, got, want)
+	}
+}
@@ -1,8 +1,10 @@
 package cm
 
 import (
+	"encoding/binary"
 	"math"
 	"testing"
+	"unsafe"
 )
 
 func TestIntConversions(t *testing.T) {
@@ -81,3 +83,181 @@ type Integers interface {
 type CoreIntegers interface {
 	uint32 | uint64
 }
+
+func TestFloatConversions(t *testing.T) {
+	for _, f := range []float32{0, -0.0, 1, -1.5, math.MaxFloat32, math.SmallestNonzeroFloat32, float32(math.Inf(1)), float32(math.NaN())} {
+		if got, want := F32ToU32(f), math.Float32bits(f); got != want {
+			t.Errorf("F32ToU32(%v): %#x, expected %#x", f, got, want)
+		}
+		if got, want := F32ToU64(f), uint64(math.Float32bits(f)); got != want {
+			t.Errorf("F32ToU64(%v): %#x, expected %#x", f, got, want)
+		}
+		if got, want := math.Float32bits(U32ToF32(F32ToU32(f))), math.Float32bits(f); got != want {
+			t.Errorf("U32ToF32(F32ToU32(%v)): %#x, expected %#x", f, got, want)
+		}
+		if got, want := math.Float32bits(U64ToF32(0xffffffff00000000|F32ToU64(f))), math.Float32bits(f); got != want {
+			t.Errorf("U64ToF32(F32ToU64(%v)): %#x, expected %#x", f, got, want)
+		}
+	}
+	for _, f := range []float64{0, -0.0, 1, -1.5, math.MaxFloat64, math.SmallestNonzeroFloat64, math.Inf(-1), math.NaN()} {
+		if got, want := F64ToU64(f), math.Float64bits(f); got != want {
+			t.Errorf("F64ToU64(%v): %#x, expected %#x", f, got, want)
+		}
+		if got, want := math.Float64bits(U64ToF64(F64ToU64(f))), math.Float64bits(f); got != want {
+			t.Errorf("U64ToF64(F64ToU64(%v)): %#x, expected %#x", f, got, want)
+		}
+	}
+}
+
+func TestBoolConversions(t *testing.T) {
+	type myBool bool
+	if got, want := BoolToU32(true), uint32(1); got != want {
+		t.Errorf("BoolToU32(true): %d, expected %d", got, want)
+	}
+	if got, want := BoolToU32(myBool(false)), uint32(0); got != want {
+		t.Errorf("BoolToU32(false): %d, expected %d", got, want)
+	}
+	for _, tt := range []struct {
+		v    uint32
+		want bool
+	}{{0, false}, {1, true}, {0x100, false}, {0x101, true}} {
+		if got := U32ToBool(tt.v); got != tt.want {
+			t.Errorf("U32ToBool(%#x): %t, expected %t", tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestLiftEnum(t *testing.T) {
+	type color uint8
+	if got, want := LiftEnum[color](uint32(2), 3), color(2); got != want {
+		t.Errorf("LiftEnum(2, 3): %d, expected %d", got, want)
+	}
+	for _, v := range []int64{3, 0x103, -1} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("LiftEnum(%d, 3): expected panic", v)
+				}
+			}()
+			LiftEnum[color](v, 3)
+		}()
+	}
+}
+
+func TestChar(t *testing.T) {
+	tests := []struct {
+		v     int64
+		valid bool
+	}{
+		{0, true},
+		{'a', true},
+		{'日', true},
+		{0xD7FF, true},
+		{0xD800, false},
+		{0xDFFF, false},
+		{0xE000, true},
+		{0x10FFFF, true},
+		{0x110000, false},
+		{-1, false},
+		{0x1_0000_0041, false},
+	}
+	for _, tt := range tests {
+		if tt.v >= math.MinInt32 && tt.v <= math.MaxInt32 {
+			r := rune(tt.v)
+			if got := IsValidChar(r); got != tt.valid {
+				t.Errorf("IsValidChar(%#x): %t, expected %t", tt.v, got, tt.valid)
+			}
+			func() {
+				defer func() {
+					if r := recover(); tt.valid != (r == nil) {
+						t.Errorf("LowerChar(%#x): recovered %v", tt.v, r)
+					}
+				}()
+				if got := LowerChar(r); got != uint32(r) {
+					t.Errorf("LowerChar(%#x): %#x", tt.v, got)
+				}
+			}()
+		}
+		func() {
+			defer func() {
+				if r := recover(); tt.valid != (r == nil) {
+					t.Errorf("LiftChar(%#x): recovered %v", tt.v, r)
+				}
+			}()
+			if got := LiftChar[rune](tt.v); int64(got) != tt.v {
+				t.Errorf("LiftChar(%#x): %#x", tt.v, got)
+			}
+		}()
+	}
+}
+
+func TestLowerStringChecked(t *testing.T) {
+	tests := []struct {
+		s     string
+		valid bool
+		lossy string
+	}{
+		{"", true, ""},
+		{"hello", true, "hello"},
+		{"日本語", true, "日本語"},
+		{"a\xffb", false, "a\uFFFDb"},
+		{"\xed\xa0\x80", false, "\uFFFD"}, // surrogate half
+		{"abc\xe6\x97", false, "abc\uFFFD"},
+	}
+	for _, tt := range tests {
+		data, n, err := LowerStringChecked(tt.s)
+		if tt.valid {
+			if err != nil {
+				t.Errorf("LowerStringChecked(%q): %v", tt.s, err)
+			}
+			if got := LiftString[string](data, n); got != tt.s {
+				t.Errorf("LowerStringChecked(%q): lowered %q", tt.s, got)
+			}
+		} else if err != ErrInvalidUTF8 {
+			t.Errorf("LowerStringChecked(%q): err == %v, expected %v", tt.s, err, ErrInvalidUTF8)
+		}
+
+		if got, err := LiftStringChecked[string](unsafe.StringData(tt.s), len(tt.s)); tt.valid && (err != nil || got != tt.s) {
+			t.Errorf("LiftStringChecked(%q): %q, %v", tt.s, got, err)
+		} else if !tt.valid && err != ErrInvalidUTF8 {
+			t.Errorf("LiftStringChecked(%q): err == %v, expected %v", tt.s, err, ErrInvalidUTF8)
+		}
+
+		data, n = LowerStringLossy(tt.s)
+		if got := LiftString[string](data, n); got != tt.lossy {
+			t.Errorf("LowerStringLossy(%q): %q, expected %q", tt.s, got, tt.lossy)
+		}
+
+		func() {
+			defer func() {
+				if r := recover(); tt.valid != (r == nil) {
+					t.Errorf("LowerStringStrict(%q): recovered %v", tt.s, r)
+				}
+			}()
+			LowerStringStrict(tt.s)
+		}()
+	}
+}
+
+// TestReinterpretAlignment reinterprets values into types with stricter alignment.
+// Run with GOARCH set to an architecture that faults on unaligned access, such as
+// GOARCH=mips or GOARCH=arm GOARM=5, to verify that Reinterpret does not perform unaligned loads.
+func TestReinterpretAlignment(t *testing.T) {
+	var packed struct {
+		_ byte
+		b [9]byte
+	}
+	packed.b = [9]byte{1, 2, 3, 4, 5, 6, 7, 8, 9}
+	if got, want := Reinterpret[uint64](packed.b), binary.NativeEndian.Uint64(packed.b[:]); got != want {
+		t.Errorf("Reinterpret[uint64](% x): %#x, expected %#x", packed.b, got, want)
+	}
+	if got, want := Reinterpret[uint32]([4]uint8{0xff, 0xff, 0xff, 0xff}), uint32(math.MaxUint32); got != want {
+		t.Errorf("Reinterpret[uint32]: %#x, expected %#x", got, want)
+	}
+	if got, want := Reinterpret[float64](math.Float64bits(1.5)), 1.5; got != want {
+		t.Errorf("Reinterpret[float64]: %v, expected %v", got, want)
+	}
+	if got, want := Reinterpret[int32](uint32(math.MaxUint32)), int32(-1); got != want {
+		t.Errorf("Reinterpret[int32]: %d, expected %d", got, want)
+	}
+}
@@ -0,0 +1,120 @@
+package cm
+
+import "unsafe"
+
+// Arena is a bump allocator for temporary values that are lowered into linear memory
+// for the duration of a call, such as the flattened parameters of an imported function.
+// Allocating temporaries from an Arena instead of the Go heap reduces garbage collector
+// pressure in high-frequency calls, such as streaming writes. Memory allocated from an
+// Arena is reused after [Arena.Reset] or [Arena.Release].
+//
+// Memory in an Arena is not scanned by the garbage collector, so a value allocated from an
+// Arena must not hold the only reference to memory allocated by Go. An Arena is not safe for
+// concurrent use. A nil *Arena is valid, and allocates from the Go heap.
+type Arena struct {
+	buf []uint64 // 8-byte aligned storage
+	off uintptr  // offset in bytes of the first free byte in buf
+}
+
+// NewArena returns a new [Arena] that can allocate size bytes before
+// falling back to the Go heap.
+func NewArena(size int) *Arena {
+	return &Arena{buf: make([]uint64, (size+7)/8)}
+}
+
+// Len returns the number of bytes allocated from a.
+func (a *Arena) Len() int {
+	if a == nil {
+		return 0
+	}
+	return int(a.off)
+}
+
+// Cap returns the number of bytes a can allocate.
+func (a *Arena) Cap() int {
+	if a == nil {
+		return 0
+	}
+	return len(a.buf) * 8
+}
+
+// Reset frees all memory allocated from a.
+// Values previously allocated from a must not be used after Reset.
+func (a *Arena) Reset() {
+	if a == nil {
+		return
+	}
+	a.off = 0
+}
+
+// Mark returns the current allocation offset of a, which can be passed to [Arena.Release]
+// to free the memory allocated after the call to Mark. Calls to Mark and Release nest, which
+// allows an Arena to be used for the temporaries of a single call, or of a frame of calls.
+func (a *Arena) Mark() int {
+	return a.Len()
+}
+
+// Release frees the memory allocated from a since the call to [Arena.Mark] that returned mark.
+// Values allocated after that call to Mark must not be used after Release.
+func (a *Arena) Release(mark int) {
+	if a == nil || uintptr(mark) > a.off {
+		return
+	}
+	a.off = uintptr(mark)
+}
+
+// alloc returns a pointer to size zeroed bytes aligned to align,
+// or nil if a is nil or does not have enough free space.
+func (a *Arena) alloc(size, align uintptr) unsafe.Pointer {
+	if a == nil || align > unsafe.Alignof(uint64(0)) {
+		return nil
+	}
+	off := (a.off + align - 1) &^ (align - 1)
+	if off+size > uintptr(len(a.buf))*8 || off+size < off {
+		return nil
+	}
+	a.off = off + size
+	if size == 0 {
+		// Avoid returning a pointer past the end of buf.
+		return unsafe.Pointer(&zeroSized)
+	}
+	p := unsafe.Add(unsafe.Pointer(unsafe.SliceData(a.buf)), off)
+	clear(unsafe.Slice((*byte)(p), size))
+	return p
+}
+
+var zeroSized uint64
+
+// ArenaNew allocates a value of type T from a, initialized to v, and returns a pointer to it.
+// If a is nil or does not have enough free space, the value is allocated on the Go heap.
+func ArenaNew[T any](a *Arena, v T) *T {
+	p := (*T)(a.alloc(unsafe.Sizeof(v), unsafe.Alignof(v)))
+	if p == nil {
+		p = new(T)
+	}
+	*p = v
+	return p
+}
+
+// ArenaSlice allocates a slice of n zero values of type T from a.
+// If a is nil or does not have enough free space, the slice is allocated on the Go heap.
+func ArenaSlice[T any](a *Arena, n int) []T {
+	var zero T
+	size := unsafe.Sizeof(zero)
+	if n < 0 || (size > 0 && uintptr(n) > ^uintptr(0)/size) {
+		panic("cm: ArenaSlice: invalid length")
+	}
+	p := (*T)(a.alloc(size*uintptr(n), unsafe.Alignof(zero)))
+	if p == nil {
+		return make([]T, n)
+	}
+	return unsafe.Slice(p, n)
+}
+
+// ArenaList copies the elements of s into a [List] allocated from a.
+// If a is nil or does not have enough free space, the list is allocated on the Go heap.
+func ArenaList[S ~[]T, T any](a *Arena, s S) List[T] {
+	l := ArenaSlice[T](a, len(s))
+	copy(l, s)
+	return ToList(l)
+}
@@ -0,0 +1,97 @@
+package cm
+
+import (
+	"slices"
+	"testing"
+	"unsafe"
+)
+
+func TestArena(t *testing.T) {
+	a := NewArena(64)
+	if got, want := a.Cap(), 64; got != want {
+		t.Errorf("Cap(): %d, expected %d", got, want)
+	}
+
+	b := ArenaNew(a, uint8(1))
+	u := ArenaNew(a, uint64(2))
+	if got, want := uintptr(unsafe.Pointer(u))%unsafe.Alignof(*u), uintptr(0); got != want {
+		t.Errorf("ArenaNew[uint64]: misaligned by %d bytes", got)
+	}
+	if got, want := a.Len(), 16; got != want {
+		t.Errorf("Len(): %d, expected %d", got, want)
+	}
+	if *b != 1 || *u != 2 {
+		t.Errorf("ArenaNew: got %d, %d, expected 1, 2", *b, *u)
+	}
+
+	mark := a.Mark()
+	s := ArenaSlice[uint32](a, 4)
+	for i := range s {
+		s[i] = 0xffffffff
+	}
+	a.Release(mark)
+	if got, want := a.Len(), mark; got != want {
+		t.Errorf("Len() after Release: %d, expected %d", got, want)
+	}
+	s = ArenaSlice[uint32](a, 4)
+	if !slices.Equal(s, []uint32{0, 0, 0, 0}) {
+		t.Errorf("ArenaSlice after Release: %v, expected zeroed memory", s)
+	}
+
+	l := ArenaList(a, []uint16{1, 2, 3})
+	if got, want := l.Slice(), []uint16{1, 2, 3}; !slices.Equal(got, want) {
+		t.Errorf("ArenaList: %v, expected %v", got, want)
+	}
+
+	a.Reset()
+	if got, want := a.Len(), 0; got != want {
+		t.Errorf("Len() after Reset: %d, expected %d", got, want)
+	}
+}
+
+func TestArenaOverflow(t *testing.T) {
+	a := NewArena(8)
+	p := ArenaNew(a, [16]byte{1})
+	if p[0] != 1 {
+		t.Errorf("ArenaNew: %v, expected first byte 1", *p)
+	}
+	if got, want := a.Len(), 0; got != want {
+		t.Errorf("Len(): %d, expected %d for value allocated on the heap", got, want)
+	}
+	if got, want := len(ArenaSlice[uint64](a, 2)), 2; got != want {
+		t.Errorf("ArenaSlice: len %d, expected %d", got, want)
+	}
+	if got, want := len(ArenaSlice[uint64](a, 0)), 0; got != want {
+		t.Errorf("ArenaSlice: len %d, expected %d", got, want)
+	}
+}
+
+func TestArenaNil(t *testing.T) {
+	var a *Arena
+	mark := a.Mark()
+	if got, want := *ArenaNew(a, "hello"), "hello"; got != want {
+		t.Errorf("ArenaNew: %q, expected %q", got, want)
+	}
+	a.Release(mark)
+	a.Reset()
+	if a.Len() != 0 || a.Cap() != 0 {
+		t.Errorf("nil Arena: Len() = %d, Cap() = %d, expected 0", a.Len(), a.Cap())
+	}
+}
+
+func TestArenaAllocs(t *testing.T) {
+	type params struct {
+		a, b, c, d, e, f, g, h, i, j, k, l, m, n, o, p, q uint32
+	}
+	a := NewArena(1024)
+	var sink *params
+	allocs := testing.AllocsPerRun(100, func() {
+		mark := a.Mark()
+		sink = ArenaNew(a, params{a: 1, q: 2})
+		a.Release(mark)
+	})
+	if allocs != 0 {
+		t.Errorf("ArenaNew: %v allocations per call, expected 0", allocs)
+	}
+	_ = sink
+}
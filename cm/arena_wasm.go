@@ -0,0 +1,8 @@
+//go:build wasm
+
+package cm
+
+// DefaultArena is the [Arena] used by generated bindings to allocate the parameter structs passed
+// by pointer to imported functions (see bindgen.ArenaParams). Memory allocated for a call is released
+// when the call returns. It may be replaced with a larger Arena, or set to nil to allocate from the Go heap.
+var DefaultArena = NewArena(4096)
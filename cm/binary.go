@@ -0,0 +1,49 @@
+package cm
+
+import (
+	"errors"
+	"strconv"
+	"unsafe"
+)
+
+// littleEndian is true if the target stores integers in little-endian byte order,
+// as the Canonical ABI does.
+var littleEndian = func() bool {
+	x := uint16(1)
+	return *(*byte)(unsafe.Pointer(&x)) == 1
+}()
+
+// MarshalBinary returns a copy of the memory representation of *v, which is
+// the Canonical ABI memory layout of a Go type generated for a WIT type.
+// Bindings generated with the binary option implement [encoding.BinaryMarshaler]
+// with MarshalBinary for WIT types that contain no pointers or resource handles.
+//
+// Types that contain pointers, such as strings and lists, are not supported,
+// as their memory representation is only meaningful in the current process.
+// Padding bytes are copied as-is. MarshalBinary returns an error on big-endian targets.
+func MarshalBinary[T any](v *T) ([]byte, error) {
+	if !littleEndian {
+		return nil, errors.New("cm: MarshalBinary: unsupported big-endian target")
+	}
+	size := unsafe.Sizeof(*v)
+	data := make([]byte, size)
+	copy(data, unsafe.Slice((*byte)(unsafe.Pointer(v)), size))
+	return data, nil
+}
+
+// UnmarshalBinary copies data, the Canonical ABI memory layout of a value of type T,
+// into *v. It returns an error if the length of data is not the size of T.
+// It does not validate data, such as variant discriminants or char values.
+// See [MarshalBinary] for more information.
+func UnmarshalBinary[T any](v *T, data []byte) error {
+	if !littleEndian {
+		return errors.New("cm: UnmarshalBinary: unsupported big-endian target")
+	}
+	size := unsafe.Sizeof(*v)
+	if uintptr(len(data)) != size {
+		return errors.New("cm: UnmarshalBinary: data length " + strconv.Itoa(len(data)) +
+			" does not match size " + strconv.Itoa(int(size)))
+	}
+	copy(unsafe.Slice((*byte)(unsafe.Pointer(v)), size), data)
+	return nil
+}
@@ -0,0 +1,47 @@
+package cm
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestMarshalBinary(t *testing.T) {
+	type record struct {
+		_ HostLayout
+		a uint8
+		b uint32
+		c Option[uint16]
+	}
+	v := record{a: 1, b: 0x05040302, c: Some[uint16](0x0706)}
+	data, err := MarshalBinary(&v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []byte{1, 0, 0, 0, 2, 3, 4, 5, 1, 0, 6, 7}
+	if !bytes.Equal(data, want) {
+		t.Errorf("MarshalBinary: %v, expected %v", data, want)
+	}
+
+	var v2 record
+	err = UnmarshalBinary(&v2, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v2 != v {
+		t.Errorf("UnmarshalBinary: %+v, expected %+v", v2, v)
+	}
+
+	err = UnmarshalBinary(&v2, data[:len(data)-1])
+	if err == nil {
+		t.Error("UnmarshalBinary with short data: expected error")
+	}
+
+	r := OK[Result[uint64, uint32, uint64]](uint32(0xffffffff))
+	data, err = MarshalBinary(&r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(data), 16; got != want {
+		t.Errorf("len(MarshalBinary(result<u32, u64>)): %d, expected %d", got, want)
+	}
+}
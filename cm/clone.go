@@ -0,0 +1,35 @@
+package cm
+
+// Clone returns a copy of list l with its elements copied into a new array on the Go heap.
+// Lifted lists and strings, such as the parameters of an exported function, refer to memory
+// owned by the caller, which may be reused after the call returns. Values that are retained
+// after the call should be copied first.
+//
+// The elements are copied shallowly: strings and lists within the elements still refer to
+// the original memory. Use [CloneFunc] to copy the elements of a list deeply.
+func Clone[L AnyList[T], T any](l L) L {
+	s := List[T](l).Slice()
+	if len(s) == 0 {
+		var zero L
+		return zero
+	}
+	c := make([]T, len(s))
+	copy(c, s)
+	return L(ToList(c))
+}
+
+// CloneFunc returns a copy of list l, with each element copied by clone into a new array
+// on the Go heap. Generated DeepCopy methods use CloneFunc to copy lists of strings, lists,
+// or types that contain them.
+func CloneFunc[L AnyList[T], T any](l L, clone func(T) T) L {
+	s := List[T](l).Slice()
+	if len(s) == 0 {
+		var zero L
+		return zero
+	}
+	c := make([]T, len(s))
+	for i := range s {
+		c[i] = clone(s[i])
+	}
+	return L(ToList(c))
+}
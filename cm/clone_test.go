@@ -0,0 +1,44 @@
+package cm
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+func TestClone(t *testing.T) {
+	type myList List[uint8]
+	data := []byte("hello")
+	l := myList(ToList(data))
+	c := Clone(l)
+	if !slices.Equal(c.Slice(), data) {
+		t.Errorf("Clone: %v, expected %v", c.Slice(), data)
+	}
+	if c.Data() == l.Data() {
+		t.Errorf("Clone: data not copied")
+	}
+	data[0] = 'j'
+	if got := string(c.Slice()); got != "hello" {
+		t.Errorf("Clone: %q after modifying original, expected %q", got, "hello")
+	}
+
+	if c := Clone(List[uint8]{}); c.Data() != nil || c.Len() != 0 {
+		t.Errorf("Clone(empty): %v, expected empty list", c)
+	}
+}
+
+func TestCloneFunc(t *testing.T) {
+	buf := []byte("abcdef")
+	s := []string{unsafe.String(&buf[0], 3), unsafe.String(&buf[3], 3)}
+	c := CloneFunc(ToList(s), strings.Clone)
+	copy(buf, "uvwxyz")
+	want := []string{"abc", "def"}
+	if !slices.Equal(c.Slice(), want) {
+		t.Errorf("CloneFunc: %v after modifying original, expected %v", c.Slice(), want)
+	}
+
+	if c := CloneFunc(List[string]{}, strings.Clone); c.Data() != nil || c.Len() != 0 {
+		t.Errorf("CloneFunc(empty): %v, expected empty list", c)
+	}
+}
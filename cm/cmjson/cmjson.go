@@ -0,0 +1,121 @@
+// Package cmjson implements JSON encoding for the generic types in package [cm].
+//
+// Package cm does not depend on encoding/json, so programs that do not use JSON
+// do not link it. Each type in this package is defined from the equivalent type in package cm,
+// so it has the same memory layout and can be converted to and from it.
+// Go code generated by wit-bindgen-go with the --json option uses these types
+// to encode WIT records, variants, and other types with fields of generic cm types.
+//
+// Lists are encoded as JSON arrays, options encode none as null and some as the JSON value
+// of the option, and results are encoded as a JSON object with a single key, either "ok" or "error".
+package cmjson
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/bytecodealliance/wasm-tools-go/cm"
+)
+
+// List is a [cm.List] that implements [json.Marshaler] and [json.Unmarshaler].
+type List[T any] cm.List[T]
+
+// MarshalJSON implements [json.Marshaler], encoding l as a JSON array.
+// An empty list is encoded as [] rather than null.
+func (l List[T]) MarshalJSON() ([]byte, error) {
+	if l.Len() == 0 {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(l.Slice())
+}
+
+// UnmarshalJSON implements [json.Unmarshaler], decoding a JSON array into l.
+// The list data is allocated on the Go heap.
+func (l *List[T]) UnmarshalJSON(data []byte) error {
+	var s []T
+	err := json.Unmarshal(data, &s)
+	if err != nil {
+		return err
+	}
+	*l = List[T](cm.ToList(s))
+	return nil
+}
+
+// Option is a [cm.Option] that implements [json.Marshaler] and [json.Unmarshaler].
+type Option[T any] cm.Option[T]
+
+// MarshalJSON implements [json.Marshaler].
+// The none case is encoded as null, and the some case is encoded as the JSON value of T.
+func (o Option[T]) MarshalJSON() ([]byte, error) {
+	if o.None() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(o.Value())
+}
+
+// UnmarshalJSON implements [json.Unmarshaler].
+// A JSON null is decoded as the none case, and any other value is decoded into the some case.
+func (o *Option[T]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*o = Option[T]{}
+		return nil
+	}
+	var v T
+	err := json.Unmarshal(data, &v)
+	if err != nil {
+		return err
+	}
+	*o = Option[T](cm.Some(v))
+	return nil
+}
+
+// Result is a [cm.Result] that implements [json.Marshaler] and [json.Unmarshaler].
+type Result[Shape, OK, Err any] cm.Result[Shape, OK, Err]
+
+var errResult = errors.New("result: expected JSON object with a single key \"ok\" or \"error\"")
+
+// MarshalJSON implements [json.Marshaler].
+// A result is encoded as a JSON object with a single key, either "ok" or "error",
+// whose value is the JSON encoding of the associated OK or Err value.
+func (r Result[Shape, OK, Err]) MarshalJSON() ([]byte, error) {
+	if r.IsErr() {
+		return json.Marshal(struct {
+			Err *Err `json:"error"`
+		}{r.Err()})
+	}
+	return json.Marshal(struct {
+		OK *OK `json:"ok"`
+	}{r.OK()})
+}
+
+// UnmarshalJSON implements [json.Unmarshaler].
+// It expects a JSON object with a single key, either "ok" or "error".
+func (r *Result[Shape, OK, Err]) UnmarshalJSON(data []byte) error {
+	var m map[string]json.RawMessage
+	err := json.Unmarshal(data, &m)
+	if err != nil {
+		return err
+	}
+	if len(m) != 1 {
+		return errResult
+	}
+	if raw, ok := m["ok"]; ok {
+		var v OK
+		err = json.Unmarshal(raw, &v)
+		if err != nil {
+			return err
+		}
+		*r = Result[Shape, OK, Err](cm.OK[cm.Result[Shape, OK, Err]](v))
+		return nil
+	}
+	if raw, ok := m["error"]; ok {
+		var v Err
+		err = json.Unmarshal(raw, &v)
+		if err != nil {
+			return err
+		}
+		*r = Result[Shape, OK, Err](cm.Err[cm.Result[Shape, OK, Err]](v))
+		return nil
+	}
+	return errResult
+}
@@ -0,0 +1,115 @@
+package cmjson
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/bytecodealliance/wasm-tools-go/cm"
+)
+
+func TestListJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		l    cm.List[string]
+		json string
+	}{
+		{"empty", cm.List[string]{}, `[]`},
+		{"one", cm.ToList([]string{"a"}), `["a"]`},
+		{"two", cm.ToList([]string{"a", "b"}), `["a","b"]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(List[string](tt.l))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.json {
+				t.Errorf("json.Marshal: %s, expected %s", got, tt.json)
+			}
+			var l List[string]
+			err = json.Unmarshal(got, &l)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(l.Slice(), tt.l.Slice()) {
+				t.Errorf("json.Unmarshal: %v, expected %v", l.Slice(), tt.l.Slice())
+			}
+		})
+	}
+}
+
+func TestOptionJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		o    cm.Option[string]
+		json string
+	}{
+		{"none", cm.None[string](), `null`},
+		{"some", cm.Some("hello"), `"hello"`},
+		{"some empty", cm.Some(""), `""`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(Option[string](tt.o))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.json {
+				t.Errorf("json.Marshal: %s, expected %s", got, tt.json)
+			}
+			o := Option[string](cm.Some("garbage"))
+			err = json.Unmarshal(got, &o)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cm.Option[string](o) != tt.o {
+				t.Errorf("json.Unmarshal: %v, expected %v", o, tt.o)
+			}
+		})
+	}
+}
+
+func TestResultJSON(t *testing.T) {
+	type stringResult = Result[string, string, uint32]
+	tests := []struct {
+		name string
+		r    stringResult
+		json string
+	}{
+		{"ok", stringResult(cm.OK[cm.Result[string, string, uint32]]("hello")), `{"ok":"hello"}`},
+		{"err", stringResult(cm.Err[cm.Result[string, string, uint32]](uint32(42))), `{"error":42}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.json {
+				t.Errorf("json.Marshal: %s, expected %s", got, tt.json)
+			}
+			var r stringResult
+			err = json.Unmarshal(got, &r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if r.IsErr() != tt.r.IsErr() {
+				t.Errorf("IsErr: %t, expected %t", r.IsErr(), tt.r.IsErr())
+			}
+			if ok := r.OK(); ok != nil && *ok != *tt.r.OK() {
+				t.Errorf("OK: %v, expected %v", *ok, *tt.r.OK())
+			}
+			if e := r.Err(); e != nil && *e != *tt.r.Err() {
+				t.Errorf("Err: %v, expected %v", *e, *tt.r.Err())
+			}
+		})
+	}
+
+	var r stringResult
+	for _, data := range []string{`{}`, `{"ok":"a","error":1}`, `{"nope":1}`, `[]`} {
+		if err := json.Unmarshal([]byte(data), &r); err == nil {
+			t.Errorf("json.Unmarshal(%s): expected error", data)
+		}
+	}
+}
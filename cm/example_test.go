@@ -0,0 +1,46 @@
+package cm_test
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bytecodealliance/wasm-tools-go/cm"
+)
+
+func ExampleOption_ValueOr() {
+	var name cm.Option[string]
+	fmt.Println(name.ValueOr("anonymous"))
+
+	name.Set("gopher")
+	fmt.Println(name.ValueOr("anonymous"))
+
+	// Output:
+	// anonymous
+	// gopher
+}
+
+func ExampleOption_Some() {
+	o := cm.Some[uint32](42)
+	if v := o.Some(); v != nil {
+		fmt.Println(*v)
+	}
+
+	o.Clear()
+	fmt.Println(o.None())
+
+	// Output:
+	// 42
+	// true
+}
+
+func ExampleMapOption() {
+	name := cm.Some("gopher")
+	fmt.Println(cm.MapOption(name, strings.ToUpper).ValueOr("ANONYMOUS"))
+
+	name.Clear()
+	fmt.Println(cm.MapOption(name, strings.ToUpper).ValueOr("ANONYMOUS"))
+
+	// Output:
+	// GOPHER
+	// ANONYMOUS
+}
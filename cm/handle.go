@@ -0,0 +1,249 @@
+package cm
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrResourceDropped is returned by [AtomicResource.Drop] and [SharedResource.Close]
+// when the resource handle has already been dropped or taken.
+var ErrResourceDropped = errors.New("cm: resource handle already dropped")
+
+// AtomicResource holds an owned [resource handle] of type T, which may be used concurrently
+// by more than one goroutine. The Component Model does not define concurrent use of a
+// resource handle, so AtomicResource ensures the handle is dropped or taken exactly once,
+// and no goroutine can load it afterward.
+//
+// Each call to Store, Take, or Drop begins a new generation, which callers can compare to
+// detect that a handle was replaced or dropped after it was loaded.
+//
+// The zero value holds no handle. An AtomicResource must not be copied after first use.
+//
+// [resource handle]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/Explainer.md#handle-types
+type AtomicResource[T ~uint32] struct {
+	v atomic.Uint64 // generation<<32 | handle
+}
+
+// NewAtomicResource returns an [AtomicResource] that holds handle.
+func NewAtomicResource[T ~uint32](handle T) *AtomicResource[T] {
+	r := &AtomicResource[T]{}
+	r.Store(handle)
+	return r
+}
+
+// Load returns the handle held by r, or [ResourceNone] if r holds no handle.
+// In debug mode, Load panics if the handle was dropped or taken. See [SetDebug].
+func (r *AtomicResource[T]) Load() T {
+	v := r.v.Load()
+	if T(v) == ResourceNone && v>>32 != 0 && Debug() {
+		panic("cm: use of dropped resource handle")
+	}
+	return T(v)
+}
+
+// Generation returns the current generation of r.
+func (r *AtomicResource[T]) Generation() uint32 {
+	return uint32(r.v.Load() >> 32)
+}
+
+// Store stores handle in r, beginning a new generation.
+// Any handle previously held by r is not dropped.
+func (r *AtomicResource[T]) Store(handle T) {
+	for {
+		v := r.v.Load()
+		if r.v.CompareAndSwap(v, (v>>32+1)<<32|uint64(handle)) {
+			return
+		}
+	}
+}
+
+// Take removes the handle from r, transferring ownership to the caller.
+// It returns false if r holds no handle.
+func (r *AtomicResource[T]) Take() (handle T, ok bool) {
+	for {
+		v := r.v.Load()
+		if T(v) == ResourceNone {
+			return ResourceNone, false
+		}
+		if r.v.CompareAndSwap(v, (v>>32+1)<<32) {
+			return T(v), true
+		}
+	}
+}
+
+// Drop removes the handle from r and calls drop with it. If more than one goroutine
+// calls Drop, drop is called at most once.
+// Drop returns [ErrResourceDropped] if r holds no handle, or panics in debug mode.
+func (r *AtomicResource[T]) Drop(drop func(T)) error {
+	handle, ok := r.Take()
+	if !ok {
+		if Debug() {
+			panic("cm: second drop of resource handle")
+		}
+		return ErrResourceDropped
+	}
+	drop(handle)
+	return nil
+}
+
+// SharedResource holds an owned [resource handle] of type T, shared by goroutines that
+// borrow it, such as a pool of connections over wasi:sockets. Unlike [AtomicResource],
+// which ensures a handle is dropped exactly once, SharedResource also ensures a handle is not
+// dropped while a goroutine is using it: Close and Swap drop a handle only after each borrow
+// of it, by Acquire or Do, is released.
+//
+// A typical use is to create a SharedResource with the handle and its drop function,
+// call Do from any goroutine to use the handle, and call Close once when done:
+//
+//	conn := cm.NewSharedResource(sock, tcp.TCPSocket.ResourceDrop)
+//	go conn.Do(func(s tcp.TCPSocket) { ... })
+//	conn.Close()
+//
+// A SharedResource must be created with [NewSharedResource], and must not be copied.
+//
+// [resource handle]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/Explainer.md#handle-types
+type SharedResource[T ~uint32] struct {
+	p    atomic.Pointer[sharedHandle[T]]
+	drop func(T)
+}
+
+// sharedHandle is a reference-counted handle held by a [SharedResource].
+// The handle is dropped when refs reaches zero, after which refs never increases.
+type sharedHandle[T ~uint32] struct {
+	handle T
+	refs   atomic.Int64
+	drop   func(T)
+}
+
+func (h *sharedHandle[T]) release() {
+	if h.refs.Add(-1) == 0 {
+		h.drop(h.handle)
+	}
+}
+
+// NewSharedResource returns a [SharedResource] that holds handle,
+// which is dropped by calling drop.
+func NewSharedResource[T ~uint32](handle T, drop func(T)) *SharedResource[T] {
+	r := &SharedResource[T]{drop: drop}
+	r.Swap(handle)
+	return r
+}
+
+// Acquire borrows the handle held by r. The handle remains valid until release is called,
+// even if r is closed or its handle swapped concurrently. Release must be called exactly once.
+// Acquire returns false if r holds no handle.
+func (r *SharedResource[T]) Acquire() (handle T, release func(), ok bool) {
+	for {
+		h := r.p.Load()
+		if h == nil {
+			return ResourceNone, nil, false
+		}
+		n := h.refs.Load()
+		if n == 0 {
+			// h was released concurrently; reload.
+			continue
+		}
+		if h.refs.CompareAndSwap(n, n+1) {
+			var released atomic.Bool
+			return h.handle, func() {
+				if released.Swap(true) {
+					panic("cm: second release of resource handle")
+				}
+				h.release()
+			}, true
+		}
+	}
+}
+
+// Do calls f with the handle held by r, borrowed for the duration of the call.
+// It returns false without calling f if r holds no handle.
+func (r *SharedResource[T]) Do(f func(T)) bool {
+	handle, release, ok := r.Acquire()
+	if !ok {
+		return false
+	}
+	defer release()
+	f(handle)
+	return true
+}
+
+// Swap replaces the handle held by r with handle. The previous handle, if any, is dropped
+// once each borrow of it is released.
+func (r *SharedResource[T]) Swap(handle T) {
+	h := &sharedHandle[T]{handle: handle, drop: r.drop}
+	h.refs.Store(1)
+	if old := r.p.Swap(h); old != nil {
+		old.release()
+	}
+}
+
+// Close removes the handle from r, which is dropped once each borrow of it is released.
+// If more than one goroutine calls Close, the handle is dropped at most once.
+// Close returns [ErrResourceDropped] if r holds no handle, or panics in debug mode.
+func (r *SharedResource[T]) Close() error {
+	old := r.p.Swap(nil)
+	if old == nil {
+		if Debug() {
+			panic("cm: second drop of resource handle")
+		}
+		return ErrResourceDropped
+	}
+	old.release()
+	return nil
+}
+
+var debug atomic.Bool
+
+// SetDebug enables or disables debug mode. In debug mode, [AtomicResource] panics on
+// use of a dropped handle or a second drop, rather than returning [ResourceNone] or
+// [ErrResourceDropped].
+func SetDebug(enabled bool) {
+	debug.Store(enabled)
+}
+
+// Debug reports whether debug mode is enabled. See [SetDebug].
+func Debug() bool {
+	return debug.Load()
+}
+
+// dropped records resource handles dropped by [DebugDrop], indexed by handle type and value.
+var dropped struct {
+	sync.Mutex
+	handles map[any]bool
+}
+
+// DebugAcquire records that the caller received ownership of handle,
+// such as from the result of an imported function.
+// Bindings generated by wit-bindgen-go with --debug call DebugAcquire, [DebugUse], and [DebugDrop],
+// which panic on use of a dropped handle or a second drop, regardless of [SetDebug].
+func DebugAcquire[T ~uint32](handle T) {
+	dropped.Lock()
+	delete(dropped.handles, handle)
+	dropped.Unlock()
+}
+
+// DebugUse panics if handle was dropped, and not acquired again since.
+func DebugUse[T ~uint32](handle T) {
+	dropped.Lock()
+	ok := dropped.handles[handle]
+	dropped.Unlock()
+	if ok {
+		panic("cm: use of dropped resource handle " + strconv.FormatUint(uint64(handle), 10))
+	}
+}
+
+// DebugDrop records that handle was dropped, or that its ownership was transferred by the caller.
+// It panics if handle was already dropped, and not acquired again since.
+func DebugDrop[T ~uint32](handle T) {
+	dropped.Lock()
+	defer dropped.Unlock()
+	if dropped.handles[handle] {
+		panic("cm: second drop of resource handle " + strconv.FormatUint(uint64(handle), 10))
+	}
+	if dropped.handles == nil {
+		dropped.handles = make(map[any]bool)
+	}
+	dropped.handles[handle] = true
+}
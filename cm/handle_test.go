@@ -0,0 +1,186 @@
+package cm
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+type testHandle uint32
+
+func mustPanic(t *testing.T, name string, f func()) {
+	t.Helper()
+	defer func() {
+		if recover() == nil {
+			t.Errorf("%s: expected panic", name)
+		}
+	}()
+	f()
+}
+
+func TestAtomicResource(t *testing.T) {
+	var r AtomicResource[testHandle]
+	if got, want := r.Load(), testHandle(ResourceNone); got != want {
+		t.Errorf("Load: %d, expected %d", got, want)
+	}
+
+	r.Store(7)
+	if got, want := r.Load(), testHandle(7); got != want {
+		t.Errorf("Load: %d, expected %d", got, want)
+	}
+	if got, want := r.Generation(), uint32(1); got != want {
+		t.Errorf("Generation: %d, expected %d", got, want)
+	}
+
+	var drops int
+	drop := func(h testHandle) {
+		if h != 7 {
+			t.Errorf("drop: %d, expected 7", h)
+		}
+		drops++
+	}
+	if err := r.Drop(drop); err != nil {
+		t.Errorf("Drop: %v", err)
+	}
+	if err := r.Drop(drop); err != ErrResourceDropped {
+		t.Errorf("Drop: %v, expected %v", err, ErrResourceDropped)
+	}
+	if drops != 1 {
+		t.Errorf("drop called %d times, expected 1", drops)
+	}
+	if got, want := r.Load(), testHandle(ResourceNone); got != want {
+		t.Errorf("Load after Drop: %d, expected %d", got, want)
+	}
+	if got, want := r.Generation(), uint32(2); got != want {
+		t.Errorf("Generation: %d, expected %d", got, want)
+	}
+	if _, ok := r.Take(); ok {
+		t.Error("Take after Drop: ok, expected false")
+	}
+
+	r2 := NewAtomicResource(testHandle(3))
+	if h, ok := r2.Take(); h != 3 || !ok {
+		t.Errorf("Take: %d, %t, expected 3, true", h, ok)
+	}
+}
+
+func TestAtomicResourceConcurrentDrop(t *testing.T) {
+	r := NewAtomicResource(testHandle(1))
+	var mu sync.Mutex
+	var drops int
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.Drop(func(testHandle) {
+				mu.Lock()
+				drops++
+				mu.Unlock()
+			})
+		}()
+	}
+	wg.Wait()
+	if drops != 1 {
+		t.Errorf("drop called %d times, expected 1", drops)
+	}
+}
+
+func TestAtomicResourceDebug(t *testing.T) {
+	SetDebug(true)
+	defer SetDebug(false)
+
+	r := NewAtomicResource(testHandle(1))
+	r.Drop(func(testHandle) {})
+	mustPanic(t, "Load", func() { r.Load() })
+	mustPanic(t, "Drop", func() { r.Drop(func(testHandle) {}) })
+
+	var zero AtomicResource[testHandle]
+	if got := zero.Load(); got != ResourceNone {
+		t.Errorf("Load: %d, expected %d", got, ResourceNone)
+	}
+}
+
+func TestDebugHandles(t *testing.T) {
+	DebugAcquire(testHandle(5))
+	DebugUse(testHandle(5))
+	DebugDrop(testHandle(5))
+	mustPanic(t, "DebugUse", func() { DebugUse(testHandle(5)) })
+	mustPanic(t, "DebugDrop", func() { DebugDrop(testHandle(5)) })
+
+	// Handles of other types are tracked separately.
+	DebugUse(Resource(5))
+
+	// The handle can be reused after it is acquired again.
+	DebugAcquire(testHandle(5))
+	DebugUse(testHandle(5))
+	DebugDrop(testHandle(5))
+	DebugAcquire(testHandle(5))
+}
+
+func TestSharedResource(t *testing.T) {
+	var dropped []testHandle
+	drop := func(h testHandle) { dropped = append(dropped, h) }
+	r := NewSharedResource(testHandle(1), drop)
+
+	h, release, ok := r.Acquire()
+	if h != 1 || !ok {
+		t.Errorf("Acquire: %d, %t, expected 1, true", h, ok)
+	}
+
+	// The borrowed handle is not dropped until it is released.
+	r.Swap(2)
+	if len(dropped) != 0 {
+		t.Errorf("Swap: dropped %v while borrowed, expected none", dropped)
+	}
+	release()
+	if len(dropped) != 1 || dropped[0] != 1 {
+		t.Errorf("release: dropped %v, expected [1]", dropped)
+	}
+	mustPanic(t, "release", release)
+
+	var got testHandle
+	if !r.Do(func(h testHandle) { got = h }) || got != 2 {
+		t.Errorf("Do: called with %d, expected 2", got)
+	}
+
+	if err := r.Close(); err != nil {
+		t.Errorf("Close: %v", err)
+	}
+	if len(dropped) != 2 || dropped[1] != 2 {
+		t.Errorf("Close: dropped %v, expected [1 2]", dropped)
+	}
+	if err := r.Close(); err != ErrResourceDropped {
+		t.Errorf("Close: %v, expected %v", err, ErrResourceDropped)
+	}
+	if _, _, ok := r.Acquire(); ok {
+		t.Error("Acquire after Close: ok, expected false")
+	}
+	if r.Do(func(testHandle) { t.Error("Do after Close: f called") }) {
+		t.Error("Do after Close: true, expected false")
+	}
+}
+
+func TestSharedResourceConcurrent(t *testing.T) {
+	var drops atomic.Int32
+	r := NewSharedResource(testHandle(1), func(testHandle) { drops.Add(1) })
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				r.Do(func(testHandle) {
+					if drops.Load() != 0 {
+						t.Error("Do: handle dropped while borrowed")
+					}
+				})
+			}
+			r.Close()
+		}()
+	}
+	wg.Wait()
+	if n := drops.Load(); n != 1 {
+		t.Errorf("drop called %d times, expected 1", n)
+	}
+}
@@ -0,0 +1,78 @@
+package cm
+
+import "sync/atomic"
+
+// Call describes a call to an imported function, passed to a [CallHook].
+type Call struct {
+	// Module is the Component Model module of the function, e.g. "wasi:random/random@0.2.0",
+	// or "$root" for a function imported by a world.
+	Module string
+
+	// Name is the name of the function, e.g. "get-random-bytes" or "[method]input-stream.read".
+	Name string
+
+	// Params are the Go values of the function params, starting with the receiver of a method.
+	Params []any
+
+	// Results are pointers to the Go results of the function, such as *List[uint8].
+	// They hold the results of the call when After is called. A hook may set them
+	// to change the results returned to the caller, e.g. to inject faults.
+	Results []any
+
+	// Skip, if set by Before, skips the call to the imported function.
+	// The function returns the values Before set through Results.
+	Skip bool
+
+	// Data holds any value set by Before for use by After, such as a tracing span or start time.
+	Data any
+}
+
+// CallHook observes calls to imported functions for tracing, metrics, or fault injection.
+// Bindings generated with call hooks call the hook registered with [SetCallHook],
+// if any, from each imported function:
+//
+//	if hook := cm.LoadCallHook(); hook != nil {
+//		call := &cm.Call{Module: "wasi:random/random@0.2.0", Name: "get-random-bytes", Params: []any{len_}, Results: []any{&result}}
+//		hook.Before(call)
+//		defer hook.After(call)
+//		if call.Skip {
+//			return
+//		}
+//	}
+//
+// A hook is called from any goroutine that calls an imported function, so it must be safe
+// for concurrent use. Imported functions called by a hook also call the hook.
+type CallHook interface {
+	// Before is called before the imported function is called.
+	Before(call *Call)
+
+	// After is called after the imported function returns, or is skipped.
+	After(call *Call)
+}
+
+type callHook struct {
+	hook CallHook
+}
+
+var currentHook atomic.Pointer[callHook]
+
+// SetCallHook registers hook to be called by imported functions in bindings generated with
+// call hooks, and returns the previously registered hook. If hook is nil, the hook is removed.
+func SetCallHook(hook CallHook) (prev CallHook) {
+	var h *callHook
+	if hook != nil {
+		h = &callHook{hook}
+	}
+	if old := currentHook.Swap(h); old != nil {
+		return old.hook
+	}
+	return nil
+}
+
+// LoadCallHook returns the [CallHook] registered with [SetCallHook], or nil if none is registered.
+func LoadCallHook() CallHook {
+	if h := currentHook.Load(); h != nil {
+		return h.hook
+	}
+	return nil
+}
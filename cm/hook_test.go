@@ -0,0 +1,55 @@
+package cm
+
+import "testing"
+
+type testHook struct {
+	calls []string
+}
+
+func (h *testHook) Before(call *Call) {
+	h.calls = append(h.calls, "before "+call.Name)
+	call.Data = len(h.calls)
+}
+
+func (h *testHook) After(call *Call) {
+	h.calls = append(h.calls, "after "+call.Name)
+	if r, ok := call.Results[0].(*uint32); ok && call.Skip {
+		*r = 42
+	}
+}
+
+func TestCallHook(t *testing.T) {
+	if got := LoadCallHook(); got != nil {
+		t.Fatalf("LoadCallHook: %v, expected nil", got)
+	}
+	h := &testHook{}
+	if prev := SetCallHook(h); prev != nil {
+		t.Errorf("SetCallHook: previous hook %v, expected nil", prev)
+	}
+	defer SetCallHook(nil)
+	if got := LoadCallHook(); got != h {
+		t.Fatalf("LoadCallHook: %v, expected %v", got, h)
+	}
+
+	var result uint32
+	call := &Call{Module: "example:foo/bar@0.1.0", Name: "baz", Params: []any{uint32(1)}, Results: []any{&result}, Skip: true}
+	hook := LoadCallHook()
+	hook.Before(call)
+	hook.After(call)
+	if result != 42 {
+		t.Errorf("result: %d, expected 42", result)
+	}
+	if call.Data != 1 {
+		t.Errorf("call.Data: %v, expected 1", call.Data)
+	}
+	if len(h.calls) != 2 || h.calls[0] != "before baz" || h.calls[1] != "after baz" {
+		t.Errorf("calls: %v, expected [before baz after baz]", h.calls)
+	}
+
+	if prev := SetCallHook(nil); prev != h {
+		t.Errorf("SetCallHook(nil): previous hook %v, expected %v", prev, h)
+	}
+	if got := LoadCallHook(); got != nil {
+		t.Errorf("LoadCallHook after SetCallHook(nil): %v, expected nil", got)
+	}
+}
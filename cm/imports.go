@@ -0,0 +1,36 @@
+package cm
+
+import "sync"
+
+// imports holds fake implementations of imported functions, registered with [RegisterImport].
+var imports struct {
+	sync.RWMutex
+	funcs map[[2]string]any
+}
+
+// RegisterImport registers f as the implementation of the function imported from
+// Component Model module with name, e.g. "wasi:random/random@0.2.0" and "get-random-bytes".
+// It is intended for testing generated bindings on hosts other than WebAssembly.
+// Bindings generated with stubs call a registered function in place of the wasmimport function.
+// The type of f must match the generated wasmimport function, with lowered parameters and results.
+// If f is nil, any registered implementation is removed.
+func RegisterImport(module, name string, f any) {
+	imports.Lock()
+	defer imports.Unlock()
+	if f == nil {
+		delete(imports.funcs, [2]string{module, name})
+		return
+	}
+	if imports.funcs == nil {
+		imports.funcs = make(map[[2]string]any)
+	}
+	imports.funcs[[2]string{module, name}] = f
+}
+
+// LookupImport returns the function registered with [RegisterImport] for module and name,
+// or nil if none is registered.
+func LookupImport(module, name string) any {
+	imports.RLock()
+	defer imports.RUnlock()
+	return imports.funcs[[2]string{module, name}]
+}
@@ -0,0 +1,27 @@
+package cm
+
+import "testing"
+
+func TestRegisterImport(t *testing.T) {
+	const module, name = "example:foo/bar@0.1.0", "baz"
+	if got := LookupImport(module, name); got != nil {
+		t.Errorf("LookupImport(%q, %q): %v, expected nil", module, name, got)
+	}
+
+	RegisterImport(module, name, func(x uint32) uint32 { return x + 1 })
+	f, ok := LookupImport(module, name).(func(uint32) uint32)
+	if !ok {
+		t.Fatalf("LookupImport(%q, %q): %T, expected func(uint32) uint32", module, name, LookupImport(module, name))
+	}
+	if got, want := f(1), uint32(2); got != want {
+		t.Errorf("f(1): %d, expected %d", got, want)
+	}
+	if got := LookupImport(module, "qux"); got != nil {
+		t.Errorf("LookupImport(%q, %q): %v, expected nil", module, "qux", got)
+	}
+
+	RegisterImport(module, name, nil)
+	if got := LookupImport(module, name); got != nil {
+		t.Errorf("LookupImport(%q, %q) after unregister: %v, expected nil", module, name, got)
+	}
+}
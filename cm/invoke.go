@@ -0,0 +1,97 @@
+package cm
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Func represents a function that can be invoked dynamically with [Value] arguments,
+// returning its results as a slice of [Value].
+type Func func(args ...Value) ([]Value, error)
+
+// Registry maps fully-qualified WIT function names to dynamically invocable functions.
+// Names are in the form "namespace:package/interface@version#function",
+// for example "wasi:random/random@0.2.0#get-random-bytes".
+type Registry map[string]Func
+
+// Lookup returns the [Func] for name, and whether it was found.
+// If name does not include a version, e.g. "wasi:random/random#get-random-bytes",
+// Lookup returns the function with a matching unversioned name, if unique.
+func (r Registry) Lookup(name string) (Func, bool) {
+	if f, ok := r[name]; ok {
+		return f, true
+	}
+	var found Func
+	for key, f := range r {
+		if unversioned(key) != name {
+			continue
+		}
+		if found != nil {
+			return nil, false // ambiguous
+		}
+		found = f
+	}
+	return found, found != nil
+}
+
+// Invoke calls the function identified by name with args, returning its results.
+// It returns an *[InvokeError] if the function is not found in r.
+func (r Registry) Invoke(name string, args ...Value) ([]Value, error) {
+	f, ok := r.Lookup(name)
+	if !ok {
+		return nil, &InvokeError{Name: name, Message: "function not found"}
+	}
+	results, err := f(args...)
+	if e, ok := err.(*InvokeError); ok && e.Name == "" {
+		e.Name = name
+	}
+	return results, err
+}
+
+// Names returns the sorted function names in r.
+func (r Registry) Names() []string {
+	names := make([]string, 0, len(r))
+	for name := range r {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// ExpectArgs returns an *[InvokeError] if len(args) is not n.
+// It is used by generated code to check the number of arguments passed to a [Func].
+func ExpectArgs(args []Value, n int) error {
+	if len(args) != n {
+		return &InvokeError{Message: "expected " + strconv.Itoa(n) + " argument(s), got " + strconv.Itoa(len(args))}
+	}
+	return nil
+}
+
+// InvokeError is returned when a [Func] cannot be invoked.
+type InvokeError struct {
+	Name    string
+	Message string
+}
+
+// Error implements the [error] interface.
+func (e *InvokeError) Error() string {
+	if e.Name == "" {
+		return "cm: " + e.Message
+	}
+	return "cm: " + e.Name + ": " + e.Message
+}
+
+// unversioned returns name with the optional "@version" suffix of its
+// package and interface removed, e.g. "wasi:random/random#get-random-bytes".
+func unversioned(name string) string {
+	at := strings.IndexByte(name, '@')
+	if at < 0 {
+		return name
+	}
+	hash := strings.IndexByte(name[at:], '#')
+	if hash < 0 {
+		return name[:at]
+	}
+	return name[:at] + name[at+hash:]
+}
@@ -0,0 +1,79 @@
+package cm
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestRegistryInvoke(t *testing.T) {
+	r := Registry{
+		"wasi:random/random@0.2.0#get-random-u64": func(args ...Value) ([]Value, error) {
+			if err := ExpectArgs(args, 0); err != nil {
+				return nil, err
+			}
+			return []Value{U64Value(4)}, nil
+		},
+		"example:foo/bar#add": func(args ...Value) ([]Value, error) {
+			if err := ExpectArgs(args, 2); err != nil {
+				return nil, err
+			}
+			return []Value{U32Value(uint32(args[0].Uint() + args[1].Uint()))}, nil
+		},
+	}
+
+	tests := []struct {
+		name    string
+		args    []Value
+		want    []Value
+		wantErr bool
+	}{
+		{"wasi:random/random@0.2.0#get-random-u64", nil, []Value{U64Value(4)}, false},
+		{"wasi:random/random#get-random-u64", nil, []Value{U64Value(4)}, false},
+		{"example:foo/bar#add", []Value{U32Value(1), U32Value(2)}, []Value{U32Value(3)}, false},
+		{"example:foo/bar#add", []Value{U32Value(1)}, nil, true},
+		{"wasi:random/random#get-random-bytes", nil, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.Invoke(tt.name, tt.args...)
+			if tt.wantErr {
+				var ierr *InvokeError
+				if !errors.As(err, &ierr) {
+					t.Fatalf("Invoke: %v, expected *InvokeError", err)
+				}
+				if got, want := ierr.Name, tt.name; got != want {
+					t.Errorf("InvokeError.Name: %q, expected %q", got, want)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Invoke: %v", err)
+			}
+			if !slices.EqualFunc(got, tt.want, func(a, b Value) bool { return a.String() == b.String() }) {
+				t.Errorf("Invoke: %v, expected %v", got, tt.want)
+			}
+		})
+	}
+
+	if got, want := r.Names(), []string{"example:foo/bar#add", "wasi:random/random@0.2.0#get-random-u64"}; !slices.Equal(got, want) {
+		t.Errorf("Names(): %v, expected %v", got, want)
+	}
+}
+
+func TestUnversioned(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"wasi:random/random@0.2.0#get-random-bytes", "wasi:random/random#get-random-bytes"},
+		{"wasi:random/random#get-random-bytes", "wasi:random/random#get-random-bytes"},
+		{"wasi:cli/command@0.2.0", "wasi:cli/command"},
+		{"f", "f"},
+	}
+	for _, tt := range tests {
+		if got := unversioned(tt.name); got != tt.want {
+			t.Errorf("unversioned(%q): %q, expected %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package cm
+
+// Null represents a value of type T that may be null. It is equivalent to
+// [sql.Null] in package database/sql, and can be converted to and from it,
+// as both types have the same fields:
+//
+//	n := sql.Null[string](cm.ToNull(o))
+//	o := cm.FromNull(cm.Null[string](n))
+//
+// [sql.Null]: https://pkg.go.dev/database/sql#Null
+type Null[T any] struct {
+	V     T
+	Valid bool // Valid is true if V is not null
+}
+
+// ToNull returns a [Null] equivalent to [Option] o.
+// Valid is true if o represents the some case.
+func ToNull[T any](o Option[T]) Null[T] {
+	return Null[T]{V: o.Value(), Valid: !o.None()}
+}
+
+// FromNull returns an [Option] equivalent to [Null] n.
+// It represents the some case if n is Valid, otherwise the none case.
+func FromNull[T any](n Null[T]) Option[T] {
+	if !n.Valid {
+		return None[T]()
+	}
+	return Some(n.V)
+}
+
+// ToPointer returns a pointer to a copy of the value of [Option] o,
+// or nil if o represents the none case.
+// Unlike the Some method, the returned pointer does not alias o.
+func ToPointer[T any](o Option[T]) *T {
+	if o.None() {
+		return nil
+	}
+	v := o.Value()
+	return &v
+}
+
+// FromPointer returns an [Option] that represents the some case with the value
+// pointed to by p, or the none case if p is nil.
+func FromPointer[T any](p *T) Option[T] {
+	if p == nil {
+		return None[T]()
+	}
+	return Some(*p)
+}
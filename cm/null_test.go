@@ -0,0 +1,54 @@
+package cm
+
+import "testing"
+
+// sqlNull has the same fields as sql.Null in package database/sql.
+type sqlNull[T any] struct {
+	V     T
+	Valid bool
+}
+
+func TestNull(t *testing.T) {
+	tests := []struct {
+		name string
+		o    Option[string]
+		want Null[string]
+	}{
+		{"none", None[string](), Null[string]{}},
+		{"some", Some("hello"), Null[string]{V: "hello", Valid: true}},
+		{"some empty", Some(""), Null[string]{Valid: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ToNull(tt.o)
+			if got != tt.want {
+				t.Errorf("ToNull(%v): %v, expected %v", tt.o, got, tt.want)
+			}
+			n := sqlNull[string](got)
+			if o := FromNull(Null[string](n)); o != tt.o {
+				t.Errorf("FromNull(%v): %v, expected %v", n, o, tt.o)
+			}
+		})
+	}
+}
+
+func TestPointer(t *testing.T) {
+	if p := ToPointer(None[int]()); p != nil {
+		t.Errorf("ToPointer(None): %v, expected nil", p)
+	}
+	o := Some(42)
+	p := ToPointer(o)
+	if p == nil || *p != 42 {
+		t.Fatalf("ToPointer(Some(42)): %v, expected pointer to 42", p)
+	}
+	*p = 7
+	if got, want := o.Value(), 42; got != want {
+		t.Errorf("Value() after modifying ToPointer result: %d, expected %d", got, want)
+	}
+	if got, want := FromPointer(p), Some(7); got != want {
+		t.Errorf("FromPointer(&7): %v, expected %v", got, want)
+	}
+	if got, want := FromPointer[int](nil), None[int](); got != want {
+		t.Errorf("FromPointer(nil): %v, expected %v", got, want)
+	}
+}
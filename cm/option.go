@@ -2,6 +2,12 @@ package cm
 
 // Option represents a Component Model [option<T>] type.
 //
+// Generated functions return an Option for a WIT function that returns an option.
+// Use [Option.ValueOr] to supply a default value if the result is none,
+// or [MapOption] to convert the value if some:
+//
+//	v := f().ValueOr(fallback)
+//
 // [option<T>]: https://component-model.bytecodealliance.org/design/wit.html#options
 type Option[T any] struct {
 	_ HostLayout
@@ -24,6 +30,15 @@ func Some[T any](v T) Option[T] {
 	}
 }
 
+// MapOption returns an [Option] with the value of o mapped by f,
+// or none if o represents the none case.
+func MapOption[T, U any](o Option[T], f func(T) U) Option[U] {
+	if !o.isSome {
+		return None[U]()
+	}
+	return Some(f(o.some))
+}
+
 // option represents the internal representation of a Component Model option type.
 // The first byte is a bool representing none or some,
 // followed by storage for the associated type T.
@@ -57,3 +72,34 @@ func (o option[T]) Value() T {
 	}
 	return o.some
 }
+
+// ValueOr returns T if o represents the some case,
+// or v if o represents the none case.
+// This does not have a pointer receiver, so it can be chained.
+func (o option[T]) ValueOr(v T) T {
+	if !o.isSome {
+		return v
+	}
+	return o.some
+}
+
+// MustValue returns T if o represents the some case.
+// It panics if o represents the none case.
+// This does not have a pointer receiver, so it can be chained.
+func (o option[T]) MustValue() T {
+	if !o.isSome {
+		panic("cm: MustValue called on none Option")
+	}
+	return o.some
+}
+
+// Set sets o to the some case with value v.
+func (o *option[T]) Set(v T) {
+	o.isSome = true
+	o.some = v
+}
+
+// Clear sets o to the none case, clearing any value of T.
+func (o *option[T]) Clear() {
+	*o = option[T]{}
+}
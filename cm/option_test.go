@@ -1,6 +1,8 @@
 package cm
 
-import "testing"
+import (
+	"testing"
+)
 
 func TestOption(t *testing.T) {
 	o1 := None[string]()
@@ -65,3 +67,43 @@ func TestOption(t *testing.T) {
 		t.Errorf("Value: %v, expected %v", got, want)
 	}
 }
+
+func TestOptionHelpers(t *testing.T) {
+	var o Option[string]
+	if got, want := o.ValueOr("default"), "default"; got != want {
+		t.Errorf("ValueOr: %q, expected %q", got, want)
+	}
+
+	o.Set("hello")
+	if got, want := o.None(), false; got != want {
+		t.Errorf("None: %t, expected %t", got, want)
+	}
+	if got, want := o.ValueOr("default"), "hello"; got != want {
+		t.Errorf("ValueOr: %q, expected %q", got, want)
+	}
+	if got, want := o.MustValue(), "hello"; got != want {
+		t.Errorf("MustValue: %q, expected %q", got, want)
+	}
+
+	o.Clear()
+	if got, want := o, None[string](); got != want {
+		t.Errorf("Clear: %v, expected %v", got, want)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("MustValue: expected panic")
+		}
+	}()
+	o.MustValue()
+}
+
+func TestMapOption(t *testing.T) {
+	length := func(s string) int { return len(s) }
+	if got, want := MapOption(Some("hello"), length), Some(5); got != want {
+		t.Errorf("MapOption: %v, expected %v", got, want)
+	}
+	if got, want := MapOption(None[string](), length), None[int](); got != want {
+		t.Errorf("MapOption: %v, expected %v", got, want)
+	}
+}
@@ -1,6 +1,9 @@
 package cm
 
-import "unsafe"
+import (
+	"strconv"
+	"unsafe"
+)
 
 const (
 	// ResultOK represents the OK case of a result.
@@ -71,6 +74,31 @@ func (r *result[Shape, OK, Err]) Err() *Err {
 	return (*Err)(unsafe.Pointer(&r.data))
 }
 
+// Unwrap returns the OK value, the error value, and true if r represents the error case.
+// The value that r does not represent is the zero value of its type.
+// This does not have a pointer receiver, so it can be chained.
+func (r result[Shape, OK, Err]) Unwrap() (ok OK, err Err, isErr bool) {
+	r.validate()
+	if r.isErr {
+		return ok, *(*Err)(unsafe.Pointer(&r.data)), true
+	}
+	return *(*OK)(unsafe.Pointer(&r.data)), err, false
+}
+
+// setOK sets r to the OK case with value ok.
+func (r *result[Shape, OK, Err]) setOK(ok OK) {
+	r.validate()
+	*r = result[Shape, OK, Err]{isErr: ResultOK}
+	*((*OK)(unsafe.Pointer(&r.data))) = ok
+}
+
+// setErr sets r to the error case with value err.
+func (r *result[Shape, OK, Err]) setErr(err Err) {
+	r.validate()
+	*r = result[Shape, OK, Err]{isErr: ResultErr}
+	*((*Err)(unsafe.Pointer(&r.data))) = err
+}
+
 // This function is sized so it can be inlined and optimized away.
 func (r *result[Shape, OK, Err]) validate() {
 	var shape Shape
@@ -100,6 +128,9 @@ func (r *result[Shape, OK, Err]) validate() {
 
 // OK returns an OK result with shape Shape and type OK and Err.
 // Pass Result[OK, OK, Err] or Result[Err, OK, Err] as the first type argument.
+// Shape, OK, and Err are inferred from it, so a generated result type can be passed:
+//
+//	return cm.OK[cm.Result[string, string, ErrorCode]]("hello")
 func OK[R AnyResult[Shape, OK, Err], Shape, OK, Err any](ok OK) R {
 	var r Result[Shape, OK, Err]
 	r.validate()
@@ -110,6 +141,7 @@ func OK[R AnyResult[Shape, OK, Err], Shape, OK, Err any](ok OK) R {
 
 // Err returns an error result with shape Shape and type OK and Err.
 // Pass Result[OK, OK, Err] or Result[Err, OK, Err] as the first type argument.
+// Shape, OK, and Err are inferred from it, as with [OK].
 func Err[R AnyResult[Shape, OK, Err], Shape, OK, Err any](err Err) R {
 	var r Result[Shape, OK, Err]
 	r.validate()
@@ -117,3 +149,96 @@ func Err[R AnyResult[Shape, OK, Err], Shape, OK, Err any](err Err) R {
 	*((*Err)(unsafe.Pointer(&r.data))) = err
 	return R(r)
 }
+
+// MapOK returns a result of type R2 with the OK value of r mapped by f,
+// or the error value of r if r represents the error case.
+// Pass the result type R2 as the first type argument; the others are inferred:
+//
+//	n := cm.MapOK[cm.Result[string, uint32, ErrorCode]](r, func(s string) uint32 { return uint32(len(s)) })
+func MapOK[R2 AnyResult[Shape2, OK2, Err], R AnyResult[Shape, OK, Err], Shape2, OK2, Shape, OK, Err any](r R, f func(OK) OK2) R2 {
+	var r2 Result[Shape2, OK2, Err]
+	ok, err, isErr := Result[Shape, OK, Err](r).Unwrap()
+	if isErr {
+		r2.setErr(err)
+	} else {
+		r2.setOK(f(ok))
+	}
+	return R2(r2)
+}
+
+// MapErr returns a result of type R2 with the error value of r mapped by f,
+// or the OK value of r if r represents the OK case.
+// Pass the result type R2 as the first type argument; the others are inferred.
+func MapErr[R2 AnyResult[Shape2, OK, Err2], R AnyResult[Shape, OK, Err], Shape2, Err2, Shape, OK, Err any](r R, f func(Err) Err2) R2 {
+	var r2 Result[Shape2, OK, Err2]
+	ok, err, isErr := Result[Shape, OK, Err](r).Unwrap()
+	if isErr {
+		r2.setErr(f(err))
+	} else {
+		r2.setOK(ok)
+	}
+	return R2(r2)
+}
+
+// ToGo converts result r to the Go convention of a value and an error.
+// If r represents the OK case, it returns the OK value and a nil error.
+// If r represents the error case, it returns the zero value of OK and the error
+// returned by mapErr, which should not be nil. If mapErr is nil, the error value
+// is returned as is if Err implements the error interface, otherwise it is
+// wrapped in an error that describes it with its String method, if any,
+// or as a string, boolean, or integer:
+//
+//	n, err := cm.ToGo(stream.Write(buf), func(e StreamError) error { return errors.New(e.String()) })
+func ToGo[R AnyResult[Shape, OK, Err], Shape, OK, Err any](r R, mapErr func(Err) error) (OK, error) {
+	ok, err, isErr := Result[Shape, OK, Err](r).Unwrap()
+	if !isErr {
+		return ok, nil
+	}
+	if mapErr != nil {
+		return ok, mapErr(err)
+	}
+	if e, isError := any(err).(error); isError {
+		return ok, e
+	}
+	return ok, &resultError[Err]{err}
+}
+
+// resultError is an error that holds the error value of a result
+// whose error type does not implement the error interface.
+type resultError[Err any] struct {
+	err Err
+}
+
+// Error implements the error interface.
+// It does not use package fmt, which would add to the size of every program that uses package cm.
+func (e *resultError[Err]) Error() string {
+	switch v := any(e.err).(type) {
+	case interface{ String() string }:
+		return "result: error " + v.String()
+	case string:
+		return "result: error " + v
+	case bool:
+		return "result: error " + strconv.FormatBool(v)
+	case int:
+		return "result: error " + strconv.FormatInt(int64(v), 10)
+	case int8:
+		return "result: error " + strconv.FormatInt(int64(v), 10)
+	case int16:
+		return "result: error " + strconv.FormatInt(int64(v), 10)
+	case int32:
+		return "result: error " + strconv.FormatInt(int64(v), 10)
+	case int64:
+		return "result: error " + strconv.FormatInt(v, 10)
+	case uint:
+		return "result: error " + strconv.FormatUint(uint64(v), 10)
+	case uint8:
+		return "result: error " + strconv.FormatUint(uint64(v), 10)
+	case uint16:
+		return "result: error " + strconv.FormatUint(uint64(v), 10)
+	case uint32:
+		return "result: error " + strconv.FormatUint(uint64(v), 10)
+	case uint64:
+		return "result: error " + strconv.FormatUint(v, 10)
+	}
+	return "result: error"
+}
@@ -1,8 +1,10 @@
 package cm
 
 import (
+	"errors"
 	"fmt"
 	"runtime"
+	"strconv"
 	"testing"
 	"unsafe"
 )
@@ -215,3 +217,63 @@ func TestIssue95BoolInt64(t *testing.T) {
 		t.Errorf("*res.OK(): %v, expected %v", got, want)
 	}
 }
+
+func TestResultUnwrap(t *testing.T) {
+	ok, err, isErr := OK[Result[string, string, uint8]]("hello").Unwrap()
+	if ok != "hello" || err != 0 || isErr {
+		t.Errorf("Unwrap(): %q, %d, %t, expected %q, 0, false", ok, err, isErr, "hello")
+	}
+	ok, err, isErr = Err[Result[string, string, uint8]](7).Unwrap()
+	if ok != "" || err != 7 || !isErr {
+		t.Errorf("Unwrap(): %q, %d, %t, expected \"\", 7, true", ok, err, isErr)
+	}
+}
+
+func TestResultMap(t *testing.T) {
+	length := func(s string) uint64 { return uint64(len(s)) }
+	r1 := MapOK[Result[uint64, uint64, uint8]](OK[Result[string, string, uint8]]("hello"), length)
+	if ok := r1.OK(); ok == nil || *ok != 5 {
+		t.Errorf("MapOK: %v, expected OK 5", r1)
+	}
+	r2 := MapOK[Result[uint64, uint64, uint8]](Err[Result[string, string, uint8]](7), length)
+	if err := r2.Err(); err == nil || *err != 7 {
+		t.Errorf("MapOK: %v, expected Err 7", r2)
+	}
+
+	double := func(e uint8) uint16 { return uint16(e) * 2 }
+	r3 := MapErr[Result[string, string, uint16]](Err[Result[string, string, uint8]](7), double)
+	if err := r3.Err(); err == nil || *err != 14 {
+		t.Errorf("MapErr: %v, expected Err 14", r3)
+	}
+	r4 := MapErr[Result[string, string, uint16]](OK[Result[string, string, uint8]]("hello"), double)
+	if ok := r4.OK(); ok == nil || *ok != "hello" {
+		t.Errorf("MapErr: %v, expected OK %q", r4, "hello")
+	}
+}
+
+type testError uint8
+
+func (e testError) Error() string { return "test error " + strconv.Itoa(int(e)) }
+
+func TestResultToGo(t *testing.T) {
+	v, err := ToGo(OK[Result[string, string, uint8]]("hello"), nil)
+	if v != "hello" || err != nil {
+		t.Errorf("ToGo: %q, %v, expected %q, nil", v, err, "hello")
+	}
+
+	errNotFound := errors.New("not found")
+	_, err = ToGo(Err[Result[string, string, uint8]](7), func(uint8) error { return errNotFound })
+	if err != errNotFound {
+		t.Errorf("ToGo: %v, expected %v", err, errNotFound)
+	}
+
+	_, err = ToGo(Err[Result[string, string, testError]](7), nil)
+	if err != testError(7) {
+		t.Errorf("ToGo: %v, expected %v", err, testError(7))
+	}
+
+	_, err = ToGo(Err[Result[string, string, uint8]](7), nil)
+	if got, want := fmt.Sprint(err), "result: error 7"; got != want {
+		t.Errorf("ToGo: %q, expected %q", got, want)
+	}
+}
@@ -0,0 +1,137 @@
+package cm
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"sync"
+	"unsafe"
+)
+
+// StackUsage describes the stack used by calls to an exported function.
+type StackUsage struct {
+	// Name is the name of the exported function, e.g. "wasi:cli/run@0.2.0#run".
+	Name string
+
+	// Calls is the number of calls to the function.
+	Calls int
+
+	// Max is the high-water stack usage in bytes, measured from the start of the
+	// exported function to the deepest call to [StackProbe].
+	Max uintptr
+}
+
+// String returns a description of u, e.g. "wasi:cli/run@0.2.0#run: 4096 bytes, 1 calls".
+func (u StackUsage) String() string {
+	return u.Name + ": " + strconv.FormatUint(uint64(u.Max), 10) + " bytes, " + strconv.Itoa(u.Calls) + " calls"
+}
+
+// stack records the stack usage of calls to exported functions.
+var stack struct {
+	sync.Mutex
+	depth int     // depth of nested calls to StackEnter
+	base  uintptr // stack pointer at the outermost call to StackEnter
+	low   uintptr // lowest stack pointer observed by StackProbe
+	usage map[string]*StackUsage
+}
+
+// stackPointer returns an approximation of the stack pointer of its caller.
+//
+//go:noinline
+func stackPointer() uintptr {
+	var b byte
+	return uintptr(unsafe.Pointer(&b))
+}
+
+// StackEnter records the stack pointer at the start of a call to an exported function,
+// and returns it to be passed to [StackExit]. Bindings generated with stack usage
+// instrumentation call StackEnter and StackExit from each exported function:
+//
+//	defer cm.StackExit("wasi:cli/run@0.2.0#run", cm.StackEnter())
+//
+// The stack grows down in linear memory, so the stack usage of a call is the difference
+// between the stack pointer at the start of the call and the lowest stack pointer observed by
+// [StackProbe]. The stack of a goroutine in Go (but not TinyGo) can move when it grows,
+// so measurements of calls that grow the stack are not reliable.
+func StackEnter() uintptr {
+	sp := stackPointer()
+	stack.Lock()
+	defer stack.Unlock()
+	stack.depth++
+	if stack.depth == 1 {
+		stack.base = sp
+		stack.low = sp
+	}
+	return sp
+}
+
+// StackProbe records the stack pointer of its caller if it is the lowest stack pointer
+// observed in the current call to an exported function. Bindings generated with stack usage
+// instrumentation call StackProbe from each imported function and each lift and lower function.
+// Application code can call StackProbe in deeply nested functions to measure their stack usage.
+// It does nothing outside of a call to an exported function.
+func StackProbe() {
+	sp := stackPointer()
+	stack.Lock()
+	defer stack.Unlock()
+	if stack.depth > 0 && sp < stack.low {
+		stack.low = sp
+	}
+}
+
+// StackExit records the stack usage of a call to exported function name that started
+// with the call to [StackEnter] that returned base. It should be deferred, so it records
+// the stack usage of calls that panic. Nested calls are included in the stack usage
+// of the outermost call.
+func StackExit(name string, base uintptr) {
+	stack.Lock()
+	defer stack.Unlock()
+	if stack.depth == 0 {
+		return
+	}
+	stack.depth--
+	if stack.depth > 0 || base != stack.base {
+		return
+	}
+	if stack.usage == nil {
+		stack.usage = make(map[string]*StackUsage)
+	}
+	u := stack.usage[name]
+	if u == nil {
+		u = &StackUsage{Name: name}
+		stack.usage[name] = u
+	}
+	u.Calls++
+	if n := stack.base - stack.low; n > u.Max {
+		u.Max = n
+	}
+}
+
+// StackReport returns the stack usage recorded for each exported function,
+// sorted by descending high-water stack usage. To print it:
+//
+//	for _, u := range cm.StackReport() {
+//		fmt.Fprintln(os.Stderr, u)
+//	}
+func StackReport() []StackUsage {
+	stack.Lock()
+	defer stack.Unlock()
+	report := make([]StackUsage, 0, len(stack.usage))
+	for _, u := range stack.usage {
+		report = append(report, *u)
+	}
+	slices.SortFunc(report, func(a, b StackUsage) int {
+		if c := cmp.Compare(b.Max, a.Max); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return report
+}
+
+// ResetStackUsage discards the stack usage recorded for exported functions.
+func ResetStackUsage() {
+	stack.Lock()
+	defer stack.Unlock()
+	stack.usage = nil
+}
@@ -0,0 +1,78 @@
+package cm
+
+import (
+	"testing"
+)
+
+//go:noinline
+func stackDeep(n int) int {
+	var buf [256]byte
+	buf[n%len(buf)] = byte(n)
+	if n == 0 {
+		StackProbe()
+		return int(buf[0])
+	}
+	return stackDeep(n-1) + int(buf[n%len(buf)])
+}
+
+func stackExport(name string, depth int) {
+	defer StackExit(name, StackEnter())
+	stackDeep(depth)
+}
+
+func TestStackUsage(t *testing.T) {
+	// Grow the goroutine stack, so it does not move while measuring.
+	stackDeep(64)
+	ResetStackUsage()
+	defer ResetStackUsage()
+
+	stackExport("shallow", 0)
+	stackExport("deep", 16)
+	stackExport("deep", 1)
+
+	report := StackReport()
+	if got, want := len(report), 2; got != want {
+		t.Fatalf("len(StackReport()): %d, expected %d", got, want)
+	}
+	deep, shallow := report[0], report[1]
+	if got, want := deep.Name, "deep"; got != want {
+		t.Errorf("StackReport()[0].Name: %q, expected %q", got, want)
+	}
+	if got, want := deep.Calls, 2; got != want {
+		t.Errorf("deep: Calls: %d, expected %d", got, want)
+	}
+	if deep.Max < 16*256 {
+		t.Errorf("deep: Max: %d, expected at least %d", deep.Max, 16*256)
+	}
+	if shallow.Max >= deep.Max {
+		t.Errorf("shallow: Max: %d, expected less than %d", shallow.Max, deep.Max)
+	}
+
+	u := StackUsage{Name: "wasi:cli/run@0.2.0#run", Calls: 1, Max: 4096}
+	if got, want := u.String(), "wasi:cli/run@0.2.0#run: 4096 bytes, 1 calls"; got != want {
+		t.Errorf("StackUsage.String(): %q, expected %q", got, want)
+	}
+}
+
+func TestStackUsagePanic(t *testing.T) {
+	ResetStackUsage()
+	defer ResetStackUsage()
+
+	func() {
+		defer func() { _ = recover() }()
+		defer StackExit("panics", StackEnter())
+		StackProbe()
+		panic("oops")
+	}()
+	report := StackReport()
+	if len(report) != 1 || report[0].Name != "panics" || report[0].Calls != 1 {
+		t.Errorf("StackReport(): %v, expected 1 call to panics", report)
+	}
+
+	// StackProbe outside of an exported function does nothing.
+	StackProbe()
+	StackExit("unbalanced", 0)
+	if got, want := len(StackReport()), 1; got != want {
+		t.Errorf("len(StackReport()): %d, expected %d", got, want)
+	}
+}
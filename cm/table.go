@@ -0,0 +1,185 @@
+package cm
+
+import (
+	"strconv"
+	"sync"
+)
+
+const (
+	// handleIndexBits is the number of low bits of a [HandleTable] handle that store its slot.
+	handleIndexBits = 20
+
+	// maxHandles is the maximum number of values in a [HandleTable].
+	maxHandles = 1<<handleIndexBits - 1
+
+	// handleGenerations is the number of generations of a [HandleTable] slot before they wrap.
+	handleGenerations = 1 << (32 - handleIndexBits)
+)
+
+// HandleTable is a table of values of type T indexed by generational handles, represented
+// as a [Rep]. It can map the reps of exported resources to Go values, or any other 32-bit
+// handles to Go values. Each handle encodes the slot of its value in its low 20 bits, and
+// the generation of the slot in its high 12 bits. The generation of a slot is incremented
+// when its value is deleted, so a stale handle to a deleted value is not found, even if
+// its slot was reused, unless the slot was reused 4096 times since.
+//
+// A handle is never zero, so zero can represent no handle. A HandleTable holds
+// at most 1<<20 - 1 values.
+//
+// The zero value is an empty table that is not safe for concurrent use.
+// Use [NewHandleTable] to create a table that is. A HandleTable must not be copied after first use.
+type HandleTable[T any] struct {
+	mu         sync.Mutex
+	concurrent bool
+	slots      []handleSlot[T]
+	free       []uint32 // indexes of unused slots
+	len        int
+}
+
+type handleSlot[T any] struct {
+	value T
+	gen   uint32
+	used  bool
+}
+
+// NewHandleTable returns an empty [HandleTable]. If concurrent is true,
+// the table is safe for concurrent use by multiple goroutines.
+func NewHandleTable[T any](concurrent bool) *HandleTable[T] {
+	return &HandleTable[T]{concurrent: concurrent}
+}
+
+func (t *HandleTable[T]) lock() {
+	if t.concurrent {
+		t.mu.Lock()
+	}
+}
+
+func (t *HandleTable[T]) unlock() {
+	if t.concurrent {
+		t.mu.Unlock()
+	}
+}
+
+// slot returns the slot for handle h, or nil if h is stale or not in t.
+// The caller must hold the lock.
+func (t *HandleTable[T]) slot(h Rep) *handleSlot[T] {
+	i := uint32(h)&maxHandles - 1
+	if uint32(h)&maxHandles == 0 || int(i) >= len(t.slots) {
+		return nil
+	}
+	s := &t.slots[i]
+	if !s.used || s.gen != uint32(h)>>handleIndexBits {
+		return nil
+	}
+	return s
+}
+
+// Insert adds v to t, returning its handle.
+// It panics (traps) if t holds the maximum number of values.
+func (t *HandleTable[T]) Insert(v T) Rep {
+	t.lock()
+	defer t.unlock()
+	var i uint32
+	if n := len(t.free); n > 0 {
+		i = t.free[n-1]
+		t.free = t.free[:n-1]
+	} else {
+		if len(t.slots) >= maxHandles {
+			panic("cm: HandleTable: too many handles")
+		}
+		i = uint32(len(t.slots))
+		t.slots = append(t.slots, handleSlot[T]{})
+	}
+	s := &t.slots[i]
+	s.value = v
+	s.used = true
+	t.len++
+	return Rep(s.gen<<handleIndexBits | (i + 1))
+}
+
+// Get returns the value for handle h, and whether h is in t.
+// It returns false if h is stale, such as after its value was deleted.
+func (t *HandleTable[T]) Get(h Rep) (v T, ok bool) {
+	t.lock()
+	defer t.unlock()
+	if s := t.slot(h); s != nil {
+		return s.value, true
+	}
+	return v, false
+}
+
+// Delete removes handle h from t, returning its value, and whether h was in t.
+// The slot of h is reused with a new generation, so h becomes stale.
+func (t *HandleTable[T]) Delete(h Rep) (v T, ok bool) {
+	t.lock()
+	defer t.unlock()
+	s := t.slot(h)
+	if s == nil {
+		return v, false
+	}
+	v = s.value
+	*s = handleSlot[T]{gen: (s.gen + 1) % handleGenerations}
+	t.free = append(t.free, uint32(h)&maxHandles-1)
+	t.len--
+	return v, true
+}
+
+// Len returns the number of values in t.
+func (t *HandleTable[T]) Len() int {
+	t.lock()
+	defer t.unlock()
+	return t.len
+}
+
+// ResourceTable maps the [Rep] of each instance of an exported resource to the Go value
+// of type T that implements it. Bindings generated by wit-bindgen-go with --resource-tables
+// declare a ResourceTable for each exported resource: the generated constructor function
+// adds a Go value to the table and passes its rep to resource-new, exported methods look up
+// the Go value for the rep passed by the caller, and the exported destructor removes it.
+//
+// Reps are generational handles allocated by a [HandleTable], so a rep of a dropped
+// resource is not found, even if a new resource reuses its slot.
+// The zero value is an empty table that is safe for concurrent use.
+// A ResourceTable must not be copied after first use.
+type ResourceTable[T any] struct {
+	mu      sync.Mutex
+	handles HandleTable[T]
+}
+
+// Add adds v to t, returning its rep.
+func (t *ResourceTable[T]) Add(v T) Rep {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.handles.Insert(v)
+}
+
+// Get returns the value for rep, and whether rep is in t.
+func (t *ResourceTable[T]) Get(rep Rep) (v T, ok bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.handles.Get(rep)
+}
+
+// Must returns the value for rep. It panics (traps) if rep is not in t,
+// such as after the resource was dropped.
+func (t *ResourceTable[T]) Must(rep Rep) T {
+	v, ok := t.Get(rep)
+	if !ok {
+		panic("cm: ResourceTable: unknown rep " + strconv.FormatUint(uint64(rep), 10))
+	}
+	return v
+}
+
+// Remove removes rep from t, returning its value, and whether rep was in t.
+func (t *ResourceTable[T]) Remove(rep Rep) (v T, ok bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.handles.Delete(rep)
+}
+
+// Len returns the number of values in t.
+func (t *ResourceTable[T]) Len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.handles.Len()
+}
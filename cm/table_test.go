@@ -0,0 +1,106 @@
+package cm
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestHandleTable(t *testing.T) {
+	var table HandleTable[string]
+	a := table.Insert("a")
+	b := table.Insert("b")
+	if a == 0 || b == 0 || a == b {
+		t.Errorf("Insert: handles %d and %d, expected distinct non-zero handles", a, b)
+	}
+	if v, ok := table.Get(a); v != "a" || !ok {
+		t.Errorf("Get(%d): %q, %t, expected %q, true", a, v, ok, "a")
+	}
+	if v, ok := table.Delete(a); v != "a" || !ok {
+		t.Errorf("Delete(%d): %q, %t, expected %q, true", a, v, ok, "a")
+	}
+	if _, ok := table.Delete(a); ok {
+		t.Errorf("Delete(%d) after Delete: true, expected false", a)
+	}
+
+	// The slot of a is reused with a new generation.
+	c := table.Insert("c")
+	if c == a {
+		t.Errorf("Insert: handle %d reused, expected a new generation", c)
+	}
+	if c&maxHandles != a&maxHandles {
+		t.Errorf("Insert: handle %#x, expected slot of deleted handle %#x", c, a)
+	}
+	if v, ok := table.Get(a); ok {
+		t.Errorf("Get(%d) of stale handle: %q, true, expected false", a, v)
+	}
+	if v, ok := table.Get(c); v != "c" || !ok {
+		t.Errorf("Get(%d): %q, %t, expected %q, true", c, v, ok, "c")
+	}
+	if got, want := table.Len(), 2; got != want {
+		t.Errorf("Len: %d, expected %d", got, want)
+	}
+
+	for _, h := range []Rep{0, maxHandles, c + 1<<handleIndexBits, b + 1} {
+		if v, ok := table.Get(h); ok {
+			t.Errorf("Get(%#x): %q, true, expected false", h, v)
+		}
+	}
+}
+
+func TestHandleTableConcurrent(t *testing.T) {
+	table := NewHandleTable[int](true)
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range 100 {
+				h := table.Insert(i*100 + j)
+				if v, ok := table.Get(h); v != i*100+j || !ok {
+					t.Errorf("Get(%d): %d, %t, expected %d, true", h, v, ok, i*100+j)
+				}
+				table.Delete(h)
+			}
+		}()
+	}
+	wg.Wait()
+	if got, want := table.Len(), 0; got != want {
+		t.Errorf("Len: %d, expected %d", got, want)
+	}
+}
+
+func TestResourceTable(t *testing.T) {
+	var table ResourceTable[string]
+	a := table.Add("a")
+	b := table.Add("b")
+	if a == 0 || a == b {
+		t.Errorf("Add: reps %d and %d, expected distinct non-zero reps", a, b)
+	}
+	if got, want := table.Len(), 2; got != want {
+		t.Errorf("Len: %d, expected %d", got, want)
+	}
+	if v, ok := table.Get(a); v != "a" || !ok {
+		t.Errorf("Get(%d): %q, %t, expected %q, true", a, v, ok, "a")
+	}
+	if v := table.Must(b); v != "b" {
+		t.Errorf("Must(%d): %q, expected %q", b, v, "b")
+	}
+
+	if v, ok := table.Remove(a); v != "a" || !ok {
+		t.Errorf("Remove(%d): %q, %t, expected %q, true", a, v, ok, "a")
+	}
+	if v, ok := table.Get(a); v != "" || ok {
+		t.Errorf("Get(%d) after Remove: %q, %t, expected %q, false", a, v, ok, "")
+	}
+	if _, ok := table.Remove(a); ok {
+		t.Errorf("Remove(%d) after Remove: true, expected false", a)
+	}
+	mustPanic(t, "Must after Remove", func() { table.Must(a) })
+
+	if c := table.Add("c"); c == a || c == b {
+		t.Errorf("Add: rep %d reused, expected a new rep", c)
+	}
+	if got, want := table.Len(), 2; got != want {
+		t.Errorf("Len: %d, expected %d", got, want)
+	}
+}
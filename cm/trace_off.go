@@ -0,0 +1,30 @@
+//go:build !cm_trace
+
+package cm
+
+import "unsafe"
+
+// traceEnabled is true if the program is built with the cm_trace build tag.
+// Tracing is compiled out of programs built without it.
+const traceEnabled = false
+
+// SetTrace has no effect unless the program is built with the cm_trace build tag,
+// which enables tracing of Canonical ABI lifts and lowers and calls across the
+// component boundary.
+func SetTrace(enabled bool) {}
+
+// Tracing reports whether tracing is enabled.
+// It returns false unless the program is built with the cm_trace build tag.
+func Tracing() bool {
+	return false
+}
+
+// TraceImport records a call to an imported function if tracing is enabled.
+// It does nothing unless the program is built with the cm_trace build tag.
+func TraceImport(module, name string) {}
+
+// TraceExport records a call to an exported function if tracing is enabled.
+// It does nothing unless the program is built with the cm_trace build tag.
+func TraceExport(module, name string) {}
+
+func traceMemory(op string, typ any, ptr unsafe.Pointer, len, elem uintptr) {}
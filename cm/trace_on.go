@@ -0,0 +1,87 @@
+//go:build cm_trace
+
+package cm
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// traceEnabled is true if the program is built with the cm_trace build tag.
+const traceEnabled = true
+
+// traceDisabled reports whether tracing was disabled with [SetTrace].
+var traceDisabled atomic.Bool
+
+// traceOutput is the writer that trace records are written to. See [SetTraceOutput].
+var traceOutput struct {
+	sync.Mutex
+	w io.Writer
+}
+
+// SetTrace enables or disables tracing. Tracing is enabled at startup in programs built
+// with the cm_trace build tag. When enabled, this package writes a record of each
+// Canonical ABI lift and lower of a string or list, with its pointer, length, and size in bytes,
+// and of each call across the component boundary by bindings generated with --debug,
+// to the writer set with [SetTraceOutput], which defaults to [os.Stderr]:
+//
+//	cm: import wasi:random/random@0.2.0 get-random-bytes
+//	cm: lift cm.List[uint8] ptr=0x11ab0 len=16 size=16
+//
+// Tracing is intended to help debug memory corruption in generated bindings, such as a list
+// lifted from memory that was freed or reused.
+func SetTrace(enabled bool) {
+	traceDisabled.Store(!enabled)
+}
+
+// Tracing reports whether tracing is enabled. See [SetTrace].
+func Tracing() bool {
+	return !traceDisabled.Load()
+}
+
+// SetTraceOutput sets the writer that trace records are written to.
+// If w is nil, records are written to [os.Stderr], which is WASI stderr in a component.
+// SetTraceOutput is only declared in programs built with the cm_trace build tag.
+func SetTraceOutput(w io.Writer) {
+	traceOutput.Lock()
+	traceOutput.w = w
+	traceOutput.Unlock()
+}
+
+// TraceImport records a call to function name imported from Component Model module,
+// e.g. "wasi:random/random@0.2.0" and "get-random-bytes", if tracing is enabled.
+// Bindings generated by wit-bindgen-go with --debug call TraceImport from each imported function.
+func TraceImport(module, name string) {
+	if Tracing() {
+		tracef("import %s %s", module, name)
+	}
+}
+
+// TraceExport records a call to function name exported to Component Model module,
+// e.g. "wasi:cli/run@0.2.0" and "run", if tracing is enabled.
+// Bindings generated by wit-bindgen-go with --debug call TraceExport from each exported function.
+func TraceExport(module, name string) {
+	if Tracing() {
+		tracef("export %s %s", module, name)
+	}
+}
+
+// traceMemory records a lift or lower of a string or list of type typ, with len elements
+// of size elem bytes at ptr.
+func traceMemory(op string, typ any, ptr unsafe.Pointer, len, elem uintptr) {
+	tracef("%s %T ptr=%#x len=%d size=%d", op, typ, uintptr(ptr), len, len*elem)
+}
+
+func tracef(format string, args ...any) {
+	traceOutput.Lock()
+	defer traceOutput.Unlock()
+	w := traceOutput.w
+	if w == nil {
+		w = os.Stderr
+	}
+	fmt.Fprintf(w, "cm: "+format+"\n", args...)
+}
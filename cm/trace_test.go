@@ -0,0 +1,53 @@
+//go:build cm_trace
+
+package cm
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+func TestTrace(t *testing.T) {
+	var buf bytes.Buffer
+	SetTraceOutput(&buf)
+	defer SetTraceOutput(nil)
+	defer SetTrace(Tracing())
+
+	SetTrace(false)
+	LiftString[string]((*uint8)(nil), 0)
+	TraceImport("wasi:random/random@0.2.0", "get-random-bytes")
+	if buf.Len() != 0 {
+		t.Errorf("trace disabled: got output %q", buf.String())
+	}
+
+	SetTrace(true)
+	s := "hello"
+	data, n := LowerString(s)
+	_ = LiftString[string](data, n)
+	list := ToList([]uint32{1, 2, 3})
+	ptr, n := LowerList(list)
+	_ = LiftList[List[uint32]](ptr, n)
+	TraceImport("wasi:random/random@0.2.0", "get-random-bytes")
+	TraceExport("wasi:cli/run@0.2.0", "run")
+
+	want := []string{
+		fmt.Sprintf("cm: lower string ptr=%#x len=5 size=5", uintptr(unsafe.Pointer(data))),
+		fmt.Sprintf("cm: lift string ptr=%#x len=5 size=5", uintptr(unsafe.Pointer(data))),
+		fmt.Sprintf("cm: lower cm.List[uint32] ptr=%#x len=3 size=12", uintptr(unsafe.Pointer(ptr))),
+		fmt.Sprintf("cm: lift cm.List[uint32] ptr=%#x len=3 size=12", uintptr(unsafe.Pointer(ptr))),
+		"cm: import wasi:random/random@0.2.0 get-random-bytes",
+		"cm: export wasi:cli/run@0.2.0 run",
+	}
+	got := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(got) != len(want) {
+		t.Fatalf("trace: got %d records, expected %d:\n%s", len(got), len(want), buf.String())
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("trace record %d: %q, expected %q", i, got[i], want[i])
+		}
+	}
+}
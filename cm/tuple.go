@@ -9,6 +9,11 @@ type Tuple[T0, T1 any] struct {
 	F1 T1
 }
 
+// Values returns the fields of tuple t.
+func (t Tuple[T0, T1]) Values() (T0, T1) {
+	return t.F0, t.F1
+}
+
 // Tuple3 represents a [Component Model tuple] with 3 fields.
 //
 // [Component Model tuple]: https://component-model.bytecodealliance.org/design/wit.html#tuples
@@ -19,6 +24,11 @@ type Tuple3[T0, T1, T2 any] struct {
 	F2 T2
 }
 
+// Values returns the fields of tuple t.
+func (t Tuple3[T0, T1, T2]) Values() (T0, T1, T2) {
+	return t.F0, t.F1, t.F2
+}
+
 // Tuple4 represents a [Component Model tuple] with 4 fields.
 //
 // [Component Model tuple]: https://component-model.bytecodealliance.org/design/wit.html#tuples
@@ -30,6 +40,11 @@ type Tuple4[T0, T1, T2, T3 any] struct {
 	F3 T3
 }
 
+// Values returns the fields of tuple t.
+func (t Tuple4[T0, T1, T2, T3]) Values() (T0, T1, T2, T3) {
+	return t.F0, t.F1, t.F2, t.F3
+}
+
 // Tuple5 represents a [Component Model tuple] with 5 fields.
 //
 // [Component Model tuple]: https://component-model.bytecodealliance.org/design/wit.html#tuples
@@ -42,6 +57,11 @@ type Tuple5[T0, T1, T2, T3, T4 any] struct {
 	F4 T4
 }
 
+// Values returns the fields of tuple t.
+func (t Tuple5[T0, T1, T2, T3, T4]) Values() (T0, T1, T2, T3, T4) {
+	return t.F0, t.F1, t.F2, t.F3, t.F4
+}
+
 // Tuple6 represents a [Component Model tuple] with 6 fields.
 //
 // [Component Model tuple]: https://component-model.bytecodealliance.org/design/wit.html#tuples
@@ -55,6 +75,11 @@ type Tuple6[T0, T1, T2, T3, T4, T5 any] struct {
 	F5 T5
 }
 
+// Values returns the fields of tuple t.
+func (t Tuple6[T0, T1, T2, T3, T4, T5]) Values() (T0, T1, T2, T3, T4, T5) {
+	return t.F0, t.F1, t.F2, t.F3, t.F4, t.F5
+}
+
 // Tuple7 represents a [Component Model tuple] with 7 fields.
 //
 // [Component Model tuple]: https://component-model.bytecodealliance.org/design/wit.html#tuples
@@ -69,6 +94,11 @@ type Tuple7[T0, T1, T2, T3, T4, T5, T6 any] struct {
 	F6 T6
 }
 
+// Values returns the fields of tuple t.
+func (t Tuple7[T0, T1, T2, T3, T4, T5, T6]) Values() (T0, T1, T2, T3, T4, T5, T6) {
+	return t.F0, t.F1, t.F2, t.F3, t.F4, t.F5, t.F6
+}
+
 // Tuple8 represents a [Component Model tuple] with 8 fields.
 //
 // [Component Model tuple]: https://component-model.bytecodealliance.org/design/wit.html#tuples
@@ -84,6 +114,11 @@ type Tuple8[T0, T1, T2, T3, T4, T5, T6, T7 any] struct {
 	F7 T7
 }
 
+// Values returns the fields of tuple t.
+func (t Tuple8[T0, T1, T2, T3, T4, T5, T6, T7]) Values() (T0, T1, T2, T3, T4, T5, T6, T7) {
+	return t.F0, t.F1, t.F2, t.F3, t.F4, t.F5, t.F6, t.F7
+}
+
 // Tuple9 represents a [Component Model tuple] with 9 fields.
 //
 // [Component Model tuple]: https://component-model.bytecodealliance.org/design/wit.html#tuples
@@ -100,6 +135,11 @@ type Tuple9[T0, T1, T2, T3, T4, T5, T6, T7, T8 any] struct {
 	F8 T8
 }
 
+// Values returns the fields of tuple t.
+func (t Tuple9[T0, T1, T2, T3, T4, T5, T6, T7, T8]) Values() (T0, T1, T2, T3, T4, T5, T6, T7, T8) {
+	return t.F0, t.F1, t.F2, t.F3, t.F4, t.F5, t.F6, t.F7, t.F8
+}
+
 // Tuple10 represents a [Component Model tuple] with 10 fields.
 //
 // [Component Model tuple]: https://component-model.bytecodealliance.org/design/wit.html#tuples
@@ -117,6 +157,11 @@ type Tuple10[T0, T1, T2, T3, T4, T5, T6, T7, T8, T9 any] struct {
 	F9 T9
 }
 
+// Values returns the fields of tuple t.
+func (t Tuple10[T0, T1, T2, T3, T4, T5, T6, T7, T8, T9]) Values() (T0, T1, T2, T3, T4, T5, T6, T7, T8, T9) {
+	return t.F0, t.F1, t.F2, t.F3, t.F4, t.F5, t.F6, t.F7, t.F8, t.F9
+}
+
 // Tuple11 represents a [Component Model tuple] with 11 fields.
 //
 // [Component Model tuple]: https://component-model.bytecodealliance.org/design/wit.html#tuples
@@ -135,6 +180,11 @@ type Tuple11[T0, T1, T2, T3, T4, T5, T6, T7, T8, T9, T10 any] struct {
 	F10 T10
 }
 
+// Values returns the fields of tuple t.
+func (t Tuple11[T0, T1, T2, T3, T4, T5, T6, T7, T8, T9, T10]) Values() (T0, T1, T2, T3, T4, T5, T6, T7, T8, T9, T10) {
+	return t.F0, t.F1, t.F2, t.F3, t.F4, t.F5, t.F6, t.F7, t.F8, t.F9, t.F10
+}
+
 // Tuple12 represents a [Component Model tuple] with 12 fields.
 //
 // [Component Model tuple]: https://component-model.bytecodealliance.org/design/wit.html#tuples
@@ -154,6 +204,11 @@ type Tuple12[T0, T1, T2, T3, T4, T5, T6, T7, T8, T9, T10, T11 any] struct {
 	F11 T11
 }
 
+// Values returns the fields of tuple t.
+func (t Tuple12[T0, T1, T2, T3, T4, T5, T6, T7, T8, T9, T10, T11]) Values() (T0, T1, T2, T3, T4, T5, T6, T7, T8, T9, T10, T11) {
+	return t.F0, t.F1, t.F2, t.F3, t.F4, t.F5, t.F6, t.F7, t.F8, t.F9, t.F10, t.F11
+}
+
 // Tuple13 represents a [Component Model tuple] with 13 fields.
 //
 // [Component Model tuple]: https://component-model.bytecodealliance.org/design/wit.html#tuples
@@ -174,6 +229,11 @@ type Tuple13[T0, T1, T2, T3, T4, T5, T6, T7, T8, T9, T10, T11, T12 any] struct {
 	F12 T12
 }
 
+// Values returns the fields of tuple t.
+func (t Tuple13[T0, T1, T2, T3, T4, T5, T6, T7, T8, T9, T10, T11, T12]) Values() (T0, T1, T2, T3, T4, T5, T6, T7, T8, T9, T10, T11, T12) {
+	return t.F0, t.F1, t.F2, t.F3, t.F4, t.F5, t.F6, t.F7, t.F8, t.F9, t.F10, t.F11, t.F12
+}
+
 // Tuple14 represents a [Component Model tuple] with 14 fields.
 //
 // [Component Model tuple]: https://component-model.bytecodealliance.org/design/wit.html#tuples
@@ -195,6 +255,11 @@ type Tuple14[T0, T1, T2, T3, T4, T5, T6, T7, T8, T9, T10, T11, T12, T13 any] str
 	F13 T13
 }
 
+// Values returns the fields of tuple t.
+func (t Tuple14[T0, T1, T2, T3, T4, T5, T6, T7, T8, T9, T10, T11, T12, T13]) Values() (T0, T1, T2, T3, T4, T5, T6, T7, T8, T9, T10, T11, T12, T13) {
+	return t.F0, t.F1, t.F2, t.F3, t.F4, t.F5, t.F6, t.F7, t.F8, t.F9, t.F10, t.F11, t.F12, t.F13
+}
+
 // Tuple15 represents a [Component Model tuple] with 15 fields.
 //
 // [Component Model tuple]: https://component-model.bytecodealliance.org/design/wit.html#tuples
@@ -217,6 +282,11 @@ type Tuple15[T0, T1, T2, T3, T4, T5, T6, T7, T8, T9, T10, T11, T12, T13, T14 any
 	F14 T14
 }
 
+// Values returns the fields of tuple t.
+func (t Tuple15[T0, T1, T2, T3, T4, T5, T6, T7, T8, T9, T10, T11, T12, T13, T14]) Values() (T0, T1, T2, T3, T4, T5, T6, T7, T8, T9, T10, T11, T12, T13, T14) {
+	return t.F0, t.F1, t.F2, t.F3, t.F4, t.F5, t.F6, t.F7, t.F8, t.F9, t.F10, t.F11, t.F12, t.F13, t.F14
+}
+
 // Tuple16 represents a [Component Model tuple] with 16 fields.
 //
 // [Component Model tuple]: https://component-model.bytecodealliance.org/design/wit.html#tuples
@@ -240,6 +310,11 @@ type Tuple16[T0, T1, T2, T3, T4, T5, T6, T7, T8, T9, T10, T11, T12, T13, T14, T1
 	F15 T15
 }
 
+// Values returns the fields of tuple t.
+func (t Tuple16[T0, T1, T2, T3, T4, T5, T6, T7, T8, T9, T10, T11, T12, T13, T14, T15]) Values() (T0, T1, T2, T3, T4, T5, T6, T7, T8, T9, T10, T11, T12, T13, T14, T15) {
+	return t.F0, t.F1, t.F2, t.F3, t.F4, t.F5, t.F6, t.F7, t.F8, t.F9, t.F10, t.F11, t.F12, t.F13, t.F14, t.F15
+}
+
 // MaxTuple specifies the maximum number of fields in a Tuple* type, currently [Tuple16].
 // See https://github.com/WebAssembly/component-model/issues/373 for more information.
 const MaxTuple = 16
@@ -15,3 +15,15 @@ func TestTuple(t *testing.T) {
 	_ = Tuple7[string, bool, uint8, uint16, uint32, uint64, float32]{HL, "hello", false, math.MaxUint8, math.MaxUint16, math.MaxUint32, math.MaxUint64, math.MaxFloat32}
 	_ = Tuple8[string, bool, uint8, uint16, uint32, uint64, float32, float64]{HL, "hello", false, math.MaxUint8, math.MaxUint16, math.MaxUint32, math.MaxUint64, math.MaxFloat32, math.MaxFloat64}
 }
+
+func TestTupleValues(t *testing.T) {
+	s, b := Tuple[string, bool]{F0: "hello", F1: true}.Values()
+	if s != "hello" || !b {
+		t.Errorf("Tuple.Values: %q, %t, expected %q, %t", s, b, "hello", true)
+	}
+	tup := Tuple16[uint8, uint8, uint8, uint8, uint8, uint8, uint8, uint8, uint8, uint8, uint8, uint8, uint8, uint8, uint8, string]{F15: "last"}
+	_, _, _, _, _, _, _, _, _, _, _, _, _, _, _, last := tup.Values()
+	if last != "last" {
+		t.Errorf("Tuple16.Values: F15 == %q, expected %q", last, "last")
+	}
+}
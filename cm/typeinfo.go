@@ -0,0 +1,129 @@
+package cm
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// TypeInfo is compact metadata describing the WIT type of a generated Go type.
+// It allows generic middleware, such as logging, metrics, or serialization,
+// to introspect values of generated types, including result and option types,
+// without reflecting over unexported fields in this package.
+type TypeInfo struct {
+	// Name is the qualified WIT name of the type, e.g. "wasi:filesystem/types@0.2.0#error-code".
+	Name string
+
+	// Kind is the kind of WIT type.
+	Kind Kind
+
+	// Cases are the names of the fields of a record, the flags of a flags type, or the
+	// cases of a variant, enum, option ("none", "some"), or result ("ok", "error"), in order.
+	// The index of a case is its tag, or bit position for flags.
+	Cases []string
+}
+
+// CaseName returns the name of case i, or an empty string if i is out of range.
+func (t *TypeInfo) CaseName(i int) string {
+	if i < 0 || i >= len(t.Cases) {
+		return ""
+	}
+	return t.Cases[i]
+}
+
+// types holds the type metadata registered with [RegisterTypes].
+var types struct {
+	sync.RWMutex
+	pkgs map[string]map[string]*TypeInfo
+}
+
+// RegisterTypes registers metadata for the Go types in package pkgPath, indexed by Go type name.
+// Bindings generated with type info call RegisterTypes when initialized.
+// If infos is nil, any registered metadata for pkgPath is removed.
+func RegisterTypes(pkgPath string, infos map[string]*TypeInfo) {
+	types.Lock()
+	defer types.Unlock()
+	if infos == nil {
+		delete(types.pkgs, pkgPath)
+		return
+	}
+	if types.pkgs == nil {
+		types.pkgs = make(map[string]map[string]*TypeInfo)
+	}
+	types.pkgs[pkgPath] = infos
+}
+
+// LookupType returns the [TypeInfo] registered with [RegisterTypes] for the Go type
+// with name in package pkgPath, or nil if none is registered. Callers with a value of
+// a generated type can obtain pkgPath and name from its [reflect.Type].
+func LookupType(pkgPath, name string) *TypeInfo {
+	types.RLock()
+	defer types.RUnlock()
+	return types.pkgs[pkgPath][name]
+}
+
+// Describable is implemented by generated Go types with type metadata. See [Describe].
+type Describable interface {
+	// WITType returns the TypeInfo for the WIT type of the receiver, and its
+	// variant, enum, option, or result case, or the bits of its flags.
+	// For other kinds of types, the second result is 0.
+	WITType() (*TypeInfo, uint64)
+}
+
+// Describe returns a human-readable description of v for debugging and logging.
+// If v implements [Describable], variant, enum, option, and result values are described
+// as their unqualified WIT type name and case, e.g. "error-code::not-permitted".
+// Flags values are described with the names of the flags that are set, e.g. "path-flags::{symlink-follow}",
+// and values of other types are described by their WIT type name.
+// If v does not implement Describable, Describe returns the result of its String method, if any,
+// or an empty string.
+func Describe(v any) string {
+	d, ok := v.(Describable)
+	if !ok {
+		if s, ok := v.(interface{ String() string }); ok {
+			return s.String()
+		}
+		return ""
+	}
+	info, tag := d.WITType()
+	if info == nil {
+		return ""
+	}
+	name := info.Name
+	if i := strings.LastIndexByte(name, '#'); i >= 0 {
+		name = name[i+1:]
+	}
+	switch info.Kind {
+	case KindVariant, KindEnum, KindOption, KindResult:
+		var c string
+		if tag < uint64(len(info.Cases)) {
+			c = info.CaseName(int(tag))
+		}
+		if c == "" {
+			c = strconv.FormatUint(tag, 10)
+		}
+		return name + "::" + c
+	case KindFlags:
+		var b strings.Builder
+		b.WriteString(name)
+		b.WriteString("::{")
+		n := 0
+		for i := 0; i < 64; i++ {
+			if tag&(1<<i) == 0 {
+				continue
+			}
+			if n > 0 {
+				b.WriteString(", ")
+			}
+			if c := info.CaseName(i); c != "" {
+				b.WriteString(c)
+			} else {
+				b.WriteString(strconv.Itoa(i))
+			}
+			n++
+		}
+		b.WriteString("}")
+		return b.String()
+	}
+	return name
+}
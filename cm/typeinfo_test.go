@@ -0,0 +1,71 @@
+package cm
+
+import "testing"
+
+func TestRegisterTypes(t *testing.T) {
+	const pkgPath = "example.com/test/types"
+	info := &TypeInfo{Name: "test:types/types#result-code", Kind: KindResult, Cases: []string{"ok", "error"}}
+	RegisterTypes(pkgPath, map[string]*TypeInfo{"ResultCode": info})
+
+	if got, want := LookupType(pkgPath, "ResultCode"), info; got != want {
+		t.Errorf("LookupType(%q, %q): %v, expected %v", pkgPath, "ResultCode", got, want)
+	}
+	if got := LookupType(pkgPath, "Missing"); got != nil {
+		t.Errorf("LookupType(%q, %q): %v, expected nil", pkgPath, "Missing", got)
+	}
+	if got, want := info.CaseName(1), "error"; got != want {
+		t.Errorf("CaseName(1): %q, expected %q", got, want)
+	}
+	if got, want := info.CaseName(2), ""; got != want {
+		t.Errorf("CaseName(2): %q, expected %q", got, want)
+	}
+
+	RegisterTypes(pkgPath, nil)
+	if got := LookupType(pkgPath, "ResultCode"); got != nil {
+		t.Errorf("LookupType(%q, %q) after unregister: %v, expected nil", pkgPath, "ResultCode", got)
+	}
+}
+
+type describeEnum uint8
+
+func (v describeEnum) WITType() (*TypeInfo, uint64) {
+	return &TypeInfo{Name: "test:types/types#error-code", Kind: KindEnum, Cases: []string{"access", "not-permitted"}}, uint64(v)
+}
+
+type describeFlags uint8
+
+func (v describeFlags) WITType() (*TypeInfo, uint64) {
+	return &TypeInfo{Name: "test:types/types#path-flags", Kind: KindFlags, Cases: []string{"read", "write"}}, uint64(v)
+}
+
+type describeRecord struct{}
+
+func (v describeRecord) WITType() (*TypeInfo, uint64) {
+	return &TypeInfo{Name: "test:types/types#stat", Kind: KindRecord, Cases: []string{"size"}}, 0
+}
+
+type describeStringer struct{}
+
+func (describeStringer) String() string { return "stringer" }
+
+func TestDescribe(t *testing.T) {
+	tests := []struct {
+		v    any
+		want string
+	}{
+		{describeEnum(1), "error-code::not-permitted"},
+		{describeEnum(7), "error-code::7"},
+		{describeFlags(0), "path-flags::{}"},
+		{describeFlags(3), "path-flags::{read, write}"},
+		{describeFlags(6), "path-flags::{write, 2}"},
+		{describeRecord{}, "stat"},
+		{describeStringer{}, "stringer"},
+		{42, ""},
+		{nil, ""},
+	}
+	for _, tt := range tests {
+		if got := Describe(tt.v); got != tt.want {
+			t.Errorf("Describe(%v): %q, expected %q", tt.v, got, tt.want)
+		}
+	}
+}
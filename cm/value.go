@@ -0,0 +1,493 @@
+package cm
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Kind represents the kind of WIT type held by a dynamic [Value].
+type Kind uint8
+
+// The kinds of [Value].
+const (
+	KindInvalid Kind = iota
+	KindBool
+	KindS8
+	KindU8
+	KindS16
+	KindU16
+	KindS32
+	KindU32
+	KindS64
+	KindU64
+	KindF32
+	KindF64
+	KindChar
+	KindString
+	KindList
+	KindRecord
+	KindTuple
+	KindVariant
+	KindEnum
+	KindOption
+	KindResult
+	KindFlags
+	KindHandle
+)
+
+var stringsKind = [...]string{
+	"invalid",
+	"bool",
+	"s8",
+	"u8",
+	"s16",
+	"u16",
+	"s32",
+	"u32",
+	"s64",
+	"u64",
+	"f32",
+	"f64",
+	"char",
+	"string",
+	"list",
+	"record",
+	"tuple",
+	"variant",
+	"enum",
+	"option",
+	"result",
+	"flags",
+	"handle",
+}
+
+// String implements [fmt.Stringer], returning the WIT name of k.
+func (k Kind) String() string {
+	if int(k) < len(stringsKind) {
+		return stringsKind[k]
+	}
+	return "kind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// Value is a dynamically-typed representation of a WIT value.
+// It allows tools such as REPLs and test drivers to construct and inspect
+// WIT values at runtime without compile-time knowledge of generated Go types.
+//
+// The zero value is an invalid Value. Values are immutable once constructed.
+// Methods that access the contents of a Value panic if called on a Value of the wrong [Kind].
+type Value struct {
+	kind   Kind
+	bits   uint64  // bool, integer, float, char, or handle
+	str    string  // string, or case name of a variant, enum, option, or result
+	elems  []Value // list or tuple elements, record field values, or a single payload
+	labels []string
+}
+
+// Field is a named field in a WIT record, used to construct a record [Value].
+type Field struct {
+	Name  string
+	Value Value
+}
+
+// BoolValue returns a [Value] of kind bool.
+func BoolValue(v bool) Value {
+	var bits uint64
+	if v {
+		bits = 1
+	}
+	return Value{kind: KindBool, bits: bits}
+}
+
+// S8Value returns a [Value] of kind s8.
+func S8Value(v int8) Value { return Value{kind: KindS8, bits: uint64(v)} }
+
+// U8Value returns a [Value] of kind u8.
+func U8Value(v uint8) Value { return Value{kind: KindU8, bits: uint64(v)} }
+
+// S16Value returns a [Value] of kind s16.
+func S16Value(v int16) Value { return Value{kind: KindS16, bits: uint64(v)} }
+
+// U16Value returns a [Value] of kind u16.
+func U16Value(v uint16) Value { return Value{kind: KindU16, bits: uint64(v)} }
+
+// S32Value returns a [Value] of kind s32.
+func S32Value(v int32) Value { return Value{kind: KindS32, bits: uint64(v)} }
+
+// U32Value returns a [Value] of kind u32.
+func U32Value(v uint32) Value { return Value{kind: KindU32, bits: uint64(v)} }
+
+// S64Value returns a [Value] of kind s64.
+func S64Value(v int64) Value { return Value{kind: KindS64, bits: uint64(v)} }
+
+// U64Value returns a [Value] of kind u64.
+func U64Value(v uint64) Value { return Value{kind: KindU64, bits: v} }
+
+// F32Value returns a [Value] of kind f32.
+func F32Value(v float32) Value { return Value{kind: KindF32, bits: math.Float64bits(float64(v))} }
+
+// F64Value returns a [Value] of kind f64.
+func F64Value(v float64) Value { return Value{kind: KindF64, bits: math.Float64bits(v)} }
+
+// CharValue returns a [Value] of kind char.
+func CharValue(v rune) Value { return Value{kind: KindChar, bits: uint64(v)} }
+
+// StringValue returns a [Value] of kind string.
+func StringValue(v string) Value { return Value{kind: KindString, str: v} }
+
+// ListValue returns a [Value] of kind list with elements elems.
+func ListValue(elems ...Value) Value {
+	return Value{kind: KindList, elems: clone(elems)}
+}
+
+// TupleValue returns a [Value] of kind tuple with elements elems.
+func TupleValue(elems ...Value) Value {
+	return Value{kind: KindTuple, elems: clone(elems)}
+}
+
+// RecordValue returns a [Value] of kind record with fields.
+func RecordValue(fields ...Field) Value {
+	v := Value{kind: KindRecord, elems: make([]Value, len(fields)), labels: make([]string, len(fields))}
+	for i, f := range fields {
+		v.labels[i] = f.Name
+		v.elems[i] = f.Value
+	}
+	return v
+}
+
+// VariantValue returns a [Value] of kind variant with case name and an optional payload.
+func VariantValue(name string, payload ...Value) Value {
+	return Value{kind: KindVariant, str: name, elems: clonePayload(payload)}
+}
+
+// EnumValue returns a [Value] of kind enum with case name.
+func EnumValue(name string) Value {
+	return Value{kind: KindEnum, str: name}
+}
+
+// FlagsValue returns a [Value] of kind flags with the set flags names.
+func FlagsValue(names ...string) Value {
+	return Value{kind: KindFlags, labels: clone(names)}
+}
+
+// SomeValue returns a [Value] of kind option representing the some case with value v.
+func SomeValue(v Value) Value {
+	return Value{kind: KindOption, str: "some", elems: []Value{v}}
+}
+
+// NoneValue returns a [Value] of kind option representing the none case.
+func NoneValue() Value {
+	return Value{kind: KindOption, str: "none"}
+}
+
+// OKValue returns a [Value] of kind result representing the ok case with an optional payload.
+func OKValue(payload ...Value) Value {
+	return Value{kind: KindResult, str: "ok", elems: clonePayload(payload)}
+}
+
+// ErrValue returns a [Value] of kind result representing the error case with an optional payload.
+func ErrValue(payload ...Value) Value {
+	return Value{kind: KindResult, str: "err", elems: clonePayload(payload)}
+}
+
+// HandleValue returns a [Value] of kind handle, representing an own or borrow resource handle.
+func HandleValue[T ~uint32](h T) Value {
+	return Value{kind: KindHandle, bits: uint64(h)}
+}
+
+func clone[T any](s []T) []T {
+	if len(s) == 0 {
+		return nil
+	}
+	return append([]T(nil), s...)
+}
+
+func clonePayload(payload []Value) []Value {
+	if len(payload) > 1 {
+		panic("cm: more than one payload value")
+	}
+	return clone(payload)
+}
+
+// Kind returns the [Kind] of v.
+func (v Value) Kind() Kind {
+	return v.kind
+}
+
+// IsValid reports whether v represents a value. It returns false for the zero Value.
+func (v Value) IsValid() bool {
+	return v.kind != KindInvalid
+}
+
+// Expect returns a *[ValueError] if v is not of [Kind] k.
+func (v Value) Expect(k Kind) error {
+	if v.kind != k {
+		return &ValueError{Want: k, Got: v.kind}
+	}
+	return nil
+}
+
+func (v Value) mustBe(method string, kinds ...Kind) {
+	for _, k := range kinds {
+		if v.kind == k {
+			return
+		}
+	}
+	panic("cm: call of Value." + method + " on " + v.kind.String() + " Value")
+}
+
+// Bool returns the value of v. It panics if v is not of kind bool.
+func (v Value) Bool() bool {
+	v.mustBe("Bool", KindBool)
+	return v.bits != 0
+}
+
+// Int returns the value of v. It panics if v is not of kind s8, s16, s32, or s64.
+func (v Value) Int() int64 {
+	v.mustBe("Int", KindS8, KindS16, KindS32, KindS64)
+	return int64(v.bits)
+}
+
+// Uint returns the value of v. It panics if v is not of kind u8, u16, u32, or u64.
+func (v Value) Uint() uint64 {
+	v.mustBe("Uint", KindU8, KindU16, KindU32, KindU64)
+	return v.bits
+}
+
+// Float returns the value of v. It panics if v is not of kind f32 or f64.
+func (v Value) Float() float64 {
+	v.mustBe("Float", KindF32, KindF64)
+	return math.Float64frombits(v.bits)
+}
+
+// Char returns the value of v. It panics if v is not of kind char.
+func (v Value) Char() rune {
+	v.mustBe("Char", KindChar)
+	return rune(v.bits)
+}
+
+// Handle returns the resource handle of v. It panics if v is not of kind handle.
+func (v Value) Handle() uint32 {
+	v.mustBe("Handle", KindHandle)
+	return uint32(v.bits)
+}
+
+// Len returns the number of elements in a list or tuple, the number of fields in a record,
+// or the number of set flags. It panics if v is not one of those kinds.
+func (v Value) Len() int {
+	v.mustBe("Len", KindList, KindTuple, KindRecord, KindFlags)
+	if v.kind == KindFlags {
+		return len(v.labels)
+	}
+	return len(v.elems)
+}
+
+// Index returns the i'th element of a list or tuple, or the i'th field value of a record.
+// It panics if v is not one of those kinds, or if i is out of range.
+func (v Value) Index(i int) Value {
+	v.mustBe("Index", KindList, KindTuple, KindRecord)
+	return v.elems[i]
+}
+
+// FieldName returns the name of the i'th field of a record.
+// It panics if v is not of kind record, or if i is out of range.
+func (v Value) FieldName(i int) string {
+	v.mustBe("FieldName", KindRecord)
+	return v.labels[i]
+}
+
+// Field returns the value of the record field with name, and whether the field was found.
+// It panics if v is not of kind record.
+func (v Value) Field(name string) (Value, bool) {
+	v.mustBe("Field", KindRecord)
+	for i, label := range v.labels {
+		if label == name {
+			return v.elems[i], true
+		}
+	}
+	return Value{}, false
+}
+
+// Case returns the case name of a variant or enum, "some" or "none" for an option,
+// or "ok" or "err" for a result. It panics if v is not one of those kinds.
+func (v Value) Case() string {
+	v.mustBe("Case", KindVariant, KindEnum, KindOption, KindResult)
+	return v.str
+}
+
+// Payload returns the value associated with a variant, option, or result case,
+// and whether the case has an associated value. It panics if v is not one of those kinds.
+func (v Value) Payload() (Value, bool) {
+	v.mustBe("Payload", KindVariant, KindOption, KindResult)
+	if len(v.elems) == 0 {
+		return Value{}, false
+	}
+	return v.elems[0], true
+}
+
+// Flags returns the names of the set flags. It panics if v is not of kind flags.
+func (v Value) Flags() []string {
+	v.mustBe("Flags", KindFlags)
+	return clone(v.labels)
+}
+
+// String returns the string value of v if v is of kind string.
+// For other kinds, it returns a representation of v in the
+// [WebAssembly Value Encoding] (WAVE) format.
+//
+// [WebAssembly Value Encoding]: https://github.com/bytecodealliance/wasm-tools/tree/main/crates/wasm-wave
+func (v Value) String() string {
+	if v.kind == KindString {
+		return v.str
+	}
+	var b strings.Builder
+	v.format(&b)
+	return b.String()
+}
+
+func (v Value) format(b *strings.Builder) {
+	switch v.kind {
+	case KindBool:
+		b.WriteString(strconv.FormatBool(v.bits != 0))
+	case KindS8, KindS16, KindS32, KindS64:
+		b.WriteString(strconv.FormatInt(int64(v.bits), 10))
+	case KindU8, KindU16, KindU32, KindU64, KindHandle:
+		b.WriteString(strconv.FormatUint(v.bits, 10))
+	case KindF32:
+		b.WriteString(strconv.FormatFloat(math.Float64frombits(v.bits), 'g', -1, 32))
+	case KindF64:
+		b.WriteString(strconv.FormatFloat(math.Float64frombits(v.bits), 'g', -1, 64))
+	case KindChar:
+		b.WriteString(strconv.QuoteRune(rune(v.bits)))
+	case KindString:
+		b.WriteString(strconv.Quote(v.str))
+	case KindList:
+		b.WriteByte('[')
+		v.formatElems(b)
+		b.WriteByte(']')
+	case KindTuple:
+		b.WriteByte('(')
+		v.formatElems(b)
+		b.WriteByte(')')
+	case KindRecord:
+		b.WriteByte('{')
+		for i, e := range v.elems {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(v.labels[i])
+			b.WriteString(": ")
+			e.format(b)
+		}
+		b.WriteByte('}')
+	case KindVariant, KindEnum, KindOption, KindResult:
+		b.WriteString(v.str)
+		if len(v.elems) > 0 {
+			b.WriteByte('(')
+			v.elems[0].format(b)
+			b.WriteByte(')')
+		}
+	case KindFlags:
+		b.WriteByte('{')
+		b.WriteString(strings.Join(v.labels, ", "))
+		b.WriteByte('}')
+	default:
+		b.WriteString("<invalid Value>")
+	}
+}
+
+func (v Value) formatElems(b *strings.Builder) {
+	for i, e := range v.elems {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		e.format(b)
+	}
+}
+
+// ValueError is returned when a [Value] is not of the expected [Kind].
+type ValueError struct {
+	Want Kind
+	Got  Kind
+}
+
+// Error implements the [error] interface.
+func (e *ValueError) Error() string {
+	return "cm: expected " + e.Want.String() + " Value, got " + e.Got.String()
+}
+
+// Primitive is a type constraint for the Go types that represent WIT primitive types.
+// Note that WIT char is represented as a [rune], an alias for int32.
+type Primitive interface {
+	bool | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64 | float32 | float64 | string
+}
+
+// FromValue returns the Go representation of primitive [Value] v, or an error if v is not of
+// the [Kind] that corresponds to T. A rune (int32) accepts a Value of kind s32 or char.
+func FromValue[T Primitive](v Value) (T, error) {
+	var t T
+	var err error
+	switch p := any(&t).(type) {
+	case *bool:
+		if err = v.Expect(KindBool); err == nil {
+			*p = v.bits != 0
+		}
+	case *int8:
+		if err = v.Expect(KindS8); err == nil {
+			*p = int8(v.bits)
+		}
+	case *uint8:
+		if err = v.Expect(KindU8); err == nil {
+			*p = uint8(v.bits)
+		}
+	case *int16:
+		if err = v.Expect(KindS16); err == nil {
+			*p = int16(v.bits)
+		}
+	case *uint16:
+		if err = v.Expect(KindU16); err == nil {
+			*p = uint16(v.bits)
+		}
+	case *int32:
+		if v.kind != KindChar {
+			err = v.Expect(KindS32)
+		}
+		if err == nil {
+			*p = int32(v.bits)
+		}
+	case *uint32:
+		if err = v.Expect(KindU32); err == nil {
+			*p = uint32(v.bits)
+		}
+	case *int64:
+		if err = v.Expect(KindS64); err == nil {
+			*p = int64(v.bits)
+		}
+	case *uint64:
+		if err = v.Expect(KindU64); err == nil {
+			*p = v.bits
+		}
+	case *float32:
+		if err = v.Expect(KindF32); err == nil {
+			*p = float32(math.Float64frombits(v.bits))
+		}
+	case *float64:
+		if err = v.Expect(KindF64); err == nil {
+			*p = math.Float64frombits(v.bits)
+		}
+	case *string:
+		if err = v.Expect(KindString); err == nil {
+			*p = v.str
+		}
+	}
+	return t, err
+}
+
+// HandleFromValue returns the resource handle of [Value] v as type T,
+// or an error if v is not of kind handle.
+func HandleFromValue[T ~uint32](v Value) (T, error) {
+	if err := v.Expect(KindHandle); err != nil {
+		return 0, err
+	}
+	return T(v.bits), nil
+}
@@ -0,0 +1,124 @@
+package cm
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValueString(t *testing.T) {
+	tests := []struct {
+		v    Value
+		want string
+	}{
+		{Value{}, "<invalid Value>"},
+		{BoolValue(true), "true"},
+		{S8Value(-8), "-8"},
+		{U64Value(1 << 63), "9223372036854775808"},
+		{F32Value(1.5), "1.5"},
+		{CharValue('x'), "'x'"},
+		{StringValue("hello"), "hello"},
+		{ListValue(StringValue("a"), StringValue("b")), `["a", "b"]`},
+		{TupleValue(U8Value(1), BoolValue(false)), "(1, false)"},
+		{RecordValue(Field{"x", S32Value(1)}, Field{"y", S32Value(-1)}), "{x: 1, y: -1}"},
+		{VariantValue("c", U32Value(7)), "c(7)"},
+		{VariantValue("d"), "d"},
+		{EnumValue("red"), "red"},
+		{SomeValue(StringValue("s")), `some("s")`},
+		{NoneValue(), "none"},
+		{OKValue(), "ok"},
+		{ErrValue(StringValue("oops")), `err("oops")`},
+		{FlagsValue("read", "write"), "{read, write}"},
+		{HandleValue(Resource(3)), "3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got, want := tt.v.String(), tt.want; got != want {
+				t.Errorf("String(): %s, expected %s", got, want)
+			}
+		})
+	}
+}
+
+func TestValueAccessors(t *testing.T) {
+	r := RecordValue(Field{"a", U8Value(1)}, Field{"b", ListValue(S16Value(-2))})
+	if got, want := r.Len(), 2; got != want {
+		t.Errorf("Len(): %d, expected %d", got, want)
+	}
+	if got, want := r.FieldName(1), "b"; got != want {
+		t.Errorf("FieldName(1): %s, expected %s", got, want)
+	}
+	b, ok := r.Field("b")
+	if !ok {
+		t.Fatalf("Field(%q): not found", "b")
+	}
+	if got, want := b.Index(0).Int(), int64(-2); got != want {
+		t.Errorf("Index(0).Int(): %d, expected %d", got, want)
+	}
+	if _, ok := r.Field("c"); ok {
+		t.Errorf("Field(%q): found, expected not found", "c")
+	}
+
+	o := SomeValue(F64Value(2.5))
+	p, ok := o.Payload()
+	if !ok || p.Float() != 2.5 {
+		t.Errorf("Payload(): %v, %t, expected 2.5, true", p, ok)
+	}
+	if _, ok := NoneValue().Payload(); ok {
+		t.Errorf("NoneValue().Payload(): true, expected false")
+	}
+	if got, want := ErrValue().Case(), "err"; got != want {
+		t.Errorf("Case(): %s, expected %s", got, want)
+	}
+	if got, want := HandleValue(Rep(9)).Handle(), uint32(9); got != want {
+		t.Errorf("Handle(): %d, expected %d", got, want)
+	}
+}
+
+func TestValuePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected panic")
+		}
+	}()
+	StringValue("x").Bool()
+}
+
+func TestFromValue(t *testing.T) {
+	u8, err := FromValue[uint8](U8Value(200))
+	if err != nil || u8 != 200 {
+		t.Errorf("FromValue[uint8]: %d, %v, expected 200, nil", u8, err)
+	}
+	s8, err := FromValue[int8](S8Value(-100))
+	if err != nil || s8 != -100 {
+		t.Errorf("FromValue[int8]: %d, %v, expected -100, nil", s8, err)
+	}
+	r, err := FromValue[rune](CharValue('€'))
+	if err != nil || r != '€' {
+		t.Errorf("FromValue[rune]: %q, %v, expected '€', nil", r, err)
+	}
+	f, err := FromValue[float32](F32Value(0.25))
+	if err != nil || f != 0.25 {
+		t.Errorf("FromValue[float32]: %v, %v, expected 0.25, nil", f, err)
+	}
+	s, err := FromValue[string](StringValue("wit"))
+	if err != nil || s != "wit" {
+		t.Errorf("FromValue[string]: %q, %v, expected \"wit\", nil", s, err)
+	}
+
+	_, err = FromValue[uint32](S32Value(1))
+	var verr *ValueError
+	if !errors.As(err, &verr) {
+		t.Fatalf("FromValue[uint32](S32Value(1)): %v, expected *ValueError", err)
+	}
+	if verr.Want != KindU32 || verr.Got != KindS32 {
+		t.Errorf("ValueError: %v", verr)
+	}
+
+	h, err := HandleFromValue[Resource](HandleValue(Resource(5)))
+	if err != nil || h != 5 {
+		t.Errorf("HandleFromValue: %d, %v, expected 5, nil", h, err)
+	}
+	if _, err := HandleFromValue[Resource](U32Value(5)); err == nil {
+		t.Errorf("HandleFromValue(U32Value(5)): nil error, expected error")
+	}
+}
@@ -1,6 +1,12 @@
 package cm
 
-import "unsafe"
+import (
+	"errors"
+	"strconv"
+	"unsafe"
+
+	"github.com/bytecodealliance/wasm-tools-go/internal/tinyunsafe"
+)
 
 // Discriminant is the set of types that can represent the tag or discriminator of a variant.
 // Use bool for 2-case variant types, result<T>, or option<T> types, uint8 where there are 256 or
@@ -55,6 +61,44 @@ func Case[T any, V AnyVariant[Tag, Shape, Align], Tag Discriminant, Shape, Align
 	return nil
 }
 
+// Validate verifies the layout of the [Variant] at v against its Shape and Align types and
+// payload type T at runtime. It returns an error if v is not aligned to the variant,
+// the payload overlaps the tag or is not aligned to Align or T, or T does not fit within Shape.
+// Validate is a debugging aid for tests, to catch miscompiled shapes on unusual architectures early.
+func Validate[T any, V AnyVariant[Tag, Shape, Align], Tag Discriminant, Shape, Align any](v *V) error {
+	if v == nil {
+		return errors.New("variant: nil pointer")
+	}
+	v2 := (*variant[Tag, Shape, Align])(unsafe.Pointer(v))
+	var t T
+	var align Align
+	if addr := uintptr(unsafe.Pointer(v2)); addr%unsafe.Alignof(*v2) != 0 {
+		return errors.New("variant: address " + strconv.FormatUint(uint64(addr), 16) +
+			" not aligned to " + strconv.Itoa(int(unsafe.Alignof(*v2))))
+	}
+	offset := tinyunsafe.OffsetOf(v2, &v2.data)
+	if offset < unsafe.Sizeof(v2.tag) {
+		return errors.New("variant: payload offset " + strconv.Itoa(int(offset)) +
+			" overlaps tag of size " + strconv.Itoa(int(unsafe.Sizeof(v2.tag))))
+	}
+	if offset%unsafe.Alignof(align) != 0 {
+		return errors.New("variant: payload offset " + strconv.Itoa(int(offset)) +
+			" not aligned to Align " + strconv.Itoa(int(unsafe.Alignof(align))))
+	}
+	if offset%unsafe.Alignof(t) != 0 {
+		return errors.New("variant: payload offset " + strconv.Itoa(int(offset)) +
+			" not aligned to type " + strconv.Itoa(int(unsafe.Alignof(t))))
+	}
+	if unsafe.Sizeof(t) > unsafe.Sizeof(v2.data) {
+		return errors.New("variant: size of type " + strconv.Itoa(int(unsafe.Sizeof(t))) +
+			" > size of Shape " + strconv.Itoa(int(unsafe.Sizeof(v2.data))))
+	}
+	if offset+unsafe.Sizeof(v2.data) > unsafe.Sizeof(*v2) {
+		return errors.New("variant: payload exceeds variant size " + strconv.Itoa(int(unsafe.Sizeof(*v2))))
+	}
+	return nil
+}
+
 // variant is the internal representation of a Component Model variant.
 // Shape and Align must be non-zero sized types.
 type variant[Tag Discriminant, Shape, Align any] struct {
@@ -68,3 +68,27 @@ func TestNewVariantValidates(t *testing.T) {
 	}()
 	_ = NewVariant[uint8, uint8, uint8](0, "hello world")
 }
+
+func TestValidate(t *testing.T) {
+	ok := NewVariant[uint8, uint64, uint64](1, uint64(42))
+	if err := Validate[uint64](&ok); err != nil {
+		t.Errorf("Validate[uint64](%s): %v, expected nil", typeName(&ok), err)
+	}
+	if err := Validate[uint32](&ok); err != nil {
+		t.Errorf("Validate[uint32](%s): %v, expected nil", typeName(&ok), err)
+	}
+
+	var small Variant[uint8, uint32, uint32]
+	if err := Validate[[8]byte](&small); err == nil {
+		t.Errorf("Validate[[8]byte](%s): expected error", typeName(&small))
+	}
+
+	var misaligned Variant[uint8, [8]byte, uint8]
+	if err := Validate[uint64](&misaligned); err == nil {
+		t.Errorf("Validate[uint64](%s): expected error", typeName(&misaligned))
+	}
+
+	if err := Validate[uint64, Variant[uint8, uint64, uint64]](nil); err == nil {
+		t.Error("Validate(nil): expected error")
+	}
+}
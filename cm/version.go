@@ -0,0 +1,26 @@
+package cm
+
+// APILevel is the API level of this package, incremented when types or functions
+// used by generated bindings are added or changed.
+//
+// Code generated by wit-bindgen-go declares the minimum API level it requires:
+//
+//	const _ uint = cm.APILevel - 2
+//
+// The declaration fails to compile with an older version of this package, such as an
+// outdated fork or vendored copy, instead of failing on a missing type or function.
+// Forks of this package should keep APILevel, and only increase it after adding
+// the types and functions of that level.
+//
+// API levels:
+//
+//   - 1: initial API level
+//   - 2: [LiftEnum]
+//   - 3: [CallHook], [Call], [SetCallHook], and [LoadCallHook]
+//   - 4: [TraceImport] and [TraceExport]
+//   - 5: [ResourceTable]
+//   - 6: [LowerStringStrict] and [LowerStringLossy]
+//   - 7: [LowerChar] and [LiftChar]
+//   - 8: [Clone] and [CloneFunc]
+//   - 9: [Null], [ToNull], and [FromNull]
+const APILevel = 9
@@ -0,0 +1,149 @@
+package describe
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bytecodealliance/wasm-tools-go/internal/witcli"
+	"github.com/bytecodealliance/wasm-tools-go/wit"
+	"github.com/bytecodealliance/wasm-tools-go/wit/ordered"
+	"github.com/urfave/cli/v3"
+)
+
+// Command is the CLI command for describe.
+var Command = &cli.Command{
+	Name:  "describe",
+	Usage: "summarizes the imports, exports, functions, resources, and type sizes of a WIT world",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "world",
+			Aliases:  []string{"w"},
+			Value:    "",
+			OnlyOnce: true,
+			Config:   cli.StringConfig{TrimSpace: true},
+			Usage:    "WIT world to describe, otherwise describe all worlds",
+		},
+	},
+	Action: action,
+}
+
+func action(ctx context.Context, cmd *cli.Command) error {
+	path, err := witcli.LoadPath(cmd.Args().Slice()...)
+	if err != nil {
+		return err
+	}
+	res, err := witcli.Load(ctx, path, witcli.Options{
+		ForceWIT:      cmd.Bool("force-wit"),
+		Lockfile:      cmd.String("lockfile"),
+		RequireDigest: cmd.Bool("require-digest"),
+	})
+	if err != nil {
+		return err
+	}
+	worlds := res.Worlds
+	world := cmd.String("world")
+	if world != "" {
+		worlds = nil
+		for _, w := range res.Worlds {
+			if w.Match(world) {
+				worlds = append(worlds, w)
+				break
+			}
+		}
+		if worlds == nil {
+			return fmt.Errorf("world %s not found", world)
+		}
+	}
+	for i, w := range worlds {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Print(describeWorld(w))
+	}
+	return nil
+}
+
+// describeWorld returns a human-readable summary of [wit.World] w.
+func describeWorld(w *wit.World) string {
+	var b strings.Builder
+	id := w.Package.Name
+	id.Extension = w.Name
+	fmt.Fprintf(&b, "world %s\n", id.String())
+
+	var faces, funcs int
+	faceSeen := make(map[*wit.Interface]bool)
+	w.AllInterfaces()(func(_ string, i *wit.Interface) bool {
+		if !faceSeen[i] {
+			faceSeen[i] = true
+			faces++
+		}
+		return true
+	})
+	w.AllFunctionsDeep()(func(*wit.Function) bool {
+		funcs++
+		return true
+	})
+	fmt.Fprintf(&b, "  %d interface(s), %d function(s)\n", faces, funcs)
+
+	describeItems(&b, "imports", &w.Imports)
+	describeItems(&b, "exports", &w.Exports)
+	return b.String()
+}
+
+func describeItems(b *strings.Builder, motion string, items *ordered.Map[string, wit.WorldItem]) {
+	if items.Len() == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\n%s:\n", motion)
+	items.All()(func(name string, item wit.WorldItem) bool {
+		switch item := item.(type) {
+		case *wit.InterfaceRef:
+			describeInterface(b, name, item.Interface)
+		case *wit.TypeDef:
+			describeTypeDef(b, "  ", item)
+		case *wit.Function:
+			describeFunction(b, "  ", item)
+		}
+		return true
+	})
+}
+
+func describeInterface(b *strings.Builder, name string, i *wit.Interface) {
+	if i.Name != nil && i.Package != nil {
+		id := i.Package.Name
+		id.Extension = *i.Name
+		name = id.String()
+	}
+	fmt.Fprintf(b, "  interface %s\n", name)
+	i.TypeDefs.All()(func(_ string, t *wit.TypeDef) bool {
+		describeTypeDef(b, "    ", t)
+		return true
+	})
+	i.Functions.All()(func(_ string, f *wit.Function) bool {
+		if f.IsFreestanding() {
+			describeFunction(b, "    ", f)
+		}
+		return true
+	})
+}
+
+func describeTypeDef(b *strings.Builder, indent string, t *wit.TypeDef) {
+	fmt.Fprintf(b, "%s%s %s (size %d, align %d)\n", indent, t.WITKind(), t.TypeName(), t.Size(), t.Align())
+	if _, ok := t.Kind.(*wit.Resource); !ok {
+		return
+	}
+	if f := t.Constructor(); f != nil {
+		describeFunction(b, indent+"  ", f)
+	}
+	for _, f := range t.StaticFunctions() {
+		describeFunction(b, indent+"  ", f)
+	}
+	for _, f := range t.Methods() {
+		describeFunction(b, indent+"  ", f)
+	}
+}
+
+func describeFunction(b *strings.Builder, indent string, f *wit.Function) {
+	fmt.Fprintf(b, "%s%s\n", indent, strings.TrimSuffix(f.WIT(nil, ""), ";"))
+}
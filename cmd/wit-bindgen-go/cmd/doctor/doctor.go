@@ -0,0 +1,360 @@
+package doctor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"go/version"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime/debug"
+	"slices"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/semver"
+)
+
+// Command is the CLI command for doctor.
+var Command = &cli.Command{
+	Name:  "doctor",
+	Usage: "diagnoses problems with the tools and Go module used to build WebAssembly components",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:      "dir",
+			Aliases:   []string{"C"},
+			Value:     ".",
+			OnlyOnce:  true,
+			TakesFile: true,
+			Config:    cli.StringConfig{TrimSpace: true},
+			Usage:     "directory of the Go module to check",
+		},
+	},
+	Action: action,
+}
+
+const (
+	// minGoVersion is the first Go version that supports //go:wasmexport.
+	minGoVersion = "go1.24"
+
+	// minTinyGoVersion is the first TinyGo version that supports the wasip2 target.
+	minTinyGoVersion = "v0.33.0"
+
+	// minWasmToolsVersion is the first wasm-tools version that serializes WIT as JSON
+	// in the format read by package wit.
+	minWasmToolsVersion = "v1.210.0"
+
+	// modulePath is the path of the module that contains package cm.
+	modulePath = "github.com/bytecodealliance/wasm-tools-go"
+)
+
+// status is the outcome of a check.
+type status int
+
+const (
+	ok status = iota
+	warning
+	failure
+)
+
+func (s status) String() string {
+	switch s {
+	case ok:
+		return "ok"
+	case warning:
+		return "warn"
+	default:
+		return "FAIL"
+	}
+}
+
+// result is the result of a single check, with steps to remediate a warning or failure.
+type result struct {
+	name   string
+	status status
+	detail string
+	remedy string
+}
+
+func action(ctx context.Context, cmd *cli.Command) error {
+	dir := cmd.String("dir")
+	results := []result{
+		checkGo(ctx),
+		checkTarget(ctx),
+		checkTinyGo(ctx),
+		checkWasmTools(ctx),
+		checkModule(ctx, dir),
+	}
+	var failures int
+	for _, r := range results {
+		r.write(os.Stdout)
+		if r.status == failure {
+			failures++
+		}
+	}
+	if failures > 0 {
+		return fmt.Errorf("found %d problem(s)", failures)
+	}
+	return nil
+}
+
+func (r *result) write(w io.Writer) {
+	fmt.Fprintf(w, "%-6s %s: %s\n", "["+r.status.String()+"]", r.name, r.detail)
+	if r.remedy != "" && r.status != ok {
+		for _, line := range strings.Split(r.remedy, "\n") {
+			fmt.Fprintf(w, "       %s\n", line)
+		}
+	}
+}
+
+// checkGo checks that the go command is installed and supports //go:wasmexport.
+func checkGo(ctx context.Context) result {
+	r := result{name: "Go"}
+	goVersion, err := goEnv(ctx, "GOVERSION")
+	if err != nil {
+		r.status = failure
+		r.detail = err.Error()
+		r.remedy = "Install Go from https://go.dev/dl/ and add it to $PATH."
+		return r
+	}
+	r.detail = goVersion
+	if !version.IsValid(goVersion) {
+		r.status = warning
+		r.detail += " (unknown version)"
+		return r
+	}
+	if version.Compare(goVersion, minGoVersion) < 0 {
+		r.status = warning
+		r.detail += " does not support //go:wasmexport"
+		r.remedy = "Upgrade to " + minGoVersion + " or later to export functions from components built with Go,\n" +
+			"or build with TinyGo."
+		return r
+	}
+	r.detail += " supports //go:wasmexport"
+	return r
+}
+
+// checkTarget checks that the go command can build for GOOS=wasip1 GOARCH=wasm,
+// and reports a GOOS or GOARCH environment that would build for another target.
+func checkTarget(ctx context.Context) result {
+	r := result{name: "Target"}
+	out, err := exec.CommandContext(ctx, "go", "tool", "dist", "list").Output()
+	if err != nil {
+		r.status = failure
+		r.detail = "cannot list Go targets: " + err.Error()
+		r.remedy = "Check that Go is installed correctly."
+		return r
+	}
+	if !slices.Contains(strings.Fields(string(out)), "wasip1/wasm") {
+		r.status = failure
+		r.detail = "Go does not support GOOS=wasip1 GOARCH=wasm"
+		r.remedy = "Upgrade to Go 1.21 or later."
+		return r
+	}
+	goos, goarch := os.Getenv("GOOS"), os.Getenv("GOARCH")
+	switch {
+	case goos == "" && goarch == "":
+		r.detail = "GOOS=wasip1 GOARCH=wasm is supported"
+	case (goos == "wasip1" || goos == "wasip2") && goarch == "wasm":
+		r.detail = fmt.Sprintf("GOOS=%s GOARCH=%s", goos, goarch)
+		if goos == "wasip2" {
+			r.detail += " (TinyGo only)"
+		}
+	default:
+		r.status = warning
+		r.detail = fmt.Sprintf("GOOS=%s GOARCH=%s is set in the environment", goos, goarch)
+		r.remedy = "Generated bindings call imported functions only on WebAssembly.\n" +
+			"Build components with GOOS=wasip1 GOARCH=wasm, or use tinygo build -target=wasip2."
+	}
+	return r
+}
+
+var tinygoVersion = regexp.MustCompile(`tinygo version (\S+)`)
+
+// checkTinyGo checks that tinygo is installed and supports the wasip2 target.
+func checkTinyGo(ctx context.Context) result {
+	r := result{name: "TinyGo"}
+	out, err := run(ctx, "tinygo", "version")
+	if err != nil {
+		r.status = warning
+		r.detail = err.Error()
+		r.remedy = "Install TinyGo " + strings.TrimPrefix(minTinyGoVersion, "v") + " or later from https://tinygo.org/getting-started/install/\n" +
+			"to build WASI Preview 2 components with tinygo build -target=wasip2."
+		return r
+	}
+	m := tinygoVersion.FindStringSubmatch(out)
+	if m == nil {
+		r.status = warning
+		r.detail = "unknown version: " + firstLine(out)
+		return r
+	}
+	return checkVersion(r, m[1], minTinyGoVersion, "does not support -target=wasip2")
+}
+
+var wasmToolsVersion = regexp.MustCompile(`wasm-tools (\S+)`)
+
+// checkWasmTools checks that wasm-tools is installed and recent enough to load WIT.
+func checkWasmTools(ctx context.Context) result {
+	r := result{name: "wasm-tools"}
+	out, err := run(ctx, "wasm-tools", "--version")
+	if err != nil {
+		r.status = warning
+		r.detail = err.Error()
+		r.remedy = "Install wasm-tools with cargo install wasm-tools, or from\n" +
+			"https://github.com/bytecodealliance/wasm-tools/releases, to load WIT files\n" +
+			"and create components with wasm-tools component new."
+		return r
+	}
+	m := wasmToolsVersion.FindStringSubmatch(out)
+	if m == nil {
+		r.status = warning
+		r.detail = "unknown version: " + firstLine(out)
+		return r
+	}
+	return checkVersion(r, m[1], minWasmToolsVersion, "cannot serialize WIT as JSON")
+}
+
+// checkVersion sets the detail and status of r from tool version v,
+// and returns a warning if v is older than minVersion.
+func checkVersion(r result, v, minVersion, problem string) result {
+	r.detail = v
+	sv := "v" + strings.TrimPrefix(v, "v")
+	if !semver.IsValid(sv) {
+		r.status = warning
+		r.detail += " (unknown version)"
+		return r
+	}
+	if semver.Compare(sv, minVersion) < 0 {
+		r.status = warning
+		r.detail += " " + problem
+		r.remedy = "Upgrade " + r.name + " to " + strings.TrimPrefix(minVersion, "v") + " or later."
+	}
+	return r
+}
+
+// checkModule checks that the version of package cm required by the Go module in dir
+// matches the version of wit-bindgen-go, so generated bindings match the cm API.
+func checkModule(ctx context.Context, dir string) result {
+	r := result{name: "Module"}
+	gomod, err := goEnvDir(ctx, dir, "GOMOD")
+	if err != nil || gomod == "" || gomod == os.DevNull {
+		r.status = warning
+		r.detail = "no go.mod file in " + dir
+		r.remedy = "Run go mod init in the directory that will contain generated bindings."
+		return r
+	}
+	b, err := os.ReadFile(gomod)
+	if err != nil {
+		r.status = failure
+		r.detail = err.Error()
+		return r
+	}
+	f, err := modfile.ParseLax(gomod, b, nil)
+	if err != nil {
+		r.status = failure
+		r.detail = err.Error()
+		r.remedy = "Fix the syntax of " + gomod + "."
+		return r
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		abs = dir
+	}
+	rel, err := filepath.Rel(abs, gomod)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		rel = gomod
+	}
+	if f.Module != nil && f.Module.Mod.Path == modulePath {
+		r.detail = rel + " is module " + modulePath
+		return r
+	}
+	var required string
+	for _, req := range f.Require {
+		if req.Mod.Path == modulePath {
+			required = req.Mod.Version
+		}
+	}
+	for _, rep := range f.Replace {
+		if rep.Old.Path == modulePath {
+			r.detail = fmt.Sprintf("%s replaces %s with %s", rel, modulePath, rep.New.String())
+			return r
+		}
+	}
+	if required == "" {
+		r.status = warning
+		r.detail = rel + " does not require " + modulePath
+		r.remedy = "Run go get " + modulePath + "/cm" + atVersion(toolVersion()) + " to use generated bindings."
+		return r
+	}
+	r.detail = rel + " requires " + modulePath + " " + required
+	tool := toolVersion()
+	if tool == "" || tool == required {
+		return r
+	}
+	r.status = warning
+	r.detail += ", but wit-bindgen-go is " + tool
+	r.remedy = "Bindings generated by wit-bindgen-go " + tool + " may not match the cm package API.\n" +
+		"Run go get " + modulePath + "@" + tool + ", or run wit-bindgen-go with\n" +
+		"go run " + modulePath + "/cmd/wit-bindgen-go@" + required + "."
+	return r
+}
+
+// toolVersion returns the module version of wit-bindgen-go,
+// or an empty string if it was not built from a released module.
+func toolVersion() string {
+	build, ok := debug.ReadBuildInfo()
+	if !ok || build.Main.Path != modulePath || !semver.IsValid(build.Main.Version) {
+		return ""
+	}
+	return build.Main.Version
+}
+
+func atVersion(v string) string {
+	if v == "" {
+		return "@latest"
+	}
+	return "@" + v
+}
+
+// goEnv returns the value of Go environment variable key.
+func goEnv(ctx context.Context, key string) (string, error) {
+	return goEnvDir(ctx, "", key)
+}
+
+func goEnvDir(ctx context.Context, dir, key string) (string, error) {
+	if _, err := exec.LookPath("go"); err != nil {
+		return "", errors.New("go not found in $PATH")
+	}
+	cmd := exec.CommandContext(ctx, "go", "env", key)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("go env: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// run runs the named program with args and returns its combined output.
+func run(ctx context.Context, name string, args ...string) (string, error) {
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return "", errors.New(name + " not found in $PATH")
+	}
+	out, err := exec.CommandContext(ctx, path, args...).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", name, err)
+	}
+	return string(out), nil
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	return line
+}
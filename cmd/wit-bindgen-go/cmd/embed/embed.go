@@ -0,0 +1,90 @@
+package embed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/bytecodealliance/wasm-tools-go/internal/witcli"
+	"github.com/bytecodealliance/wasm-tools-go/wit"
+	"github.com/bytecodealliance/wasm-tools-go/wit/metadata"
+	"github.com/urfave/cli/v3"
+)
+
+// Command is the CLI command for embed.
+var Command = &cli.Command{
+	Name:      "embed",
+	Usage:     "embeds the component-type metadata of a WIT world in a core WebAssembly module",
+	ArgsUsage: "<wit-path> <module.wasm>",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "world",
+			Aliases:  []string{"w"},
+			Value:    "",
+			OnlyOnce: true,
+			Config:   cli.StringConfig{TrimSpace: true},
+			Usage:    "WIT world to embed, required if the WIT defines more than one world",
+		},
+		&cli.StringFlag{
+			Name:      "out",
+			Aliases:   []string{"o"},
+			Value:     "",
+			OnlyOnce:  true,
+			TakesFile: true,
+			Config:    cli.StringConfig{TrimSpace: true},
+			Usage:     "output file, otherwise write to stdout",
+		},
+	},
+	Action: action,
+}
+
+func action(ctx context.Context, cmd *cli.Command) error {
+	args := cmd.Args().Slice()
+	if len(args) != 2 {
+		return fmt.Errorf("found %d arguments, expecting a WIT path and a WebAssembly module", len(args))
+	}
+	res, err := witcli.Load(ctx, args[0], witcli.Options{
+		ForceWIT:      cmd.Bool("force-wit"),
+		Lockfile:      cmd.String("lockfile"),
+		RequireDigest: cmd.Bool("require-digest"),
+	})
+	if err != nil {
+		return err
+	}
+	w, err := findWorld(res, cmd.String("world"))
+	if err != nil {
+		return err
+	}
+
+	module, err := os.ReadFile(args[1])
+	if err != nil {
+		return err
+	}
+	b, err := metadata.Embed(module, w)
+	if err != nil {
+		return fmt.Errorf("%s: %w", args[1], err)
+	}
+
+	out := cmd.String("out")
+	if out == "" || out == "-" {
+		_, err = os.Stdout.Write(b)
+		return err
+	}
+	return os.WriteFile(out, b, 0o644)
+}
+
+// findWorld returns the world in res matching pattern,
+// or the only world in res if pattern is empty.
+func findWorld(res *wit.Resolve, pattern string) (*wit.World, error) {
+	if pattern == "" {
+		if len(res.Worlds) != 1 {
+			return nil, errors.New("WIT defines more than one world, select one with --world")
+		}
+		return res.Worlds[0], nil
+	}
+	if w := res.FindWorld(pattern); w != nil {
+		return w, nil
+	}
+	return nil, fmt.Errorf("world %s not found", pattern)
+}
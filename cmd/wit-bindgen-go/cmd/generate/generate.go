@@ -2,14 +2,21 @@ package generate
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"runtime/debug"
+	"slices"
 	"strings"
 
 	"github.com/bytecodealliance/wasm-tools-go/internal/codec"
+	"github.com/bytecodealliance/wasm-tools-go/internal/gencache"
 	"github.com/bytecodealliance/wasm-tools-go/internal/go/gen"
 	"github.com/bytecodealliance/wasm-tools-go/internal/witcli"
+	"github.com/bytecodealliance/wasm-tools-go/wit"
 	"github.com/bytecodealliance/wasm-tools-go/wit/bindgen"
 	"github.com/urfave/cli/v3"
 )
@@ -45,6 +52,13 @@ var Command = &cli.Command{
 			Config:   cli.StringConfig{TrimSpace: true},
 			Usage:    "Go package root, e.g. github.com/org/repo/internal",
 		},
+		&cli.StringFlag{
+			Name:     "module",
+			Value:    "",
+			OnlyOnce: true,
+			Config:   cli.StringConfig{TrimSpace: true},
+			Usage:    "write go.mod and doc.go for Go module path to the output directory, e.g. github.com/org/bindings",
+		},
 		&cli.StringFlag{
 			Name:     "cm",
 			Value:    "",
@@ -52,10 +66,227 @@ var Command = &cli.Command{
 			Config:   cli.StringConfig{TrimSpace: true},
 			Usage:    "Import path for the Component Model utility package, e.g. github.com/bytecodealliance/wasm-tools-go/cm",
 		},
+		&cli.BoolFlag{
+			Name:  "cm-api-check",
+			Value: true,
+			Usage: "declare the minimum API level of the Component Model utility package required by generated code",
+		},
+		&cli.BoolFlag{
+			Name:  "enum-check",
+			Value: true,
+			Usage: "panic (trap) when lifting an out-of-range enum discriminant",
+		},
+		&cli.BoolFlag{
+			Name:  "char-check",
+			Usage: "panic (trap) when lowering or lifting a char that is not a Unicode scalar value",
+		},
+		&cli.StringFlag{
+			Name:     "string-check",
+			Value:    bindgen.StringCheckNone,
+			OnlyOnce: true,
+			Config:   cli.StringConfig{TrimSpace: true},
+			Usage:    "check that lowered strings are valid UTF-8: panic (strict), replace invalid bytes (lossy), or none",
+		},
 		&cli.BoolFlag{
 			Name:  "versioned",
 			Usage: "emit versioned Go package(s) for each WIT version",
 		},
+		&cli.StringSliceFlag{
+			Name:  "select",
+			Usage: "select a version of a WIT package by semver constraint, removing other versions, e.g. wasi:io@^0.2 (repeatable)",
+		},
+		&cli.StringFlag{
+			Name:     "naming",
+			Value:    bindgen.DefaultNaming.String(),
+			OnlyOnce: true,
+			Config:   cli.StringConfig{TrimSpace: true},
+			Usage:    "naming scheme that maps WIT names to Go names (v1 or v2), pinned to keep Go identifiers stable",
+		},
+		&cli.StringMapFlag{
+			Name:  "rename",
+			Usage: "Go name of a WIT type or function, e.g. wasi:io/streams#input-stream=Reader (repeatable)",
+		},
+		&cli.BoolFlag{
+			Name:  "json",
+			Usage: "generate JSON marshaling methods for records, variants, and enums",
+		},
+		&cli.BoolFlag{
+			Name:  "ir",
+			Usage: "emit a JSON intermediate representation (IR) of generated names for each world",
+		},
+		&cli.BoolFlag{
+			Name:  "free-functions",
+			Usage: "generate functions on resource types as free functions instead of methods",
+		},
+		&cli.BoolFlag{
+			Name:  "managed-resources",
+			Usage: "generate managed wrapper types that implement io.Closer for imported resources",
+		},
+		&cli.BoolFlag{
+			Name:  "finalizers",
+			Usage: "register finalizers that drop leaked resource handles (implies --managed-resources)",
+		},
+		&cli.BoolFlag{
+			Name:  "resource-tables",
+			Usage: "generate a resource table for each exported resource that dispatches methods to Go values",
+		},
+		&cli.BoolFlag{
+			Name:  "scaffold",
+			Usage: "generate a main package for each world that exports wasi:cli/run, unless it exists",
+		},
+		&cli.BoolFlag{
+			Name:  "dynamic-values",
+			Usage: "generate ToValue and FromValue methods that convert to and from dynamic cm.Value values",
+		},
+		&cli.BoolFlag{
+			Name:  "binary",
+			Usage: "generate MarshalBinary and UnmarshalBinary methods that encode values in their Canonical ABI memory layout",
+		},
+		&cli.BoolFlag{
+			Name:  "equal",
+			Usage: "generate Equal methods that compare values, including the contents of lists and variants",
+		},
+		&cli.BoolFlag{
+			Name:  "deep-copy",
+			Usage: "generate DeepCopy methods that copy strings and lists into memory allocated on the Go heap",
+		},
+		&cli.BoolFlag{
+			Name:  "invoker",
+			Usage: "generate a registry to invoke imported functions by name with cm.Value arguments (implies --dynamic-values)",
+		},
+		&cli.BoolFlag{
+			Name:  "mock-imports",
+			Usage: "generate mockable imported functions for unit testing on the host",
+		},
+		&cli.BoolFlag{
+			Name:  "call-hooks",
+			Usage: "call the cm.CallHook registered with cm.SetCallHook before and after each imported function, for tracing",
+		},
+		&cli.BoolFlag{
+			Name:  "layout-tests",
+			Usage: "generate tests that verify the size and alignment of generated Go types",
+		},
+		&cli.BoolFlag{
+			Name:  "type-info",
+			Usage: "generate a table describing the WIT type of each Go type for introspection by middleware",
+		},
+		&cli.BoolFlag{
+			Name:  "prune",
+			Usage: "omit WIT types and functions not reachable from the imports and exports of the selected world",
+		},
+		&cli.BoolFlag{
+			Name:  "exhaustive",
+			Usage: "generate case lists and case types for exhaustive switch statements over enums and variants",
+		},
+		&cli.BoolFlag{
+			Name:  "constructors",
+			Usage: "generate a NewFoo constructor function and FieldsZero method for each record type",
+		},
+		&cli.BoolFlag{
+			Name:  "null-accessors",
+			Usage: "generate FooNull and SetFooNull methods that get and set option-typed record fields as cm.Null values",
+		},
+		&cli.StringFlag{
+			Name:      "struct-tags",
+			Value:     "",
+			OnlyOnce:  true,
+			TakesFile: true,
+			Config:    cli.StringConfig{TrimSpace: true},
+			Usage:     "path to a JSON file configuring struct tags (e.g. json, yaml) on generated record fields",
+		},
+		&cli.StringFlag{
+			Name:      "type-overrides",
+			Value:     "",
+			OnlyOnce:  true,
+			TakesFile: true,
+			Config:    cli.StringConfig{TrimSpace: true},
+			Usage:     "path to a JSON file mapping WIT record types to existing Go types, e.g. {\"wasi:clocks/wall-clock#datetime\": {\"type\": \"time.Time\", ...}}",
+		},
+		&cli.BoolFlag{
+			Name:  "debug",
+			Usage: "generate functions that panic on use of a dropped resource handle or a second drop, and trace calls when built with the cm_trace build tag",
+		},
+		&cli.BoolFlag{
+			Name:  "arena-params",
+			Usage: "allocate the parameter struct that imported functions pass by pointer from cm.DefaultArena instead of the Go heap",
+		},
+		&cli.BoolFlag{
+			Name:  "stack-usage",
+			Usage: "instrument exported functions to record their high-water stack usage, reported by cm.StackReport",
+		},
+		&cli.StringMapFlag{
+			Name:  "docs-url",
+			Usage: "URL template for documentation links of WIT interfaces in a namespace, e.g. wasi=https://example.com/{package}/{interface}#{item}",
+		},
+		&cli.BoolFlag{
+			Name:  "strip-docs",
+			Usage: "remove WIT documentation from generated code",
+		},
+		&cli.StringFlag{
+			Name:     "build-tags",
+			Value:    "",
+			OnlyOnce: true,
+			Config:   cli.StringConfig{TrimSpace: true},
+			Usage:    "emit a //go:build constraint on generated files, e.g. wasip2",
+		},
+		&cli.BoolFlag{
+			Name:  "stubs",
+			Usage: "generate stub functions that panic on targets that do not satisfy --build-tags",
+		},
+		&cli.StringFlag{
+			Name:     "empty-asm",
+			Value:    bindgen.EmptyAsmAuto,
+			OnlyOnce: true,
+			Config:   cli.StringConfig{TrimSpace: true},
+			Usage:    "emit empty.s in Go packages with wasmimport functions (auto), in all packages (always), or none (never)",
+		},
+		&cli.StringFlag{
+			Name:     "target",
+			Value:    bindgen.TargetAny,
+			OnlyOnce: true,
+			Config:   cli.StringConfig{TrimSpace: true},
+			Usage:    "Go toolchain that builds generated code: go, tinygo, or any (either)",
+		},
+		&cli.BoolFlag{
+			Name:  "build-json",
+			Usage: "write build.json describing the module, packages, Go version, build tags, and targets of generated code",
+		},
+		&cli.StringFlag{
+			Name:     "artifact-cache",
+			Value:    "",
+			OnlyOnce: true,
+			Config:   cli.StringConfig{TrimSpace: true},
+			Usage:    "URL of an HTTP cache of generated bindings; skip generation if it has bindings for the same WIT and options",
+		},
+		&cli.BoolFlag{
+			Name:  "artifact-cache-push",
+			Usage: "store generated bindings in the --artifact-cache",
+		},
+		&cli.BoolFlag{
+			Name:  "verify",
+			Usage: "type-check generated Go before writing it, reporting errors with the WIT item that generated them",
+		},
+		&cli.BoolFlag{
+			Name:  "incremental",
+			Value: true,
+			Usage: "skip writing files whose content is unchanged, recording content hashes in " + gen.CacheFile + " in the output directory",
+		},
+		&cli.StringFlag{
+			Name:      "manifest",
+			Value:     "",
+			TakesFile: true,
+			OnlyOnce:  true,
+			Config:    cli.StringConfig{TrimSpace: true},
+			Usage:     "write a JSON manifest of generated files, with their Go packages, WIT origins, and hashes, to `file`",
+		},
+		&cli.BoolFlag{
+			Name:  "bad-files",
+			Usage: "write Go files that cannot be formatted with a .bad extension, for debugging",
+		},
+		&cli.BoolFlag{
+			Name:  "clean",
+			Usage: "remove stale Go files previously generated by wit-bindgen-go from the output directory",
+		},
 		&cli.BoolFlag{
 			Name:  "dry-run",
 			Usage: "do not write files; print to stdout",
@@ -67,13 +298,62 @@ var Command = &cli.Command{
 // Config is the configuration for the `generate` command.
 type config struct {
 	dryRun    bool
+	clean     bool
 	out       string
 	outPerm   os.FileMode
 	pkgRoot   string
+	module    string
 	world     string
 	cm        string
+	cmCheck   bool
+	enumCheck bool
+	charCheck bool
+	strCheck  string
 	versioned bool
+	selects   []string
+	naming    bindgen.Naming
+	renames   map[string]string
+	json      bool
+	ir        bool
+	freeFuncs bool
+	managed   bool
+	finalize  bool
+	tables    bool
+	scaffold  bool
+	values    bool
+	binary    bool
+	equal     bool
+	deepCopy  bool
+	invoker   bool
+	mocks     bool
+	hooks     bool
+	layout    bool
+	typeInfo  bool
+	prune     bool
+	exhaust   bool
+	ctors     bool
+	nulls     bool
+	tags      *bindgen.StructTagConfig
+	overrides map[string]bindgen.TypeOverride
+	debug     bool
+	arena     bool
+	stack     bool
+	docsURLs  map[string]string
+	stripDocs bool
+	buildTags string
+	stubs     bool
+	emptyAsm  string
+	target    string
+	buildJSON bool
+	cacheURL  string
+	cachePush bool
+	verify    bool
+	incr      bool
+	manifest  string
+	badFiles  bool
 	forceWIT  bool
+	lockfile  string
+	reqDigest bool
 	path      string
 }
 
@@ -83,23 +363,334 @@ func action(ctx context.Context, cmd *cli.Command) error {
 		return err
 	}
 
-	res, err := witcli.LoadWIT(ctx, cfg.forceWIT, cfg.path)
+	res, err := witcli.Load(ctx, cfg.path, witcli.Options{
+		ForceWIT:      cfg.forceWIT,
+		Lockfile:      cfg.lockfile,
+		RequireDigest: cfg.reqDigest,
+	})
 	if err != nil {
 		return err
 	}
+	err = res.ResolveIncludes()
+	if err != nil {
+		return err
+	}
+	if cfg.stripDocs {
+		res.StripDocs()
+	}
+	if len(cfg.selects) > 0 {
+		err = res.SelectVersions(cfg.selects...)
+		if err != nil {
+			return err
+		}
+	}
 
-	packages, err := bindgen.Go(res,
+	var cache *gencache.Cache
+	var key string
+	if cfg.cacheURL != "" {
+		key, err = cacheKey(cmd, cfg, res.HashWith(wit.HashOptions{Docs: true, Order: true}))
+		if err != nil {
+			return err
+		}
+	}
+	if key != "" {
+		cache = &gencache.Cache{URL: cfg.cacheURL}
+		files, err := cache.Get(ctx, key)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error fetching from artifact cache: %v\n", err)
+		} else if files != nil {
+			fmt.Fprintf(os.Stderr, "Fetched %d file(s) from artifact cache: %s\n", len(files), key)
+			return writeCachedFiles(files, cfg, cmd.Root().Name)
+		}
+	}
+
+	opts := []bindgen.Option{
 		bindgen.GeneratedBy(cmd.Root().Name),
 		bindgen.World(cfg.world),
 		bindgen.PackageRoot(cfg.pkgRoot),
 		bindgen.Versioned(cfg.versioned),
+		bindgen.NamingScheme(cfg.naming),
 		bindgen.CMPackage(cfg.cm),
-	)
+		bindgen.CMAPICheck(cfg.cmCheck),
+		bindgen.EnumCheck(cfg.enumCheck),
+		bindgen.CharCheck(cfg.charCheck),
+		bindgen.StringCheck(cfg.strCheck),
+		bindgen.JSON(cfg.json),
+		bindgen.EmitIR(cfg.ir),
+		bindgen.FreeFunctions(cfg.freeFuncs),
+		bindgen.ManagedResources(cfg.managed),
+		bindgen.ResourceFinalizers(cfg.finalize),
+		bindgen.ResourceTables(cfg.tables),
+		bindgen.Scaffold(cfg.scaffold),
+		bindgen.DynamicValues(cfg.values),
+		bindgen.BinaryMarshal(cfg.binary),
+		bindgen.EqualMethods(cfg.equal),
+		bindgen.DeepCopy(cfg.deepCopy),
+		bindgen.Invoker(cfg.invoker),
+		bindgen.MockImports(cfg.mocks),
+		bindgen.CallHooks(cfg.hooks),
+		bindgen.LayoutTests(cfg.layout),
+		bindgen.TypeInfo(cfg.typeInfo),
+		bindgen.Prune(cfg.prune),
+		bindgen.Exhaustive(cfg.exhaust),
+		bindgen.Constructors(cfg.ctors),
+		bindgen.NullAccessors(cfg.nulls),
+		bindgen.StructTags(cfg.tags),
+		bindgen.Debug(cfg.debug),
+		bindgen.ArenaParams(cfg.arena),
+		bindgen.StackUsage(cfg.stack),
+		bindgen.BuildTags(cfg.buildTags),
+		bindgen.Stubs(cfg.stubs),
+		bindgen.EmptyAsm(cfg.emptyAsm),
+		bindgen.Target(cfg.target),
+	}
+	for ident, goName := range cfg.renames {
+		opts = append(opts, bindgen.Rename(ident, goName))
+	}
+	for namespace, template := range cfg.docsURLs {
+		opts = append(opts, bindgen.DocsURL(namespace, template))
+	}
+	for ident, override := range cfg.overrides {
+		opts = append(opts, bindgen.OverrideType(ident, override))
+	}
+
+	packages, err := bindgen.Go(res, opts...)
 	if err != nil {
 		return err
 	}
 
-	return writeGoPackages(packages, cfg)
+	if cfg.module != "" {
+		pkg, err := modulePackage(cfg, cmd.Root().Name)
+		if err != nil {
+			return err
+		}
+		packages = append(packages, pkg)
+	}
+
+	if cfg.buildJSON {
+		packages, err = addBuildJSON(packages, cfg, opts)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cfg.verify {
+		err = bindgen.Verify(packages, cfg.out, cfg.pkgRoot)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Verified %d package(s)\n", len(packages))
+	}
+
+	files, err := writeGoPackages(packages, cfg, cmd.Root().Name)
+	if err != nil {
+		return err
+	}
+
+	if cache != nil && cfg.cachePush && !cfg.dryRun {
+		if files == nil {
+			fmt.Fprintf(os.Stderr, "Not storing in artifact cache: some files could not be formatted\n")
+			return nil
+		}
+		err = cache.Put(ctx, key, files)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Stored %d file(s) in artifact cache: %s\n", len(files), key)
+	}
+	return nil
+}
+
+// cacheKey returns the artifact cache key for generating Go from WIT with content hash witHash,
+// which includes documentation and declaration order, as both change the generated files,
+// and the flags set on cmd, or an empty string if the version of this program is unknown,
+// such as a development build without version control information.
+// Flags that do not change the generated files, such as the output directory, are ignored.
+func cacheKey(cmd *cli.Command, cfg *config, witHash string) (string, error) {
+	version := buildVersion()
+	if version == "" {
+		fmt.Fprintf(os.Stderr, "Unknown version of %s; artifact cache disabled\n", cmd.Root().Name)
+		return "", nil
+	}
+	options := []string{cmd.Root().Name + "@" + version}
+	for _, f := range cmd.Flags {
+		name := f.Names()[0]
+		switch name {
+		case "out", "clean", "dry-run", "verify", "incremental", "manifest", "bad-files", "artifact-cache", "artifact-cache-push":
+			continue
+		}
+		if !cmd.IsSet(name) {
+			continue
+		}
+		value := fmt.Sprint(cmd.Value(name))
+		if name == "struct-tags" {
+			// The key depends on the struct tag configuration, not its path.
+			b, err := json.Marshal(cfg.tags)
+			if err != nil {
+				return "", err
+			}
+			value = string(b)
+		}
+		if name == "type-overrides" {
+			// The key depends on the type overrides, not the path of their file.
+			b, err := json.Marshal(cfg.overrides)
+			if err != nil {
+				return "", err
+			}
+			value = string(b)
+		}
+		options = append(options, "--"+name+"="+value)
+	}
+	return gencache.Key(witHash, options), nil
+}
+
+// buildVersion returns the module version of this program, or the version control
+// revision of a development build, or an empty string if unknown.
+func buildVersion() string {
+	if version := moduleVersion(cmModule); version != "" {
+		return version
+	}
+	build, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	var revision string
+	for _, s := range build.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			revision = s.Value
+		case "vcs.modified":
+			if s.Value == "true" {
+				// Uncommitted changes may change the generated files.
+				return ""
+			}
+		}
+	}
+	return revision
+}
+
+// writeCachedFiles writes files fetched from an artifact cache, indexed by path
+// relative to the output directory.
+func writeCachedFiles(files map[string][]byte, cfg *config, generatedBy string) error {
+	w, err := newWriter(cfg)
+	if err != nil {
+		return err
+	}
+	var written []string
+	stats := newWriteStats(cfg)
+	for _, rel := range codec.SortedKeys(files) {
+		path, err := writeFile(w, cfg, rel, "Generated file", nil, files[rel], stats)
+		if err != nil {
+			return err
+		}
+		written = append(written, path)
+	}
+	return finishWrite(w, cfg, generatedBy, written, stats)
+}
+
+// modulePackage returns a [gen.Package] for the root of Go module cfg.module,
+// containing a go.mod file and a doc.go file with package documentation.
+func modulePackage(cfg *config, generatedBy string) (*gen.Package, error) {
+	requires := make(map[string]string)
+	cm := cfg.cm
+	if cm == "" {
+		cm = cmPackage
+	}
+	switch {
+	case cm == cfg.module || strings.HasPrefix(cm, cfg.module+"/"):
+		// Package cm is within the generated module.
+	case cm == cmPackage:
+		if version := moduleVersion(cmModule); version != "" {
+			requires[cmModule] = version
+		} else {
+			fmt.Fprintf(os.Stderr, "Unknown version of module %s; run go mod tidy in %s\n", cmModule, cfg.out)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown module for package %s; run go mod tidy in %s\n", cm, cfg.out)
+	}
+
+	modFile, err := gen.ModFile(cfg.module, bindgen.GoVersion, requires)
+	if err != nil {
+		return nil, err
+	}
+
+	name := bindgen.GoPackageName(path.Base(cfg.module))
+	pkg := gen.NewPackage(cfg.module + "#" + name)
+	pkg.File("go.mod").Write(modFile)
+	doc := pkg.File("doc.go")
+	doc.GeneratedBy = generatedBy
+	doc.PackageDocs = "Package " + name + " is the root of Go module " + cfg.module +
+		", which contains Go bindings for WebAssembly Interface Types (WIT) generated by " + generatedBy + ".\n"
+	return pkg, nil
+}
+
+// addBuildJSON adds a build.json file describing how to build packages
+// to the package at the package root, creating it if necessary.
+func addBuildJSON(packages []*gen.Package, cfg *config, opts []bindgen.Option) ([]*gen.Package, error) {
+	build, err := bindgen.NewBuild(packages, opts...)
+	if err != nil {
+		return nil, err
+	}
+	build.Module = cfg.module
+
+	var root *gen.Package
+	for _, pkg := range packages {
+		if pkg.Path == cfg.pkgRoot {
+			root = pkg
+			break
+		}
+	}
+	if root == nil {
+		root = gen.NewPackage(cfg.pkgRoot)
+		packages = append(packages, root)
+	}
+
+	// Include build.json in its own description.
+	i := slices.IndexFunc(build.Packages, func(p bindgen.BuildPackage) bool { return p.Path == root.Path })
+	if i < 0 {
+		build.Packages = append(build.Packages, bindgen.BuildPackage{Path: root.Path, Dir: "."})
+		i = len(build.Packages) - 1
+	}
+	build.Packages[i].Files = append(build.Packages[i].Files, "build.json")
+	slices.Sort(build.Packages[i].Files)
+	slices.SortFunc(build.Packages, func(a, b bindgen.BuildPackage) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+
+	b, err := json.MarshalIndent(build, "", "\t")
+	if err != nil {
+		return nil, err
+	}
+	root.File("build.json").Write(append(b, '\n'))
+	return packages, nil
+}
+
+const (
+	cmModule  = "github.com/bytecodealliance/wasm-tools-go"
+	cmPackage = cmModule + "/cm"
+)
+
+// moduleVersion returns the version of Go module modpath that this program was built with,
+// or an empty string if unknown, such as a development build.
+func moduleVersion(modpath string) string {
+	build, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	version := ""
+	if build.Main.Path == modpath {
+		version = build.Main.Version
+	} else {
+		for _, dep := range build.Deps {
+			if dep.Path == modpath {
+				version = dep.Version
+			}
+		}
+	}
+	if version == "(devel)" {
+		return ""
+	}
+	return version
 }
 
 func parseFlags(cmd *cli.Command) (*config, error) {
@@ -116,8 +707,14 @@ func parseFlags(cmd *cli.Command) (*config, error) {
 	fmt.Fprintf(os.Stderr, "Output dir: %s\n", out)
 	outPerm := info.Mode().Perm()
 
+	module := cmd.String("module")
 	pkgRoot := cmd.String("package-root")
-	if !cmd.IsSet("package-root") {
+	switch {
+	case module != "" && !cmd.IsSet("package-root"):
+		pkgRoot = module
+	case module != "" && pkgRoot != module:
+		return nil, fmt.Errorf("package root %s does not match module path %s", pkgRoot, module)
+	case !cmd.IsSet("package-root"):
 		pkgRoot, err = gen.PackagePath(out)
 		if err != nil {
 			return nil, err
@@ -125,6 +722,31 @@ func parseFlags(cmd *cli.Command) (*config, error) {
 	}
 	fmt.Fprintf(os.Stderr, "Package root: %s\n", pkgRoot)
 
+	if cmd.Bool("stubs") && cmd.String("build-tags") == "" {
+		return nil, errors.New("--stubs requires --build-tags")
+	}
+
+	naming, err := bindgen.ParseNaming(cmd.String("naming"))
+	if err != nil {
+		return nil, err
+	}
+
+	var tags *bindgen.StructTagConfig
+	if file := cmd.String("struct-tags"); file != "" {
+		tags, err = loadStructTags(file)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var overrides map[string]bindgen.TypeOverride
+	if file := cmd.String("type-overrides"); file != "" {
+		overrides, err = loadTypeOverrides(file)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	path, err := witcli.LoadPath(cmd.Args().Slice()...)
 	if err != nil {
 		return nil, err
@@ -132,18 +754,113 @@ func parseFlags(cmd *cli.Command) (*config, error) {
 
 	return &config{
 		dryRun,
+		cmd.Bool("clean"),
 		out,
 		outPerm,
 		pkgRoot,
+		module,
 		cmd.String("world"),
 		cmd.String("cm"),
+		cmd.Bool("cm-api-check"),
+		cmd.Bool("enum-check"),
+		cmd.Bool("char-check"),
+		cmd.String("string-check"),
 		cmd.Bool("versioned"),
+		cmd.StringSlice("select"),
+		naming,
+		cmd.StringMap("rename"),
+		cmd.Bool("json"),
+		cmd.Bool("ir"),
+		cmd.Bool("free-functions"),
+		cmd.Bool("managed-resources"),
+		cmd.Bool("finalizers"),
+		cmd.Bool("resource-tables"),
+		cmd.Bool("scaffold"),
+		cmd.Bool("dynamic-values"),
+		cmd.Bool("binary"),
+		cmd.Bool("equal"),
+		cmd.Bool("deep-copy"),
+		cmd.Bool("invoker"),
+		cmd.Bool("mock-imports"),
+		cmd.Bool("call-hooks"),
+		cmd.Bool("layout-tests"),
+		cmd.Bool("type-info"),
+		cmd.Bool("prune"),
+		cmd.Bool("exhaustive"),
+		cmd.Bool("constructors"),
+		cmd.Bool("null-accessors"),
+		tags,
+		overrides,
+		cmd.Bool("debug"),
+		cmd.Bool("arena-params"),
+		cmd.Bool("stack-usage"),
+		cmd.StringMap("docs-url"),
+		cmd.Bool("strip-docs"),
+		cmd.String("build-tags"),
+		cmd.Bool("stubs"),
+		cmd.String("empty-asm"),
+		cmd.String("target"),
+		cmd.Bool("build-json"),
+		cmd.String("artifact-cache"),
+		cmd.Bool("artifact-cache-push"),
+		cmd.Bool("verify"),
+		cmd.Bool("incremental"),
+		cmd.String("manifest"),
+		cmd.Bool("bad-files"),
 		cmd.Bool("force-wit"),
+		cmd.String("lockfile"),
+		cmd.Bool("require-digest"),
 		path,
 	}, nil
 }
 
-func writeGoPackages(packages []*gen.Package, cfg *config) error {
+// loadStructTags loads a [bindgen.StructTagConfig] from the JSON file at path.
+func loadStructTags(path string) (*bindgen.StructTagConfig, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	dec := json.NewDecoder(f)
+	dec.DisallowUnknownFields()
+	var tags bindgen.StructTagConfig
+	err = dec.Decode(&tags)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &tags, nil
+}
+
+// loadTypeOverrides loads a map of WIT type identifiers to [bindgen.TypeOverride]
+// from the JSON file at path.
+func loadTypeOverrides(path string) (map[string]bindgen.TypeOverride, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	dec := json.NewDecoder(f)
+	dec.DisallowUnknownFields()
+	var overrides map[string]bindgen.TypeOverride
+	err = dec.Decode(&overrides)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return overrides, nil
+}
+
+// writeGoPackages writes the files in packages to the output directory.
+// It returns the content of the written files, indexed by path relative to the output directory,
+// or nil if any Go file could not be formatted.
+func writeGoPackages(packages []*gen.Package, cfg *config, generatedBy string) (map[string][]byte, error) {
+	w, err := newWriter(cfg)
+	if err != nil {
+		return nil, err
+	}
+	var written []string
+	stats := newWriteStats(cfg)
+	var bad bool
+	files := make(map[string][]byte)
 	fmt.Fprintf(os.Stderr, "Generated %d package(s)\n", len(packages))
 	for _, pkg := range packages {
 		if !pkg.HasContent() {
@@ -154,37 +871,174 @@ func writeGoPackages(packages []*gen.Package, cfg *config) error {
 
 		for _, filename := range codec.SortedKeys(pkg.Files) {
 			file := pkg.Files[filename]
-			dir := filepath.Join(cfg.out, strings.TrimPrefix(file.Package.Path, cfg.pkgRoot))
-			path := filepath.Join(dir, file.Name)
+			rel, err := w.Rel(file)
+			if err != nil {
+				return nil, err
+			}
+			path := filepath.Join(w.Root, filepath.FromSlash(rel))
 
 			if !file.HasContent() {
 				fmt.Fprintf(os.Stderr, "Skipping empty file: %s\n", path)
 				continue
 			}
-
-			if err := os.MkdirAll(dir, cfg.outPerm); err != nil {
-				return err
+			if file.Scaffold {
+				// Scaffolds are edited after they are written, so an existing scaffold is not
+				// overwritten. They are not stored in the artifact cache for the same reason.
+				if _, err := os.Stat(path); err == nil {
+					fmt.Fprintf(os.Stderr, "Skipping existing scaffold: %s\n", path)
+					continue
+				}
 			}
 
 			content, err := file.Bytes()
+			status := "Generated file"
 			if err != nil {
 				if content == nil {
-					return err
+					return nil, err
 				}
 				fmt.Fprintf(os.Stderr, "Error formatting file: %v\n", err)
-			} else {
-				fmt.Fprintf(os.Stderr, "Generated file: %s\n", path)
+				bad = true
+				status = ""
+				if cfg.badFiles {
+					// Write the unformatted source to a .bad file for debugging,
+					// rather than a Go file that does not compile.
+					rel += ".bad"
+					status = "Writing unformatted file"
+				}
 			}
-
-			if cfg.dryRun {
-				fmt.Println(string(content))
-				fmt.Println()
-				continue
+			if !file.Scaffold {
+				files[rel] = content
 			}
 
-			if err := os.WriteFile(path, content, cfg.outPerm); err != nil {
-				return err
+			path, err = writeFile(w, cfg, rel, status, pkg, content, stats)
+			if err != nil {
+				return nil, err
 			}
+			written = append(written, path)
+		}
+	}
+
+	if bad {
+		files = nil
+		if stats.manifest != nil {
+			fmt.Fprintf(os.Stderr, "Not writing manifest: some files could not be formatted\n")
+			stats.manifest = nil
+		}
+	}
+	return files, finishWrite(w, cfg, generatedBy, written, stats)
+}
+
+// newWriter returns a [gen.Writer] for the output directory, loading the content hashes
+// of previously written files if incremental generation is enabled.
+func newWriter(cfg *config) (*gen.Writer, error) {
+	w := &gen.Writer{
+		Root:        cfg.out,
+		PackageRoot: cfg.pkgRoot,
+		Perm:        cfg.outPerm,
+	}
+	if cfg.incr && !cfg.dryRun {
+		if err := w.LoadCache(); err != nil {
+			return nil, err
+		}
+	}
+	return w, nil
+}
+
+// writeStats counts the files written, and skipped because their content is unchanged,
+// and lists them in a manifest, if requested.
+type writeStats struct {
+	written   int
+	unchanged int
+	manifest  *bindgen.Manifest
+}
+
+func newWriteStats(cfg *config) *writeStats {
+	stats := &writeStats{}
+	if cfg.manifest != "" {
+		stats.manifest = &bindgen.Manifest{PackageRoot: cfg.pkgRoot}
+	}
+	return stats
+}
+
+// writeFile writes content to the file at slash-separated path rel in the output directory,
+// reporting it with status, if non-empty, or as unchanged if the file was not written.
+// In a dry run, content is printed to stdout instead. It returns the path of the file.
+// The file is added to the manifest with Go package pkg, if known.
+func writeFile(w *gen.Writer, cfg *config, rel, status string, pkg *gen.Package, content []byte, stats *writeStats) (string, error) {
+	if stats.manifest != nil {
+		stats.manifest.Add(rel, pkg, content)
+	}
+	path := filepath.Join(w.Root, filepath.FromSlash(rel))
+	if cfg.dryRun {
+		if status != "" {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", status, path)
+		}
+		fmt.Println(string(content))
+		fmt.Println()
+		return path, nil
+	}
+	_, ok, err := w.Update(rel, content)
+	if err != nil {
+		return "", err
+	}
+	if ok {
+		stats.written++
+	} else {
+		stats.unchanged++
+		status = "Unchanged file"
+	}
+	if status != "" {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", status, path)
+	}
+	return path, nil
+}
+
+// finishWrite removes stale files if cfg.clean is set, saves the content hashes of written files
+// if incremental generation is enabled, writes the manifest, if any, and reports stats.
+func finishWrite(w *gen.Writer, cfg *config, generatedBy string, written []string, stats *writeStats) error {
+	if cfg.clean {
+		if err := removeStale(w, cfg, generatedBy, written); err != nil {
+			return err
+		}
+	}
+	if cfg.dryRun {
+		return nil
+	}
+	if cfg.incr {
+		if err := w.SaveCache(); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(os.Stderr, "Wrote %d file(s), skipped %d unchanged file(s)\n", stats.written, stats.unchanged)
+	if stats.manifest == nil {
+		return nil
+	}
+	b, err := json.MarshalIndent(stats.manifest, "", "\t")
+	if err != nil {
+		return err
+	}
+	err = os.WriteFile(cfg.manifest, append(b, '\n'), 0o644)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Wrote manifest of %d file(s): %s\n", len(stats.manifest.Files), cfg.manifest)
+	return nil
+}
+
+// removeStale removes Go files previously generated by generatedBy
+// from the output directory that are not in written.
+func removeStale(w *gen.Writer, cfg *config, generatedBy string, written []string) error {
+	stale, err := w.Stale(generatedBy, written)
+	if err != nil {
+		return err
+	}
+	for _, path := range stale {
+		fmt.Fprintf(os.Stderr, "Removing stale file: %s\n", path)
+		if cfg.dryRun {
+			continue
+		}
+		if err := w.Remove(path); err != nil {
+			return err
 		}
 	}
 	return nil
@@ -0,0 +1,65 @@
+package size
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/bytecodealliance/wasm-tools-go/internal/sizereport"
+)
+
+// Command is the CLI command for size.
+var Command = &cli.Command{
+	Name:      "size",
+	Usage:     "reports the code size of generated Go packages built with Go (GOARCH=wasm) and TinyGo",
+	ArgsUsage: "[<package-pattern>...]",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:      "dir",
+			Aliases:   []string{"C"},
+			Value:     ".",
+			OnlyOnce:  true,
+			TakesFile: true,
+			Config:    cli.StringConfig{TrimSpace: true},
+			Usage:     "directory of the Go module containing the generated packages",
+		},
+		&cli.StringFlag{
+			Name:     "tags",
+			OnlyOnce: true,
+			Config:   cli.StringConfig{TrimSpace: true},
+			Usage:    "build tags passed to each compiler, e.g. wasip2",
+		},
+		&cli.StringFlag{
+			Name:      "tinygo",
+			OnlyOnce:  true,
+			TakesFile: true,
+			Config:    cli.StringConfig{TrimSpace: true},
+			Usage:     "path to the tinygo command (default: tinygo in PATH, if any)",
+		},
+		&cli.BoolFlag{
+			Name:  "json",
+			Usage: "write the report as JSON",
+		},
+	},
+	Action: action,
+}
+
+func action(ctx context.Context, cmd *cli.Command) error {
+	r, err := sizereport.Run(ctx, sizereport.Options{
+		Dir:      cmd.String("dir"),
+		Patterns: cmd.Args().Slice(),
+		Tags:     cmd.String("tags"),
+		TinyGo:   cmd.String("tinygo"),
+	})
+	if err != nil {
+		return err
+	}
+	if cmd.Bool("json") {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	}
+	return r.Write(os.Stdout)
+}
@@ -0,0 +1,143 @@
+package verify
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/bytecodealliance/wasm-tools-go/internal/go/gen"
+	"github.com/bytecodealliance/wasm-tools-go/internal/witcli"
+	"github.com/bytecodealliance/wasm-tools-go/wit/bindgen"
+)
+
+// Command is the CLI command for verify.
+var Command = &cli.Command{
+	Name:      "verify",
+	Usage:     "reports drift between existing Go bindings and the Go generated from a WIT world",
+	ArgsUsage: "[<dir>]",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:      "wit",
+			Value:     "",
+			OnlyOnce:  true,
+			TakesFile: true,
+			Config:    cli.StringConfig{TrimSpace: true},
+			Usage:     "path to WIT or WIT JSON to generate Go from, otherwise read from stdin",
+		},
+		&cli.StringFlag{
+			Name:     "world",
+			Aliases:  []string{"w"},
+			Value:    "",
+			OnlyOnce: true,
+			Config:   cli.StringConfig{TrimSpace: true},
+			Usage:    "WIT world the bindings were generated from, otherwise all worlds",
+		},
+		&cli.StringFlag{
+			Name:     "package-root",
+			Aliases:  []string{"p"},
+			Value:    "",
+			OnlyOnce: true,
+			Config:   cli.StringConfig{TrimSpace: true},
+			Usage:    "Go package root of the bindings, otherwise derived from the directory",
+		},
+		&cli.BoolFlag{
+			Name:  "versioned",
+			Usage: "the bindings have versioned Go package(s) for each WIT version",
+		},
+		&cli.StringFlag{
+			Name:     "naming",
+			Value:    bindgen.DefaultNaming.String(),
+			OnlyOnce: true,
+			Config:   cli.StringConfig{TrimSpace: true},
+			Usage:    "naming scheme that maps WIT names to Go names (v1 or v2)",
+		},
+		&cli.StringMapFlag{
+			Name:  "rename",
+			Usage: "Go name of a WIT type or function, e.g. wasi:io/streams#input-stream=Reader (repeatable)",
+		},
+		&cli.BoolFlag{
+			Name:  "free-functions",
+			Usage: "the bindings have free functions instead of methods on resource types",
+		},
+		&cli.BoolFlag{
+			Name:  "managed-resources",
+			Usage: "the bindings have managed wrapper types for imported resources",
+		},
+	},
+	Action: action,
+}
+
+func action(ctx context.Context, cmd *cli.Command) error {
+	dir := "."
+	switch cmd.Args().Len() {
+	case 0:
+	case 1:
+		dir = cmd.Args().First()
+	default:
+		return fmt.Errorf("found %d directory arguments, expecting 0 or 1", cmd.Args().Len())
+	}
+
+	var paths []string
+	if p := cmd.String("wit"); p != "" {
+		paths = append(paths, p)
+	}
+	path, err := witcli.LoadPath(paths...)
+	if err != nil {
+		return err
+	}
+	res, err := witcli.Load(ctx, path, witcli.Options{
+		ForceWIT:      cmd.Bool("force-wit"),
+		Lockfile:      cmd.String("lockfile"),
+		RequireDigest: cmd.Bool("require-digest"),
+	})
+	if err != nil {
+		return err
+	}
+	err = res.ResolveIncludes()
+	if err != nil {
+		return err
+	}
+
+	pkgRoot := cmd.String("package-root")
+	if pkgRoot == "" {
+		pkgRoot, err = gen.PackagePath(dir)
+		if err != nil {
+			return err
+		}
+	}
+	naming, err := bindgen.ParseNaming(cmd.String("naming"))
+	if err != nil {
+		return err
+	}
+	opts := []bindgen.Option{
+		bindgen.GeneratedBy(cmd.Root().Name),
+		bindgen.World(cmd.String("world")),
+		bindgen.PackageRoot(pkgRoot),
+		bindgen.Versioned(cmd.Bool("versioned")),
+		bindgen.NamingScheme(naming),
+		bindgen.FreeFunctions(cmd.Bool("free-functions")),
+		bindgen.ManagedResources(cmd.Bool("managed-resources")),
+	}
+	for ident, goName := range cmd.StringMap("rename") {
+		opts = append(opts, bindgen.Rename(ident, goName))
+	}
+
+	packages, err := bindgen.Go(res, opts...)
+	if err != nil {
+		return err
+	}
+	drift, err := bindgen.Compare(packages, dir)
+	if err != nil {
+		return err
+	}
+	for _, d := range drift {
+		fmt.Println(d.String())
+	}
+	if len(drift) > 0 {
+		return fmt.Errorf("%d declaration(s) in %s differ from the Go generated from WIT", len(drift), dir)
+	}
+	fmt.Fprintf(os.Stderr, "Verified %d package(s) in %s\n", len(packages), dir)
+	return nil
+}
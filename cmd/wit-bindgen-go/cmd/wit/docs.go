@@ -0,0 +1,74 @@
+package wit
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bytecodealliance/wasm-tools-go/internal/witcli"
+	"github.com/bytecodealliance/wasm-tools-go/wit/docs"
+	"github.com/urfave/cli/v3"
+)
+
+var docsCommand = &cli.Command{
+	Name:  "docs",
+	Usage: "renders documentation for WIT packages as Markdown or HTML",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "html",
+			Usage: "render HTML instead of Markdown",
+		},
+		&cli.StringFlag{
+			Name:      "out",
+			Aliases:   []string{"o"},
+			Value:     "",
+			OnlyOnce:  true,
+			TakesFile: true,
+			Config:    cli.StringConfig{TrimSpace: true},
+			Usage:     "output directory for one file per WIT package, otherwise write to stdout",
+		},
+	},
+	Action: docsAction,
+}
+
+func docsAction(ctx context.Context, cmd *cli.Command) error {
+	path, err := witcli.LoadPath(cmd.Args().Slice()...)
+	if err != nil {
+		return err
+	}
+	res, err := witcli.Load(ctx, path, witcli.Options{
+		ForceWIT:      cmd.Bool("force-wit"),
+		Lockfile:      cmd.String("lockfile"),
+		RequireDigest: cmd.Bool("require-digest"),
+	})
+	if err != nil {
+		return err
+	}
+
+	render, ext := docs.Markdown, ".md"
+	if cmd.Bool("html") {
+		render, ext = docs.HTML, ".html"
+	}
+
+	out := cmd.String("out")
+	if out == "" {
+		fmt.Print(render(res.Packages...))
+		return nil
+	}
+	err = os.MkdirAll(out, 0755)
+	if err != nil {
+		return err
+	}
+	for _, pkg := range res.Packages {
+		name := strings.NewReplacer(":", "-", "/", "-").Replace(pkg.Name.String()) + ext
+		path := filepath.Join(out, name)
+		err = os.WriteFile(path, []byte(render(pkg)), 0644)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Generated file: %s\n", path)
+	}
+	return nil
+}
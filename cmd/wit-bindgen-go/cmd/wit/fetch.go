@@ -0,0 +1,65 @@
+package wit
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/bytecodealliance/wasm-tools-go/internal/oci"
+	"github.com/bytecodealliance/wasm-tools-go/internal/witcli"
+	"github.com/urfave/cli/v3"
+)
+
+var fetchCommand = &cli.Command{
+	Name:      "fetch",
+	Usage:     "fetches WIT packages and their dependencies from OCI registries into a WIT deps directory",
+	ArgsUsage: "<oci-ref>...",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:      "out",
+			Aliases:   []string{"o"},
+			Value:     "wit/deps",
+			OnlyOnce:  true,
+			TakesFile: true,
+			Config:    cli.StringConfig{TrimSpace: true},
+			Usage:     "output directory for one subdirectory per WIT package",
+		},
+		&cli.StringFlag{
+			Name:      "cache-dir",
+			Value:     defaultCacheDir(),
+			OnlyOnce:  true,
+			TakesFile: true,
+			Config:    cli.StringConfig{TrimSpace: true},
+			Usage:     "directory to cache WIT fetched from OCI registries, or empty to disable caching",
+		},
+	},
+	Action: fetchAction,
+}
+
+func defaultCacheDir() string {
+	dir, _ := oci.DefaultCacheDir()
+	return dir
+}
+
+func fetchAction(ctx context.Context, cmd *cli.Command) error {
+	res, err := witcli.Fetch(ctx, cmd.Args().Slice(), witcli.Options{
+		Lockfile:      cmd.String("lockfile"),
+		RequireDigest: cmd.Bool("require-digest"),
+		CacheDir:      cmd.String("cache-dir"),
+	})
+	if err != nil {
+		return err
+	}
+	err = res.Validate()
+	if err != nil {
+		return err
+	}
+	paths, err := witcli.WriteDeps(cmd.String("out"), res)
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		fmt.Fprintf(os.Stderr, "Generated file: %s\n", path)
+	}
+	return nil
+}
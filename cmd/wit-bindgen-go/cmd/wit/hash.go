@@ -0,0 +1,46 @@
+package wit
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bytecodealliance/wasm-tools-go/internal/witcli"
+	"github.com/bytecodealliance/wasm-tools-go/wit"
+	"github.com/urfave/cli/v3"
+)
+
+var hashCommand = &cli.Command{
+	Name:  "hash",
+	Usage: "prints a canonical content hash of WIT, independent of documentation and declaration order",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "docs",
+			Usage: "include documentation in the hash",
+		},
+		&cli.BoolFlag{
+			Name:  "order",
+			Usage: "include the order of declarations in the hash",
+		},
+	},
+	Action: hashAction,
+}
+
+func hashAction(ctx context.Context, cmd *cli.Command) error {
+	path, err := witcli.LoadPath(cmd.Args().Slice()...)
+	if err != nil {
+		return err
+	}
+	res, err := witcli.Load(ctx, path, witcli.Options{
+		ForceWIT:      cmd.Bool("force-wit"),
+		Lockfile:      cmd.String("lockfile"),
+		RequireDigest: cmd.Bool("require-digest"),
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Println(res.HashWith(wit.HashOptions{
+		Docs:  cmd.Bool("docs"),
+		Order: cmd.Bool("order"),
+	}))
+	return nil
+}
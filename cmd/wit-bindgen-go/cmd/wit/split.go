@@ -0,0 +1,114 @@
+package wit
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/bytecodealliance/wasm-tools-go/internal/witcli"
+	"github.com/bytecodealliance/wasm-tools-go/wit"
+	"github.com/urfave/cli/v3"
+)
+
+var splitCommand = &cli.Command{
+	Name:  "split",
+	Usage: "writes WIT packages to files in a directory, such as a wit directory with deps",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:      "out",
+			Aliases:   []string{"o"},
+			Value:     ".",
+			OnlyOnce:  true,
+			TakesFile: true,
+			Config:    cli.StringConfig{TrimSpace: true},
+			Usage:     "output directory",
+		},
+		&cli.StringFlag{
+			Name:     "layout",
+			Value:    "deps",
+			OnlyOnce: true,
+			Config:   cli.StringConfig{TrimSpace: true},
+			Usage:    "file layout: multi (one file), nested (one file, braced packages), files (one file per package), or deps (root package with deps/ directory)",
+		},
+		&cli.StringFlag{
+			Name:     "world",
+			Aliases:  []string{"w"},
+			Value:    "",
+			OnlyOnce: true,
+			Config:   cli.StringConfig{TrimSpace: true},
+			Usage:    "WIT world to write, with the interfaces it references, otherwise write all worlds",
+		},
+		&cli.StringFlag{
+			Name:     "root",
+			Value:    "",
+			OnlyOnce: true,
+			Config:   cli.StringConfig{TrimSpace: true},
+			Usage:    "root WIT package, e.g. wasi:cli, otherwise the package of --world or the last package",
+		},
+		&cli.BoolFlag{
+			Name:  "strip-docs",
+			Usage: "remove documentation from WIT output",
+		},
+	},
+	Action: splitAction,
+}
+
+func splitAction(ctx context.Context, cmd *cli.Command) error {
+	layout, ok := wit.ParseLayout(cmd.String("layout"))
+	if !ok {
+		return fmt.Errorf("invalid --layout value %q: must be multi, nested, files, or deps", cmd.String("layout"))
+	}
+	path, err := witcli.LoadPath(cmd.Args().Slice()...)
+	if err != nil {
+		return err
+	}
+	res, err := witcli.Load(ctx, path, witcli.Options{
+		ForceWIT:      cmd.Bool("force-wit"),
+		Lockfile:      cmd.String("lockfile"),
+		RequireDigest: cmd.Bool("require-digest"),
+	})
+	if err != nil {
+		return err
+	}
+	if cmd.Bool("strip-docs") {
+		res.StripDocs()
+	}
+	opts := []wit.WriteOption{wit.WithLayout(layout)}
+	if root := cmd.String("root"); root != "" {
+		p := res.FindPackage(root)
+		if p == nil {
+			return fmt.Errorf("package %s not found", root)
+		}
+		opts = append(opts, wit.WithRoot(p))
+	}
+	var w *wit.World
+	if world := cmd.String("world"); world != "" {
+		w = res.FindWorld(world)
+		if w == nil {
+			return fmt.Errorf("world %s not found", world)
+		}
+	}
+
+	files := res.WITFiles(w, opts...)
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	out := cmd.String("out")
+	for _, name := range names {
+		path := filepath.Join(out, filepath.FromSlash(name))
+		err = os.MkdirAll(filepath.Dir(path), 0755)
+		if err != nil {
+			return err
+		}
+		err = os.WriteFile(path, []byte(files[name]), 0644)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Generated file: %s\n", path)
+	}
+	return nil
+}
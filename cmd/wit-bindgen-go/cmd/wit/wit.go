@@ -2,7 +2,12 @@ package wit
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
 
 	"github.com/bytecodealliance/wasm-tools-go/internal/witcli"
 	"github.com/bytecodealliance/wasm-tools-go/wit"
@@ -22,6 +27,34 @@ var Command = &cli.Command{
 			Config:   cli.StringConfig{TrimSpace: true},
 			Usage:    "WIT world to generate, otherwise generate all worlds",
 		},
+		&cli.StringSliceFlag{
+			Name:  "select",
+			Usage: "select a version of a WIT package by semver constraint, removing other versions, e.g. wasi:io@^0.2 (repeatable)",
+		},
+		&cli.BoolFlag{
+			Name:  "strip-docs",
+			Usage: "remove documentation from WIT output",
+		},
+		&cli.StringFlag{
+			Name:     "color",
+			Value:    "auto",
+			OnlyOnce: true,
+			Config:   cli.StringConfig{TrimSpace: true},
+			Usage:    "highlight WIT syntax with ANSI colors: auto, always, or never",
+		},
+		&cli.StringFlag{
+			Name:     "pager",
+			Value:    "auto",
+			OnlyOnce: true,
+			Config:   cli.StringConfig{TrimSpace: true},
+			Usage:    "page output with $PAGER or less -R: auto, always, or never",
+		},
+	},
+	Commands: []*cli.Command{
+		docsCommand,
+		fetchCommand,
+		hashCommand,
+		splitCommand,
 	},
 	Action: action,
 }
@@ -31,27 +64,98 @@ func action(ctx context.Context, cmd *cli.Command) error {
 	if err != nil {
 		return err
 	}
-	res, err := witcli.LoadWIT(ctx, cmd.Bool("force-wit"), path)
+	res, err := witcli.Load(ctx, path, witcli.Options{
+		ForceWIT:      cmd.Bool("force-wit"),
+		Lockfile:      cmd.String("lockfile"),
+		RequireDigest: cmd.Bool("require-digest"),
+	})
 	if err != nil {
 		return err
 	}
+	if selects := cmd.StringSlice("select"); len(selects) > 0 {
+		err = res.SelectVersions(selects...)
+		if err != nil {
+			return err
+		}
+	}
+	if cmd.Bool("strip-docs") {
+		res.StripDocs()
+	}
 	var w *wit.World
 	world := cmd.String("world")
 	if world != "" {
-		w = findWorld(res, world)
+		w = res.FindWorld(world)
 		if w == nil {
 			return fmt.Errorf("world %s not found", world)
 		}
 	}
-	fmt.Print(res.WIT(w, ""))
+	color, err := parseWhen("color", cmd.String("color"))
+	if err != nil {
+		return err
+	}
+	pager, err := parseWhen("pager", cmd.String("pager"))
+	if err != nil {
+		return err
+	}
+	tty := isTerminal(os.Stdout)
+	if color == "auto" {
+		color = "never"
+		if tty && os.Getenv("NO_COLOR") == "" && os.Getenv("TERM") != "dumb" {
+			color = "always"
+		}
+	}
+	if pager == "auto" {
+		pager = "never"
+		if tty {
+			pager = "always"
+		}
+	}
+
+	if color == "never" && pager == "never" {
+		_, err = res.WriteWIT(os.Stdout, w)
+		return err
+	}
+	out := res.WIT(w, "")
+	if color == "always" {
+		out = witcli.Highlight(out)
+	}
+	if pager == "always" {
+		return page(out)
+	}
+	fmt.Print(out)
 	return nil
 }
 
-func findWorld(r *wit.Resolve, pattern string) *wit.World {
-	for _, w := range r.Worlds {
-		if w.Match(pattern) {
-			return w
-		}
+// parseWhen validates the value of a flag that accepts auto, always, or never.
+func parseWhen(flag, value string) (string, error) {
+	switch value {
+	case "auto", "always", "never":
+		return value, nil
 	}
-	return nil
+	return "", fmt.Errorf("invalid --%s value %q: must be auto, always, or never", flag, value)
+}
+
+// isTerminal returns true if f is a character device, such as a terminal.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// page writes s to a pager: $PAGER if set, otherwise less -FRX, which preserves
+// ANSI colors and exits if s fits on one screen. If the pager cannot be started,
+// s is written to stdout.
+func page(s string) error {
+	args := []string{"less", "-FRX"}
+	if p := strings.Fields(os.Getenv("PAGER")); len(p) > 0 {
+		args = p
+	}
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Stdin = strings.NewReader(s)
+	err := cmd.Run()
+	if errors.Is(err, exec.ErrNotFound) {
+		_, err = io.WriteString(os.Stdout, s)
+	}
+	return err
 }
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/bytecodealliance/wasm-tools-go/wit"
+	"github.com/bytecodealliance/wasm-tools-go/wit/bindgen"
+)
+
+// printError writes err to w. Errors that identify a WIT item, such as a
+// [bindgen.GenerateError] or [wit.ValidationError], are rendered with the
+// position of the item in WIT source, if known, and a suggestion, if any:
+//
+//	error: cannot rename to Reader: WIT type or function not found
+//	  --> wasi:io/streams@0.2.0#input-strem
+//	  help: did you mean input-stream?
+func printError(w io.Writer, err error) {
+	var gerr *bindgen.GenerateError
+	if errors.As(err, &gerr) {
+		printDiagnostic(w, gerr.Msg, gerr.Pos, gerr.Ident(), gerr.Suggestion)
+		return
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, err := range joined.Unwrap() {
+			printError(w, err)
+		}
+		return
+	}
+	var verr *wit.ValidationError
+	if errors.As(err, &verr) {
+		printDiagnostic(w, verr.Message, verr.Pos, verr.Path, "")
+		return
+	}
+	fmt.Fprintf(w, "error: %v\n", err)
+}
+
+func printDiagnostic(w io.Writer, msg string, pos wit.Position, ident, suggestion string) {
+	fmt.Fprintf(w, "error: %s\n", msg)
+	switch {
+	case pos.IsValid() && ident != "":
+		fmt.Fprintf(w, "  --> %s (%s)\n", pos, ident)
+	case pos.IsValid():
+		fmt.Fprintf(w, "  --> %s\n", pos)
+	case ident != "":
+		fmt.Fprintf(w, "  --> %s\n", ident)
+	}
+	if suggestion != "" {
+		fmt.Fprintf(w, "  help: %s\n", suggestion)
+	}
+}
@@ -2,13 +2,17 @@ package main
 
 import (
 	"context"
-	"fmt"
 	"os"
 	"runtime/debug"
 
 	"github.com/urfave/cli/v3"
 
+	"github.com/bytecodealliance/wasm-tools-go/cmd/wit-bindgen-go/cmd/describe"
+	"github.com/bytecodealliance/wasm-tools-go/cmd/wit-bindgen-go/cmd/doctor"
+	"github.com/bytecodealliance/wasm-tools-go/cmd/wit-bindgen-go/cmd/embed"
 	"github.com/bytecodealliance/wasm-tools-go/cmd/wit-bindgen-go/cmd/generate"
+	"github.com/bytecodealliance/wasm-tools-go/cmd/wit-bindgen-go/cmd/size"
+	"github.com/bytecodealliance/wasm-tools-go/cmd/wit-bindgen-go/cmd/verify"
 	"github.com/bytecodealliance/wasm-tools-go/cmd/wit-bindgen-go/cmd/wit"
 )
 
@@ -44,7 +48,12 @@ func main() {
 		Name:  "wit-bindgen-go",
 		Usage: "inspect or manipulate WebAssembly Interface Types for Go",
 		Commands: []*cli.Command{
+			describe.Command,
+			doctor.Command,
+			embed.Command,
 			generate.Command,
+			size.Command,
+			verify.Command,
 			wit.Command,
 		},
 		Flags: []cli.Flag{
@@ -52,13 +61,25 @@ func main() {
 				Name:  "force-wit",
 				Usage: "force loading WIT via wasm-tools",
 			},
+			&cli.StringFlag{
+				Name:      "lockfile",
+				Value:     "",
+				OnlyOnce:  true,
+				TakesFile: true,
+				Config:    cli.StringConfig{TrimSpace: true},
+				Usage:     "path to lockfile that pins and records digests of WIT fetched from OCI registries",
+			},
+			&cli.BoolFlag{
+				Name:  "require-digest",
+				Usage: "require WIT fetched from OCI registries to be pinned by digest, in the reference or lockfile",
+			},
 		},
 		Version: versionString,
 	}
 
 	err := cmd.Run(context.Background(), os.Args)
 	if err != nil {
-		fmt.Printf("error: %v\n", err)
+		printError(os.Stdout, err)
 		os.Exit(1)
 	}
 }
@@ -0,0 +1,208 @@
+// Package gencache implements a content-addressed HTTP cache of generated Go bindings.
+//
+// A cache is an HTTP server that stores archives of generated files. Each archive is
+// identified by a key, the SHA-256 digest of the WIT input and the options used to
+// generate it. The protocol is deliberately simple, so it can be served by a static file
+// server or an object store:
+//
+//	GET {url}/{key}.tar.gz   fetch an archive; 404 Not Found if missing
+//	PUT {url}/{key}.tar.gz   store an archive
+//
+// An archive is a gzip-compressed tar file of regular files, with slash-separated
+// paths relative to the output directory.
+package gencache
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+)
+
+// MaxSize is the maximum size in bytes of the uncompressed files in an archive.
+const MaxSize = 256 << 20
+
+// Key returns the cache key for generated files from WIT input wit, such as WIT source text
+// or a content hash, generated with options, e.g. "--json" or "--world=wasi:cli/command".
+// Options are sorted, so their order does not change the key.
+func Key(wit string, options []string) string {
+	options = slices.Clone(options)
+	slices.Sort(options)
+	h := sha256.New()
+	fmt.Fprintf(h, "%d\n%s\n", len(wit), wit)
+	for _, opt := range options {
+		fmt.Fprintf(h, "%d\n%s\n", len(opt), opt)
+	}
+	return "sha256-" + hex.EncodeToString(h.Sum(nil))
+}
+
+// Cache is a client for an HTTP cache of generated files.
+type Cache struct {
+	// URL is the base URL of the cache, e.g. "https://cache.example.com/wit-bindgen-go".
+	URL string
+
+	// Client is the HTTP client used to make requests.
+	// If nil, [http.DefaultClient] is used.
+	Client *http.Client
+}
+
+// Get fetches the files stored under key, indexed by relative path.
+// It returns nil and no error if the cache does not contain key.
+func (c *Cache) Get(ctx context.Context, key string) (map[string][]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url(key), nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.client().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	switch res.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("GET %s: %s", req.URL, res.Status)
+	}
+	files, err := Decode(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("GET %s: %w", req.URL, err)
+	}
+	return files, nil
+}
+
+// Put stores files under key, indexed by relative path.
+func (c *Cache) Put(ctx context.Context, key string, files map[string][]byte) error {
+	var b bytes.Buffer
+	err := Encode(&b, files)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, c.url(key), &b)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/gzip")
+	res, err := c.client().Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("PUT %s: %s", req.URL, res.Status)
+	}
+	return nil
+}
+
+func (c *Cache) url(key string) string {
+	return strings.TrimSuffix(c.URL, "/") + "/" + key + ".tar.gz"
+}
+
+func (c *Cache) client() *http.Client {
+	if c.Client != nil {
+		return c.Client
+	}
+	return http.DefaultClient
+}
+
+// Encode writes files, indexed by relative path, to w as a gzip-compressed tar archive.
+// Files are written in sorted order, so the archive is reproducible.
+func Encode(w io.Writer, files map[string][]byte) error {
+	zw := gzip.NewWriter(w)
+	tw := tar.NewWriter(zw)
+	paths := make([]string, 0, len(files))
+	for name := range files {
+		paths = append(paths, name)
+	}
+	slices.Sort(paths)
+	for _, name := range paths {
+		if !isLocal(name) {
+			return fmt.Errorf("invalid path %q", name)
+		}
+		content := files[name]
+		err := tw.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     name,
+			Mode:     0o644,
+			Size:     int64(len(content)),
+		})
+		if err != nil {
+			return err
+		}
+		_, err = tw.Write(content)
+		if err != nil {
+			return err
+		}
+	}
+	err := tw.Close()
+	if err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// Decode reads a gzip-compressed tar archive from r, returning its files indexed by relative path.
+// It returns an error if the archive contains entries other than regular files,
+// paths outside of the archive root, or more than [MaxSize] bytes.
+func Decode(r io.Reader) (map[string][]byte, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	tr := tar.NewReader(zr)
+	files := make(map[string][]byte)
+	var size int64
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if h.Typeflag != tar.TypeReg {
+			return nil, fmt.Errorf("unsupported entry %q in archive", h.Name)
+		}
+		if !isLocal(h.Name) {
+			return nil, fmt.Errorf("invalid path %q in archive", h.Name)
+		}
+		if _, ok := files[h.Name]; ok {
+			return nil, fmt.Errorf("duplicate path %q in archive", h.Name)
+		}
+		size += h.Size
+		if h.Size < 0 || size > MaxSize {
+			return nil, errors.New("archive exceeds maximum size")
+		}
+		content, err := io.ReadAll(io.LimitReader(tr, h.Size))
+		if err != nil {
+			return nil, err
+		}
+		files[h.Name] = content
+	}
+	return files, nil
+}
+
+// isLocal reports whether name is a clean, slash-separated relative path
+// that does not refer to a parent directory.
+func isLocal(name string) bool {
+	if name == "" || path.IsAbs(name) || path.Clean(name) != name || strings.ContainsAny(name, "\\\x00") {
+		return false
+	}
+	for _, elem := range strings.Split(name, "/") {
+		if elem == ".." || elem == "." {
+			return false
+		}
+	}
+	return true
+}
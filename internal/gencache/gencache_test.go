@@ -0,0 +1,140 @@
+package gencache
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestKey(t *testing.T) {
+	wit := "package wasi:cli@0.2.0;"
+	a := Key(wit, []string{"--json", "--world=command"})
+	b := Key(wit, []string{"--world=command", "--json"})
+	if a != b {
+		t.Errorf("Key(): %s != %s, expected order of options to be ignored", a, b)
+	}
+	if !strings.HasPrefix(a, "sha256-") {
+		t.Errorf("Key(): %s, expected sha256- prefix", a)
+	}
+	for _, k := range []string{
+		Key(wit, []string{"--json"}),
+		Key(wit, nil),
+		Key("package wasi:cli@0.2.1;", []string{"--json", "--world=command"}),
+		Key(wit, []string{"--json", "--world", "=command"}),
+	} {
+		if k == a {
+			t.Errorf("Key(): %s, expected different keys for different inputs", k)
+		}
+	}
+}
+
+func TestCache(t *testing.T) {
+	var mu sync.Mutex
+	store := make(map[string][]byte)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case http.MethodGet:
+			b, ok := store[r.URL.Path]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			w.Write(b)
+		case http.MethodPut:
+			b, err := io.ReadAll(r.Body)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			store[r.URL.Path] = b
+			w.WriteHeader(http.StatusCreated)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	c := &Cache{URL: srv.URL + "/bindings/", Client: srv.Client()}
+	key := Key("package example:foo;", nil)
+
+	files, err := c.Get(ctx, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if files != nil {
+		t.Errorf("Get(): %v, expected nil for missing key", files)
+	}
+
+	want := map[string][]byte{
+		"go.mod":                 []byte("module example.com/bindings\n"),
+		"example/foo/foo.wit.go": []byte("package foo\n"),
+	}
+	err = c.Put(ctx, key, want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := store["/bindings/"+key+".tar.gz"]; !ok {
+		t.Errorf("Put(): archive not stored at /bindings/%s.tar.gz", key)
+	}
+
+	files, err = c.Get(ctx, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !maps.EqualFunc(files, want, bytes.Equal) {
+		t.Errorf("Get(): %v, expected %v", files, want)
+	}
+}
+
+func TestEncodeInvalidPath(t *testing.T) {
+	for _, name := range []string{"", "../evil.go", "/evil.go", "foo/../evil.go", "./evil.go", `foo\evil.go`} {
+		err := Encode(io.Discard, map[string][]byte{name: nil})
+		if err == nil {
+			t.Errorf("Encode(%q): expected error", name)
+		}
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers []tar.Header
+	}{
+		{"parent", []tar.Header{{Typeflag: tar.TypeReg, Name: "../evil.go"}}},
+		{"absolute", []tar.Header{{Typeflag: tar.TypeReg, Name: "/etc/evil.go"}}},
+		{"symlink", []tar.Header{{Typeflag: tar.TypeSymlink, Name: "link", Linkname: "/etc"}}},
+		{"duplicate", []tar.Header{{Typeflag: tar.TypeReg, Name: "foo.go"}, {Typeflag: tar.TypeReg, Name: "foo.go"}}},
+		{"size", []tar.Header{{Typeflag: tar.TypeReg, Name: "foo.go", Size: MaxSize + 1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			zw := gzip.NewWriter(&b)
+			tw := tar.NewWriter(zw)
+			for i := range tt.headers {
+				err := tw.WriteHeader(&tt.headers[i])
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			// Do not close tw, which would require writing Size bytes of content.
+			tw.Flush()
+			zw.Close()
+			_, err := Decode(&b)
+			if err == nil {
+				t.Errorf("Decode(): expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package gen
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/tools/go/packages"
+)
+
+// BuildEnv describes the environment used to verify generated Go packages in a directory
+// with the go command. It honors the user's Go environment, including GOFLAGS, go.work
+// workspaces, and vendored dependencies, so verification behaves like a build in the same
+// directory would.
+type BuildEnv struct {
+	// Dir is the directory the go command runs in.
+	Dir string
+
+	// GOMOD is the path to the go.mod file of the module containing Dir.
+	GOMOD string
+
+	// GOWORK is the path to the go.work file, or empty if not in workspace mode.
+	GOWORK string
+
+	// GOFLAGS is the effective value of GOFLAGS for the go command.
+	GOFLAGS string
+
+	// Vendor is true if dependencies are loaded from a vendor directory.
+	Vendor bool
+
+	// Env is the environment for the go command.
+	Env []string
+}
+
+// LoadBuildEnv returns the [BuildEnv] for directory dir, as reported by the go command.
+// Dir must be within a Go module.
+//
+// If dir is within a go.work workspace that does not use the module containing dir,
+// such as a newly generated module, workspace mode is disabled with GOWORK=off.
+// In workspace mode, -mod=mod is removed from GOFLAGS, as the go command rejects it.
+func LoadBuildEnv(ctx context.Context, dir string) (*BuildEnv, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	env := &BuildEnv{
+		Dir: dir,
+		Env: os.Environ(),
+	}
+
+	cmd := exec.CommandContext(ctx, "go", "env", "-json", "GOMOD", "GOWORK", "GOFLAGS")
+	cmd.Dir = dir
+	cmd.Env = env.Env
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("go env: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	var vars struct {
+		GOMOD   string
+		GOWORK  string
+		GOFLAGS string
+	}
+	err = json.Unmarshal(out, &vars)
+	if err != nil {
+		return nil, err
+	}
+	if vars.GOMOD == "" || vars.GOMOD == os.DevNull {
+		return nil, fmt.Errorf("no go.mod file for directory %s", dir)
+	}
+	env.GOMOD = vars.GOMOD
+	env.GOFLAGS = vars.GOFLAGS
+
+	if vars.GOWORK != "" && vars.GOWORK != "off" {
+		used, err := workspaceUses(vars.GOWORK, filepath.Dir(vars.GOMOD))
+		if err != nil {
+			return nil, err
+		}
+		if used {
+			env.GOWORK = vars.GOWORK
+		} else {
+			env.setenv("GOWORK", "off")
+		}
+	}
+
+	mod := modFlag(env.GOFLAGS)
+	if env.GOWORK != "" && mod == "mod" {
+		env.GOFLAGS = removeModFlag(env.GOFLAGS)
+		env.setenv("GOFLAGS", env.GOFLAGS)
+		mod = ""
+	}
+
+	switch mod {
+	case "vendor":
+		env.Vendor = true
+	case "":
+		// The go command defaults to -mod=vendor if a vendor directory is present
+		// alongside the go.work file in workspace mode, or the go.mod file otherwise.
+		root := filepath.Dir(env.GOMOD)
+		if env.GOWORK != "" {
+			root = filepath.Dir(env.GOWORK)
+		}
+		_, err := os.Stat(filepath.Join(root, "vendor", "modules.txt"))
+		env.Vendor = err == nil
+	}
+
+	return env, nil
+}
+
+// Command returns an [exec.Cmd] that runs the go command with args in env.Dir with env.Env.
+func (env *BuildEnv) Command(ctx context.Context, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "go", args...)
+	cmd.Dir = env.Dir
+	cmd.Env = env.Env
+	return cmd
+}
+
+// PackagesConfig returns a [packages.Config] that loads packages with mode in env.
+// If tags is not empty, it is passed to the go command with -tags.
+func (env *BuildEnv) PackagesConfig(ctx context.Context, mode packages.LoadMode, tags string) *packages.Config {
+	cfg := &packages.Config{
+		Context: ctx,
+		Mode:    mode,
+		Dir:     env.Dir,
+		Env:     env.Env,
+	}
+	if tags != "" {
+		cfg.BuildFlags = []string{"-tags", tags}
+	}
+	return cfg
+}
+
+// VendoredModule returns true if module modpath is listed in the vendor/modules.txt file
+// used by env. It returns an error if env does not vendor dependencies.
+func (env *BuildEnv) VendoredModule(modpath string) (bool, error) {
+	if !env.Vendor {
+		return false, errors.New("dependencies are not vendored")
+	}
+	root := filepath.Dir(env.GOMOD)
+	if env.GOWORK != "" {
+		root = filepath.Dir(env.GOWORK)
+	}
+	b, err := os.ReadFile(filepath.Join(root, "vendor", "modules.txt"))
+	if err != nil {
+		return false, err
+	}
+	for _, line := range strings.Split(string(b), "\n") {
+		// Module lines are of the form: # path version
+		f := strings.Fields(line)
+		if len(f) >= 2 && f[0] == "#" && f[1] == modpath {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (env *BuildEnv) setenv(key, value string) {
+	env.Env = slices.DeleteFunc(env.Env, func(kv string) bool {
+		return strings.HasPrefix(kv, key+"=")
+	})
+	env.Env = append(env.Env, key+"="+value)
+}
+
+// workspaceUses returns true if the go.work file at path has a use directive for directory dir.
+func workspaceUses(path, dir string) (bool, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	f, err := modfile.ParseWork(path, b, nil)
+	if err != nil {
+		return false, err
+	}
+	for _, use := range f.Use {
+		p := use.Path
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(filepath.Dir(path), p)
+		}
+		if filepath.Clean(p) == filepath.Clean(dir) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// modFlag returns the value of the last -mod flag in goflags, or an empty string if none.
+func modFlag(goflags string) string {
+	var mod string
+	for _, f := range strings.Fields(goflags) {
+		if v, ok := strings.CutPrefix(strings.TrimLeft(f, "-"), "mod="); ok {
+			mod = v
+		}
+	}
+	return mod
+}
+
+// removeModFlag returns goflags without any -mod flags.
+func removeModFlag(goflags string) string {
+	return strings.Join(slices.DeleteFunc(strings.Fields(goflags), func(f string) bool {
+		return strings.HasPrefix(f, "-mod=") || strings.HasPrefix(f, "--mod=")
+	}), " ")
+}
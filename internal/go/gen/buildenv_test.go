@@ -0,0 +1,124 @@
+package gen
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		err := os.MkdirAll(filepath.Dir(path), 0o755)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(path, []byte(content), 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLoadBuildEnv(t *testing.T) {
+	if testing.Short() {
+		// t.Skip is not available in TinyGo, requires runtime.Goexit()
+		return
+	}
+
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"work/go.work":                  "go 1.22.0\n\nuse ./used\n",
+		"work/used/go.mod":              "module example.com/used\n\ngo 1.22.0\n",
+		"work/used/used.go":             "package used\n",
+		"work/gen/go.mod":               "module example.com/gen\n\ngo 1.22.0\n",
+		"work/gen/gen.go":               "package gen\n",
+		"vendored/go.mod":               "module example.com/vendored\n\ngo 1.22.0\n",
+		"vendored/vendor/modules.txt":   "",
+		"vendored/vendored.go":          "package vendored\n",
+		"workvendor/go.work":            "go 1.22.0\n\nuse ./used\n",
+		"workvendor/vendor/modules.txt": "## workspace\n",
+		"workvendor/used/go.mod":        "module example.com/used\n\ngo 1.22.0\n",
+		"workvendor/used/used.go":       "package used\n",
+		"plain/go.mod":                  "module example.com/plain\n\ngo 1.22.0\n",
+		"plain/plain.go":                "package plain\n",
+	})
+
+	tests := []struct {
+		dir       string
+		goflags   string
+		workspace bool
+		vendor    bool
+	}{
+		{"plain", "", false, false},
+		{"plain", "-mod=mod", false, false},
+		{"work/used", "", true, false},
+		{"work/used", "-mod=mod", true, false},
+		{"work/gen", "", false, false},
+		{"work/gen", "-mod=mod", false, false},
+		{"vendored", "", false, true},
+		{"vendored", "-mod=mod", false, false},
+		{"vendored", "-mod=vendor", false, true},
+		{"workvendor/used", "", true, true},
+		{"workvendor/used", "-mod=mod", true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dir+"#"+tt.goflags, func(t *testing.T) {
+			t.Setenv("GOFLAGS", tt.goflags)
+			t.Setenv("GOWORK", "")
+			t.Setenv("GOTOOLCHAIN", "local")
+
+			ctx := context.Background()
+			env, err := LoadBuildEnv(ctx, filepath.Join(root, tt.dir))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := env.GOWORK != "", tt.workspace; got != want {
+				t.Errorf("workspace: %t, expected %t (GOWORK=%q)", got, want, env.GOWORK)
+			}
+			if got, want := env.Vendor, tt.vendor; got != want {
+				t.Errorf("Vendor: %t, expected %t", got, want)
+			}
+
+			out, err := env.Command(ctx, "list", "./...").CombinedOutput()
+			if err != nil {
+				t.Errorf("go list: %v: %s", err, out)
+			}
+		})
+	}
+}
+
+func TestLoadBuildEnvNoModule(t *testing.T) {
+	if testing.Short() {
+		// t.Skip is not available in TinyGo, requires runtime.Goexit()
+		return
+	}
+	t.Setenv("GOWORK", "")
+	_, err := LoadBuildEnv(context.Background(), t.TempDir())
+	if err == nil {
+		t.Error("LoadBuildEnv: expected error for directory outside a module")
+	}
+}
+
+func TestModFlag(t *testing.T) {
+	tests := []struct {
+		goflags string
+		mod     string
+		removed string
+	}{
+		{"", "", ""},
+		{"-mod=mod", "mod", ""},
+		{"-trimpath --mod=vendor", "vendor", "-trimpath"},
+		{"-mod=mod -mod=readonly -v", "readonly", "-v"},
+	}
+	for _, tt := range tests {
+		if got, want := modFlag(tt.goflags), tt.mod; got != want {
+			t.Errorf("modFlag(%q): %q, expected %q", tt.goflags, got, want)
+		}
+		if got, want := removeModFlag(tt.goflags), tt.removed; got != want {
+			t.Errorf("removeModFlag(%q): %q, expected %q", tt.goflags, got, want)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package gen
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+)
+
+// CacheFile is the name of the file in the root directory of a [Writer]
+// that records the content hashes of the files it wrote. See [Cache].
+const CacheFile = ".wit-bindgen-cache.json"
+
+// cacheVersion is the version of the [Cache] format. Caches with other versions are ignored.
+const cacheVersion = 1
+
+// Cache records the content hashes of files written by a [Writer], grouped by package directory,
+// so regenerating a large tree of packages rewrites only files whose content changed,
+// and does not invalidate build caches for the others.
+//
+// A file is unchanged if its content hash matches the cache, and its size and
+// modification time on disk match those recorded when it was written,
+// so a file modified or removed since is rewritten.
+type Cache struct {
+	Version int `json:"version"`
+
+	// Packages maps a slash-separated package directory relative to the output directory,
+	// or "." for the output directory itself, to the hashes of its files.
+	Packages map[string]*CachePackage `json:"packages"`
+
+	seen map[string]bool // slash-separated paths of files written or skipped
+}
+
+// CachePackage records the content hashes of the files in a package directory.
+type CachePackage struct {
+	// Hash is the SHA-256 hash of the names and hashes of the files in the package, in hex.
+	// It changes if any file in the package changes.
+	Hash string `json:"hash"`
+
+	// Files maps file names to their hashes.
+	Files map[string]*CacheEntry `json:"files"`
+}
+
+// CacheEntry records the content hash of a written file.
+type CacheEntry struct {
+	Hash    string `json:"hash"`  // SHA-256 hash of the content, in hex
+	Size    int64  `json:"size"`  // size of the file in bytes
+	ModTime int64  `json:"mtime"` // modification time of the file, in nanoseconds since the Unix epoch
+}
+
+// LoadCache loads the [Cache] in w.Root, if any, enabling [Writer.Update] to skip unchanged files.
+// A missing, malformed, or outdated cache file is ignored, so every file will be written.
+func (w *Writer) LoadCache() error {
+	w.cache = &Cache{Version: cacheVersion, Packages: make(map[string]*CachePackage)}
+	b, err := os.ReadFile(filepath.Join(w.Root, CacheFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var c Cache
+	if json.Unmarshal(b, &c) != nil || c.Version != cacheVersion || c.Packages == nil {
+		return nil
+	}
+	w.cache.Packages = c.Packages
+	return nil
+}
+
+// SaveCache writes the cache loaded by [Writer.LoadCache] to w.Root, recording the files
+// written or skipped by [Writer.Update] since. Files that were not are forgotten.
+func (w *Writer) SaveCache() error {
+	if w.cache == nil {
+		return errors.New("gen: cache not loaded")
+	}
+	c := &Cache{Version: cacheVersion, Packages: make(map[string]*CachePackage)}
+	for dir, pkg := range w.cache.Packages {
+		for name, e := range pkg.Files {
+			if !w.cache.seen[path.Join(dir, name)] {
+				continue
+			}
+			if c.Packages[dir] == nil {
+				c.Packages[dir] = &CachePackage{Files: make(map[string]*CacheEntry)}
+			}
+			c.Packages[dir].Files[name] = e
+		}
+	}
+	for _, pkg := range c.Packages {
+		pkg.Hash = packageHash(pkg.Files)
+	}
+	b, err := json.MarshalIndent(c, "", "\t")
+	if err != nil {
+		return err
+	}
+	return w.write(filepath.Join(w.Root, CacheFile), append(b, '\n'))
+}
+
+// Update writes content to the file at slash-separated path rel, relative to w.Root, like
+// [Writer.WritePath], unless the cache loaded by [Writer.LoadCache] shows the file is unchanged.
+// It returns the path of the file, and whether it was written.
+// If no cache was loaded, Update always writes the file.
+func (w *Writer) Update(rel string, content []byte) (path string, written bool, err error) {
+	if w.cache == nil {
+		path, err = w.WritePath(rel, content)
+		return path, err == nil, err
+	}
+	dir, name := splitRel(rel)
+	hash := contentHash(content)
+	w.cache.see(rel)
+
+	path = filepath.Join(w.Root, filepath.FromSlash(rel))
+	pkg := w.cache.Packages[dir]
+	if pkg != nil {
+		if e := pkg.Files[name]; e != nil && e.Hash == hash {
+			if info, err := os.Lstat(path); err == nil && info.Mode().IsRegular() &&
+				info.Size() == e.Size && info.ModTime().UnixNano() == e.ModTime {
+				return path, false, nil
+			}
+		}
+	}
+
+	path, err = w.WritePath(rel, content)
+	if err != nil {
+		return path, false, err
+	}
+	info, err := os.Lstat(path)
+	if err != nil {
+		return path, true, err
+	}
+	if pkg == nil {
+		pkg = &CachePackage{Files: make(map[string]*CacheEntry)}
+		w.cache.Packages[dir] = pkg
+	}
+	pkg.Files[name] = &CacheEntry{Hash: hash, Size: info.Size(), ModTime: info.ModTime().UnixNano()}
+	return path, true, nil
+}
+
+func (c *Cache) see(rel string) {
+	if c.seen == nil {
+		c.seen = make(map[string]bool)
+	}
+	c.seen[rel] = true
+}
+
+// splitRel splits slash-separated path rel into its package directory and file name.
+func splitRel(rel string) (dir, name string) {
+	dir, name = path.Split(rel)
+	if dir == "" {
+		return ".", name
+	}
+	return dir[:len(dir)-1], name
+}
+
+// contentHash returns the hex SHA-256 hash of content.
+func contentHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// packageHash returns the hex SHA-256 hash of the sorted names and hashes of files.
+func packageHash(files map[string]*CacheEntry) string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	h := sha256.New()
+	for _, name := range names {
+		h.Write([]byte(name))
+		h.Write([]byte{0})
+		h.Write([]byte(files[name].Hash))
+		h.Write([]byte{'\n'})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
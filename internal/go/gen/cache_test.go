@@ -0,0 +1,115 @@
+package gen
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWriterUpdate(t *testing.T) {
+	root := t.TempDir()
+	newWriter := func() *Writer {
+		w := &Writer{Root: root, Perm: 0o755}
+		if err := w.LoadCache(); err != nil {
+			t.Fatal(err)
+		}
+		return w
+	}
+	update := func(w *Writer, rel, content string, want bool) {
+		t.Helper()
+		_, written, err := w.Update(rel, []byte(content))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if written != want {
+			t.Errorf("Update(%q): written = %t, expected %t", rel, written, want)
+		}
+	}
+
+	w := newWriter()
+	update(w, "go.mod", "module example.com/bindings\n", true)
+	update(w, "foo/foo.go", "package foo\n", true)
+	update(w, "foo/bar/bar.go", "package bar\n", true)
+	if err := w.SaveCache(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Unchanged files are not written. Files not updated are forgotten.
+	w = newWriter()
+	update(w, "go.mod", "module example.com/bindings\n", false)
+	update(w, "foo/foo.go", "package foo // changed\n", true)
+	if err := w.SaveCache(); err != nil {
+		t.Fatal(err)
+	}
+	c := readCache(t, root)
+	if len(c.Packages) != 2 || c.Packages["."] == nil || c.Packages["foo"] == nil {
+		t.Errorf("SaveCache(): packages %v, expected . and foo", c.Packages)
+	}
+	for dir, pkg := range c.Packages {
+		if pkg.Hash != packageHash(pkg.Files) || pkg.Hash == "" {
+			t.Errorf("SaveCache(): package %s has hash %q, expected %q", dir, pkg.Hash, packageHash(pkg.Files))
+		}
+	}
+
+	// Files modified or removed since they were written are rewritten.
+	path := filepath.Join(root, "foo", "foo.go")
+	if err := os.WriteFile(path, []byte("package foo // edited\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, time.Time{}, time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(root, "go.mod")); err != nil {
+		t.Fatal(err)
+	}
+	w = newWriter()
+	update(w, "foo/foo.go", "package foo // changed\n", true)
+	update(w, "go.mod", "module example.com/bindings\n", true)
+	if b, _ := os.ReadFile(path); string(b) != "package foo // changed\n" {
+		t.Errorf("Update(): file content %q, expected regenerated content", b)
+	}
+
+	// A malformed cache is ignored.
+	if err := os.WriteFile(filepath.Join(root, CacheFile), []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	w = newWriter()
+	update(w, "foo/foo.go", "package foo // changed\n", true)
+
+	// Without a cache, files are always written.
+	w = &Writer{Root: root, Perm: 0o755}
+	update(w, "foo/foo.go", "package foo // changed\n", true)
+	if err := w.SaveCache(); err == nil {
+		t.Error("SaveCache(): expected error without LoadCache")
+	}
+}
+
+func TestSplitRel(t *testing.T) {
+	tests := []struct {
+		rel, dir, name string
+	}{
+		{"go.mod", ".", "go.mod"},
+		{"foo/foo.go", "foo", "foo.go"},
+		{"wasi/cli/run/run.wit.go", "wasi/cli/run", "run.wit.go"},
+	}
+	for _, tt := range tests {
+		if dir, name := splitRel(tt.rel); dir != tt.dir || name != tt.name {
+			t.Errorf("splitRel(%q): %q, %q, expected %q, %q", tt.rel, dir, name, tt.dir, tt.name)
+		}
+	}
+}
+
+func readCache(t *testing.T, root string) *Cache {
+	t.Helper()
+	b, err := os.ReadFile(filepath.Join(root, CacheFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var c Cache
+	if err := json.Unmarshal(b, &c); err != nil {
+		t.Fatal(err)
+	}
+	return &c
+}
@@ -19,6 +19,10 @@ type File struct {
 	// Leave empty to omit the "Code generated by ..." header.
 	GeneratedBy string
 
+	// Scaffold is true if this file is a starting point that is meant to be edited,
+	// and should not be overwritten if it exists.
+	Scaffold bool
+
 	// GoBuild contains build tags, serialized as //go:build ...
 	// Ignored if this is not a Go file.
 	GoBuild string
@@ -45,6 +49,27 @@ type File struct {
 
 	// Trailer is the file trailer, written after content.
 	Trailer string
+
+	// decls records the names and offsets in Content of declarations
+	// written with BeginDecl and EndDecl or WriteDecl.
+	decls []declSpan
+
+	// formatted caches the result of formatting this file.
+	formatted *formatResult
+}
+
+// declSpan is the name and byte offsets in [File] Content of a declaration.
+// End is -1 until the declaration is ended.
+type declSpan struct {
+	name       string
+	start, end int
+}
+
+// formatResult is the result of formatting the source of a Go file.
+type formatResult struct {
+	src []byte
+	out []byte
+	err error
 }
 
 // NewFile returns a newly initialized file.
@@ -91,14 +116,76 @@ func (f *File) WriteString(s string) (int, error) {
 	return len(s), nil
 }
 
+// BeginDecl records the start of a package-scoped declaration named name,
+// such as "Descriptor" or "Descriptor.Read", at the end of the file content.
+// Content written until [File.EndDecl] is called is part of the declaration.
+// If the file cannot be formatted, the [FormatError] names the declaration that contains the error.
+func (f *File) BeginDecl(name string) {
+	f.EndDecl()
+	f.decls = append(f.decls, declSpan{name: name, start: len(f.Content), end: -1})
+}
+
+// EndDecl records the end of the declaration started by [File.BeginDecl], if any.
+func (f *File) EndDecl() {
+	if n := len(f.decls); n > 0 && f.decls[n-1].end < 0 {
+		f.decls[n-1].end = len(f.Content)
+	}
+}
+
+// WriteDecl writes s, the source of the package-scoped declaration named name,
+// to the file content, recording its start and end offsets. See [File.BeginDecl].
+func (f *File) WriteDecl(name, s string) {
+	f.BeginDecl(name)
+	f.WriteString(s)
+	f.EndDecl()
+}
+
+// declAt returns the name of the declaration that contains offset in the file content, if any.
+func (f *File) declAt(offset int) string {
+	for _, d := range f.decls {
+		end := d.end
+		if end < 0 {
+			end = len(f.Content)
+		}
+		if offset >= d.start && offset < end {
+			return d.name
+		}
+	}
+	return ""
+}
+
 const HeaderPattern = `// Code generated by %s. DO NOT EDIT.`
 
 // Bytes returns the byte values of this file.
+// Go files are formatted with [format.Source]. The formatted source is cached
+// until the file changes, so Bytes may be called repeatedly or after [Format].
+// If the file cannot be formatted, Bytes returns the unformatted source and a [*FormatError].
+// The returned slice must not be modified.
 func (f *File) Bytes() ([]byte, error) {
 	if !f.IsGo() {
 		return f.Content, nil
 	}
 
+	unformatted, content := f.unformatted()
+	if c := f.formatted; c != nil && bytes.Equal(c.src, unformatted) {
+		return c.out, c.err
+	}
+	c := &formatResult{src: unformatted}
+	c.out, c.err = format.Source(unformatted)
+	if c.err != nil {
+		e := newFormatError(f.Name, unformatted, c.err)
+		if e.Offset >= 0 {
+			e.Decl = f.declAt(e.Offset - content)
+		}
+		c.out, c.err = unformatted, e
+	}
+	f.formatted = c
+	return c.out, c.err
+}
+
+// unformatted returns the unformatted source of Go file f,
+// and the offset of the file content in the source.
+func (f *File) unformatted() ([]byte, int) {
 	var b bytes.Buffer
 
 	if f.GeneratedBy != "" {
@@ -125,15 +212,11 @@ func (f *File) Bytes() ([]byte, error) {
 	}
 
 	b.Write([]byte(f.Header))
+	content := b.Len()
 	b.Write(f.Content)
 	b.Write([]byte(f.Trailer))
 
-	unformatted := b.Bytes()
-	formatted, err := format.Source(unformatted)
-	if err != nil {
-		return unformatted, fmt.Errorf("error in %s: %w", f.Name, err)
-	}
-	return formatted, nil
+	return b.Bytes(), content
 }
 
 // DeclareName adds a package-scoped identifier to [File] f.
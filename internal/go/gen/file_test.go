@@ -1,6 +1,10 @@
 package gen
 
-import "testing"
+import (
+	"errors"
+	"strings"
+	"testing"
+)
 
 func TestFileHasContent(t *testing.T) {
 	positives := []File{
@@ -50,6 +54,138 @@ func TestFileBytes(t *testing.T) {
 	}
 }
 
+func TestFormat(t *testing.T) {
+	var pkgs []*Package
+	for _, path := range []string{"example.com/a", "example.com/b"} {
+		pkg := NewPackage(path)
+		for _, name := range []string{"x.go", "y.go", "z.s"} {
+			f := pkg.File(name)
+			f.WriteString("var  v =  1\n")
+		}
+		pkgs = append(pkgs, pkg)
+	}
+	Format(pkgs)
+	for _, pkg := range pkgs {
+		for _, f := range pkg.Files {
+			if !f.IsGo() {
+				if f.formatted != nil {
+					t.Errorf("%s/%s: non-Go file was formatted", pkg.Path, f.Name)
+				}
+				continue
+			}
+			if f.formatted == nil {
+				t.Errorf("%s/%s: not formatted", pkg.Path, f.Name)
+				continue
+			}
+			b, err := f.Bytes()
+			if err != nil {
+				t.Error(err)
+			}
+			if got, want := string(b), "package "+pkg.Name+"\n\nvar v = 1\n"; got != want {
+				t.Errorf("%s/%s: Bytes(): %q, expected %q", pkg.Path, f.Name, got, want)
+			}
+		}
+	}
+
+	// Changes to the file invalidate the cached result.
+	f := pkgs[0].Files["x.go"]
+	f.WriteString("func {\n")
+	b, err := f.Bytes()
+	if err == nil {
+		t.Errorf("Bytes(): expected error, got %q", b)
+	}
+}
+
+func TestFileBytesFormatError(t *testing.T) {
+	pkg := NewPackage("example.com/foo")
+	f := pkg.File("foo.go")
+	f.WriteDecl("A", "func A() {}\n\n")
+	f.WriteDecl("B", "// B is broken.\nfunc B( {\n\treturn\n}\n")
+	b, err := f.Bytes()
+	var e *FormatError
+	if !errors.As(err, &e) {
+		t.Fatalf("Bytes(): %v, expected *FormatError", err)
+	}
+	if !strings.HasPrefix(string(b), "package foo\n") {
+		t.Errorf("Bytes(): %q, expected unformatted source", b)
+	}
+	if got, want := e.Line, 6; got != want {
+		t.Errorf("Line: %d, expected %d", got, want)
+	}
+	if got, want := e.Decl, "B"; got != want {
+		t.Errorf("Decl: %q, expected %q", got, want)
+	}
+	if got, want := e.Context(1), "  5 | // B is broken.\n> 6 | func B( {\n  7 | \treturn"; got != want {
+		t.Errorf("Context(1):\n%s\nexpected:\n%s", got, want)
+	}
+	for _, want := range []string{"error in foo.go: 6:", "in declaration: B", "> 6 | func B( {"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Error(): %q does not contain %q", err.Error(), want)
+		}
+	}
+}
+
+func TestFileBytesFormatErrorDecl(t *testing.T) {
+	tests := []struct {
+		name  string
+		write func(f *File)
+		want  string
+	}{
+		{
+			"written incrementally",
+			func(f *File) {
+				f.BeginDecl("T.M")
+				f.WriteString("func (T) M() {\n")
+				f.WriteString("\treturn )\n")
+				f.WriteString("}\n\n")
+				f.EndDecl()
+				f.WriteDecl("U", "type U int\n")
+			},
+			"T.M",
+		},
+		{
+			"outside declarations",
+			func(f *File) {
+				f.WriteDecl("U", "type U int\n")
+				f.WriteString("func V( {}\n")
+			},
+			"",
+		},
+		{
+			"unterminated at end of file",
+			func(f *File) {
+				f.WriteDecl("U", "type U int\n")
+				f.WriteDecl("W", "func W() {\n")
+			},
+			"W",
+		},
+		{
+			"not ended",
+			func(f *File) {
+				f.BeginDecl("X")
+				f.WriteString("var X = (\n")
+			},
+			"X",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pkg := NewPackage("example.com/foo")
+			f := pkg.File("foo.go")
+			f.Header = "// Header\n"
+			tt.write(f)
+			_, err := f.Bytes()
+			var e *FormatError
+			if !errors.As(err, &e) {
+				t.Fatalf("Bytes(): %v, expected *FormatError", err)
+			}
+			if e.Decl != tt.want {
+				t.Errorf("Decl: %q, expected %q", e.Decl, tt.want)
+			}
+		})
+	}
+}
+
 func TestFileAddImport(t *testing.T) {
 	pkg := NewPackage("wasm/wasi/clocks/wallclock")
 	f := pkg.File("wallclock.wit.go")
@@ -1,6 +1,14 @@
 package gen
 
-import "strings"
+import (
+	"errors"
+	"fmt"
+	"go/scanner"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
 
 const (
 	DocCommentPrefix = "//"
@@ -54,3 +62,109 @@ func FormatDocComments(docs string, indent bool) string {
 	}
 	return b.String()
 }
+
+// Format formats the Go files in pkgs in parallel, caching the formatted
+// source of each [File] for later calls to [File.Bytes]. Formatting is the
+// most expensive step of rendering a file, and files are formatted independently,
+// so the result is the same as calling Bytes on each file in order.
+// Errors are not returned, but are reported by Bytes.
+// Files must not be modified concurrently with Format.
+func Format(pkgs []*Package) {
+	files := make(chan *File)
+	var wg sync.WaitGroup
+	for range runtime.GOMAXPROCS(0) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for f := range files {
+				_, _ = f.Bytes()
+			}
+		}()
+	}
+	for _, pkg := range pkgs {
+		for _, f := range pkg.Files {
+			if f.IsGo() && f.HasContent() {
+				files <- f
+			}
+		}
+	}
+	close(files)
+	wg.Wait()
+}
+
+// FormatError is returned by [File.Bytes] if a generated Go file cannot be formatted,
+// usually because of a bug in the generator. It describes the position of the first
+// syntax error and the declaration that contains it.
+type FormatError struct {
+	// Name is the name of the file.
+	Name string
+
+	// Line and Column are the position of the first syntax error in Source,
+	// starting at 1, or 0 if unknown.
+	Line, Column int
+
+	// Offset is the byte offset of the first syntax error in Source, or -1 if unknown.
+	Offset int
+
+	// Decl is the name of the declaration that contains the error, if it was
+	// written with [File.BeginDecl] or [File.WriteDecl].
+	Decl string
+
+	// Source is the unformatted source of the file.
+	Source []byte
+
+	// Err is the error returned by [format.Source].
+	Err error
+}
+
+// newFormatError returns a [FormatError] for err, returned from formatting src in file name.
+func newFormatError(name string, src []byte, err error) *FormatError {
+	e := &FormatError{Name: name, Offset: -1, Source: src, Err: err}
+	var list scanner.ErrorList
+	if errors.As(err, &list) && len(list) > 0 {
+		e.Line, e.Column = list[0].Pos.Line, list[0].Pos.Column
+		// Errors at the end of the file are reported at len(src).
+		e.Offset = min(list[0].Pos.Offset, len(src)-1)
+	}
+	return e
+}
+
+// Error returns the error message, the offending declaration, and the surrounding source.
+func (e *FormatError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "error in %s: %v", e.Name, e.Err)
+	if e.Decl != "" {
+		fmt.Fprintf(&b, "\nin declaration: %s", e.Decl)
+	}
+	if context := e.Context(3); context != "" {
+		b.WriteString("\n")
+		b.WriteString(context)
+	}
+	return b.String()
+}
+
+// Unwrap returns the underlying error.
+func (e *FormatError) Unwrap() error {
+	return e.Err
+}
+
+// Context returns up to n lines of source before and after the line with the error,
+// prefixed with line numbers. The line with the error is marked with >.
+// It returns an empty string if the position of the error is unknown.
+func (e *FormatError) Context(n int) string {
+	lines := strings.Split(string(e.Source), "\n")
+	if e.Line < 1 || e.Line > len(lines) {
+		return ""
+	}
+	first, last := max(e.Line-n, 1), min(e.Line+n, len(lines))
+	width := len(strconv.Itoa(last))
+	var b strings.Builder
+	for i := first; i <= last; i++ {
+		mark := ' '
+		if i == e.Line {
+			mark = '>'
+		}
+		fmt.Fprintf(&b, "%c %*d | %s\n", mark, width, i, lines[i-1])
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
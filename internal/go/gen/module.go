@@ -8,6 +8,7 @@ import (
 	"path"
 	"path/filepath"
 
+	"github.com/bytecodealliance/wasm-tools-go/internal/codec"
 	"github.com/bytecodealliance/wasm-tools-go/internal/relpath"
 	"golang.org/x/mod/modfile"
 )
@@ -72,3 +73,25 @@ func PackagePath(dir string) (string, error) {
 	}
 	return path.Join(modpath, subdirs), nil
 }
+
+// ModFile returns the contents of a go.mod file for module path modpath with Go version goVersion.
+// The requires argument maps module paths to versions, and may be nil.
+func ModFile(modpath, goVersion string, requires map[string]string) ([]byte, error) {
+	f := &modfile.File{}
+	err := f.AddModuleStmt(modpath)
+	if err != nil {
+		return nil, err
+	}
+	err = f.AddGoStmt(goVersion)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range codec.SortedKeys(requires) {
+		err = f.AddRequire(path, requires[path])
+		if err != nil {
+			return nil, err
+		}
+	}
+	f.Cleanup()
+	return f.Format()
+}
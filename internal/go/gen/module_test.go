@@ -1,6 +1,7 @@
 package gen
 
 import (
+	"bytes"
 	"os"
 	"testing"
 
@@ -28,3 +29,21 @@ func TestPackagePath(t *testing.T) {
 		t.Errorf("PackagePath(%q): expected error, got nil", tmp)
 	}
 }
+
+func TestModFile(t *testing.T) {
+	got, err := ModFile("example.com/bindings", "1.22.0", map[string]string{
+		"github.com/bytecodealliance/wasm-tools-go": "v0.3.0",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []byte("module example.com/bindings\n\ngo 1.22.0\n\nrequire github.com/bytecodealliance/wasm-tools-go v0.3.0\n")
+	if !bytes.Equal(got, want) {
+		t.Errorf("ModFile: got %q, expected %q", got, want)
+	}
+
+	_, err = ModFile("example.com/bindings", "not a version", nil)
+	if err == nil {
+		t.Errorf("ModFile: expected error for invalid Go version, got nil")
+	}
+}
@@ -15,14 +15,20 @@ type Package struct {
 	// Declared tracks declared package-scoped identifiers,
 	// including constants, variables, and functions.
 	Scope
+
+	// Origins optionally maps the names of package-scoped declarations to the source they were
+	// generated from, e.g. a WIT type or function, for reporting errors in generated code.
+	// Methods are indexed as "Type.Method". The empty name describes the package itself.
+	Origins map[string]string
 }
 
 // NewPackage returns a newly instantiated Package for path.
 // The local name may optionally be specified with a "#name" suffix.
 func NewPackage(path string) *Package {
 	p := &Package{
-		Files: make(map[string]*File),
-		Scope: NewScope(nil),
+		Files:   make(map[string]*File),
+		Scope:   NewScope(nil),
+		Origins: make(map[string]string),
 	}
 	p.Path, p.Name = ParseSelector(path)
 	return p
@@ -0,0 +1,211 @@
+package gen
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Writer writes generated files to a directory on the local filesystem.
+// It refuses to write files outside of its root directory, guarding against
+// package paths or file names derived from untrusted input, such as WIT names
+// with path separators or parent directory (..) elements.
+type Writer struct {
+	// Root is the output directory.
+	Root string
+
+	// PackageRoot is the Go package path that corresponds to Root.
+	// Packages with paths outside of PackageRoot are written relative to Root.
+	PackageRoot string
+
+	// Perm is the permission used to create directories and files.
+	Perm os.FileMode
+
+	cache *Cache // loaded by LoadCache
+}
+
+// Path returns the local filesystem path for [File] f, or an error if the path
+// would be outside of w.Root.
+func (w *Writer) Path(f *File) (string, error) {
+	rel, err := w.Rel(f)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(w.Root, filepath.FromSlash(rel)), nil
+}
+
+// Rel returns the slash-separated path of [File] f relative to w.Root,
+// or an error if the path would be outside of w.Root.
+func (w *Writer) Rel(f *File) (string, error) {
+	rel := f.Package.Path
+	if rel == w.PackageRoot {
+		rel = ""
+	} else if w.PackageRoot != "" && strings.HasPrefix(rel, w.PackageRoot+"/") {
+		rel = rel[len(w.PackageRoot)+1:]
+	}
+	if rel != "" {
+		for _, elem := range strings.Split(rel, "/") {
+			if !isLocalElem(elem) {
+				return "", fmt.Errorf("invalid path element %q in package %s", elem, f.Package.Path)
+			}
+		}
+	}
+	if !isLocalElem(f.Name) {
+		return "", fmt.Errorf("invalid file name %q in package %s", f.Name, f.Package.Path)
+	}
+	if rel == "" {
+		return f.Name, nil
+	}
+	return rel + "/" + f.Name, nil
+}
+
+// WriteFile writes content for [File] f, creating any necessary directories.
+// It returns the path of the written file.
+// It returns an error if the file would be written outside of w.Root,
+// including via a symbolic link.
+func (w *Writer) WriteFile(f *File, content []byte) (string, error) {
+	path, err := w.Path(f)
+	if err != nil {
+		return "", err
+	}
+	return path, w.write(path, content)
+}
+
+// WritePath writes content to the file at slash-separated path rel, relative to w.Root,
+// such as a path returned by [Writer.Rel], creating any necessary directories.
+// It returns the path of the written file.
+// It returns an error if the file would be written outside of w.Root,
+// including via a symbolic link.
+func (w *Writer) WritePath(rel string, content []byte) (string, error) {
+	for _, elem := range strings.Split(rel, "/") {
+		if !isLocalElem(elem) {
+			return "", fmt.Errorf("invalid path element %q in %s", elem, rel)
+		}
+	}
+	path := filepath.Join(w.Root, filepath.FromSlash(rel))
+	return path, w.write(path, content)
+}
+
+// write writes content to the file at path under w.Root.
+func (w *Writer) write(path string, content []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, w.Perm); err != nil {
+		return err
+	}
+
+	// Verify the directory does not resolve outside of root via symbolic links.
+	root, err := filepath.EvalSymlinks(w.Root)
+	if err != nil {
+		return err
+	}
+	realDir, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return err
+	}
+	if rel, err := filepath.Rel(root, realDir); err != nil || !filepath.IsLocal(rel) && rel != "." {
+		return fmt.Errorf("directory %s is outside of %s", dir, w.Root)
+	}
+	if info, err := os.Lstat(path); err == nil && info.Mode()&os.ModeSymlink != 0 {
+		return fmt.Errorf("refusing to write through symbolic link %s", path)
+	}
+
+	return os.WriteFile(path, content, w.Perm)
+}
+
+// isLocalElem reports whether elem is a single, non-empty path element
+// that does not refer to the current or parent directory.
+func isLocalElem(elem string) bool {
+	if elem == "" || elem == "." || elem == ".." {
+		return false
+	}
+	if strings.ContainsAny(elem, `/\`) || strings.ContainsRune(elem, 0) {
+		return false
+	}
+	return filepath.IsLocal(elem)
+}
+
+// Stale returns the paths of Go files under w.Root that were generated by generatedBy,
+// identified by their "Code generated by" header, and are not in written.
+// It does not descend into hidden, testdata, or vendor directories,
+// or into nested Go modules, and does not follow symbolic links.
+func (w *Writer) Stale(generatedBy string, written []string) ([]string, error) {
+	keep := make(map[string]bool, len(written))
+	for _, path := range written {
+		keep[filepath.Clean(path)] = true
+	}
+	var stale []string
+	err := filepath.WalkDir(w.Root, func(path string, d fs.DirEntry, err error) error {
+		if path == w.Root && errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path == w.Root {
+				return nil
+			}
+			name := d.Name()
+			if strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "testdata" || name == "vendor" {
+				return fs.SkipDir
+			}
+			if _, err := os.Lstat(filepath.Join(path, "go.mod")); err == nil {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || filepath.Ext(path) != ".go" || keep[filepath.Clean(path)] {
+			return nil
+		}
+		ok, err := IsGenerated(path, generatedBy)
+		if err != nil {
+			return err
+		}
+		if ok {
+			stale = append(stale, path)
+		}
+		return nil
+	})
+	return stale, err
+}
+
+// Remove removes the file at path, and any parent directories under w.Root left empty.
+func (w *Writer) Remove(path string) error {
+	err := os.Remove(path)
+	if err != nil {
+		return err
+	}
+	root := filepath.Clean(w.Root)
+	for dir := filepath.Dir(path); dir != root; dir = filepath.Dir(dir) {
+		rel, err := filepath.Rel(root, dir)
+		if err != nil || !filepath.IsLocal(rel) {
+			break
+		}
+		// os.Remove fails on non-empty directories.
+		if os.Remove(dir) != nil {
+			break
+		}
+	}
+	return nil
+}
+
+// IsGenerated reports whether the file at path starts with the "Code generated by" header
+// for generatedBy. See [HeaderPattern].
+func IsGenerated(path, generatedBy string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+	header := fmt.Sprintf(HeaderPattern, generatedBy)
+	line, err := bufio.NewReader(io.LimitReader(f, int64(len(header))+2)).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, err
+	}
+	return strings.TrimRight(line, "\r\n") == header, nil
+}
@@ -0,0 +1,177 @@
+package gen
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestWriterPath(t *testing.T) {
+	root := t.TempDir()
+	w := &Writer{Root: root, PackageRoot: "example.com/bindings", Perm: 0o755}
+
+	tests := []struct {
+		pkg     string
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{"example.com/bindings", "go.mod", "go.mod", false},
+		{"example.com/bindings/wasi/cli/run", "run.wit.go", "wasi/cli/run/run.wit.go", false},
+		{"wasi/cli/run", "run.wit.go", "wasi/cli/run/run.wit.go", false},
+		{"example.com/bindingsextra/foo", "foo.wit.go", "example.com/bindingsextra/foo/foo.wit.go", false},
+		{"example.com/bindings/../../etc", "passwd.go", "", true},
+		{"example.com/bindings/..", "evil.go", "", true},
+		{"example.com/bindings/a/./b", "b.go", "", true},
+		{"example.com/bindings//abs", "abs.go", "", true},
+		{`example.com/bindings/a\..\..\b`, "b.go", "", true},
+		{"example.com/bindings/ok", "../evil.go", "", true},
+		{"example.com/bindings/ok", "..", "", true},
+		{"example.com/bindings/ok", "", "", true},
+		{"example.com/bindings/ok", "a\x00.go", "", true},
+		{"/etc", "passwd.go", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pkg+"#"+tt.name, func(t *testing.T) {
+			f := NewPackage(tt.pkg).File(tt.name)
+			got, err := w.Path(f)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Path(): %q, expected error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Path(): %v", err)
+			}
+			if want := filepath.Join(root, filepath.FromSlash(tt.want)); got != want {
+				t.Errorf("Path(): %q, expected %q", got, want)
+			}
+		})
+	}
+}
+
+func TestWriterWriteFile(t *testing.T) {
+	if testing.Short() {
+		// t.Skip is not available in TinyGo, requires runtime.Goexit()
+		return
+	}
+	root := t.TempDir()
+	outside := t.TempDir()
+	w := &Writer{Root: root, PackageRoot: "example.com/bindings", Perm: 0o755}
+
+	f := NewPackage("example.com/bindings/foo").File("foo.go")
+	path, err := w.WriteFile(f, []byte("package foo\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := path, filepath.Join(root, "foo", "foo.go"); got != want {
+		t.Errorf("WriteFile(): %q, expected %q", got, want)
+	}
+
+	// A symbolic link inside root must not be followed outside of root.
+	err = os.Symlink(outside, filepath.Join(root, "link"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f = NewPackage("example.com/bindings/link").File("evil.go")
+	_, err = w.WriteFile(f, []byte("package link\n"))
+	if err == nil {
+		t.Errorf("WriteFile(): expected error writing through symbolic link")
+	}
+	if _, err := os.Stat(filepath.Join(outside, "evil.go")); err == nil {
+		t.Errorf("WriteFile(): wrote file outside of root")
+	}
+}
+
+func TestWriterWritePath(t *testing.T) {
+	root := t.TempDir()
+	w := &Writer{Root: root, PackageRoot: "example.com/bindings", Perm: 0o755}
+
+	f := NewPackage("example.com/bindings/foo").File("foo.go")
+	rel, err := w.Rel(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := rel, "foo/foo.go"; got != want {
+		t.Errorf("Rel(): %q, expected %q", got, want)
+	}
+	path, err := w.WritePath(rel, []byte("package foo\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := path, filepath.Join(root, "foo", "foo.go"); got != want {
+		t.Errorf("WritePath(): %q, expected %q", got, want)
+	}
+
+	for _, rel := range []string{"", "../evil.go", "foo/../../evil.go", "/evil.go", "foo//evil.go", "./evil.go"} {
+		_, err := w.WritePath(rel, []byte("package evil\n"))
+		if err == nil {
+			t.Errorf("WritePath(%q): expected error", rel)
+		}
+	}
+}
+
+func TestWriterStale(t *testing.T) {
+	root := t.TempDir()
+	w := &Writer{Root: root, PackageRoot: "example.com/bindings", Perm: 0o755}
+
+	files := map[string]string{
+		"foo/foo.wit.go":         "// Code generated by wit-bindgen-go. DO NOT EDIT.\n\npackage foo\n",
+		"bar/bar.wit.go":         "// Code generated by wit-bindgen-go. DO NOT EDIT.\n\npackage bar\n",
+		"bar/bar.go":             "package bar\n",
+		"baz/baz.go":             "// Code generated by other-tool. DO NOT EDIT.\n\npackage baz\n",
+		"nested/go.mod":          "module example.com/nested\n",
+		"nested/nested.wit.go":   "// Code generated by wit-bindgen-go. DO NOT EDIT.\n\npackage nested\n",
+		"testdata/data.wit.go":   "// Code generated by wit-bindgen-go. DO NOT EDIT.\n\npackage data\n",
+		"qux/quux/quux.wit.go":   "// Code generated by wit-bindgen-go. DO NOT EDIT.\n\npackage quux\n",
+		"qux/quux/quux.wit.json": "{}",
+		"old/v1/old.wit.go":      "// Code generated by wit-bindgen-go. DO NOT EDIT.\n\npackage old\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	written := []string{filepath.Join(root, "foo", "foo.wit.go")}
+	stale, err := w.Stale("wit-bindgen-go", written)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		filepath.Join(root, "bar", "bar.wit.go"),
+		filepath.Join(root, "old", "v1", "old.wit.go"),
+		filepath.Join(root, "qux", "quux", "quux.wit.go"),
+	}
+	if !slices.Equal(stale, want) {
+		t.Errorf("Stale(): %q, expected %q", stale, want)
+	}
+
+	for _, path := range stale {
+		if err := w.Remove(path); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(root, "bar", "bar.go")); err != nil {
+		t.Errorf("Remove(): removed file not generated by wit-bindgen-go: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "qux")); err != nil {
+		t.Errorf("Remove(): removed non-empty directory: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "old")); err == nil {
+		t.Errorf("Remove(): did not remove empty directory")
+	}
+
+	// Stale files in a missing output directory.
+	w.Root = filepath.Join(root, "missing")
+	stale, err = w.Stale("wit-bindgen-go", nil)
+	if err != nil || len(stale) != 0 {
+		t.Errorf("Stale(): %q, %v, expected no stale files", stale, err)
+	}
+}
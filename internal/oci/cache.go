@@ -0,0 +1,117 @@
+package oci
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Cache is a local, content-addressed cache of WIT artifacts fetched from OCI registries,
+// so artifacts pinned by digest are not fetched again.
+//
+// The WIT content of each artifact is stored by layer digest in Dir/blobs/sha256/<hex>,
+// and the layer digest of each manifest in Dir/manifests/sha256/<hex>.
+// Cached content is verified against its digest when read.
+type Cache struct {
+	Dir string
+}
+
+// DefaultCacheDir returns the default directory of a [Cache] in the user cache directory,
+// e.g. ~/.cache/wit-bindgen-go/oci on Linux.
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "wit-bindgen-go", "oci"), nil
+}
+
+// Get returns the cached [Artifact] for OCI reference ref with manifest digest,
+// or nil if it is not cached.
+func (c *Cache) Get(ref, digest string) (*Artifact, error) {
+	path, err := c.path("manifests", digest)
+	if err != nil {
+		return nil, err
+	}
+	layer, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	a := &Artifact{Ref: ref, Digest: digest, Layer: strings.TrimSpace(string(layer))}
+	path, err = c.path("blobs", a.Layer)
+	if err != nil {
+		return nil, err
+	}
+	a.Content, err = os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if sum := sha256.Sum256(a.Content); "sha256:"+hex.EncodeToString(sum[:]) != a.Layer {
+		return nil, fmt.Errorf("cached content of %s does not match digest %s", path, a.Layer)
+	}
+	return a, nil
+}
+
+// Put stores the content of [Artifact] a in c.
+func (c *Cache) Put(a *Artifact) error {
+	blob, err := c.path("blobs", a.Layer)
+	if err != nil {
+		return err
+	}
+	manifest, err := c.path("manifests", a.Digest)
+	if err != nil {
+		return err
+	}
+	err = writeFile(blob, a.Content)
+	if err != nil {
+		return err
+	}
+	return writeFile(manifest, []byte(a.Layer+"\n"))
+}
+
+// path returns the path of the file of kind for digest, which must be a SHA-256 digest.
+func (c *Cache) path(kind, digest string) (string, error) {
+	hexDigest, ok := strings.CutPrefix(digest, "sha256:")
+	if !ok || len(hexDigest) != sha256.Size*2 || strings.ToLower(hexDigest) != hexDigest {
+		return "", fmt.Errorf("unsupported digest %q", digest)
+	}
+	if _, err := hex.DecodeString(hexDigest); err != nil {
+		return "", fmt.Errorf("unsupported digest %q", digest)
+	}
+	return filepath.Join(c.Dir, kind, "sha256", hexDigest), nil
+}
+
+// writeFile writes content to path, creating its parent directory if necessary.
+// The file is written to a temporary file and renamed, so readers never see partial content.
+func writeFile(path string, content []byte) error {
+	err := os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(content)
+	if err2 := f.Close(); err == nil {
+		err = err2
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), path)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+	return err
+}
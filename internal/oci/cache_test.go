@@ -0,0 +1,72 @@
+package oci
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"testing"
+)
+
+func testDigest(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func TestCache(t *testing.T) {
+	c := &Cache{Dir: t.TempDir()}
+	a := &Artifact{
+		Ref:     "ghcr.io/webassembly/wasi/http:0.2.0",
+		Digest:  testDigest("manifest"),
+		Layer:   testDigest("package wasi:http;"),
+		Content: []byte("package wasi:http;"),
+	}
+
+	got, err := c.Get(a.Ref, a.Digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != nil {
+		t.Errorf("Get(): %v, expected nil for missing artifact", got)
+	}
+
+	err = c.Put(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err = c.Get(a.Ref, a.Digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == nil || got.Layer != a.Layer || !bytes.Equal(got.Content, a.Content) {
+		t.Errorf("Get(): %+v, expected %+v", got, a)
+	}
+
+	// Corrupt the cached content.
+	path, err := c.path("blobs", a.Layer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(path, []byte("package evil:http;"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.Get(a.Ref, a.Digest)
+	if err == nil {
+		t.Error("Get(): expected error for corrupt content")
+	}
+}
+
+func TestCacheInvalidDigest(t *testing.T) {
+	c := &Cache{Dir: t.TempDir()}
+	for _, digest := range []string{"", "sha256:aaaa", "sha512:" + testDigest("x")[7:], "sha256:../../../../etc/passwd"} {
+		_, err := c.Get("example.com/foo:1.0", digest)
+		if err == nil {
+			t.Errorf("Get(%q): expected error", digest)
+		}
+		err = c.Put(&Artifact{Digest: digest, Layer: testDigest("x")})
+		if err == nil {
+			t.Errorf("Put(%q): expected error", digest)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package oci
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Artifact represents a WIT artifact fetched from an OCI registry.
+type Artifact struct {
+	// Ref is the OCI reference used to fetch the artifact, e.g. "ghcr.io/webassembly/wasi/http:0.2.0".
+	Ref string `json:"ref"`
+
+	// Digest is the digest of the artifact manifest, e.g. "sha256:...".
+	Digest string `json:"digest"`
+
+	// Layer is the digest of the layer containing the WIT content.
+	Layer string `json:"layer"`
+
+	// Content is the WIT content of the artifact.
+	Content []byte `json:"-"`
+}
+
+// Lockfile records the digests of WIT artifacts fetched from OCI registries,
+// so that later fetches of the same reference can be pinned and verified.
+type Lockfile struct {
+	Artifacts []Artifact `json:"artifacts"`
+}
+
+// LoadLockfile reads a [Lockfile] from path.
+// If path does not exist, it returns an empty Lockfile.
+func LoadLockfile(path string) (*Lockfile, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &Lockfile{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	l := &Lockfile{}
+	err = json.Unmarshal(data, l)
+	if err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Save writes l to path as JSON, with artifacts sorted by reference.
+func (l *Lockfile) Save(path string) error {
+	slices.SortFunc(l.Artifacts, func(a, b Artifact) int {
+		return strings.Compare(a.Ref, b.Ref)
+	})
+	data, err := json.MarshalIndent(l, "", "\t")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Lookup returns the locked [Artifact] for OCI reference ref, or nil if not found.
+func (l *Lockfile) Lookup(ref string) *Artifact {
+	for i := range l.Artifacts {
+		if l.Artifacts[i].Ref == ref {
+			return &l.Artifacts[i]
+		}
+	}
+	return nil
+}
+
+// Record adds or replaces the locked artifact for a.Ref.
+// It returns true if l was changed.
+func (l *Lockfile) Record(a Artifact) bool {
+	a.Content = nil
+	if locked := l.Lookup(a.Ref); locked != nil {
+		if locked.Digest == a.Digest && locked.Layer == a.Layer {
+			return false
+		}
+		*locked = a
+		return true
+	}
+	l.Artifacts = append(l.Artifacts, a)
+	return true
+}
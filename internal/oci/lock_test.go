@@ -0,0 +1,59 @@
+package oci
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestLockfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wit.lock")
+
+	l, err := LoadLockfile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(l.Artifacts), 0; got != want {
+		t.Fatalf("len(Artifacts): %d, expected %d", got, want)
+	}
+
+	http := Artifact{Ref: "ghcr.io/webassembly/wasi/http:0.2.0", Digest: "sha256:aaaa", Layer: "sha256:bbbb", Content: []byte("package wasi:http;")}
+	cli := Artifact{Ref: "ghcr.io/webassembly/wasi/cli:0.2.0", Digest: "sha256:cccc", Layer: "sha256:dddd"}
+	if !l.Record(http) {
+		t.Errorf("Record(%s): false, expected true", http.Ref)
+	}
+	if l.Record(http) {
+		t.Errorf("Record(%s): true for unchanged artifact, expected false", http.Ref)
+	}
+	if !l.Record(cli) {
+		t.Errorf("Record(%s): false, expected true", cli.Ref)
+	}
+	if got := l.Lookup(http.Ref); got == nil || got.Content != nil {
+		t.Errorf("Lookup(%s): %v, expected artifact without content", http.Ref, got)
+	}
+	if got := l.Lookup("ghcr.io/webassembly/wasi/io:0.2.0"); got != nil {
+		t.Errorf("Lookup(): %v, expected nil", got)
+	}
+
+	err = l.Save(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l2, err := LoadLockfile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(l2.Artifacts), 2; got != want {
+		t.Fatalf("len(Artifacts): %d, expected %d", got, want)
+	}
+	if got, want := l2.Artifacts[0].Ref, cli.Ref; got != want {
+		t.Errorf("Artifacts[0].Ref: %q, expected %q", got, want)
+	}
+
+	http.Digest = "sha256:eeee"
+	if !l2.Record(http) {
+		t.Errorf("Record(%s): false for changed digest, expected true", http.Ref)
+	}
+	if got, want := l2.Lookup(http.Ref).Digest, http.Digest; got != want {
+		t.Errorf("Lookup(%s).Digest: %q, expected %q", http.Ref, got, want)
+	}
+}
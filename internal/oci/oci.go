@@ -15,3 +15,17 @@ func IsOCIPath(path string) bool {
 	_, err := ref.New(path)
 	return err == nil
 }
+
+// IsPinned checks if a given OCI path is pinned by digest, e.g. "ghcr.io/webassembly/wasi/http@sha256:...".
+func IsPinned(path string) bool {
+	return PinnedDigest(path) != ""
+}
+
+// PinnedDigest returns the digest an OCI path is pinned by, or "" if it is not pinned.
+func PinnedDigest(path string) string {
+	r, err := ref.New(path)
+	if err != nil {
+		return ""
+	}
+	return r.Digest
+}
@@ -18,10 +18,25 @@ import (
 // processes it with `wasm-tools`.
 // The output is returned as raw bytes.
 func PullWIT(ctx context.Context, path string) ([]byte, error) {
+	a, err := Pull(ctx, path, "")
+	if err != nil {
+		return nil, err
+	}
+	return a.Content, nil
+}
+
+// Pull fetches the WIT [Artifact] for OCI reference path.
+// If digest is not empty, the artifact manifest is pinned to digest, overriding any tag in path.
+// The manifest digest is verified against a pinned digest, if any,
+// and the WIT content is verified against the layer digest.
+func Pull(ctx context.Context, path, digest string) (*Artifact, error) {
 	r, err := ref.New(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse ref: %v", err)
 	}
+	if digest != "" {
+		r = r.SetDigest(digest)
+	}
 
 	rc := regclient.New()
 	defer rc.Close(ctx, r)
@@ -30,6 +45,10 @@ func PullWIT(ctx context.Context, path string) ([]byte, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to get manifest: %v", err)
 	}
+	manifestDigest := string(m.GetDescriptor().Digest)
+	if r.Digest != "" && manifestDigest != r.Digest {
+		return nil, fmt.Errorf("manifest digest mismatch: got %s, expected %s", manifestDigest, r.Digest)
+	}
 
 	mi, ok := m.(manifest.Imager)
 	if !ok {
@@ -56,11 +75,21 @@ func PullWIT(ctx context.Context, path string) ([]byte, error) {
 	}
 	defer rdr.Close()
 
-	// Read the blob content into a buffer
+	// Read the blob content into a buffer, verifying its digest
 	var buf bytes.Buffer
-	_, err = io.Copy(&buf, rdr)
+	verifier := layer.Digest.Verifier()
+	_, err = io.Copy(io.MultiWriter(&buf, verifier), rdr)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read blob content: %v", err)
 	}
-	return buf.Bytes(), nil
+	if !verifier.Verified() {
+		return nil, fmt.Errorf("layer content does not match digest %s", string(layer.Digest))
+	}
+
+	return &Artifact{
+		Ref:     path,
+		Digest:  manifestDigest,
+		Layer:   string(layer.Digest),
+		Content: buf.Bytes(),
+	}, nil
 }
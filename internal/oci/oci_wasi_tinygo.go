@@ -10,3 +10,7 @@ import (
 func PullWIT(ctx context.Context, path string) ([]byte, error) {
 	return nil, errors.New("OCI not supported on WASI or TinyGo")
 }
+
+func Pull(ctx context.Context, path, digest string) (*Artifact, error) {
+	return nil, errors.New("OCI not supported on WASI or TinyGo")
+}
@@ -0,0 +1,300 @@
+// Package sizereport reports the code size contributed by generated Go packages
+// to WebAssembly binaries built with the Go and TinyGo compilers.
+package sizereport
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"go/types"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/bytecodealliance/wasm-tools-go/internal/go/gen"
+	"github.com/bytecodealliance/wasm-tools-go/internal/wasm"
+)
+
+// cmPackage is the import path of package cm, which is always reported if present.
+const cmPackage = "github.com/bytecodealliance/wasm-tools-go/cm"
+
+// Options are the options for [Run].
+type Options struct {
+	// Dir is the directory of the Go module containing the generated packages.
+	Dir string
+
+	// Patterns are the go command package patterns of the generated packages, e.g. "./...".
+	Patterns []string
+
+	// Tags are build tags passed to each compiler, e.g. "wasip2".
+	Tags string
+
+	// TinyGo is the path of the tinygo command, or empty to find tinygo in PATH.
+	// If tinygo is not found, only the Go compiler is used.
+	TinyGo string
+}
+
+// Report is the code size report for a set of Go packages.
+type Report struct {
+	// Packages are the import paths of the measured packages, in order.
+	Packages []string `json:"packages"`
+
+	// Builds are the results for each compiler.
+	Builds []*Build `json:"builds"`
+}
+
+// Build is the size of a WebAssembly binary built by one compiler.
+type Build struct {
+	// Compiler is "go" or "tinygo".
+	Compiler string `json:"compiler"`
+
+	// Size is the size in bytes of the binary.
+	Size int `json:"size"`
+
+	// Code is the size in bytes of all function bodies in the binary.
+	Code int `json:"code"`
+
+	// Packages is the size in bytes of function bodies attributed to each package.
+	// Code not attributed to a measured package, e.g. the runtime, is not included.
+	Packages map[string]int `json:"packages"`
+
+	// Error is the error output of the compiler if the build failed, in which case
+	// the sizes are zero. For example, the Go compiler rejects //go:wasmimport functions
+	// with pointer parameters to types other than those supported by the Go toolchain.
+	Error string `json:"error,omitempty"`
+}
+
+// Run builds a program that uses every exported function and method of the packages
+// matched by opts.Patterns with the Go compiler (GOOS=wasip1 GOARCH=wasm), and TinyGo
+// (-target=wasip1) if available, and returns a [Report] of the code size attributed to
+// each package. Code is attributed by function name from the "name" custom section,
+// so data, such as string tables, is not attributed to any package.
+//
+// A failed build is reported by [Build.Error]. Run returns an error if no build succeeds.
+func Run(ctx context.Context, opts Options) (*Report, error) {
+	env, err := gen.LoadBuildEnv(ctx, opts.Dir)
+	if err != nil {
+		return nil, err
+	}
+	env.Env = append(env.Env, "GOOS=wasip1", "GOARCH=wasm")
+
+	cfg := env.PackagesConfig(ctx, packages.NeedName|packages.NeedTypes|packages.NeedImports|packages.NeedDeps, opts.Tags)
+	patterns := opts.Patterns
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return nil, err
+	}
+	pkgs = slices.DeleteFunc(pkgs, func(pkg *packages.Package) bool { return pkg.Name == "main" })
+	if len(pkgs) == 0 {
+		return nil, fmt.Errorf("no packages matched %s", strings.Join(patterns, " "))
+	}
+	var errs []error
+	for _, pkg := range pkgs {
+		for _, err := range pkg.Errors {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	slices.SortFunc(pkgs, func(a, b *packages.Package) int { return strings.Compare(a.PkgPath, b.PkgPath) })
+
+	dir, err := os.MkdirTemp(env.Dir, "sizereport-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	main := filepath.Join(dir, "main.go")
+	err = os.WriteFile(main, mainFile(pkgs), 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Report{}
+	for _, pkg := range pkgs {
+		r.Packages = append(r.Packages, pkg.PkgPath)
+	}
+	if !slices.Contains(r.Packages, cmPackage) {
+		r.Packages = append(r.Packages, cmPackage)
+	}
+
+	args := []string{"build", "-o", filepath.Join(dir, "go.wasm")}
+	if opts.Tags != "" {
+		args = append(args, "-tags", opts.Tags)
+	}
+	b, err := r.build(env.Command(ctx, append(args, main)...), "go")
+	if err != nil {
+		return nil, err
+	}
+	r.Builds = append(r.Builds, b)
+	failed := b.Error != ""
+
+	tinygo := opts.TinyGo
+	if tinygo == "" {
+		tinygo, _ = exec.LookPath("tinygo")
+	}
+	if tinygo != "" {
+		args := []string{"build", "-target=wasip1", "-o", filepath.Join(dir, "tinygo.wasm")}
+		if opts.Tags != "" {
+			args = append(args, "-tags", opts.Tags)
+		}
+		cmd := exec.CommandContext(ctx, tinygo, append(args, main)...)
+		cmd.Dir = env.Dir
+		cmd.Env = env.Env
+		b, err := r.build(cmd, "tinygo")
+		if err != nil {
+			return nil, err
+		}
+		r.Builds = append(r.Builds, b)
+		failed = failed && b.Error != ""
+	}
+
+	if failed {
+		return nil, errors.New(r.Builds[len(r.Builds)-1].Error)
+	}
+	return r, nil
+}
+
+// build runs cmd, which writes a binary to the path following its -o flag,
+// and returns the [Build] for the binary. If cmd fails, the returned Build records the error.
+func (r *Report) build(cmd *exec.Cmd, compiler string) (*Build, error) {
+	b := &Build{
+		Compiler: compiler,
+		Packages: make(map[string]int),
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if err != nil {
+		b.Error = fmt.Sprintf("%s build: %v\n%s", compiler, err, strings.TrimSpace(stderr.String()))
+		return b, nil
+	}
+	out := cmd.Args[slices.Index(cmd.Args, "-o")+1]
+	bin, err := os.ReadFile(out)
+	if err != nil {
+		return nil, err
+	}
+	funcs, err := wasm.Funcs(bin)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", out, err)
+	}
+	b.Size = len(bin)
+	for _, f := range funcs {
+		b.Code += f.Size
+		if pkg := r.packageOf(f.Name); pkg != "" {
+			b.Packages[pkg] += f.Size
+		}
+	}
+	return b, nil
+}
+
+// packageOf returns the measured package of the function with linker symbol name,
+// or an empty string if none. Both compilers prefix function names with the package path,
+// e.g. example.com/pkg.Func or example.com/pkg.(*T).Method. The Go linker replaces
+// each '/' in the package path with '_', and TinyGo may wrap the receiver of a method,
+// e.g. (*example.com/pkg.T).Method.
+func (r *Report) packageOf(name string) string {
+	name = strings.TrimLeft(name, "(*")
+	var pkg string
+	for _, path := range r.Packages {
+		if len(path) <= len(pkg) {
+			continue
+		}
+		if strings.HasPrefix(name, path+".") || strings.HasPrefix(name, strings.ReplaceAll(path, "/", "_")+".") {
+			pkg = path
+		}
+	}
+	return pkg
+}
+
+// mainFile returns the source of a main package that references every exported function
+// and method of pkgs, so the linker keeps them in the binary.
+func mainFile(pkgs []*packages.Package) []byte {
+	var b bytes.Buffer
+	b.WriteString("// Code generated by wit-bindgen-go. DO NOT EDIT.\n\npackage main\n\nimport (\n")
+	for i, pkg := range pkgs {
+		fmt.Fprintf(&b, "\tp%d %q\n", i, pkg.PkgPath)
+	}
+	b.WriteString(")\n\nvar keep = []any{\n")
+	for i, pkg := range pkgs {
+		scope := pkg.Types.Scope()
+		for _, name := range scope.Names() {
+			switch obj := scope.Lookup(name).(type) {
+			case *types.Func:
+				if obj.Exported() && obj.Type().(*types.Signature).TypeParams().Len() == 0 {
+					fmt.Fprintf(&b, "\tp%d.%s,\n", i, name)
+				}
+			case *types.TypeName:
+				named, ok := obj.Type().(*types.Named)
+				if !obj.Exported() || obj.IsAlias() || !ok || named.TypeParams().Len() > 0 {
+					continue
+				}
+				if types.IsInterface(named) {
+					continue
+				}
+				for j := 0; j < named.NumMethods(); j++ {
+					if m := named.Method(j); m.Exported() {
+						fmt.Fprintf(&b, "\t(*p%d.%s).%s,\n", i, name, m.Name())
+					}
+				}
+			}
+		}
+	}
+	b.WriteString("}\n\nfunc main() {\n\tprintln(len(keep))\n}\n")
+	return b.Bytes()
+}
+
+// Write writes a table of the code size attributed to each package to w,
+// with a column for each compiler, followed by the error of each failed build.
+func (r *Report) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', tabwriter.AlignRight)
+	row := func(label string, size func(b *Build) int) {
+		for _, b := range r.Builds {
+			if b.Error != "" {
+				fmt.Fprint(tw, "-\t")
+				continue
+			}
+			fmt.Fprintf(tw, "%d\t", size(b))
+		}
+		fmt.Fprintf(tw, "  %s\n", label)
+	}
+	for _, b := range r.Builds {
+		fmt.Fprintf(tw, "%s\t", b.Compiler)
+	}
+	fmt.Fprintf(tw, "  package\n")
+	for _, path := range r.Packages {
+		row(path, func(b *Build) int { return b.Packages[path] })
+	}
+	row("(other code)", func(b *Build) int {
+		n := b.Code
+		for _, size := range b.Packages {
+			n -= size
+		}
+		return n
+	})
+	row("(code total)", func(b *Build) int { return b.Code })
+	row("(binary total)", func(b *Build) int { return b.Size })
+	err := tw.Flush()
+	if err != nil {
+		return err
+	}
+	for _, b := range r.Builds {
+		if b.Error != "" {
+			_, err = fmt.Fprintf(w, "\n%s\n", b.Error)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
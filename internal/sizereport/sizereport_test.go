@@ -0,0 +1,94 @@
+package sizereport
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPackageOf(t *testing.T) {
+	r := &Report{Packages: []string{"example.com/foo", "example.com/foo/bar", cmPackage}}
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"example.com/foo.Func", "example.com/foo"},
+		{"example.com/foo/bar.(*T).Method", "example.com/foo/bar"},
+		{"(*example.com/foo/bar.T).Method", "example.com/foo/bar"},
+		{"example.com/foo/bar.Func.func1", "example.com/foo/bar"},
+		{"example.com_foo_bar.(*T).Method", "example.com/foo/bar"},
+		{cmPackage + ".LiftString[...]", cmPackage},
+		{"example.com/foobar.Func", ""},
+		{"runtime.mallocgc", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := r.packageOf(tt.name); got != tt.want {
+			t.Errorf("packageOf(%q): %q, expected %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping build of WebAssembly binaries in short mode")
+	}
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":     "module example.com/size\n\ngo 1.22\n",
+		"foo/foo.go": "package foo\n\nfunc Sum(s []int) (n int) {\n\tfor _, v := range s {\n\t\tn += v\n\t}\n\treturn n\n}\n",
+		"bar/bar.go": "package bar\n\ntype T struct{ s string }\n\nfunc (t *T) Len() int { return len(t.s) }\n\nfunc unused() {}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		err := os.MkdirAll(filepath.Dir(path), 0o755)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(path, []byte(content), 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r, err := Run(context.Background(), Options{Dir: dir, Patterns: []string{"./..."}, TinyGo: "/nonexistent/tinygo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"example.com/size/bar", "example.com/size/foo", cmPackage}
+	if strings.Join(r.Packages, " ") != strings.Join(want, " ") {
+		t.Errorf("Packages: %v, expected %v", r.Packages, want)
+	}
+	if len(r.Builds) != 2 {
+		t.Fatalf("Builds: %d, expected 2", len(r.Builds))
+	}
+	b := r.Builds[0]
+	if b.Error != "" {
+		t.Fatal(b.Error)
+	}
+	for _, path := range want[:2] {
+		if b.Packages[path] == 0 {
+			t.Errorf("go build: no code attributed to package %s", path)
+		}
+	}
+	if b.Size <= b.Code {
+		t.Errorf("go build: binary size %d, expected more than code size %d", b.Size, b.Code)
+	}
+	if r.Builds[1].Error == "" {
+		t.Error("tinygo build: expected error")
+	}
+
+	var out strings.Builder
+	err = r.Write(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "example.com/size/foo") {
+		t.Errorf("Write(): expected package example.com/size/foo:\n%s", out.String())
+	}
+	if tmp, _ := filepath.Glob(filepath.Join(dir, "sizereport-*")); len(tmp) != 0 {
+		t.Errorf("temporary files not removed: %v", tmp)
+	}
+}
@@ -0,0 +1,53 @@
+package wasm
+
+// AppendU32 appends the unsigned LEB128 encoding of v to b.
+func AppendU32(b []byte, v uint32) []byte {
+	for {
+		c := byte(v & 0x7f)
+		v >>= 7
+		if v != 0 {
+			c |= 0x80
+		}
+		b = append(b, c)
+		if v == 0 {
+			return b
+		}
+	}
+}
+
+// AppendS33 appends the signed LEB128 encoding of v to b.
+func AppendS33(b []byte, v int64) []byte {
+	for {
+		c := byte(v & 0x7f)
+		v >>= 7
+		if (v == 0 && c&0x40 == 0) || (v == -1 && c&0x40 != 0) {
+			return append(b, c)
+		}
+		b = append(b, c|0x80)
+	}
+}
+
+// AppendName appends the length-prefixed encoding of name to b.
+func AppendName(b []byte, name string) []byte {
+	b = AppendU32(b, uint32(len(name)))
+	return append(b, name...)
+}
+
+// AppendSection appends a section with id and data to b.
+func AppendSection(b []byte, id byte, data []byte) []byte {
+	b = append(b, id)
+	b = AppendU32(b, uint32(len(data)))
+	return append(b, data...)
+}
+
+// AppendCustomSection appends a custom section with name and payload to b.
+func AppendCustomSection(b []byte, name string, payload []byte) []byte {
+	data := AppendName(nil, name)
+	return AppendSection(b, SectionCustom, append(data, payload...))
+}
+
+// AppendHeader appends the preamble for [Header] h to b.
+func AppendHeader(b []byte, h Header) []byte {
+	b = append(b, Magic...)
+	return append(b, byte(h.Version), byte(h.Version>>8), byte(h.Layer), byte(h.Layer>>8))
+}
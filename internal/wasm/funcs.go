@@ -0,0 +1,149 @@
+package wasm
+
+import (
+	"fmt"
+)
+
+// Core module section IDs used by [Funcs].
+const (
+	SectionModuleImport = 2
+	SectionModuleCode   = 10
+)
+
+// Core module import kinds.
+const (
+	importFunc   = 0x00
+	importTable  = 0x01
+	importMemory = 0x02
+	importGlobal = 0x03
+	importTag    = 0x04
+)
+
+// Func is a function defined in a core WebAssembly module.
+type Func struct {
+	Index uint32 // function index, including imported functions
+	Name  string // name from the "name" custom section, if any
+	Size  int    // size in bytes of the function body in the code section
+}
+
+// Funcs returns the functions defined in core WebAssembly module b,
+// named by the function names subsection of the "name" custom section, if present.
+// Imported functions are not returned, as they have no body.
+func Funcs(b []byte) ([]Func, error) {
+	h, sections, err := ReadSections(b)
+	if err != nil {
+		return nil, err
+	}
+	if h.IsComponent() {
+		return nil, fmt.Errorf("not a core WebAssembly module")
+	}
+	var imported uint32
+	var funcs []Func
+	names := make(map[uint32]string)
+	for _, s := range sections {
+		switch s.ID {
+		case SectionModuleImport:
+			imported, err = importedFuncs(s.Data)
+		case SectionModuleCode:
+			funcs, err = codeFuncs(s.Data)
+		case SectionCustom:
+			var name string
+			var payload []byte
+			name, payload, err = CustomSection(s.Data)
+			if err == nil && name == "name" {
+				err = funcNames(payload, names)
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	for i := range funcs {
+		funcs[i].Index += imported
+		funcs[i].Name = names[funcs[i].Index]
+	}
+	return funcs, nil
+}
+
+// importedFuncs returns the number of functions imported by import section data.
+func importedFuncs(data []byte) (uint32, error) {
+	r := NewReader(data)
+	var n uint32
+	count := r.U32()
+	for i := uint32(0); i < count && r.Err() == nil; i++ {
+		r.Name() // module
+		r.Name() // name
+		switch kind := r.Byte(); kind {
+		case importFunc:
+			r.U32() // type index
+			n++
+		case importTable:
+			r.Byte() // reference type
+			limits(r)
+		case importMemory:
+			limits(r)
+		case importGlobal:
+			r.Byte() // value type
+			r.Byte() // mutability
+		case importTag:
+			r.Byte() // attribute
+			r.U32()  // type index
+		default:
+			r.Failf("unknown import kind 0x%02x", kind)
+		}
+	}
+	if err := r.Err(); err != nil {
+		return 0, fmt.Errorf("import section: %w", err)
+	}
+	return n, nil
+}
+
+// limits reads table or memory limits from r.
+func limits(r *Reader) {
+	flags := r.Byte()
+	r.U32() // min
+	if flags&0x01 != 0 {
+		r.U32() // max
+	}
+}
+
+// codeFuncs returns the functions of code section data, indexed from zero.
+func codeFuncs(data []byte) ([]Func, error) {
+	r := NewReader(data)
+	count := r.U32()
+	var funcs []Func
+	for i := uint32(0); i < count && r.Err() == nil; i++ {
+		size := int(r.U32())
+		r.Bytes(size)
+		funcs = append(funcs, Func{Index: i, Size: size})
+	}
+	if err := r.Err(); err != nil {
+		return nil, fmt.Errorf("code section: %w", err)
+	}
+	return funcs, nil
+}
+
+// funcNames reads the function names subsection of "name" custom section payload into names.
+func funcNames(payload []byte, names map[uint32]string) error {
+	r := NewReader(payload)
+	for r.Len() > 0 && r.Err() == nil {
+		id := r.Byte()
+		sub := r.Bytes(int(r.U32()))
+		if id != 1 || r.Err() != nil {
+			continue
+		}
+		s := NewReader(sub)
+		count := s.U32()
+		for i := uint32(0); i < count && s.Err() == nil; i++ {
+			idx := s.U32()
+			names[idx] = s.Name()
+		}
+		if err := s.Err(); err != nil {
+			return fmt.Errorf("name section: %w", err)
+		}
+	}
+	if err := r.Err(); err != nil {
+		return fmt.Errorf("name section: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package wasm
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFuncs(t *testing.T) {
+	var imports []byte
+	imports = AppendU32(imports, 2)
+	imports = AppendName(imports, "env")
+	imports = AppendName(imports, "f")
+	imports = append(imports, importFunc, 0)
+	imports = AppendName(imports, "env")
+	imports = AppendName(imports, "memory")
+	imports = append(imports, importMemory, 0x01, 1, 2)
+
+	var code []byte
+	code = AppendU32(code, 2)
+	code = AppendU32(code, 3)
+	code = append(code, 0, 0x01, 0x0b)
+	code = AppendU32(code, 2)
+	code = append(code, 0, 0x0b)
+
+	var names []byte
+	names = AppendU32(names, 2)
+	names = AppendU32(names, 0)
+	names = AppendName(names, "f")
+	names = AppendU32(names, 2)
+	names = AppendName(names, "example.com/pkg.Func")
+	var payload []byte
+	payload = append(payload, 0)
+	payload = AppendName(payload, "module")
+	payload = append(payload, 1)
+	payload = AppendU32(payload, uint32(len(names)))
+	payload = append(payload, names...)
+
+	var b []byte
+	b = AppendHeader(b, Header{Version: 1, Layer: LayerModule})
+	b = AppendSection(b, SectionModuleImport, imports)
+	b = AppendSection(b, SectionModuleCode, code)
+	b = AppendCustomSection(b, "name", payload)
+
+	got, err := Funcs(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Func{
+		{Index: 1, Size: 3},
+		{Index: 2, Name: "example.com/pkg.Func", Size: 2},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Funcs(): %+v, expected %+v", got, want)
+	}
+
+	b = AppendHeader(nil, Header{Version: 0xd, Layer: LayerComponent})
+	_, err = Funcs(b)
+	if err == nil {
+		t.Error("Funcs(component): expected error")
+	}
+}
@@ -0,0 +1,238 @@
+// Package wasm reads and writes the WebAssembly binary format, including the [binary format]
+// of the WebAssembly Component Model.
+//
+// [binary format]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/Binary.md
+package wasm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+// Magic is the magic number at the beginning of WebAssembly binaries.
+var Magic = []byte("\x00asm")
+
+// Binary layers, as encoded in the preamble of a WebAssembly binary.
+const (
+	LayerModule    = 0
+	LayerComponent = 1
+)
+
+// Component model section IDs.
+// Core module sections share the same space of IDs, but not the same meanings,
+// except for [SectionCustom].
+const (
+	SectionCustom       = 0
+	SectionCoreModule   = 1
+	SectionCoreInstance = 2
+	SectionCoreType     = 3
+	SectionComponent    = 4
+	SectionInstance     = 5
+	SectionAlias        = 6
+	SectionType         = 7
+	SectionCanon        = 8
+	SectionStart        = 9
+	SectionImport       = 10
+	SectionExport       = 11
+	SectionValue        = 12
+)
+
+// Header is the preamble of a WebAssembly binary.
+type Header struct {
+	Version uint16
+	Layer   uint16
+}
+
+// IsComponent returns true if h is the header of a WebAssembly component.
+func (h Header) IsComponent() bool {
+	return h.Layer == LayerComponent
+}
+
+// Section is a section of a WebAssembly binary.
+type Section struct {
+	ID   byte
+	Data []byte
+}
+
+// ReadSections reads the [Header] and [Section] values of WebAssembly binary b.
+// The returned sections reference b.
+func ReadSections(b []byte) (Header, []Section, error) {
+	var h Header
+	if !bytes.HasPrefix(b, Magic) {
+		return h, nil, errors.New("not a WebAssembly binary")
+	}
+	r := NewReader(b[len(Magic):])
+	v := r.Bytes(4)
+	if r.Err() != nil {
+		return h, nil, errors.New("truncated WebAssembly preamble")
+	}
+	h.Version = uint16(v[0]) | uint16(v[1])<<8
+	h.Layer = uint16(v[2]) | uint16(v[3])<<8
+	if h.Layer != LayerModule && h.Layer != LayerComponent {
+		return h, nil, fmt.Errorf("unknown WebAssembly binary layer %d", h.Layer)
+	}
+
+	var sections []Section
+	for r.Len() > 0 {
+		var s Section
+		s.ID = r.Byte()
+		s.Data = r.Bytes(int(r.U32()))
+		if err := r.Err(); err != nil {
+			return h, nil, fmt.Errorf("section %d: %w", len(sections), err)
+		}
+		sections = append(sections, s)
+	}
+	return h, sections, nil
+}
+
+// CustomSection returns the name and payload of the custom section with data.
+func CustomSection(data []byte) (name string, payload []byte, err error) {
+	r := NewReader(data)
+	name = r.Name()
+	if err := r.Err(); err != nil {
+		return "", nil, fmt.Errorf("custom section name: %w", err)
+	}
+	return name, r.Rest(), nil
+}
+
+// Reader reads values encoded in the WebAssembly binary format from a byte slice.
+// Read errors are sticky: after the first error, subsequent reads return zero values,
+// and the error is reported by [Reader.Err].
+type Reader struct {
+	b   []byte
+	off int
+	err error
+}
+
+// NewReader returns a [Reader] that reads from b.
+func NewReader(b []byte) *Reader {
+	return &Reader{b: b}
+}
+
+// Err returns the first error encountered by r, if any.
+func (r *Reader) Err() error {
+	return r.err
+}
+
+// Len returns the number of unread bytes in r.
+func (r *Reader) Len() int {
+	return len(r.b) - r.off
+}
+
+// Offset returns the offset of the next byte to be read by r.
+func (r *Reader) Offset() int {
+	return r.off
+}
+
+// Fail records err as the error of r, if r has not already failed.
+func (r *Reader) Fail(err error) {
+	if r.err == nil {
+		r.err = err
+	}
+}
+
+// Failf records a formatted error as the error of r, if r has not already failed.
+// The error includes the current offset of r.
+func (r *Reader) Failf(format string, args ...any) {
+	r.Fail(fmt.Errorf("offset %d: %s", r.off, fmt.Sprintf(format, args...)))
+}
+
+// Byte reads a single byte.
+func (r *Reader) Byte() byte {
+	if r.err != nil {
+		return 0
+	}
+	if r.off >= len(r.b) {
+		r.Failf("unexpected end of data")
+		return 0
+	}
+	c := r.b[r.off]
+	r.off++
+	return c
+}
+
+// Peek returns the next byte without advancing r.
+func (r *Reader) Peek() byte {
+	if r.err != nil || r.off >= len(r.b) {
+		return 0
+	}
+	return r.b[r.off]
+}
+
+// Bytes reads n bytes. The returned slice references the underlying data of r.
+func (r *Reader) Bytes(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || n > r.Len() {
+		r.Failf("length %d exceeds remaining data", n)
+		return nil
+	}
+	b := r.b[r.off : r.off+n]
+	r.off += n
+	return b
+}
+
+// Rest reads the remaining bytes.
+func (r *Reader) Rest() []byte {
+	return r.Bytes(r.Len())
+}
+
+// U32 reads an unsigned LEB128-encoded 32-bit integer.
+func (r *Reader) U32() uint32 {
+	var v uint32
+	for shift := 0; shift < 35; shift += 7 {
+		c := r.Byte()
+		if r.err != nil {
+			return 0
+		}
+		if shift == 28 && c > 0x0f {
+			r.Failf("u32 overflow")
+			return 0
+		}
+		v |= uint32(c&0x7f) << shift
+		if c&0x80 == 0 {
+			return v
+		}
+	}
+	return 0
+}
+
+// S33 reads a signed LEB128-encoded 33-bit integer.
+func (r *Reader) S33() int64 {
+	var v int64
+	var shift uint
+	for {
+		c := r.Byte()
+		if r.err != nil {
+			return 0
+		}
+		v |= int64(c&0x7f) << shift
+		shift += 7
+		if c&0x80 == 0 {
+			if shift < 64 && c&0x40 != 0 {
+				v |= -1 << shift
+			}
+			return v
+		}
+		if shift >= 35 {
+			r.Failf("s33 overflow")
+			return 0
+		}
+	}
+}
+
+// Name reads a length-prefixed UTF-8 string.
+func (r *Reader) Name() string {
+	b := r.Bytes(int(r.U32()))
+	if r.err != nil {
+		return ""
+	}
+	if !utf8.Valid(b) {
+		r.Failf("invalid UTF-8 name")
+		return ""
+	}
+	return string(b)
+}
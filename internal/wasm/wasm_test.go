@@ -0,0 +1,123 @@
+package wasm
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestReaderU32(t *testing.T) {
+	tests := []struct {
+		b       []byte
+		want    uint32
+		wantErr bool
+	}{
+		{[]byte{0x00}, 0, false},
+		{[]byte{0x7f}, 127, false},
+		{[]byte{0x80, 0x01}, 128, false},
+		{[]byte{0xe5, 0x8e, 0x26}, 624485, false},
+		{[]byte{0xff, 0xff, 0xff, 0xff, 0x0f}, 0xffffffff, false},
+		{[]byte{0xff, 0xff, 0xff, 0xff, 0x1f}, 0, true},
+		{[]byte{0x80}, 0, true},
+	}
+	for _, tt := range tests {
+		r := NewReader(tt.b)
+		got := r.U32()
+		if got != tt.want || (r.Err() != nil) != tt.wantErr {
+			t.Errorf("U32(% x): %d, %v, expected %d, error: %t", tt.b, got, r.Err(), tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestReaderS33(t *testing.T) {
+	tests := []struct {
+		b    []byte
+		want int64
+	}{
+		{[]byte{0x00}, 0},
+		{[]byte{0x3f}, 63},
+		{[]byte{0x7f}, -1},
+		{[]byte{0xc0, 0x00}, 64},
+		{[]byte{0x73}, -13},
+	}
+	for _, tt := range tests {
+		r := NewReader(tt.b)
+		got := r.S33()
+		if got != tt.want || r.Err() != nil {
+			t.Errorf("S33(% x): %d, %v, expected %d", tt.b, got, r.Err(), tt.want)
+		}
+	}
+}
+
+func TestReaderName(t *testing.T) {
+	r := NewReader([]byte("\x05hello\x02\xff\xfe"))
+	if got, want := r.Name(), "hello"; got != want {
+		t.Errorf("Name(): %q, expected %q", got, want)
+	}
+	r.Name()
+	if r.Err() == nil {
+		t.Error("Name(): expected error for invalid UTF-8")
+	}
+}
+
+func TestReadSections(t *testing.T) {
+	b := []byte("\x00asm\x0d\x00\x01\x00" + "\x00\x04\x03abc" + "\x07\x01\x00")
+	h, sections, err := ReadSections(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !h.IsComponent() {
+		t.Errorf("IsComponent(): false, expected true")
+	}
+	if len(sections) != 2 {
+		t.Fatalf("ReadSections: %d sections, expected 2", len(sections))
+	}
+	if sections[1].ID != SectionType || !bytes.Equal(sections[1].Data, []byte{0x00}) {
+		t.Errorf("sections[1]: %v, expected type section", sections[1])
+	}
+	name, payload, err := CustomSection(sections[0].Data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "abc" || len(payload) != 0 {
+		t.Errorf("CustomSection: %q, %v, expected \"abc\" with empty payload", name, payload)
+	}
+
+	for _, b := range []string{"", "\x00asm", "\x00asm\x01\x00\x00\x00\x01\x05", "\x00asm\x01\x00\x02\x00"} {
+		_, _, err := ReadSections([]byte(b))
+		if err == nil {
+			t.Errorf("ReadSections(%q): expected error", b)
+		}
+	}
+}
+
+func TestAppend(t *testing.T) {
+	for _, v := range []uint32{0, 1, 63, 64, 127, 128, 624485, 0xffffffff} {
+		r := NewReader(AppendU32(nil, v))
+		if got := r.U32(); got != v || r.Len() != 0 || r.Err() != nil {
+			t.Errorf("AppendU32(%d): read %d, %v", v, got, r.Err())
+		}
+	}
+	for _, v := range []int64{0, 1, 63, 64, -1, -64, -65, 1 << 32} {
+		r := NewReader(AppendS33(nil, v))
+		if got := r.S33(); got != v || r.Len() != 0 || r.Err() != nil {
+			t.Errorf("AppendS33(%d): read %d, %v", v, got, r.Err())
+		}
+	}
+
+	b := AppendHeader(nil, Header{Version: 1, Layer: LayerModule})
+	b = AppendCustomSection(b, "name", []byte{1, 2, 3})
+	h, sections, err := ReadSections(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.IsComponent() || h.Version != 1 || len(sections) != 1 {
+		t.Fatalf("ReadSections: %v, %d sections, expected core module with 1 section", h, len(sections))
+	}
+	name, payload, err := CustomSection(sections[0].Data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "name" || !bytes.Equal(payload, []byte{1, 2, 3}) {
+		t.Errorf("CustomSection: %q, %v, expected \"name\" with payload [1 2 3]", name, payload)
+	}
+}
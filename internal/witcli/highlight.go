@@ -0,0 +1,151 @@
+package witcli
+
+import "strings"
+
+// ANSI escape sequences used by [Highlight].
+const (
+	ansiReset      = "\x1b[0m"
+	ansiKeyword    = "\x1b[35m" // magenta
+	ansiType       = "\x1b[36m" // cyan
+	ansiComment    = "\x1b[90m" // bright black
+	ansiDocComment = "\x1b[32m" // green
+	ansiAnnotation = "\x1b[33m" // yellow
+)
+
+// Highlight returns WIT text src with ANSI escape sequences for syntax highlighting
+// of keywords, built-in types, comments, doc comments, and annotations such as @since,
+// suitable for printing to a terminal.
+func Highlight(src string) string {
+	var b strings.Builder
+	b.Grow(len(src) * 5 / 4)
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case strings.HasPrefix(src[i:], "//"):
+			end := strings.IndexByte(src[i:], '\n')
+			if end < 0 {
+				end = len(src) - i
+			}
+			color := ansiComment
+			if strings.HasPrefix(src[i:], "///") {
+				color = ansiDocComment
+			}
+			writeColor(&b, color, src[i:i+end])
+			i += end
+
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				end = len(src) - i
+			} else {
+				end += 4
+			}
+			color := ansiComment
+			if strings.HasPrefix(src[i:], "/**") && !strings.HasPrefix(src[i:], "/**/") {
+				color = ansiDocComment
+			}
+			writeColor(&b, color, src[i:i+end])
+			i += end
+
+		case c == '@' && i+1 < len(src) && isIdentStart(src[i+1]):
+			end := i + 1 + identLen(src[i+1:])
+			writeColor(&b, ansiAnnotation, src[i:end])
+			i = end
+
+		case c == '%':
+			// Explicitly escaped identifier, e.g. %type
+			end := i + 1 + identLen(src[i+1:])
+			b.WriteString(src[i:end])
+			i = end
+
+		case isIdentStart(c):
+			end := i + identLen(src[i:])
+			word := src[i:end]
+			switch {
+			case witTypes[word]:
+				writeColor(&b, ansiType, word)
+			case witKeywords[word]:
+				writeColor(&b, ansiKeyword, word)
+			default:
+				b.WriteString(word)
+			}
+			i = end
+
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	return b.String()
+}
+
+func writeColor(b *strings.Builder, color, s string) {
+	b.WriteString(color)
+	b.WriteString(s)
+	b.WriteString(ansiReset)
+}
+
+func isIdentStart(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdent(c byte) bool {
+	return isIdentStart(c) || (c >= '0' && c <= '9') || c == '-' || c == '_'
+}
+
+// identLen returns the length of the WIT identifier at the start of s.
+func identLen(s string) int {
+	n := 0
+	for n < len(s) && isIdent(s[n]) {
+		n++
+	}
+	return n
+}
+
+// witKeywords are WIT keywords highlighted by [Highlight], excluding built-in types.
+var witKeywords = map[string]bool{
+	"as":          true,
+	"constructor": true,
+	"enum":        true,
+	"export":      true,
+	"flags":       true,
+	"from":        true,
+	"func":        true,
+	"import":      true,
+	"include":     true,
+	"interface":   true,
+	"package":     true,
+	"record":      true,
+	"resource":    true,
+	"static":      true,
+	"type":        true,
+	"use":         true,
+	"variant":     true,
+	"with":        true,
+	"world":       true,
+}
+
+// witTypes are WIT built-in types highlighted by [Highlight].
+var witTypes = map[string]bool{
+	"bool":   true,
+	"borrow": true,
+	"char":   true,
+	"f32":    true,
+	"f64":    true,
+	"future": true,
+	"list":   true,
+	"option": true,
+	"own":    true,
+	"result": true,
+	"s16":    true,
+	"s32":    true,
+	"s64":    true,
+	"s8":     true,
+	"stream": true,
+	"string": true,
+	"tuple":  true,
+	"u16":    true,
+	"u32":    true,
+	"u64":    true,
+	"u8":     true,
+}
@@ -0,0 +1,41 @@
+package witcli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{"", ""},
+		{"foo-bar", "foo-bar"},
+		{"interface", ansiKeyword + "interface" + ansiReset},
+		{"%interface", "%interface"},
+		{"list<u8>", ansiType + "list" + ansiReset + "<" + ansiType + "u8" + ansiReset + ">"},
+		{"// a\nworld", ansiComment + "// a" + ansiReset + "\n" + ansiKeyword + "world" + ansiReset},
+		{"/// docs", ansiDocComment + "/// docs" + ansiReset},
+		{"/* a */b", ansiComment + "/* a */" + ansiReset + "b"},
+		{"@since(version = 0.2.0)", ansiAnnotation + "@since" + ansiReset + "(version = 0.2.0)"},
+		{"records", "records"},
+		{"wasi:cli@0.2.0", "wasi:cli@0.2.0"},
+	}
+	for _, tt := range tests {
+		if got := Highlight(tt.src); got != tt.want {
+			t.Errorf("Highlight(%q): %q, expected %q", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestHighlightPreservesText(t *testing.T) {
+	src := "package wasi:cli@0.2.0;\n\n/// Docs\ninterface %type {\n\t@unstable(feature = x)\n\tf: func(a: list<u8>) -> result<_, string>; /* unterminated"
+	got := Highlight(src)
+	for _, seq := range []string{ansiReset, ansiKeyword, ansiType, ansiComment, ansiDocComment, ansiAnnotation} {
+		got = strings.ReplaceAll(got, seq, "")
+	}
+	if got != src {
+		t.Errorf("Highlight(src) without escapes: %q, expected %q", got, src)
+	}
+}
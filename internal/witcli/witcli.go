@@ -1,35 +1,292 @@
 package witcli
 
 import (
+	"bytes"
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
-	"strings"
+	"os/exec"
+	"path/filepath"
 
 	"github.com/bytecodealliance/wasm-tools-go/internal/oci"
 	"github.com/bytecodealliance/wasm-tools-go/wit"
+	"github.com/bytecodealliance/wasm-tools-go/wit/bindgen"
+	"github.com/bytecodealliance/wasm-tools-go/wit/extract"
 )
 
 // LoadWIT loads a single [wit.Resolve].
 // If path is a OCI path, it pulls from the OCI registry and load WIT
 // from the buffer.
 // If path == "" or "-", then it reads from stdin.
-// If the resolved path doesn’t end in ".json", it will attempt to load
-// WIT indirectly by processing the input through wasm-tools.
+// The input format is detected as described in [Load].
 // If forceWIT is true, it will always process input through wasm-tools.
 func LoadWIT(ctx context.Context, forceWIT bool, path string) (*wit.Resolve, error) {
+	return Load(ctx, path, Options{ForceWIT: forceWIT})
+}
+
+// Options configure how [Load] loads WIT.
+type Options struct {
+	// ForceWIT, if true, always processes input through wasm-tools.
+	ForceWIT bool
+
+	// Lockfile is an optional path to a lockfile that records the digests
+	// of WIT artifacts fetched from OCI registries. Fetches of a reference
+	// recorded in the lockfile are pinned to and verified against its digest.
+	// New references are recorded in the lockfile.
+	Lockfile string
+
+	// RequireDigest, if true, requires OCI references to be pinned by digest,
+	// either in the reference itself or in the lockfile.
+	RequireDigest bool
+
+	// CacheDir is an optional directory of an [oci.Cache] of WIT artifacts fetched
+	// from OCI registries. Artifacts pinned by digest are loaded from the cache if present,
+	// and fetched artifacts are stored in the cache.
+	CacheDir string
+}
+
+// Load loads a single [wit.Resolve] from path, as described in [LoadWIT],
+// with the supplied [Options].
+//
+// The input format is detected automatically. Input from stdin or a file is loaded as
+// JSON if it begins with '{', or decoded as a WebAssembly binary with embedded WIT if it
+// begins with the WebAssembly magic number. Otherwise, it is processed through wasm-tools
+// as WIT text. A directory of .wit files is processed through wasm-tools.
+func Load(ctx context.Context, path string, opts Options) (*wit.Resolve, error) {
+	return load(ctx, path, os.Stdin, opts)
+}
+
+func load(ctx context.Context, path string, stdin io.Reader, opts Options) (*wit.Resolve, error) {
 	if oci.IsOCIPath(path) {
-		fmt.Fprintf(os.Stderr, "Fetching OCI artifact %s\n", path)
-		if bytes, err := oci.PullWIT(ctx, path); err != nil {
+		content, err := pullWIT(ctx, path, opts)
+		if err != nil {
+			return nil, err
+		}
+		return parse(content, FormatWasm)
+	}
+
+	if path == "" || path == "-" {
+		content, err := io.ReadAll(stdin)
+		if err != nil {
 			return nil, err
+		}
+		if opts.ForceWIT {
+			return parse(content, FormatWIT)
+		}
+		return parse(content, DetectFormat(content))
+	}
+
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if fi.IsDir() {
+		return loadWIT(path, FormatDir)
+	}
+	if opts.ForceWIT {
+		return loadWIT(path, FormatWIT)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	format := DetectFormat(content)
+	if format == FormatJSON || format == FormatWasm {
+		return parse(content, format)
+	}
+	// Load files by path, so wasm-tools can resolve any dependencies.
+	return loadWIT(path, format)
+}
+
+// Format is a WIT input format.
+type Format int
+
+const (
+	// FormatJSON is the JSON representation of a fully-resolved WIT package.
+	FormatJSON Format = iota
+
+	// FormatWIT is WIT text.
+	FormatWIT
+
+	// FormatWasm is a WebAssembly binary with embedded WIT, decoded with [extract.Decode].
+	FormatWasm
+
+	// FormatDir is a directory of WIT files.
+	FormatDir
+)
+
+// String returns the name of format f.
+func (f Format) String() string {
+	switch f {
+	case FormatJSON:
+		return "JSON"
+	case FormatWIT:
+		return "WIT"
+	case FormatWasm:
+		return "WebAssembly"
+	case FormatDir:
+		return "WIT directory"
+	}
+	return "unknown"
+}
+
+// wasmMagic is the magic number at the beginning of WebAssembly binaries.
+var wasmMagic = []byte("\x00asm")
+
+// DetectFormat returns the [Format] of content, which is JSON if it begins with '{'
+// after any whitespace, WebAssembly if it begins with the WebAssembly magic number,
+// or WIT text otherwise.
+func DetectFormat(content []byte) Format {
+	if bytes.HasPrefix(content, wasmMagic) {
+		return FormatWasm
+	}
+	if bytes.HasPrefix(bytes.TrimLeft(content, " \t\r\n"), []byte("{")) {
+		return FormatJSON
+	}
+	return FormatWIT
+}
+
+func parse(content []byte, format Format) (*wit.Resolve, error) {
+	switch format {
+	case FormatJSON:
+		return wit.DecodeJSON(bytes.NewReader(content))
+	case FormatWasm:
+		return extract.Decode(content)
+	}
+	if _, err := exec.LookPath("wasm-tools"); err != nil {
+		return nil, fmt.Errorf("loading %s input requires wasm-tools: %w", format, err)
+	}
+	return wit.ParseWIT(content)
+}
+
+func loadWIT(path string, format Format) (*wit.Resolve, error) {
+	if _, err := exec.LookPath("wasm-tools"); err != nil {
+		return nil, fmt.Errorf("loading %s %s requires wasm-tools: %w", format, path, err)
+	}
+	return wit.LoadWIT(path)
+}
+
+func pullWIT(ctx context.Context, path string, opts Options) ([]byte, error) {
+	var lock *oci.Lockfile
+	var digest string
+	if opts.Lockfile != "" {
+		var err error
+		lock, err = oci.LoadLockfile(opts.Lockfile)
+		if err != nil {
+			return nil, err
+		}
+		if locked := lock.Lookup(path); locked != nil && !oci.IsPinned(path) {
+			digest = locked.Digest
+		}
+	}
+	if opts.RequireDigest && digest == "" && !oci.IsPinned(path) {
+		return nil, fmt.Errorf("OCI reference %s is not pinned by digest", path)
+	}
+
+	var cache *oci.Cache
+	var a *oci.Artifact
+	if opts.CacheDir != "" {
+		cache = &oci.Cache{Dir: opts.CacheDir}
+		if pinned := cmp.Or(digest, oci.PinnedDigest(path)); pinned != "" {
+			var err error
+			a, err = cache.Get(path, pinned)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if a != nil {
+		fmt.Fprintf(os.Stderr, "Loaded OCI artifact %s (%s) from cache\n", path, a.Digest)
+	} else {
+		if digest != "" {
+			fmt.Fprintf(os.Stderr, "Fetching OCI artifact %s (%s)\n", path, digest)
 		} else {
-			return wit.ParseWIT(bytes)
+			fmt.Fprintf(os.Stderr, "Fetching OCI artifact %s\n", path)
+		}
+		var err error
+		a, err = oci.Pull(ctx, path, digest)
+		if err != nil {
+			return nil, err
+		}
+		if cache != nil {
+			err = cache.Put(a)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if lock != nil {
+		if locked := lock.Lookup(path); locked != nil && locked.Layer != a.Layer {
+			return nil, fmt.Errorf("OCI artifact %s layer digest %s does not match lockfile digest %s", path, a.Layer, locked.Layer)
+		}
+		if lock.Record(*a) {
+			fmt.Fprintf(os.Stderr, "Recording OCI artifact %s (%s) in %s\n", path, a.Digest, opts.Lockfile)
+			err := lock.Save(opts.Lockfile)
+			if err != nil {
+				return nil, err
+			}
 		}
 	}
-	if forceWIT || !strings.HasSuffix(path, ".json") {
-		return wit.LoadWIT(path)
+	return a.Content, nil
+}
+
+// Fetch pulls the WIT packages at OCI references refs and merges them into a single
+// [wit.Resolve] with [wit.Merge]. Each artifact is a WebAssembly-encoded WIT package, which
+// includes the definitions of the dependencies it uses, so the Resolve is complete without
+// a deps directory. Artifacts are pinned, verified, and cached as described in [Options].
+func Fetch(ctx context.Context, refs []string, opts Options) (*wit.Resolve, error) {
+	if len(refs) == 0 {
+		return nil, errors.New("no OCI references")
+	}
+	var res *wit.Resolve
+	for _, ref := range refs {
+		if !oci.IsOCIPath(ref) {
+			return nil, fmt.Errorf("%s is not an OCI reference", ref)
+		}
+		r, err := load(ctx, ref, nil, opts)
+		if err != nil {
+			return nil, err
+		}
+		if res == nil {
+			res = r
+			continue
+		}
+		res, err = wit.Merge(res, r)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", ref, err)
+		}
+	}
+	return res, nil
+}
+
+// WriteDeps writes each package in [wit.Resolve] res as WIT text to its own directory in dir,
+// e.g. dir/wasi-io-0.2.0/package.wit, in the layout of a WIT deps directory.
+// It returns the paths of the written files.
+func WriteDeps(dir string, res *wit.Resolve) ([]string, error) {
+	var paths []string
+	for _, pkg := range res.Packages {
+		name := pkg.Name.Namespace + "-" + pkg.Name.Package
+		if pkg.Name.Version != nil {
+			name += "-" + pkg.Name.Version.String()
+		}
+		pkgDir := filepath.Join(dir, bindgen.PathSegment(name))
+		err := os.MkdirAll(pkgDir, 0o755)
+		if err != nil {
+			return nil, err
+		}
+		path := filepath.Join(pkgDir, "package.wit")
+		err = os.WriteFile(path, []byte(pkg.WIT(nil, "")), 0o644)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
 	}
-	return wit.LoadJSON(path)
+	return paths, nil
 }
 
 // LoadPath parses paths and returns the first path.
@@ -0,0 +1,166 @@
+package witcli
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bytecodealliance/wasm-tools-go/internal/oci"
+)
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		content string
+		want    Format
+	}{
+		{"", FormatWIT},
+		{"{}", FormatJSON},
+		{"\n\t {\"worlds\": []}", FormatJSON},
+		{"package foo:bar;", FormatWIT},
+		{"\x00asm\x0d\x00\x01\x00", FormatWasm},
+	}
+	for _, tt := range tests {
+		if got := DetectFormat([]byte(tt.content)); got != tt.want {
+			t.Errorf("DetectFormat(%q): %v, expected %v", tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	const testdata = "../../testdata/wasi/cli.wit.json"
+	content, err := os.ReadFile(testdata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A JSON file without a .json extension
+	noext := filepath.Join(t.TempDir(), "resolve")
+	err = os.WriteFile(noext, content, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		path  string
+		stdin []byte
+	}{
+		{"json file", testdata, nil},
+		{"json file without extension", noext, nil},
+		{"json stdin", "-", content},
+		{"json stdin with empty path", "", content},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := load(context.Background(), tt.path, bytes.NewReader(tt.stdin), Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(res.Worlds) == 0 {
+				t.Error("no worlds loaded")
+			}
+		})
+	}
+}
+
+// testWasm is a WIT package encoded as a component: package foo:bar; interface baz { f: func() -> u32; }
+var testWasm = []byte("\x00asm\x0d\x00\x01\x00\x07!\x01A\x02\x01B\x02\x01@\x00\x00y\x04\x00\x01f\x01\x00\x04\x00\x0bfoo:bar/baz\x05\x00\x0b\x09\x01\x00\x03baz\x03\x00\x00")
+
+func TestLoadWasm(t *testing.T) {
+	content := testWasm
+	path := filepath.Join(t.TempDir(), "foo.wasm")
+	err := os.WriteFile(path, content, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		path  string
+		stdin []byte
+	}{
+		{"wasm file", path, nil},
+		{"wasm stdin", "-", content},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := load(context.Background(), tt.path, bytes.NewReader(tt.stdin), Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(res.Interfaces) != 1 || res.Interfaces[0].Functions.Get("f") == nil {
+				t.Error("interface foo:bar/baz not loaded")
+			}
+		})
+	}
+}
+
+func TestLoadWITWithoutWasmTools(t *testing.T) {
+	if _, err := exec.LookPath("wasm-tools"); err == nil {
+		// t.Skip is not available in TinyGo, requires runtime.Goexit()
+		return
+	}
+	_, err := load(context.Background(), "-", bytes.NewReader([]byte("package foo:bar;")), Options{})
+	if err == nil {
+		t.Error("expected error loading WIT without wasm-tools")
+	}
+	_, err = load(context.Background(), t.TempDir(), nil, Options{})
+	if err == nil {
+		t.Error("expected error loading WIT directory without wasm-tools")
+	}
+}
+
+func TestFetchCached(t *testing.T) {
+	sha := func(b []byte) string {
+		sum := sha256.Sum256(b)
+		return "sha256:" + hex.EncodeToString(sum[:])
+	}
+	dir := t.TempDir()
+	digest := sha([]byte("manifest"))
+	ref := "registry.invalid/foo/bar@" + digest
+	err := (&oci.Cache{Dir: dir}).Put(&oci.Artifact{Ref: ref, Digest: digest, Layer: sha(testWasm), Content: testWasm})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The artifact is pinned by digest and cached, so it is loaded without the network.
+	res, err := Fetch(context.Background(), []string{ref}, Options{CacheDir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Interfaces) != 1 || res.Interfaces[0].Functions.Get("f") == nil {
+		t.Error("interface foo:bar/baz not loaded")
+	}
+
+	_, err = Fetch(context.Background(), []string{"registry.invalid/foo/bar:1.0.0"}, Options{CacheDir: dir, RequireDigest: true})
+	if err == nil {
+		t.Error("Fetch: expected error for unpinned reference with RequireDigest")
+	}
+}
+
+func TestWriteDeps(t *testing.T) {
+	res, err := Load(context.Background(), "../../testdata/wasi/cli.wit.json", Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	paths, err := WriteDeps(dir, res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != len(res.Packages) {
+		t.Errorf("WriteDeps: wrote %d files, expected %d", len(paths), len(res.Packages))
+	}
+	content, err := os.ReadFile(filepath.Join(dir, "wasi-io-0.2.0", "package.wit"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(content), "package wasi:io@0.2.0;") {
+		t.Errorf("WriteDeps: unexpected content:\n%s", content)
+	}
+}
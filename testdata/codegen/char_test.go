@@ -0,0 +1,46 @@
+package chars
+
+// This file is copied into the Go package generated from char.wit.json
+// by TestGenerateTestdataBehavior in package wit/bindgen.
+
+import (
+	"testing"
+
+	"github.com/bytecodealliance/wasm-tools-go/cm"
+)
+
+// mustPanic calls f and fails t if it does not panic.
+func mustPanic(t *testing.T, name string, f func()) {
+	t.Helper()
+	defer func() {
+		if recover() == nil {
+			t.Errorf("%s: expected panic", name)
+		}
+	}()
+	f()
+}
+
+func TestCharCheck(t *testing.T) {
+	var chars []uint32
+	cm.RegisterImport("foo:foo/chars", "take-char", func(x0 uint32) { chars = append(chars, x0) })
+	defer cm.RegisterImport("foo:foo/chars", "take-char", nil)
+	var result uint32
+	cm.RegisterImport("foo:foo/chars", "return-char", func() uint32 { return result })
+	defer cm.RegisterImport("foo:foo/chars", "return-char", nil)
+
+	TakeChar('x')
+	mustPanic(t, "TakeChar(0xD800)", func() { TakeChar(0xD800) })
+	mustPanic(t, "TakeChar(0x110000)", func() { TakeChar(0x110000) })
+	if len(chars) != 1 || chars[0] != 'x' {
+		t.Errorf("take-char called with %v, expected ['x']", chars)
+	}
+
+	result = 'y'
+	if got := ReturnChar(); got != 'y' {
+		t.Errorf("ReturnChar: %q, expected 'y'", got)
+	}
+	result = 0xDFFF
+	mustPanic(t, "ReturnChar with surrogate", func() { ReturnChar() })
+	result = 0xFFFFFFFF
+	mustPanic(t, "ReturnChar with out of range char", func() { ReturnChar() })
+}
@@ -0,0 +1,39 @@
+package manyarg
+
+// This file is copied into the Go package generated from many-arguments.wit.json
+// by TestGenerateTestdataBehavior in package wit/bindgen.
+
+import (
+	"testing"
+
+	"github.com/bytecodealliance/wasm-tools-go/cm"
+)
+
+func TestArenaParams(t *testing.T) {
+	// DefaultArena is nil on hosts other than WebAssembly.
+	defer func(a *cm.Arena) { cm.DefaultArena = a }(cm.DefaultArena)
+	cm.DefaultArena = cm.NewArena(4096)
+
+	var got BigStruct
+	var arenaLen int
+	cm.RegisterImport("many:arguments/manyarg", "big-argument", func(x *BigStruct) {
+		got = *x
+		arenaLen = cm.DefaultArena.Len()
+	})
+	defer cm.RegisterImport("many:arguments/manyarg", "big-argument", nil)
+
+	want := BigStruct{A1: "a1", A10: "a10", A20: "a20"}
+	mark := cm.DefaultArena.Mark()
+	BigArgument(want)
+	if got != want {
+		t.Errorf("big-argument called with %+v, expected %+v", got, want)
+	}
+
+	// The params are allocated in the arena for the duration of the call.
+	if arenaLen <= mark {
+		t.Errorf("arena length during call: %d, expected more than %d", arenaLen, mark)
+	}
+	if n := cm.DefaultArena.Len(); n != mark {
+		t.Errorf("arena length after call: %d, expected %d", n, mark)
+	}
+}
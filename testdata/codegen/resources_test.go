@@ -0,0 +1,57 @@
+package exports
+
+// This file is copied into the Go package generated from resources.wit.json
+// by TestGenerateTestdataBehavior in package wit/bindgen.
+
+import (
+	"testing"
+
+	"github.com/bytecodealliance/wasm-tools-go/cm"
+)
+
+type testX struct {
+	a         float64
+	destroyed bool
+}
+
+func (x *testX) GetA() float64  { return x.a }
+func (x *testX) SetA(a float64) { x.a = a }
+func (x *testX) Destructor()    { x.destroyed = true }
+
+func TestResourceTable(t *testing.T) {
+	var reps []uint32
+	cm.RegisterImport("[export]exports", "[resource-new]x", func(rep0 uint32) uint32 {
+		reps = append(reps, rep0)
+		return 1
+	})
+	defer cm.RegisterImport("[export]exports", "[resource-new]x", nil)
+
+	impl := &testX{a: 1.5}
+	if got := NewX(impl); got != 1 {
+		t.Errorf("NewX: handle %d, expected 1", got)
+	}
+	if len(reps) != 1 {
+		t.Fatalf("resource-new called %d times, expected 1", len(reps))
+	}
+	rep := cm.Rep(reps[0])
+	if got, ok := XTable.Get(rep); !ok || got != impl {
+		t.Errorf("XTable.Get(%d): %v, %t, expected %v, true", rep, got, ok, impl)
+	}
+
+	// Exported methods are dispatched to the XImpl in XTable.
+	if got := Exports.X.GetA(rep); got != 1.5 {
+		t.Errorf("Exports.X.GetA: %v, expected 1.5", got)
+	}
+	Exports.X.SetA(rep, 2.5)
+	if impl.a != 2.5 {
+		t.Errorf("Exports.X.SetA(2.5): a == %v", impl.a)
+	}
+
+	Exports.X.Destructor(rep)
+	if !impl.destroyed {
+		t.Error("Exports.X.Destructor did not call Destructor")
+	}
+	if _, ok := XTable.Get(rep); ok {
+		t.Errorf("XTable.Get(%d) after Destructor: found", rep)
+	}
+}
@@ -0,0 +1,29 @@
+package enums
+
+// This file is copied into the Go package generated from simple-enum.wit.json
+// by TestGenerateTestdataBehavior in package wit/bindgen.
+
+import (
+	"testing"
+
+	"github.com/bytecodealliance/wasm-tools-go/cm"
+)
+
+func TestEnumCheck(t *testing.T) {
+	var result uint32
+	cm.RegisterImport("foo:foo/enums", "e1-ret", func(x0 uint32) uint32 { return result })
+	defer cm.RegisterImport("foo:foo/enums", "e1-ret", nil)
+
+	result = 1
+	if got := E1Ret(E1A); got != E2Else {
+		t.Errorf("E1Ret: %v, expected %v", got, E2Else)
+	}
+
+	result = 2
+	defer func() {
+		if recover() == nil {
+			t.Error("E1Ret with out of range case: expected panic")
+		}
+	}()
+	E1Ret(E1A)
+}
@@ -0,0 +1,257 @@
+package values
+
+// This file is copied into the Go package generated from values.wit.json
+// by TestGenerateTestdataBehavior in package wit/bindgen.
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/bytecodealliance/wasm-tools-go/cm"
+	"github.com/bytecodealliance/wasm-tools-go/cm/cmjson"
+)
+
+var testHeader = Header{
+	Color:       ColorBlue,
+	Permissions: PermissionsRead | PermissionsExec,
+	Size:        0x1234,
+	ID:          0x0102030405060708,
+}
+
+func testItems() []Item {
+	return []Item{
+		{},
+		{
+			Name:    "item",
+			Tags:    cm.ToList([]string{"a", "b"}),
+			Header:  testHeader,
+			Shape:   ShapeLabel("label"),
+			Maybe:   cm.Some[uint32](7),
+			Outcome: cm.OK[cm.Result[string, uint32, string]](42),
+		},
+		{
+			Name:    "circle",
+			Shape:   ShapeCircle(3),
+			Outcome: cm.Err[cm.Result[string, uint32, string]]("failed"),
+		},
+	}
+}
+
+func TestJSON(t *testing.T) {
+	tests := []struct {
+		v    any
+		want string
+	}{
+		{ColorGreen, `"green"`},
+		{ShapeNone(), `{"none":null}`},
+		{ShapeCircle(3), `{"circle":3}`},
+		{ShapeLabel("x"), `{"label":"x"}`},
+		{cmjson.Option[uint32](cm.Some[uint32](7)), `7`},
+		{cmjson.Option[uint32](cm.None[uint32]()), `null`},
+	}
+	for _, tt := range tests {
+		b, err := json.Marshal(tt.v)
+		if err != nil {
+			t.Errorf("json.Marshal(%v): %v", tt.v, err)
+			continue
+		}
+		if string(b) != tt.want {
+			t.Errorf("json.Marshal(%v): %s, expected %s", tt.v, b, tt.want)
+		}
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	for _, want := range testItems() {
+		b, err := json.Marshal(want)
+		if err != nil {
+			t.Errorf("json.Marshal: %v", err)
+			continue
+		}
+		var got Item
+		err = json.Unmarshal(b, &got)
+		if err != nil {
+			t.Errorf("json.Unmarshal(%s): %v", b, err)
+			continue
+		}
+		if !got.Equal(want) {
+			t.Errorf("json round trip of %s: %+v, expected %+v", b, got, want)
+		}
+	}
+}
+
+func TestValueRoundTrip(t *testing.T) {
+	for _, want := range testItems() {
+		var got Item
+		err := got.FromValue(want.ToValue())
+		if err != nil {
+			t.Errorf("FromValue: %v", err)
+			continue
+		}
+		if !got.Equal(want) {
+			t.Errorf("value round trip: %+v, expected %+v", got, want)
+		}
+	}
+
+	var c Color
+	if err := c.FromValue(ColorBlue.ToValue()); err != nil || c != ColorBlue {
+		t.Errorf("Color value round trip: %v, %v, expected %v", c, err, ColorBlue)
+	}
+}
+
+func TestMarshalBinary(t *testing.T) {
+	// Canonical ABI layout of record header: color (u8) at offset 0,
+	// permissions (u8) at 1, size (u16) at 2, 4 bytes of padding, then id (u64) at 8.
+	want := []byte{
+		2, 0b101, 0x34, 0x12, 0, 0, 0, 0,
+		0x08, 0x07, 0x06, 0x05, 0x04, 0x03, 0x02, 0x01,
+	}
+	got, err := testHeader.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("MarshalBinary: %x, expected %x", got, want)
+	}
+
+	var h Header
+	err = h.UnmarshalBinary(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h != testHeader {
+		t.Errorf("UnmarshalBinary: %+v, expected %+v", h, testHeader)
+	}
+}
+
+func TestDeepCopy(t *testing.T) {
+	tags := []string{"a", "b"}
+	item := Item{Tags: cm.ToList(tags), Shape: ShapeLabel("label")}
+	c := item.DeepCopy()
+	tags[0] = "z"
+	if got := c.Tags.Slice()[0]; got != "a" {
+		t.Errorf("DeepCopy: Tags[0] == %q after modifying original, expected %q", got, "a")
+	}
+	if !c.Shape.Equal(item.Shape) {
+		t.Errorf("DeepCopy: Shape %v, expected %v", c.Shape, item.Shape)
+	}
+}
+
+func TestNullAccessors(t *testing.T) {
+	var item Item
+	if n := item.MaybeNull(); n.Valid {
+		t.Errorf("MaybeNull: %+v, expected invalid", n)
+	}
+	item.SetMaybeNull(cm.Null[uint32]{V: 7, Valid: true})
+	if got, want := item.Maybe, cm.Some[uint32](7); got != want {
+		t.Errorf("SetMaybeNull: Maybe == %v, expected %v", got, want)
+	}
+	if got, want := item.MaybeNull(), (cm.Null[uint32]{V: 7, Valid: true}); got != want {
+		t.Errorf("MaybeNull: %+v, expected %+v", got, want)
+	}
+	item.SetMaybeNull(cm.Null[uint32]{})
+	if !item.Maybe.None() {
+		t.Errorf("SetMaybeNull: Maybe == %v, expected none", item.Maybe)
+	}
+}
+
+func TestCases(t *testing.T) {
+	if got, want := ColorCases(), []Color{ColorRed, ColorGreen, ColorBlue}; !slices.Equal(got, want) {
+		t.Errorf("ColorCases: %v, expected %v", got, want)
+	}
+	if got, want := ShapeCases(), []ShapeCase{ShapeCaseNone, ShapeCaseCircle, ShapeCaseLabel}; !slices.Equal(got, want) {
+		t.Errorf("ShapeCases: %v, expected %v", got, want)
+	}
+	for _, tt := range []struct {
+		v    Shape
+		want ShapeCase
+	}{
+		{ShapeNone(), ShapeCaseNone},
+		{ShapeCircle(3), ShapeCaseCircle},
+		{ShapeLabel("x"), ShapeCaseLabel},
+	} {
+		if got := tt.v.Case(); got != tt.want {
+			t.Errorf("%v.Case: %d, expected %d", tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestParseEnum(t *testing.T) {
+	for _, c := range ColorCases() {
+		got, ok := ParseColor(c.String())
+		if !ok || got != c {
+			t.Errorf("ParseColor(%q): %v, %t, expected %v, true", c.String(), got, ok, c)
+		}
+		if !c.IsValid() {
+			t.Errorf("%v.IsValid: false, expected true", c)
+		}
+	}
+	if _, ok := ParseColor("purple"); ok {
+		t.Errorf("ParseColor(%q): true, expected false", "purple")
+	}
+	if Color(len(ColorCases())).IsValid() {
+		t.Errorf("Color(%d).IsValid: true, expected false", len(ColorCases()))
+	}
+
+	var c Color
+	if err := c.UnmarshalText([]byte("green")); err != nil || c != ColorGreen {
+		t.Errorf("UnmarshalText(%q): %v, %v, expected %v", "green", c, err, ColorGreen)
+	}
+	if err := c.UnmarshalText([]byte("purple")); err == nil {
+		t.Errorf("UnmarshalText(%q): expected error", "purple")
+	}
+}
+
+func TestConstructors(t *testing.T) {
+	h := NewHeader(testHeader.Color, testHeader.Permissions, testHeader.Size, testHeader.ID)
+	if h != testHeader {
+		t.Errorf("NewHeader: %+v, expected %+v", h, testHeader)
+	}
+	if got := h.FieldsZero(); len(got) != 0 {
+		t.Errorf("FieldsZero: %v, expected none", got)
+	}
+	h = NewHeader(ColorRed, 0, 1, 0)
+	if got, want := h.FieldsZero(), []string{"color", "permissions", "id"}; !slices.Equal(got, want) {
+		t.Errorf("FieldsZero: %v, expected %v", got, want)
+	}
+
+	item := NewItem("item", cm.List[string]{}, testHeader, ShapeCircle(3), cm.None[uint32](), cm.Result[string, uint32, string]{})
+	if got, want := item.FieldsZero(), []string{"tags", "maybe", "outcome"}; !slices.Equal(got, want) {
+		t.Errorf("FieldsZero: %v, expected %v", got, want)
+	}
+}
+
+func TestTypeInfo(t *testing.T) {
+	pkgPath := reflect.TypeFor[Color]().PkgPath()
+	if got, want := cm.LookupType(pkgPath, "Color"), WITTypes["Color"]; got == nil || got != want {
+		t.Errorf("cm.LookupType(%q, %q): %v, expected %v", pkgPath, "Color", got, want)
+	}
+	tests := []struct {
+		v     cm.Describable
+		name  string
+		kind  cm.Kind
+		cases []string
+		tag   uint64
+	}{
+		{ColorBlue, "foo:foo/values#color", cm.KindEnum, []string{"red", "green", "blue"}, 2},
+		{PermissionsRead | PermissionsExec, "foo:foo/values#permissions", cm.KindFlags, []string{"read", "write", "exec"}, 0b101},
+		{testHeader, "foo:foo/values#header", cm.KindRecord, []string{"color", "permissions", "size", "id"}, 0},
+		{ShapeLabel("x"), "foo:foo/values#shape", cm.KindVariant, []string{"none", "circle", "label"}, 2},
+	}
+	for _, tt := range tests {
+		info, tag := tt.v.WITType()
+		if info == nil {
+			t.Errorf("%T.WITType: nil TypeInfo", tt.v)
+			continue
+		}
+		if info.Name != tt.name || info.Kind != tt.kind || !slices.Equal(info.Cases, tt.cases) || tag != tt.tag {
+			t.Errorf("%T.WITType: %+v, %d, expected {Name:%s Kind:%v Cases:%v}, %d", tt.v, *info, tag, tt.name, tt.kind, tt.cases, tt.tag)
+		}
+	}
+	if got, want := cm.Describe(ShapeCircle(3)), "shape::circle"; got != want {
+		t.Errorf("cm.Describe: %q, expected %q", got, want)
+	}
+}
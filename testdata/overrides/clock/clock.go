@@ -0,0 +1,15 @@
+// Package clock declares the functions that convert between time.Time and
+// the wasi:clocks/wall-clock#datetime record in tests of type overrides.
+package clock
+
+import "time"
+
+// FromDatetime returns the time.Time for a datetime record.
+func FromDatetime(seconds uint64, nanoseconds uint32) time.Time {
+	return time.Unix(int64(seconds), int64(nanoseconds))
+}
+
+// ToDatetime returns the fields of the datetime record for t.
+func ToDatetime(t time.Time) (seconds uint64, nanoseconds uint32) {
+	return uint64(t.Unix()), uint32(t.Nanosecond())
+}
@@ -0,0 +1,89 @@
+package random
+
+// This file is copied into the Go package generated from cli.wit.json
+// by TestGenerateTestdataBehavior in package wit/bindgen.
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/bytecodealliance/wasm-tools-go/cm"
+)
+
+func TestStubs(t *testing.T) {
+	cm.RegisterImport("wasi:random/random@0.2.0", "get-random-u64", func() uint64 { return 42 })
+	defer cm.RegisterImport("wasi:random/random@0.2.0", "get-random-u64", nil)
+	if got, want := GetRandomU64(), uint64(42); got != want {
+		t.Errorf("GetRandomU64: %d, expected %d", got, want)
+	}
+
+	cm.RegisterImport("wasi:random/random@0.2.0", "get-random-bytes", func(len0 uint64, result *cm.List[uint8]) {
+		*result = cm.ToList(make([]uint8, len0))
+	})
+	defer cm.RegisterImport("wasi:random/random@0.2.0", "get-random-bytes", nil)
+	if got, want := GetRandomBytes(3).Len(), uintptr(3); got != want {
+		t.Errorf("GetRandomBytes(3): %d bytes, expected %d", got, want)
+	}
+}
+
+func TestMocks(t *testing.T) {
+	cm.RegisterImport("wasi:random/random@0.2.0", "get-random-u64", func() uint64 { return 42 })
+	defer cm.RegisterImport("wasi:random/random@0.2.0", "get-random-u64", nil)
+	Mocks.GetRandomU64 = func() uint64 { return 7 }
+	defer func() { Mocks = MocksInstance{} }()
+	if got, want := GetRandomU64(), uint64(7); got != want {
+		t.Errorf("GetRandomU64: %d, expected mock result %d", got, want)
+	}
+}
+
+type testHook struct {
+	calls []*cm.Call
+	after int
+}
+
+func (h *testHook) Before(call *cm.Call) {
+	h.calls = append(h.calls, call)
+	if call.Name == "get-random-bytes" {
+		*call.Results[0].(*cm.List[uint8]) = cm.ToList([]uint8{1, 2, 3})
+		call.Skip = true
+	}
+}
+
+func (h *testHook) After(call *cm.Call) {
+	h.after++
+}
+
+func TestCallHook(t *testing.T) {
+	h := &testHook{}
+	cm.SetCallHook(h)
+	defer cm.SetCallHook(nil)
+
+	// The hook skips the call, so no import or mock is called.
+	got := GetRandomBytes(3)
+	if want := []uint8{1, 2, 3}; !slices.Equal(got.Slice(), want) {
+		t.Errorf("GetRandomBytes(3): %v, expected %v", got.Slice(), want)
+	}
+	if len(h.calls) != 1 || h.after != 1 {
+		t.Fatalf("hook called %d times before, %d times after, expected 1", len(h.calls), h.after)
+	}
+	call := h.calls[0]
+	if call.Module != "wasi:random/random@0.2.0" || call.Name != "get-random-bytes" {
+		t.Errorf("Call: %s %s, expected wasi:random/random@0.2.0 get-random-bytes", call.Module, call.Name)
+	}
+	if want := []any{uint64(3)}; !slices.Equal(call.Params, want) {
+		t.Errorf("Call.Params: %v, expected %v", call.Params, want)
+	}
+
+	// The hook observes calls that it does not skip.
+	Mocks.GetRandomU64 = func() uint64 { return 7 }
+	defer func() { Mocks = MocksInstance{} }()
+	if got, want := GetRandomU64(), uint64(7); got != want {
+		t.Errorf("GetRandomU64: %d, expected %d", got, want)
+	}
+	if len(h.calls) != 2 || h.after != 2 {
+		t.Errorf("hook called %d times before, %d times after, expected 2", len(h.calls), h.after)
+	}
+	if got := *h.calls[1].Results[0].(*uint64); got != 7 {
+		t.Errorf("Call.Results[0] after call: %d, expected 7", got)
+	}
+}
@@ -0,0 +1,70 @@
+package streams
+
+// This file is copied into the Go package generated from cli.wit.json
+// by TestGenerateTestdataBehavior in package wit/bindgen.
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bytecodealliance/wasm-tools-go/cm"
+)
+
+// mustPanic calls f and returns the value it panics with, or fails t if it does not panic.
+func mustPanic(t *testing.T, f func()) (v any) {
+	t.Helper()
+	defer func() {
+		v = recover()
+		if v == nil {
+			t.Error("expected panic")
+		}
+	}()
+	f()
+	return nil
+}
+
+func TestDebugHandles(t *testing.T) {
+	var drops []uint32
+	cm.RegisterImport("wasi:io/streams@0.2.0", "[resource-drop]input-stream", func(self0 uint32) {
+		drops = append(drops, self0)
+	})
+	defer cm.RegisterImport("wasi:io/streams@0.2.0", "[resource-drop]input-stream", nil)
+	cm.RegisterImport("wasi:io/streams@0.2.0", "[method]input-stream.blocking-read", func(self0 uint32, len0 uint64, result *cm.Result[cm.List[uint8], cm.List[uint8], StreamError]) {
+		*result = cm.OK[cm.Result[cm.List[uint8], cm.List[uint8], StreamError]](cm.ToList(make([]uint8, len0)))
+	})
+	defer cm.RegisterImport("wasi:io/streams@0.2.0", "[method]input-stream.blocking-read", nil)
+
+	s := InputStream(100)
+	if result := s.BlockingRead(2); result.IsErr() || result.OK().Len() != 2 {
+		t.Errorf("BlockingRead(2): %v, expected 2 bytes", result)
+	}
+	s.ResourceDrop()
+	if len(drops) != 1 || drops[0] != 100 {
+		t.Errorf("dropped handles %v, expected [100]", drops)
+	}
+
+	v := mustPanic(t, func() { s.BlockingRead(2) })
+	if msg, _ := v.(string); !strings.Contains(msg, "use of dropped resource handle 100") {
+		t.Errorf("BlockingRead after ResourceDrop: panic %v", v)
+	}
+	v = mustPanic(t, func() { s.ResourceDrop() })
+	if msg, _ := v.(string); !strings.Contains(msg, "second drop of resource handle 100") {
+		t.Errorf("second ResourceDrop: panic %v", v)
+	}
+	if len(drops) != 1 {
+		t.Errorf("dropped handles %v, expected [100]", drops)
+	}
+}
+
+func TestResourceMocks(t *testing.T) {
+	Mocks.InputStream.BlockingRead = func(self InputStream, len_ uint64) cm.Result[cm.List[uint8], cm.List[uint8], StreamError] {
+		return cm.OK[cm.Result[cm.List[uint8], cm.List[uint8], StreamError]](cm.ToList([]uint8("mock")))
+	}
+	defer func() { Mocks = MocksInstance{} }()
+
+	// No import is registered, so the stub would panic if the mock were not called.
+	result := InputStream(200).BlockingRead(4)
+	if result.IsErr() || string(result.OK().Slice()) != "mock" {
+		t.Errorf("BlockingRead(4): %v, expected mock result", result)
+	}
+}
@@ -0,0 +1,79 @@
+package types
+
+// This file is copied into the Go package generated from cli.wit.json
+// by TestGenerateTestdataBehavior in package wit/bindgen.
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"unsafe"
+
+	"github.com/bytecodealliance/wasm-tools-go/cm"
+)
+
+func TestStringCheck(t *testing.T) {
+	var paths []string
+	cm.RegisterImport("wasi:filesystem/types@0.2.0", "[method]descriptor.open-at", func(self0 uint32, pathFlags0 uint32, path0 *uint8, path1 uint32, openFlags0 uint32, flags0 uint32, result *cm.Result[Descriptor, Descriptor, ErrorCode]) {
+		paths = append(paths, unsafe.String(path0, path1))
+		*result = cm.OK[cm.Result[Descriptor, Descriptor, ErrorCode]](Descriptor(1))
+	})
+	defer cm.RegisterImport("wasi:filesystem/types@0.2.0", "[method]descriptor.open-at", nil)
+
+	d := Descriptor(100)
+	if result := d.OpenAt(0, "dir/file", 0, 0); result.IsErr() {
+		t.Errorf("OpenAt: %v", result)
+	}
+	func() {
+		defer func() {
+			if v := recover(); v != cm.ErrInvalidUTF8 {
+				t.Errorf("OpenAt with invalid UTF-8 path: panic %v, expected %v", v, cm.ErrInvalidUTF8)
+			}
+		}()
+		d.OpenAt(0, "dir/\xff", 0, 0)
+	}()
+	if len(paths) != 1 || paths[0] != "dir/file" {
+		t.Errorf("paths passed to open-at: %q, expected [\"dir/file\"]", paths)
+	}
+}
+
+func TestStructTags(t *testing.T) {
+	stat := DescriptorStat{Type: DescriptorTypeRegularFile, LinkCount: 2, Size: 3}
+	b, err := json.Marshal(stat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m map[string]any
+	err = json.Unmarshal(b, &m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := m["type"], "regular-file"; got != want {
+		t.Errorf("json.Marshal: type: %v, expected %q", got, want)
+	}
+	if got, want := m["size_bytes"], 3.0; got != want {
+		t.Errorf("json.Marshal: size_bytes: %v, expected %v", got, want)
+	}
+	for _, key := range []string{"size", "link-count", "LinkCount"} {
+		if _, ok := m[key]; ok {
+			t.Errorf("json.Marshal: unexpected key %q in %s", key, b)
+		}
+	}
+
+	typ := reflect.TypeFor[DescriptorStat]()
+	for name, want := range map[string]string{
+		"Type":                "type,omitempty",
+		"LinkCount":           "link_count,omitempty",
+		"Size":                "",
+		"DataAccessTimestamp": "data_access_timestamp,omitempty",
+	} {
+		f, ok := typ.FieldByName(name)
+		if !ok {
+			t.Errorf("DescriptorStat.%s not declared", name)
+			continue
+		}
+		if got := f.Tag.Get("yaml"); got != want {
+			t.Errorf("DescriptorStat.%s: yaml tag %q, expected %q", name, got, want)
+		}
+	}
+}
@@ -267,28 +267,68 @@ const (
 // Its params and results may be [flattened] according to the Canonical ABI specification.
 // The flattening rules vary based on whether the returned function is imported or exported,
 // e.g. using go:wasmimport or go:wasmexport.
+// It is equivalent to [CoreSignature] with the default [ABIOptions].
 //
 // [Core WebAssembly function]: https://webassembly.github.io/spec/core/syntax/modules.html#syntax-func
 // [flattened]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md#flattening
 func (f *Function) CoreFunction(op Direction) *Function {
+	return CoreSignature(f, op, ABIOptions{})
+}
+
+// ABIOptions configures the Canonical ABI rules used by [CoreSignature].
+type ABIOptions struct {
+	// MaxFlatParams is the maximum number of flattened params passed directly.
+	// If zero, [MaxFlatParams] is used.
+	MaxFlatParams int
+
+	// MaxFlatResults is the maximum number of flattened results returned directly.
+	// If zero, [MaxFlatResults] is used.
+	MaxFlatResults int
+}
+
+// CoreSignature returns the [Core WebAssembly function] signature of [Function] f,
+// imported or exported according to dir, for code generators that lower WIT functions
+// to Core WebAssembly. The Canonical ABI [flattening] rules are:
+//
+//   - Each param is flattened into one or more core values, named after the param
+//     with an index suffix, e.g. a string param s becomes s0 (pointer) and s1 (length).
+//   - If the flattened params number more than opts.MaxFlatParams, they are replaced by
+//     a single [Pointer] param: to the type of the only param (named after it),
+//     or to a record of all params (named params).
+//   - Results are flattened the same way. If they number more than opts.MaxFlatResults,
+//     an exported function returns a single [Pointer] result to the results in its memory,
+//     and an imported function has no results and a final [Pointer] param (named result
+//     or results), through which the callee returns them in the caller's memory.
+//
+// The returned [Function] has the same name and kind as f. If f has no params or results, f is returned.
+//
+// [Core WebAssembly function]: https://webassembly.github.io/spec/core/syntax/modules.html#syntax-func
+// [flattening]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md#flattening
+func CoreSignature(f *Function, dir Direction, opts ABIOptions) *Function {
 	if len(f.Params) == 0 && len(f.Results) == 0 {
 		return f
 	}
+	maxParams := opts.MaxFlatParams
+	if maxParams == 0 {
+		maxParams = MaxFlatParams
+	}
+	maxResults := opts.MaxFlatResults
+	if maxResults == 0 {
+		maxResults = MaxFlatResults
+	}
 
 	// Clone the function
 	cf := *f
 
-	// Max 16 params
 	cf.Params = flattenParams(f.Params)
-	if len(cf.Params) > MaxFlatParams {
+	if len(cf.Params) > maxParams {
 		cf.Params = []Param{compoundParam("param", "params", f.Params)}
 	}
 
-	// Max 1 result
 	cf.Results = flattenParams(f.Results)
-	if len(cf.Results) > MaxFlatResults {
+	if len(cf.Results) > maxResults {
 		p := compoundParam("result", "results", f.Results)
-		if op == Exported {
+		if dir == Exported {
 			cf.Results = []Param{p}
 		} else {
 			cf.Params = append(cf.Params, p)
@@ -310,16 +350,11 @@ func flatParams(pfx string, flat []Type) []Param {
 func flattenParams(params []Param) []Param {
 	var out []Param
 	for _, p := range params {
-		flat := p.Type.Flat()
-		if len(flat) == 1 {
-			if p.Name == "" {
-				p.Name = "result"
-			}
-			out = append(out, Param{Name: p.Name + "0", Type: flat[0]})
-		} else {
-			for i, t := range flat {
-				out = append(out, Param{Name: p.Name + strconv.Itoa(i), Type: t})
-			}
+		if p.Name == "" {
+			p.Name = "result"
+		}
+		for i, t := range p.Type.Flat() {
+			out = append(out, Param{Name: p.Name + strconv.Itoa(i), Type: t})
 		}
 	}
 	return out
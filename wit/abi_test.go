@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math"
 	"reflect"
+	"sync"
 	"testing"
 )
 
@@ -119,6 +120,9 @@ func TestTypeSize(t *testing.T) {
 		{"f64", F64{}, 8, 8},
 		{"char", Char{}, 4, 4},
 		{"string", String{}, 8, 4},
+		{"record { u64, u32 }", &TypeDef{Kind: &Record{Fields: []Field{{Type: U64{}}, {Type: U32{}}}}}, 16, 8},
+		{"record { u8, u16, u8 }", &TypeDef{Kind: &Record{Fields: []Field{{Type: U8{}}, {Type: U16{}}, {Type: U8{}}}}}, 6, 2},
+		{"tuple<u32, u8>", &TypeDef{Kind: &Tuple{Types: []Type{U32{}, U8{}}}}, 8, 4},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -211,3 +215,120 @@ func TestHasBorrow(t *testing.T) {
 		})
 	}
 }
+
+func TestTypeDefABICache(t *testing.T) {
+	td := &TypeDef{Kind: U8{}}
+	if got, want := td.Size(), uintptr(1); got != want {
+		t.Errorf("Size(): %d, expected %d", got, want)
+	}
+	td.Kind = &Record{Fields: []Field{{Name: "a", Type: U8{}}, {Name: "b", Type: U64{}}}}
+	if got, want := td.Size(), uintptr(16); got != want {
+		t.Errorf("Size() after replacing Kind: %d, expected %d", got, want)
+	}
+	if got, want := td.Align(), uintptr(8); got != want {
+		t.Errorf("Align() after replacing Kind: %d, expected %d", got, want)
+	}
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got, want := len(td.Flat()), 2; got != want {
+				t.Errorf("len(Flat()): %d, expected %d", got, want)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := td.Flat(); cap(got) != len(got) {
+		t.Errorf("cap(Flat()): %d, expected %d", cap(got), len(got))
+	}
+}
+
+func BenchmarkTypeDefABI(b *testing.B) {
+	var resolves []*Resolve
+	err := loadTestdata(func(path string, res *Resolve) error {
+		resolves = append(resolves, res)
+		return nil
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, res := range resolves {
+			for _, t := range res.TypeDefs {
+				_ = t.Size()
+				_ = t.Align()
+				_ = t.Flat()
+			}
+		}
+	}
+}
+
+func TestCoreSignature(t *testing.T) {
+	params := func(n int) []Param {
+		p := make([]Param, n)
+		for i := range p {
+			p[i] = Param{Name: fmt.Sprintf("p%d", i), Type: U32{}}
+		}
+		return p
+	}
+	types := func(n int) []Type {
+		t := make([]Type, n)
+		for i := range t {
+			t[i] = U64{}
+		}
+		return t
+	}
+	sig := func(f *Function) string {
+		names := func(params []Param) string {
+			var s []string
+			for _, p := range params {
+				name := p.Name
+				if _, ok := p.Type.(*TypeDef); ok && KindOf[*Pointer](p.Type) != nil {
+					name = "*" + name
+				}
+				s = append(s, name)
+			}
+			return fmt.Sprint(s)
+		}
+		return names(f.Params) + " -> " + names(f.Results)
+	}
+	tests := []struct {
+		name string
+		f    *Function
+		dir  Direction
+		opts ABIOptions
+		want string
+	}{
+		{"flat", &Function{Params: params(2), Results: []Param{{Type: U32{}}}}, Imported, ABIOptions{}, "[p00 p10] -> [result0]"},
+		{"string param", &Function{Params: []Param{{Name: "s", Type: String{}}}}, Imported, ABIOptions{}, "[*s0 s1] -> []"},
+		{"17 params", &Function{Params: params(17)}, Imported, ABIOptions{}, "[*params] -> []"},
+		{"17 params, max 20", &Function{Params: params(17)}, Imported, ABIOptions{MaxFlatParams: 20}, "[p00 p10 p20 p30 p40 p50 p60 p70 p80 p90 p100 p110 p120 p130 p140 p150 p160] -> []"},
+		{"one compound param", &Function{Params: []Param{{Name: "t", Type: &TypeDef{Kind: &Tuple{Types: types(17)}}}}}, Exported, ABIOptions{}, "[*t] -> []"},
+		{"string result, imported", &Function{Params: params(1), Results: []Param{{Type: String{}}}}, Imported, ABIOptions{}, "[p00 *result] -> []"},
+		{"string result, exported", &Function{Params: params(1), Results: []Param{{Type: String{}}}}, Exported, ABIOptions{}, "[p00] -> [*result]"},
+		{"string result, max 2", &Function{Params: params(1), Results: []Param{{Type: String{}}}}, Imported, ABIOptions{MaxFlatResults: 2}, "[p00] -> [*result0 result1]"},
+		{"named results, imported", &Function{Results: []Param{{Name: "a", Type: U32{}}, {Name: "b", Type: U32{}}}}, Imported, ABIOptions{}, "[*results] -> []"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CoreSignature(tt.f, tt.dir, tt.opts)
+			if s := sig(got); s != tt.want {
+				t.Errorf("CoreSignature: %s, expected %s", s, tt.want)
+			}
+			if tt.opts == (ABIOptions{}) {
+				if s := sig(tt.f.CoreFunction(tt.dir)); s != tt.want {
+					t.Errorf("CoreFunction: %s, expected %s", s, tt.want)
+				}
+			}
+		})
+	}
+
+	f := &Function{Name: "f"}
+	if got := CoreSignature(f, Imported, ABIOptions{}); got != f {
+		t.Errorf("CoreSignature(f) with no params or results: %v, expected f", got)
+	}
+}
@@ -0,0 +1,43 @@
+package bindgen
+
+import (
+	"strings"
+
+	"github.com/bytecodealliance/wasm-tools-go/internal/go/gen"
+	"github.com/bytecodealliance/wasm-tools-go/internal/stringio"
+	"github.com/bytecodealliance/wasm-tools-go/wit"
+)
+
+// binaryMethods returns Go source for the MarshalBinary and UnmarshalBinary methods
+// of type goName, which encode values in the Canonical ABI memory layout of [wit.TypeDef] t.
+// Types that contain pointers or resource handles, types without a Canonical ABI
+// representation, and records with a field that would collide with either method,
+// have no binary marshaling methods.
+func (g *generator) binaryMethods(file *gen.File, t *wit.TypeDef, goName string) string {
+	if t.Size() == 0 || wit.HasPointer(t) || wit.HasResource(t) {
+		return ""
+	}
+	switch kind := t.Kind.(type) {
+	case *wit.Future, *wit.Stream:
+		return ""
+	case *wit.Record:
+		for _, f := range kind.Fields {
+			switch g.fieldName(f.Name, true) {
+			case "MarshalBinary", "UnmarshalBinary":
+				return ""
+			}
+		}
+	}
+
+	cm := file.Import(g.opts.cmPackage)
+	var b strings.Builder
+	b.WriteString(formatDocComments("MarshalBinary implements [encoding.BinaryMarshaler], encoding v in its Canonical ABI memory layout.", true))
+	stringio.Write(&b, "func (v ", goName, ") MarshalBinary() ([]byte, error) {\n")
+	stringio.Write(&b, "return ", cm, ".MarshalBinary(&v)\n")
+	b.WriteString("}\n\n")
+	b.WriteString(formatDocComments("UnmarshalBinary implements [encoding.BinaryUnmarshaler], decoding data in the Canonical ABI memory layout of "+goName+" into v.", true))
+	stringio.Write(&b, "func (v *", goName, ") UnmarshalBinary(data []byte) error {\n")
+	stringio.Write(&b, "return ", cm, ".UnmarshalBinary(v, data)\n")
+	b.WriteString("}\n\n")
+	return b.String()
+}
@@ -0,0 +1,290 @@
+// Package bindgentest implements regression tests for Go code generated by package bindgen.
+// Programs that embed the generator can use it to test generated bindings for their own WIT:
+//
+//	var update = flag.Bool("update", false, "update golden files")
+//
+//	func TestBindings(t *testing.T) {
+//		bindgentest.Check(t, "wit/app.wit.json", bindgentest.Options{
+//			Dir:    "generated",
+//			Golden: "testdata/golden",
+//			Update: *update,
+//		})
+//	}
+package bindgentest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/bytecodealliance/wasm-tools-go/internal/codec"
+	"github.com/bytecodealliance/wasm-tools-go/internal/go/gen"
+	"github.com/bytecodealliance/wasm-tools-go/wit"
+	"github.com/bytecodealliance/wasm-tools-go/wit/bindgen"
+)
+
+// Options configure [Check].
+type Options struct {
+	// Dir is a directory in a Go module that can import the Component Model utility
+	// package, such as a module that requires github.com/bytecodealliance/wasm-tools-go.
+	// Generated packages are type-checked as if they were in Dir, but are not written to it.
+	// Dir is created if it does not exist.
+	Dir string
+
+	// Golden is the directory of golden files, with one file per generated file,
+	// at the same path relative to Golden as the generated file is relative to Dir.
+	// If empty, generated files are not compared with golden files.
+	Golden string
+
+	// Update writes the generated files to Golden instead of comparing them,
+	// removing golden files that are no longer generated. It is typically set by an -update flag.
+	Update bool
+
+	// Options are passed to [bindgen.Go] after the options set by [Generate].
+	Options []bindgen.Option
+}
+
+// Check generates Go packages from the WIT JSON file at path with [Generate],
+// checks that they type-check with [TypeCheck], and compares them with golden files
+// with [CompareGolden]. It reports failures with t and returns the generated packages.
+func Check(t testing.TB, path string, opts Options) []*gen.Package {
+	t.Helper()
+	pkgs := Generate(t, path, opts.Dir, opts.Options...)
+	if pkgs == nil {
+		return nil
+	}
+	TypeCheck(t, opts.Dir, pkgs)
+	if opts.Golden != "" {
+		CompareGolden(t, opts.Dir, opts.Golden, pkgs, opts.Update)
+	}
+	return pkgs
+}
+
+// Generate generates Go packages from the WIT JSON file at path, rooted at the
+// Go package path of directory dir, with opts. It returns nil after reporting
+// an error with t if the WIT cannot be loaded or Go code cannot be generated.
+func Generate(t testing.TB, path, dir string, opts ...bindgen.Option) []*gen.Package {
+	t.Helper()
+	res, err := wit.LoadJSON(path)
+	if err != nil {
+		t.Error(err)
+		return nil
+	}
+	err = res.ResolveIncludes()
+	if err != nil {
+		t.Error(err)
+		return nil
+	}
+	pkgPath, err := packagePath(dir)
+	if err != nil {
+		t.Error(err)
+		return nil
+	}
+	opts = append([]bindgen.Option{
+		bindgen.GeneratedBy("bindgentest"),
+		bindgen.PackageRoot(pkgPath),
+	}, opts...)
+	pkgs, err := bindgen.Go(res, opts...)
+	if err != nil {
+		t.Errorf("%s: %v", path, err)
+		return nil
+	}
+	return pkgs
+}
+
+// TypeCheck type-checks Go packages pkgs, generated with a package root of the
+// Go package path of directory dir, with the go command and go/types.
+// Generated files are overlaid on dir, and are not written to it. The go command runs with
+// the Go environment of dir, including GOFLAGS, go.work workspaces, and vendored dependencies.
+// It reports each package, parse, or type error with t.
+func TypeCheck(t testing.TB, dir string, pkgs []*gen.Package) {
+	t.Helper()
+	pkgPath, err := packagePath(dir)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	ctx := context.Background()
+	env, err := gen.LoadBuildEnv(ctx, abs)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	cfg := env.PackagesConfig(ctx, packages.NeedName|packages.NeedFiles|packages.NeedImports|packages.NeedTypes, "")
+	cfg.Fset = token.NewFileSet()
+	cfg.Overlay = make(map[string][]byte)
+	pkgMap := make(map[string]*gen.Package)
+	for _, pkg := range pkgs {
+		if !pkg.HasContent() {
+			continue
+		}
+		pkgMap[pkg.Path] = pkg
+		for _, file := range pkg.Files {
+			src, err := file.Bytes()
+			if err != nil {
+				t.Error(err)
+			}
+			cfg.Overlay[filepath.Join(abs, relPath(pkgPath, pkg.Path), file.Name)] = src
+		}
+	}
+	if len(pkgMap) == 0 {
+		return
+	}
+	goPackages, err := packages.Load(cfg, codec.SortedKeys(pkgMap)...)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	for _, goPkg := range goPackages {
+		for _, err := range goPkg.Errors {
+			// The go command cannot run the assembler in directories that exist only in the overlay.
+			if err.Kind == packages.ListError && err.Pos == "" {
+				continue
+			}
+			t.Errorf("%s: %v", goPkg.PkgPath, err)
+		}
+	}
+}
+
+// CompareGolden compares the files of Go packages pkgs, generated with a package root
+// of the Go package path of directory dir, with the golden files in directory golden.
+// It reports each file that differs, is missing, or is not generated with t.
+// If update is true, it writes the generated files to golden instead.
+func CompareGolden(t testing.TB, dir, golden string, pkgs []*gen.Package, update bool) {
+	t.Helper()
+	pkgPath, err := packagePath(dir)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	files := make(map[string][]byte)
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			if !file.HasContent() {
+				continue
+			}
+			b, err := file.Bytes()
+			if err != nil {
+				t.Error(err)
+			}
+			files[filepath.Join(relPath(pkgPath, pkg.Path), file.Name)] = b
+		}
+	}
+
+	var existing []string
+	err = filepath.WalkDir(golden, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(golden, path)
+		existing = append(existing, rel)
+		return err
+	})
+	if err != nil && !os.IsNotExist(err) {
+		t.Error(err)
+		return
+	}
+
+	if update {
+		for _, rel := range existing {
+			if _, ok := files[rel]; !ok {
+				err := os.Remove(filepath.Join(golden, rel))
+				if err != nil {
+					t.Error(err)
+				}
+			}
+		}
+		for rel, b := range files {
+			path := filepath.Join(golden, rel)
+			err := os.MkdirAll(filepath.Dir(path), 0o755)
+			if err == nil {
+				err = os.WriteFile(path, b, 0o644)
+			}
+			if err != nil {
+				t.Error(err)
+			}
+		}
+		return
+	}
+
+	for _, rel := range existing {
+		if _, ok := files[rel]; !ok {
+			t.Errorf("golden file %s: not generated", filepath.Join(golden, rel))
+		}
+	}
+	for _, rel := range codec.SortedKeys(files) {
+		path := filepath.Join(golden, rel)
+		if !slices.Contains(existing, rel) {
+			t.Errorf("golden file %s: missing for generated file", path)
+			continue
+		}
+		want, err := os.ReadFile(path)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if line, ok := diff(files[rel], want); !ok {
+			t.Errorf("golden file %s: generated file differs at line %d:\n%s", path, line.n, line)
+		}
+	}
+}
+
+// diffLine is the first line at which two files differ.
+type diffLine struct {
+	n         int
+	got, want string
+}
+
+func (d diffLine) String() string {
+	return fmt.Sprintf("got:  %s\nwant: %s", d.got, d.want)
+}
+
+// diff returns the first line at which got and want differ, and false, or true if they are equal.
+func diff(got, want []byte) (diffLine, bool) {
+	if bytes.Equal(got, want) {
+		return diffLine{}, true
+	}
+	gotLines := strings.Split(string(got), "\n")
+	wantLines := strings.Split(string(want), "\n")
+	for i := 0; ; i++ {
+		var d diffLine
+		d.n = i + 1
+		if i < len(gotLines) {
+			d.got = gotLines[i]
+		}
+		if i < len(wantLines) {
+			d.want = wantLines[i]
+		}
+		if d.got != d.want || i >= len(gotLines) || i >= len(wantLines) {
+			return d, false
+		}
+	}
+}
+
+// packagePath returns the Go package path of directory dir, creating dir if necessary.
+func packagePath(dir string) (string, error) {
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return "", err
+	}
+	return gen.PackagePath(dir)
+}
+
+// relPath returns the directory of Go package path relative to root, with the OS separator.
+func relPath(root, path string) string {
+	return filepath.FromSlash(strings.TrimPrefix(strings.TrimPrefix(path, root), "/"))
+}
@@ -0,0 +1,114 @@
+package bindgentest
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+const (
+	testdataPath  = "../../../testdata"
+	generatedPath = "../../../generated/bindgentest"
+)
+
+func TestCheck(t *testing.T) {
+	pkgs := Check(t, testdataPath+"/codegen/simple-enum.wit.json", Options{
+		Dir:    generatedPath,
+		Golden: "testdata/simple-enum",
+		Update: *update,
+	})
+	if len(pkgs) == 0 {
+		t.Error("Check(): no packages generated")
+	}
+}
+
+func TestCompareGolden(t *testing.T) {
+	pkgs := Generate(t, testdataPath+"/codegen/simple-enum.wit.json", generatedPath)
+	golden := t.TempDir()
+	CompareGolden(t, generatedPath, golden, pkgs, true)
+
+	// Modify, add, and remove golden files.
+	var files []string
+	err := filepath.WalkDir(golden, func(path string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			files = append(files, path)
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) < 2 {
+		t.Fatalf("CompareGolden(update): wrote %d files, expected at least 2", len(files))
+	}
+	err = os.WriteFile(files[0], []byte("package changed\n"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.Remove(files[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	extra := filepath.Join(golden, "extra.go")
+	err = os.WriteFile(extra, []byte("package extra\n"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := &recorder{TB: t}
+	CompareGolden(r, generatedPath, golden, pkgs, false)
+	for _, want := range []string{files[0] + ": generated file differs at line 1", files[1] + ": missing", extra + ": not generated"} {
+		if !strings.Contains(strings.Join(r.errors, "\n"), want) {
+			t.Errorf("CompareGolden(): expected error %q, got:\n%s", want, strings.Join(r.errors, "\n"))
+		}
+	}
+	if len(r.errors) != 3 {
+		t.Errorf("CompareGolden(): %d errors, expected 3", len(r.errors))
+	}
+
+	// Update restores the golden files.
+	CompareGolden(t, generatedPath, golden, pkgs, true)
+	r = &recorder{TB: t}
+	CompareGolden(r, generatedPath, golden, pkgs, false)
+	if len(r.errors) != 0 {
+		t.Errorf("CompareGolden() after update: %s", strings.Join(r.errors, "\n"))
+	}
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		got, want string
+		n         int
+		ok        bool
+	}{
+		{"a\nb\n", "a\nb\n", 0, true},
+		{"a\nb\n", "a\nc\n", 2, false},
+		{"a\n", "a\nb\n", 2, false},
+		{"a\nb", "a\nb\n", 3, false},
+	}
+	for _, tt := range tests {
+		d, ok := diff([]byte(tt.got), []byte(tt.want))
+		if ok != tt.ok || d.n != tt.n {
+			t.Errorf("diff(%q, %q): %d, %t, expected %d, %t", tt.got, tt.want, d.n, ok, tt.n, tt.ok)
+		}
+	}
+}
+
+// recorder records errors instead of failing the test.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Error(args ...any) {
+	r.errors = append(r.errors, fmt.Sprint(args...))
+}
@@ -0,0 +1,375 @@
+package bindgen
+
+import (
+	"fmt"
+	"go/build/constraint"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/bytecodealliance/wasm-tools-go/internal/codec"
+	"github.com/bytecodealliance/wasm-tools-go/internal/go/gen"
+	"github.com/bytecodealliance/wasm-tools-go/internal/stringio"
+)
+
+// applyBuildTags sets the //go:build constraint on generated Go and assembly files.
+// If stubs are enabled, only files with wasmimport and wasmexport declarations
+// are constrained, and stub files are constrained to the negated expression.
+func (g *generator) applyBuildTags() {
+	if g.opts.buildTags == "" {
+		return
+	}
+	for _, pkg := range g.packages {
+		for _, file := range pkg.Files {
+			switch {
+			case file.Name == "empty.s":
+				file.Content = append([]byte("//go:build "+g.opts.buildTags+"\n\n"), file.Content...)
+			case !file.IsGo() || file.GoBuild != "":
+				// Skip non-Go files and stub files
+			case !g.opts.stubs || strings.HasSuffix(file.Name, ".wasm.go"):
+				file.GoBuild = g.opts.buildTags
+			}
+		}
+	}
+}
+
+// defineStub generates a stub for the wasmimport function for decl, which calls a fake
+// registered with cm.RegisterImport, or panics when called on targets that do not
+// satisfy the build tags.
+func (g *generator) defineStub(decl *funcDecl) {
+	file := g.stubFileFor(decl.wasmFunc.file.Package)
+	cm := file.Import(g.opts.cmPackage)
+	scope := gen.NewScope(file)
+	for _, p := range decl.wasmFunc.params {
+		scope.DeclareName(p.name)
+	}
+	for _, r := range decl.wasmFunc.results {
+		scope.DeclareName(r.name)
+	}
+	fake := scope.DeclareName("fake")
+	module, name, _ := strings.Cut(decl.linkerName, " ")
+
+	var b strings.Builder
+	b.WriteString("func ")
+	if decl.wasmFunc.isMethod() {
+		stringio.Write(&b, "(", decl.wasmFunc.receiver.name, " ", g.typeRep(file, decl.wasmFunc.receiver.dir, decl.wasmFunc.receiver.typ), ") ", decl.wasmFunc.name)
+	} else {
+		b.WriteString(decl.wasmFunc.name)
+	}
+	b.WriteString(g.functionSignature(file, decl.wasmFunc))
+	b.WriteString(" {\n")
+
+	// The fake has the same signature, with any receiver as its first parameter.
+	args := make([]string, len(decl.wasmFunc.params))
+	for i, p := range decl.wasmFunc.params {
+		args[i] = p.name
+	}
+	results := decl.wasmFunc.results
+	stringio.Write(&b, "if ", fake, ", ok := ", cm, ".LookupImport(", strconv.Quote(module), ", ", strconv.Quote(name), ").(", g.funcTypeRep(file, decl.wasmFunc), "); ok {\n")
+	if len(results) > 0 {
+		b.WriteString("return ")
+	}
+	stringio.Write(&b, fake, "(", strings.Join(args, ", "), ")\n")
+	if len(results) == 0 {
+		b.WriteString("return\n")
+	}
+	b.WriteString("}\n")
+	stringio.Write(&b, "panic(", strconv.Quote("wasmimport "+decl.linkerName+" is not available on this platform"), ")\n")
+	b.WriteString("}\n\n")
+
+	file.WriteString(b.String())
+}
+
+func (g *generator) stubFileFor(pkg *gen.Package) *gen.File {
+	file := pkg.File(pkg.Name + ".stubs.go")
+	file.GeneratedBy = g.opts.generatedBy
+	if file.GoBuild == "" {
+		// BuildTags validates the expression.
+		expr, _ := constraint.Parse("//go:build " + g.opts.buildTags)
+		file.GoBuild = (&constraint.NotExpr{X: expr}).String()
+		file.Header = fmt.Sprintf("// This file contains stub wasmimport functions for targets that do not satisfy build constraint %q.\n"+
+			"// Each stub calls a fake registered with cm.RegisterImport, otherwise it panics.\n\n", g.opts.buildTags)
+	}
+	return file
+}
+
+// defineCMAPIChecks declares the minimum API level of package cm required by each
+// generated Go package that imports it, preferring a file without build constraints.
+// The level is computed from the types and functions of package cm the package uses.
+func (g *generator) defineCMAPIChecks() {
+	if g.opts.noCMAPICheck {
+		return
+	}
+	cmPath, _ := gen.ParseSelector(g.opts.cmPackage)
+	for _, pkg := range g.packages {
+		var file *gen.File
+		level := packageCMAPILevel(pkg, cmPath)
+		if level == 0 {
+			continue
+		}
+		for _, name := range codec.SortedKeys(pkg.Files) {
+			f := pkg.Files[name]
+			if !f.IsGo() || !f.HasContent() || strings.HasSuffix(f.Name, "_test.go") {
+				continue
+			}
+			if f.Trailer != "" {
+				// Content is written inside a declaration, e.g. the ExportsInstance struct.
+				continue
+			}
+			// Prefer a file without build constraints, so the check applies on all targets,
+			// then a file that already imports package cm.
+			switch {
+			case file == nil,
+				file.GoBuild != "" && f.GoBuild == "",
+				file.GoBuild == f.GoBuild && file.Imports[cmPath] == "" && f.Imports[cmPath] != "":
+				file = f
+			}
+		}
+		if file == nil {
+			continue
+		}
+		cm := file.Import(g.opts.cmPackage)
+		stringio.Write(file, "\n// This package requires API level ", strconv.Itoa(level), " or later of package ", cm, ".\n")
+		stringio.Write(file, "const _ uint = ", cm, ".APILevel - ", strconv.Itoa(level), "\n")
+	}
+}
+
+// CMAPILevel is the highest API level of package cm that generated Go code can require.
+// Each generated Go package that imports package cm declares the API level it requires,
+// computed from the types and functions of package cm it uses, which fails to compile
+// with an older package cm. See [CMAPICheck].
+const CMAPILevel = 9
+
+// cmAPILevels maps the types and functions of package cm used by generated code to
+// the API level that added them. Others require API level 1. See cm.APILevel.
+var cmAPILevels = map[string]int{
+	"LiftEnum":          2,
+	"CallHook":          3,
+	"Call":              3,
+	"SetCallHook":       3,
+	"LoadCallHook":      3,
+	"TraceImport":       4,
+	"TraceExport":       4,
+	"ResourceTable":     5,
+	"LowerStringStrict": 6,
+	"LowerStringLossy":  6,
+	"LowerChar":         7,
+	"LiftChar":          7,
+	"Clone":             8,
+	"CloneFunc":         8,
+	"Null":              9,
+	"ToNull":            9,
+	"FromNull":          9,
+}
+
+// packageCMAPILevel returns the minimum API level of package cm, with import path cmPath,
+// required by the Go files in pkg, or 0 if pkg does not import package cm.
+func packageCMAPILevel(pkg *gen.Package, cmPath string) int {
+	level := 0
+	for _, f := range pkg.Files {
+		if !f.IsGo() || !f.HasContent() || strings.HasSuffix(f.Name, "_test.go") {
+			continue
+		}
+		cm := f.Imports[cmPath]
+		if cm == "" {
+			continue
+		}
+		level = max(level, 1)
+		src := string(f.Content)
+		for {
+			i := strings.Index(src, cm+".")
+			if i < 0 {
+				break
+			}
+			before := i > 0 && isIdentRune(rune(src[i-1]))
+			src = src[i+len(cm)+1:]
+			if before {
+				continue
+			}
+			n := 0
+			for n < len(src) && isIdentRune(rune(src[n])) {
+				n++
+			}
+			level = max(level, cmAPILevels[src[:n]])
+		}
+	}
+	return level
+}
+
+func isIdentRune(r rune) bool {
+	return r == '_' || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9'
+}
+
+// GoVersion is the minimum Go version required to build generated Go code,
+// which matches the Go version required by package cm.
+const GoVersion = "1.22.0"
+
+// Build is a machine-readable description of how to build the Go packages generated by [Go],
+// so CI systems and IDEs can configure builds of generated code automatically.
+// It is serialized as JSON.
+type Build struct {
+	// Module is the Go module path, if the generated code is a standalone Go module.
+	Module string `json:"module,omitempty"`
+
+	// PackageRoot is the root Go package path of the generated packages.
+	PackageRoot string `json:"package_root,omitempty"`
+
+	// GoVersion is the minimum Go version required to build the generated packages.
+	GoVersion string `json:"go_version"`
+
+	// CMAPILevel is the minimum API level of package cm required by the generated packages,
+	// or 0 if the generated packages do not check it. See [CMAPILevel].
+	CMAPILevel int `json:"cm_api_level,omitempty"`
+
+	// BuildTags is the //go:build constraint on generated files, if any. See [BuildTags].
+	BuildTags string `json:"build_tags,omitempty"`
+
+	// Host is true if the generated packages build on targets other than WebAssembly,
+	// such as for unit testing or IDE tooling.
+	Host bool `json:"host"`
+
+	// Targets are the WebAssembly targets that can build the generated packages.
+	Targets []BuildTarget `json:"targets"`
+
+	// Packages are the generated Go packages, sorted by path.
+	Packages []BuildPackage `json:"packages"`
+}
+
+// BuildTarget describes a compiler and GOOS/GOARCH pair that can build generated Go packages.
+type BuildTarget struct {
+	// Compiler is the Go compiler, either "gc" or "tinygo".
+	Compiler string `json:"compiler"`
+
+	// GOOS is the target operating system, e.g. "wasip2".
+	GOOS string `json:"goos"`
+
+	// GOARCH is the target architecture, e.g. "wasm".
+	GOARCH string `json:"goarch"`
+
+	// Tags are any additional build tags required to satisfy BuildTags on this target.
+	Tags []string `json:"tags,omitempty"`
+}
+
+// BuildPackage describes a generated Go package.
+type BuildPackage struct {
+	// Path is the Go package path.
+	Path string `json:"path"`
+
+	// Dir is the package directory, relative to the directory of PackageRoot, using forward slashes.
+	Dir string `json:"dir"`
+
+	// Files are the names of the files in the package, sorted.
+	Files []string `json:"files"`
+}
+
+// buildTargets are the WebAssembly targets supported by generated code,
+// with the build tags implicitly satisfied by each.
+var buildTargets = []struct {
+	BuildTarget
+	tags []string
+}{
+	{BuildTarget{Compiler: "tinygo", GOOS: "wasip2", GOARCH: "wasm"}, []string{"tinygo", "wasip2", "wasm"}},
+	{BuildTarget{Compiler: "tinygo", GOOS: "wasip1", GOARCH: "wasm"}, []string{"tinygo", "wasip1", "wasm"}},
+	{BuildTarget{Compiler: "gc", GOOS: "wasip1", GOARCH: "wasm"}, []string{"gc", "wasip1", "wasm"}},
+}
+
+// NewBuild returns a [Build] description of Go packages pkgs generated by [Go] with opts.
+// The caller may set Module if the generated packages are a standalone Go module.
+func NewBuild(pkgs []*gen.Package, opts ...Option) (*Build, error) {
+	var o options
+	err := o.apply(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Build{
+		PackageRoot: o.packageRoot,
+		GoVersion:   GoVersion,
+		BuildTags:   o.buildTags,
+		Host:        o.buildTags == "" || o.stubs,
+	}
+
+	if !o.noCMAPICheck {
+		if o.cmPackage == "" {
+			o.cmPackage = cmPackage
+		}
+		cmPath, _ := gen.ParseSelector(o.cmPackage)
+		for _, pkg := range pkgs {
+			b.CMAPILevel = max(b.CMAPILevel, packageCMAPILevel(pkg, cmPath))
+		}
+	}
+
+	var expr constraint.Expr
+	if o.buildTags != "" {
+		// BuildTags validates the expression.
+		expr, _ = constraint.Parse("//go:build " + o.buildTags)
+	}
+	for _, t := range buildTargets {
+		if (o.target == TargetGo && t.Compiler != "gc") || (o.target == TargetTinyGo && t.Compiler != "tinygo") {
+			continue
+		}
+		if expr == nil {
+			b.Targets = append(b.Targets, t.BuildTarget)
+			continue
+		}
+		// Satisfy the constraint with the implicit tags of the target,
+		// and, if necessary, any tags in the constraint not known to the target.
+		var extra []string
+		if !expr.Eval(func(tag string) bool { return slices.Contains(t.tags, tag) }) {
+			ok := expr.Eval(func(tag string) bool {
+				if slices.Contains(t.tags, tag) {
+					return true
+				}
+				if isKnownTag(tag) {
+					return false
+				}
+				if !slices.Contains(extra, tag) {
+					extra = append(extra, tag)
+				}
+				return true
+			})
+			if !ok {
+				continue
+			}
+			slices.Sort(extra)
+		}
+		t.BuildTarget.Tags = extra
+		b.Targets = append(b.Targets, t.BuildTarget)
+	}
+
+	for _, pkg := range pkgs {
+		if !pkg.HasContent() {
+			continue
+		}
+		p := BuildPackage{Path: pkg.Path, Dir: pkg.Path}
+		if pkg.Path == o.packageRoot {
+			p.Dir = "."
+		} else if o.packageRoot != "" && strings.HasPrefix(pkg.Path, o.packageRoot+"/") {
+			p.Dir = pkg.Path[len(o.packageRoot)+1:]
+		}
+		for name, file := range pkg.Files {
+			if file.HasContent() {
+				p.Files = append(p.Files, name)
+			}
+		}
+		slices.Sort(p.Files)
+		b.Packages = append(b.Packages, p)
+	}
+	slices.SortFunc(b.Packages, func(a, b BuildPackage) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+
+	return b, nil
+}
+
+// isKnownTag reports whether tag is a GOOS, GOARCH, or compiler name that
+// cannot be set with -tags to satisfy a build constraint.
+func isKnownTag(tag string) bool {
+	switch tag {
+	case "gc", "gccgo", "tinygo", "wasm", "wasip1", "wasip2", "js",
+		"linux", "darwin", "windows", "freebsd", "netbsd", "openbsd", "android", "ios",
+		"amd64", "arm64", "386", "arm", "riscv64":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,47 @@
+package bindgen
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/bytecodealliance/wasm-tools-go/internal/go/gen"
+	"github.com/bytecodealliance/wasm-tools-go/wit"
+)
+
+// debugTrace returns a Go statement that calls cm function fn, either TraceImport or TraceExport,
+// with the module and name of the function for decl, which records the call if tracing is enabled.
+func (g *generator) debugTrace(decl *funcDecl, fn string) string {
+	module := g.moduleName(decl.owner)
+	file := decl.goFunc.file
+	if fn == "TraceExport" {
+		file = decl.wasmFunc.file
+	}
+	return g.cmCall(file, fn, strconv.Quote(module)+", "+strconv.Quote(decl.f.Name)) + "\n"
+}
+
+// debugParams returns Go statements for the body of the imported function for decl
+// that check and record the resource handles passed as parameters.
+func (g *generator) debugParams(decl *funcDecl) string {
+	file := decl.goFunc.file
+	var b strings.Builder
+	for i, p := range decl.goFunc.params {
+		switch {
+		case i == 0 && strings.HasPrefix(decl.f.Name, "[resource-drop]"):
+			b.WriteString(g.cmCall(file, "DebugDrop", p.name) + "\n")
+		case wit.KindOf[*wit.Own](p.typ) != nil:
+			b.WriteString(g.cmCall(file, "DebugDrop", p.name) + "\n")
+		case wit.KindOf[*wit.Borrow](p.typ) != nil:
+			b.WriteString(g.cmCall(file, "DebugUse", p.name) + "\n")
+		}
+	}
+	return b.String()
+}
+
+// debugResult returns a Go statement that records the resource handle in result expression
+// expr of type t as acquired, or an empty string if t is not an own<T> handle.
+func (g *generator) debugResult(file *gen.File, t wit.Type, expr string) string {
+	if wit.KindOf[*wit.Own](t) == nil {
+		return ""
+	}
+	return g.cmCall(file, "DebugAcquire", expr) + "\n"
+}
@@ -0,0 +1,199 @@
+package bindgen
+
+import (
+	"go/token"
+	"strconv"
+	"strings"
+
+	"github.com/bytecodealliance/wasm-tools-go/internal/go/gen"
+	"github.com/bytecodealliance/wasm-tools-go/internal/stringio"
+	"github.com/bytecodealliance/wasm-tools-go/wit"
+)
+
+// hasDeepCopy returns true if a DeepCopy method is generated for named [wit.TypeDef] t.
+// Only types that contain strings or lists, which may refer to memory owned by the caller,
+// have a DeepCopy method. Types that contain futures or streams, and types that contain
+// a record with a field that would collide with the DeepCopy method, have no DeepCopy method.
+func (g *generator) hasDeepCopy(t *wit.TypeDef) bool {
+	if !wit.HasPointer(t) {
+		return false
+	}
+	for _, dep := range wit.DependencyGraph(t).TypeDefs() {
+		switch kind := dep.Kind.(type) {
+		case *wit.Future, *wit.Stream, *wit.Pointer:
+			return false
+		case *wit.Record:
+			for _, f := range kind.Fields {
+				if g.fieldName(f.Name, true) == "DeepCopy" {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// deepCopyMethod returns Go source for the DeepCopy method of named [wit.TypeDef] t,
+// which copies the strings and lists in a value into memory allocated on the Go heap.
+// Variants with associated types are handled in variantDeepCopyMethod.
+func (g *generator) deepCopyMethod(file *gen.File, dir wit.Direction, t *wit.TypeDef, goName string) string {
+	if !g.hasDeepCopy(t) {
+		return ""
+	}
+
+	var b strings.Builder
+	switch kind := t.Kind.(type) {
+	case *wit.Variant:
+		return "" // see variantDeepCopyMethod
+
+	case *wit.Record:
+		exported := token.IsExported(goName)
+		for _, f := range kind.Fields {
+			if !wit.HasPointer(f.Type) {
+				continue
+			}
+			name := "self." + g.fieldName(f.Name, exported)
+			stringio.Write(&b, name, " = ", g.deepCopyExpr(file, dir, f.Type, name), "\n")
+		}
+		b.WriteString("return self\n")
+
+	default:
+		// Defined types with the same underlying type as a WIT list, option, result,
+		// tuple, or string are copied as the underlying type.
+		rep := g.typeDefKindRep(file, dir, kind, "")
+		stringio.Write(&b, "return ", goName, "(", g.deepCopyKindFunc(file, dir, kind), "(", rep, "(self)))\n")
+	}
+
+	return deepCopyMethodSource(goName, b.String())
+}
+
+func deepCopyMethodSource(goName, body string) string {
+	var b strings.Builder
+	b.WriteString(formatDocComments("DeepCopy returns a copy of self with its strings and lists copied into memory allocated on the Go heap. Values lifted from the parameters of an exported function refer to memory owned by the caller, and must be copied to be retained after the function returns.", true))
+	stringio.Write(&b, "func (self ", goName, ") DeepCopy() ", goName, " {\n")
+	b.WriteString(body)
+	b.WriteString("}\n\n")
+	return b.String()
+}
+
+// variantDeepCopyMethod returns Go source for the DeepCopy method of variant type goName.
+func (g *generator) variantDeepCopyMethod(file *gen.File, dir wit.Direction, v *wit.Variant, goName string, caseNames, constructorNames []string) string {
+	// A variant has no DeepCopy method unless it contains strings or lists.
+	if !g.hasDeepCopy(&wit.TypeDef{Kind: v}) {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("switch self.Tag() {\n")
+	for i, c := range v.Cases {
+		if c.Type == nil || !wit.HasPointer(c.Type) {
+			continue
+		}
+		stringio.Write(&b, "case ", strconv.Itoa(i), ":\n")
+		stringio.Write(&b, "return ", constructorNames[i], "(", g.deepCopyExpr(file, dir, c.Type, "*self."+caseNames[i]+"()"), ")\n")
+	}
+	b.WriteString("}\n")
+	b.WriteString("return self\n")
+
+	return deepCopyMethodSource(goName, b.String())
+}
+
+// deepCopyExpr returns a Go expression that copies Go expression x
+// of the Go type of [wit.Type] t.
+func (g *generator) deepCopyExpr(file *gen.File, dir wit.Direction, t wit.Type, x string) string {
+	if !wit.HasPointer(t) {
+		return x
+	}
+	if td, ok := t.(*wit.TypeDef); ok {
+		if _, ok := g.typeDecl(dir, td); ok {
+			if strings.HasPrefix(x, "*") {
+				x = "(" + x + ")"
+			}
+			return x + ".DeepCopy()"
+		}
+		if l, ok := td.Kind.(*wit.List); ok {
+			cm := file.Import(g.opts.cmPackage)
+			if !wit.HasPointer(l.Type) {
+				return cm + ".Clone(" + x + ")"
+			}
+			return cm + ".CloneFunc(" + x + ", " + g.deepCopyFunc(file, dir, l.Type) + ")"
+		}
+	}
+	return g.deepCopyFunc(file, dir, t) + "(" + x + ")"
+}
+
+// deepCopyFunc returns a Go expression of type func(T) T,
+// where T is the Go type of [wit.Type] t.
+func (g *generator) deepCopyFunc(file *gen.File, dir wit.Direction, t wit.Type) string {
+	switch t := t.(type) {
+	case *wit.TypeDef:
+		decl, ok := g.typeDecl(dir, t)
+		if !ok {
+			return g.deepCopyKindFunc(file, dir, t.Kind)
+		}
+		typ := file.RelativeName(decl.file.Package, decl.name)
+		return typ + ".DeepCopy"
+	case wit.String:
+		return file.Import("strings") + ".Clone"
+	}
+	return "nil"
+}
+
+// deepCopyKindFunc returns a Go expression of type func(T) T,
+// where T is the Go type of anonymous [wit.TypeDefKind] kind.
+func (g *generator) deepCopyKindFunc(file *gen.File, dir wit.Direction, kind wit.TypeDefKind) string {
+	cm := file.Import(g.opts.cmPackage)
+	rep := g.typeDefKindRep(file, dir, kind, "")
+	var b strings.Builder
+	switch kind := kind.(type) {
+	case wit.Type:
+		return g.deepCopyFunc(file, dir, kind)
+
+	case *wit.List:
+		if !wit.HasPointer(kind.Type) {
+			return cm + ".Clone[" + rep + "]"
+		}
+		stringio.Write(&b, "func(v ", rep, ") ", rep, " {\n")
+		stringio.Write(&b, "return ", cm, ".CloneFunc(v, ", g.deepCopyFunc(file, dir, kind.Type), ")\n")
+		b.WriteString("}")
+
+	case *wit.Option:
+		stringio.Write(&b, "func(v ", rep, ") ", rep, " {\n")
+		b.WriteString("if some := v.Some(); some != nil {\n")
+		stringio.Write(&b, "return ", cm, ".Some(", g.deepCopyExpr(file, dir, kind.Type, "*some"), ")\n")
+		b.WriteString("}\n")
+		b.WriteString("return v\n")
+		b.WriteString("}")
+
+	case *wit.Result:
+		stringio.Write(&b, "func(v ", rep, ") ", rep, " {\n")
+		if kind.Err != nil && wit.HasPointer(kind.Err) {
+			stringio.Write(&b, "if err := v.Err(); err != nil {\n")
+			stringio.Write(&b, "return ", cm, ".Err[", rep, "](", g.deepCopyExpr(file, dir, kind.Err, "*err"), ")\n")
+			b.WriteString("}\n")
+		}
+		if kind.OK != nil && wit.HasPointer(kind.OK) {
+			stringio.Write(&b, "if ok := v.OK(); ok != nil {\n")
+			stringio.Write(&b, "return ", cm, ".OK[", rep, "](", g.deepCopyExpr(file, dir, kind.OK, "*ok"), ")\n")
+			b.WriteString("}\n")
+		}
+		b.WriteString("return v\n")
+		b.WriteString("}")
+
+	case *wit.Tuple:
+		stringio.Write(&b, "func(v ", rep, ") ", rep, " {\n")
+		for i, typ := range kind.Types {
+			if !wit.HasPointer(typ) {
+				continue
+			}
+			elem := tupleElem(kind, "v", i)
+			stringio.Write(&b, elem, " = ", g.deepCopyExpr(file, dir, typ, elem), "\n")
+		}
+		b.WriteString("return v\n")
+		b.WriteString("}")
+
+	default: // own, borrow, future, stream
+		stringio.Write(&b, "func(v ", rep, ") ", rep, " {\nreturn v\n}")
+	}
+	return b.String()
+}
@@ -0,0 +1,215 @@
+package bindgen
+
+import (
+	"go/token"
+	"strings"
+
+	"github.com/bytecodealliance/wasm-tools-go/wit"
+)
+
+// directivePrefix is the prefix of a directive line in a WIT doc comment,
+// such as "wit-bindgen-go:name InputReader" or "wit-bindgen-go:skip".
+const directivePrefix = "wit-bindgen-go:"
+
+// directives are the directives in the doc comments of a WIT type or function.
+type directives struct {
+	// name is the Go name specified with "wit-bindgen-go:name <GoName>".
+	name string
+
+	// skip is true if "wit-bindgen-go:skip" was specified.
+	skip bool
+}
+
+// parseDirectives parses the directive lines in WIT doc comments s.
+// Lines without [directivePrefix] are ignored.
+func parseDirectives(s string) (directives, error) {
+	var d directives
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, directivePrefix) {
+			continue
+		}
+		verb, arg, _ := strings.Cut(line[len(directivePrefix):], " ")
+		arg = strings.TrimSpace(arg)
+		switch verb {
+		case "name":
+			if !token.IsIdentifier(arg) || !token.IsExported(arg) {
+				return d, &directiveError{line, "expected an exported Go identifier"}
+			}
+			d.name = arg
+		case "skip":
+			if arg != "" {
+				return d, &directiveError{line, "unexpected argument " + arg}
+			}
+			d.skip = true
+		default:
+			return d, &directiveError{line, "unknown directive"}
+		}
+	}
+	return d, nil
+}
+
+type directiveError struct {
+	line string
+	msg  string
+}
+
+func (e *directiveError) Error() string {
+	return "invalid directive \"" + e.line + "\": " + e.msg
+}
+
+// docContents returns the contents of WIT doc comments d without directive lines.
+func docContents(d wit.Docs) string {
+	if !strings.Contains(d.Contents, directivePrefix) {
+		return d.Contents
+	}
+	var lines []string
+	for _, line := range strings.Split(d.Contents, "\n") {
+		if !strings.HasPrefix(strings.TrimSpace(line), directivePrefix) {
+			lines = append(lines, line)
+		}
+	}
+	s := strings.TrimRight(strings.Join(lines, "\n"), "\n")
+	if s != "" && strings.HasSuffix(d.Contents, "\n") {
+		s += "\n"
+	}
+	return s
+}
+
+// checkDirectives parses the directives in the doc comments of each type and function
+// in g.res. It returns an error if a directive is invalid, or if a skipped type is used
+// by a type or function that is not skipped.
+func (g *generator) checkDirectives() error {
+	g.directives = make(map[wit.Node]directives)
+	add := func(owner wit.TypeOwner, name string, node wit.Node, docs wit.Docs, pos wit.Position) error {
+		if !strings.Contains(docs.Contents, directivePrefix) {
+			return nil
+		}
+		d, err := parseDirectives(docs.Contents)
+		if err != nil {
+			return newGenerateError(owner, name, pos, err.Error())
+		}
+		if f, ok := node.(*wit.Function); ok && d.name != "" && !f.IsFreestanding() {
+			return newGenerateError(owner, name, pos, "cannot rename "+f.WITKind()+" with "+directivePrefix+"name")
+		}
+		g.directives[node] = d
+		return nil
+	}
+
+	var err error
+	for _, t := range g.res.TypeDefs {
+		if t.Name != nil {
+			err = add(t.Owner, *t.Name, t, t.Docs, t.Pos)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	for _, i := range g.res.Interfaces {
+		i.Functions.All()(func(name string, f *wit.Function) bool {
+			err = add(i, name, f, f.Docs, f.Pos)
+			return err == nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	for _, w := range g.res.Worlds {
+		w.AllImportsAndExports()(func(name string, item wit.WorldItem) bool {
+			if f, ok := item.(*wit.Function); ok {
+				err = add(w, name, f, f.Docs, f.Pos)
+			}
+			return err == nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// Check that no skipped type is used by a type or function that is generated.
+	var skip bool
+	for _, d := range g.directives {
+		skip = skip || d.skip
+	}
+	if !skip {
+		return nil
+	}
+	used := func(owner wit.TypeOwner, name string, pos wit.Position, types ...wit.Type) error {
+		for _, t := range types {
+			td, ok := t.(*wit.TypeDef)
+			if !ok {
+				continue
+			}
+			for _, dep := range wit.DependencyGraph(td).TypeDefs() {
+				if dep.Name != nil && g.directives[dep].skip {
+					return newGenerateError(owner, name, pos, "uses "+dep.WITKind()+" "+*dep.Name+", which is skipped with "+directivePrefix+"skip")
+				}
+			}
+		}
+		return nil
+	}
+	for _, t := range g.res.TypeDefs {
+		if t.Name == nil || g.skipped(t) {
+			continue
+		}
+		err = used(t.Owner, *t.Name, t.Pos, t)
+		if err != nil {
+			return err
+		}
+	}
+	check := func(owner wit.TypeOwner, name string, f *wit.Function) bool {
+		if g.skipped(f) {
+			return true
+		}
+		var types []wit.Type
+		for _, p := range f.Params {
+			types = append(types, p.Type)
+		}
+		for _, r := range f.Results {
+			types = append(types, r.Type)
+		}
+		err = used(owner, name, f.Pos, types...)
+		return err == nil
+	}
+	for _, i := range g.res.Interfaces {
+		i.Functions.All()(func(name string, f *wit.Function) bool {
+			return check(i, name, f)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	for _, w := range g.res.Worlds {
+		w.AllImportsAndExports()(func(name string, item wit.WorldItem) bool {
+			if f, ok := item.(*wit.Function); ok {
+				return check(w, name, f)
+			}
+			return true
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// skipped returns true if the WIT type or function v is skipped with a "wit-bindgen-go:skip"
+// directive. A method, constructor, or static function is skipped with its resource type.
+func (g *generator) skipped(v wit.Node) bool {
+	if g.directives[v].skip {
+		return true
+	}
+	if f, ok := v.(*wit.Function); ok && !f.IsFreestanding() {
+		if t, ok := f.Type().(*wit.TypeDef); ok {
+			return g.directives[t].skip
+		}
+	}
+	return false
+}
+
+// directiveName returns the Go name of the WIT type or function v specified with a
+// "wit-bindgen-go:name" directive, if any.
+func (g *generator) directiveName(v wit.Node) (string, bool) {
+	d := g.directives[v]
+	return d.name, d.name != ""
+}
@@ -3,7 +3,11 @@ package bindgen
 import (
 	"strings"
 
+	"github.com/coreos/go-semver/semver"
+
 	"github.com/bytecodealliance/wasm-tools-go/internal/go/gen"
+	"github.com/bytecodealliance/wasm-tools-go/internal/stringio"
+	"github.com/bytecodealliance/wasm-tools-go/wit"
 )
 
 func formatDocComments(s string, indent bool) string {
@@ -24,3 +28,51 @@ func processMarkdown(s string) string {
 	}
 	return strings.Join(lines, "\n")
 }
+
+// stabilityDocs returns documentation for the WIT stability s of an item, or an empty string
+// if s is nil. It notes the version of the WIT package that added a stable item, or the feature
+// that gates an unstable item. If the item is deprecated, it adds a "Deprecated:" paragraph,
+// which Go tools such as gopls and staticcheck report to users of the Go declaration.
+func stabilityDocs(s wit.Stability) string {
+	var b strings.Builder
+	var deprecated *semver.Version
+	switch s := s.(type) {
+	case *wit.Stable:
+		stringio.Write(&b, "Since version ", s.Since.String(), ".\n")
+		deprecated = s.Deprecated
+	case *wit.Unstable:
+		stringio.Write(&b, "Unstable: requires feature \"", s.Feature, "\".\n")
+		deprecated = s.Deprecated
+	}
+	if deprecated != nil {
+		stringio.Write(&b, "\nDeprecated: as of version ", deprecated.String(), ".\n")
+	}
+	return b.String()
+}
+
+// docLink returns a Go doc link to the Go type generated for WIT type t, for use in a doc comment
+// in file. Types declared in the same Go package as file are linked by name, such as [Foo].
+// Types declared in other Go packages are linked by full import path, such as
+// [example.com/wasi/io/streams.InputStream], so the link resolves regardless of how the
+// package is imported, or whether it is imported by the package of file at all.
+// Types without a declared Go name, such as cm.List[string], are returned without a link.
+func (g *generator) docLink(file *gen.File, dir wit.Direction, t wit.Type) string {
+	if t, ok := t.(*wit.TypeDef); ok {
+		if decl, ok := g.typeDecl(dir, t); ok {
+			if decl.file.Package == file.Package {
+				return "[" + decl.name + "]"
+			}
+			return "[" + decl.file.Package.Path + "." + decl.name + "]"
+		}
+		// own<T> and imported borrow<T> handles are represented by the Go type for T.
+		switch kind := t.Kind.(type) {
+		case *wit.Own:
+			return g.docLink(file, dir, kind.Type)
+		case *wit.Borrow:
+			if dir == wit.Imported {
+				return g.docLink(file, dir, kind.Type)
+			}
+		}
+	}
+	return g.typeRep(file, dir, t)
+}
@@ -0,0 +1,56 @@
+package bindgen
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/bytecodealliance/wasm-tools-go/wit"
+)
+
+// wasiDocsURL is the default URL template for links to the documentation of
+// interfaces in the wasi namespace, which are defined in a repository per package.
+const wasiDocsURL = "https://github.com/WebAssembly/wasi-{package}/blob/{ref}/wit/{interface}.wit"
+
+// docsURL returns a link to the documentation of item in owner, expanded from the URL template
+// configured for the namespace of owner. Item is the WIT name of a type or function, or empty
+// for owner itself. It returns an empty string if owner is not a named interface, or if no
+// template is configured for its namespace.
+func (g *generator) docsURL(owner wit.TypeOwner, item string) string {
+	i, ok := owner.(*wit.Interface)
+	if !ok || i.Name == nil || i.Package == nil {
+		return ""
+	}
+	id := i.Package.Name
+	template, ok := g.opts.docsURLs[id.Namespace]
+	if !ok && id.Namespace == "wasi" {
+		template = wasiDocsURL
+	}
+	if template == "" {
+		return ""
+	}
+	var version string
+	ref := "main"
+	if id.Version != nil {
+		version = id.Version.String()
+		ref = "v" + version
+	}
+	r := strings.NewReplacer(
+		"{namespace}", url.PathEscape(id.Namespace),
+		"{package}", url.PathEscape(id.Package),
+		"{version}", url.PathEscape(version),
+		"{ref}", url.PathEscape(ref),
+		"{interface}", url.PathEscape(*i.Name),
+		"{item}", url.PathEscape(item),
+	)
+	return strings.TrimSuffix(r.Replace(template), "#")
+}
+
+// docsURLComment returns a doc comment paragraph that links to the documentation
+// of item in owner, or an empty string if there is none. See [generator.docsURL].
+func (g *generator) docsURLComment(owner wit.TypeOwner, item string) string {
+	u := g.docsURL(owner, item)
+	if u == "" {
+		return ""
+	}
+	return "// See " + u + " for the upstream documentation.\n//\n"
+}
@@ -0,0 +1,324 @@
+package bindgen
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/bytecodealliance/wasm-tools-go/internal/codec"
+	"github.com/bytecodealliance/wasm-tools-go/internal/go/gen"
+)
+
+// DriftKind describes how an exported Go declaration in existing bindings
+// differs from the declaration generated from WIT.
+type DriftKind int
+
+const (
+	// Missing means the declaration, or its package, is not in the existing bindings.
+	Missing DriftKind = iota
+
+	// Changed means the signature of the declaration changed, such as the type of a
+	// function param, the fields of a struct, or the value of a constant.
+	Changed
+
+	// Stale means the declaration in the existing bindings was generated from
+	// a WIT item that no longer exists.
+	Stale
+)
+
+// String returns a description of k, e.g. "missing".
+func (k DriftKind) String() string {
+	switch k {
+	case Missing:
+		return "missing"
+	case Changed:
+		return "changed"
+	case Stale:
+		return "stale"
+	}
+	return fmt.Sprintf("DriftKind(%d)", int(k))
+}
+
+// Drift describes an exported Go declaration in existing bindings that differs from
+// the declaration generated from WIT, as reported by [Compare].
+type Drift struct {
+	Kind DriftKind
+
+	// Package is the Go package path of the declaration.
+	Package string
+
+	// Decl is the name of the package-scoped Go declaration. Methods are named "Type.Method".
+	// It is empty if the package itself is missing.
+	Decl string
+
+	// Origin describes the WIT item that Decl or Package was generated from, if known,
+	// e.g. `function "wasi:clocks/wall-clock@0.2.0#now"`.
+	Origin string
+
+	// Want is the signature of the declaration generated from WIT, if any, e.g. "func() DateTime".
+	// Param and result names are omitted from function signatures.
+	Want string
+
+	// Got is the signature of the declaration in the existing bindings, if any.
+	Got string
+}
+
+// String returns a description of d, e.g. "example.com/gen/wasi/clocks/wall-clock: changed Now:
+// func() Instant, expected func() DateTime (generated from function "wasi:clocks/wall-clock#now")".
+func (d *Drift) String() string {
+	var b strings.Builder
+	b.WriteString(d.Package)
+	b.WriteString(": ")
+	b.WriteString(d.Kind.String())
+	if d.Decl != "" {
+		b.WriteByte(' ')
+		b.WriteString(d.Decl)
+	} else {
+		b.WriteString(" package")
+	}
+	switch d.Kind {
+	case Changed:
+		fmt.Fprintf(&b, ": %s, expected %s", d.Got, d.Want)
+	case Stale:
+		fmt.Fprintf(&b, ": %s", d.Got)
+	}
+	if d.Origin != "" {
+		fmt.Fprintf(&b, " (generated from %s)", d.Origin)
+	}
+	return b.String()
+}
+
+// Compare compares the exported declarations of Go packages pkgs, generated from WIT by [Go],
+// with the existing Go packages with the same paths, loaded with the go command in directory dir,
+// e.g. the output directory of the existing bindings. Dir must be in a Go module, and the go command
+// runs with its Go environment, including GOFLAGS, go.work workspaces, and vendored dependencies.
+// Files excluded by build constraints are included, as generated packages may declare stubs
+// for other targets.
+//
+// It returns the declarations that are missing, have changed signatures, or were generated
+// from WIT items that no longer exist, sorted by package and name. Exported declarations that
+// were not generated from a WIT item, such as methods added by options like [JSON], are not
+// reported unless they are missing or changed.
+func Compare(pkgs []*gen.Package, dir string) ([]Drift, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	fset := token.NewFileSet()
+	pkgMap := make(map[string]*gen.Package)
+	want := make(map[string]map[string]*apiDecl)
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			if !file.IsGo() || !file.HasContent() {
+				continue
+			}
+			content, err := file.Bytes()
+			if err != nil && content == nil {
+				return nil, err
+			}
+			f, err := parser.ParseFile(fset, file.Name, content, parser.ParseComments)
+			if err != nil {
+				return nil, err
+			}
+			if want[pkg.Path] == nil {
+				want[pkg.Path] = make(map[string]*apiDecl)
+				pkgMap[pkg.Path] = pkg
+			}
+			exportedDecls(fset, f, want[pkg.Path])
+		}
+	}
+	if len(pkgMap) == 0 {
+		return nil, nil
+	}
+
+	ctx := context.Background()
+	env, err := gen.LoadBuildEnv(ctx, abs)
+	if err != nil {
+		return nil, err
+	}
+	cfg := env.PackagesConfig(ctx, packages.NeedName|packages.NeedFiles, "")
+	goPackages, err := packages.Load(cfg, codec.SortedKeys(pkgMap)...)
+	if err != nil {
+		return nil, err
+	}
+	got := make(map[string]map[string]*apiDecl)
+	for _, goPkg := range goPackages {
+		if pkgMap[goPkg.PkgPath] == nil {
+			continue
+		}
+		for _, path := range slices.Concat(goPkg.GoFiles, goPkg.IgnoredFiles) {
+			if !strings.HasSuffix(path, ".go") {
+				continue
+			}
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+			f, err := parser.ParseFile(fset, path, content, parser.ParseComments)
+			if err != nil {
+				return nil, err
+			}
+			if got[goPkg.PkgPath] == nil {
+				got[goPkg.PkgPath] = make(map[string]*apiDecl)
+			}
+			exportedDecls(fset, f, got[goPkg.PkgPath])
+		}
+	}
+
+	var drift []Drift
+	for _, path := range codec.SortedKeys(pkgMap) {
+		pkg := pkgMap[path]
+		want, got := want[path], got[path]
+		if got == nil {
+			drift = append(drift, Drift{Kind: Missing, Package: path, Origin: pkg.Origins[""]})
+			continue
+		}
+		for _, name := range codec.SortedKeys(want) {
+			d := Drift{Package: path, Decl: name, Origin: declOrigin(pkg, name), Want: want[name].sig}
+			if g, ok := got[name]; !ok {
+				d.Kind = Missing
+			} else if g.sig != d.Want {
+				d.Kind = Changed
+				d.Got = g.sig
+			} else {
+				continue
+			}
+			drift = append(drift, d)
+		}
+		for _, name := range codec.SortedKeys(got) {
+			if _, ok := want[name]; ok || !got[name].fromWIT {
+				continue
+			}
+			drift = append(drift, Drift{Kind: Stale, Package: path, Decl: name, Got: got[name].sig})
+		}
+	}
+	return drift, nil
+}
+
+// declOrigin returns the WIT origin of declaration name in pkg, or that of its receiver type.
+func declOrigin(pkg *gen.Package, name string) string {
+	if o, ok := pkg.Origins[name]; ok {
+		return o
+	}
+	if recv, _, ok := strings.Cut(name, "."); ok {
+		return pkg.Origins[recv]
+	}
+	return ""
+}
+
+// apiDecl is the signature of an exported Go declaration.
+type apiDecl struct {
+	sig string
+
+	// fromWIT is true if the declaration is documented as generated from a WIT item,
+	// e.g. "Now represents the imported function "now"."
+	fromWIT bool
+}
+
+// exportedDecls adds the exported package-scoped declarations and methods in file f to decls.
+// Methods are named "Type.Method".
+func exportedDecls(fset *token.FileSet, f *ast.File, decls map[string]*apiDecl) {
+	for _, decl := range f.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if !decl.Name.IsExported() {
+				continue
+			}
+			name := decl.Name.Name
+			if decl.Recv != nil && len(decl.Recv.List) > 0 {
+				recv := receiverName(decl.Recv.List[0].Type)
+				if !token.IsExported(recv) {
+					continue
+				}
+				name = recv + "." + name
+			}
+			decls[name] = &apiDecl{
+				sig:     nodeString(fset, funcTypeWithoutNames(decl.Type)),
+				fromWIT: representsWIT(decl.Name.Name, decl.Doc),
+			}
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					if !spec.Name.IsExported() {
+						continue
+					}
+					doc := spec.Doc
+					if doc == nil && len(decl.Specs) == 1 {
+						doc = decl.Doc
+					}
+					var b strings.Builder
+					b.WriteString("type")
+					if spec.TypeParams != nil {
+						b.WriteString(nodeString(fset, spec.TypeParams))
+					}
+					if spec.Assign.IsValid() {
+						b.WriteString(" =")
+					}
+					b.WriteByte(' ')
+					b.WriteString(nodeString(fset, spec.Type))
+					decls[spec.Name.Name] = &apiDecl{sig: b.String(), fromWIT: representsWIT(spec.Name.Name, doc)}
+				case *ast.ValueSpec:
+					for i, name := range spec.Names {
+						if !name.IsExported() {
+							continue
+						}
+						sig := decl.Tok.String()
+						if spec.Type != nil {
+							sig += " " + nodeString(fset, spec.Type)
+						}
+						if decl.Tok == token.CONST && i < len(spec.Values) {
+							sig += " = " + nodeString(fset, spec.Values[i])
+						}
+						doc := spec.Doc
+						if doc == nil {
+							doc = decl.Doc
+						}
+						decls[name.Name] = &apiDecl{sig: sig, fromWIT: representsWIT(name.Name, doc)}
+					}
+				}
+			}
+		}
+	}
+}
+
+// representsWIT returns true if doc describes the WIT item that declaration name
+// was generated from, e.g. "Now represents the imported function "now"".
+func representsWIT(name string, doc *ast.CommentGroup) bool {
+	return doc != nil && strings.HasPrefix(doc.Text(), name+" represents the ")
+}
+
+// funcTypeWithoutNames returns a copy of t without param and result names,
+// which do not change the signature of a function.
+func funcTypeWithoutNames(t *ast.FuncType) *ast.FuncType {
+	strip := func(fields *ast.FieldList) *ast.FieldList {
+		if fields == nil {
+			return nil
+		}
+		stripped := &ast.FieldList{}
+		for _, f := range fields.List {
+			for range max(len(f.Names), 1) {
+				stripped.List = append(stripped.List, &ast.Field{Type: f.Type})
+			}
+		}
+		return stripped
+	}
+	return &ast.FuncType{TypeParams: t.TypeParams, Params: strip(t.Params), Results: strip(t.Results)}
+}
+
+// nodeString returns node formatted as Go source without comments, on a single line.
+func nodeString(fset *token.FileSet, node ast.Node) string {
+	var buf bytes.Buffer
+	printer.Fprint(&buf, fset, node)
+	return strings.Join(strings.Fields(buf.String()), " ")
+}
@@ -0,0 +1,223 @@
+package bindgen
+
+import (
+	"go/token"
+	"strconv"
+	"strings"
+
+	"github.com/bytecodealliance/wasm-tools-go/internal/go/gen"
+	"github.com/bytecodealliance/wasm-tools-go/internal/stringio"
+	"github.com/bytecodealliance/wasm-tools-go/wit"
+)
+
+// hasEqual returns true if an Equal method is generated for named [wit.TypeDef] t.
+// Types that contain resource handles, futures, or streams, and types that contain
+// a record with a field that would collide with the Equal method, have no Equal method.
+func (g *generator) hasEqual(t *wit.TypeDef) bool {
+	if wit.HasResource(t) {
+		return false
+	}
+	for _, dep := range wit.DependencyGraph(t).TypeDefs() {
+		switch kind := dep.Kind.(type) {
+		case *wit.Resource, *wit.Own, *wit.Borrow, *wit.Future, *wit.Stream, *wit.Pointer:
+			return false
+		case *wit.Record:
+			for _, f := range kind.Fields {
+				if g.fieldName(f.Name, true) == "Equal" {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// equalMethod returns Go source for the Equal method of named [wit.TypeDef] t,
+// which reports whether two values are equal. Unlike ==, Equal compares the elements
+// of lists and the payloads of options, results, and variants.
+// Variants with associated types are handled in variantEqualMethod.
+func (g *generator) equalMethod(file *gen.File, dir wit.Direction, t *wit.TypeDef, goName string) string {
+	if !g.hasEqual(t) {
+		return ""
+	}
+
+	var body string
+	switch kind := t.Kind.(type) {
+	case *wit.Variant:
+		if kind.Enum() == nil {
+			return "" // see variantEqualMethod
+		}
+		body = "return self == other\n"
+
+	case *wit.Record:
+		exported := token.IsExported(goName)
+		var exprs []string
+		for _, f := range kind.Fields {
+			name := g.fieldName(f.Name, exported)
+			exprs = append(exprs, g.equalExpr(file, dir, f.Type, "self."+name, "other."+name))
+		}
+		if len(exprs) == 0 {
+			body = "return true\n"
+		} else {
+			body = "return " + strings.Join(exprs, " &&\n") + "\n"
+		}
+
+	default:
+		// Defined types with the same underlying type as a WIT list, option, result,
+		// tuple, or primitive type are compared as the underlying type.
+		if equalOperator(kind) {
+			body = "return self == other\n"
+		} else {
+			rep := g.typeDefKindRep(file, dir, kind, "")
+			body = "return " + g.equalKindFunc(file, dir, kind) + "(" + rep + "(self), " + rep + "(other))\n"
+		}
+	}
+
+	return equalMethodSource(goName, body)
+}
+
+func equalMethodSource(goName, body string) string {
+	var b strings.Builder
+	b.WriteString(formatDocComments("Equal reports whether self and other are equal, comparing the contents of lists and strings, and the payloads of options, results, and variants.", true))
+	stringio.Write(&b, "func (self ", goName, ") Equal(other ", goName, ") bool {\n")
+	b.WriteString(body)
+	b.WriteString("}\n\n")
+	return b.String()
+}
+
+// variantEqualMethod returns Go source for the Equal method of variant type goName.
+func (g *generator) variantEqualMethod(file *gen.File, dir wit.Direction, v *wit.Variant, goName string, caseNames []string) string {
+	// A variant has no Equal method if any of its associated types has none.
+	if !g.hasEqual(&wit.TypeDef{Kind: v}) {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("if self.Tag() != other.Tag() {\nreturn false\n}\n")
+	b.WriteString("switch self.Tag() {\n")
+	for i, c := range v.Cases {
+		if c.Type == nil {
+			continue
+		}
+		stringio.Write(&b, "case ", strconv.Itoa(i), ":\n")
+		stringio.Write(&b, "return ", g.equalExpr(file, dir, c.Type, "*self."+caseNames[i]+"()", "*other."+caseNames[i]+"()"), "\n")
+	}
+	b.WriteString("}\n")
+	b.WriteString("return true\n")
+
+	return equalMethodSource(goName, b.String())
+}
+
+// equalOperator returns true if Go values of [wit.TypeDefKind] kind are compared with ==.
+func equalOperator(kind wit.TypeDefKind) bool {
+	switch kind := kind.(type) {
+	case wit.Primitive, *wit.Enum, *wit.Flags:
+		return true
+	case *wit.Variant:
+		return kind.Enum() != nil
+	case *wit.Result:
+		return kind.OK == nil && kind.Err == nil
+	case *wit.TypeDef:
+		return equalOperator(kind.Root().Kind)
+	}
+	return false
+}
+
+// equalExpr returns a Go boolean expression that compares Go expressions a and b
+// of the Go type of [wit.Type] t.
+func (g *generator) equalExpr(file *gen.File, dir wit.Direction, t wit.Type, a, b string) string {
+	if equalOperator(t) {
+		return a + " == " + b
+	}
+	if td, ok := t.(*wit.TypeDef); ok {
+		if _, ok := g.typeDecl(dir, td); ok {
+			if strings.HasPrefix(a, "*") {
+				a = "(" + a + ")"
+			}
+			return a + ".Equal(" + b + ")"
+		}
+	}
+	return g.equalFunc(file, dir, t) + "(" + a + ", " + b + ")"
+}
+
+// equalFunc returns a Go expression of type func(T, T) bool,
+// where T is the Go type of [wit.Type] t.
+func (g *generator) equalFunc(file *gen.File, dir wit.Direction, t wit.Type) string {
+	switch t := t.(type) {
+	case *wit.TypeDef:
+		decl, ok := g.typeDecl(dir, t)
+		if !ok {
+			return g.equalKindFunc(file, dir, t.Kind)
+		}
+		typ := file.RelativeName(decl.file.Package, decl.name)
+		if equalOperator(t) {
+			return "func(a, b " + typ + ") bool { return a == b }"
+		}
+		return typ + ".Equal"
+	case wit.Primitive:
+		return "func(a, b " + g.primitiveRep(t) + ") bool { return a == b }"
+	}
+	return "nil"
+}
+
+// equalKindFunc returns a Go expression of type func(T, T) bool,
+// where T is the Go type of anonymous [wit.TypeDefKind] kind.
+func (g *generator) equalKindFunc(file *gen.File, dir wit.Direction, kind wit.TypeDefKind) string {
+	rep := g.typeDefKindRep(file, dir, kind, "")
+	var b strings.Builder
+	switch kind := kind.(type) {
+	case wit.Type:
+		return g.equalFunc(file, dir, kind)
+
+	case *wit.List:
+		slices := file.Import("slices")
+		stringio.Write(&b, "func(a, b ", rep, ") bool {\n")
+		if equalOperator(kind.Type) {
+			stringio.Write(&b, "return ", slices, ".Equal(a.Slice(), b.Slice())\n")
+		} else {
+			stringio.Write(&b, "return ", slices, ".EqualFunc(a.Slice(), b.Slice(), ", g.equalFunc(file, dir, kind.Type), ")\n")
+		}
+		b.WriteString("}")
+
+	case *wit.Option:
+		stringio.Write(&b, "func(a, b ", rep, ") bool {\n")
+		b.WriteString("x, y := a.Some(), b.Some()\n")
+		b.WriteString("if x == nil || y == nil {\nreturn x == y\n}\n")
+		stringio.Write(&b, "return ", g.equalExpr(file, dir, kind.Type, "*x", "*y"), "\n")
+		b.WriteString("}")
+
+	case *wit.Result:
+		stringio.Write(&b, "func(a, b ", rep, ") bool {\n")
+		if kind.OK == nil && kind.Err == nil {
+			b.WriteString("return a == b\n")
+		} else {
+			b.WriteString("if a.IsErr() != b.IsErr() {\nreturn false\n}\n")
+			if kind.Err == nil {
+				b.WriteString("if a.IsErr() {\nreturn true\n}\n")
+			} else {
+				stringio.Write(&b, "if a.IsErr() {\nreturn ", g.equalExpr(file, dir, kind.Err, "*a.Err()", "*b.Err()"), "\n}\n")
+			}
+			if kind.OK == nil {
+				b.WriteString("return true\n")
+			} else {
+				stringio.Write(&b, "return ", g.equalExpr(file, dir, kind.OK, "*a.OK()", "*b.OK()"), "\n")
+			}
+		}
+		b.WriteString("}")
+
+	case *wit.Tuple:
+		stringio.Write(&b, "func(a, b ", rep, ") bool {\n")
+		b.WriteString("return ")
+		for i, typ := range kind.Types {
+			if i > 0 {
+				b.WriteString(" &&\n")
+			}
+			b.WriteString(g.equalExpr(file, dir, typ, tupleElem(kind, "a", i), tupleElem(kind, "b", i)))
+		}
+		b.WriteString("\n}")
+
+	default: // own, borrow, future, stream
+		stringio.Write(&b, "func(a, b ", rep, ") bool {\nreturn false\n}")
+	}
+	return b.String()
+}
@@ -0,0 +1,142 @@
+package bindgen
+
+import (
+	"strings"
+
+	"github.com/bytecodealliance/wasm-tools-go/wit"
+)
+
+// GenerateError is returned by [Go] if a WIT world, interface, type, or function
+// cannot be represented in Go. It identifies the WIT item that caused the error,
+// its position in WIT source if known, and a suggestion to fix the WIT, if any.
+type GenerateError struct {
+	// Package is the name of the WIT package of the item, e.g. "wasi:io@0.2.0".
+	Package string
+
+	// Owner is the name of the WIT interface or world of the item, e.g. "streams", if any.
+	Owner string
+
+	// Name is the WIT type or function name, e.g. "[method]output-stream.write", if any.
+	Name string
+
+	// Pos is the position of the item in WIT source, if known. See [wit.Position].
+	Pos wit.Position
+
+	// Msg is the error message.
+	Msg string
+
+	// Suggestion describes how to fix the error, if known.
+	Suggestion string
+}
+
+// newGenerateError returns a [GenerateError] for the WIT type or function name
+// in owner at pos. Name may be empty if the error is in owner itself.
+func newGenerateError(owner wit.TypeOwner, name string, pos wit.Position, msg string) *GenerateError {
+	e := &GenerateError{Name: name, Pos: pos, Msg: msg}
+	if owner != nil {
+		if p := owner.WITPackage(); p != nil {
+			e.Package = p.Name.String()
+		}
+		switch owner := owner.(type) {
+		case *wit.Interface:
+			if owner.Name != nil {
+				e.Owner = *owner.Name
+			}
+		case *wit.World:
+			e.Owner = owner.Name
+		}
+	}
+	return e
+}
+
+// newIdentError returns a [GenerateError] for the item identified by ident, a qualified
+// WIT identifier such as wasi:io/streams#input-stream, which may not exist.
+func newIdentError(ident string, msg string) *GenerateError {
+	owner, name, _ := strings.Cut(ident, "#")
+	id, err := wit.ParseIdent(owner)
+	if err != nil {
+		return &GenerateError{Name: ident, Msg: msg}
+	}
+	e := &GenerateError{Owner: id.Extension, Name: name, Msg: msg}
+	id.Extension = ""
+	e.Package = id.String()
+	return e
+}
+
+// Ident returns the qualified WIT identifier of the item, such as
+// wasi:io/streams@0.2.0#[method]output-stream.write.
+func (e *GenerateError) Ident() string {
+	var b strings.Builder
+	pkg, version, versioned := strings.Cut(e.Package, "@")
+	b.WriteString(pkg)
+	if e.Owner != "" {
+		if b.Len() > 0 {
+			b.WriteByte('/')
+		}
+		b.WriteString(e.Owner)
+	}
+	if versioned {
+		b.WriteByte('@')
+		b.WriteString(version)
+	}
+	if e.Name != "" {
+		if b.Len() > 0 {
+			b.WriteByte('#')
+		}
+		b.WriteString(e.Name)
+	}
+	return b.String()
+}
+
+// Error implements the error interface, prefixing Msg with the position and identifier
+// of the item, e.g. "streams.wit:12:5: wasi:io/streams@0.2.0#output-stream: message".
+// The suggestion, if any, is not included.
+func (e *GenerateError) Error() string {
+	var b strings.Builder
+	if e.Pos.IsValid() {
+		b.WriteString(e.Pos.String())
+		b.WriteString(": ")
+	}
+	if id := e.Ident(); id != "" {
+		b.WriteString(id)
+		b.WriteString(": ")
+	}
+	b.WriteString(e.Msg)
+	return b.String()
+}
+
+// suggestName returns the name in names most similar to name, or "" if none is similar.
+// Names are similar if they differ only in case, or by at most 2 single-byte edits.
+func suggestName(name string, names []string) string {
+	best, bestDist := "", 3
+	for _, n := range names {
+		if strings.EqualFold(n, name) {
+			return n
+		}
+		if d := editDistance(n, name); d < bestDist {
+			best, bestDist = n, d
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
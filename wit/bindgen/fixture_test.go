@@ -0,0 +1,302 @@
+package bindgen
+
+import (
+	"bytes"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/bytecodealliance/wasm-tools-go/internal/go/gen"
+	"github.com/bytecodealliance/wasm-tools-go/wit"
+)
+
+// fixtureRoot is the Go package root of bindings generated by generateFixture.
+const fixtureRoot = "example.com/gen"
+
+// loadFixture loads the WIT JSON fixture at path, relative to testdata.
+func loadFixture(t *testing.T, path string) *wit.Resolve {
+	t.Helper()
+	res, err := wit.LoadJSON(testdataPath + "/" + path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+// generateFixture generates Go bindings for the WIT JSON fixture at path, relative to testdata,
+// with package root [fixtureRoot], and returns the formatted source of each generated file.
+// See [generateSources].
+func generateFixture(t *testing.T, path string, opts ...Option) map[string]string {
+	t.Helper()
+	return generateSources(t, loadFixture(t, path), opts...)
+}
+
+// generateSources generates Go bindings for res with package root [fixtureRoot], and returns
+// the formatted source of each generated file with content, indexed by its path relative
+// to the package root, e.g. "wasi/io/streams/streams.wit.go".
+func generateSources(t *testing.T, res *wit.Resolve, opts ...Option) map[string]string {
+	t.Helper()
+	pkgs, err := Go(res, append([]Option{PackageRoot(fixtureRoot)}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return packageSources(t, pkgs)
+}
+
+// packageSources returns the formatted source of each file with content in pkgs,
+// indexed by its path relative to [fixtureRoot].
+func packageSources(t *testing.T, pkgs []*gen.Package) map[string]string {
+	t.Helper()
+	files := make(map[string]string)
+	for _, pkg := range pkgs {
+		for _, f := range pkg.Files {
+			if !f.HasContent() {
+				continue
+			}
+			b, err := f.Bytes()
+			if err != nil {
+				t.Fatal(err)
+			}
+			files[path.Join(strings.TrimPrefix(pkg.Path, fixtureRoot+"/"), f.Name)] = string(b)
+		}
+	}
+	return files
+}
+
+// findPackage returns the package in pkgs with path rel relative to [fixtureRoot].
+func findPackage(t *testing.T, pkgs []*gen.Package, rel string) *gen.Package {
+	t.Helper()
+	for _, pkg := range pkgs {
+		if pkg.Path == fixtureRoot+"/"+rel {
+			return pkg
+		}
+	}
+	t.Fatalf("package %s not generated", rel)
+	return nil
+}
+
+// parseFile parses the generated Go file at path in files, returned by [generateSources].
+func parseFile(t *testing.T, files map[string]string, path string) *ast.File {
+	t.Helper()
+	src, ok := files[path]
+	if !ok {
+		t.Fatalf("file %s not generated", path)
+	}
+	return parseSource(t, path, src)
+}
+
+// parseSource parses Go source src of the file with name.
+func parseSource(t *testing.T, name, src string) *ast.File {
+	t.Helper()
+	f, err := parser.ParseFile(token.NewFileSet(), name, src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+// findDecl returns the declaration of name in f, and its doc comment, or nil if name is not declared.
+// The declaration of a function or method is an [*ast.FuncDecl], where a method is named "Type.Method".
+// The declaration of a type is an [*ast.TypeSpec], and of a constant or variable an [*ast.ValueSpec].
+func findDecl(f *ast.File, name string) (ast.Node, *ast.CommentGroup) {
+	recv, name, isMethod := strings.Cut(name, ".")
+	if !isMethod {
+		name, recv = recv, ""
+	}
+	for _, decl := range f.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			var r string
+			if decl.Recv != nil && len(decl.Recv.List) > 0 {
+				r = receiverName(decl.Recv.List[0].Type)
+			}
+			if decl.Name.Name == name && r == recv {
+				return decl, decl.Doc
+			}
+		case *ast.GenDecl:
+			if isMethod {
+				continue
+			}
+			for _, spec := range decl.Specs {
+				doc := decl.Doc
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					if spec.Doc != nil {
+						doc = spec.Doc
+					}
+					if spec.Name.Name == name {
+						return spec, doc
+					}
+				case *ast.ValueSpec:
+					if spec.Doc != nil {
+						doc = spec.Doc
+					}
+					for _, id := range spec.Names {
+						if id.Name == name {
+							return spec, doc
+						}
+					}
+				}
+			}
+		}
+	}
+	return nil, nil
+}
+
+// hasDecl returns true if name is declared in f. See [findDecl].
+func hasDecl(f *ast.File, name string) bool {
+	decl, _ := findDecl(f, name)
+	return decl != nil
+}
+
+// mustFunc returns the declaration of function or method name in f, or fails t if not declared.
+func mustFunc(t *testing.T, f *ast.File, name string) *ast.FuncDecl {
+	t.Helper()
+	decl, _ := findDecl(f, name)
+	fn, ok := decl.(*ast.FuncDecl)
+	if !ok {
+		t.Fatalf("%s: function %s not declared", f.Name.Name, name)
+	}
+	return fn
+}
+
+// mustType returns the declaration of type name in f, or fails t if not declared.
+func mustType(t *testing.T, f *ast.File, name string) *ast.TypeSpec {
+	t.Helper()
+	decl, _ := findDecl(f, name)
+	spec, ok := decl.(*ast.TypeSpec)
+	if !ok {
+		t.Fatalf("%s: type %s not declared", f.Name.Name, name)
+	}
+	return spec
+}
+
+// docText returns the text of the doc comment of the declaration of name in f,
+// with runs of white space replaced by a single space.
+func docText(f *ast.File, name string) string {
+	_, doc := findDecl(f, name)
+	return strings.Join(strings.Fields(doc.Text()), " ")
+}
+
+// calls returns the functions called in the body of n in order,
+// e.g. "cm.LowerString" or "cm.LiftChar[rune]".
+func calls(n ast.Node) []string {
+	var names []string
+	ast.Inspect(n, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			names = append(names, types.ExprString(call.Fun))
+		}
+		return true
+	})
+	return names
+}
+
+// goDirectives returns the directives in the doc comment of fn, e.g. "//go:wasmimport wasi:io/streams@0.2.0 drop".
+func goDirectives(fn *ast.FuncDecl) []string {
+	var lines []string
+	if fn.Doc == nil {
+		return nil
+	}
+	for _, c := range fn.Doc.List {
+		if strings.HasPrefix(c.Text, "//go:") || strings.HasPrefix(c.Text, "//export ") {
+			lines = append(lines, c.Text)
+		}
+	}
+	return lines
+}
+
+// funcsWithDirective returns the functions in f with a directive starting with prefix.
+func funcsWithDirective(f *ast.File, prefix string) []*ast.FuncDecl {
+	var funcs []*ast.FuncDecl
+	for _, decl := range f.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		for _, d := range goDirectives(fn) {
+			if strings.HasPrefix(d, prefix) {
+				funcs = append(funcs, fn)
+				break
+			}
+		}
+	}
+	return funcs
+}
+
+// cmAPILevel returns the cm API level required by f, declared as:
+//
+//	const _ uint = cm.APILevel - level
+func cmAPILevel(f *ast.File) (level string, ok bool) {
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			vs := spec.(*ast.ValueSpec)
+			if len(vs.Names) != 1 || vs.Names[0].Name != "_" || len(vs.Values) != 1 {
+				continue
+			}
+			if x, ok := vs.Values[0].(*ast.BinaryExpr); ok && types.ExprString(x.X) == "cm.APILevel" && x.Op == token.SUB {
+				return types.ExprString(x.Y), true
+			}
+		}
+	}
+	return "", false
+}
+
+// initAssign returns the value assigned to lhs in a func init in f, e.g. "run.Exports.Run",
+// or nil if not assigned.
+func initAssign(f *ast.File, lhs string) ast.Expr {
+	for _, decl := range f.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv != nil || fn.Name.Name != "init" {
+			continue
+		}
+		for _, stmt := range fn.Body.List {
+			assign, ok := stmt.(*ast.AssignStmt)
+			if !ok {
+				continue
+			}
+			for i, x := range assign.Lhs {
+				if types.ExprString(x) == lhs && i < len(assign.Rhs) {
+					return assign.Rhs[i]
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// fieldType returns the type of field name in struct type x.
+func fieldType(t *testing.T, x ast.Expr, name string) string {
+	t.Helper()
+	st, ok := x.(*ast.StructType)
+	if !ok {
+		t.Fatalf("%s is not a struct type", types.ExprString(x))
+	}
+	for _, field := range st.Fields.List {
+		for _, id := range field.Names {
+			if id.Name == name {
+				return types.ExprString(field.Type)
+			}
+		}
+	}
+	t.Fatalf("field %s not declared", name)
+	return ""
+}
+
+// exprSource returns the formatted Go source of x.
+func exprSource(t *testing.T, x ast.Expr) string {
+	t.Helper()
+	var b bytes.Buffer
+	if err := format.Node(&b, token.NewFileSet(), x); err != nil {
+		t.Fatal(err)
+	}
+	return b.String()
+}
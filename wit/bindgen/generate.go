@@ -6,6 +6,8 @@ import (
 )
 
 // Go generates one or more Go packages from [wit.Resolve] res.
+// Include statements in res must be resolved by [wit.Resolve.ResolveIncludes] first;
+// Go returns a [GenerateError] for a world with unresolved includes. Go does not modify res.
 // It returns any error that occurs during code generation.
 func Go(res *wit.Resolve, opts ...Option) ([]*gen.Package, error) {
 	g, err := newGenerator(res, opts...)
@@ -14,3 +16,40 @@ func Go(res *wit.Resolve, opts ...Option) ([]*gen.Package, error) {
 	}
 	return g.generate()
 }
+
+// Generate generates one or more Go packages from [wit.Resolve] res, like [Go].
+// If progress is non-nil, it is called synchronously after each WIT world or interface
+// is generated, allowing tools that embed the generator to report progress and
+// inspect partial results before generation is complete. Go packages are generated
+// in parallel: the Package of each [Progress] is not modified while progress is called,
+// but other packages may be. Steps are reported in the same order for each call.
+// It returns any error that occurs during code generation.
+func Generate(res *wit.Resolve, progress func(Progress), opts ...Option) ([]*gen.Package, error) {
+	g, err := newGenerator(res, opts...)
+	if err != nil {
+		return nil, err
+	}
+	g.progress = progress
+	return g.generate()
+}
+
+// Progress describes a single step of code generation, reported to the progress
+// callback passed to [Generate].
+type Progress struct {
+	// World is the WIT world being generated.
+	World *wit.World
+
+	// Owner is the WIT world or interface that was generated.
+	Owner wit.TypeOwner
+
+	// Name is the fully-qualified WIT name of Owner, e.g. "wasi:clocks/wall-clock@0.2.0".
+	Name string
+
+	// Direction is the direction Owner is imported into or exported from World.
+	// It is [wit.Exported] if Owner is World.
+	Direction wit.Direction
+
+	// Package is the Go package generated for Owner. Its files may continue to
+	// change as generation proceeds, for example as shared ABI helpers are added.
+	Package *gen.Package
+}
@@ -0,0 +1,1929 @@
+package bindgen
+
+import (
+	"errors"
+	"go/ast"
+	"go/doc/comment"
+	"go/token"
+	"go/types"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/bytecodealliance/wasm-tools-go/internal/go/gen"
+	"github.com/bytecodealliance/wasm-tools-go/wit"
+)
+
+func TestGenerateProgress(t *testing.T) {
+	res := loadFixture(t, "wasi/cli.wit.json")
+
+	var steps []Progress
+	pkgs, err := Generate(res, func(p Progress) {
+		steps = append(steps, p)
+	}, PackageRoot(fixtureRoot))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(steps) == 0 {
+		t.Fatal("progress was not called")
+	}
+	last := steps[len(steps)-1]
+	if last.Owner != last.World {
+		t.Errorf("last progress step: %s, expected world %s", last.Name, last.World.Name)
+	}
+	for _, p := range steps {
+		if p.Package == nil {
+			t.Errorf("progress step %s: nil Package", p.Name)
+		}
+		if p.Name == "" {
+			t.Errorf("progress step for %s: empty Name", p.Owner.WITKind())
+		}
+	}
+
+	// Each world and interface maps to at most one Go package.
+	if len(steps) < len(pkgs) {
+		t.Errorf("%d progress steps for %d packages", len(steps), len(pkgs))
+	}
+}
+
+// TestGenerateDeterministic tests that Go packages rendered in parallel are identical to
+// each other. Run it with -race to check that rendering does not race on generator state.
+func TestGenerateDeterministic(t *testing.T) {
+	res := loadFixture(t, "wasi/http.wit.json")
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+
+	generate := func() (map[string]string, []string) {
+		var steps []string
+		pkgs, err := Generate(res, func(p Progress) {
+			steps = append(steps, p.Name+" "+p.Direction.String())
+		}, PackageRoot(fixtureRoot), JSON(true), DynamicValues(true), MockImports(true))
+		if err != nil {
+			t.Fatal(err)
+		}
+		files := make(map[string]string)
+		for _, pkg := range pkgs {
+			for _, f := range pkg.Files {
+				b, err := f.Bytes()
+				if err != nil {
+					t.Fatal(err)
+				}
+				files[pkg.Path+"/"+f.Name] = string(b)
+			}
+		}
+		return files, steps
+	}
+
+	want, wantSteps := generate()
+	for range 4 {
+		got, steps := generate()
+		if !slices.Equal(steps, wantSteps) {
+			t.Errorf("progress steps:\n%v\nexpected:\n%v", steps, wantSteps)
+		}
+		if len(got) != len(want) {
+			t.Errorf("%d files, expected %d", len(got), len(want))
+		}
+		for path, src := range want {
+			if got[path] != src {
+				t.Errorf("%s differs between runs", path)
+			}
+		}
+	}
+}
+
+func TestGenerateInvalidResolve(t *testing.T) {
+	res := loadFixture(t, "wasi/cli.wit.json")
+	res.Worlds[0].Package = nil
+
+	_, err := Go(res, PackageRoot(fixtureRoot))
+	var verr *wit.ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("Go(): %v, expected *wit.ValidationError", err)
+	}
+	if got, want := verr.Message, "World has nil Package"; got != want {
+		t.Errorf("Message: %q, expected %q", got, want)
+	}
+}
+
+func TestGenerateAdversarialNames(t *testing.T) {
+	pkg := &wit.Package{Name: wit.Ident{Namespace: "../..", Package: "etc/passwd"}}
+	name := "../../../escape"
+	face := &wit.Interface{Name: &name, Package: pkg}
+	face.Functions.Set("f", &wit.Function{Name: "f", Kind: &wit.Freestanding{}})
+	w := &wit.World{Name: `..\world`, Package: pkg}
+	w.Imports.Set(name, &wit.InterfaceRef{Interface: face})
+	res := &wit.Resolve{
+		Packages:   []*wit.Package{pkg},
+		Worlds:     []*wit.World{w},
+		Interfaces: []*wit.Interface{face},
+	}
+
+	pkgs, err := Go(res, PackageRoot(fixtureRoot))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pkgs) == 0 {
+		t.Fatal("no packages generated")
+	}
+	for _, p := range pkgs {
+		if !strings.HasPrefix(p.Path, "example.com/gen/") {
+			t.Errorf("package path %q is outside of package root", p.Path)
+		}
+		if path.Clean(p.Path) != p.Path || strings.ContainsAny(p.Path, `\`) {
+			t.Errorf("package path %q is not clean", p.Path)
+		}
+		for _, elem := range strings.Split(p.Path, "/") {
+			if strings.Trim(elem, ".") == "" {
+				t.Errorf("package path %q contains element %q", p.Path, elem)
+			}
+		}
+	}
+}
+
+func TestGenerateBuildTags(t *testing.T) {
+	res := loadFixture(t, "wasi/cli.wit.json")
+
+	tests := []struct {
+		stubs bool
+		files map[string]string
+	}{
+		{false, map[string]string{
+			"environment.wit.go":  "wasip1 || wasip2",
+			"environment.wasm.go": "wasip1 || wasip2",
+		}},
+		{true, map[string]string{
+			"environment.wit.go":   "",
+			"environment.wasm.go":  "wasip1 || wasip2",
+			"environment.stubs.go": "!(wasip1 || wasip2)",
+		}},
+	}
+	for _, tt := range tests {
+		pkgs, err := Go(res, PackageRoot(fixtureRoot), BuildTags("wasip1 || wasip2"), Stubs(tt.stubs))
+		if err != nil {
+			t.Fatal(err)
+		}
+		env := findPackage(t, pkgs, "wasi/cli/environment")
+		for name, want := range tt.files {
+			f := env.Files[name]
+			if f == nil {
+				t.Errorf("Stubs(%t): file %s not generated", tt.stubs, name)
+				continue
+			}
+			if got := f.GoBuild; got != want {
+				t.Errorf("Stubs(%t): %s GoBuild: %q, expected %q", tt.stubs, name, got, want)
+			}
+		}
+		if got, want := string(env.Files["empty.s"].Content), "//go:build wasip1 || wasip2\n"; !strings.HasPrefix(got, want) {
+			t.Errorf("Stubs(%t): empty.s: %q, expected prefix %q", tt.stubs, got, want)
+		}
+		if !tt.stubs && env.Files["environment.stubs.go"] != nil {
+			t.Errorf("Stubs(%t): unexpected stubs file", tt.stubs)
+		}
+		if tt.stubs {
+			// The behavior of stubs is tested by TestGenerateTestdataBehavior.
+			f := parseFile(t, packageSources(t, pkgs), "wasi/cli/environment/environment.stubs.go")
+			if got := calls(mustFunc(t, f, "wasmimport_GetArguments")); !slices.Contains(got, "cm.LookupImport") {
+				t.Errorf("Stubs(%t): wasmimport_GetArguments calls %v, expected cm.LookupImport", tt.stubs, got)
+			}
+		}
+	}
+
+	_, err := Go(res, BuildTags("wasip2 &&"))
+	if err == nil {
+		t.Error("BuildTags(): expected error for invalid expression")
+	}
+}
+
+func TestGenerateExportsInstance(t *testing.T) {
+	f := parseFile(t, generateFixture(t, "wasi/cli.wit.json"), "wasi/cli/run/run.exports.go")
+	if _, ok := mustType(t, f, "ExportsInstance").Type.(*ast.StructType); !ok {
+		t.Error("ExportsInstance is not a struct type")
+	}
+	decl, _ := findDecl(f, "Exports")
+	if v, ok := decl.(*ast.ValueSpec); !ok || types.ExprString(v.Type) != "ExportsInstance" {
+		t.Error("var Exports ExportsInstance not declared")
+	}
+}
+
+func TestGenerateMockImports(t *testing.T) {
+	// The behavior of mocks is tested by TestGenerateTestdataBehavior.
+	files := generateFixture(t, "wasi/cli.wit.json", MockImports(true))
+	f := parseFile(t, files, "wasi/io/streams/streams.mocks.go")
+	mocks, ok := mustType(t, f, "MocksInstance").Type.(*ast.StructType)
+	if !ok {
+		t.Fatal("MocksInstance is not a struct type")
+	}
+	var fields []string
+	for _, field := range mocks.Fields.List {
+		for _, name := range field.Names {
+			fields = append(fields, name.Name)
+		}
+	}
+	for _, want := range []string{"InputStream", "OutputStream"} {
+		if !slices.Contains(fields, want) {
+			t.Errorf("MocksInstance fields %v, expected %s", fields, want)
+		}
+	}
+	if !hasDecl(f, "Mocks") {
+		t.Error("var Mocks not declared")
+	}
+}
+
+func TestNewBuild(t *testing.T) {
+	res := loadFixture(t, "wasi/cli.wit.json")
+
+	tests := []struct {
+		buildTags string
+		stubs     bool
+		host      bool
+		targets   []string
+	}{
+		{"", false, true, []string{"tinygo/wasip2/wasm", "tinygo/wasip1/wasm", "gc/wasip1/wasm"}},
+		{"wasip2", false, false, []string{"tinygo/wasip2/wasm"}},
+		{"wasip2", true, true, []string{"tinygo/wasip2/wasm"}},
+		{"wasip1 && !tinygo", false, false, []string{"gc/wasip1/wasm"}},
+		{"wasip2 && bindings", false, false, []string{"tinygo/wasip2/wasm[bindings]"}},
+		{"linux", false, false, nil},
+	}
+	for _, tt := range tests {
+		opts := []Option{PackageRoot(fixtureRoot), BuildTags(tt.buildTags), Stubs(tt.stubs)}
+		pkgs, err := Go(res, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := NewBuild(pkgs, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := b.GoVersion, GoVersion; got != want {
+			t.Errorf("BuildTags(%q): GoVersion: %q, expected %q", tt.buildTags, got, want)
+		}
+		if got, want := b.Host, tt.host; got != want {
+			t.Errorf("BuildTags(%q), Stubs(%t): Host: %t, expected %t", tt.buildTags, tt.stubs, got, want)
+		}
+		var targets []string
+		for _, target := range b.Targets {
+			s := target.Compiler + "/" + target.GOOS + "/" + target.GOARCH
+			if len(target.Tags) > 0 {
+				s += "[" + strings.Join(target.Tags, ",") + "]"
+			}
+			targets = append(targets, s)
+		}
+		if got, want := strings.Join(targets, " "), strings.Join(tt.targets, " "); got != want {
+			t.Errorf("BuildTags(%q): Targets: %q, expected %q", tt.buildTags, got, want)
+		}
+
+		var env *BuildPackage
+		for i := range b.Packages {
+			if b.Packages[i].Path == "example.com/gen/wasi/cli/environment" {
+				env = &b.Packages[i]
+			}
+			if i > 0 && b.Packages[i-1].Path >= b.Packages[i].Path {
+				t.Errorf("BuildTags(%q): Packages not sorted: %s >= %s", tt.buildTags, b.Packages[i-1].Path, b.Packages[i].Path)
+			}
+		}
+		if env == nil {
+			t.Fatal("package wasi/cli/environment not described")
+		}
+		if got, want := env.Dir, "wasi/cli/environment"; got != want {
+			t.Errorf("BuildTags(%q): Dir: %q, expected %q", tt.buildTags, got, want)
+		}
+		if !slices.Contains(env.Files, "environment.wasm.go") || !slices.IsSorted(env.Files) {
+			t.Errorf("BuildTags(%q): Files: %v, expected sorted files including environment.wasm.go", tt.buildTags, env.Files)
+		}
+	}
+}
+
+func TestManifest(t *testing.T) {
+	res := loadFixture(t, "wasi/cli.wit.json")
+	pkgs, err := Go(res, PackageRoot(fixtureRoot))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := &Manifest{PackageRoot: "example.com/gen"}
+	for _, pkg := range pkgs {
+		switch path.Base(pkg.Path) {
+		case "command", "environment":
+			rel := strings.TrimPrefix(pkg.Path, "example.com/gen/") + "/" + path.Base(pkg.Path) + ".wit.go"
+			m.Add(rel, pkg, []byte(pkg.Path))
+		}
+	}
+	m.Add("build.json", nil, []byte("{}\n"))
+
+	want := []ManifestFile{
+		{Path: "build.json", Package: "example.com/gen", SHA256: "ca3d163bab055381827226140568f3bef7eaac187cebd76878e0b63e9e442356"},
+		{Path: "wasi/cli/command/command.wit.go", Package: "example.com/gen/wasi/cli/command", World: "wasi:cli/command@0.2.0"},
+		{Path: "wasi/cli/environment/environment.wit.go", Package: "example.com/gen/wasi/cli/environment", Interface: "wasi:cli/environment@0.2.0"},
+	}
+	if len(m.Files) != len(want) {
+		t.Fatalf("Files: %d, expected %d: %v", len(m.Files), len(want), m.Files)
+	}
+	for i, f := range m.Files {
+		w := want[i]
+		if w.SHA256 == "" {
+			w.SHA256 = f.SHA256
+		}
+		if f != w {
+			t.Errorf("Files[%d]: %+v, expected %+v", i, f, w)
+		}
+	}
+}
+
+func TestGenerateLayoutTests(t *testing.T) {
+	// The generated layout tests are run by TestGenerateTestdataBehavior.
+	files := generateFixture(t, "wasi/cli.wit.json", LayoutTests(true))
+	f := parseFile(t, files, "wasi/clocks/wall-clock/wall-clock.layout_test.go")
+	if got := f.Name.Name; got != "wallclock" {
+		t.Errorf("wall-clock.layout_test.go: package %s, expected wallclock", got)
+	}
+	if got := types.ExprString(mustFunc(t, f, "TestLayout").Type); got != "func(t *testing.T)" {
+		t.Errorf("TestLayout: %s, expected func(t *testing.T)", got)
+	}
+}
+
+func TestGenerateTypeInfo(t *testing.T) {
+	// The behavior of type info is tested by TestGenerateTestdataBehavior.
+	files := generateFixture(t, "wasi/cli.wit.json", TypeInfo(true))
+	f := parseFile(t, files, "wasi/filesystem/types/types.types.go")
+	if !hasDecl(f, "WITTypes") {
+		t.Error("var WITTypes not declared")
+	}
+	for _, name := range []string{"ErrorCode", "NewTimestamp", "PathFlags", "DirectoryEntry"} {
+		if got := types.ExprString(mustFunc(t, f, name+".WITType").Type); got != "func() (*cm.TypeInfo, uint64)" {
+			t.Errorf("%s.WITType: %s", name, got)
+		}
+	}
+	if got := calls(mustFunc(t, f, "init")); !slices.Equal(got, []string{"cm.RegisterTypes"}) {
+		t.Errorf("init calls %v, expected cm.RegisterTypes", got)
+	}
+}
+
+func TestGeneratePrune(t *testing.T) {
+	res := loadFixture(t, "wasi/cli.wit.json")
+	tests := []struct {
+		prune bool
+		want  bool
+	}{
+		{false, true},
+		{true, false},
+	}
+	for _, tt := range tests {
+		files := generateSources(t, res, Prune(tt.prune))
+		f := parseFile(t, files, "wasi/sockets/tcp-create-socket/tcp-create-socket.wit.go")
+		// The network type alias is not used by any function in tcp-create-socket.
+		if got := hasDecl(f, "Network"); got != tt.want {
+			t.Errorf("Prune(%t): type Network declared: %t, expected %t", tt.prune, got, tt.want)
+		}
+		if !hasDecl(f, "CreateTCPSocket") {
+			t.Errorf("Prune(%t): function CreateTCPSocket not generated", tt.prune)
+		}
+	}
+}
+
+func TestGenerateExhaustive(t *testing.T) {
+	// The behavior of case functions is tested by TestGenerateTestdataBehavior.
+	for _, exhaustive := range []bool{false, true} {
+		files := generateFixture(t, "wasi/cli.wit.json", Exhaustive(exhaustive))
+		f := parseFile(t, files, "wasi/filesystem/types/types.wit.go")
+		for _, name := range []string{"ErrorCodeCases", "NewTimestampCase", "NewTimestampCases", "NewTimestamp.Case"} {
+			if got := hasDecl(f, name); got != exhaustive {
+				t.Errorf("Exhaustive(%t): %s declared: %t", exhaustive, name, got)
+			}
+		}
+	}
+}
+
+func TestGenerateEnumParse(t *testing.T) {
+	// The behavior of enum parsing is tested by TestGenerateTestdataBehavior.
+	files := generateFixture(t, "wasi/cli.wit.json", JSON(true))
+	f := parseFile(t, files, "wasi/filesystem/types/types.wit.go")
+	for name, want := range map[string]string{
+		"ParseErrorCode":          "func(s string) (ErrorCode, bool)",
+		"ErrorCode.IsValid":       "func() bool",
+		"ErrorCode.UnmarshalText": "func(text []byte) error",
+		"ParseDescriptorType":     "func(s string) (DescriptorType, bool)",
+	} {
+		if got := types.ExprString(mustFunc(t, f, name).Type); got != want {
+			t.Errorf("%s: %s, expected %s", name, got, want)
+		}
+	}
+}
+
+func TestGenerateEnumCheck(t *testing.T) {
+	// The behavior of enum checks is tested by TestGenerateTestdataBehavior.
+	res := loadFixture(t, "codegen/simple-enum.wit.json")
+	for _, enabled := range []bool{true, false} {
+		files := generateSources(t, res, EnumCheck(enabled))
+		f := parseFile(t, files, "foo/foo/enums/enums.wit.go")
+		got := calls(mustFunc(t, f, "E1Ret"))
+		if slices.Contains(got, "cm.LiftEnum[E2]") != enabled {
+			t.Errorf("EnumCheck(%t): E1Ret calls %v", enabled, got)
+		}
+	}
+}
+
+func TestGenerateCharCheck(t *testing.T) {
+	// The behavior of char checks is tested by TestGenerateTestdataBehavior.
+	res := loadFixture(t, "codegen/char.wit.json")
+	for _, enabled := range []bool{true, false} {
+		files := generateSources(t, res, CharCheck(enabled))
+		f := parseFile(t, files, "foo/foo/chars/chars.wit.go")
+		lower := slices.Contains(calls(mustFunc(t, f, "TakeChar")), "cm.LowerChar")
+		lift := slices.Contains(calls(mustFunc(t, f, "ReturnChar")), "cm.LiftChar[rune]")
+		if lower != enabled || lift != enabled {
+			t.Errorf("CharCheck(%t): calls cm.LowerChar: %t, cm.LiftChar: %t, expected %t", enabled, lower, lift, enabled)
+		}
+	}
+	validateGeneratedGo(t, res, "char-check", CharCheck(true))
+}
+
+func TestGenerateStringCheck(t *testing.T) {
+	// The behavior of strict string checks is tested by TestGenerateTestdataBehavior.
+	res := loadFixture(t, "wasi/cli.wit.json")
+	for mode, want := range map[string]string{
+		"":                "cm.LowerString",
+		StringCheckNone:   "cm.LowerString",
+		StringCheckStrict: "cm.LowerStringStrict",
+		StringCheckLossy:  "cm.LowerStringLossy",
+	} {
+		files := generateSources(t, res, StringCheck(mode))
+		f := parseFile(t, files, "wasi/filesystem/types/types.wit.go")
+		if got := calls(mustFunc(t, f, "Descriptor.OpenAt")); !slices.Contains(got, want) {
+			t.Errorf("StringCheck(%q): Descriptor.OpenAt calls %v, expected %s", mode, got, want)
+		}
+	}
+	validateGeneratedGo(t, res, "string-check", StringCheck(StringCheckStrict))
+
+	_, err := Go(res, StringCheck("utf-8"))
+	if err == nil {
+		t.Errorf("StringCheck(%q): expected error", "utf-8")
+	}
+}
+
+func TestGenerateConstructors(t *testing.T) {
+	// The behavior of constructors is tested by TestGenerateTestdataBehavior.
+	files := generateFixture(t, "wasi/cli.wit.json", Constructors(true))
+	f := parseFile(t, files, "wasi/filesystem/types/types.wit.go")
+	for name, want := range map[string]string{
+		"NewDirectoryEntry":         "func(type_ DescriptorType, name string) DirectoryEntry",
+		"DirectoryEntry.FieldsZero": "func() []string",
+	} {
+		if got := types.ExprString(mustFunc(t, f, name).Type); got != want {
+			t.Errorf("%s: %s, expected %s", name, got, want)
+		}
+	}
+}
+
+func TestGenerateDocsURL(t *testing.T) {
+	res := loadFixture(t, "wasi/cli.wit.json")
+	tests := []struct {
+		name string
+		opts []Option
+		want map[string]string // indexed by declaration name, or "package" for the package docs
+	}{
+		{"default", nil, map[string]string{
+			"package":     "See https://github.com/WebAssembly/wasi-io/blob/v0.2.0/wit/streams.wit for the upstream documentation.",
+			"StreamError": "See https://github.com/WebAssembly/wasi-io/blob/v0.2.0/wit/streams.wit for the upstream documentation.",
+		}},
+		{"template", []Option{DocsURL("wasi", "https://docs.example.com/{namespace}:{package}@{version}/{interface}#{item}")}, map[string]string{
+			"package":          "See https://docs.example.com/wasi:io@0.2.0/streams for the upstream documentation.",
+			"StreamError":      "See https://docs.example.com/wasi:io@0.2.0/streams#stream-error for the upstream documentation.",
+			"InputStream.Read": "See https://docs.example.com/wasi:io@0.2.0/streams#%5Bmethod%5Dinput-stream.read for the upstream documentation.",
+		}},
+		{"disabled", []Option{DocsURL("wasi", "")}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := parseFile(t, generateSources(t, res, tt.opts...), "wasi/io/streams/streams.wit.go")
+			for name, want := range tt.want {
+				got := docText(f, name)
+				if name == "package" {
+					got = strings.Join(strings.Fields(f.Doc.Text()), " ")
+				}
+				if !strings.Contains(got, want) {
+					t.Errorf("%s docs: %s\nexpected: %s", name, got, want)
+				}
+			}
+			if tt.want != nil {
+				return
+			}
+			for _, c := range f.Comments {
+				if strings.Contains(c.Text(), "upstream documentation") {
+					t.Errorf("streams.wit.go contains documentation link: %s", c.Text())
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateDocLinks(t *testing.T) {
+	files := generateFixture(t, "wasi/cli.wit.json")
+	tests := []struct {
+		file string
+		decl string
+		link *comment.DocLink
+	}{
+		{
+			"wasi/sockets/tcp/tcp.wit.go",
+			"InputStream",
+			&comment.DocLink{ImportPath: "example.com/gen/wasi/io/streams", Name: "InputStream"},
+		},
+		{
+			"wasi/io/streams/streams.wit.go",
+			"StreamError.LastOperationFailed",
+			&comment.DocLink{Name: "Error"},
+		},
+	}
+	for _, tt := range tests {
+		f := parseFile(t, files, tt.file)
+		_, doc := findDecl(f, tt.decl)
+		if doc == nil {
+			t.Errorf("%s: %s has no doc comment", tt.file, tt.decl)
+			continue
+		}
+		p := comment.Parser{
+			LookupSym: func(recv, name string) bool { return true },
+		}
+		var found bool
+		for _, block := range p.Parse(doc.Text()).Content {
+			para, ok := block.(*comment.Paragraph)
+			if !ok {
+				continue
+			}
+			for _, text := range para.Text {
+				if link, ok := text.(*comment.DocLink); ok && link.ImportPath == tt.link.ImportPath && link.Name == tt.link.Name {
+					found = true
+				}
+			}
+		}
+		if !found {
+			t.Errorf("%s: %s docs do not link to %+v:\n%s", tt.file, tt.decl, tt.link, doc.Text())
+		}
+	}
+}
+
+func TestGenerateStructTags(t *testing.T) {
+	// The JSON encoding of struct tags is tested by TestGenerateTestdataBehavior.
+	files := generateFixture(t, "wasi/cli.wit.json", JSON(true), StructTags(&StructTagConfig{
+		Default: map[string]string{"yaml": "snake,omitempty"},
+		Fields: map[string]map[string]string{
+			"wasi:filesystem/types#descriptor-stat.size":             {"json": "size_bytes", "yaml": ""},
+			"wasi:filesystem/types@0.2.0#descriptor-stat.link-count": {"json": "-"},
+		},
+	}))
+	f := parseFile(t, files, "wasi/filesystem/types/types.wit.go")
+	st, ok := mustType(t, f, "DescriptorStat").Type.(*ast.StructType)
+	if !ok {
+		t.Fatal("DescriptorStat is not a struct type")
+	}
+	tags := make(map[string]reflect.StructTag)
+	for _, field := range st.Fields.List {
+		if field.Tag == nil || len(field.Names) == 0 {
+			continue
+		}
+		tag, err := strconv.Unquote(field.Tag.Value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tags[field.Names[0].Name] = reflect.StructTag(tag)
+	}
+	for _, tt := range []struct {
+		field, json, yaml string
+	}{
+		{"Type", "type", "type,omitempty"},
+		{"LinkCount", "-", "link_count,omitempty"},
+		{"Size", "size_bytes", ""},
+		{"DataAccessTimestamp", "data-access-timestamp", "data_access_timestamp,omitempty"},
+	} {
+		tag := tags[tt.field]
+		if got := tag.Get("json"); got != tt.json {
+			t.Errorf("DescriptorStat.%s: json tag %q, expected %q", tt.field, got, tt.json)
+		}
+		if got := tag.Get("yaml"); got != tt.yaml {
+			t.Errorf("DescriptorStat.%s: yaml tag %q, expected %q", tt.field, got, tt.yaml)
+		}
+	}
+}
+
+func TestStructTagsInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *StructTagConfig
+	}{
+		{"unknown policy", &StructTagConfig{Default: map[string]string{"json": "upper"}}},
+		{"invalid key", &StructTagConfig{Default: map[string]string{"json:": "kebab"}}},
+		{"unqualified field", &StructTagConfig{Fields: map[string]map[string]string{"size": {"json": "s"}}}},
+		{"backquote", &StructTagConfig{Fields: map[string]map[string]string{"a:b/c#d.e": {"json": "`"}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts options
+			err := opts.apply(StructTags(tt.config))
+			if err == nil {
+				t.Errorf("StructTags(%v): expected error", tt.config)
+			}
+		})
+	}
+}
+
+func TestGenerateDualVersion(t *testing.T) {
+	// Versioned Go packages are detected without the Versioned option.
+	files := generateFixture(t, "codegen/dual-version.wit.json", World("example:dual-version/app"))
+	for _, tt := range []struct {
+		path string
+		fn   string
+		want string
+	}{
+		{"wasi/io/v0.2.0/streams/streams.wasm.go", "wasmimport_OutputStreamWrite", "//go:wasmimport wasi:io/streams@0.2.0 [method]output-stream.write"},
+		{"wasi/io/v0.2.1/streams/streams.wasm.go", "wasmimport_OutputStreamFlush", "//go:wasmimport wasi:io/streams@0.2.1 [method]output-stream.flush"},
+	} {
+		f := parseFile(t, files, tt.path)
+		if got := goDirectives(mustFunc(t, f, tt.fn)); !slices.Contains(got, tt.want) {
+			t.Errorf("%s: %s directives %v, expected %s", tt.path, tt.fn, got, tt.want)
+		}
+	}
+	f := parseFile(t, files, "wasi/io/v0.2.0/streams/streams.wasm.go")
+	if hasDecl(f, "wasmimport_OutputStreamFlush") {
+		t.Errorf("wasi:io/streams@0.2.0 contains flush from wasi:io/streams@0.2.1")
+	}
+	f = parseFile(t, files, "wasi/io/v0.2.1/streams/streams.wit.go")
+	if got, want := docText(f, "OutputStream"), `represents the imported resource "wasi:io/streams@0.2.1#output-stream"`; !strings.Contains(got, want) {
+		t.Errorf("OutputStream docs: %s\nexpected: %s", got, want)
+	}
+
+	f = parseFile(t, files, "example/dual-version/bridge/bridge.wit.go")
+	imports := make(map[string]string)
+	for _, spec := range f.Imports {
+		var name string
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		imports[strings.Trim(spec.Path.Value, `"`)] = name
+	}
+	for path, want := range map[string]string{
+		"example.com/gen/wasi/io/v0.2.0/streams": "",
+		"example.com/gen/wasi/io/v0.2.1/streams": "streams_",
+	} {
+		if got, ok := imports[path]; !ok || got != want {
+			t.Errorf("bridge.wit.go: import %q as %q, expected %q", path, got, want)
+		}
+	}
+
+	for path := range files {
+		if strings.HasPrefix(path, "wasi/io/streams/") {
+			t.Errorf("unversioned file %s generated", path)
+		}
+	}
+}
+
+func TestGenerateMixedVersions(t *testing.T) {
+	res := loadFixture(t, "codegen/mixed-versions.wit.json")
+	tests := []struct {
+		name      string
+		versioned bool
+		want      []string
+	}{
+		{
+			"detected",
+			false,
+			[]string{
+				"example.com/gen/example/mixed-versions/app-types",
+				"example.com/gen/example/util/types",
+				"example.com/gen/example/util/v1.0.0/types",
+				"example.com/gen/wasi/clocks/monotonic-clock",
+			},
+		},
+		{
+			"versioned",
+			true,
+			[]string{
+				"example.com/gen/example/mixed-versions/app-types",
+				"example.com/gen/example/util/types",
+				"example.com/gen/example/util/v1.0.0/types",
+				"example.com/gen/wasi/clocks/v0.2.0/monotonic-clock",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pkgs, err := Go(res, PackageRoot(fixtureRoot), World("example:mixed-versions/app"), Versioned(tt.versioned))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, pkg := range pkgs {
+				if len(pkg.Files) > 0 && !strings.HasSuffix(pkg.Path, "/app") {
+					got = append(got, pkg.Path)
+				}
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Go package paths:\n%s\nexpected:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestGenerateDebug(t *testing.T) {
+	// The behavior of handle checks is tested by TestGenerateTestdataBehavior.
+	files := generateFixture(t, "wasi/cli.wit.json", Debug(true))
+	for _, tt := range []struct {
+		path string
+		fn   string
+		want []string
+	}{
+		{"wasi/io/streams/streams.wit.go", "InputStream.ResourceDrop", []string{"cm.TraceImport", "cm.DebugDrop"}},
+		{"wasi/io/streams/streams.wit.go", "InputStream.Read", []string{"cm.TraceImport", "cm.DebugUse"}},
+		{"wasi/io/streams/streams.wit.go", "OutputStream.Splice", []string{"cm.TraceImport", "cm.DebugUse", "cm.DebugUse"}},
+		{"wasi/cli/stdin/stdin.wit.go", "GetStdin", []string{"cm.TraceImport", "cm.DebugAcquire"}},
+		{"wasi/cli/run/run.wasm.go", "wasmexport_Run", []string{"cm.TraceExport"}},
+	} {
+		f := parseFile(t, files, tt.path)
+		var got []string
+		for _, name := range calls(mustFunc(t, f, tt.fn)) {
+			if strings.HasPrefix(name, "cm.Trace") || strings.HasPrefix(name, "cm.Debug") {
+				got = append(got, name)
+			}
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: %s calls %v, expected %v", tt.path, tt.fn, got, tt.want)
+		}
+	}
+}
+
+func TestGenerateEmptyAsm(t *testing.T) {
+	res := loadFixture(t, "wasi/cli.wit.json")
+	for _, mode := range []string{"", EmptyAsmAuto, EmptyAsmAlways, EmptyAsmNever} {
+		pkgs, err := Go(res, PackageRoot(fixtureRoot), EmptyAsm(mode))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var withAsm int
+		for _, pkg := range pkgs {
+			if !pkg.HasContent() {
+				continue
+			}
+			var imports bool
+			for name, f := range pkg.Files {
+				if !strings.HasSuffix(name, ".go") || !f.HasContent() {
+					continue
+				}
+				b, err := f.Bytes()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(funcsWithDirective(parseSource(t, name, string(b)), "//go:wasmimport ")) > 0 {
+					imports = true
+				}
+			}
+			var want bool
+			switch mode {
+			case "", EmptyAsmAuto:
+				want = imports
+			case EmptyAsmAlways:
+				want = true
+			}
+			f := pkg.Files["empty.s"]
+			got := f != nil && f.HasContent()
+			if got {
+				withAsm++
+			}
+			if got != want {
+				t.Errorf("EmptyAsm(%q): %s: empty.s: %t, expected %t", mode, pkg.Path, got, want)
+			}
+		}
+		if withAsm == 0 && mode != EmptyAsmNever {
+			t.Errorf("EmptyAsm(%q): no packages with empty.s", mode)
+		}
+	}
+
+	// Exported functions have a body, so packages with only exports do not need empty.s.
+	pkgs, err := Go(res, PackageRoot(fixtureRoot))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f := findPackage(t, pkgs, "wasi/cli/run").Files["empty.s"]; f != nil && f.HasContent() {
+		t.Errorf("wasi/cli/run: unexpected empty.s in package with only exported functions")
+	}
+
+	_, err = Go(res, EmptyAsm("sometimes"))
+	if err == nil {
+		t.Error("EmptyAsm(\"sometimes\"): expected error")
+	}
+}
+
+func TestGenerateTarget(t *testing.T) {
+	res := loadFixture(t, "wasi/cli.wit.json")
+	tests := []struct {
+		target     string
+		wasmexport bool
+		export     bool
+		emptyAsm   bool
+		compilers  []string
+	}{
+		{"", true, true, true, []string{"tinygo", "tinygo", "gc"}},
+		{TargetAny, true, true, true, []string{"tinygo", "tinygo", "gc"}},
+		{TargetGo, true, false, true, []string{"gc"}},
+		{TargetTinyGo, false, true, false, []string{"tinygo", "tinygo"}},
+	}
+	for _, tt := range tests {
+		opts := []Option{PackageRoot(fixtureRoot), Target(tt.target)}
+		pkgs, err := Go(res, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var withAsm bool
+		for _, pkg := range pkgs {
+			if f := pkg.Files["empty.s"]; f != nil && f.HasContent() {
+				withAsm = true
+			}
+		}
+		f := parseFile(t, packageSources(t, pkgs), "wasi/cli/run/run.wasm.go")
+		directives := goDirectives(mustFunc(t, f, "wasmexport_Run"))
+		if got := slices.Contains(directives, "//go:wasmexport wasi:cli/run@0.2.0#run"); got != tt.wasmexport {
+			t.Errorf("Target(%q): //go:wasmexport: %t, expected %t", tt.target, got, tt.wasmexport)
+		}
+		if got := slices.Contains(directives, "//export wasi:cli/run@0.2.0#run"); got != tt.export {
+			t.Errorf("Target(%q): //export: %t, expected %t", tt.target, got, tt.export)
+		}
+		if withAsm != tt.emptyAsm {
+			t.Errorf("Target(%q): empty.s: %t, expected %t", tt.target, withAsm, tt.emptyAsm)
+		}
+
+		b, err := NewBuild(pkgs, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var compilers []string
+		for _, target := range b.Targets {
+			compilers = append(compilers, target.Compiler)
+		}
+		if !slices.Equal(compilers, tt.compilers) {
+			t.Errorf("Target(%q): build targets %v, expected %v", tt.target, compilers, tt.compilers)
+		}
+	}
+
+	// An explicit empty.s mode applies to every target.
+	pkgs, err := Go(res, PackageRoot(fixtureRoot), Target(TargetTinyGo), EmptyAsm(EmptyAsmAlways))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, pkg := range pkgs {
+		if f := pkg.Files["empty.s"]; pkg.HasContent() && (f == nil || !f.HasContent()) {
+			t.Errorf("Target(%q), EmptyAsm(%q): %s: missing empty.s", TargetTinyGo, EmptyAsmAlways, pkg.Path)
+		}
+	}
+
+	_, err = Go(res, Target("gccgo"))
+	if err == nil {
+		t.Error("Target(\"gccgo\"): expected error")
+	}
+}
+
+func TestGenerateArenaParams(t *testing.T) {
+	// The behavior of arena params is tested by TestGenerateTestdataBehavior.
+	pkg := &wit.Package{Name: wit.Ident{Namespace: "example", Package: "arena"}}
+	name := "calls"
+	face := &wit.Interface{Name: &name, Package: pkg}
+	f := &wit.Function{Name: "send", Kind: &wit.Freestanding{}}
+	for i := range 9 {
+		f.Params = append(f.Params, wit.Param{Name: "s" + strconv.Itoa(i), Type: wit.String{}})
+	}
+	face.Functions.Set(f.Name, f)
+	face.Functions.Set("small", &wit.Function{Name: "small", Kind: &wit.Freestanding{}, Params: []wit.Param{{Name: "x", Type: wit.U32{}}}})
+	w := &wit.World{Name: "imports", Package: pkg}
+	w.Imports.Set(name, &wit.InterfaceRef{Interface: face})
+	pkg.Interfaces.Set(name, face)
+	pkg.Worlds.Set(w.Name, w)
+	res := &wit.Resolve{
+		Packages:   []*wit.Package{pkg},
+		Worlds:     []*wit.World{w},
+		Interfaces: []*wit.Interface{face},
+	}
+
+	for _, arena := range []bool{false, true} {
+		f := parseFile(t, generateSources(t, res, ArenaParams(arena)), "example/arena/calls/calls.wit.go")
+		send := calls(mustFunc(t, f, "Send"))
+		for _, name := range []string{"cm.DefaultArena.Mark", "cm.ArenaNew", "cm.DefaultArena.Release", "runtime.KeepAlive"} {
+			if got := slices.Contains(send, name); got != arena {
+				t.Errorf("ArenaParams(%t): Send calls %s: %t", arena, name, got)
+			}
+		}
+		if !slices.Contains(send, "wasmimport_Send") {
+			t.Errorf("ArenaParams(%t): Send does not call wasmimport_Send", arena)
+		}
+		if slices.Contains(calls(mustFunc(t, f, "Small")), "cm.ArenaNew") {
+			t.Errorf("ArenaParams(%t): Small with flat params calls cm.ArenaNew", arena)
+		}
+	}
+}
+
+func TestGenerateStackUsage(t *testing.T) {
+	res := loadFixture(t, "wasi/cli.wit.json")
+	for _, stack := range []bool{false, true} {
+		files := generateSources(t, res, StackUsage(stack))
+		for _, tt := range []struct {
+			path string
+			fn   string
+			want string
+		}{
+			{"wasi/cli/run/run.wasm.go", "wasmexport_Run", "cm.StackEnter"},
+			{"wasi/cli/run/run.wasm.go", "wasmexport_Run", "cm.StackExit"},
+			{"wasi/io/streams/streams.wit.go", "InputStream.Read", "cm.StackProbe"},
+			{"wasi/cli/environment/environment.wit.go", "GetArguments", "cm.StackProbe"},
+		} {
+			fn := mustFunc(t, parseFile(t, files, tt.path), tt.fn)
+			if got := slices.Contains(calls(fn), tt.want); got != stack {
+				t.Errorf("StackUsage(%t): %s: %s calls %s: %t", stack, tt.path, tt.fn, tt.want, got)
+			}
+		}
+	}
+}
+
+func TestGenerateWasmExportPointers(t *testing.T) {
+	tests := []struct {
+		path string
+		file string
+		want map[string]string // indexed by function name
+	}{
+		{
+			"codegen/just-export.wit.json",
+			"foo/foo/foo/foo.wasm.go",
+			map[string]string{
+				"wasmexport_Generate": "func(name0Ptr unsafe.Pointer, name1 uint32, wit0Ptr unsafe.Pointer, wit1 uint32) (resultPtr unsafe.Pointer)",
+			},
+		},
+		{
+			"example/non-flat-params.wit.json",
+			"example/non-flat-params/corner-case/cornercase.wasm.go",
+			map[string]string{
+				"wasmexport_WindF16U32": "func(paramsPtr unsafe.Pointer) (result0 uint32)",
+			},
+		},
+	}
+	for _, tt := range tests {
+		f := parseFile(t, generateFixture(t, tt.path), tt.file)
+		for name, want := range tt.want {
+			if got := types.ExprString(mustFunc(t, f, name).Type); got != want {
+				t.Errorf("%s: %s: %s\nexpected: %s", tt.file, name, got, want)
+			}
+		}
+		for _, fn := range funcsWithDirective(f, "//go:wasmexport ") {
+			ast.Inspect(fn.Type, func(n ast.Node) bool {
+				if _, ok := n.(*ast.StarExpr); ok {
+					t.Errorf("%s: go:wasmexport function with pointer param or result: %s%s", tt.file, fn.Name.Name, types.ExprString(fn.Type))
+					return false
+				}
+				return true
+			})
+		}
+	}
+}
+
+func TestGenerateLargeTuples(t *testing.T) {
+	res := loadFixture(t, "example/tuples.wit.json")
+	i := res.FindInterface("example:tuples/tuples")
+	if i == nil {
+		t.Fatal("interface example:tuples/tuples not found")
+	}
+	// Extend named type t10 and the anonymous param of g10 to 18 fields.
+	extra := []wit.Type{wit.S32{}, wit.S64{}, wit.Char{}, wit.String{}, wit.Bool{}, wit.U8{}, wit.U16{}, wit.U32{}}
+	named := i.FindTypeDef("t10").Kind.(*wit.Tuple)
+	named.Types = append(named.Types, extra...)
+	anon := i.FindFunction("g10").Params[0].Type.(*wit.TypeDef).Kind.(*wit.Tuple)
+	anon.Types = append(anon.Types, extra...)
+
+	files := generateSources(t, res)
+	f := parseFile(t, files, "example/tuples/tuples/abi.go")
+	spec := mustType(t, f, "Tuple18")
+	var params []string
+	for _, field := range spec.TypeParams.List {
+		for _, name := range field.Names {
+			params = append(params, name.Name)
+		}
+	}
+	var fields []string
+	for _, field := range spec.Type.(*ast.StructType).Fields.List {
+		for _, name := range field.Names {
+			if name.Name != "_" {
+				fields = append(fields, name.Name+" "+types.ExprString(field.Type))
+			}
+		}
+	}
+	if len(params) != 18 || len(fields) != 18 {
+		t.Errorf("Tuple18: %d type params and %d fields, expected 18", len(params), len(fields))
+	}
+	for i := range fields {
+		if want := "F" + strconv.Itoa(i) + " T" + strconv.Itoa(i); fields[i] != want {
+			t.Errorf("Tuple18: field %d: %s, expected %s", i, fields[i], want)
+		}
+	}
+	if !hasDecl(f, "Tuple18.Values") {
+		t.Error("Tuple18.Values not declared")
+	}
+
+	f = parseFile(t, files, "example/tuples/tuples/tuples.wit.go")
+	want := "Tuple18[string, bool, uint8, uint16, uint32, uint64, float32, float64, int8, int16, int32, int64, rune, string, bool, uint8, uint16, uint32]"
+	if got := types.ExprString(mustType(t, f, "T10").Type); got != want {
+		t.Errorf("T10: %s\nexpected: %s", got, want)
+	}
+	if got := types.ExprString(mustFunc(t, f, "G10").Type.Params.List[0].Type); !strings.HasPrefix(got, "Tuple18[") {
+		t.Errorf("G10: param type %s, expected Tuple18", got)
+	}
+
+	var n int
+	for path := range files {
+		if strings.HasPrefix(path, "example/tuples/tuples/") && strings.HasSuffix(path, ".go") && hasDecl(parseFile(t, files, path), "Tuple18") {
+			n++
+		}
+	}
+	if n != 1 {
+		t.Errorf("Tuple18 declared in %d files", n)
+	}
+
+	validateGeneratedGo(t, res, "large-tuples")
+}
+
+func TestGenerateRename(t *testing.T) {
+	res := loadFixture(t, "wasi/cli.wit.json")
+	opts := []Option{
+		Rename("wasi:io/streams#input-stream", "Reader"),
+		Rename("wasi:io/streams@0.2.0#stream-error", "StreamErr"),
+		Rename("wasi:cli/environment#get-environment", "Environ"),
+	}
+	files := generateSources(t, res, opts...)
+	for _, tt := range []struct {
+		path  string
+		name  string
+		alias bool
+		want  string
+	}{
+		{"wasi/io/streams/streams.wit.go", "Reader", false, "cm.Resource"},
+		{"wasi/io/streams/streams.wit.go", "StreamErr", false, "cm.Variant[uint8, Error, Error]"},
+		{"wasi/io/streams/streams.wit.go", "InputStream", true, "Reader"},
+		{"wasi/io/streams/streams.wit.go", "StreamError", true, "StreamErr"},
+		{"wasi/cli/stdin/stdin.wit.go", "InputStream", true, "streams.Reader"},
+	} {
+		spec := mustType(t, parseFile(t, files, tt.path), tt.name)
+		if got := types.ExprString(spec.Type); got != tt.want || spec.Assign.IsValid() != tt.alias {
+			t.Errorf("%s: type %s (alias %t): %s, expected %s (alias %t)", tt.path, tt.name, spec.Assign.IsValid(), got, tt.want, tt.alias)
+		}
+	}
+	if f := parseFile(t, files, "wasi/io/streams/streams.wit.go"); !hasDecl(f, "Reader.Read") {
+		t.Error("Reader.Read not declared")
+	}
+	f := parseFile(t, files, "wasi/cli/environment/environment.wit.go")
+	if got, want := types.ExprString(mustFunc(t, f, "Environ").Type), "func() (result cm.List[[2]string])"; got != want {
+		t.Errorf("Environ: %s, expected %s", got, want)
+	}
+	validateGeneratedGo(t, res, "rename", opts...)
+
+	for _, tt := range []struct {
+		ident, goName string
+	}{
+		{"wasi:io/streams#nonexistent", "Foo"},
+		{"wasi:io/nonexistent#input-stream", "Foo"},
+		{"streams#input-stream", "Foo"},
+		{"wasi:io/streams", "Foo"},
+		{"wasi:io/streams#input-stream", "reader"},
+		{"wasi:io/streams#input-stream", "Read er"},
+	} {
+		_, err := Go(res, Rename(tt.ident, tt.goName))
+		if err == nil {
+			t.Errorf("Rename(%q, %q): expected error", tt.ident, tt.goName)
+		}
+	}
+}
+
+func TestGenerateDirectives(t *testing.T) {
+	load := func(t *testing.T, docs map[string]string) *wit.Resolve {
+		res := loadFixture(t, "wasi/cli.wit.json")
+		for ident, contents := range docs {
+			owner, name, _ := strings.Cut(ident, "#")
+			i := res.FindInterface(owner)
+			if i == nil {
+				t.Fatalf("interface %s not found", owner)
+			}
+			if td := i.TypeDefs.Get(name); td != nil {
+				td.Docs.Contents = contents
+			} else if f := i.Functions.Get(name); f != nil {
+				f.Docs.Contents = contents
+			} else {
+				t.Fatalf("%s not found", ident)
+			}
+		}
+		return res
+	}
+
+	res := load(t, map[string]string{
+		"wasi:cli/environment#get-environment":       "Returns the environment.\nwit-bindgen-go:name Environ\n",
+		"wasi:cli/environment#initial-cwd":           "wit-bindgen-go:skip",
+		"wasi:io/streams#input-stream":               "wit-bindgen-go:name Reader",
+		"wasi:cli/terminal-input#terminal-input":     "wit-bindgen-go:skip",
+		"wasi:cli/terminal-stdin#terminal-input":     "wit-bindgen-go:skip",
+		"wasi:cli/terminal-stdin#get-terminal-stdin": "wit-bindgen-go:skip",
+	})
+	opts := []Option{
+		Rename("wasi:io/streams#input-stream", "InputReader"),
+	}
+	files := generateSources(t, res, opts...)
+	f := parseFile(t, files, "wasi/cli/environment/environment.wit.go")
+	if got, want := docText(f, "Environ"), `Environ represents the imported function "get-environment". Returns the environment.`; !strings.HasPrefix(got, want) {
+		t.Errorf("Environ docs: %s\nexpected: %s", got, want)
+	}
+	for _, c := range f.Comments {
+		if strings.Contains(c.Text(), "wit-bindgen-go:") {
+			t.Errorf("environment.wit.go: unexpected directive in comment: %s", c.Text())
+		}
+	}
+	for _, tt := range []struct {
+		path string
+		name string
+		want bool
+	}{
+		{"wasi/cli/environment/environment.wit.go", "Environ", true},
+		{"wasi/cli/environment/environment.wit.go", "InitialCWD", false},
+		{"wasi/io/streams/streams.wit.go", "InputReader", true},
+		{"wasi/cli/terminal-input/terminal-input.wit.go", "TerminalInput", false},
+		{"wasi/cli/terminal-stdin/terminal-stdin.wit.go", "GetTerminalStdin", false},
+	} {
+		if got := hasDecl(parseFile(t, files, tt.path), tt.name); got != tt.want {
+			t.Errorf("%s: %s declared: %t, expected %t", tt.path, tt.name, got, tt.want)
+		}
+	}
+	validateGeneratedGo(t, res, "directives", opts...)
+
+	for _, docs := range []map[string]string{
+		{"wasi:cli/environment#initial-cwd": "wit-bindgen-go:unknown"},
+		{"wasi:cli/environment#initial-cwd": "wit-bindgen-go:name initialCWD"},
+		{"wasi:cli/environment#initial-cwd": "wit-bindgen-go:skip please"},
+		{"wasi:io/streams#[method]input-stream.read": "wit-bindgen-go:name ReadBytes"},
+		{"wasi:io/error#error": "wit-bindgen-go:skip"},
+		{"wasi:cli/terminal-input#terminal-input": "wit-bindgen-go:skip"},
+	} {
+		_, err := Go(load(t, docs))
+		if err == nil {
+			t.Errorf("Go(%v): expected error", docs)
+		}
+	}
+}
+
+func TestGenerateCallHooks(t *testing.T) {
+	// The behavior of call hooks is tested by TestGenerateTestdataBehavior.
+	res := loadFixture(t, "wasi/cli.wit.json")
+	for _, hooks := range []bool{false, true} {
+		files := generateSources(t, res, CallHooks(hooks))
+		for _, tt := range []struct {
+			path string
+			fn   string
+		}{
+			{"wasi/random/random/random.wit.go", "GetRandomBytes"},
+			{"wasi/io/streams/streams.wit.go", "InputStream.Read"},
+			{"wasi/cli/exit/exit.wit.go", "Exit"},
+		} {
+			f := parseFile(t, files, tt.path)
+			if got := slices.Contains(calls(mustFunc(t, f, tt.fn)), "cm.LoadCallHook"); got != hooks {
+				t.Errorf("CallHooks(%t): %s: %s calls cm.LoadCallHook: %t", hooks, tt.path, tt.fn, got)
+			}
+			if got := len(funcsWithDirective(f, "//go:nosplit")) > 0; got == hooks {
+				t.Errorf("CallHooks(%t): %s: contains //go:nosplit: %t", hooks, tt.path, got)
+			}
+		}
+	}
+	validateGeneratedGo(t, res, "call-hooks", CallHooks(true))
+}
+
+func TestGenerateBinaryMarshal(t *testing.T) {
+	// The behavior of binary marshaling is tested by TestGenerateTestdataBehavior.
+	files := generateFixture(t, "wasi/cli.wit.json", BinaryMarshal(true))
+	f := parseFile(t, files, "wasi/clocks/wall-clock/wall-clock.wit.go")
+	for name, want := range map[string]string{
+		"DateTime.MarshalBinary":   "cm.MarshalBinary",
+		"DateTime.UnmarshalBinary": "cm.UnmarshalBinary",
+	} {
+		if got := calls(mustFunc(t, f, name)); !slices.Contains(got, want) {
+			t.Errorf("%s calls %v, expected %s", name, got, want)
+		}
+	}
+
+	// Types with resource handles have no binary marshaling methods.
+	f = parseFile(t, files, "wasi/io/streams/streams.wit.go")
+	for _, decl := range f.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv != nil && fn.Name.Name == "MarshalBinary" {
+			t.Errorf("streams.wit.go: %s.MarshalBinary declared", receiverName(fn.Recv.List[0].Type))
+		}
+	}
+}
+
+func TestGenerateCMAPICheck(t *testing.T) {
+	res := loadFixture(t, "wasi/cli.wit.json")
+	for _, check := range []bool{true, false} {
+		pkgs, err := Go(res, PackageRoot(fixtureRoot), CMAPICheck(check), BuildTags("wasip2"), Stubs(true))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, pkg := range pkgs {
+			var imports bool
+			var checks []string
+			for name, f := range pkg.Files {
+				if f.Imports[cmPackage] != "" {
+					imports = true
+				}
+				if !strings.HasSuffix(name, ".go") || !f.HasContent() {
+					continue
+				}
+				b, err := f.Bytes()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if _, ok := cmAPILevel(parseSource(t, name, string(b))); ok {
+					checks = append(checks, name)
+					if f.GoBuild != "" {
+						t.Errorf("CMAPICheck(%t): %s/%s: API level declared in file with build constraint %s", check, pkg.Path, name, f.GoBuild)
+					}
+				}
+			}
+			n := 0
+			if check && imports {
+				n = 1
+			}
+			if len(checks) != n {
+				t.Errorf("CMAPICheck(%t): %s: API level declared in %v, expected %d file(s)", check, pkg.Path, checks, n)
+			}
+		}
+	}
+}
+
+func TestGenerateCMAPILevel(t *testing.T) {
+	tests := []struct {
+		path  string
+		opts  []Option
+		level int
+	}{
+		{"codegen/variants.wit.json", nil, 2},
+		{"codegen/strings.wit.json", nil, 1},
+		{"codegen/strings.wit.json", []Option{StringCheck(StringCheckStrict)}, 6},
+		{"codegen/char.wit.json", []Option{CharCheck(true)}, 7},
+		{"codegen/lists.wit.json", []Option{DeepCopy(true)}, 8},
+	}
+	for _, tt := range tests {
+		opts := append([]Option{PackageRoot(fixtureRoot)}, tt.opts...)
+		pkgs, err := Go(loadFixture(t, tt.path), opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files := packageSources(t, pkgs)
+		var found bool
+		for path := range files {
+			if !strings.HasSuffix(path, ".go") {
+				continue
+			}
+			if level, ok := cmAPILevel(parseFile(t, files, path)); ok && level == strconv.Itoa(tt.level) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("%s: API level %d not declared", tt.path, tt.level)
+		}
+		b, err := NewBuild(pkgs, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b.CMAPILevel != tt.level {
+			t.Errorf("%s: Build.CMAPILevel == %d, expected %d", tt.path, b.CMAPILevel, tt.level)
+		}
+	}
+}
+
+func BenchmarkGenerate(b *testing.B) {
+	res, err := wit.LoadJSON(testdataPath + "/wasi/http.wit.json")
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := Go(res, PackageRoot(fixtureRoot))
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestGenerateIncludes(t *testing.T) {
+	res := loadFixture(t, "wasi/cli.wit.json")
+	var imports *wit.World
+	for _, w := range res.Worlds {
+		if w.Match("wasi:cli/imports") {
+			imports = w
+		}
+	}
+	if imports == nil {
+		t.Fatal("world wasi:cli/imports not found")
+	}
+
+	// world app { include wasi:cli/imports; export run: func(); }
+	app := &wit.World{Name: "app", Package: imports.Package, Includes: []*wit.Include{{World: imports}}}
+	app.Exports.Set("run", &wit.Function{Name: "run", Kind: &wit.Freestanding{}})
+	res.Worlds = append(res.Worlds, app)
+	imports.Package.Worlds.Set("app", app)
+
+	// Go does not resolve includes, which would modify res.
+	_, err := Go(res, World("app"), PackageRoot(fixtureRoot))
+	var gerr *GenerateError
+	if !errors.As(err, &gerr) {
+		t.Fatalf("Go(): %v, expected *GenerateError", err)
+	}
+	if gerr.Owner != "app" {
+		t.Errorf("Owner: %q, expected %q", gerr.Owner, "app")
+	}
+	if len(app.Includes) != 1 || app.Imports.Len() != 0 {
+		t.Errorf("Go() modified world app: %d includes, %d imports", len(app.Includes), app.Imports.Len())
+	}
+
+	err = res.ResolveIncludes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkgs, err := Go(res, World("app"), PackageRoot(fixtureRoot))
+	if err != nil {
+		t.Fatal(err)
+	}
+	paths := make(map[string]bool)
+	for _, p := range pkgs {
+		paths[p.Path] = true
+	}
+	for _, path := range []string{"example.com/gen/wasi/cli/app", "example.com/gen/wasi/io/streams", "example.com/gen/wasi/cli/environment"} {
+		if !paths[path] {
+			t.Errorf("package %s not generated", path)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	if !canGo() {
+		t.Skip("skipping test: can't run go (TinyGo without fork?)")
+	}
+	res := loadFixture(t, "codegen/simple-enum.wit.json")
+	dir := path.Join(generatedPath, "verify")
+	err := os.MkdirAll(dir, fs.ModePerm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, err := gen.PackagePath(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkgs, err := Go(res, PackageRoot(root))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = Verify(pkgs, dir, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Break the generated code of enum e1.
+	var enums *gen.Package
+	for _, pkg := range pkgs {
+		if strings.HasSuffix(pkg.Path, "/enums") {
+			enums = pkg
+		}
+	}
+	if enums == nil {
+		t.Fatal("package enums not generated")
+	}
+	file := enums.File("broken.go")
+	file.Write([]byte("func (e E1) String() string {\n\treturn undefinedName\n}\n"))
+
+	err = Verify(pkgs, dir, root)
+	var verr *VerifyError
+	if !errors.As(err, &verr) {
+		t.Fatalf("Verify(): %v, expected *VerifyError", err)
+	}
+	want := map[string]bool{
+		"undefined: undefinedName":          false,
+		"method E1.String already declared": false,
+	}
+	for _, d := range verr.Diagnostics {
+		for msg := range want {
+			if !strings.HasPrefix(d.Msg, msg) {
+				continue
+			}
+			want[msg] = true
+			if d.Decl != "E1.String" || d.Origin != `enum "foo:foo/enums#e1"` {
+				t.Errorf("Diagnostic %q: Decl %q, Origin %q, expected E1.String, enum \"foo:foo/enums#e1\"", d.Msg, d.Decl, d.Origin)
+			}
+			if !strings.HasPrefix(d.Pos.Filename, dir) {
+				t.Errorf("Diagnostic %q: Pos %s, expected file in %s", d.Msg, d.Pos, dir)
+			}
+		}
+	}
+	for msg, found := range want {
+		if !found {
+			t.Errorf("Verify(): no diagnostic %q in:\n%v", msg, err)
+		}
+	}
+}
+
+// TestVerifyWorkspace tests that Verify runs the go command with the Go environment of
+// its directory, which is outside the go.work workspace set by GOWORK.
+func TestVerifyWorkspace(t *testing.T) {
+	if !canGo() {
+		t.Skip("skipping test: can't run go (TinyGo without fork?)")
+	}
+	work := t.TempDir()
+	for name, content := range map[string]string{
+		"go.work":      "go 1.22.0\n\nuse ./other\n",
+		"other/go.mod": "module example.com/other\n\ngo 1.22.0\n",
+	} {
+		path := filepath.Join(work, name)
+		err := os.MkdirAll(filepath.Dir(path), fs.ModePerm)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(path, []byte(content), 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("GOWORK", filepath.Join(work, "go.work"))
+
+	res := loadFixture(t, "codegen/simple-enum.wit.json")
+	dir := path.Join(generatedPath, "verify-workspace")
+	err := os.MkdirAll(dir, fs.ModePerm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, err := gen.PackagePath(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkgs, err := Go(res, PackageRoot(root))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = Verify(pkgs, dir, root)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	if !canGo() {
+		t.Skip("skipping test: can't run go (TinyGo without fork?)")
+	}
+	res := loadFixture(t, "codegen/simple-enum.wit.json")
+	// Existing bindings are only parsed, so their module need not require package cm.
+	dir := t.TempDir()
+	const root = "example.com/compare"
+	err := os.WriteFile(path.Join(dir, "go.mod"), []byte("module "+root+"\n"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkgs, err := Go(res, PackageRoot(root))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Write the generated Go files, and compare them with themselves.
+	w := &gen.Writer{PackageRoot: root}
+	var enums *gen.Package
+	for _, pkg := range pkgs {
+		if strings.HasSuffix(pkg.Path, "/enums") {
+			enums = pkg
+		}
+		for _, file := range pkg.Files {
+			if !file.IsGo() || !file.HasContent() {
+				continue
+			}
+			rel, err := w.Rel(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := file.Bytes()
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = os.MkdirAll(path.Join(dir, path.Dir(rel)), fs.ModePerm)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = os.WriteFile(path.Join(dir, rel), b, 0o644)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if enums == nil {
+		t.Fatal("package enums not generated")
+	}
+	drift, err := Compare(pkgs, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(drift) != 0 {
+		t.Errorf("Compare(): %v, expected no drift", drift)
+	}
+
+	// Evolve the generated API, and add declarations to the existing bindings.
+	enums.File("api.go").WriteString("func Added() {}\n\nfunc Changed(e E1) string { return \"\" }\n")
+	rel, err := w.Rel(enums.File("api.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(path.Join(dir, rel), []byte("package enums\n\n"+
+		"func Changed(e E1) int { return 0 }\n\n"+
+		"// Removed represents the imported function \"removed\".\nfunc Removed() {}\n\n"+
+		"// Helper is written by hand.\nfunc Helper() {}\n"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	drift, err = Compare(pkgs, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		enums.Path + ": missing Added",
+		enums.Path + ": changed Changed: func(E1) int, expected func(E1) string",
+		enums.Path + ": stale Removed: func()",
+	}
+	var got []string
+	for _, d := range drift {
+		got = append(got, d.String())
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Compare():\n%s\nexpected:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestParsePosition(t *testing.T) {
+	tests := []struct {
+		s    string
+		want token.Position
+	}{
+		{"", token.Position{}},
+		{"-", token.Position{}},
+		{"a/b.go", token.Position{Filename: "a/b.go"}},
+		{"a/b.go:12", token.Position{Filename: "a/b.go", Line: 12}},
+		{"a/b.go:12:3", token.Position{Filename: "a/b.go", Line: 12, Column: 3}},
+		{"C:/a/b.go:12:3", token.Position{Filename: "C:/a/b.go", Line: 12, Column: 3}},
+	}
+	for _, tt := range tests {
+		if got := parsePosition(tt.s); got != tt.want {
+			t.Errorf("parsePosition(%q): %#v, expected %#v", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestGenerateError(t *testing.T) {
+	res := loadFixture(t, "wasi/cli.wit.json")
+	_, err := Go(res, Rename("wasi:io/streams@0.2.0#input-strem", "Reader"))
+	var e *GenerateError
+	if !errors.As(err, &e) {
+		t.Fatalf("Go: %v, expected *GenerateError", err)
+	}
+	want := "wasi:io/streams@0.2.0#input-strem: cannot rename to Reader: WIT type or function not found"
+	if got := e.Error(); got != want {
+		t.Errorf("Error(): %q, expected %q", got, want)
+	}
+	if got, want := e.Suggestion, "did you mean input-stream?"; got != want {
+		t.Errorf("Suggestion: %q, expected %q", got, want)
+	}
+
+	_, err = Go(res, Rename("wasi:io/nonexistent#input-stream", "Reader"))
+	if !errors.As(err, &e) {
+		t.Fatalf("Go: %v, expected *GenerateError", err)
+	}
+	if got, want := e.Ident(), "wasi:io/nonexistent#input-stream"; got != want {
+		t.Errorf("Ident(): %q, expected %q", got, want)
+	}
+}
+
+func TestSuggestName(t *testing.T) {
+	names := []string{"input-stream", "output-stream", "stream-error", "pollable"}
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"input-strem", "input-stream"},
+		{"Output-Stream", "output-stream"},
+		{"stream-errors", "stream-error"},
+		{"poll", ""},
+		{"nonexistent", ""},
+	}
+	for _, tt := range tests {
+		if got := suggestName(tt.name, names); got != tt.want {
+			t.Errorf("suggestName(%q): %q, expected %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestGenerateTypeOverrides(t *testing.T) {
+	res := loadFixture(t, "wasi/cli.wit.json")
+
+	// Export wasi:clocks/wall-clock from a new world
+	clocks := res.FindInterface("wasi:clocks/wall-clock@0.2.0")
+	if clocks == nil {
+		t.Fatal("interface wasi:clocks/wall-clock not found")
+	}
+	w := &wit.World{Name: "clock", Package: clocks.Package}
+	w.Exports.Set("wasi:clocks/wall-clock@0.2.0", &wit.InterfaceRef{Interface: clocks})
+	clocks.Package.Worlds.Set(w.Name, w)
+	res.Worlds = append(res.Worlds, w)
+
+	const clock = "github.com/bytecodealliance/wasm-tools-go/testdata/overrides/clock"
+	override := OverrideType("wasi:clocks/wall-clock#datetime", TypeOverride{
+		Type:  "time.Time",
+		Lift:  clock + ".FromDatetime",
+		Lower: clock + ".ToDatetime",
+	})
+	type decl struct {
+		name  string
+		typ   string
+		calls []string
+	}
+	for _, tt := range []struct {
+		world string
+		want  map[string][]decl
+	}{
+		{
+			"wasi:cli/command",
+			map[string][]decl{
+				"wasi/clocks/wall-clock/wall-clock.wit.go": {
+					{"now", "func() (result DateTime)", []string{"wasmimport_Now"}},
+					{"Now", "func() (result time.Time)", []string{"now", "clock.FromDatetime"}},
+				},
+			},
+		},
+		{
+			"wasi:clocks/clock",
+			map[string][]decl{
+				"wasi/clocks/wall-clock/wall-clock.wit.go": {
+					{"exportsNow", "func() (result DateTime)", []string{"Exports.Now", "clock.ToDatetime"}},
+				},
+			},
+		},
+	} {
+		t.Run(tt.world, func(t *testing.T) {
+			files := generateSources(t, res, World(tt.world), override)
+			for path, decls := range tt.want {
+				f := parseFile(t, files, path)
+				if _, ok := mustType(t, f, "DateTime").Type.(*ast.StructType); !ok {
+					t.Errorf("%s: DateTime is not a struct type", path)
+				}
+				for _, d := range decls {
+					fn := mustFunc(t, f, d.name)
+					if got := types.ExprString(fn.Type); got != d.typ {
+						t.Errorf("%s: %s: %s, expected %s", path, d.name, got, d.typ)
+					}
+					if got := calls(fn); !slices.Equal(got, d.calls) {
+						t.Errorf("%s: %s calls %v, expected %v", path, d.name, got, d.calls)
+					}
+				}
+			}
+			switch tt.world {
+			case "wasi:cli/command":
+				f := parseFile(t, files, "wasi/filesystem/types/types.wit.go")
+				if got, want := fieldType(t, mustType(t, f, "DescriptorStat").Type, "DataAccessTimestamp"), "cm.Option[DateTime]"; got != want {
+					t.Errorf("DescriptorStat.DataAccessTimestamp: %s, expected %s", got, want)
+				}
+			case "wasi:clocks/clock":
+				f := parseFile(t, files, "wasi/clocks/wall-clock/wall-clock.exports.go")
+				if got, want := fieldType(t, mustType(t, f, "ExportsInstance").Type, "Now"), "func() (result time.Time)"; got != want {
+					t.Errorf("Exports.Now: %s, expected %s", got, want)
+				}
+			}
+			validateGeneratedGo(t, res, "type-overrides", World(tt.world), override)
+		})
+	}
+
+	for _, tt := range []struct {
+		ident    string
+		override TypeOverride
+	}{
+		{"wasi:clocks/wall-clock#nonexistent", TypeOverride{"time.Time", clock + ".FromDatetime", clock + ".ToDatetime"}},
+		{"wasi:io/streams#input-stream", TypeOverride{"time.Time", clock + ".FromDatetime", clock + ".ToDatetime"}},
+		{"wasi:clocks/wall-clock#datetime", TypeOverride{"Time", clock + ".FromDatetime", clock + ".ToDatetime"}},
+		{"wasi:clocks/wall-clock#datetime", TypeOverride{"time.Time", clock + ".fromDatetime", clock + ".ToDatetime"}},
+		{"wasi:clocks/wall-clock", TypeOverride{"time.Time", clock + ".FromDatetime", clock + ".ToDatetime"}},
+	} {
+		_, err := Go(res, OverrideType(tt.ident, tt.override))
+		if err == nil {
+			t.Errorf("OverrideType(%q, %v): expected error", tt.ident, tt.override)
+		}
+	}
+}
+
+func TestGenerateResourceTables(t *testing.T) {
+	// The behavior of resource tables is tested by TestGenerateTestdataBehavior.
+	res := loadFixture(t, "codegen/resources.wit.json")
+	const path = "my/resources/resources/exports/exports.wit.go"
+	for _, tables := range []bool{false, true} {
+		f := parseFile(t, generateSources(t, res, ResourceTables(tables)), path)
+		for _, name := range []string{"XImpl", "XTable", "NewX"} {
+			if got := hasDecl(f, name); got != tables {
+				t.Errorf("ResourceTables(%t): %s declared: %t", tables, name, got)
+			}
+		}
+		for lhs, want := range map[string]string{
+			"Exports.X.Destructor": "XTable.Remove",
+			"Exports.X.GetA":       "XTable.Must(self).GetA",
+			"Exports.X.SetA":       "XTable.Must(self).SetA",
+		} {
+			fn, ok := initAssign(f, lhs).(*ast.FuncLit)
+			if got := ok && slices.Contains(calls(fn), want); got != tables {
+				t.Errorf("ResourceTables(%t): %s calls %s: %t", tables, lhs, want, got)
+			}
+		}
+		if !tables {
+			fn, ok := initAssign(f, "Exports.X.Destructor").(*ast.FuncLit)
+			if !ok || len(fn.Body.List) != 0 {
+				t.Errorf("ResourceTables(%t): Exports.X.Destructor is not an empty function", tables)
+			}
+		}
+	}
+	validateGeneratedGo(t, res, "resource-tables", ResourceTables(true))
+}
+
+func TestGenerateStabilityDocs(t *testing.T) {
+	files := generateFixture(t, "codegen/stability.wit.json")
+	tests := []struct {
+		path string
+		name string // or "package" for the package docs
+		want string
+	}{
+		{"gates/all/w/w.wit.go", "package", "World docs.\n\nUnstable: requires feature \"fancy\".\n"},
+		{"gates/all/types/types.wit.go", "package", "Interface docs.\n\nSince version 1.0.0.\n"},
+		{"gates/all/types/types.wit.go", "T", "Type docs.\n\nSince version 1.0.0.\n\nDeprecated: as of version 1.0.1.\n\n\ttype t = u32\n"},
+		{"gates/all/types/types.wit.go", "R", "Unstable: requires feature \"fancy\".\n\n\trecord r {"},
+		{"gates/all/types/types.wit.go", "NewRes", "Constructor docs.\n\nSince version 1.0.0.\n\n\tconstructor()\n"},
+		{"gates/all/types/types.wit.go", "Get", "Function docs.\n\nUnstable: requires feature \"fancy\".\n\nDeprecated: as of version 1.0.1.\n"},
+	}
+	for _, tt := range tests {
+		f := parseFile(t, files, tt.path)
+		doc := f.Doc
+		if tt.name != "package" {
+			_, doc = findDecl(f, tt.name)
+		}
+		if got := doc.Text(); !strings.Contains(got, tt.want) {
+			t.Errorf("%s: %s docs:\n%s\nexpected:\n%s", tt.path, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestGenerateScaffold(t *testing.T) {
+	res := loadFixture(t, "wasi/cli.wit.json")
+	const path = "wasi/cli/command/cmd/command"
+	for _, scaffold := range []bool{false, true} {
+		opts := []Option{PackageRoot(fixtureRoot), World("wasi:cli/command"), Scaffold(scaffold)}
+		pkgs, err := Go(res, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files := packageSources(t, pkgs)
+		if _, ok := files[path+"/main.go"]; ok != scaffold {
+			t.Fatalf("Scaffold(%t): file %s/main.go generated: %t", scaffold, path, ok)
+		}
+		if !scaffold {
+			continue
+		}
+		if f := findPackage(t, pkgs, path).Files["main.go"]; !f.Scaffold || f.GeneratedBy != "" {
+			t.Errorf("Scaffold(%t): %s/main.go: Scaffold = %t, GeneratedBy = %q, expected true, \"\"", scaffold, path, f.Scaffold, f.GeneratedBy)
+		}
+		f := parseFile(t, files, path+"/main.go")
+		if f.Name.Name != "main" {
+			t.Errorf("Scaffold(%t): package %s, expected main", scaffold, f.Name.Name)
+		}
+		for name, want := range map[string]string{
+			"Stdin":  "&inputStream{get: stdin.GetStdin}",
+			"Stdout": "&outputStream{get: stdout.GetStdout}",
+			"Stderr": "&outputStream{get: stderr.GetStderr}",
+		} {
+			spec, _ := findDecl(f, name)
+			vs, ok := spec.(*ast.ValueSpec)
+			if !ok || len(vs.Values) != 1 {
+				t.Errorf("Scaffold(%t): var %s not declared", scaffold, name)
+				continue
+			}
+			if got := exprSource(t, vs.Values[0]); got != want {
+				t.Errorf("Scaffold(%t): var %s = %s, expected %s", scaffold, name, got, want)
+			}
+		}
+		for name, want := range map[string]string{
+			"Run":                "",
+			"main":               "",
+			"Args":               "environment.GetArguments",
+			"outputStream.Write": "w.stream.BlockingWriteAndFlush",
+			"inputStream.Read":   "r.stream.BlockingRead",
+		} {
+			fn := mustFunc(t, f, name)
+			if want != "" && !slices.Contains(calls(fn), want) {
+				t.Errorf("Scaffold(%t): %s does not call %s", scaffold, name, want)
+			}
+		}
+		fn, ok := initAssign(f, "run.Exports.Run").(*ast.FuncLit)
+		if !ok {
+			t.Fatalf("Scaffold(%t): run.Exports.Run not assigned in init", scaffold)
+		}
+		if got := calls(fn); !slices.Contains(got, "Run") || !slices.Contains(got, "Args") {
+			t.Errorf("Scaffold(%t): run.Exports.Run calls %v, expected Run(Args())", scaffold, got)
+		}
+	}
+	validateGeneratedGo(t, res, "scaffold", World("wasi:cli/command"), Scaffold(true))
+}
+
+func TestGenerateEqualMethods(t *testing.T) {
+	// The behavior of Equal methods is tested by TestGenerateTestdataBehavior.
+	res := loadFixture(t, "wasi/cli.wit.json")
+	files := generateSources(t, res, EqualMethods(true))
+	for path, names := range map[string][]string{
+		"wasi/clocks/wall-clock/wall-clock.wit.go": {"DateTime"},
+		"wasi/filesystem/types/types.wit.go":       {"DescriptorType", "DescriptorFlags", "DirectoryEntry", "NewTimestamp"},
+	} {
+		f := parseFile(t, files, path)
+		for _, name := range names {
+			want := "func(other " + name + ") bool"
+			if got := types.ExprString(mustFunc(t, f, name+".Equal").Type); got != want {
+				t.Errorf("%s: %s.Equal: %s, expected %s", path, name, got, want)
+			}
+		}
+	}
+
+	// Types with resource handles have no Equal method.
+	f := parseFile(t, files, "wasi/io/streams/streams.wit.go")
+	for _, decl := range f.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv != nil && fn.Name.Name == "Equal" {
+			t.Errorf("streams.wit.go: %s.Equal declared", receiverName(fn.Recv.List[0].Type))
+		}
+	}
+
+	validateGeneratedGo(t, res, "equal-methods", EqualMethods(true))
+	for _, name := range []string{"lists", "option-result", "records", "variants", "small-anonymous"} {
+		validateGeneratedGo(t, loadFixture(t, "codegen/"+name+".wit.json"), "equal-methods/"+name, EqualMethods(true))
+	}
+}
+
+func TestGenerateDeepCopy(t *testing.T) {
+	// The behavior of DeepCopy methods is tested by TestGenerateTestdataBehavior.
+	res := loadFixture(t, "wasi/cli.wit.json")
+	files := generateSources(t, res, DeepCopy(true))
+	f := parseFile(t, files, "wasi/filesystem/types/types.wit.go")
+	fn := mustFunc(t, f, "DirectoryEntry.DeepCopy")
+	if got, want := types.ExprString(fn.Type), "func() DirectoryEntry"; got != want {
+		t.Errorf("DirectoryEntry.DeepCopy: %s, expected %s", got, want)
+	}
+	if got := calls(fn); !slices.Contains(got, "strings.Clone") {
+		t.Errorf("DirectoryEntry.DeepCopy calls %v, expected strings.Clone", got)
+	}
+
+	// Types without strings or lists have no DeepCopy method.
+	f = parseFile(t, files, "wasi/clocks/wall-clock/wall-clock.wit.go")
+	if hasDecl(f, "DateTime.DeepCopy") {
+		t.Error("DateTime.DeepCopy declared")
+	}
+
+	validateGeneratedGo(t, res, "deep-copy", DeepCopy(true))
+	for _, name := range []string{"lists", "option-result", "records", "variants", "resources-with-lists", "resources-in-aggregates"} {
+		validateGeneratedGo(t, loadFixture(t, "codegen/"+name+".wit.json"), "deep-copy/"+name, DeepCopy(true))
+	}
+}
+
+func TestGenerateMethodNameCollisions(t *testing.T) {
+	res := loadFixture(t, "codegen/method-names.wit.json")
+	opts := []Option{Invoker(true), BinaryMarshal(true), EqualMethods(true), DeepCopy(true), TypeInfo(true)}
+	files := generateSources(t, res, opts...)
+	strs := make(map[string]bool)
+	for path := range files {
+		if !strings.HasSuffix(path, ".go") {
+			continue
+		}
+		f := parseFile(t, files, path)
+		// Records with a field that collides with a method, and types that contain them, have no such method.
+		for _, name := range []string{"Choice.ToValue", "Wrapper.Equal", "Wrapper.DeepCopy"} {
+			if hasDecl(f, name) {
+				t.Errorf("%s: %s declared", path, name)
+			}
+		}
+		ast.Inspect(f, func(n ast.Node) bool {
+			if lit, ok := n.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+				s, _ := strconv.Unquote(lit.Value)
+				strs[s] = true
+			}
+			return true
+		})
+	}
+	if strs["foo:foo/method-names#take-methods"] {
+		t.Error("invoker contains take-methods")
+	}
+	if !strs["foo:foo/method-names#take-string"] {
+		t.Error("invoker does not contain take-string")
+	}
+	validateGeneratedGo(t, res, "method-names", opts...)
+}
@@ -14,6 +14,7 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/bytecodealliance/wasm-tools-go/cm"
 	"github.com/bytecodealliance/wasm-tools-go/internal/codec"
@@ -50,6 +51,10 @@ type funcDecl struct {
 	goFunc     function // The Go function
 	wasmFunc   function // The wasmimport or wasmexport function
 	linkerName string   // The wasmimport or wasmexport mangled linker name
+
+	// overrideName is the name of the imported Go function with the types specified with
+	// [OverrideType] that calls goFunc, if any.
+	overrideName string
 }
 
 // function represents a Go function created from a Component Model function
@@ -66,6 +71,16 @@ func (f *function) isMethod() bool {
 	return f.receiver.typ != nil
 }
 
+// funcDeclName returns the name of function f as recorded with [gen.File.BeginDecl],
+// e.g. "Descriptor.Read" for a method.
+func (g *generator) funcDeclName(f *function) string {
+	if !f.isMethod() {
+		return f.name
+	}
+	recv := g.typeRep(f.file, f.receiver.dir, f.receiver.typ)
+	return strings.TrimPrefix(recv, "*") + "." + f.name
+}
+
 // param represents a Go function parameter or result.
 // name is a unique Go name within the function scope.
 type param struct {
@@ -80,13 +95,19 @@ type typeUse struct {
 	typ *wit.TypeDef
 }
 
+// tupleUse is a Go generic tuple type with n fields declared in a Go package.
+type tupleUse struct {
+	pkg *gen.Package
+	n   int
+}
+
 type generator struct {
 	opts options
 	res  *wit.Resolve
 
-	// versioned is set to true if there are multiple versions of a WIT package in res,
-	// which affects the generated Go package paths.
-	versioned bool
+	// versioned is the set of WIT package names, without versions, that have more than one
+	// version in res. Go package paths for these packages include a version segment.
+	versioned map[string]bool
 
 	// packages are Go packages indexed on Go package paths.
 	packages map[string]*gen.Package
@@ -118,6 +139,45 @@ type generator struct {
 	// lowering and lifting functions for defined types.
 	lowerFunctions map[typeUse]function
 	liftFunctions  map[typeUse]function
+
+	// directives are the directives in the doc comments of WIT types and functions.
+	directives map[wit.Node]directives
+
+	// renamedTypes are the type declarations renamed with the Rename option.
+	renamedTypes []renamedType
+
+	// tuples are the generic tuple types with more than cm.MaxTuple fields,
+	// declared on demand in the abi.go file of a Go package.
+	tuples map[tupleUse]string
+
+	// mocks are the imported functions with mock implementations, indexed by Go package.
+	mocks map[*gen.Package][]*funcDecl
+
+	// layouts are the type declarations with layout tests, indexed by Go package.
+	layouts map[*gen.Package][]declaredType
+
+	// typeInfos are the type declarations with type metadata, indexed by Go package.
+	typeInfos map[*gen.Package][]declaredType
+
+	// reachable are the types reachable from the selected world(s), if pruning is enabled.
+	reachable map[*wit.TypeDef]bool
+
+	// progress, if non-nil, is called after each world or interface is generated.
+	progress func(Progress)
+
+	// units are the worlds and interfaces to be rendered, in declaration order.
+	units []*unit
+
+	// mu guards types, functions, defined, shapes, lowerFunctions, liftFunctions,
+	// tuples, renamedTypes, mocks, layouts, and typeInfos while units are rendered
+	// in parallel. The other fields are not modified while rendering.
+	//
+	// Lookups and inserts of types, shapes, and tuples are done with mu held across the
+	// declaration of the Go name. The keys of lowerFunctions and liftFunctions include
+	// the Go package they are declared in, and each Go package is rendered by a single
+	// goroutine (see renderUnits), so a key is only inserted by that goroutine.
+	// Their Go functions are declared without mu held, as declaring them locks mu.
+	mu sync.Mutex
 }
 
 func newGenerator(res *wit.Resolve, opts ...Option) (*generator, error) {
@@ -129,6 +189,10 @@ func newGenerator(res *wit.Resolve, opts ...Option) (*generator, error) {
 		shapes:         make(map[typeUse]string),
 		lowerFunctions: make(map[typeUse]function),
 		liftFunctions:  make(map[typeUse]function),
+		tuples:         make(map[tupleUse]string),
+		mocks:          make(map[*gen.Package][]*funcDecl),
+		layouts:        make(map[*gen.Package][]declaredType),
+		typeInfos:      make(map[*gen.Package][]declaredType),
 	}
 	for i := 0; i < 2; i++ {
 		g.types[i] = make(map[*wit.TypeDef]*typeDecl)
@@ -146,48 +210,143 @@ func newGenerator(res *wit.Resolve, opts ...Option) (*generator, error) {
 	if g.opts.cmPackage == "" {
 		g.opts.cmPackage = cmPackage
 	}
+	if g.opts.naming == 0 {
+		g.opts.naming = DefaultNaming
+	}
 	g.res = res
 	return g, nil
 }
 
 func (g *generator) generate() ([]*gen.Package, error) {
+	err := g.checkIncludes()
+	if err != nil {
+		return nil, err
+	}
+	err = g.res.Validate()
+	if err != nil {
+		return nil, err
+	}
+	err = g.checkRenames()
+	if err != nil {
+		return nil, err
+	}
+	err = g.checkOverrides()
+	if err != nil {
+		return nil, err
+	}
+	err = g.checkDirectives()
+	if err != nil {
+		return nil, err
+	}
 	g.detectVersionedPackages()
-	err := g.defineWorlds()
+	if g.opts.prune {
+		g.detectReachableTypes()
+	}
+	err = g.defineWorlds()
 	if err != nil {
 		return nil, err
 	}
+	g.defineRenameAliases()
+	if g.opts.invoker {
+		g.defineInvokers()
+	}
+	if g.opts.mockImports {
+		g.defineMocks()
+	}
+	if g.opts.layoutTests {
+		g.defineLayoutTests()
+	}
+	if g.opts.typeInfo {
+		g.defineTypeInfos()
+	}
+	if g.opts.emitIR {
+		err = g.emitIR()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if g.opts.emptyAsmMode == EmptyAsmAlways {
+		for _, pkg := range g.packages {
+			if !pkg.HasContent() {
+				continue
+			}
+			err = g.ensureEmptyAsm(pkg)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	g.applyBuildTags()
+	g.defineCMAPIChecks()
+	g.defineOrigins()
+	if g.opts.scaffold {
+		// Scaffolds are defined last, as they are not generated bindings.
+		err = g.defineScaffolds()
+		if err != nil {
+			return nil, err
+		}
+	}
 	var packages []*gen.Package
 	for _, path := range codec.SortedKeys(g.packages) {
 		packages = append(packages, g.packages[path])
 	}
+
+	// Once all files are complete, format them in parallel.
+	gen.Format(packages)
+
 	return packages, nil
 }
 
-func (g *generator) detectVersionedPackages() {
-	if g.opts.versioned {
-		g.versioned = true
-		// fmt.Fprintf(os.Stderr, "Generated versions for all package(s)\n")
-		return
+// checkIncludes returns an error if a world has unresolved include statements.
+// The generator does not resolve includes itself, as it would modify the caller's [wit.Resolve].
+func (g *generator) checkIncludes() error {
+	for _, w := range g.res.Worlds {
+		if len(w.Includes) > 0 {
+			err := newGenerateError(w, "", w.Pos, "world has unresolved include statements")
+			err.Suggestion = "call (*wit.Resolve).ResolveIncludes before generating Go code"
+			return err
+		}
 	}
+	return nil
+}
+
+// detectVersionedPackages records the WIT packages with more than one version in g.res.
+// See [Versioned] for the policy that maps WIT package versions to Go package paths.
+func (g *generator) detectVersionedPackages() {
+	g.versioned = make(map[string]bool)
 	packages := make(map[string]string)
 	for _, pkg := range g.res.Packages {
-		id := pkg.Name
-		id.Version = nil
-		path := id.String()
-		if packages[path] != "" && packages[path] != pkg.Name.String() {
-			g.versioned = true
+		path := unversionedName(pkg.Name)
+		if prev, ok := packages[path]; ok && prev != pkg.Name.String() {
+			g.versioned[path] = true
 		} else {
 			packages[path] = pkg.Name.String()
 		}
 	}
-	// if g.versioned {
-	// 	fmt.Fprintf(os.Stderr, "Multiple versions of package(s) detected\n")
-	// }
+}
+
+// versionSegment returns the version segment of the Go package path for WIT package id,
+// or an empty string if the path has no version segment.
+func (g *generator) versionSegment(id wit.Ident) string {
+	if id.Version == nil || (!g.opts.versioned && !g.versioned[unversionedName(id)]) {
+		return ""
+	}
+	return PathSegment("v" + id.Version.String())
+}
+
+// unversionedName returns the WIT package name of id without a version or extension,
+// e.g. "wasi:io".
+func unversionedName(id wit.Ident) string {
+	id.Version = nil
+	id.Extension = ""
+	return id.String()
 }
 
 // define marks a world, interface, type, or function as defined.
 // It returns true if was newly defined.
 func (g *generator) define(dir wit.Direction, v wit.Node) (defined bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
 	if g.defined[dir][v] {
 		return false
 	}
@@ -198,17 +357,112 @@ func (g *generator) define(dir wit.Direction, v wit.Node) (defined bool) {
 // By default, each WIT interface and world maps to a single Go package.
 // Options might override the Go package, including combining multiple
 // WIT interfaces and/or worlds into a single Go package.
+//
+// Worlds and interfaces are declared sequentially, as Go names depend on the order
+// in which they are declared. Each world or interface is then rendered into its
+// Go package by renderUnits.
 func (g *generator) defineWorlds() error {
 	// fmt.Fprintf(os.Stderr, "Generating Go for %d world(s)\n", len(g.res.Worlds))
+	for _, w := range g.worlds() {
+		err := g.defineWorld(w)
+		if err != nil {
+			return err
+		}
+	}
+	return g.renderUnits()
+}
+
+// unit is a world or interface declared by defineWorld or defineInterface,
+// to be rendered into Go package pkg by renderUnits. Channel done is closed
+// once all units in pkg are rendered.
+type unit struct {
+	w      *wit.World
+	owner  wit.TypeOwner
+	dir    wit.Direction
+	pkg    *gen.Package
+	render func() error
+	err    error
+	done   chan struct{}
+}
+
+// addUnit adds a unit to be rendered by renderUnits.
+func (g *generator) addUnit(w *wit.World, owner wit.TypeOwner, dir wit.Direction, pkg *gen.Package, render func() error) {
+	g.units = append(g.units, &unit{
+		w:      w,
+		owner:  owner,
+		dir:    dir,
+		pkg:    pkg,
+		render: render,
+		done:   make(chan struct{}),
+	})
+}
+
+// renderUnits renders the declared units, rendering each Go package in its own goroutine.
+// Units that share a Go package are rendered in the order they were declared, and each
+// package is rendered only from declarations and its own files, so the generated source
+// is the same as if all units were rendered sequentially. Progress is reported, and the
+// first error is returned, in declaration order. Progress for a unit is reported once
+// all units in its Go package are rendered.
+func (g *generator) renderUnits() error {
+	var groups [][]*unit
+	index := make(map[*gen.Package]int)
+	for _, u := range g.units {
+		i, ok := index[u.pkg]
+		if !ok {
+			i = len(groups)
+			index[u.pkg] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], u)
+	}
+
+	sem := make(chan struct{}, runtime.GOMAXPROCS(0))
+	var wg sync.WaitGroup
+	for _, units := range groups {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			var err error
+			for _, u := range units {
+				// Units after an error in the same package are not rendered.
+				if err == nil {
+					err = u.render()
+					u.err = err
+				}
+			}
+			for _, u := range units {
+				close(u.done)
+			}
+		}()
+	}
+
+	var err error
+	for _, u := range g.units {
+		<-u.done
+		if err == nil {
+			err = u.err
+		}
+		if err == nil {
+			g.reportProgress(u.w, u.owner, u.dir)
+		}
+	}
+	wg.Wait()
+	g.units = nil
+	return err
+}
+
+// worlds returns the world(s) selected for generation, which match the world option,
+// or the last world in the [wit.Resolve] if none was specified.
+func (g *generator) worlds() []*wit.World {
+	var worlds []*wit.World
 	for i, w := range g.res.Worlds {
 		if w.Match(g.opts.world) || (g.opts.world == "" && i == len(g.res.Worlds)-1) {
-			err := g.defineWorld(w)
-			if err != nil {
-				return err
-			}
+			worlds = append(worlds, w)
 		}
 	}
-	return nil
+	return worlds
 }
 
 func (g *generator) defineWorld(w *wit.World) error {
@@ -225,6 +479,44 @@ func (g *generator) defineWorld(w *wit.World) error {
 		return err
 	}
 
+	// Declare the interfaces imported and exported by w
+	w.Imports.All()(func(name string, v wit.WorldItem) bool {
+		if v, ok := v.(*wit.InterfaceRef); ok {
+			// TODO: handle Stability
+			err = g.defineInterface(w, wit.Imported, v.Interface, name)
+		}
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+
+	w.Exports.All()(func(name string, v wit.WorldItem) bool {
+		switch v := v.(type) {
+		case *wit.InterfaceRef:
+			// TODO: handle Stability
+			err = g.defineInterface(w, wit.Exported, v.Interface, name)
+		case *wit.TypeDef:
+			// WIT does not currently allow worlds to export types.
+			e := newGenerateError(w, name, v.Pos, "exported type in world "+w.Name)
+			e.Suggestion = "import type " + name + ", or export an interface that defines it"
+			err = e
+		}
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+
+	g.addUnit(w, w, wit.Exported, pkg, func() error {
+		return g.renderWorld(w, pkg)
+	})
+	return nil
+}
+
+// renderWorld renders the Go package docs, WIT file, and the types and functions
+// imported and exported by world w into Go package pkg.
+func (g *generator) renderWorld(w *wit.World, pkg *gen.Package) error {
 	// Write WIT file for this world
 	witFile := g.witFileFor(w)
 	witFile.WriteString(g.res.WIT(w, ""))
@@ -233,17 +525,21 @@ func (g *generator) defineWorld(w *wit.World) error {
 	file := g.fileFor(w)
 	var b strings.Builder
 	stringio.Write(&b, "Package ", pkg.Name, " represents the ", w.WITKind(), " \"", g.moduleNames[w], "\".\n")
-	if w.Docs.Contents != "" {
+	if docContents(w.Docs) != "" {
 		b.WriteString("\n")
-		b.WriteString(w.Docs.Contents)
+		b.WriteString(docContents(w.Docs))
+	}
+	if s := stabilityDocs(w.Stability); s != "" {
+		if !strings.HasSuffix(b.String(), "\n") {
+			b.WriteString("\n")
+		}
+		stringio.Write(&b, "\n", s)
 	}
 	file.PackageDocs = b.String()
 
+	var err error
 	w.Imports.All()(func(name string, v wit.WorldItem) bool {
 		switch v := v.(type) {
-		case *wit.InterfaceRef:
-			// TODO: handle Stability
-			err = g.defineInterface(w, wit.Imported, v.Interface, name)
 		case *wit.TypeDef:
 			err = g.defineTypeDef(wit.Imported, v, name)
 		case *wit.Function:
@@ -258,24 +554,28 @@ func (g *generator) defineWorld(w *wit.World) error {
 	}
 
 	w.Exports.All()(func(name string, v wit.WorldItem) bool {
-		switch v := v.(type) {
-		case *wit.InterfaceRef:
-			// TODO: handle Stability
-			err = g.defineInterface(w, wit.Exported, v.Interface, name)
-		case *wit.TypeDef:
-			// WIT does not currently allow worlds to export types.
-			err = errors.New("exported type in world " + w.Name)
-		case *wit.Function:
-			if v.IsFreestanding() {
-				err = g.defineFunction(w, wit.Exported, v)
-			}
+		if v, ok := v.(*wit.Function); ok && v.IsFreestanding() {
+			err = g.defineFunction(w, wit.Exported, v)
 		}
 		return err == nil
 	})
-
 	return err
 }
 
+// reportProgress calls the progress callback, if any, for owner in world w.
+func (g *generator) reportProgress(w *wit.World, owner wit.TypeOwner, dir wit.Direction) {
+	if g.progress == nil {
+		return
+	}
+	g.progress(Progress{
+		World:     w,
+		Owner:     owner,
+		Name:      g.moduleNames[owner],
+		Direction: dir,
+		Package:   g.packageFor(owner),
+	})
+}
+
 func (g *generator) defineInterface(w *wit.World, dir wit.Direction, i *wit.Interface, name string) error {
 	if !g.define(dir, i) {
 		return nil
@@ -294,37 +594,53 @@ func (g *generator) defineInterface(w *wit.World, dir wit.Direction, i *wit.Inte
 	if err != nil {
 		return err
 	}
+
+	// Declare types
+	i.TypeDefs.All()(func(name string, td *wit.TypeDef) bool {
+		g.declareTypeDef(nil, dir, td, "")
+		return true
+	})
+
+	g.addUnit(w, i, dir, pkg, func() error {
+		g.renderInterface(dir, i, pkg)
+		return nil
+	})
+	return nil
+}
+
+// renderInterface renders the Go package docs, types, and functions
+// of interface i in [wit.Direction] dir into Go package pkg.
+func (g *generator) renderInterface(dir wit.Direction, i *wit.Interface, pkg *gen.Package) {
 	file := g.fileFor(i)
 
 	{
 		var b strings.Builder
 		stringio.Write(&b, "Package ", pkg.Name, " represents the ", dir.String(), " ", i.WITKind(), " \"", g.moduleNames[i], "\".\n")
-		if i.Docs.Contents != "" {
+		if docContents(i.Docs) != "" {
 			b.WriteString("\n")
-			b.WriteString(i.Docs.Contents)
+			b.WriteString(docContents(i.Docs))
+		}
+		if s := stabilityDocs(i.Stability); s != "" {
+			if !strings.HasSuffix(b.String(), "\n") {
+				b.WriteString("\n")
+			}
+			stringio.Write(&b, "\n", s)
+		}
+		if u := g.docsURL(i, ""); u != "" {
+			if !strings.HasSuffix(b.String(), "\n") {
+				b.WriteString("\n")
+			}
+			stringio.Write(&b, "\nSee ", u, " for the upstream documentation.\n")
 		}
 		file.PackageDocs = b.String()
 	}
 
-	// Declare types
-	i.TypeDefs.All()(func(name string, td *wit.TypeDef) bool {
-		g.declareTypeDef(nil, dir, td, "")
-		return true
-	})
-
 	// Define types
 	i.TypeDefs.All()(func(name string, td *wit.TypeDef) bool {
 		g.defineTypeDef(dir, td, name)
 		return true
 	})
 
-	// TODO: delete this
-	// Declare all functions
-	// i.Functions.All()(func(_ string, f *wit.Function) bool {
-	// 	g.declareFunction(id, dir, f)
-	// 	return true
-	// })
-
 	// Define standalone functions
 	i.Functions.All()(func(_ string, f *wit.Function) bool {
 		if f.IsFreestanding() {
@@ -332,11 +648,12 @@ func (g *generator) defineInterface(w *wit.World, dir wit.Direction, i *wit.Inte
 		}
 		return true
 	})
-
-	return nil
 }
 
 func (g *generator) defineTypeDef(dir wit.Direction, t *wit.TypeDef, name string) error {
+	if g.pruned(t) || dir == wit.Imported && g.skipped(t) {
+		return nil
+	}
 	if !g.define(dir, t) {
 		return nil
 	}
@@ -360,19 +677,54 @@ func (g *generator) defineTypeDef(dir wit.Direction, t *wit.TypeDef, name string
 	parent := t.TypeDef()
 	if parent != t {
 		// Type alias
-		stringio.Write(&b, "// See [", g.typeRep(decl.file, dir, parent), "] for more information.\n")
+		stringio.Write(&b, "// See ", g.docLink(decl.file, dir, parent), " for more information.\n")
+		if s := stabilityDocs(t.Stability); s != "" {
+			b.WriteString("//\n")
+			b.WriteString(formatDocComments(s, false))
+		}
 		stringio.Write(&b, "type ", decl.name, " = ", g.typeRep(decl.file, dir, parent), "\n\n")
 	} else {
-		b.WriteString(formatDocComments(t.Docs.Contents, false))
+		b.WriteString(formatDocComments(docContents(t.Docs), false))
+		if s := stabilityDocs(t.Stability); s != "" {
+			if docContents(t.Docs) != "" {
+				b.WriteString("//\n")
+			}
+			b.WriteString(formatDocComments(s, false))
+		}
 		b.WriteString("//\n")
+		b.WriteString(g.docsURLComment(t.Owner, name))
 		b.WriteString(formatDocComments(t.Kind.WIT(nil, t.TypeName()), true))
 		stringio.Write(&b, "type ", decl.name, " ", g.typeDefRep(decl.file, dir, t, decl.name), "\n\n")
+		if g.opts.generateJSON {
+			b.WriteString(g.jsonMarshalers(decl.file, dir, t, decl.name))
+		}
+		if g.opts.dynamicValues {
+			b.WriteString(g.valueMethods(decl.file, dir, t, decl.name))
+		}
+		if g.opts.binaryMarshal {
+			b.WriteString(g.binaryMethods(decl.file, t, decl.name))
+		}
+		if g.opts.equalMethods {
+			b.WriteString(g.equalMethod(decl.file, dir, t, decl.name))
+		}
+		if g.opts.deepCopy {
+			b.WriteString(g.deepCopyMethod(decl.file, dir, t, decl.name))
+		}
+		if r, ok := t.Kind.(*wit.Record); ok && g.opts.constructors {
+			b.WriteString(g.recordConstructor(decl.file, dir, r, decl.name))
+		}
+		if r, ok := t.Kind.(*wit.Record); ok && g.opts.nullAccessors {
+			b.WriteString(g.recordNullAccessors(decl.file, dir, r, decl.name))
+		}
+		if g.opts.layoutTests {
+			g.addLayout(decl, t)
+		}
+		if g.opts.typeInfo {
+			g.addTypeInfo(decl, t)
+		}
 	}
 
-	_, err = decl.file.Write(b.Bytes())
-	if err != nil {
-		return err
-	}
+	decl.file.WriteDecl(decl.name, b.String())
 
 	// Return now unless the type is a resource.
 	if _, ok := t.Kind.(*wit.Resource); !ok {
@@ -383,12 +735,13 @@ func (g *generator) defineTypeDef(dir wit.Direction, t *wit.TypeDef, name string
 	if dir == wit.Exported {
 		exportsFile := g.exportsFileFor(t.Owner)
 		scope := g.exportScopes[t.Owner]
-		goName := scope.GetName(GoName(*t.Name, true))
+		goName := scope.GetName(g.goName(*t.Name, true))
 		stringio.Write(exportsFile, "\n// ", goName, " represents the caller-defined exports for ", t.WITKind(), " \"", g.moduleNames[t.Owner], "#", name, "\".\n")
 		stringio.Write(exportsFile, goName, " struct {")
 	}
 
 	// Define any associated functions
+	var newFunc, dtorFunc *wit.Function
 	switch dir {
 	case wit.Imported:
 		if f := t.ResourceDrop(); f != nil {
@@ -396,10 +749,17 @@ func (g *generator) defineTypeDef(dir wit.Direction, t *wit.TypeDef, name string
 			if err != nil {
 				return nil
 			}
+			if g.opts.managedResources {
+				err = g.defineManagedResource(decl, g.funcDecl(wit.Imported, f))
+				if err != nil {
+					return err
+				}
+			}
 		}
 
 	case wit.Exported:
 		if f := t.ResourceNew(); f != nil {
+			newFunc = f
 			err := g.defineFunction(t.Owner, importedWithExportedTypes, f)
 			if err != nil {
 				return nil
@@ -421,6 +781,7 @@ func (g *generator) defineTypeDef(dir wit.Direction, t *wit.TypeDef, name string
 		}
 
 		if f := t.Destructor(); f != nil {
+			dtorFunc = f
 			err := g.defineFunction(t.Owner, dir, f)
 			if err != nil {
 				return nil
@@ -458,19 +819,76 @@ func (g *generator) defineTypeDef(dir wit.Direction, t *wit.TypeDef, name string
 		stringio.Write(exportsFile, "\n}\n")
 	}
 
+	if dir == wit.Exported && g.opts.resourceTables {
+		return g.defineResourceTable(decl, t, newFunc, dtorFunc)
+	}
+
 	return nil
 }
 
+// defineManagedResource defines a wrapper type for an imported resource that implements
+// [io.Closer], calling the resource-drop function drop when closed or, optionally,
+// when garbage collected.
+func (g *generator) defineManagedResource(decl *typeDecl, drop *funcDecl) error {
+	file := decl.file
+	name := file.DeclareName("Managed" + decl.name)
+	constructor := file.DeclareName("NewManaged" + decl.name)
+
+	var b bytes.Buffer
+	stringio.Write(&b, "// ", name, " is a managed wrapper for resource [", decl.name, "] that implements [io.Closer].\n")
+	stringio.Write(&b, "// Calling Close drops the underlying resource handle.\n")
+	if g.opts.resourceFinalizers {
+		stringio.Write(&b, "// If Close is not called, the resource handle is dropped when the ", name, " is garbage collected.\n")
+	}
+	stringio.Write(&b, "type ", name, " struct {\n", decl.name, "\n}\n\n")
+
+	stringio.Write(&b, "// ", constructor, " returns a [", name, "] that takes ownership of resource handle.\n")
+	stringio.Write(&b, "func ", constructor, "(handle ", decl.name, ") *", name, " {\n")
+	stringio.Write(&b, "self := &", name, "{handle}\n")
+	if g.opts.resourceFinalizers {
+		stringio.Write(&b, file.Import("runtime"), ".SetFinalizer(self, (*", name, ").Close)\n")
+	}
+	b.WriteString("return self\n}\n\n")
+
+	stringio.Write(&b, "// Close drops the underlying resource handle. It is safe to call Close more than once.\n")
+	stringio.Write(&b, "// Close implements [io.Closer].\n")
+	stringio.Write(&b, "func (self *", name, ") Close() error {\n")
+	stringio.Write(&b, "if self.", decl.name, " == 0 {\nreturn nil\n}\n")
+	if drop.goFunc.isMethod() {
+		stringio.Write(&b, "self.", decl.name, ".", drop.goFunc.name, "()\n")
+	} else {
+		stringio.Write(&b, drop.goFunc.name, "(self.", decl.name, ")\n")
+	}
+	stringio.Write(&b, "self.", decl.name, " = 0\n")
+	if g.opts.resourceFinalizers {
+		stringio.Write(&b, file.Import("runtime"), ".SetFinalizer(self, nil)\n")
+	}
+	b.WriteString("return nil\n}\n\n")
+
+	file.WriteDecl(name, b.String())
+	return nil
+}
+
+// declareTypeDef declares the Go type for [wit.TypeDef] t in direction dir, if not already declared.
+// The lookup, the declaration of its name, and the insert into g.types are done with g.mu held,
+// so t is declared once even if packages that use it are rendered concurrently.
 func (g *generator) declareTypeDef(file *gen.File, dir wit.Direction, t *wit.TypeDef, goName string) (*typeDecl, error) {
+	g.mu.Lock()
 	decl, ok := g.types[dir][t]
 	if ok {
+		g.mu.Unlock()
 		return decl, nil
 	}
+	var defaultName string
 	if goName == "" {
 		if t.Name == nil {
+			g.mu.Unlock()
 			return nil, errors.New("BUG: cannot declare unnamed wit.TypeDef")
 		}
-		goName = GoName(*t.Name, true)
+		goName = g.goName(*t.Name, true)
+		if name, ok := g.renamed(t.Owner, *t.Name, t); ok && name != goName {
+			defaultName, goName = goName, name
+		}
 	}
 	if file == nil {
 		file = g.fileFor(t.Owner)
@@ -491,8 +909,14 @@ func (g *generator) declareTypeDef(file *gen.File, dir wit.Direction, t *wit.Typ
 	otherDir := ^dir & 1
 	if _, ok := g.types[otherDir][t]; !ok && !wit.HasResource(t) {
 		g.types[otherDir][t] = decl
-		g.define(otherDir, t) // Mark this type as defined
+		g.defined[otherDir][t] = true // Mark this type as defined
+	}
+	g.mu.Unlock()
+
+	if defaultName != "" && decl.name == goName {
+		g.addRenameAlias(decl, t, defaultName)
 	}
+
 	// fmt.Fprintf(os.Stderr, "Type:\t%s.%s\n\t%s.%s\n", owner.String(), name, decl.Package.Path, decl.Name)
 
 	// Predeclare own<T> and borrow<T> for resource types.
@@ -546,6 +970,8 @@ func declareDirectedName(scope gen.Scope, dir wit.Direction, name string) string
 
 // typeDecl returns the typeDecl for [wit.Direction] dir and [wit.TypeDef] t, and whether it was declared.
 func (g *generator) typeDecl(dir wit.Direction, t *wit.TypeDef) (decl *typeDecl, ok bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
 	if decl, ok = g.types[dir][t]; ok {
 		return decl, true
 	}
@@ -565,6 +991,8 @@ func (g *generator) typeDir(dir wit.Direction, t wit.Type) (tdir wit.Direction,
 	if !ok {
 		return dir, false
 	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
 	if _, ok = g.types[dir][td]; ok {
 		return dir, true
 	}
@@ -685,39 +1113,147 @@ func (g *generator) recordRep(file *gen.File, dir wit.Direction, r *wit.Record,
 	b.WriteString("struct {\n")
 	stringio.Write(&b, "_ ", file.Import(g.opts.cmPackage), ".HostLayout")
 	for i, f := range r.Fields {
-		if i == 0 || i > 0 && f.Docs.Contents != "" {
+		if i == 0 || i > 0 && docContents(f.Docs) != "" {
 			b.WriteRune('\n')
 		}
-		b.WriteString(formatDocComments(f.Docs.Contents, false))
-		stringio.Write(&b, fieldName(f.Name, exported), " ", g.typeRep(file, dir, f.Type), "\n")
+		b.WriteString(formatDocComments(docContents(f.Docs), false))
+		stringio.Write(&b, g.fieldName(f.Name, exported), " ", g.typeRep(file, dir, f.Type))
+		if exported {
+			b.WriteString(g.fieldTags(r, &r.Fields[i]))
+		}
+		b.WriteRune('\n')
 	}
 	b.WriteRune('}')
 	return b.String()
 }
 
+// recordConstructor returns Go source for a NewFoo function that returns record goName
+// with each field set to an argument, and a FieldsZero method that reports the fields of
+// a record value that hold their zero value, such as a resource handle that was never set.
+// recordNullAccessors returns Go source for the FooNull and SetFooNull methods of each field Foo
+// of record type goName whose type is an option, which get and set the field as a cm.Null.
+// Methods that would conflict with a field name are omitted.
+func (g *generator) recordNullAccessors(file *gen.File, dir wit.Direction, r *wit.Record, goName string) string {
+	if !token.IsExported(goName) {
+		return ""
+	}
+	fields := make(map[string]bool, len(r.Fields))
+	for _, f := range r.Fields {
+		fields[g.fieldName(f.Name, true)] = true
+	}
+	cm := file.Import(g.opts.cmPackage)
+	var b strings.Builder
+	for _, f := range r.Fields {
+		td, ok := f.Type.(*wit.TypeDef)
+		if !ok {
+			continue
+		}
+		o, ok := td.Root().Kind.(*wit.Option)
+		if !ok {
+			continue
+		}
+		field := g.fieldName(f.Name, true)
+		getter, setter := field+"Null", "Set"+field+"Null"
+		if fields[getter] || fields[setter] {
+			continue
+		}
+		elem := g.typeRep(file, dir, o.Type)
+		value, option := "v."+field, cm+".FromNull(n)"
+		if typ := g.typeRep(file, dir, f.Type); typ != cm+".Option["+elem+"]" {
+			// Named option types are converted to and from cm.Option.
+			value, option = cm+".Option["+elem+"]("+value+")", typ+"("+option+")"
+		}
+		stringio.Write(&b, "// ", getter, " returns field ", field, " of v as a [", cm, ".Null].\n")
+		stringio.Write(&b, "func (v *", goName, ") ", getter, "() ", cm, ".Null[", elem, "] {\n")
+		stringio.Write(&b, "return ", cm, ".ToNull(", value, ")\n")
+		b.WriteString("}\n\n")
+		stringio.Write(&b, "// ", setter, " sets field ", field, " of v from a [", cm, ".Null].\n")
+		stringio.Write(&b, "func (v *", goName, ") ", setter, "(n ", cm, ".Null[", elem, "]) {\n")
+		stringio.Write(&b, "v.", field, " = ", option, "\n")
+		b.WriteString("}\n\n")
+	}
+	return b.String()
+}
+
+func (g *generator) recordConstructor(file *gen.File, dir wit.Direction, r *wit.Record, goName string) string {
+	if !token.IsExported(goName) || len(r.Fields) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	name := file.DeclareName("New" + goName)
+	scope := gen.NewScope(file)
+	params := make([]string, len(r.Fields))
+	stringio.Write(&b, "// ", name, " returns a [", goName, "] with each field set to the corresponding argument.\n")
+	stringio.Write(&b, "func ", name, "(")
+	for i, f := range r.Fields {
+		params[i] = scope.DeclareName(g.fieldName(f.Name, false))
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		stringio.Write(&b, params[i], " ", g.typeRep(file, dir, f.Type))
+	}
+	stringio.Write(&b, ") ", goName, " {\n")
+	stringio.Write(&b, "return ", goName, "{\n")
+	for i, f := range r.Fields {
+		stringio.Write(&b, g.fieldName(f.Name, true), ": ", params[i], ",\n")
+	}
+	b.WriteString("}\n")
+	b.WriteString("}\n\n")
+
+	// A field named fields-zero would conflict with the FieldsZero method.
+	for _, f := range r.Fields {
+		if g.fieldName(f.Name, true) == "FieldsZero" {
+			return b.String()
+		}
+	}
+	b.WriteString("// FieldsZero returns the WIT names of the fields of v that hold their zero value, in order.\n")
+	b.WriteString("// It returns nil if every field is set.\n")
+	stringio.Write(&b, "func (v *", goName, ") FieldsZero() []string {\n")
+	stringio.Write(&b, "var zero ", goName, "\n")
+	b.WriteString("var fields []string\n")
+	for _, f := range r.Fields {
+		field := g.fieldName(f.Name, true)
+		stringio.Write(&b, "if v.", field, " == zero.", field, " {\n")
+		stringio.Write(&b, "fields = append(fields, ", strconv.Quote(f.Name), ")\n")
+		b.WriteString("}\n")
+	}
+	b.WriteString("return fields\n")
+	b.WriteString("}\n\n")
+	return b.String()
+}
+
+// goName returns the Go name for a WIT name using the configured naming scheme.
+func (g *generator) goName(name string, export bool) string {
+	return g.opts.naming.GoName(name, export)
+}
+
 // Field names are implicitly scoped to their parent struct,
 // so we don't need to track the mapping between WIT names and Go names.
-func fieldName(name string, export bool) string {
+func (g *generator) fieldName(name string, export bool) string {
 	if name == "" {
 		return ""
 	}
 	if name[0] >= '0' && name[0] <= '9' {
 		name = "f" + name
 	}
-	return gen.UniqueName(GoName(name, export), gen.IsReserved)
+	return gen.UniqueName(g.goName(name, export), gen.IsReserved)
 }
 
 func (g *generator) tupleRep(file *gen.File, dir wit.Direction, t *wit.Tuple, goName string) string {
 	var b strings.Builder
 	if typ := t.Type(); typ != nil {
 		stringio.Write(&b, "[", strconv.Itoa(len(t.Types)), "]", g.typeRep(file, dir, typ))
-	} else if len(t.Types) == 0 || len(t.Types) > cm.MaxTuple {
+	} else if len(t.Types) == 0 {
 		// Force struct representation
 		return g.typeDefKindRep(file, dir, t.Despecialize(), goName)
 	} else {
-		stringio.Write(&b, file.Import(g.opts.cmPackage), ".Tuple")
-		if len(t.Types) > 2 {
-			b.WriteString(strconv.Itoa(len(t.Types)))
+		if len(t.Types) > cm.MaxTuple {
+			b.WriteString(g.tupleType(file.Package, len(t.Types)))
+		} else {
+			stringio.Write(&b, file.Import(g.opts.cmPackage), ".Tuple")
+			if len(t.Types) > 2 {
+				b.WriteString(strconv.Itoa(len(t.Types)))
+			}
 		}
 		b.WriteRune('[')
 		for i, typ := range t.Types {
@@ -731,6 +1267,44 @@ func (g *generator) tupleRep(file *gen.File, dir wit.Direction, t *wit.Tuple, go
 	return b.String()
 }
 
+// tupleType returns the name of a generic tuple type with n fields, declared in the abi.go file
+// of Go package pkg, for tuples with more fields than the largest tuple type in package cm.
+// The type has the same layout and API as the cm.Tuple types.
+func (g *generator) tupleType(pkg *gen.Package, n int) string {
+	use := tupleUse{pkg, n}
+	abiFile := g.abiFile(pkg)
+	g.mu.Lock()
+	name, ok := g.tuples[use]
+	if !ok {
+		name = abiFile.DeclareName("Tuple" + strconv.Itoa(n))
+		g.tuples[use] = name
+	}
+	g.mu.Unlock()
+	if ok {
+		return name
+	}
+	params := make([]string, n)
+	fields := make([]string, n)
+	for i := range params {
+		params[i] = "T" + strconv.Itoa(i)
+		fields[i] = "t.F" + strconv.Itoa(i)
+	}
+	typeParams := strings.Join(params, ", ")
+	stringio.Write(abiFile, "// ", name, " represents a [Component Model tuple] with ", strconv.Itoa(n), " fields.\n")
+	stringio.Write(abiFile, "// It is declared here because package cm declares tuple types with at most ", strconv.Itoa(cm.MaxTuple), " fields.\n")
+	stringio.Write(abiFile, "//\n// [Component Model tuple]: https://component-model.bytecodealliance.org/design/wit.html#tuples\n")
+	stringio.Write(abiFile, "type ", name, "[", typeParams, " any] struct {\n")
+	stringio.Write(abiFile, "_ ", abiFile.Import(g.opts.cmPackage), ".HostLayout\n")
+	for i, p := range params {
+		stringio.Write(abiFile, "F", strconv.Itoa(i), " ", p, "\n")
+	}
+	abiFile.WriteString("}\n\n")
+	stringio.Write(abiFile, "// Values returns the fields of tuple t.\n")
+	stringio.Write(abiFile, "func (t ", name, "[", typeParams, "]) Values() (", typeParams, ") {\n")
+	stringio.Write(abiFile, "return ", strings.Join(fields, ", "), "\n}\n\n")
+	return name
+}
+
 func (g *generator) flagsRep(file *gen.File, dir wit.Direction, flags *wit.Flags, goName string) string {
 	var b strings.Builder
 
@@ -754,11 +1328,11 @@ func (g *generator) flagsRep(file *gen.File, dir wit.Direction, flags *wit.Flags
 	b.WriteString("\n\n")
 	b.WriteString("const (\n")
 	for i, flag := range flags.Flags {
-		if i > 0 && flag.Docs.Contents != "" {
+		if i > 0 && docContents(flag.Docs) != "" {
 			b.WriteRune('\n')
 		}
-		b.WriteString(formatDocComments(flag.Docs.Contents, false))
-		flagName := file.DeclareName(goName + GoName(flag.Name, true))
+		b.WriteString(formatDocComments(docContents(flag.Docs), false))
+		flagName := file.DeclareName(goName + g.goName(flag.Name, true))
 		b.WriteString(flagName)
 		if i == 0 {
 			stringio.Write(&b, " ", goName, " = 1 << iota")
@@ -775,12 +1349,14 @@ func (g *generator) enumRep(file *gen.File, dir wit.Direction, e *wit.Enum, goNa
 	b.WriteString(g.typeRep(file, dir, disc))
 	b.WriteString("\n\n")
 	b.WriteString("const (\n")
+	caseNames := make([]string, len(e.Cases))
 	for i, c := range e.Cases {
-		if i > 0 && c.Docs.Contents != "" {
+		if i > 0 && docContents(c.Docs) != "" {
 			b.WriteRune('\n')
 		}
-		b.WriteString(formatDocComments(c.Docs.Contents, false))
-		b.WriteString(file.DeclareName(goName + GoName(c.Name, true)))
+		b.WriteString(formatDocComments(docContents(c.Docs), false))
+		caseNames[i] = file.DeclareName(goName + g.goName(c.Name, true))
+		b.WriteString(caseNames[i])
 		if i == 0 {
 			b.WriteRune(' ')
 			b.WriteString(goName)
@@ -790,7 +1366,7 @@ func (g *generator) enumRep(file *gen.File, dir wit.Direction, e *wit.Enum, goNa
 	}
 	b.WriteString(")\n\n")
 
-	stringsName := file.DeclareName("strings" + GoName(goName, true))
+	stringsName := file.DeclareName("strings" + g.goName(goName, true))
 	stringio.Write(&b, "var ", stringsName, " = [", fmt.Sprintf("%d", len(e.Cases)), "]string {\n")
 	for _, c := range e.Cases {
 		stringio.Write(&b, `"`, c.Name, `"`, ",\n")
@@ -802,6 +1378,99 @@ func (g *generator) enumRep(file *gen.File, dir wit.Direction, e *wit.Enum, goNa
 	stringio.Write(&b, "return ", stringsName, "[e]\n")
 	b.WriteString("}\n\n")
 
+	parseName := file.DeclareName("Parse" + goName)
+	stringio.Write(&b, "// ", parseName, " returns the [", goName, "] with enum case name s.\n")
+	b.WriteString("// It returns false if s is not a case name.\n")
+	stringio.Write(&b, "func ", parseName, "(s string) (", goName, ", bool) {\n")
+	stringio.Write(&b, "for i, name := range ", stringsName, " {\n")
+	b.WriteString("if name == s {\n")
+	stringio.Write(&b, "return ", goName, "(i), true\n")
+	b.WriteString("}\n")
+	b.WriteString("}\n")
+	b.WriteString("return 0, false\n")
+	b.WriteString("}\n\n")
+
+	stringio.Write(&b, "// IsValid returns true if e is a case of [", goName, "].\n")
+	stringio.Write(&b, "func (e ", goName, ") IsValid() bool {\n")
+	stringio.Write(&b, "return int(e) < len(", stringsName, ")\n")
+	b.WriteString("}\n\n")
+
+	if g.opts.generateJSON {
+		b.WriteString(g.enumTextMarshalers(file, goName, parseName))
+	}
+	if g.opts.exhaustive {
+		b.WriteString(g.casesFunc(file, goName, goName, caseNames))
+	}
+
+	return b.String()
+}
+
+// casesFunc returns Go source for a function that returns all cases of enum or variant
+// goName, represented as Go type caseType with constants caseNames, in order.
+func (g *generator) casesFunc(file *gen.File, goName, caseType string, caseNames []string) string {
+	var b strings.Builder
+	name := file.DeclareName(goName + "Cases")
+	stringio.Write(&b, "// ", name, " returns all cases of [", goName, "], in order.\n")
+	b.WriteString("// Tests can range over it to check that a switch statement handles every case.\n")
+	stringio.Write(&b, "func ", name, "() []", caseType, " {\n")
+	stringio.Write(&b, "return []", caseType, "{\n")
+	for _, name := range caseNames {
+		stringio.Write(&b, name, ",\n")
+	}
+	b.WriteString("}\n")
+	b.WriteString("}\n\n")
+	return b.String()
+}
+
+// variantCaseType returns Go source for an enum type that represents the cases of variant goName,
+// a Case method that returns the case of a variant value, and a function that returns all cases.
+// Switch statements over the case type can be checked for exhaustiveness by linters.
+func (g *generator) variantCaseType(file *gen.File, dir wit.Direction, v *wit.Variant, goName string) string {
+	var b strings.Builder
+	caseType := file.DeclareName(goName + "Case")
+	disc := g.typeRep(file, dir, wit.Discriminant(len(v.Cases)))
+	stringio.Write(&b, "// ", caseType, " represents the case of a [", goName, "], as returned by [", goName, ".Case].\n")
+	stringio.Write(&b, "type ", caseType, " ", disc, "\n\n")
+	b.WriteString("const (\n")
+	caseNames := make([]string, len(v.Cases))
+	for i, c := range v.Cases {
+		caseNames[i] = file.DeclareName(caseType + g.goName(c.Name, true))
+		b.WriteString(caseNames[i])
+		if i == 0 {
+			stringio.Write(&b, " ", caseType, " = iota")
+		}
+		b.WriteRune('\n')
+	}
+	b.WriteString(")\n\n")
+
+	stringio.Write(&b, "// Case returns the case of v.\n")
+	stringio.Write(&b, "func (v ", goName, ") Case() ", caseType, " {\n")
+	stringio.Write(&b, "return ", caseType, "(v.Tag())\n")
+	b.WriteString("}\n\n")
+
+	b.WriteString(g.casesFunc(file, goName, caseType, caseNames))
+	return b.String()
+}
+
+// enumTextMarshalers returns Go source for the MarshalText and UnmarshalText methods
+// of enum type goName, which encode enum values as their WIT case names with parse function parseName.
+// These are used by encoding/json to represent enum values as JSON strings.
+func (g *generator) enumTextMarshalers(file *gen.File, goName, parseName string) string {
+	var b strings.Builder
+	b.WriteString(formatDocComments("MarshalText implements [encoding.TextMarshaler], returning the enum case name of e.", true))
+	stringio.Write(&b, "func (e ", goName, ") MarshalText() ([]byte, error) {\n")
+	b.WriteString("return []byte(e.String()), nil\n")
+	b.WriteString("}\n\n")
+
+	b.WriteString(formatDocComments("UnmarshalText implements [encoding.TextUnmarshaler], decoding an enum case name into e.", true))
+	stringio.Write(&b, "func (e *", goName, ") UnmarshalText(text []byte) error {\n")
+	stringio.Write(&b, "v, ok := ", parseName, "(string(text))\n")
+	b.WriteString("if !ok {\n")
+	stringio.Write(&b, "return ", file.Import("errors"), ".New(\"unknown enum case: \" + string(text))\n")
+	b.WriteString("}\n")
+	b.WriteString("*e = v\n")
+	b.WriteString("return nil\n")
+	b.WriteString("}\n\n")
 	return b.String()
 }
 
@@ -824,6 +1493,30 @@ func (g *generator) variantRep(file *gen.File, dir wit.Direction, v *wit.Variant
 
 	scope := gen.NewScope(file)
 	scope.DeclareName("String") // For fmt.Stringer
+	if g.opts.generateJSON {
+		scope.DeclareName("MarshalJSON")   // For json.Marshaler
+		scope.DeclareName("UnmarshalJSON") // For json.Unmarshaler
+	}
+	if g.opts.dynamicValues {
+		scope.DeclareName("ToValue")
+		scope.DeclareName("FromValue")
+	}
+	if g.opts.binaryMarshal {
+		scope.DeclareName("MarshalBinary")   // For encoding.BinaryMarshaler
+		scope.DeclareName("UnmarshalBinary") // For encoding.BinaryUnmarshaler
+	}
+	if g.opts.equalMethods {
+		scope.DeclareName("Equal")
+	}
+	if g.opts.deepCopy {
+		scope.DeclareName("DeepCopy")
+	}
+	if g.opts.typeInfo {
+		scope.DeclareName("WITType") // For cm.Describable
+	}
+	if g.opts.exhaustive {
+		scope.DeclareName("Case")
+	}
 
 	// Emit type
 	var b strings.Builder
@@ -831,16 +1524,20 @@ func (g *generator) variantRep(file *gen.File, dir wit.Direction, v *wit.Variant
 	stringio.Write(&b, cm, ".Variant[", g.typeRep(file, dir, disc), ", ", typeShape, ", ", g.typeRep(file, dir, align), "]\n\n")
 
 	// Emit cases
+	caseNames := make([]string, len(v.Cases))
+	constructorNames := make([]string, len(v.Cases))
 	for i, c := range v.Cases {
 		caseNum := strconv.Itoa(i)
-		caseName := scope.DeclareName(GoName(c.Name, true))
+		caseName := scope.DeclareName(g.goName(c.Name, true))
 		constructorName := file.DeclareName(goName + caseName)
+		caseNames[i] = caseName
+		constructorNames[i] = constructorName
 		typeRep := g.typeRep(file, dir, c.Type)
 
 		// Emit constructor
 		stringio.Write(&b, "// ", constructorName, " returns a [", goName, "] of case \"", c.Name, "\".\n")
 		b.WriteString("//\n")
-		b.WriteString(formatDocComments(c.Docs.Contents, false))
+		b.WriteString(formatDocComments(docContents(c.Docs), false))
 		stringio.Write(&b, "func ", constructorName, "(")
 		dataName := "data"
 		if c.Type != nil {
@@ -862,14 +1559,14 @@ func (g *generator) variantRep(file *gen.File, dir wit.Direction, v *wit.Variant
 			b.WriteString("}\n\n")
 		} else {
 			// Case with associated type T returns *T
-			stringio.Write(&b, "// ", caseName, " returns a non-nil *[", typeRep, "] if [", goName, "] represents the variant case \"", c.Name, "\".\n")
+			stringio.Write(&b, "// ", caseName, " returns a non-nil *", g.docLink(file, dir, c.Type), " if [", goName, "] represents the variant case \"", c.Name, "\".\n")
 			stringio.Write(&b, "func (self *", goName, ") ", caseName, "() *", typeRep, " {\n")
 			stringio.Write(&b, "return ", cm, ".Case[", typeRep, "](self, ", caseNum, ")")
 			b.WriteString("}\n\n")
 		}
 	}
 
-	stringsName := file.DeclareName("strings" + GoName(goName, true))
+	stringsName := file.DeclareName("strings" + g.goName(goName, true))
 	stringio.Write(&b, "var ", stringsName, " = [", fmt.Sprintf("%d", len(v.Cases)), "]string {\n")
 	for _, c := range v.Cases {
 		stringio.Write(&b, `"`, c.Name, `"`, ",\n")
@@ -881,6 +1578,86 @@ func (g *generator) variantRep(file *gen.File, dir wit.Direction, v *wit.Variant
 	stringio.Write(&b, "return ", stringsName, "[v.Tag()]\n")
 	b.WriteString("}\n\n")
 
+	if g.opts.exhaustive {
+		b.WriteString(g.variantCaseType(file, dir, v, goName))
+	}
+	if g.opts.generateJSON {
+		b.WriteString(g.variantJSONMarshalers(file, dir, v, goName, caseNames, constructorNames))
+	}
+	if g.opts.dynamicValues {
+		b.WriteString(g.variantValueMethods(file, dir, v, goName, caseNames, constructorNames))
+	}
+	if g.opts.equalMethods {
+		b.WriteString(g.variantEqualMethod(file, dir, v, goName, caseNames))
+	}
+	if g.opts.deepCopy {
+		b.WriteString(g.variantDeepCopyMethod(file, dir, v, goName, caseNames, constructorNames))
+	}
+
+	return b.String()
+}
+
+// variantJSONMarshalers returns Go source for the MarshalJSON and UnmarshalJSON methods
+// of variant type goName. A variant is encoded as a JSON object with a single key
+// for the case name, whose value is the associated value, or null if the case has no
+// associated type.
+func (g *generator) variantJSONMarshalers(file *gen.File, dir wit.Direction, v *wit.Variant, goName string, caseNames, constructorNames []string) string {
+	json := file.Import("encoding/json")
+	var b strings.Builder
+
+	b.WriteString(formatDocComments("MarshalJSON implements [json.Marshaler], encoding v as a JSON object with a single key for the variant case name.", true))
+	stringio.Write(&b, "func (v ", goName, ") MarshalJSON() ([]byte, error) {\n")
+	b.WriteString("var data any\n")
+	b.WriteString("switch v.Tag() {\n")
+	for i, c := range v.Cases {
+		if c.Type == nil {
+			continue
+		}
+		stringio.Write(&b, "case ", strconv.Itoa(i), ": // ", c.Name, "\n")
+		if g.hasJSONType(dir, c.Type) {
+			stringio.Write(&b, "data = (*", g.jsonTypeRep(file, dir, c.Type), ")(", file.Import("unsafe"), ".Pointer(v.", caseNames[i], "()))\n")
+		} else {
+			stringio.Write(&b, "data = v.", caseNames[i], "()\n")
+		}
+	}
+	b.WriteString("}\n")
+	stringio.Write(&b, "return ", json, ".Marshal(map[string]any{v.String(): data})\n")
+	b.WriteString("}\n\n")
+
+	b.WriteString(formatDocComments("UnmarshalJSON implements [json.Unmarshaler], decoding a JSON object with a single key for the variant case name into v.", true))
+	stringio.Write(&b, "func (v *", goName, ") UnmarshalJSON(data []byte) error {\n")
+	stringio.Write(&b, "var m map[string]", json, ".RawMessage\n")
+	stringio.Write(&b, "if err := ", json, ".Unmarshal(data, &m); err != nil {\n")
+	b.WriteString("return err\n")
+	b.WriteString("}\n")
+	b.WriteString("if len(m) != 1 {\n")
+	stringio.Write(&b, "return ", file.Import("errors"), ".New(\"variant: expected JSON object with a single key\")\n")
+	b.WriteString("}\n")
+	b.WriteString("for name, raw := range m {\n")
+	b.WriteString("switch name {\n")
+	for i, c := range v.Cases {
+		stringio.Write(&b, "case \"", c.Name, "\":\n")
+		if c.Type == nil {
+			stringio.Write(&b, "*v = ", constructorNames[i], "()\n")
+		} else {
+			stringio.Write(&b, "var value ", g.typeRep(file, dir, c.Type), "\n")
+			if g.hasJSONType(dir, c.Type) {
+				stringio.Write(&b, "if err := ", json, ".Unmarshal(raw, (*", g.jsonTypeRep(file, dir, c.Type), ")(", file.Import("unsafe"), ".Pointer(&value))); err != nil {\n")
+			} else {
+				stringio.Write(&b, "if err := ", json, ".Unmarshal(raw, &value); err != nil {\n")
+			}
+			b.WriteString("return err\n")
+			b.WriteString("}\n")
+			stringio.Write(&b, "*v = ", constructorNames[i], "(value)\n")
+		}
+		b.WriteString("return nil\n")
+	}
+	b.WriteString("}\n")
+	stringio.Write(&b, "return ", file.Import("errors"), ".New(\"variant: unknown case: \" + name)\n")
+	b.WriteString("}\n")
+	b.WriteString("return nil\n")
+	b.WriteString("}\n\n")
+
 	return b.String()
 }
 
@@ -970,11 +1747,16 @@ func (g *generator) typeDefShape(file *gen.File, dir wit.Direction, t *wit.TypeD
 	}
 
 	use := typeUse{file.Package, dir, t}
+	abiFile := g.abiFile(file.Package)
+	goName := g.typeDefGoName(dir, t) // locks g.mu
+	g.mu.Lock()
 	name, ok := g.shapes[use]
 	if !ok {
-		abiFile := g.abiFile(file.Package)
-		name = abiFile.DeclareName(g.typeDefGoName(dir, t) + "Shape")
+		name = abiFile.DeclareName(goName + "Shape")
 		g.shapes[use] = name
+	}
+	g.mu.Unlock()
+	if !ok {
 		var b bytes.Buffer
 		stringio.Write(&b, "// ", name, " is used for storage in variant or result types.\n")
 		stringio.Write(&b, "type ", name, " struct {\n")
@@ -988,10 +1770,13 @@ func (g *generator) typeDefShape(file *gen.File, dir wit.Direction, t *wit.TypeD
 
 // typeDefGoName returns a mangled Go name for t.
 func (g *generator) typeDefGoName(dir wit.Direction, t *wit.TypeDef) string {
-	if decl, ok := g.types[dir][t]; ok && decl.name != "" {
+	g.mu.Lock()
+	decl, ok := g.types[dir][t]
+	g.mu.Unlock()
+	if ok && decl.name != "" {
 		return decl.name
 	}
-	return GoName(t.WIT(nil, t.TypeName()), true)
+	return g.goName(t.WIT(nil, t.TypeName()), true)
 }
 
 func (g *generator) lowerType(file *gen.File, dir wit.Direction, t wit.Type, input string) string {
@@ -1046,13 +1831,17 @@ func (g *generator) lowerTypeDef(file *gen.File, dir wit.Direction, t *wit.TypeD
 
 func (g *generator) typeDefLowerFunction(file *gen.File, dir wit.Direction, t *wit.TypeDef, input string, body string) string {
 	use := typeUse{file.Package, dir, t}
+	g.mu.Lock()
 	f, ok := g.lowerFunctions[use]
+	g.mu.Unlock()
 	if !ok {
 		abiFile := g.abiFile(file.Package)
 		name := abiFile.DeclareName("lower_" + g.typeDefGoName(dir, t))
 		f = g.goFunction(abiFile, dir, wit.Imported, wit.LowerFunction(t), name)
+		g.mu.Lock()
 		g.lowerFunctions[use] = f
-		stringio.Write(abiFile, "func ", name, g.functionSignature(abiFile, f), " {\n", body, "}\n\n")
+		g.mu.Unlock()
+		stringio.Write(abiFile, "func ", name, g.functionSignature(abiFile, f), " {\n", g.stackProbe(abiFile), body, "}\n\n")
 	}
 	return f.name + "(" + input + ")"
 }
@@ -1070,7 +1859,7 @@ func (g *generator) lowerRecord(file *gen.File, dir wit.Direction, t *wit.TypeDe
 			stringio.Write(&b, "f"+strconv.Itoa(i))
 			i++
 		}
-		stringio.Write(&b, " = ", g.lowerType(abiFile, dir, f.Type, "v."+fieldName(f.Name, true)), "\n")
+		stringio.Write(&b, " = ", g.lowerType(abiFile, dir, f.Type, "v."+g.fieldName(f.Name, true)), "\n")
 	}
 	b.WriteString("return\n")
 	return g.typeDefLowerFunction(file, dir, t, input, b.String())
@@ -1125,7 +1914,7 @@ func (g *generator) lowerVariant(file *gen.File, dir wit.Direction, t *wit.TypeD
 			continue
 		}
 		caseNum := strconv.Itoa(i)
-		caseName := GoName(c.Name, true)
+		caseName := g.goName(c.Name, true)
 		stringio.Write(&b, "case ", caseNum, ": // ", c.Name, "\n")
 		b.WriteString(g.lowerVariantCaseInto(abiFile, dir, c.Type, flat[1:], "*v."+caseName+"()"))
 	}
@@ -1188,7 +1977,18 @@ func (g *generator) lowerPrimitive(file *gen.File, dir wit.Direction, p wit.Prim
 	flat := p.Flat()
 	switch p := p.(type) {
 	case wit.String:
+		switch g.opts.stringCheck {
+		case StringCheckStrict:
+			return g.cmCall(file, "LowerStringStrict", input)
+		case StringCheckLossy:
+			return g.cmCall(file, "LowerStringLossy", input)
+		}
 		return g.cmCall(file, "LowerString", input)
+	case wit.Char:
+		if g.opts.charCheck {
+			return g.cmCall(file, "LowerChar", input)
+		}
+		return g.cast(file, dir, p, flat[0], input)
 	default:
 		return g.cast(file, dir, p, flat[0], input)
 	}
@@ -1223,7 +2023,6 @@ func (g *generator) liftType(file *gen.File, dir wit.Direction, t wit.Type, inpu
 }
 
 func (g *generator) liftTypeDef(file *gen.File, dir wit.Direction, t *wit.TypeDef, input string) string {
-	flat := t.Flat()
 	switch kind := t.Kind.(type) {
 	case wit.Primitive:
 		return g.liftPrimitive(file, dir, t, input)
@@ -1239,7 +2038,7 @@ func (g *generator) liftTypeDef(file *gen.File, dir wit.Direction, t *wit.TypeDe
 	case *wit.Flags:
 		return g.liftFlags(file, dir, t, input)
 	case *wit.Enum:
-		return g.cast(file, dir, flat[0], t, input)
+		return g.liftEnum(file, dir, t, len(kind.Cases), input)
 	case *wit.Variant:
 		return g.liftVariant(file, dir, t, input)
 	case *wit.Result:
@@ -1261,17 +2060,30 @@ func (g *generator) liftTypeDef(file *gen.File, dir wit.Direction, t *wit.TypeDe
 
 func (g *generator) typeDefLiftFunction(file *gen.File, dir wit.Direction, t *wit.TypeDef, input string, body string) string {
 	use := typeUse{file.Package, dir, t}
+	g.mu.Lock()
 	f, ok := g.liftFunctions[use]
+	g.mu.Unlock()
 	if !ok {
 		abiFile := g.abiFile(file.Package)
 		name := abiFile.DeclareName("lift_" + g.typeDefGoName(dir, t))
 		f = g.goFunction(abiFile, dir, wit.Imported, wit.LiftFunction(t), name)
+		g.mu.Lock()
 		g.liftFunctions[use] = f
-		stringio.Write(abiFile, "func ", name, g.functionSignature(abiFile, f), " {\n", body, "}\n\n")
+		g.mu.Unlock()
+		stringio.Write(abiFile, "func ", name, g.functionSignature(abiFile, f), " {\n", g.stackProbe(abiFile), body, "}\n\n")
 	}
 	return f.name + "(" + input + ")"
 }
 
+// liftEnum returns Go source that lifts the Core WebAssembly discriminant input into
+// enum type t with n cases. Unless disabled with [EnumCheck], an out-of-range discriminant panics.
+func (g *generator) liftEnum(file *gen.File, dir wit.Direction, t *wit.TypeDef, n int, input string) string {
+	if g.opts.noEnumCheck {
+		return g.cast(file, dir, t.Flat()[0], t, input)
+	}
+	return g.cmCall(file, "LiftEnum["+g.typeRep(file, dir, t)+"]", input+", "+strconv.Itoa(n))
+}
+
 func (g *generator) liftRecord(file *gen.File, dir wit.Direction, t *wit.TypeDef, input string) string {
 	r := t.Kind.(*wit.Record)
 	abiFile := g.abiFile(file.Package)
@@ -1286,7 +2098,7 @@ func (g *generator) liftRecord(file *gen.File, dir wit.Direction, t *wit.TypeDef
 			stringio.Write(&b2, "f"+strconv.Itoa(i))
 			i++
 		}
-		stringio.Write(&b, "v."+fieldName(f.Name, true), " = ", g.liftType(abiFile, dir, f.Type, b2.String()), "\n")
+		stringio.Write(&b, "v."+g.fieldName(f.Name, true), " = ", g.liftType(abiFile, dir, f.Type, b2.String()), "\n")
 	}
 	b.WriteString("return\n")
 	return g.typeDefLiftFunction(abiFile, dir, t, input, b.String())
@@ -1331,7 +2143,7 @@ func (g *generator) liftVariant(file *gen.File, dir wit.Direction, t *wit.TypeDe
 	v := t.Kind.(*wit.Variant)
 	flat := t.Flat()
 	if v.Enum() != nil {
-		return g.cast(file, dir, flat[0], t, input)
+		return g.liftEnum(file, dir, t, len(v.Cases), input)
 	}
 	abiFile := g.abiFile(file.Package)
 	var b strings.Builder
@@ -1402,6 +2214,11 @@ func (g *generator) liftPrimitive(file *gen.File, dir wit.Direction, t wit.Type,
 	switch p.(type) {
 	case wit.String:
 		return g.cmCall(file, "LiftString["+g.typeRep(file, dir, t)+"]", input)
+	case wit.Char:
+		if g.opts.charCheck {
+			return g.cmCall(file, "LiftChar["+g.typeRep(file, dir, t)+"]", input)
+		}
+		return g.cast(file, dir, flat[0], t, input)
 	default:
 		return g.cast(file, dir, flat[0], t, input)
 	}
@@ -1511,7 +2328,7 @@ func (g *generator) goFunction(file *gen.File, tdir, dir wit.Direction, f *wit.F
 	if len(out.results) == 1 && out.results[0].name == "" {
 		out.results[0].name = scope.DeclareName("result")
 	}
-	if dir == wit.Imported && f.IsMethod() {
+	if dir == wit.Imported && f.IsMethod() && !g.opts.freeFunctions {
 		out.receiver = out.params[0]
 		// out.params = out.params[1:]
 	}
@@ -1522,23 +2339,29 @@ func (g *generator) goParams(scope gen.Scope, dir wit.Direction, params []wit.Pa
 	out := make([]param, len(params))
 	for i, p := range params {
 		tdir, _ := g.typeDir(dir, p.Type)
-		out[i].name = scope.DeclareName(GoName(p.Name, false))
+		out[i].name = scope.DeclareName(g.goName(p.Name, false))
 		out[i].typ = p.Type
 		out[i].dir = tdir
 	}
 	return out
 }
 
+// moduleName returns the Component Model module name of functions imported or exported by owner,
+// e.g. "wasi:cli/environment@0.2.0", or "$root" for functions of a world.
+func (g *generator) moduleName(owner wit.TypeOwner) string {
+	if _, ok := owner.(*wit.World); ok {
+		return "$root"
+	}
+	return g.moduleNames[owner]
+}
+
 func (g *generator) declareFunction(owner wit.TypeOwner, dir wit.Direction, f *wit.Function) (*funcDecl, error) {
 	file := g.fileFor(owner)
 	wasmFile := g.wasmFileFor(owner)
 	var scope gen.Scope = file
 	wasm := f.CoreFunction(dir)
 	tdir := dir
-	module := g.moduleNames[owner]
-	if _, ok := owner.(*wit.World); ok {
-		module = "$root"
-	}
+	module := g.moduleName(owner)
 	var goPrefix, linkerName string
 
 	switch dir {
@@ -1565,7 +2388,7 @@ func (g *generator) declareFunction(owner wit.TypeOwner, dir wit.Direction, f *w
 		return nil, errors.New("BUG: unknown direction " + dir.String())
 	}
 
-	if fdecl, ok := g.functions[dir][f]; ok {
+	if fdecl := g.funcDecl(dir, f); fdecl != nil {
 		return fdecl, nil
 	}
 
@@ -1575,9 +2398,13 @@ func (g *generator) declareFunction(owner wit.TypeOwner, dir wit.Direction, f *w
 	}
 
 	var funcName, wasmName string
+	funcScope := scope
 	switch f.Kind.(type) {
 	case *wit.Freestanding:
-		baseName := GoName(f.BaseName(), true)
+		baseName := g.goName(f.BaseName(), true)
+		if name, ok := g.renamed(owner, f.Name, f); ok {
+			baseName = name
+		}
 		funcName = declareDirectedName(scope, dir, baseName)
 		wasmName = wasmFile.DeclareName(goPrefix + baseName)
 
@@ -1586,7 +2413,7 @@ func (g *generator) declareFunction(owner wit.TypeOwner, dir wit.Direction, f *w
 		td, _ := g.typeDecl(tdir, t)
 		baseName := "New" + td.name
 		if dir == wit.Exported {
-			baseName = GoName(f.BaseName(), true)
+			baseName = g.goName(f.BaseName(), true)
 		}
 		funcName = declareDirectedName(scope, dir, baseName)
 		wasmName = wasmFile.DeclareName(goPrefix + baseName)
@@ -1594,9 +2421,9 @@ func (g *generator) declareFunction(owner wit.TypeOwner, dir wit.Direction, f *w
 	case *wit.Static:
 		t := f.Type().(*wit.TypeDef)
 		td, _ := g.typeDecl(tdir, t)
-		baseName := td.name + GoName(f.BaseName(), true)
+		baseName := td.name + g.goName(f.BaseName(), true)
 		if dir == wit.Exported {
-			baseName = GoName(f.BaseName(), true)
+			baseName = g.goName(f.BaseName(), true)
 		}
 		funcName = declareDirectedName(scope, dir, baseName)
 		wasmName = wasmFile.DeclareName(goPrefix + baseName)
@@ -1604,39 +2431,76 @@ func (g *generator) declareFunction(owner wit.TypeOwner, dir wit.Direction, f *w
 	case *wit.Method:
 		t := f.Type().(*wit.TypeDef)
 		if t.Owner != owner {
-			return nil, fmt.Errorf("cannot emit methods in package %s on type %s", owner.WITPackage().Name.String(), t.TypeName())
+			err := newGenerateError(owner, f.Name, f.Pos, "cannot emit methods in package "+owner.WITPackage().Name.String()+" on type "+t.TypeName())
+			if id, ok := ownerIdent(t.Owner); ok {
+				err.Suggestion = "declare " + f.Name + " in " + t.Owner.WITKind() + " " + id.String() + ", which defines " + t.WITKind() + " " + t.TypeName()
+			}
+			return nil, err
 		}
 		td, _ := g.typeDecl(tdir, t)
 		switch dir {
 		case wit.Imported:
-			funcName = td.scope.DeclareName(GoName(f.BaseName(), true))
+			if g.opts.freeFunctions {
+				funcName = declareDirectedName(scope, dir, td.name+g.goName(f.BaseName(), true))
+				wasmName = wasmFile.DeclareName(goPrefix + funcName)
+				break
+			}
+			funcName = td.scope.DeclareName(g.goName(f.BaseName(), true))
+			funcScope = td.scope
 			if wasm.IsMethod() {
 				wasmName = td.scope.DeclareName(goPrefix + funcName)
 			} else {
 				wasmName = wasmFile.DeclareName(goPrefix + td.name + funcName)
 			}
 		case wit.Exported:
-			funcName = td.scope.DeclareName(GoName(f.BaseName(), true))
-			wasmName = wasmFile.DeclareName(goPrefix + GoName(*t.Name, true) + GoName(f.BaseName(), true))
+			funcName = td.scope.DeclareName(g.goName(f.BaseName(), true))
+			wasmName = wasmFile.DeclareName(goPrefix + g.goName(*t.Name, true) + g.goName(f.BaseName(), true))
 		}
 	}
 
-	fdecl := &funcDecl{
-		owner:      owner,
-		dir:        dir,
-		f:          f,
-		goFunc:     g.goFunction(file, tdir, dir, f, funcName),
-		wasmFunc:   g.goFunction(wasmFile, tdir, dir, wasm, wasmName),
-		linkerName: linkerName,
+	// Imported functions with overridden types call a Go function with the generated types
+	var overrideName string
+	if g.hasOverrides(f) {
+		if dir == wit.Exported {
+			g.importOverrideFuncs(file, dir, f)
+			g.importOverrideTypes(g.exportsFileFor(owner), f)
+		} else if tdir == wit.Imported {
+			g.importOverrideTypes(file, f)
+			g.importOverrideFuncs(file, dir, f)
+			overrideName = funcName
+			funcName = funcScope.DeclareName(unexportedName(funcName))
+		}
 	}
+
+	fdecl := &funcDecl{
+		owner:        owner,
+		dir:          dir,
+		f:            f,
+		goFunc:       g.goFunction(file, tdir, dir, f, funcName),
+		wasmFunc:     g.goFunction(wasmFile, tdir, dir, wasm, wasmName),
+		linkerName:   linkerName,
+		overrideName: overrideName,
+	}
+	g.mu.Lock()
 	g.functions[dir][f] = fdecl
+	g.mu.Unlock()
 	return fdecl, nil
 }
 
+// funcDecl returns the funcDecl for [wit.Direction] dir and [wit.Function] f, or nil if not declared.
+func (g *generator) funcDecl(dir wit.Direction, f *wit.Function) *funcDecl {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.functions[dir][f]
+}
+
 // FIXME: this is a fun hack
 const importedWithExportedTypes = 2
 
 func (g *generator) defineFunction(owner wit.TypeOwner, dir wit.Direction, f *wit.Function) error {
+	if dir == wit.Imported && g.skipped(f) {
+		return nil
+	}
 	decl, err := g.declareFunction(owner, dir, f)
 	if err != nil {
 		return err
@@ -1712,10 +2576,22 @@ func (g *generator) defineImportedFunction(decl *funcDecl) error {
 	var b bytes.Buffer
 
 	// Emit docs
-	b.WriteString(g.functionDocs(dir, decl.f, decl.goFunc.name))
+	if decl.overrideName != "" {
+		link := decl.overrideName
+		if decl.goFunc.isMethod() {
+			link = g.typeRep(file, decl.goFunc.receiver.dir, decl.goFunc.receiver.typ) + "." + link
+		}
+		stringio.Write(&b, "// ", decl.goFunc.name, " calls the imported function ", strconv.Quote(decl.f.Name), " with the generated types.\n")
+		stringio.Write(&b, "// See [", link, "].\n")
+	} else {
+		b.WriteString(g.functionDocs(dir, decl.owner, decl.f, decl.goFunc.name))
+	}
 
 	// Emit Go function
-	b.WriteString("//go:nosplit\n")
+	hooks := g.opts.callHooks && !strings.HasPrefix(decl.linkerName, "[export]")
+	if !hooks {
+		b.WriteString("//go:nosplit\n")
+	}
 	b.WriteString("func ")
 	if decl.goFunc.isMethod() {
 		stringio.Write(&b, "(", decl.goFunc.receiver.name, " ", g.typeRep(file, decl.goFunc.receiver.dir, decl.goFunc.receiver.typ), ") ", decl.goFunc.name)
@@ -1726,20 +2602,56 @@ func (g *generator) defineImportedFunction(decl *funcDecl) error {
 
 	// Emit function body
 	b.WriteString(" {\n")
+	b.WriteString(g.stackProbe(file))
+
+	// Call the registered cm.CallHook, if any
+	if hooks {
+		b.WriteString(g.callHook(decl))
+	}
+
+	// Track resource handles in debug mode
+	debug := g.opts.debug && !strings.HasPrefix(decl.linkerName, "[export]")
+	if debug {
+		b.WriteString(g.debugTrace(decl, "TraceImport"))
+		b.WriteString(g.debugParams(decl))
+	}
+
+	// Call mock implementation, if any
+	if g.opts.mockImports && !strings.HasPrefix(decl.linkerName, "[export]") {
+		b.WriteString(g.mockCall(decl))
+	}
+
+	// Allocate params passed by pointer from the arena
+	var mark, arena string
+	if g.opts.arenaParams && (pointerParam.typ != nil || compoundParams.typ != nil) {
+		mark = decl.goFunc.scope.DeclareName("mark")
+		arena = file.Import(g.opts.cmPackage) + ".DefaultArena"
+		stringio.Write(&b, mark, " := ", arena, ".Mark()\n")
+	}
 
 	// Lower into wasmimport variables
 	if pointerParam.typ != nil {
-		stringio.Write(&b, callParams[0].name, " := &", decl.goFunc.params[0].name, "\n")
+		if arena != "" {
+			stringio.Write(&b, callParams[0].name, " := ", g.cmCall(file, "ArenaNew", arena+", "+decl.goFunc.params[0].name), "\n")
+		} else {
+			stringio.Write(&b, callParams[0].name, " := &", decl.goFunc.params[0].name, "\n")
+		}
 	} else if compoundParams.typ != nil {
-		stringio.Write(&b, compoundParams.name, " := ", g.typeRep(file, compoundParams.dir, compoundParams.typ), "{ ")
+		var fields strings.Builder
+		stringio.Write(&fields, g.typeRep(file, compoundParams.dir, compoundParams.typ), "{ ")
 		for i, p := range decl.goFunc.params {
 			if i > 0 {
-				b.WriteString(", ")
+				fields.WriteString(", ")
 			}
 			// compound parameter struct field names are identical to parameter names
-			stringio.Write(&b, p.name, ": ", p.name)
+			stringio.Write(&fields, p.name, ": ", p.name)
+		}
+		fields.WriteString(" }")
+		if arena != "" {
+			stringio.Write(&b, compoundParams.name, " := ", g.cmCall(file, "ArenaNew", arena+", "+fields.String()), "\n")
+		} else {
+			stringio.Write(&b, compoundParams.name, " := ", fields.String(), "\n")
 		}
-		b.WriteString(" }\n")
 	} else if len(callParams) > 0 {
 		i := 0
 		for _, p := range decl.goFunc.params {
@@ -1777,7 +2689,7 @@ func (g *generator) defineImportedFunction(decl *funcDecl) error {
 		}
 		t := derefPointer(p.typ)
 		// TODO: this logic is ugly
-		if t != nil && (t == compoundParams.typ || t == compoundResults.typ || p.typ == pointerResult.typ) {
+		if t != nil && ((t == compoundParams.typ && arena == "") || t == compoundResults.typ || p.typ == pointerResult.typ) {
 			b.WriteRune('&')
 			b.WriteString(p.name)
 		} else {
@@ -1785,14 +2697,28 @@ func (g *generator) defineImportedFunction(decl *funcDecl) error {
 		}
 	}
 	b.WriteString(")\n")
+	if mark != "" {
+		stringio.Write(&b, arena, ".Release(", mark, ")\n")
+		// The arena is not scanned by the GC, so keep params that hold pointers alive
+		for _, p := range decl.goFunc.params {
+			if wit.HasPointer(p.typ) {
+				stringio.Write(&b, file.Import("runtime"), ".KeepAlive(", p.name, ")\n")
+			}
+		}
+	}
 	if compoundResults.typ != nil {
 		rec := wit.KindOf[*wit.Record](compoundResults.typ)
+		if debug {
+			for _, f := range rec.Fields {
+				b.WriteString(g.debugResult(file, f.Type, compoundResults.name+"."+g.fieldName(f.Name, false)))
+			}
+		}
 		b.WriteString("return ")
 		for i, f := range rec.Fields {
 			if i > 0 {
 				b.WriteString(", ")
 			}
-			stringio.Write(&b, compoundResults.name, ".", fieldName(f.Name, false))
+			stringio.Write(&b, compoundResults.name, ".", g.fieldName(f.Name, false))
 		}
 		b.WriteString("\n")
 	} else if len(callResults) > 0 {
@@ -1800,6 +2726,9 @@ func (g *generator) defineImportedFunction(decl *funcDecl) error {
 		for _, r := range decl.goFunc.results {
 			flat := r.typ.Flat()
 			stringio.Write(&b, r.name, " = ", g.liftType(file, r.dir, r.typ, g.liftTypeInput(file, r.dir, r.typ, callResults[i:i+len(flat)])), "\n")
+			if debug {
+				b.WriteString(g.debugResult(file, r.typ, r.name))
+			}
 			i += len(flat)
 		}
 		b.WriteString("return\n")
@@ -1810,7 +2739,7 @@ func (g *generator) defineImportedFunction(decl *funcDecl) error {
 
 	// Emit wasmimport function in wasm file
 	wasmFile := decl.wasmFunc.file
-
+	wasmFile.BeginDecl(g.funcDeclName(&decl.wasmFunc))
 	stringio.Write(wasmFile, "//go:wasmimport ", decl.linkerName, "\n")
 	wasmFile.WriteString("//go:noescape\n")
 	wasmFile.WriteString("func ")
@@ -1820,8 +2749,13 @@ func (g *generator) defineImportedFunction(decl *funcDecl) error {
 		wasmFile.WriteString(decl.wasmFunc.name)
 	}
 	wasmFile.WriteString(g.functionSignature(wasmFile, decl.wasmFunc))
-
 	wasmFile.WriteString("\n\n")
+	wasmFile.EndDecl()
+
+	// Emit stub function for other targets
+	if g.opts.stubs && g.opts.buildTags != "" {
+		g.defineStub(decl)
+	}
 
 	// Emit shared types
 	if t, ok := compoundParams.typ.(*wit.TypeDef); ok {
@@ -1839,7 +2773,11 @@ func (g *generator) defineImportedFunction(decl *funcDecl) error {
 	}
 
 	// Write to file
-	file.Write(b.Bytes())
+	file.WriteDecl(g.funcDeclName(&decl.goFunc), b.String())
+
+	if decl.overrideName != "" {
+		g.defineOverrideWrapper(decl)
+	}
 
 	return g.ensureEmptyAsm(file.Package)
 }
@@ -1887,19 +2825,33 @@ func (g *generator) defineExportedFunction(decl *funcDecl) error {
 	// Emit exports declaration in exports file
 	{
 		exportsFile := g.exportsFileFor(decl.owner)
-		stringio.Write(exportsFile, "\n", g.functionDocs(dir, decl.f, decl.goFunc.name))
-		stringio.Write(exportsFile, decl.goFunc.name, " func", g.functionSignature(exportsFile, decl.goFunc), "\n")
+		stringio.Write(exportsFile, "\n", g.functionDocs(dir, decl.owner, decl.f, decl.goFunc.name))
+		stringio.Write(exportsFile, decl.goFunc.name, " func", g.overrideSignature(exportsFile, decl.goFunc), "\n")
 	}
 
 	// Emit wasmexport function in wasm file
 	wasmFile := decl.wasmFunc.file
+	wasmFile.BeginDecl(decl.wasmFunc.name)
 
-	stringio.Write(wasmFile, "//go:wasmexport ", decl.linkerName, "\n")
-	stringio.Write(wasmFile, "//export ", decl.linkerName, "\n") // TODO: remove this once TinyGo supports go:wasmexport.
-	stringio.Write(wasmFile, "func ", decl.wasmFunc.name, g.functionSignature(wasmFile, decl.wasmFunc))
+	if g.opts.target != TargetTinyGo {
+		stringio.Write(wasmFile, "//go:wasmexport ", decl.linkerName, "\n")
+	}
+	if g.opts.target != TargetGo {
+		stringio.Write(wasmFile, "//export ", decl.linkerName, "\n") // TODO: remove this once TinyGo supports go:wasmexport.
+	}
+	signature, lift, lower := g.wasmexportSignature(wasmFile, &decl.wasmFunc)
+	stringio.Write(wasmFile, "func ", decl.wasmFunc.name, signature)
 
 	// Emit function body
 	wasmFile.WriteString(" {\n")
+	if g.opts.stackUsage {
+		cm := wasmFile.Import(g.opts.cmPackage)
+		stringio.Write(wasmFile, "defer ", cm, ".StackExit(", strconv.Quote(decl.linkerName), ", ", cm, ".StackEnter())\n")
+	}
+	if g.opts.debug {
+		wasmFile.WriteString(g.debugTrace(decl, "TraceExport"))
+	}
+	wasmFile.WriteString(lift)
 
 	// Lift arguments
 	if compoundParams.typ == nil {
@@ -1928,7 +2880,7 @@ func (g *generator) defineExportedFunction(decl *funcDecl) error {
 			if i > 0 {
 				wasmFile.WriteString(", ")
 			}
-			stringio.Write(wasmFile, compoundResults.name, ".", fieldName(f.Name, false))
+			stringio.Write(wasmFile, compoundResults.name, ".", g.fieldName(f.Name, false))
 		}
 		wasmFile.WriteString(" = ")
 	} else if len(callResults) > 0 {
@@ -1944,9 +2896,13 @@ func (g *generator) defineExportedFunction(decl *funcDecl) error {
 	// Emit caller-defined function name
 	fqName := file.GetName("Exports") + "." + decl.goFunc.name
 	if t := decl.f.Type(); t != nil {
-		fqName = file.GetName("Exports") + "." + scope.GetName(GoName(t.TypeName(), true)) + "." + decl.goFunc.name
+		fqName = file.GetName("Exports") + "." + scope.GetName(g.goName(t.TypeName(), true)) + "." + decl.goFunc.name
 	}
-	stringio.Write(wasmFile, fqName, "(")
+	callName := fqName
+	if g.hasOverrides(decl.f) {
+		callName = g.defineOverrideAdapter(decl, fqName)
+	}
+	stringio.Write(wasmFile, callName, "(")
 
 	// Emit call params
 	if compoundParams.typ != nil {
@@ -1955,7 +2911,7 @@ func (g *generator) defineExportedFunction(decl *funcDecl) error {
 			if i > 0 {
 				wasmFile.WriteString(", ")
 			}
-			stringio.Write(wasmFile, compoundParams.name, ".", fieldName(f.Name, false))
+			stringio.Write(wasmFile, compoundParams.name, ".", g.fieldName(f.Name, false))
 		}
 	} else {
 		for i, p := range callParams {
@@ -1998,13 +2954,15 @@ func (g *generator) defineExportedFunction(decl *funcDecl) error {
 		}
 	}
 
+	wasmFile.WriteString(lower)
 	wasmFile.WriteString("return\n")
 	wasmFile.WriteString("}\n\n")
+	wasmFile.EndDecl()
 
 	var b bytes.Buffer
 
-	// Emit default function body
-	if strings.HasPrefix(decl.f.Name, "[dtor]") || strings.HasPrefix(decl.f.Name, "cabi_post_") {
+	// Emit default function body, unless a resource table defines the destructor
+	if (strings.HasPrefix(decl.f.Name, "[dtor]") && !g.opts.resourceTables) || strings.HasPrefix(decl.f.Name, "cabi_post_") {
 		stringio.Write(&b, "func init() {")
 		stringio.Write(&b, fqName, " = func", g.functionSignature(file, decl.goFunc), " {}\n")
 		b.WriteString("}\n\n")
@@ -2026,12 +2984,29 @@ func (g *generator) defineExportedFunction(decl *funcDecl) error {
 	}
 
 	// Write to file
-	file.Write(b.Bytes())
+	file.WriteDecl(fqName, b.String())
 
-	return g.ensureEmptyAsm(file.Package)
+	return nil
+}
+
+// stackProbe returns a call to cm.StackProbe if stack usage instrumentation is enabled,
+// or an empty string otherwise.
+func (g *generator) stackProbe(file *gen.File) string {
+	if !g.opts.stackUsage {
+		return ""
+	}
+	return g.cmCall(file, "StackProbe", "") + "\n"
 }
 
 func (g *generator) functionSignature(file *gen.File, f function) string {
+	return g.signature(f, func(p param) string {
+		return g.typeRep(file, p.dir, p.typ)
+	})
+}
+
+// signature returns the Go signature of function f, with the Go type of each param
+// and result returned by rep.
+func (g *generator) signature(f function, rep func(param) string) string {
 	var b strings.Builder
 
 	b.WriteRune('(')
@@ -2045,24 +3020,93 @@ func (g *generator) functionSignature(file *gen.File, f function) string {
 		if i > 0 {
 			b.WriteString(", ")
 		}
-		stringio.Write(&b, p.name, " ", g.typeRep(file, p.dir, p.typ))
+		stringio.Write(&b, p.name, " ", rep(p))
 	}
 	b.WriteString(") ")
 
 	// Emit results
 	if len(f.results) == 1 && f.results[0].name == "" {
-		b.WriteString(g.typeRep(file, f.results[0].dir, f.results[0].typ))
+		b.WriteString(rep(f.results[0]))
 	} else if len(f.results) > 0 {
 		b.WriteRune('(')
 		for i, r := range f.results {
 			if i > 0 {
 				b.WriteString(", ")
 			}
-			stringio.Write(&b, r.name, " ", g.typeRep(file, r.dir, r.typ))
+			stringio.Write(&b, r.name, " ", rep(r))
+		}
+		b.WriteRune(')')
+	}
+
+	return b.String()
+}
+
+// wasmexportSignature returns the signature of go:wasmexport function f, with each pointer
+// param and result lowered to unsafe.Pointer, as go:wasmexport in Go 1.24 and later rejects
+// most pointer types, such as *string, or a pointer to a struct with a string field.
+// It also returns the statements that derive the typed params at the start of the function body,
+// and the statements that lower the typed results before it returns. The typed params and
+// results keep the names of those of f, so the body of the function need not change.
+func (g *generator) wasmexportSignature(file *gen.File, f *function) (signature, lift, lower string) {
+	var b, liftb, lowerb strings.Builder
+	b.WriteRune('(')
+	for i, p := range f.params {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		if !isPointer(p.typ) {
+			stringio.Write(&b, p.name, " ", g.typeRep(file, p.dir, p.typ))
+			continue
+		}
+		ptr := f.scope.DeclareName(p.name + "Ptr")
+		stringio.Write(&b, ptr, " ", file.Import("unsafe"), ".Pointer")
+		stringio.Write(&liftb, p.name, " := (", g.typeRep(file, p.dir, p.typ), ")(", ptr, ")\n")
+	}
+	b.WriteString(") ")
+	if len(f.results) > 0 {
+		b.WriteRune('(')
+		for i, r := range f.results {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			if !isPointer(r.typ) {
+				stringio.Write(&b, r.name, " ", g.typeRep(file, r.dir, r.typ))
+				continue
+			}
+			ptr := f.scope.DeclareName(r.name + "Ptr")
+			stringio.Write(&b, ptr, " ", file.Import("unsafe"), ".Pointer")
+			stringio.Write(&liftb, "var ", r.name, " ", g.typeRep(file, r.dir, r.typ), "\n")
+			stringio.Write(&lowerb, ptr, " = ", file.Import("unsafe"), ".Pointer(", r.name, ")\n")
 		}
 		b.WriteRune(')')
 	}
+	return b.String(), liftb.String(), lowerb.String()
+}
 
+// funcTypeRep returns the Go func type for function f. If f is a method,
+// the receiver is the first parameter.
+func (g *generator) funcTypeRep(file *gen.File, f function) string {
+	var b strings.Builder
+	b.WriteString("func(")
+	for i, p := range f.params {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(g.typeRep(file, p.dir, p.typ))
+	}
+	b.WriteRune(')')
+	if len(f.results) == 1 {
+		stringio.Write(&b, " ", g.typeRep(file, f.results[0].dir, f.results[0].typ))
+	} else if len(f.results) > 1 {
+		b.WriteString(" (")
+		for i, r := range f.results {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(g.typeRep(file, r.dir, r.typ))
+		}
+		b.WriteRune(')')
+	}
 	return b.String()
 }
 
@@ -2119,7 +3163,7 @@ func derefAnonRecord(t wit.Type) *wit.TypeDef {
 	return nil
 }
 
-func (g *generator) functionDocs(dir wit.Direction, f *wit.Function, goName string) string {
+func (g *generator) functionDocs(dir wit.Direction, owner wit.TypeOwner, f *wit.Function, goName string) string {
 	var b strings.Builder
 	kind := f.WITKind()
 	dirString := "the " + dir.String()
@@ -2135,11 +3179,16 @@ func (g *generator) functionDocs(dir wit.Direction, f *wit.Function, goName stri
 	} else {
 		stringio.Write(&b, "// ", goName, " represents ", dirString, " ", kind, " \"", f.BaseName(), "\".\n")
 	}
-	if f.Docs.Contents != "" {
+	if docContents(f.Docs) != "" {
 		b.WriteString("//\n")
-		b.WriteString(formatDocComments(f.Docs.Contents, false))
+		b.WriteString(formatDocComments(docContents(f.Docs), false))
+	}
+	if s := stabilityDocs(f.Stability); s != "" {
+		b.WriteString("//\n")
+		b.WriteString(formatDocComments(s, false))
 	}
 	b.WriteString("//\n")
+	b.WriteString(g.docsURLComment(owner, f.Name))
 	if !f.IsAdmin() {
 		w := strings.TrimSuffix(f.WIT(nil, f.BaseName()), ";")
 		b.WriteString(formatDocComments(w, true))
@@ -2147,7 +3196,16 @@ func (g *generator) functionDocs(dir wit.Direction, f *wit.Function, goName stri
 	return b.String()
 }
 
+// ensureEmptyAsm adds an empty.s file to pkg, which allows wasmimport functions
+// to be declared without a body. It does nothing if the empty.s mode is [EmptyAsmNever],
+// or [EmptyAsmAuto] for [TargetTinyGo].
 func (g *generator) ensureEmptyAsm(pkg *gen.Package) error {
+	if g.opts.emptyAsmMode == EmptyAsmNever {
+		return nil
+	}
+	if g.opts.target == TargetTinyGo && g.opts.emptyAsmMode != EmptyAsmAlways {
+		return nil
+	}
 	f := pkg.File("empty.s")
 	if len(f.Content) > 0 {
 		return nil
@@ -2181,12 +3239,20 @@ func (g *generator) exportsFileFor(owner wit.TypeOwner) *gen.File {
 	file.GeneratedBy = g.opts.generatedBy
 	if len(file.Header) == 0 {
 		exports := file.GetName("Exports")
+		instance := file.GetName("ExportsInstance")
 		var b strings.Builder
-		stringio.Write(&b, "// ", exports, " represents the caller-defined exports from \"", g.moduleNames[owner], "\".\n")
-		stringio.Write(&b, "var ", exports, " struct {")
+		stringio.Write(&b, "// ", instance, " represents the caller-defined exports from \"", g.moduleNames[owner], "\".\n")
+		stringio.Write(&b, "// Tests can construct isolated instances. The exported functions in this package\n")
+		stringio.Write(&b, "// call the default instance, [", exports, "].\n")
+		stringio.Write(&b, "type ", instance, " struct {")
 		file.Header = b.String()
+
+		b.Reset()
+		b.WriteString("}\n\n")
+		stringio.Write(&b, "// ", exports, " is the default [", instance, "], called by the exported functions in this package.\n")
+		stringio.Write(&b, "var ", exports, " ", instance, "\n")
+		file.Trailer = b.String()
 	}
-	file.Trailer = "}\n"
 	return file
 }
 
@@ -2242,13 +3308,14 @@ func (g *generator) newPackage(w *wit.World, i *wit.Interface, name string) (*ge
 	if g.opts.packageRoot != "" && g.opts.packageRoot != "std" {
 		segments = append(segments, g.opts.packageRoot)
 	}
-	segments = append(segments, id.Namespace, id.Package)
-	if g.versioned && id.Version != nil {
-		segments = append(segments, "v"+id.Version.String())
+	// WIT names are sanitized, as they may come from untrusted sources.
+	segments = append(segments, PathSegment(id.Namespace), PathSegment(id.Package))
+	if v := g.versionSegment(id); v != "" {
+		segments = append(segments, v)
 	}
-	segments = append(segments, id.Extension)
+	segments = append(segments, PathSegment(id.Extension))
 	if name != id.Extension {
-		segments = append(segments, name) // for anonymous interfaces nested under worlds
+		segments = append(segments, PathSegment(name)) // for anonymous interfaces nested under worlds
 	}
 	path := strings.Join(segments, "/")
 
@@ -2269,6 +3336,17 @@ func (g *generator) newPackage(w *wit.World, i *wit.Interface, name string) (*ge
 	g.witPackages[owner] = pkg
 	g.exportScopes[owner] = gen.NewScope(nil)
 	pkg.DeclareName("Exports")
+	pkg.DeclareName("ExportsInstance")
+	if g.opts.mockImports {
+		pkg.DeclareName("Mocks")
+		pkg.DeclareName("MocksInstance")
+	}
+	if g.opts.layoutTests {
+		pkg.DeclareName("TestLayout")
+	}
+	if g.opts.typeInfo {
+		pkg.DeclareName("WITTypes")
+	}
 
 	return pkg, nil
 }
@@ -0,0 +1,45 @@
+package bindgen
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/bytecodealliance/wasm-tools-go/internal/stringio"
+)
+
+// callHook returns Go statements for the body of the imported function for decl
+// that call the cm.CallHook registered with cm.SetCallHook, if any, before and after
+// the call, and return early if the hook skips the call.
+func (g *generator) callHook(decl *funcDecl) string {
+	file := decl.goFunc.file
+	cm := file.Import(g.opts.cmPackage)
+	hook := decl.goFunc.scope.DeclareName("hook")
+	call := decl.goFunc.scope.DeclareName("call")
+
+	module := g.moduleName(decl.owner)
+
+	params := make([]string, len(decl.goFunc.params))
+	for i, p := range decl.goFunc.params {
+		params[i] = p.name
+	}
+	results := make([]string, len(decl.goFunc.results))
+	for i, r := range decl.goFunc.results {
+		results[i] = "&" + r.name
+	}
+
+	var b strings.Builder
+	stringio.Write(&b, "if ", hook, " := ", cm, ".LoadCallHook(); ", hook, " != nil {\n")
+	stringio.Write(&b, call, " := &", cm, ".Call{Module: ", strconv.Quote(module), ", Name: ", strconv.Quote(decl.f.Name))
+	if len(params) > 0 {
+		stringio.Write(&b, ", Params: []any{", strings.Join(params, ", "), "}")
+	}
+	if len(results) > 0 {
+		stringio.Write(&b, ", Results: []any{", strings.Join(results, ", "), "}")
+	}
+	b.WriteString("}\n")
+	stringio.Write(&b, hook, ".Before(", call, ")\n")
+	stringio.Write(&b, "defer ", hook, ".After(", call, ")\n")
+	stringio.Write(&b, "if ", call, ".Skip {\nreturn\n}\n")
+	b.WriteString("}\n")
+	return b.String()
+}
@@ -0,0 +1,199 @@
+package bindgen
+
+import (
+	"cmp"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/bytecodealliance/wasm-tools-go/internal/go/gen"
+	"github.com/bytecodealliance/wasm-tools-go/internal/stringio"
+	"github.com/bytecodealliance/wasm-tools-go/wit"
+)
+
+// defineInvokers generates a registry of dynamically invocable imported functions
+// in each Go package with imported functions, and in each generated world package.
+// Functions are registered by the package that declares them, because anonymous
+// types such as results and shapes are local to a Go package.
+func (g *generator) defineInvokers() {
+	decls := make(map[*gen.Package][]*funcDecl)
+	for _, decl := range g.functions[wit.Imported] {
+		// Skip imported functions on exported resources, e.g. [export]foo:bar/baz [resource-drop]qux.
+		if strings.HasPrefix(decl.linkerName, "[export]") {
+			continue
+		}
+		// Skip functions with params or results that cannot be converted to or from cm.Value.
+		if !g.invokable(decl.f) {
+			continue
+		}
+		pkg := decl.goFunc.file.Package
+		decls[pkg] = append(decls[pkg], decl)
+	}
+
+	imports := make(map[*gen.Package]string)
+	for _, w := range g.res.Worlds {
+		if g.witPackages[w] == nil {
+			continue
+		}
+		var pkgs []*gen.Package
+		w.Imports.All()(func(_ string, v wit.WorldItem) bool {
+			if ref, ok := v.(*wit.InterfaceRef); ok {
+				if pkg := g.packageFor(ref.Interface); len(decls[pkg]) > 0 && !slices.Contains(pkgs, pkg) {
+					pkgs = append(pkgs, pkg)
+				}
+			}
+			return true
+		})
+		slices.SortFunc(pkgs, func(a, b *gen.Package) int {
+			return strings.Compare(a.Path, b.Path)
+		})
+		for _, pkg := range pkgs {
+			if _, ok := imports[pkg]; !ok {
+				imports[pkg] = g.defineInvoker(pkg, decls[pkg][0].owner, decls[pkg], nil, nil)
+			}
+		}
+		pkg := g.packageFor(w)
+		g.defineWorldInvoker(w, g.defineInvoker(pkg, w, decls[pkg], pkgs, imports))
+	}
+}
+
+// defineInvoker generates function Imports in Go package pkg, which returns a cm.Registry of the
+// functions in decls, merged with the registries of any packages in merge.
+// The names map holds the Go names of the registry functions in other packages.
+// It returns the declared Go name of the function.
+func (g *generator) defineInvoker(pkg *gen.Package, owner wit.TypeOwner, decls []*funcDecl, merge []*gen.Package, names map[*gen.Package]string) string {
+	slices.SortFunc(decls, func(a, b *funcDecl) int {
+		return cmp.Or(
+			strings.Compare(g.invokeName(a), g.invokeName(b)),
+			strings.Compare(a.goFunc.name, b.goFunc.name),
+		)
+	})
+	// Worlds that include other worlds can import more than one function with the same name.
+	decls = slices.CompactFunc(decls, func(a, b *funcDecl) bool {
+		return g.invokeName(a) == g.invokeName(b)
+	})
+
+	file := g.invokeFileFor(pkg)
+	cm := file.Import(g.opts.cmPackage)
+	imports := file.DeclareName("Imports")
+
+	var b strings.Builder
+	stringio.Write(&b, "// ", imports, " returns a [", cm, ".Registry] of the imported functions of ", owner.WITKind(), " \"", g.moduleNames[owner], "\",\n")
+	b.WriteString("// indexed by their fully-qualified WIT names. Each function converts its [")
+	stringio.Write(&b, cm, ".Value] arguments\n")
+	b.WriteString("// and calls the corresponding generated Go function.\n")
+	stringio.Write(&b, "func ", imports, "() ", cm, ".Registry {\n")
+	scope := gen.NewScope(file)
+	r := scope.DeclareName("r")
+	stringio.Write(&b, r, " := ", cm, ".Registry{\n")
+	for _, decl := range decls {
+		stringio.Write(&b, strconv.Quote(g.invokeName(decl)), ": ", g.invokeFunc(file, decl), ",\n")
+	}
+	b.WriteString("}\n")
+	for _, pkg := range merge {
+		stringio.Write(&b, file.Import("maps"), ".Copy(", r, ", ", file.RelativeName(pkg, names[pkg]), "())\n")
+	}
+	stringio.Write(&b, "return ", r, "\n")
+	b.WriteString("}\n\n")
+
+	file.WriteString(b.String())
+	return imports
+}
+
+// defineWorldInvoker generates function Invoke in the Go package for world w,
+// which calls the function returned by imports.
+func (g *generator) defineWorldInvoker(w *wit.World, imports string) {
+	file := g.invokeFileFor(g.packageFor(w))
+	cm := file.Import(g.opts.cmPackage)
+	invoke := file.DeclareName("Invoke")
+
+	var b strings.Builder
+	stringio.Write(&b, "// ", invoke, " calls the function imported by ", w.WITKind(), " \"", g.moduleNames[w], "\" identified by name,\n")
+	b.WriteString("// for example \"wasi:random/random@0.2.0#get-random-bytes\", with args.\n")
+	stringio.Write(&b, "// To call more than one function, retain the [", cm, ".Registry] returned by [", imports, "].\n")
+	stringio.Write(&b, "func ", invoke, "(name string, args ...", cm, ".Value) ([]", cm, ".Value, error) {\n")
+	stringio.Write(&b, "return ", imports, "().Invoke(name, args...)\n")
+	b.WriteString("}\n")
+
+	file.WriteString(b.String())
+}
+
+// invokable returns true if the params and results of function f
+// can be converted to and from dynamic cm.Value values.
+func (g *generator) invokable(f *wit.Function) bool {
+	for _, p := range f.Params {
+		if t, ok := p.Type.(*wit.TypeDef); ok && !g.hasValueMethods(t) {
+			return false
+		}
+	}
+	for _, r := range f.Results {
+		if t, ok := r.Type.(*wit.TypeDef); ok && !g.hasValueMethods(t) {
+			return false
+		}
+	}
+	return true
+}
+
+func (g *generator) invokeFileFor(pkg *gen.Package) *gen.File {
+	file := pkg.File(path.Base(pkg.Path) + ".invoke.go")
+	file.GeneratedBy = g.opts.generatedBy
+	return file
+}
+
+// invokeName returns the fully-qualified WIT name of decl used in the invoker registry.
+func (g *generator) invokeName(decl *funcDecl) string {
+	return g.moduleNames[decl.owner] + "#" + decl.f.Name
+}
+
+// invokeFunc returns a Go func literal of type cm.Func that calls the Go function for decl.
+func (g *generator) invokeFunc(file *gen.File, decl *funcDecl) string {
+	cm := file.Import(g.opts.cmPackage)
+	scope := gen.NewScope(file)
+	args := scope.DeclareName("args")
+	errName := scope.DeclareName("err")
+	goFunc := &decl.goFunc
+
+	var b strings.Builder
+	stringio.Write(&b, "func(", args, " ...", cm, ".Value) ([]", cm, ".Value, error) {\n")
+	stringio.Write(&b, "if ", errName, " := ", cm, ".ExpectArgs(", args, ", ", strconv.Itoa(len(goFunc.params)), "); ", errName, " != nil {\n")
+	stringio.Write(&b, "return nil, ", errName, "\n")
+	b.WriteString("}\n")
+
+	params := make([]string, len(goFunc.params))
+	for i, p := range goFunc.params {
+		params[i] = scope.DeclareName(p.name)
+		stringio.Write(&b, params[i], ", ", errName, " := ", g.fromValueFunc(file, p.dir, p.typ), "(", args, "[", strconv.Itoa(i), "])\n")
+		stringio.Write(&b, "if ", errName, " != nil {\n")
+		stringio.Write(&b, "return nil, ", errName, "\n")
+		b.WriteString("}\n")
+	}
+
+	results := make([]string, len(goFunc.results))
+	for i, r := range goFunc.results {
+		results[i] = scope.DeclareName(r.name)
+	}
+	if len(results) > 0 {
+		stringio.Write(&b, strings.Join(results, ", "), " := ")
+	}
+	if goFunc.isMethod() {
+		stringio.Write(&b, params[0], ".", goFunc.name, "(", strings.Join(params[1:], ", "), ")\n")
+	} else {
+		stringio.Write(&b, file.RelativeName(goFunc.file.Package, goFunc.name), "(", strings.Join(params, ", "), ")\n")
+	}
+
+	if len(results) == 0 {
+		b.WriteString("return nil, nil\n")
+	} else {
+		stringio.Write(&b, "return []", cm, ".Value{")
+		for i, r := range goFunc.results {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			stringio.Write(&b, g.toValueFunc(file, r.dir, r.typ), "(", results[i], ")")
+		}
+		b.WriteString("}, nil\n")
+	}
+	b.WriteString("}")
+	return b.String()
+}
@@ -0,0 +1,245 @@
+package bindgen
+
+import (
+	"cmp"
+	"encoding/json"
+	"path"
+	"slices"
+
+	"github.com/bytecodealliance/wasm-tools-go/internal/go/gen"
+	"github.com/bytecodealliance/wasm-tools-go/wit"
+)
+
+// IR is a language-neutral intermediate representation of the decisions made
+// by the generator for a single WIT world, including the Go package layout,
+// the mapping of WIT types to Go names, and function name mangling.
+// Other tools can consume the IR to use naming consistent with generated Go code
+// without reimplementing [GoName] or the Canonical ABI logic.
+//
+// The IR is serialized as JSON when the [EmitIR] option is set.
+type IR struct {
+	// World is the fully-qualified WIT world, e.g. "wasi:cli/command@0.2.0".
+	World string `json:"world"`
+
+	// Packages are the Go packages generated for the world, sorted by path.
+	Packages []IRPackage `json:"packages"`
+}
+
+// IRPackage describes a Go package generated for a WIT world or interface.
+type IRPackage struct {
+	// Path is the Go package path.
+	Path string `json:"path"`
+
+	// Name is the Go package name.
+	Name string `json:"name"`
+
+	// Owner is the WIT world or interface, e.g. "wasi:clocks/wall-clock@0.2.0".
+	Owner string `json:"owner"`
+
+	// Kind is the WIT kind of Owner, either "world" or "interface".
+	Kind string `json:"kind"`
+
+	// Types are the named WIT types declared in this package, sorted by Go name.
+	Types []IRType `json:"types,omitempty"`
+
+	// Functions are the WIT functions declared in this package, sorted by Go name.
+	Functions []IRFunction `json:"functions,omitempty"`
+}
+
+// IRType describes the mapping of a named WIT type to a Go type.
+type IRType struct {
+	// WITName is the WIT type name, e.g. "datetime".
+	WITName string `json:"wit_name"`
+
+	// WITKind is the WIT kind, e.g. "record" or "resource".
+	WITKind string `json:"wit_kind"`
+
+	// GoName is the unqualified Go type name, e.g. "DateTime".
+	GoName string `json:"go_name"`
+
+	// Direction is either "imported" or "exported" for types that contain
+	// a resource, and empty otherwise.
+	Direction string `json:"direction,omitempty"`
+
+	// Size is the Canonical ABI byte size of the type.
+	Size uintptr `json:"size"`
+
+	// Align is the Canonical ABI byte alignment of the type.
+	Align uintptr `json:"align"`
+}
+
+// IRFunction describes the mapping of a WIT function to Go.
+type IRFunction struct {
+	// WITName is the WIT function name, e.g. "[method]fields.get".
+	WITName string `json:"wit_name"`
+
+	// WITKind is the WIT kind, e.g. "function" or "method".
+	WITKind string `json:"wit_kind"`
+
+	// Direction is either "imported" or "exported".
+	Direction string `json:"direction"`
+
+	// Admin is true if the function is a Canonical ABI administrative function,
+	// such as [resource-drop].
+	Admin bool `json:"admin,omitempty"`
+
+	// GoName is the Go function or method name.
+	GoName string `json:"go_name"`
+
+	// GoReceiver is the Go receiver type name for methods, otherwise empty.
+	// For exported functions, this is the resource type the function is defined on.
+	GoReceiver string `json:"go_receiver,omitempty"`
+
+	// WasmName is the name of the go:wasmimport or go:wasmexport Go function.
+	WasmName string `json:"wasm_name"`
+
+	// LinkerName is the mangled wasmimport or wasmexport linker name.
+	LinkerName string `json:"linker_name"`
+
+	// Params are the function parameters.
+	Params []IRParam `json:"params,omitempty"`
+
+	// Results are the function results.
+	Results []IRParam `json:"results,omitempty"`
+}
+
+// IRParam describes a function parameter or result.
+type IRParam struct {
+	// WITName is the WIT parameter name, which is empty for an anonymous result.
+	WITName string `json:"wit_name,omitempty"`
+
+	// WITType is the WIT type, e.g. "list<u8>".
+	WITType string `json:"wit_type"`
+
+	// GoName is the Go parameter name.
+	GoName string `json:"go_name"`
+}
+
+// emitIR writes a JSON-encoded [IR] for each generated world into its Go package.
+func (g *generator) emitIR() error {
+	for _, w := range g.res.Worlds {
+		pkg := g.witPackages[w]
+		if pkg == nil {
+			continue
+		}
+		file := pkg.File(path.Base(pkg.Path) + ".ir.json")
+		enc := json.NewEncoder(file)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "\t")
+		err := enc.Encode(g.worldIR(w))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// worldIR returns the [IR] for [wit.World] w and the interfaces it references.
+func (g *generator) worldIR(w *wit.World) *IR {
+	owners := []wit.TypeOwner{w}
+	w.AllInterfaces()(func(_ string, i *wit.Interface) bool {
+		owners = append(owners, i)
+		return true
+	})
+
+	ir := &IR{World: g.moduleNames[w]}
+	for _, owner := range owners {
+		pkg := g.witPackages[owner]
+		if pkg == nil || slices.ContainsFunc(ir.Packages, func(p IRPackage) bool { return p.Path == pkg.Path }) {
+			continue
+		}
+		ir.Packages = append(ir.Packages, IRPackage{
+			Path:      pkg.Path,
+			Name:      pkg.Name,
+			Owner:     g.moduleNames[owner],
+			Kind:      owner.WITKind(),
+			Types:     g.irTypes(pkg),
+			Functions: g.irFunctions(pkg),
+		})
+	}
+	slices.SortFunc(ir.Packages, func(a, b IRPackage) int {
+		return cmp.Compare(a.Path, b.Path)
+	})
+	return ir
+}
+
+func (g *generator) irTypes(pkg *gen.Package) []IRType {
+	var types []IRType
+	seen := make(map[*typeDecl]bool)
+	for _, dir := range []wit.Direction{wit.Imported, wit.Exported} {
+		for t, decl := range g.types[dir] {
+			if decl.file.Package != pkg || t.Name == nil || seen[decl] {
+				continue
+			}
+			seen[decl] = true
+			var tdir string
+			if wit.HasResource(t) {
+				tdir = dir.String()
+			}
+			types = append(types, IRType{
+				WITName:   *t.Name,
+				WITKind:   t.WITKind(),
+				GoName:    decl.name,
+				Direction: tdir,
+				Size:      t.Size(),
+				Align:     t.Align(),
+			})
+		}
+	}
+	slices.SortFunc(types, func(a, b IRType) int {
+		return cmp.Or(cmp.Compare(a.GoName, b.GoName), cmp.Compare(a.Direction, b.Direction))
+	})
+	return types
+}
+
+func (g *generator) irFunctions(pkg *gen.Package) []IRFunction {
+	var funcs []IRFunction
+	for _, dir := range []wit.Direction{wit.Imported, wit.Exported} {
+		for f, decl := range g.functions[dir] {
+			if decl.goFunc.file.Package != pkg {
+				continue
+			}
+			irf := IRFunction{
+				WITName:    f.Name,
+				WITKind:    f.WITKind(),
+				Direction:  dir.String(),
+				Admin:      f.IsAdmin(),
+				GoName:     decl.goFunc.name,
+				WasmName:   decl.wasmFunc.name,
+				LinkerName: decl.linkerName,
+				Params:     irParams(f.Params, decl.goFunc.params),
+				Results:    irParams(f.Results, decl.goFunc.results),
+			}
+			if t, ok := f.Type().(*wit.TypeDef); ok && (dir == wit.Exported || decl.goFunc.isMethod()) {
+				if td, ok := g.typeDecl(dir, t); ok {
+					irf.GoReceiver = td.name
+				}
+			}
+			funcs = append(funcs, irf)
+		}
+	}
+	slices.SortFunc(funcs, func(a, b IRFunction) int {
+		return cmp.Or(
+			cmp.Compare(a.GoReceiver, b.GoReceiver),
+			cmp.Compare(a.GoName, b.GoName),
+			cmp.Compare(a.Direction, b.Direction),
+			cmp.Compare(a.WITName, b.WITName),
+		)
+	})
+	return funcs
+}
+
+func irParams(params []wit.Param, goParams []param) []IRParam {
+	var out []IRParam
+	for i, p := range params {
+		irp := IRParam{
+			WITName: p.Name,
+			WITType: p.Type.WIT(&params[i], ""),
+		}
+		if i < len(goParams) {
+			irp.GoName = goParams[i].name
+		}
+		out = append(out, irp)
+	}
+	return out
+}
@@ -0,0 +1,52 @@
+package bindgen
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/bytecodealliance/wasm-tools-go/wit"
+)
+
+func TestEmitIR(t *testing.T) {
+	res, err := wit.LoadJSON(testdataPath + "/codegen/resources.wit.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkgs, err := Go(res, PackageRoot("example.com/gen"), EmitIR(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var found bool
+	for _, pkg := range pkgs {
+		file := pkg.Files["resources.ir.json"]
+		if file == nil {
+			continue
+		}
+		found = true
+		var ir IR
+		err := json.Unmarshal(file.Content, &ir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := ir.World, "my:resources/resources"; got != want {
+			t.Errorf("ir.World: %q, expected %q", got, want)
+		}
+		if len(ir.Packages) == 0 {
+			t.Fatal("ir.Packages: expected at least 1 package")
+		}
+		for _, p := range ir.Packages {
+			if p.Path == "" || p.Name == "" || p.Owner == "" {
+				t.Errorf("incomplete package: %+v", p)
+			}
+			for _, f := range p.Functions {
+				if f.GoName == "" || f.WasmName == "" || f.LinkerName == "" {
+					t.Errorf("incomplete function in %s: %+v", p.Path, f)
+				}
+			}
+		}
+	}
+	if !found {
+		t.Error("resources.ir.json not found in generated packages")
+	}
+}
@@ -0,0 +1,167 @@
+package bindgen
+
+import (
+	"fmt"
+	"go/token"
+	"strconv"
+	"strings"
+
+	"github.com/bytecodealliance/wasm-tools-go/cm"
+	"github.com/bytecodealliance/wasm-tools-go/internal/go/gen"
+	"github.com/bytecodealliance/wasm-tools-go/internal/stringio"
+	"github.com/bytecodealliance/wasm-tools-go/wit"
+)
+
+// cmjsonPackage returns the path of the Go package with JSON encodings of the generic types
+// of the configured cm package.
+func (g *generator) cmjsonPackage() string {
+	return g.opts.cmPackage + "/cmjson"
+}
+
+// hasJSONType returns true if values of WIT type t are encoded as JSON with a different
+// Go type than the type returned by typeRep, because t is or contains an anonymous list,
+// option, or result. Package cm does not implement JSON, so these are encoded with the
+// types of package cmjson. Named types implement JSON themselves.
+func (g *generator) hasJSONType(dir wit.Direction, t wit.Type) bool {
+	td, ok := t.(*wit.TypeDef)
+	if !ok {
+		return false
+	}
+	if _, ok := g.typeDecl(dir, td); ok {
+		return false
+	}
+	return g.hasJSONKind(dir, td.Kind)
+}
+
+// hasJSONKind returns true if values of an anonymous type with [wit.TypeDefKind] kind
+// are encoded as JSON with a different Go type. See [generator.hasJSONType].
+func (g *generator) hasJSONKind(dir wit.Direction, kind wit.TypeDefKind) bool {
+	switch kind := kind.(type) {
+	case wit.Type:
+		return g.hasJSONType(dir, kind)
+	case *wit.List, *wit.Option:
+		return true
+	case *wit.Result:
+		return kind.OK != nil || kind.Err != nil
+	case *wit.Tuple:
+		for _, typ := range kind.Types {
+			if g.hasJSONType(dir, typ) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// jsonTypeRep returns the Go type used to encode a value of WIT type t as JSON.
+// It has the same memory layout as the type returned by typeRep, with anonymous lists,
+// options, and results represented by the equivalent types of package cmjson.
+func (g *generator) jsonTypeRep(file *gen.File, dir wit.Direction, t wit.Type) string {
+	if !g.hasJSONType(dir, t) {
+		return g.typeRep(file, dir, t)
+	}
+	return g.jsonKindRep(file, dir, t.(*wit.TypeDef).Kind)
+}
+
+// jsonKindRep returns the Go type used to encode a value of an anonymous type with
+// [wit.TypeDefKind] kind as JSON. See [generator.jsonTypeRep].
+func (g *generator) jsonKindRep(file *gen.File, dir wit.Direction, kind wit.TypeDefKind) string {
+	cmjson := file.Import(g.cmjsonPackage())
+	switch kind := kind.(type) {
+	case wit.Type:
+		return g.jsonTypeRep(file, dir, kind)
+	case *wit.List:
+		return cmjson + ".List[" + g.jsonTypeRep(file, dir, kind.Type) + "]"
+	case *wit.Option:
+		return cmjson + ".Option[" + g.jsonTypeRep(file, dir, kind.Type) + "]"
+	case *wit.Result:
+		shape := variantShape(kind.Types())
+		var typeShape string
+		if len(kind.Types()) == 1 {
+			typeShape = g.typeRep(file, dir, shape)
+		} else {
+			typeShape = g.typeShape(file, dir, shape)
+		}
+		return cmjson + ".Result[" + typeShape + ", " + g.jsonTypeRep(file, dir, kind.OK) + ", " + g.jsonTypeRep(file, dir, kind.Err) + "]"
+	case *wit.Tuple:
+		if typ := kind.Type(); typ != nil {
+			return "[" + strconv.Itoa(len(kind.Types)) + "]" + g.jsonTypeRep(file, dir, typ)
+		}
+		var b strings.Builder
+		if len(kind.Types) > cm.MaxTuple {
+			b.WriteString(g.tupleType(file.Package, len(kind.Types)))
+		} else {
+			stringio.Write(&b, file.Import(g.opts.cmPackage), ".Tuple")
+			if len(kind.Types) > 2 {
+				b.WriteString(strconv.Itoa(len(kind.Types)))
+			}
+		}
+		b.WriteRune('[')
+		for i, typ := range kind.Types {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(g.jsonTypeRep(file, dir, typ))
+		}
+		b.WriteRune(']')
+		return b.String()
+	}
+	panic(fmt.Sprintf("BUG: unexpected JSON type %T", kind)) // should never reach here
+}
+
+// jsonMarshalers returns Go source for the MarshalJSON and UnmarshalJSON methods of
+// named [wit.TypeDef] t, if its Go type does not otherwise encode as JSON like its WIT type.
+// A record with fields of anonymous lists, options, or results, and a named list, option,
+// result, or tuple, is encoded by converting it to a Go type with the same layout that
+// uses the types of package cmjson. See [generator.jsonTypeRep].
+// Variants and enums are handled in variantJSONMarshalers and enumTextMarshalers.
+func (g *generator) jsonMarshalers(file *gen.File, dir wit.Direction, t *wit.TypeDef, goName string) string {
+	var jsonType string
+	switch kind := t.Kind.(type) {
+	case *wit.Record:
+		exported := token.IsExported(goName)
+		if !exported {
+			return ""
+		}
+		var needed bool
+		for _, f := range kind.Fields {
+			switch g.fieldName(f.Name, exported) {
+			case "MarshalJSON", "UnmarshalJSON":
+				return ""
+			}
+			needed = needed || g.hasJSONType(dir, f.Type)
+		}
+		if !needed {
+			return ""
+		}
+		var b strings.Builder
+		b.WriteString("struct {\n")
+		stringio.Write(&b, "_ ", file.Import(g.opts.cmPackage), ".HostLayout\n")
+		for i, f := range kind.Fields {
+			stringio.Write(&b, g.fieldName(f.Name, exported), " ", g.jsonTypeRep(file, dir, f.Type), g.fieldTags(kind, &kind.Fields[i]), "\n")
+		}
+		b.WriteRune('}')
+		jsonType = b.String()
+	case *wit.List, *wit.Option, *wit.Result, *wit.Tuple:
+		if !g.hasJSONKind(dir, kind) {
+			return ""
+		}
+		jsonType = g.jsonKindRep(file, dir, kind)
+	default:
+		return ""
+	}
+
+	json := file.Import("encoding/json")
+	unsafe := file.Import("unsafe")
+	var b strings.Builder
+	b.WriteString(formatDocComments("MarshalJSON implements [json.Marshaler].", true))
+	stringio.Write(&b, "func (v ", goName, ") MarshalJSON() ([]byte, error) {\n")
+	stringio.Write(&b, "return ", json, ".Marshal((*", jsonType, ")(", unsafe, ".Pointer(&v)))\n")
+	b.WriteString("}\n\n")
+
+	b.WriteString(formatDocComments("UnmarshalJSON implements [json.Unmarshaler].", true))
+	stringio.Write(&b, "func (v *", goName, ") UnmarshalJSON(data []byte) error {\n")
+	stringio.Write(&b, "return ", json, ".Unmarshal(data, (*", jsonType, ")(", unsafe, ".Pointer(v)))\n")
+	b.WriteString("}\n\n")
+	return b.String()
+}
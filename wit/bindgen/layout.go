@@ -0,0 +1,80 @@
+package bindgen
+
+import (
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/bytecodealliance/wasm-tools-go/internal/stringio"
+	"github.com/bytecodealliance/wasm-tools-go/wit"
+)
+
+// declaredType is a Go type declaration for a [wit.TypeDef].
+type declaredType struct {
+	decl *typeDecl
+	t    *wit.TypeDef
+}
+
+// addLayout records a layout test for the Go type declaration decl of [wit.TypeDef] t.
+// Types without a Canonical ABI representation, such as future and stream, are skipped.
+func (g *generator) addLayout(decl *typeDecl, t *wit.TypeDef) {
+	if t.Size() == 0 {
+		return
+	}
+	pkg := decl.file.Package
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, l := range g.layouts[pkg] {
+		if l.decl == decl {
+			return
+		}
+	}
+	g.layouts[pkg] = append(g.layouts[pkg], declaredType{decl, t})
+}
+
+// defineLayoutTests generates a test in each Go package with type declarations that verifies
+// the size and alignment of each Go type matches the Canonical ABI size and alignment of its WIT type.
+func (g *generator) defineLayoutTests() {
+	for pkg, layouts := range g.layouts {
+		file := pkg.File(path.Base(pkg.Path) + ".layout_test.go")
+		file.GeneratedBy = g.opts.generatedBy
+		testing := file.Import("testing")
+		unsafe := file.Import("unsafe")
+		test := file.GetName("TestLayout")
+
+		var b strings.Builder
+		stringio.Write(&b, "// ", test, " verifies that the size and alignment of each Go type in this package\n")
+		b.WriteString("// match the Canonical ABI size and alignment of its WIT type.\n")
+		b.WriteString("// Types that contain pointers are only verified on targets with 32-bit pointers.\n")
+		stringio.Write(&b, "func ", test, "(t *", testing, ".T) {\n")
+		stringio.Write(&b, "ptr32 := ", unsafe, ".Sizeof(uintptr(0)) == 4\n")
+		b.WriteString("tests := []struct {\n")
+		b.WriteString("name string\n")
+		b.WriteString("size, align uintptr\n")
+		b.WriteString("wantSize, wantAlign uintptr\n")
+		b.WriteString("hasPointer bool\n")
+		b.WriteString("}{\n")
+		for _, l := range layouts {
+			zero := "*new(" + l.decl.name + ")"
+			stringio.Write(&b, "{", strconv.Quote(l.decl.name), ", ",
+				unsafe, ".Sizeof(", zero, "), ", unsafe, ".Alignof(", zero, "), ",
+				strconv.FormatUint(uint64(l.t.Size()), 10), ", ", strconv.FormatUint(uint64(l.t.Align()), 10), ", ",
+				strconv.FormatBool(wit.HasPointer(l.t)), "},\n")
+		}
+		b.WriteString("}\n")
+		b.WriteString("for _, tt := range tests {\n")
+		b.WriteString("if tt.hasPointer && !ptr32 {\n")
+		b.WriteString("continue\n")
+		b.WriteString("}\n")
+		b.WriteString("if tt.size != tt.wantSize {\n")
+		b.WriteString("t.Errorf(\"Sizeof(%s): %d, expected %d\", tt.name, tt.size, tt.wantSize)\n")
+		b.WriteString("}\n")
+		b.WriteString("if tt.align != tt.wantAlign {\n")
+		b.WriteString("t.Errorf(\"Alignof(%s): %d, expected %d\", tt.name, tt.align, tt.wantAlign)\n")
+		b.WriteString("}\n")
+		b.WriteString("}\n")
+		b.WriteString("}\n")
+
+		file.WriteString(b.String())
+	}
+}
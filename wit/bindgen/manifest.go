@@ -0,0 +1,81 @@
+package bindgen
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/bytecodealliance/wasm-tools-go/internal/go/gen"
+)
+
+// Manifest is a machine-readable list of generated files, with the Go package and
+// WIT world or interface each was generated from, so build systems can declare
+// the outputs of code generation precisely. It is serialized as JSON.
+type Manifest struct {
+	// PackageRoot is the root Go package path of the generated packages,
+	// which corresponds to the output directory.
+	PackageRoot string `json:"package_root,omitempty"`
+
+	// Files are the generated files, sorted by path.
+	Files []ManifestFile `json:"files"`
+}
+
+// ManifestFile describes a generated file.
+type ManifestFile struct {
+	// Path is the path of the file relative to the output directory, using forward slashes.
+	Path string `json:"path"`
+
+	// Package is the Go package path of the package that contains the file.
+	Package string `json:"package"`
+
+	// World is the qualified name of the WIT world the package was generated from, if any.
+	World string `json:"world,omitempty"`
+
+	// Interface is the qualified name of the WIT interface the package was generated from, if any.
+	Interface string `json:"interface,omitempty"`
+
+	// SHA256 is the SHA-256 hash of the content of the file, in hex.
+	SHA256 string `json:"sha256"`
+}
+
+// Add adds the file at slash-separated path rel, relative to the output directory, to m.
+// If pkg is nil, such as for files fetched from a cache, the Go package path is derived
+// from rel and m.PackageRoot, and the WIT origin of the file is unknown.
+func (m *Manifest) Add(rel string, pkg *gen.Package, content []byte) {
+	sum := sha256.Sum256(content)
+	f := ManifestFile{Path: rel, SHA256: hex.EncodeToString(sum[:])}
+	if pkg != nil {
+		f.Package = pkg.Path
+		kind, name := packageOrigin(pkg)
+		switch kind {
+		case "world":
+			f.World = name
+		case "interface":
+			f.Interface = name
+		}
+	} else {
+		f.Package = path.Join(m.PackageRoot, path.Dir(rel))
+	}
+	i, _ := slices.BinarySearchFunc(m.Files, rel, func(f ManifestFile, rel string) int {
+		return strings.Compare(f.Path, rel)
+	})
+	m.Files = slices.Insert(m.Files, i, f)
+}
+
+// packageOrigin returns the kind and qualified name of the WIT world or interface
+// that Go package pkg was generated from, recorded by defineOrigins,
+// or empty strings if pkg was not generated from WIT.
+func packageOrigin(pkg *gen.Package) (kind, name string) {
+	kind, quoted, ok := strings.Cut(pkg.Origins[""], " ")
+	if !ok {
+		return "", ""
+	}
+	name, err := strconv.Unquote(quoted)
+	if err != nil {
+		return "", ""
+	}
+	return kind, name
+}
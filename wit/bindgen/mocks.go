@@ -0,0 +1,94 @@
+package bindgen
+
+import (
+	"path"
+	"strings"
+
+	"github.com/bytecodealliance/wasm-tools-go/internal/go/gen"
+	"github.com/bytecodealliance/wasm-tools-go/internal/stringio"
+)
+
+// mockCall returns Go statements for the body of the imported function for decl
+// that call its mock implementation, if non-nil, and return its results.
+func (g *generator) mockCall(decl *funcDecl) string {
+	file := decl.goFunc.file
+	g.mu.Lock()
+	g.mocks[file.Package] = append(g.mocks[file.Package], decl)
+	g.mu.Unlock()
+	field := g.mockField(file, decl)
+
+	args := make([]string, len(decl.goFunc.params))
+	for i, p := range decl.goFunc.params {
+		args[i] = p.name
+	}
+
+	var b strings.Builder
+	stringio.Write(&b, "if ", field, " != nil {\n")
+	if len(decl.goFunc.results) > 0 {
+		b.WriteString("return ")
+	}
+	stringio.Write(&b, field, "(", strings.Join(args, ", "), ")\n")
+	if len(decl.goFunc.results) == 0 {
+		b.WriteString("return\n")
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// mockField returns the Go expression for the mock implementation of decl,
+// e.g. Mocks.GetArguments or Mocks.Descriptor.Read.
+func (g *generator) mockField(file *gen.File, decl *funcDecl) string {
+	mocks := file.GetName("Mocks")
+	if decl.goFunc.isMethod() {
+		return mocks + "." + g.typeRep(file, decl.goFunc.receiver.dir, decl.goFunc.receiver.typ) + "." + decl.goFunc.name
+	}
+	return mocks + "." + decl.goFunc.name
+}
+
+// defineMocks generates the Mocks variable in each Go package with imported functions.
+func (g *generator) defineMocks() {
+	for pkg, decls := range g.mocks {
+		file := pkg.File(path.Base(pkg.Path) + ".mocks.go")
+		file.GeneratedBy = g.opts.generatedBy
+		mocks := file.GetName("Mocks")
+		instance := file.GetName("MocksInstance")
+
+		var b strings.Builder
+		stringio.Write(&b, "// ", instance, " holds mock implementations of the functions imported by this package,\n")
+		b.WriteString("// for unit testing on the host. If a mock is non-nil, the imported function calls it\n")
+		b.WriteString("// instead of the wasmimport function. Methods on resources are grouped by resource type.\n")
+		stringio.Write(&b, "type ", instance, " struct {\n")
+
+		// Group methods by receiver type, in order of declaration.
+		var resources []string
+		methods := make(map[string][]*funcDecl)
+		for _, decl := range decls {
+			if !decl.goFunc.isMethod() {
+				stringio.Write(&b, "// ", decl.goFunc.name, " mocks imported ", decl.f.WITKind(), " \"", decl.f.Name, "\".\n")
+				stringio.Write(&b, decl.goFunc.name, " ", g.funcTypeRep(file, decl.goFunc), "\n\n")
+				continue
+			}
+			typ := g.typeRep(file, decl.goFunc.receiver.dir, decl.goFunc.receiver.typ)
+			if methods[typ] == nil {
+				resources = append(resources, typ)
+			}
+			methods[typ] = append(methods[typ], decl)
+		}
+		for _, typ := range resources {
+			stringio.Write(&b, "// ", typ, " mocks the imported methods of resource [", typ, "].\n")
+			stringio.Write(&b, typ, " struct {\n")
+			for _, decl := range methods[typ] {
+				stringio.Write(&b, "// ", decl.goFunc.name, " mocks imported ", decl.f.WITKind(), " \"", decl.f.Name, "\".\n")
+				stringio.Write(&b, decl.goFunc.name, " ", g.funcTypeRep(file, decl.goFunc), "\n\n")
+			}
+			b.WriteString("}\n\n")
+		}
+		b.WriteString("}\n\n")
+
+		stringio.Write(&b, "// ", mocks, " is the [", instance, "] called by the imported functions in this package.\n")
+		stringio.Write(&b, "// Tests can assign mock implementations and reset it to the zero value when done.\n")
+		stringio.Write(&b, "var ", mocks, " ", instance, "\n")
+
+		file.WriteString(b.String())
+	}
+}
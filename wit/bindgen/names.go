@@ -1,8 +1,10 @@
 package bindgen
 
 import (
+	"fmt"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 
 	"github.com/bytecodealliance/wasm-tools-go/internal/go/gen"
 )
@@ -17,8 +19,68 @@ func GoPackageName(name string) string {
 	}, strings.ToLower(name))
 }
 
-// GoName returns an idiomatic (exported CamelCase) Go name for a WIT name.
+// PathSegment returns a sanitized Go package path segment for a WIT name or version.
+// WIT names may come from untrusted sources such as registries, so any character
+// other than a letter, digit, '-', '.', '_', or '+' is replaced with '_', as are
+// leading dots, so the segment cannot contain path separators or refer to a parent
+// directory (..). An empty name returns "_".
+func PathSegment(name string) string {
+	segment := strings.Map(func(c rune) rune {
+		if c < utf8.RuneSelf && (unicode.IsLetter(c) || unicode.IsDigit(c) || strings.ContainsRune("-._+", c)) {
+			return c
+		}
+		return '_'
+	}, name)
+	trimmed := strings.TrimLeft(segment, ".")
+	segment = strings.Repeat("_", len(segment)-len(trimmed)) + trimmed
+	if segment == "" {
+		return "_"
+	}
+	return segment
+}
+
+// Naming is a version of the rules that map WIT names to Go names.
+// Once released, the rules of a version do not change, so code generated with
+// a pinned version keeps the same identifiers when this package is updated.
+// Changes to the rules, such as new initialisms, are introduced in a new version.
+type Naming int
+
+const (
+	// NamingV1 is the original naming scheme.
+	NamingV1 Naming = 1
+
+	// NamingV2 extends NamingV1 to apply initialisms to segments with a numeric suffix,
+	// e.g. "http2" becomes HTTP2 rather than Http2, and "tcp4-socket" becomes TCP4Socket.
+	NamingV2 Naming = 2
+
+	// DefaultNaming is the naming scheme used if none is specified.
+	DefaultNaming = NamingV1
+)
+
+// ParseNaming parses a naming scheme version, e.g. "v1" or "v2".
+func ParseNaming(s string) (Naming, error) {
+	for _, n := range []Naming{NamingV1, NamingV2} {
+		if s == n.String() {
+			return n, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown naming scheme %q: must be v1 or v2", s)
+}
+
+// String returns the name of naming scheme n, e.g. "v1".
+func (n Naming) String() string {
+	return fmt.Sprintf("v%d", int(n))
+}
+
+// GoName returns an idiomatic (exported CamelCase) Go name for a WIT name
+// using the [DefaultNaming] scheme.
 func GoName(name string, export bool) string {
+	return DefaultNaming.GoName(name, export)
+}
+
+// GoName returns an idiomatic (exported CamelCase) Go name for a WIT name
+// using naming scheme n.
+func (n Naming) GoName(name string, export bool) string {
 	var b strings.Builder
 	for i, segment := range segments(name) {
 		if i == 0 && !export {
@@ -40,6 +102,9 @@ func GoName(name string, export bool) string {
 			} else if gen.Initialisms[segment] {
 				// Use opinionated segment from initialisms
 				b.WriteString(strings.ToUpper(segment))
+			} else if n >= NamingV2 && gen.Initialisms[strings.TrimRight(segment, "0123456789")] {
+				// Use opinionated segment from initialisms with a numeric suffix
+				b.WriteString(strings.ToUpper(segment))
 			} else {
 				// Title-case the segment
 				runes := []rune(segment)
@@ -35,3 +35,79 @@ func TestGoName(t *testing.T) {
 		})
 	}
 }
+
+func TestNamingGoName(t *testing.T) {
+	tests := []struct {
+		name   string
+		naming Naming
+		want   string
+	}{
+		{"http2-frame", NamingV1, "Http2Frame"},
+		{"http2-frame", NamingV2, "HTTP2Frame"},
+		{"tcp4-socket", NamingV1, "Tcp4Socket"},
+		{"tcp4-socket", NamingV2, "TCP4Socket"},
+		{"via-tls13", NamingV1, "ViaTls13"},
+		{"via-tls13", NamingV2, "ViaTLS13"},
+		{"ipv4-socket", NamingV2, "IPv4Socket"},
+		{"blocking-read", NamingV2, "BlockingRead"},
+		{"fifo-queue", NamingV2, "FIFOQueue"},
+		{"item-2", NamingV2, "Item2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.naming.String()+"/"+tt.name, func(t *testing.T) {
+			got := tt.naming.GoName(tt.name, true)
+			if got != tt.want {
+				t.Errorf("%s.GoName(%q, true): %q, expected %q", tt.naming, tt.name, got, tt.want)
+			}
+		})
+	}
+	if got, want := GoName("http2-frame", true), DefaultNaming.GoName("http2-frame", true); got != want {
+		t.Errorf("GoName(%q, true): %q, expected %q", "http2-frame", got, want)
+	}
+}
+
+func TestParseNaming(t *testing.T) {
+	for _, n := range []Naming{NamingV1, NamingV2} {
+		got, err := ParseNaming(n.String())
+		if err != nil {
+			t.Errorf("ParseNaming(%q): %v", n, err)
+		}
+		if got != n {
+			t.Errorf("ParseNaming(%q): %v, expected %v", n, got, n)
+		}
+	}
+	for _, s := range []string{"", "v0", "v3", "1", "V1"} {
+		_, err := ParseNaming(s)
+		if err == nil {
+			t.Errorf("ParseNaming(%q): expected error", s)
+		}
+	}
+}
+
+func TestPathSegment(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"wasi", "wasi"},
+		{"ip-name-lookup", "ip-name-lookup"},
+		{"v0.2.0-rc.1+build", "v0.2.0-rc.1+build"},
+		{"", "_"},
+		{".", "_"},
+		{"..", "__"},
+		{"../../etc", "___.._etc"},
+		{"foo/bar", "foo_bar"},
+		{`..\windows`, "___windows"},
+		{"/abs", "_abs"},
+		{"C:", "C_"},
+		{"nul\x00", "nul_"},
+		{"ünïcode", "_n_code"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PathSegment(tt.name); got != tt.want {
+				t.Errorf("PathSegment(%q): %q, expected %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
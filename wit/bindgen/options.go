@@ -1,5 +1,12 @@
 package bindgen
 
+import (
+	"fmt"
+	"go/build/constraint"
+	"go/token"
+	"strings"
+)
+
 // Option represents a single configuration option for this package.
 type Option interface {
 	applyOption(*options) error
@@ -26,8 +33,146 @@ type options struct {
 	// Default: github.com/bytecodealliance/wasm-tools-go/cm.
 	cmPackage string
 
+	// noCMAPICheck determines if generated Go packages omit the declaration
+	// of the minimum API level of package cm they require.
+	noCMAPICheck bool
+
+	// noEnumCheck determines if generated code lifts enum values without checking
+	// that the discriminant is in range.
+	noEnumCheck bool
+
+	// charCheck determines if generated code checks that chars lowered or lifted
+	// are Unicode scalar values.
+	charCheck bool
+
+	// stringCheck is the [StringCheck] mode for lowering strings.
+	// Default: [StringCheckNone].
+	stringCheck string
+
 	// versioned determines if Go packages are generated with version numbers.
 	versioned bool
+
+	// naming is the scheme that maps WIT names to Go names.
+	// Default: [DefaultNaming].
+	naming Naming
+
+	// renames maps qualified WIT identifiers of types and functions to Go names.
+	renames map[string]string
+
+	// overrides maps qualified WIT identifiers of record types to existing Go types.
+	overrides map[string]TypeOverride
+
+	// generateJSON determines if JSON marshaling methods are generated for
+	// records, variants, and enums.
+	generateJSON bool
+
+	// emitIR determines if a JSON-encoded IR file is generated for each world.
+	emitIR bool
+
+	// freeFunctions determines if functions on resource types are generated
+	// as free functions rather than methods.
+	freeFunctions bool
+
+	// managedResources determines if managed wrapper types that implement
+	// io.Closer are generated for imported resources.
+	managedResources bool
+
+	// resourceFinalizers determines if managed resource wrappers register
+	// a runtime finalizer that drops the resource handle.
+	resourceFinalizers bool
+
+	// resourceTables determines if a resource table that maps reps to Go values
+	// is generated for each exported resource.
+	resourceTables bool
+
+	// scaffold determines if a scaffold main package is generated
+	// for each world that exports wasi:cli/run.
+	scaffold bool
+
+	// dynamicValues determines if ToValue and FromValue methods that convert
+	// to and from dynamic cm.Value values are generated for WIT types.
+	dynamicValues bool
+
+	// binaryMarshal determines if MarshalBinary and UnmarshalBinary methods that encode
+	// values in their Canonical ABI memory layout are generated for WIT types.
+	binaryMarshal bool
+
+	// equalMethods determines if Equal methods that compare values,
+	// including the contents of lists and variants, are generated for WIT types.
+	equalMethods bool
+
+	// deepCopy determines if DeepCopy methods that copy strings and lists
+	// into memory allocated on the Go heap are generated for WIT types.
+	deepCopy bool
+
+	// invoker determines if a registry of dynamically invocable imported
+	// functions is generated for each world.
+	invoker bool
+
+	// buildTags is a build constraint expression, e.g. "wasip2",
+	// emitted as a //go:build line on generated files.
+	buildTags string
+
+	// stubs determines if stub functions that panic are generated for
+	// imported functions on targets that do not satisfy buildTags.
+	stubs bool
+
+	// mockImports determines if imported functions can be replaced
+	// with mock implementations for testing.
+	mockImports bool
+
+	// callHooks determines if imported functions call the cm.CallHook registered
+	// with cm.SetCallHook, if any, before and after each call.
+	callHooks bool
+
+	// layoutTests determines if tests that verify the size and alignment
+	// of generated Go types are generated for each Go package.
+	layoutTests bool
+
+	// typeInfo determines if a table of cm.TypeInfo metadata describing
+	// the WIT type of each generated Go type is generated for each Go package.
+	typeInfo bool
+
+	// prune determines if WIT types and functions that are not reachable
+	// from the selected world(s) are omitted from generated Go code.
+	prune bool
+
+	// exhaustive determines if helpers for exhaustive switch statements
+	// over enum and variant cases are generated.
+	exhaustive bool
+
+	// constructors determines if a NewFoo constructor function and FieldsZero method
+	// are generated for each record type.
+	constructors bool
+
+	// nullAccessors determines if methods that get and set option-typed record fields
+	// as cm.Null values are generated.
+	nullAccessors bool
+
+	// docsURLs maps WIT package namespaces to URL templates for links to documentation.
+	docsURLs map[string]string
+
+	// structTags configures the struct tags on generated record fields, if non-nil.
+	structTags *StructTagConfig
+
+	// debug determines if imported functions track the resource handles they
+	// receive, use, and drop with the cm debug hooks.
+	debug bool
+
+	// arenaParams determines if imported functions allocate the parameter struct passed
+	// by pointer to their wasmimport function from cm.DefaultArena.
+	arenaParams bool
+
+	// stackUsage determines if exported functions record their high-water stack usage.
+	stackUsage bool
+
+	// emptyAsmMode determines which Go packages have an empty.s file,
+	// one of [EmptyAsmAuto], [EmptyAsmAlways], or [EmptyAsmNever].
+	emptyAsmMode string
+
+	// target is the Go toolchain that builds generated code,
+	// one of [TargetAny], [TargetGo], or [TargetTinyGo].
+	target string
 }
 
 func (opts *options) apply(o ...Option) error {
@@ -74,11 +219,605 @@ func CMPackage(path string) Option {
 	})
 }
 
+// CMAPICheck returns an [Option] that specifies whether generated Go packages that import
+// the Component Model utility package declare the minimum API level they require, computed
+// from the types and functions of the package they use, so they fail to compile with an
+// older version of the package, such as an outdated fork or vendored copy (default: true). Disable the check for forks of the
+// package that predate its APILevel constant.
+func CMAPICheck(enabled bool) Option {
+	return optionFunc(func(opts *options) error {
+		opts.noCMAPICheck = !enabled
+		return nil
+	})
+}
+
+// EnumCheck returns an [Option] that specifies whether generated code checks that enum
+// discriminants lifted from Core WebAssembly values are in range, with [cm.LiftEnum],
+// which panics (traps) on an out-of-range discriminant as specified by the Canonical ABI
+// (default: true). Disabling the check lifts discriminants with a type conversion,
+// which is faster, but may yield a value that is not a case of the enum type.
+//
+// [cm.LiftEnum]: https://pkg.go.dev/github.com/bytecodealliance/wasm-tools-go/cm#LiftEnum
+func EnumCheck(enabled bool) Option {
+	return optionFunc(func(opts *options) error {
+		opts.noEnumCheck = !enabled
+		return nil
+	})
+}
+
+// CharCheck returns an [Option] that specifies whether generated code checks that WIT chars
+// are Unicode scalar values, with [cm.LowerChar] and [cm.LiftChar] (default: false).
+// A Go rune may hold a surrogate code point or a value greater than 0x10FFFF, which
+// is not a valid char. The Canonical ABI traps on an invalid char; with this option,
+// generated code panics in Go with a descriptive message instead.
+//
+// [cm.LowerChar]: https://pkg.go.dev/github.com/bytecodealliance/wasm-tools-go/cm#LowerChar
+// [cm.LiftChar]: https://pkg.go.dev/github.com/bytecodealliance/wasm-tools-go/cm#LiftChar
+func CharCheck(enabled bool) Option {
+	return optionFunc(func(opts *options) error {
+		opts.charCheck = enabled
+		return nil
+	})
+}
+
+// Modes for the [StringCheck] option.
+const (
+	// StringCheckNone lowers strings with [cm.LowerString], without checking their encoding.
+	//
+	// [cm.LowerString]: https://pkg.go.dev/github.com/bytecodealliance/wasm-tools-go/cm#LowerString
+	StringCheckNone = "none"
+
+	// StringCheckStrict lowers strings with [cm.LowerStringStrict], which panics on invalid UTF-8.
+	//
+	// [cm.LowerStringStrict]: https://pkg.go.dev/github.com/bytecodealliance/wasm-tools-go/cm#LowerStringStrict
+	StringCheckStrict = "strict"
+
+	// StringCheckLossy lowers strings with [cm.LowerStringLossy], which replaces invalid UTF-8
+	// with the Unicode replacement character U+FFFD.
+	//
+	// [cm.LowerStringLossy]: https://pkg.go.dev/github.com/bytecodealliance/wasm-tools-go/cm#LowerStringLossy
+	StringCheckLossy = "lossy"
+)
+
+// StringCheck returns an [Option] that specifies how generated code checks that strings lowered
+// into Core WebAssembly values are valid UTF-8. Component Model strings are sequences of Unicode
+// scalar values, but a Go string may contain arbitrary bytes, on which the host may trap.
+// [StringCheckStrict] panics in Go with a descriptive error instead, and [StringCheckLossy]
+// replaces invalid bytes. By default ([StringCheckNone]), strings are not checked.
+//
+// Strings are checked when lowered into the flat params or results of a function.
+// Strings passed in linear memory, such as the elements of a list, are not checked.
+func StringCheck(mode string) Option {
+	return optionFunc(func(opts *options) error {
+		switch mode {
+		case "":
+			mode = StringCheckNone
+		case StringCheckNone, StringCheckStrict, StringCheckLossy:
+		default:
+			return fmt.Errorf("invalid string check mode %q: expected %q, %q, or %q", mode, StringCheckNone, StringCheckStrict, StringCheckLossy)
+		}
+		opts.stringCheck = mode
+		return nil
+	})
+}
+
 // Versioned returns an [Option] that specifies that all generated Go packages
-// will have versions that match WIT versions.
+// for versioned WIT packages will have versions that match WIT versions.
+//
+// The Go package path for a WIT package includes a version segment, e.g. wasi/io/v0.2.0/streams,
+// only if the WIT package is versioned, and either this option is set or the [wit.Resolve]
+// contains more than one version of the package, such as wasi:io@0.2.0 and wasi:io@0.2.1.
+// Other packages are not affected, so adding a version of one WIT package does not change
+// the Go package paths of unrelated packages.
+//
+// Unversioned WIT packages never have a version segment, even if versioned packages with
+// the same name are present: example:util maps to example/util/types, and example:util@1.0.0
+// to example/util/v1.0.0/types. Version segments always contain a '.', so they cannot
+// conflict with WIT interface or world names.
 func Versioned(versioned bool) Option {
 	return optionFunc(func(opts *options) error {
 		opts.versioned = versioned
 		return nil
 	})
 }
+
+// NamingScheme returns an [Option] that specifies the scheme used to map WIT names to Go names,
+// e.g. [NamingV1]. Pinning a scheme keeps the identifiers in generated code stable when the
+// naming rules of this package are improved. Default: [DefaultNaming].
+func NamingScheme(n Naming) Option {
+	return optionFunc(func(opts *options) error {
+		if n < NamingV1 || n > NamingV2 {
+			return fmt.Errorf("unknown naming scheme %s", n)
+		}
+		opts.naming = n
+		return nil
+	})
+}
+
+// Rename returns an [Option] that specifies the Go name of a WIT type or freestanding function,
+// overriding the name derived by the naming scheme, such as a name mangled to avoid a collision.
+// Ident is the qualified WIT identifier of the type or function in the form
+// "wasi:io/streams@0.2.0#input-stream", or "wasi:io/streams#input-stream" to match any version.
+// The owner may be an interface or a world. GoName must be an exported Go identifier.
+//
+// A renamed type is also declared with its default Go name as a type alias, if that name
+// is not otherwise used, so code written against the default name continues to compile.
+// Generation fails if ident does not identify a type or function in the [wit.Resolve].
+//
+// A Go name can also be specified with a "wit-bindgen-go:name <GoName>" line in the WIT
+// doc comments of a type or freestanding function. Rename takes precedence over it.
+func Rename(ident, goName string) Option {
+	return optionFunc(func(opts *options) error {
+		owner, name, ok := strings.Cut(ident, "#")
+		if !ok || owner == "" || name == "" {
+			return fmt.Errorf("invalid rename %q: expected a WIT identifier such as wasi:io/streams#input-stream", ident)
+		}
+		if !token.IsIdentifier(goName) || !token.IsExported(goName) {
+			return fmt.Errorf("invalid rename %q: %q is not an exported Go identifier", ident, goName)
+		}
+		if opts.renames == nil {
+			opts.renames = make(map[string]string)
+		}
+		opts.renames[ident] = goName
+		return nil
+	})
+}
+
+// OverrideType returns an [Option] that specifies an existing Go type, such as time.Time,
+// that represents a WIT record type in the params and results of functions.
+// Ident is the qualified WIT identifier of the record type in the form
+// "wasi:clocks/wall-clock@0.2.0#datetime", or "wasi:clocks/wall-clock#datetime" to match any version.
+// See [TypeOverride] for more information.
+//
+// Generation fails if ident does not identify a record type in the [wit.Resolve].
+func OverrideType(ident string, override TypeOverride) Option {
+	return optionFunc(func(opts *options) error {
+		owner, name, ok := strings.Cut(ident, "#")
+		if !ok || owner == "" || name == "" {
+			return fmt.Errorf("invalid type override %q: expected a WIT identifier such as wasi:clocks/wall-clock#datetime", ident)
+		}
+		err := override.validate()
+		if err != nil {
+			return fmt.Errorf("invalid type override %q: %w", ident, err)
+		}
+		if opts.overrides == nil {
+			opts.overrides = make(map[string]TypeOverride)
+		}
+		opts.overrides[ident] = override
+		return nil
+	})
+}
+
+// JSON returns an [Option] that specifies whether to generate JSON marshaling
+// methods for WIT records, variants, and enums. Record fields are tagged with
+// their WIT names, variants are encoded as JSON objects with a single key
+// for the case name, and enums are encoded as JSON strings.
+func JSON(enabled bool) Option {
+	return optionFunc(func(opts *options) error {
+		opts.generateJSON = enabled
+		return nil
+	})
+}
+
+// EmitIR returns an [Option] that specifies whether to generate a JSON-encoded [IR] file
+// in the Go package for each WIT world, describing the Go package layout, type names,
+// and function names chosen by the generator.
+func EmitIR(enabled bool) Option {
+	return optionFunc(func(opts *options) error {
+		opts.emitIR = enabled
+		return nil
+	})
+}
+
+// FreeFunctions returns an [Option] that specifies whether functions on resource types,
+// such as WIT methods and resource-drop, are generated as free functions that accept
+// the resource handle as their first argument, rather than as Go methods.
+// For example, the WIT method "[method]descriptor.read" becomes
+// DescriptorRead(self Descriptor, ...) instead of (Descriptor).Read(...).
+func FreeFunctions(enabled bool) Option {
+	return optionFunc(func(opts *options) error {
+		opts.freeFunctions = enabled
+		return nil
+	})
+}
+
+// ManagedResources returns an [Option] that specifies whether to generate a managed wrapper
+// type for each imported resource. The wrapper type implements [io.Closer],
+// dropping the resource handle when closed.
+func ManagedResources(enabled bool) Option {
+	return optionFunc(func(opts *options) error {
+		opts.managedResources = enabled
+		return nil
+	})
+}
+
+// ResourceFinalizers returns an [Option] that specifies whether managed resource wrappers
+// register a runtime finalizer that drops leaked resource handles when garbage collected.
+// Enabling ResourceFinalizers implies [ManagedResources].
+func ResourceFinalizers(enabled bool) Option {
+	return optionFunc(func(opts *options) error {
+		opts.resourceFinalizers = enabled
+		if enabled {
+			opts.managedResources = true
+		}
+		return nil
+	})
+}
+
+// ResourceTables returns an [Option] that specifies whether to generate a resource table
+// for each exported resource, which maps the rep of each resource to a Go value that implements
+// its methods. For an exported resource x, it generates an interface XImpl with the methods
+// of x, a [cm.ResourceTable] XTable, and a function NewX that adds an XImpl to XTable and
+// returns a new handle. The exported methods of x call the methods of the XImpl for the rep
+// passed by the caller, and the exported destructor removes it from XTable and calls its
+// Destructor method, if any. The caller only defines the constructor and static functions
+// of x in Exports.
+func ResourceTables(enabled bool) Option {
+	return optionFunc(func(opts *options) error {
+		opts.resourceTables = enabled
+		return nil
+	})
+}
+
+// Scaffold returns an [Option] that specifies whether to generate a scaffold for each world
+// that exports "wasi:cli/run", as a starting point for a command component. The scaffold is
+// a Go main package in directory cmd/<world> of the world package, which sets the exported
+// run function to call a Run function with the command-line arguments, and declares Stdin,
+// Stdout, and Stderr for the standard streams imported by the world. The scaffold file is
+// meant to be edited, so it is not marked as generated, and wit-bindgen-go does not overwrite it.
+func Scaffold(enabled bool) Option {
+	return optionFunc(func(opts *options) error {
+		opts.scaffold = enabled
+		return nil
+	})
+}
+
+// DynamicValues returns an [Option] that specifies whether to generate ToValue and FromValue
+// methods for named WIT types, which convert Go values to and from the dynamic cm.Value
+// representation. This allows generic tooling, such as REPLs and test drivers,
+// to construct and inspect WIT values without compile-time knowledge of the generated types.
+func DynamicValues(enabled bool) Option {
+	return optionFunc(func(opts *options) error {
+		opts.dynamicValues = enabled
+		return nil
+	})
+}
+
+// BinaryMarshal returns an [Option] that specifies whether to generate MarshalBinary and
+// UnmarshalBinary methods for named WIT types, which implement [encoding.BinaryMarshaler]
+// and [encoding.BinaryUnmarshaler] by copying the value in its Canonical ABI memory layout.
+// This is useful for snapshotting values, golden tests, and comparing values between
+// host and guest. Types that contain pointers or resource handles, such as strings,
+// lists, and resources, have no binary marshaling methods.
+func BinaryMarshal(enabled bool) Option {
+	return optionFunc(func(opts *options) error {
+		opts.binaryMarshal = enabled
+		return nil
+	})
+}
+
+// EqualMethods returns an [Option] that specifies whether to generate Equal methods for
+// named WIT types, which report whether two values are equal. Values that contain lists,
+// options, results, or variants cannot be meaningfully compared with ==, as a cm.List is
+// compared by pointer and a variant or result by its storage. Equal compares the elements
+// of lists and the payloads of options, results, and variants instead.
+// Types that contain resource handles, futures, or streams have no Equal method.
+func EqualMethods(enabled bool) Option {
+	return optionFunc(func(opts *options) error {
+		opts.equalMethods = enabled
+		return nil
+	})
+}
+
+// DeepCopy returns an [Option] that specifies whether to generate DeepCopy methods for named
+// WIT types that contain strings or lists. Strings and lists lifted from the parameters of an
+// exported function refer to memory owned by the caller, which may be reused after the function
+// returns. DeepCopy copies them into memory allocated on the Go heap, using strings.Clone and
+// cm.Clone, so the value can be retained.
+func DeepCopy(enabled bool) Option {
+	return optionFunc(func(opts *options) error {
+		opts.deepCopy = enabled
+		return nil
+	})
+}
+
+// Invoker returns an [Option] that specifies whether to generate a registry of the functions
+// imported by each world, which can be called by name with dynamic cm.Value arguments, e.g.
+// Invoke("wasi:random/random@0.2.0#get-random-bytes", cm.U64Value(16)).
+// This is intended for scripting and fuzz harnesses. Enabling Invoker implies [DynamicValues].
+func Invoker(enabled bool) Option {
+	return optionFunc(func(opts *options) error {
+		opts.invoker = enabled
+		if enabled {
+			opts.dynamicValues = true
+		}
+		return nil
+	})
+}
+
+// BuildTags returns an [Option] that specifies a build constraint expression, such as "wasip2"
+// or "wasip1 || wasip2", emitted as a //go:build line on generated Go and assembly files.
+// This allows generated bindings to live in the same Go module as native implementations
+// for other platforms.
+func BuildTags(tags string) Option {
+	return optionFunc(func(opts *options) error {
+		if tags != "" {
+			_, err := constraint.Parse("//go:build " + tags)
+			if err != nil {
+				return fmt.Errorf("invalid build tags %q: %w", tags, err)
+			}
+		}
+		opts.buildTags = tags
+		return nil
+	})
+}
+
+// Stubs returns an [Option] that specifies whether to generate stub implementations
+// of imported functions for targets that do not satisfy [BuildTags]. Each stub calls
+// a fake registered with cm.RegisterImport, otherwise it panics. If enabled, only the wasmimport and wasmexport declarations are
+// constrained by BuildTags, so the remainder of the generated bindings build on all targets,
+// enabling cross-platform builds and IDE tooling on host operating systems.
+// Stubs has no effect unless BuildTags is set.
+func Stubs(enabled bool) Option {
+	return optionFunc(func(opts *options) error {
+		opts.stubs = enabled
+		return nil
+	})
+}
+
+// MockImports returns an [Option] that specifies whether imported functions can be replaced
+// with mock implementations for unit testing guest code on the host. If enabled, each Go package
+// with imported functions has a Mocks variable with a func field for each imported function.
+// Resource methods are grouped by resource type, e.g. Mocks.Descriptor.Read.
+// Imported functions call their mock, if non-nil, instead of the wasmimport function.
+func MockImports(enabled bool) Option {
+	return optionFunc(func(opts *options) error {
+		opts.mockImports = enabled
+		return nil
+	})
+}
+
+// CallHooks returns an [Option] that specifies whether imported functions call the cm.CallHook
+// registered with cm.SetCallHook, if any, for tracing, metrics, or fault injection without editing
+// generated code. The hook is called before and after each call with the module, function name,
+// params, and results. A hook can change the results, or skip the call to the wasmimport function.
+// If no hook is registered, the overhead of each call is an atomic load. If enabled, imported
+// functions are not marked //go:nosplit, as they defer the call to the hook.
+func CallHooks(enabled bool) Option {
+	return optionFunc(func(opts *options) error {
+		opts.callHooks = enabled
+		return nil
+	})
+}
+
+// LayoutTests returns an [Option] that specifies whether to generate a test in each Go package
+// that verifies the size and alignment of each generated Go type matches the size and alignment
+// of its WIT type in the [Canonical ABI]. This catches layout mismatches between the generator
+// and the Go compiler, such as with gc and TinyGo. Types that contain pointers are only verified
+// on targets with 32-bit pointers.
+//
+// [Canonical ABI]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md
+func LayoutTests(enabled bool) Option {
+	return optionFunc(func(opts *options) error {
+		opts.layoutTests = enabled
+		return nil
+	})
+}
+
+// TypeInfo returns an [Option] that specifies whether to generate a WITTypes table in each
+// Go package, mapping the name of each generated Go type to cm.TypeInfo metadata with its
+// WIT name, kind, and case names. The table is registered with cm.RegisterTypes when the
+// package is initialized, so generic middleware, such as logging, metrics, or serialization,
+// can introspect values of generated types, including result and option types.
+// Each generated type, except resource types, also has a WITType method that implements
+// cm.Describable, so its values can be described with cm.Describe.
+func TypeInfo(enabled bool) Option {
+	return optionFunc(func(opts *options) error {
+		opts.typeInfo = enabled
+		return nil
+	})
+}
+
+// Prune returns an [Option] that specifies whether to omit generated Go code for WIT types
+// that are not reachable from the functions imported or exported by the selected world(s),
+// and for methods of imported resources that are otherwise unreachable. Pruning reduces
+// the size of generated code and of binaries built with it.
+func Prune(enabled bool) Option {
+	return optionFunc(func(opts *options) error {
+		opts.prune = enabled
+		return nil
+	})
+}
+
+// Exhaustive returns an [Option] that specifies whether to generate helpers for checking that
+// switch statements over WIT enum and variant cases are exhaustive. Each enum type Foo has a
+// FooCases function that returns all of its cases. Each variant type Foo has a FooCase enum type
+// with a constant for each case, a Case method, and a FooCases function. Switch statements over
+// these enum types can be checked by linters such as exhaustive, so application code is
+// flagged when a new case is added to the WIT definition.
+func Exhaustive(enabled bool) Option {
+	return optionFunc(func(opts *options) error {
+		opts.exhaustive = enabled
+		return nil
+	})
+}
+
+// Constructors returns an [Option] that specifies whether to generate constructors for WIT
+// record types. Each record type Foo has a NewFoo function that accepts a value for each
+// field, in order, so adding a field to the WIT definition breaks callers that would otherwise
+// leave it unset. Each record type also has a FieldsZero method that returns the names of the
+// fields that hold their zero value, which can be used to check that fields whose zero value
+// is not meaningful, such as resource handles, were set.
+func Constructors(enabled bool) Option {
+	return optionFunc(func(opts *options) error {
+		opts.constructors = enabled
+		return nil
+	})
+}
+
+// NullAccessors returns an [Option] that specifies whether to generate methods that get and set
+// the option-typed fields of WIT record types as cm.Null values, which have the same fields as
+// sql.Null in package database/sql, for code that uses Go nullability patterns rather than
+// cm.Option. A field Foo of type option<T> has methods FooNull, which returns a cm.Null[T],
+// and SetFooNull, which sets the field from a cm.Null[T].
+func NullAccessors(enabled bool) Option {
+	return optionFunc(func(opts *options) error {
+		opts.nullAccessors = enabled
+		return nil
+	})
+}
+
+// DocsURL returns an [Option] that specifies a URL template for links to the documentation of
+// WIT interfaces in namespace, such as "wasi". Links are emitted in the doc comments of the Go
+// packages, types, and functions generated for each interface. The template may contain the
+// placeholders {namespace}, {package}, {version}, {ref}, {interface}, and {item}, where {ref}
+// is "v" followed by the package version, or "main" if the package is unversioned, and {item}
+// is the WIT name of the type or function, or empty for the interface itself.
+//
+// By default, interfaces in the wasi namespace link to their WIT definitions in the
+// WebAssembly/wasi-{package} repository on GitHub. An empty template disables links for namespace.
+func DocsURL(namespace, template string) Option {
+	return optionFunc(func(opts *options) error {
+		if opts.docsURLs == nil {
+			opts.docsURLs = make(map[string]string)
+		}
+		opts.docsURLs[namespace] = template
+		return nil
+	})
+}
+
+// StructTags returns an [Option] that specifies the struct tags, such as json or yaml,
+// emitted on the Go struct fields generated for WIT record fields, so generated types can be
+// encoded by standard encoders without wrapper types. Tags configured for the json key take
+// precedence over the tags emitted by [JSON]. See [StructTagConfig] for more information.
+func StructTags(config *StructTagConfig) Option {
+	return optionFunc(func(opts *options) error {
+		if config != nil {
+			err := config.validate()
+			if err != nil {
+				return err
+			}
+		}
+		opts.structTags = config
+		return nil
+	})
+}
+
+// Debug returns an [Option] that specifies that imported functions call the debug hooks
+// in package cm, which panic on use of a dropped resource handle or a second drop.
+// Imported functions acquire own<T> results, use borrow<T> parameters, and drop own<T>
+// parameters and the handles passed to resource-drop functions.
+// Only parameters and results of handle types are tracked, not handles nested in other types.
+// Imported and exported functions also call cm.TraceImport and cm.TraceExport, which record
+// each call across the component boundary if the program is built with the cm_trace build tag.
+func Debug(debug bool) Option {
+	return optionFunc(func(opts *options) error {
+		opts.debug = debug
+		return nil
+	})
+}
+
+// ArenaParams returns an [Option] that specifies whether imported functions allocate the struct
+// of parameters passed by pointer to their wasmimport function, such as the flattened parameters
+// of functions with many parameters, from cm.DefaultArena instead of the Go heap. The memory is
+// released when the wasmimport function returns. Only the parameter struct is allocated from the
+// arena. Strings and lists are lowered as pointers to their existing memory, and are not copied.
+// This reduces garbage collector pressure from high-frequency calls to imported functions.
+func ArenaParams(enabled bool) Option {
+	return optionFunc(func(opts *options) error {
+		opts.arenaParams = enabled
+		return nil
+	})
+}
+
+// StackUsage returns an [Option] that specifies whether to instrument generated bindings to
+// measure the high-water stack usage of each exported function, to help size the stack of
+// components built with TinyGo, which have small, fixed-size stacks. Exported functions call
+// cm.StackEnter and cm.StackExit, which records the stack usage of calls that panic,
+// and imported functions and lift and lower functions call cm.StackProbe. Use cm.StackReport
+// to report the stack usage. This is intended for debug builds.
+func StackUsage(enabled bool) Option {
+	return optionFunc(func(opts *options) error {
+		opts.stackUsage = enabled
+		return nil
+	})
+}
+
+// Modes for the [EmptyAsm] option.
+const (
+	// EmptyAsmAuto emits empty.s only in Go packages with wasmimport functions,
+	// unless the [Target] is TinyGo, which does not require it.
+	EmptyAsmAuto = "auto"
+
+	// EmptyAsmAlways emits empty.s in every generated Go package.
+	EmptyAsmAlways = "always"
+
+	// EmptyAsmNever does not emit empty.s.
+	EmptyAsmNever = "never"
+)
+
+// EmptyAsm returns an [Option] that specifies which generated Go packages have an empty.s
+// assembly file. The go command only compiles functions declared without a body, such as
+// wasmimport functions, in packages with assembly files. By default ([EmptyAsmAuto]), empty.s
+// is only emitted in packages with wasmimport functions, and not at all for [TargetTinyGo],
+// as TinyGo compiles functions without a body without one. [EmptyAsmAlways] and [EmptyAsmNever]
+// support build setups that add or provide their own assembly files.
+func EmptyAsm(mode string) Option {
+	return optionFunc(func(opts *options) error {
+		switch mode {
+		case "":
+			mode = EmptyAsmAuto
+		case EmptyAsmAuto, EmptyAsmAlways, EmptyAsmNever:
+		default:
+			return fmt.Errorf("invalid empty.s mode %q: expected %q, %q, or %q", mode, EmptyAsmAuto, EmptyAsmAlways, EmptyAsmNever)
+		}
+		opts.emptyAsmMode = mode
+		return nil
+	})
+}
+
+// Targets for the [Target] option.
+const (
+	// TargetAny generates code that builds with either the gc toolchain or TinyGo.
+	TargetAny = "any"
+
+	// TargetGo generates code for the gc toolchain, Go 1.24 or later.
+	TargetGo = "go"
+
+	// TargetTinyGo generates code for TinyGo.
+	TargetTinyGo = "tinygo"
+)
+
+// Target returns an [Option] that specifies the Go toolchain that builds generated code:
+// [TargetGo] for the gc toolchain, [TargetTinyGo] for TinyGo, or [TargetAny] (the default)
+// for code that builds with either. Generated code differs by target in:
+//
+//	                            any                          go                   tinygo
+//	exported function pragmas   //go:wasmexport and //export //go:wasmexport      //export
+//	empty.s with EmptyAsmAuto   wasmimport packages          wasmimport packages  none
+//	build.json targets          gc and tinygo                gc                   tinygo
+//
+// Other generated code is the same for every target:
+//
+//   - In TinyGo on wasm, pointers, uintptr, and int are 32 bits, which matches the Canonical ABI.
+//     With gc they are 64 bits, so generated types that contain pointers, such as lists and strings,
+//     match their Canonical ABI layout only with TinyGo. Generated layout tests (see [LayoutTests])
+//     check the size of pointers when run. Generated types never use int in their layout.
+//   - Imported functions that allocate params from an arena (see [ArenaParams]) call runtime.KeepAlive
+//     to keep the params alive, which both toolchains support. //go:uintptrescapes is not used,
+//     as TinyGo does not support it, and gc applies it only to uintptr params.
+//   - Options that rely on reflection, such as [JSON], use packages such as encoding/json,
+//     which TinyGo supports with limitations.
+func Target(target string) Option {
+	return optionFunc(func(opts *options) error {
+		switch target {
+		case "":
+			target = TargetAny
+		case TargetAny, TargetGo, TargetTinyGo:
+		default:
+			return fmt.Errorf("invalid target %q: expected %q, %q, or %q", target, TargetAny, TargetGo, TargetTinyGo)
+		}
+		opts.target = target
+		return nil
+	})
+}
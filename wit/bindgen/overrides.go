@@ -0,0 +1,337 @@
+package bindgen
+
+import (
+	"fmt"
+	"go/token"
+	"slices"
+	"strings"
+
+	"github.com/bytecodealliance/wasm-tools-go/internal/go/gen"
+	"github.com/bytecodealliance/wasm-tools-go/internal/stringio"
+	"github.com/bytecodealliance/wasm-tools-go/wit"
+)
+
+// TypeOverride describes an existing Go type, such as time.Time, that represents a WIT
+// record type in the params and results of functions, with functions that convert between
+// the Go type and the fields of the record. It can be decoded from JSON, for example:
+//
+//	{
+//		"type": "time.Time",
+//		"lift": "example.com/clock.FromDatetime",
+//		"lower": "example.com/clock.ToDatetime"
+//	}
+//
+// An imported function converts Go values to records with Lower before it calls the
+// imported function, and converts the records it returns with Lift. An exported function
+// converts the records passed to it with Lift before it calls the Go function in Exports,
+// and converts the Go values that function returns with Lower.
+//
+// The Go struct type for the record is still generated. It represents the record where it
+// is nested in other types, such as a list or another record, which must match the memory
+// layout of the Canonical ABI. It is also used by mocks, call hooks, and the invoker.
+//
+// Lift and Lower take and return the fields of the record rather than the struct, so
+// the package that declares them need not import the generated package. Their package
+// must not import the generated package, which imports it. For wasi:clocks/wall-clock#datetime:
+//
+//	func FromDatetime(seconds uint64, nanoseconds uint32) time.Time {
+//		return time.Unix(int64(seconds), int64(nanoseconds))
+//	}
+//
+//	func ToDatetime(t time.Time) (seconds uint64, nanoseconds uint32) {
+//		return uint64(t.Unix()), uint32(t.Nanosecond())
+//	}
+type TypeOverride struct {
+	// Type is the qualified name of the Go type, e.g. "time.Time" or "example.com/clock.Instant".
+	Type string `json:"type"`
+
+	// Lift is the qualified name of a Go function that converts the fields of the record,
+	// in order, to a value of Type, e.g. "example.com/clock.FromDatetime".
+	Lift string `json:"lift"`
+
+	// Lower is the qualified name of a Go function that converts a value of Type to the
+	// fields of the record, in order, e.g. "example.com/clock.ToDatetime".
+	Lower string `json:"lower"`
+}
+
+// validate returns an error if o contains a name that is not a qualified Go name.
+func (o *TypeOverride) validate() error {
+	for _, s := range []struct{ key, name string }{{"type", o.Type}, {"lift", o.Lift}, {"lower", o.Lower}} {
+		if _, _, ok := parseQualifiedName(s.name); !ok {
+			return fmt.Errorf("%s %q: expected a qualified Go name, e.g. time.Time", s.key, s.name)
+		}
+	}
+	return nil
+}
+
+// parseQualifiedName parses a qualified Go name, such as example.com/clock.Instant,
+// into its package path and exported name.
+func parseQualifiedName(s string) (path, name string, ok bool) {
+	i := strings.LastIndexByte(s, '.')
+	if i <= 0 || i < strings.LastIndexByte(s, '/') {
+		return "", "", false
+	}
+	path, name = s[:i], s[i+1:]
+	return path, name, token.IsIdentifier(name) && token.IsExported(name)
+}
+
+// qualifiedName returns a reference to qualified Go name s in file, importing its package.
+func qualifiedName(file *gen.File, s string) string {
+	path, name, _ := parseQualifiedName(s)
+	if pkg := file.Import(path); pkg != "" {
+		return pkg + "." + name
+	}
+	return name
+}
+
+// unexportedName returns Go name with its first letter in lower case, e.g. "now" for "Now".
+func unexportedName(name string) string {
+	if name == "" {
+		return name
+	}
+	return strings.ToLower(name[:1]) + name[1:]
+}
+
+// checkOverrides returns an error if any identifier specified with [OverrideType]
+// does not identify a record type in g.res.
+func (g *generator) checkOverrides() error {
+	idents := make([]string, 0, len(g.opts.overrides))
+	for ident := range g.opts.overrides {
+		idents = append(idents, ident)
+	}
+	slices.Sort(idents)
+	for _, ident := range idents {
+		owner, name, _ := strings.Cut(ident, "#")
+		var t *wit.TypeDef
+		if i := g.res.FindInterface(owner); i != nil && strings.Contains(owner, "/") {
+			t = i.FindTypeDef(name)
+		} else if w := g.res.FindWorld(owner); w != nil && strings.Contains(owner, "/") {
+			t, _ = w.FindImport(name).(*wit.TypeDef)
+		}
+		if t == nil {
+			return newIdentError(ident, "cannot override type: WIT type not found")
+		}
+		if _, ok := t.Root().Kind.(*wit.Record); !ok {
+			err := newGenerateError(t.Owner, name, t.Pos, "cannot override "+t.Root().WITKind()+" "+name+": only records can be overridden")
+			err.Suggestion = "override a record type, or a type alias of a record"
+			return err
+		}
+	}
+	return nil
+}
+
+// typeOverride returns the [TypeOverride] for type t and the record it represents,
+// if t or a type it aliases was specified with [OverrideType].
+func (g *generator) typeOverride(t wit.Type) (*TypeOverride, *wit.Record) {
+	if len(g.opts.overrides) == 0 {
+		return nil, nil
+	}
+	td, _ := t.(*wit.TypeDef)
+	for td != nil {
+		if td.Name != nil {
+			if o, ok := lookupIdent(g.opts.overrides, td.Owner, *td.Name); ok {
+				r, _ := td.Root().Kind.(*wit.Record)
+				return &o, r
+			}
+		}
+		td, _ = td.Kind.(*wit.TypeDef)
+	}
+	return nil, nil
+}
+
+// hasOverrides returns true if any param or result of function f
+// has a type specified with [OverrideType].
+func (g *generator) hasOverrides(f *wit.Function) bool {
+	if len(g.opts.overrides) == 0 {
+		return false
+	}
+	for _, p := range slices.Concat(f.Params, f.Results) {
+		if o, _ := g.typeOverride(p.Type); o != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// importOverrideTypes imports the packages of the Go types specified with [OverrideType]
+// for the params and results of function f into file. It is called before the names of
+// the params are declared, so the package names do not collide with them.
+func (g *generator) importOverrideTypes(file *gen.File, f *wit.Function) {
+	for _, p := range slices.Concat(f.Params, f.Results) {
+		if o, _ := g.typeOverride(p.Type); o != nil {
+			_ = qualifiedName(file, o.Type)
+		}
+	}
+}
+
+// importOverrideFuncs imports the packages of the Lift and Lower functions specified with
+// [OverrideType] that convert the params and results of function f in direction dir.
+// Imported functions lower params and lift results; exported functions do the reverse.
+func (g *generator) importOverrideFuncs(file *gen.File, dir wit.Direction, f *wit.Function) {
+	for _, p := range f.Params {
+		if o, _ := g.typeOverride(p.Type); o != nil {
+			if dir == wit.Imported {
+				_ = qualifiedName(file, o.Lower)
+			} else {
+				_ = qualifiedName(file, o.Lift)
+			}
+		}
+	}
+	for _, r := range f.Results {
+		if o, _ := g.typeOverride(r.Type); o != nil {
+			if dir == wit.Imported {
+				_ = qualifiedName(file, o.Lift)
+			} else {
+				_ = qualifiedName(file, o.Lower)
+			}
+		}
+	}
+}
+
+// overrideSignature returns the Go signature of function f, with the Go type
+// specified with [OverrideType] for each param or result of an overridden type.
+func (g *generator) overrideSignature(file *gen.File, f function) string {
+	return g.signature(f, func(p param) string {
+		if o, _ := g.typeOverride(p.typ); o != nil {
+			return qualifiedName(file, o.Type)
+		}
+		return g.typeRep(file, p.dir, p.typ)
+	})
+}
+
+// liftOverride returns a call to the Lift function of [TypeOverride] o
+// with the fields of record r in input.
+func (g *generator) liftOverride(file *gen.File, o *TypeOverride, r *wit.Record, input string) string {
+	fields := make([]string, len(r.Fields))
+	for i, f := range r.Fields {
+		fields[i] = input + "." + g.fieldName(f.Name, true)
+	}
+	return qualifiedName(file, o.Lift) + "(" + strings.Join(fields, ", ") + ")"
+}
+
+// lowerOverride returns a statement that assigns the fields of record r in output
+// from a call to the Lower function of [TypeOverride] o with input.
+func (g *generator) lowerOverride(file *gen.File, o *TypeOverride, r *wit.Record, output, input string) string {
+	fields := make([]string, len(r.Fields))
+	for i, f := range r.Fields {
+		fields[i] = output + "." + g.fieldName(f.Name, true)
+	}
+	return strings.Join(fields, ", ") + " = " + qualifiedName(file, o.Lower) + "(" + input + ")\n"
+}
+
+// defineOverrideWrapper emits the Go function for imported function decl with the types
+// specified with [OverrideType], which calls decl.goFunc with the generated types.
+func (g *generator) defineOverrideWrapper(decl *funcDecl) {
+	file := decl.goFunc.file
+	f := decl.goFunc
+	f.name = decl.overrideName
+	scope := declareParams(file, f)
+
+	var b strings.Builder
+	b.WriteString(g.functionDocs(wit.Imported, decl.owner, decl.f, f.name))
+	b.WriteString("func ")
+	callee := decl.goFunc.name
+	params := f.params
+	if f.isMethod() {
+		stringio.Write(&b, "(", f.receiver.name, " ", g.typeRep(file, f.receiver.dir, f.receiver.typ), ") ", f.name)
+		callee = f.receiver.name + "." + callee
+		params = params[1:]
+	} else {
+		b.WriteString(f.name)
+	}
+	b.WriteString(g.overrideSignature(file, f))
+	b.WriteString(" {\n")
+
+	// Lower params
+	args := make([]string, len(params))
+	for i, p := range params {
+		args[i] = p.name
+		if o, r := g.typeOverride(p.typ); o != nil {
+			args[i] = scope.DeclareName(p.name + "_")
+			stringio.Write(&b, "var ", args[i], " ", g.typeRep(file, p.dir, p.typ), "\n")
+			b.WriteString(g.lowerOverride(file, o, r, args[i], p.name))
+		}
+	}
+
+	// Call function with generated types
+	results := make([]string, len(f.results))
+	for i, r := range f.results {
+		results[i] = scope.DeclareName(r.name + "_")
+	}
+	if len(results) > 0 {
+		stringio.Write(&b, strings.Join(results, ", "), " := ")
+	}
+	stringio.Write(&b, callee, "(", strings.Join(args, ", "), ")\n")
+
+	// Lift results
+	for i, r := range f.results {
+		if o, rec := g.typeOverride(r.typ); o != nil {
+			stringio.Write(&b, r.name, " = ", g.liftOverride(file, o, rec, results[i]), "\n")
+		} else {
+			stringio.Write(&b, r.name, " = ", results[i], "\n")
+		}
+	}
+	b.WriteString("return\n")
+	b.WriteString("}\n\n")
+
+	file.WriteString(b.String())
+}
+
+// defineOverrideAdapter emits a Go function with the generated types of exported function decl,
+// which calls the Go function fqName in Exports with the types specified with [OverrideType].
+// It returns the name of the emitted function.
+func (g *generator) defineOverrideAdapter(decl *funcDecl, fqName string) string {
+	file := decl.goFunc.file
+	f := decl.goFunc
+	f.name = file.DeclareName("exports" + strings.ReplaceAll(strings.TrimPrefix(fqName, file.GetName("Exports")+"."), ".", ""))
+	scope := declareParams(file, f)
+
+	var b strings.Builder
+	stringio.Write(&b, "// ", f.name, " calls [", fqName, "], converting values of the types specified with type overrides.\n")
+	stringio.Write(&b, "func ", f.name, g.functionSignature(file, f), " {\n")
+
+	// Lift params
+	args := make([]string, len(f.params))
+	for i, p := range f.params {
+		args[i] = p.name
+		if o, r := g.typeOverride(p.typ); o != nil {
+			args[i] = g.liftOverride(file, o, r, p.name)
+		}
+	}
+
+	// Call caller-defined function
+	results := make([]string, len(f.results))
+	for i, r := range f.results {
+		results[i] = scope.DeclareName(r.name + "_")
+	}
+	if len(results) > 0 {
+		stringio.Write(&b, strings.Join(results, ", "), " := ")
+	}
+	stringio.Write(&b, fqName, "(", strings.Join(args, ", "), ")\n")
+
+	// Lower results
+	for i, r := range f.results {
+		if o, rec := g.typeOverride(r.typ); o != nil {
+			b.WriteString(g.lowerOverride(file, o, rec, r.name, results[i]))
+		} else {
+			stringio.Write(&b, r.name, " = ", results[i], "\n")
+		}
+	}
+	b.WriteString("return\n")
+	b.WriteString("}\n\n")
+
+	file.WriteString(b.String())
+	return f.name
+}
+
+// declareParams returns a new scope in file with the names of the params and results of f.
+func declareParams(file *gen.File, f function) gen.Scope {
+	scope := gen.NewScope(file)
+	for _, p := range f.params {
+		scope.DeclareName(p.name)
+	}
+	for _, r := range f.results {
+		scope.DeclareName(r.name)
+	}
+	return scope
+}
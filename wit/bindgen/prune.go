@@ -0,0 +1,90 @@
+package bindgen
+
+import (
+	"github.com/bytecodealliance/wasm-tools-go/wit"
+)
+
+// detectReachableTypes finds the WIT types reachable from the functions imported or
+// exported by the selected world(s), including types referenced transitively by other types.
+// Functions in imported interfaces are reachable, except for methods, which are only
+// reachable if their resource type is reachable. All functions and resource types in
+// exported interfaces are reachable, as the caller must implement them.
+func (g *generator) detectReachableTypes() {
+	g.reachable = make(map[*wit.TypeDef]bool)
+	var resources []*wit.TypeDef
+
+	reachType := func(t wit.Type) {
+		td, ok := t.(*wit.TypeDef)
+		if !ok || g.reachable[td] {
+			return
+		}
+		for _, td := range wit.DependencyGraph(td).TypeDefs() {
+			if g.reachable[td] {
+				continue
+			}
+			g.reachable[td] = true
+			if _, ok := td.Kind.(*wit.Resource); ok {
+				resources = append(resources, td)
+			}
+		}
+	}
+
+	reachFunction := func(f *wit.Function) {
+		for _, p := range f.Params {
+			reachType(p.Type)
+		}
+		for _, r := range f.Results {
+			reachType(r.Type)
+		}
+	}
+
+	for _, w := range g.worlds() {
+		w.Imports.All()(func(_ string, v wit.WorldItem) bool {
+			switch v := v.(type) {
+			case *wit.InterfaceRef:
+				v.Interface.Functions.All()(func(_ string, f *wit.Function) bool {
+					if !f.IsMethod() {
+						reachFunction(f)
+					}
+					return true
+				})
+			case *wit.Function:
+				reachFunction(v)
+			}
+			return true
+		})
+		w.Exports.All()(func(_ string, v wit.WorldItem) bool {
+			switch v := v.(type) {
+			case *wit.InterfaceRef:
+				v.Interface.TypeDefs.All()(func(_ string, td *wit.TypeDef) bool {
+					if _, ok := td.Kind.(*wit.Resource); ok {
+						reachType(td)
+					}
+					return true
+				})
+				v.Interface.Functions.All()(func(_ string, f *wit.Function) bool {
+					reachFunction(f)
+					return true
+				})
+			case *wit.Function:
+				reachFunction(v)
+			}
+			return true
+		})
+	}
+
+	// Methods of reachable resources are reachable,
+	// which may in turn reach other resources.
+	for len(resources) > 0 {
+		t := resources[0]
+		resources = resources[1:]
+		for _, f := range t.Methods() {
+			reachFunction(f)
+		}
+	}
+}
+
+// pruned returns true if [wit.TypeDef] t is omitted from generated code.
+func (g *generator) pruned(t *wit.TypeDef) bool {
+	return g.reachable != nil && !g.reachable[t]
+}
@@ -0,0 +1,139 @@
+package bindgen
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/bytecodealliance/wasm-tools-go/internal/stringio"
+	"github.com/bytecodealliance/wasm-tools-go/wit"
+)
+
+// renamedType is a type declaration renamed with [Rename], with its default Go name.
+type renamedType struct {
+	decl        *typeDecl
+	t           *wit.TypeDef
+	defaultName string
+}
+
+// ownerIdent returns the qualified WIT identifier of owner, such as wasi:io/streams@0.2.0,
+// or false if owner is an anonymous interface.
+func ownerIdent(owner wit.TypeOwner) (wit.Ident, bool) {
+	var id wit.Ident
+	switch owner := owner.(type) {
+	case *wit.Interface:
+		if owner.Name == nil || owner.Package == nil {
+			return id, false
+		}
+		id = owner.Package.Name
+		id.Extension = *owner.Name
+	case *wit.World:
+		id = owner.Package.Name
+		id.Extension = owner.Name
+	default:
+		return id, false
+	}
+	return id, true
+}
+
+// renamed returns the Go name for the WIT type or function v named name in owner
+// specified with [Rename], if any. A versioned identifier takes precedence.
+// Otherwise, it returns the name specified with a "wit-bindgen-go:name" directive, if any.
+func (g *generator) renamed(owner wit.TypeOwner, name string, v wit.Node) (string, bool) {
+	if goName, ok := lookupIdent(g.opts.renames, owner, name); ok {
+		return goName, true
+	}
+	return g.directiveName(v)
+}
+
+// lookupIdent returns the value in m for the WIT type or function name in owner,
+// keyed by qualified identifiers such as wasi:io/streams@0.2.0#input-stream,
+// or wasi:io/streams#input-stream to match any version. A versioned identifier takes precedence.
+func lookupIdent[V any](m map[string]V, owner wit.TypeOwner, name string) (V, bool) {
+	var zero V
+	if len(m) == 0 {
+		return zero, false
+	}
+	id, ok := ownerIdent(owner)
+	if !ok {
+		return zero, false
+	}
+	if v, ok := m[id.String()+"#"+name]; ok {
+		return v, true
+	}
+	id.Version = nil
+	v, ok := m[id.String()+"#"+name]
+	return v, ok
+}
+
+// checkRenames returns an error if any identifier specified with [Rename]
+// does not identify a type or function in g.res.
+func (g *generator) checkRenames() error {
+	idents := make([]string, 0, len(g.opts.renames))
+	for ident := range g.opts.renames {
+		idents = append(idents, ident)
+	}
+	slices.Sort(idents)
+	for _, ident := range idents {
+		owner, name, _ := strings.Cut(ident, "#")
+		var t wit.TypeOwner
+		var names []string
+		if i := g.res.FindInterface(owner); i != nil && strings.Contains(owner, "/") {
+			t = i
+			i.TypeDefs.All()(func(name string, _ *wit.TypeDef) bool {
+				names = append(names, name)
+				return true
+			})
+			i.Functions.All()(func(name string, _ *wit.Function) bool {
+				names = append(names, name)
+				return true
+			})
+		} else if w := g.res.FindWorld(owner); w != nil && strings.Contains(owner, "/") {
+			t = w
+			w.AllImportsAndExports()(func(name string, item wit.WorldItem) bool {
+				switch item.(type) {
+				case *wit.TypeDef, *wit.Function:
+					names = append(names, name)
+				}
+				return true
+			})
+		}
+		if slices.Contains(names, name) {
+			continue
+		}
+		msg := "cannot rename to " + g.opts.renames[ident] + ": WIT type or function not found"
+		if t == nil {
+			err := newIdentError(ident, msg)
+			err.Suggestion = "specify a type or function in an interface or world, such as wasi:io/streams#input-stream"
+			return err
+		}
+		err := newGenerateError(t, name, wit.Position{}, msg)
+		if s := suggestName(name, names); s != "" {
+			err.Suggestion = "did you mean " + s + "?"
+		}
+		return err
+	}
+	return nil
+}
+
+// addRenameAlias records that type t was declared as decl with a name specified
+// with [Rename] instead of defaultName.
+func (g *generator) addRenameAlias(decl *typeDecl, t *wit.TypeDef, defaultName string) {
+	g.mu.Lock()
+	g.renamedTypes = append(g.renamedTypes, renamedType{decl, t, defaultName})
+	g.mu.Unlock()
+}
+
+// defineRenameAliases declares the default Go name of each renamed type as an alias
+// of the renamed type, unless the default name was declared by another type or function.
+func (g *generator) defineRenameAliases() {
+	for _, r := range g.renamedTypes {
+		file := r.decl.file
+		if !g.defined[wit.Imported][r.t] && !g.defined[wit.Exported][r.t] || file.HasName(r.defaultName) {
+			continue
+		}
+		name := file.DeclareName(r.defaultName)
+		stringio.Write(file, "\n// ", name, " is an alias for [", r.decl.name, "], the Go name of ", r.t.WITKind(),
+			" \"", g.moduleNames[r.t.Owner], "#", r.t.TypeName(), "\" before it was renamed.\n")
+		stringio.Write(file, "type ", name, " = ", r.decl.name, "\n")
+	}
+}
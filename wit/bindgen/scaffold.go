@@ -0,0 +1,283 @@
+package bindgen
+
+import (
+	"path"
+	"strings"
+
+	"github.com/bytecodealliance/wasm-tools-go/internal/go/gen"
+	"github.com/bytecodealliance/wasm-tools-go/internal/stringio"
+	"github.com/bytecodealliance/wasm-tools-go/wit"
+)
+
+// maxScaffoldWrite is the maximum number of bytes written by a scaffold with a single
+// blocking-write-and-flush, which hosts may limit to 4096 bytes.
+const maxScaffoldWrite = "4096"
+
+// defineScaffolds generates a scaffold main package for each generated world that exports
+// "wasi:cli/run", as a starting point for a command component.
+func (g *generator) defineScaffolds() error {
+	for _, w := range g.worlds() {
+		run := worldInterface(w, wit.Exported, "wasi:cli", "run")
+		if run == nil {
+			continue
+		}
+		err := g.defineScaffold(w, run)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// worldInterface returns the interface name in WIT package pkgName, e.g. "wasi:cli",
+// imported or exported by world w, or nil if w does not import or export it.
+func worldInterface(w *wit.World, dir wit.Direction, pkgName, name string) *wit.Interface {
+	items := &w.Imports
+	if dir == wit.Exported {
+		items = &w.Exports
+	}
+	var i *wit.Interface
+	items.All()(func(_ string, v wit.WorldItem) bool {
+		ref, ok := v.(*wit.InterfaceRef)
+		if ok && ref.Interface.Name != nil && *ref.Interface.Name == name && unversionedName(ref.Interface.Package.Name) == pkgName {
+			i = ref.Interface
+			return false
+		}
+		return true
+	})
+	return i
+}
+
+// defineScaffold generates Go package main for world w, which exports interface run.
+// Its init function sets the exported run function to call function Run with the
+// command-line arguments, and the standard streams of "wasi:cli" imported by w are
+// adapted to io.Reader and io.Writer values. The scaffold file is meant to be edited,
+// so it is not marked as generated, and is not overwritten if it exists.
+func (g *generator) defineScaffold(w *wit.World, run *wit.Interface) error {
+	f := run.Functions.Get("run")
+	decl := g.functions[wit.Exported][f]
+	if decl == nil || len(f.Params) != 0 || len(f.Results) != 1 || !isBoolResult(f.Results[0].Type) {
+		return newGenerateError(run, "run", run.Pos, "cannot generate scaffold for exported function with unexpected signature")
+	}
+
+	worldPkg := g.packageFor(w)
+	pkg := gen.NewPackage(worldPkg.Path + "/cmd/" + path.Base(worldPkg.Path) + "#main")
+	g.packages[pkg.Path] = pkg
+	file := pkg.File("main.go")
+	file.Scaffold = true
+
+	var b strings.Builder
+	stringio.Write(&b, "Command ", path.Base(worldPkg.Path), " is a component that targets ", w.WITKind(), " \"", g.moduleNames[w], "\".\n\n")
+	stringio.Write(&b, "This file was generated by ", g.opts.generatedBy, " as a starting point. It is not overwritten\n")
+	b.WriteString("when bindings are regenerated, so edit it to implement the component.\n")
+	file.PackageDocs = b.String()
+
+	fmt := file.Import("fmt")
+	io := file.Import("io")
+	exports := file.RelativeName(decl.goFunc.file.Package, g.exportsFileFor(run).GetName("Exports"))
+	runFunc := file.DeclareName("Run")
+	argsFunc := file.DeclareName("Args")
+	stdin := file.DeclareName("Stdin")
+	stdout := file.DeclareName("Stdout")
+	stderr := file.DeclareName("Stderr")
+
+	b.Reset()
+	b.WriteString("func init() {\n")
+	stringio.Write(&b, exports, ".", decl.goFunc.name, " = func", g.functionSignature(file, decl.goFunc), " {\n")
+	stringio.Write(&b, "if err := ", runFunc, "(", argsFunc, "()); err != nil {\n")
+	stringio.Write(&b, fmt, ".Fprintln(", stderr, ", err)\n")
+	b.WriteString("return true\n")
+	b.WriteString("}\n")
+	b.WriteString("return false\n")
+	b.WriteString("}\n")
+	b.WriteString("}\n\n")
+
+	stringio.Write(&b, "// ", runFunc, " is called when the component is run, with the command-line arguments.\n")
+	stringio.Write(&b, "// If it returns an error, the error is written to [", stderr, "], and the component fails.\n")
+	stringio.Write(&b, "func ", runFunc, "(args []string) error {\n")
+	stringio.Write(&b, fmt, ".Fprintf(", stdout, ", \"Hello from %s!\\n\", \"", g.moduleNames[w], "\")\n")
+	b.WriteString("return nil\n")
+	b.WriteString("}\n\n")
+
+	main := file.DeclareName("main")
+	stringio.Write(&b, "// ", main, " is required by Go, but not called by the host, which calls the exported run function.\n")
+	stringio.Write(&b, "func ", main, "() {}\n\n")
+
+	// Command-line arguments
+	stringio.Write(&b, "// ", argsFunc, " returns the command-line arguments, starting with the program name, if any.\n")
+	stringio.Write(&b, "func ", argsFunc, "() []string {\n")
+	if get := g.scaffoldFunction(w, "environment", "get-arguments"); get != nil {
+		stringio.Write(&b, "return ", g.scaffoldCall(file, get, ""), ".Slice()\n")
+	} else {
+		b.WriteString("return nil\n")
+	}
+	b.WriteString("}\n\n")
+
+	// Standard streams
+	var outputType, inputType wit.Type
+	outputStream := file.DeclareName("outputStream")
+	inputStream := file.DeclareName("inputStream")
+	stringio.Write(&b, "// ", stdin, " reads from the stream returned by \"wasi:cli/stdin\".\n")
+	if get := g.scaffoldFunction(w, "stdin", "get-stdin"); get != nil && g.scaffoldMethod(get.f.Results[0].Type, "blocking-read") != nil {
+		inputType = get.f.Results[0].Type
+		stringio.Write(&b, "var ", stdin, " ", io, ".Reader = &", inputStream, "{get: ", g.scaffoldFuncName(file, get), "}\n\n")
+	} else {
+		stringio.Write(&b, "var ", stdin, " ", io, ".Reader = ", io, ".MultiReader()\n\n")
+	}
+	for _, stream := range [][2]string{{"stdout", stdout}, {"stderr", stderr}} {
+		name, goName := stream[0], stream[1]
+		stringio.Write(&b, "// ", goName, " writes to the stream returned by \"wasi:cli/", name, "\".\n")
+		if get := g.scaffoldFunction(w, name, "get-"+name); get != nil && g.scaffoldMethod(get.f.Results[0].Type, "blocking-write-and-flush") != nil {
+			outputType = get.f.Results[0].Type
+			stringio.Write(&b, "var ", goName, " ", io, ".Writer = &", outputStream, "{get: ", g.scaffoldFuncName(file, get), "}\n\n")
+		} else {
+			stringio.Write(&b, "var ", goName, " ", io, ".Writer = ", io, ".Discard\n\n")
+		}
+	}
+	if outputType != nil {
+		g.scaffoldOutputStream(&b, file, outputStream, outputType)
+	}
+	if inputType != nil {
+		g.scaffoldInputStream(&b, file, inputStream, inputType)
+	}
+
+	_, err := file.WriteString(b.String())
+	return err
+}
+
+// scaffoldOutputStream writes type goName to b, an io.Writer for
+// a standard output stream of imported resource type typ.
+func (g *generator) scaffoldOutputStream(b *strings.Builder, file *gen.File, goName string, typ wit.Type) {
+	write := g.scaffoldMethod(typ, "blocking-write-and-flush")
+	errors := file.Import("errors")
+	io := file.Import("io")
+	stringio.Write(b, "// ", goName, " is a blocking [", io, ".Writer] for a standard output stream,\n")
+	b.WriteString("// which is retrieved from the host when first written.\n")
+	stringio.Write(b, "type ", goName, " struct {\n")
+	stringio.Write(b, "get func() ", g.typeRep(file, wit.Imported, scaffoldResource(typ)), "\n")
+	stringio.Write(b, "stream ", g.typeRep(file, wit.Imported, scaffoldResource(typ)), "\n")
+	b.WriteString("}\n\n")
+	stringio.Write(b, "// Write implements [", io, ".Writer], blocking until p is written and flushed.\n")
+	stringio.Write(b, "func (w *", goName, ") Write(p []byte) (n int, err error) {\n")
+	b.WriteString("if w.stream == 0 {\n")
+	b.WriteString("w.stream = w.get()\n")
+	b.WriteString("}\n")
+	b.WriteString("for len(p) > 0 {\n")
+	stringio.Write(b, "chunk := p[:min(len(p), ", maxScaffoldWrite, ")]\n")
+	stringio.Write(b, "if result := ", g.scaffoldCall(file, write, "w.stream", file.Import(g.opts.cmPackage)+".ToList(chunk)"), "; result.IsErr() {\n")
+	stringio.Write(b, "return n, ", errors, ".New(\"write failed\")\n")
+	b.WriteString("}\n")
+	b.WriteString("n += len(chunk)\n")
+	b.WriteString("p = p[len(chunk):]\n")
+	b.WriteString("}\n")
+	b.WriteString("return n, nil\n")
+	b.WriteString("}\n\n")
+}
+
+// scaffoldInputStream writes type goName to b, an io.Reader for
+// the standard input stream of imported resource type typ.
+func (g *generator) scaffoldInputStream(b *strings.Builder, file *gen.File, goName string, typ wit.Type) {
+	read := g.scaffoldMethod(typ, "blocking-read")
+	io := file.Import("io")
+	stringio.Write(b, "// ", goName, " is a blocking [", io, ".Reader] for the standard input stream,\n")
+	b.WriteString("// which is retrieved from the host when first read.\n")
+	stringio.Write(b, "type ", goName, " struct {\n")
+	stringio.Write(b, "get func() ", g.typeRep(file, wit.Imported, scaffoldResource(typ)), "\n")
+	stringio.Write(b, "stream ", g.typeRep(file, wit.Imported, scaffoldResource(typ)), "\n")
+	b.WriteString("}\n\n")
+	stringio.Write(b, "// Read implements [", io, ".Reader], blocking until at least one byte is read.\n")
+	stringio.Write(b, "// It returns [", io, ".EOF] when the stream is closed or fails.\n")
+	stringio.Write(b, "func (r *", goName, ") Read(p []byte) (int, error) {\n")
+	b.WriteString("if r.stream == 0 {\n")
+	b.WriteString("r.stream = r.get()\n")
+	b.WriteString("}\n")
+	b.WriteString("if len(p) == 0 {\n")
+	b.WriteString("return 0, nil\n")
+	b.WriteString("}\n")
+	b.WriteString("for {\n")
+	stringio.Write(b, "result := ", g.scaffoldCall(file, read, "r.stream", "uint64(len(p))"), "\n")
+	b.WriteString("if result.IsErr() {\n")
+	stringio.Write(b, "return 0, ", io, ".EOF\n")
+	b.WriteString("}\n")
+	b.WriteString("if data := result.OK().Slice(); len(data) > 0 {\n")
+	b.WriteString("return copy(p, data), nil\n")
+	b.WriteString("}\n")
+	b.WriteString("}\n")
+	b.WriteString("}\n\n")
+}
+
+// scaffoldFunction returns the declaration of function name in interface "wasi:cli/<iface>"
+// imported by world w, or nil if w does not import it.
+func (g *generator) scaffoldFunction(w *wit.World, iface, name string) *funcDecl {
+	i := worldInterface(w, wit.Imported, "wasi:cli", iface)
+	if i == nil {
+		return nil
+	}
+	f := i.Functions.Get(name)
+	if f == nil || len(f.Results) != 1 {
+		return nil
+	}
+	return g.functions[wit.Imported][f]
+}
+
+// scaffoldMethod returns the declaration of imported method name of resource type t,
+// or an owned handle to it.
+func (g *generator) scaffoldMethod(t wit.Type, name string) *funcDecl {
+	r := scaffoldResource(t)
+	if r == nil || r.Name == nil {
+		return nil
+	}
+	i, ok := r.Owner.(*wit.Interface)
+	if !ok {
+		return nil
+	}
+	f := i.Functions.Get("[method]" + *r.Name + "." + name)
+	if f == nil {
+		return nil
+	}
+	return g.functions[wit.Imported][f]
+}
+
+// scaffoldResource returns the resource type of t, which may be an owned handle, or nil.
+func scaffoldResource(t wit.Type) *wit.TypeDef {
+	td, ok := t.(*wit.TypeDef)
+	if !ok {
+		return nil
+	}
+	r := td.Root()
+	if own, ok := r.Kind.(*wit.Own); ok {
+		r = own.Type.Root()
+	}
+	if _, ok := r.Kind.(*wit.Resource); !ok {
+		return nil
+	}
+	return r
+}
+
+// scaffoldCall returns a Go expression that calls the imported function decl in file with args.
+// If decl is a method, it is called on recv.
+func (g *generator) scaffoldCall(file *gen.File, decl *funcDecl, recv string, args ...string) string {
+	if decl.goFunc.isMethod() {
+		return recv + "." + decl.goFunc.name + "(" + strings.Join(args, ", ") + ")"
+	}
+	if recv != "" {
+		args = append([]string{recv}, args...)
+	}
+	return g.scaffoldFuncName(file, decl) + "(" + strings.Join(args, ", ") + ")"
+}
+
+// scaffoldFuncName returns the qualified Go name of the imported function decl in file.
+func (g *generator) scaffoldFuncName(file *gen.File, decl *funcDecl) string {
+	return file.RelativeName(decl.goFunc.file.Package, decl.goFunc.name)
+}
+
+// isBoolResult returns true if t is a WIT result type without OK or error types,
+// represented in Go as a cm.BoolResult.
+func isBoolResult(t wit.Type) bool {
+	td, ok := t.(*wit.TypeDef)
+	if !ok {
+		return false
+	}
+	r, ok := td.Root().Kind.(*wit.Result)
+	return ok && r.OK == nil && r.Err == nil
+}
@@ -0,0 +1,91 @@
+package bindgen
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/bytecodealliance/wasm-tools-go/internal/stringio"
+	"github.com/bytecodealliance/wasm-tools-go/wit"
+)
+
+// defineResourceTable defines a resource table for exported resource t, with an interface
+// implemented by Go values that represent it, and a function that adds a Go value to the table
+// and returns a new handle with resource-new function newFunc. It sets the exported methods
+// and destructor dtorFunc of t in Exports to call the Go value for the rep passed by the caller.
+func (g *generator) defineResourceTable(decl *typeDecl, t *wit.TypeDef, newFunc, dtorFunc *wit.Function) error {
+	newDecl := g.funcDecl(wit.Imported, newFunc)
+	if newDecl == nil {
+		return nil
+	}
+	file := decl.file
+	cm := file.Import(g.opts.cmPackage)
+	name := "resource \"" + g.moduleNames[t.Owner] + "#" + *t.Name + "\""
+	impl := file.DeclareName(decl.name + "Impl")
+	table := file.DeclareName(decl.name + "Table")
+	constructor := file.DeclareName("New" + decl.name)
+	exports := file.GetName("Exports") + "." + g.exportScopes[t.Owner].GetName(g.goName(*t.Name, true))
+
+	var b bytes.Buffer
+
+	// Emit interface
+	stringio.Write(&b, "// ", impl, " is implemented by Go values that represent exported ", name, ".\n")
+	stringio.Write(&b, "// Calls to the methods of [", decl.name, "] are dispatched to the ", impl, " in [", table, "]\n")
+	stringio.Write(&b, "// for the rep passed by the caller. If an ", impl, " has a Destructor method,\n")
+	stringio.Write(&b, "// it is called after the resource is dropped and removed from [", table, "].\n")
+	stringio.Write(&b, "type ", impl, " interface {\n")
+	methods := t.Methods()
+	for i, f := range methods {
+		mdecl := g.funcDecl(wit.Exported, f)
+		if mdecl == nil {
+			continue
+		}
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		m := mdecl.goFunc
+		m.params = m.params[1:] // self
+		b.WriteString(g.functionDocs(wit.Exported, mdecl.owner, f, m.name))
+		stringio.Write(&b, m.name, g.overrideSignature(file, m), "\n")
+	}
+	b.WriteString("}\n\n")
+
+	// Emit table and constructor function
+	stringio.Write(&b, "// ", table, " maps the rep of each instance of exported ", name, " to its ", impl, ".\n")
+	stringio.Write(&b, "var ", table, " ", cm, ".ResourceTable[", impl, "]\n\n")
+	stringio.Write(&b, "// ", constructor, " adds impl to [", table, "] and returns a new owned handle to ", name, "\n")
+	stringio.Write(&b, "// that represents it, for example, in the result of the exported constructor.\n")
+	stringio.Write(&b, "func ", constructor, "(impl ", impl, ") ", decl.name, " {\n")
+	stringio.Write(&b, "return ", newDecl.goFunc.name, "(", table, ".Add(impl))\n")
+	b.WriteString("}\n\n")
+
+	// Dispatch methods and destructor
+	b.WriteString("func init() {\n")
+	if ddecl := g.funcDecl(wit.Exported, dtorFunc); ddecl != nil {
+		self := ddecl.goFunc.params[0].name
+		stringio.Write(&b, exports, ".", ddecl.goFunc.name, " = func", g.functionSignature(file, ddecl.goFunc), " {\n")
+		stringio.Write(&b, "if impl, ok := ", table, ".Remove(", self, "); ok {\n")
+		b.WriteString("if d, ok := impl.(interface{ Destructor() }); ok {\nd.Destructor()\n}\n")
+		b.WriteString("}\n}\n")
+	}
+	for _, f := range methods {
+		mdecl := g.funcDecl(wit.Exported, f)
+		if mdecl == nil {
+			continue
+		}
+		m := mdecl.goFunc
+		args := make([]string, len(m.params)-1)
+		for i, p := range m.params[1:] {
+			args[i] = p.name
+		}
+		stringio.Write(&b, exports, ".", m.name, " = func", g.overrideSignature(file, m), " {\n")
+		if len(m.results) > 0 {
+			b.WriteString("return ")
+		}
+		stringio.Write(&b, table, ".Must(", m.params[0].name, ").", m.name, "(", strings.Join(args, ", "), ")\n")
+		b.WriteString("}\n")
+	}
+	b.WriteString("}\n\n")
+
+	_, err := file.Write(b.Bytes())
+	return err
+}
@@ -0,0 +1,161 @@
+package bindgen
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/bytecodealliance/wasm-tools-go/wit"
+)
+
+// StructTagConfig configures the struct tags emitted on Go struct fields generated for
+// WIT record fields. It can be decoded from JSON, for example:
+//
+//	{
+//		"default": {"json": "kebab", "yaml": "snake,omitempty"},
+//		"fields": {
+//			"wasi:filesystem/types#descriptor-stat.size": {"json": "size_bytes", "yaml": "-"}
+//		}
+//	}
+type StructTagConfig struct {
+	// Default maps a struct tag key, e.g. "json" or "yaml", to a naming policy applied to
+	// every record field. The policy is one of "kebab" (the WIT field name, e.g. "link-count"),
+	// "snake" (link_count), "camel" (linkCount), or "pascal" (LinkCount), optionally followed
+	// by tag options, e.g. "snake,omitempty".
+	Default map[string]string `json:"default,omitempty"`
+
+	// Fields maps a WIT record field to struct tag values for that field, indexed by key,
+	// which take precedence over Default. Fields are identified by the qualified name of
+	// their record and the field name, e.g. "wasi:filesystem/types@0.2.0#descriptor-stat.size".
+	// The version may be omitted, e.g. "wasi:filesystem/types#descriptor-stat.size".
+	// An empty value omits the key from the struct tag of the field.
+	Fields map[string]map[string]string `json:"fields,omitempty"`
+}
+
+// validate returns an error if c contains an invalid key, policy, or value.
+func (c *StructTagConfig) validate() error {
+	for key, policy := range c.Default {
+		err := validateTagKey(key)
+		if err != nil {
+			return err
+		}
+		policy, _, _ = strings.Cut(policy, ",")
+		if _, ok := tagNamers[policy]; !ok {
+			return fmt.Errorf("struct tag %q: unknown naming policy %q", key, policy)
+		}
+	}
+	for field, tags := range c.Fields {
+		if !strings.Contains(field, "#") || !strings.Contains(field, ".") {
+			return fmt.Errorf("struct tag field %q: expected qualified record field name, e.g. wasi:filesystem/types#descriptor-stat.size", field)
+		}
+		for key, value := range tags {
+			err := validateTagKey(key)
+			if err != nil {
+				return err
+			}
+			if strings.ContainsRune(value, '`') {
+				return fmt.Errorf("struct tag %q for field %q: value contains a backquote", key, field)
+			}
+		}
+	}
+	return nil
+}
+
+func validateTagKey(key string) error {
+	if key == "" || strings.ContainsAny(key, " \t\n:\"`") {
+		return fmt.Errorf("invalid struct tag key %q", key)
+	}
+	return nil
+}
+
+// tagNamers map naming policies to functions that convert a WIT field name into a struct tag name.
+var tagNamers = map[string]func(string) string{
+	"kebab": func(name string) string { return name },
+	"snake": func(name string) string { return strings.ReplaceAll(name, "-", "_") },
+	"camel": func(name string) string { return joinWords(name, false) },
+	"pascal": func(name string) string {
+		return joinWords(name, true)
+	},
+}
+
+// joinWords joins the words of kebab-case WIT name into a single camel-case word,
+// with the first letter in upper case if upper is true.
+func joinWords(name string, upper bool) string {
+	var b strings.Builder
+	for i, w := range strings.Split(name, "-") {
+		if w == "" {
+			continue
+		}
+		if i > 0 || upper {
+			w = strings.ToUpper(w[:1]) + w[1:]
+		}
+		b.WriteString(w)
+	}
+	return b.String()
+}
+
+// fieldTags returns the struct tag, including a leading space, for the Go struct field
+// generated for field f of record r, or an empty string if none.
+func (g *generator) fieldTags(r *wit.Record, f *wit.Field) string {
+	tags := make(map[string]string)
+	if g.opts.generateJSON {
+		tags["json"] = f.Name
+	}
+	if g.opts.structTags != nil {
+		for key, policy := range g.opts.structTags.Default {
+			policy, opts, _ := strings.Cut(policy, ",")
+			tags[key] = tagNamers[policy](f.Name)
+			if opts != "" {
+				tags[key] += "," + opts
+			}
+		}
+		if len(g.opts.structTags.Fields) > 0 {
+			for _, name := range g.qualifiedFieldNames(r, f) {
+				for key, value := range g.opts.structTags.Fields[name] {
+					tags[key] = value
+				}
+			}
+		}
+	}
+
+	var keys []string
+	for key, value := range tags {
+		if value != "" {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return ""
+	}
+	slices.Sort(keys)
+	var b strings.Builder
+	b.WriteString(" `")
+	for i, key := range keys {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(key)
+		b.WriteByte(':')
+		b.WriteString(strconv.Quote(tags[key]))
+	}
+	b.WriteByte('`')
+	return b.String()
+}
+
+// qualifiedFieldNames returns the qualified names of field f of record r, without and
+// with the package version, in order of increasing precedence.
+func (g *generator) qualifiedFieldNames(r *wit.Record, f *wit.Field) []string {
+	for _, t := range g.res.TypeDefs {
+		if t.Kind != r || t.Name == nil {
+			continue
+		}
+		owner := g.moduleNames[t.Owner]
+		name := owner + "#" + *t.Name + "." + f.Name
+		if base, _, ok := strings.Cut(owner, "@"); ok {
+			return []string{base + "#" + *t.Name + "." + f.Name, name}
+		}
+		return []string{name}
+	}
+	return nil
+}
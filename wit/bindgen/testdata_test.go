@@ -11,6 +11,7 @@ import (
 	"os/exec"
 	"path"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"testing"
@@ -23,7 +24,10 @@ import (
 	"github.com/bytecodealliance/wasm-tools-go/wit"
 )
 
-var writeGoFiles = flag.Bool("write", false, "write generated Go files")
+var (
+	writeGoFiles = flag.Bool("write", false, "write generated Go files")
+	allOptions   = flag.Bool("all-options", false, "validate every testdata fixture with every option set")
+)
 
 const (
 	testdataPath  = "../../testdata"
@@ -79,7 +83,7 @@ var canGo = sync.OnceValue[bool](func() bool {
 })
 
 // validateGeneratedGo loads the Go package(s) generated
-func validateGeneratedGo(t *testing.T, res *wit.Resolve, origin string) {
+func validateGeneratedGo(t *testing.T, res *wit.Resolve, origin string, opts ...Option) {
 	if !canGo() {
 		t.Log("skipping test: can't run go (TinyGo without fork?)")
 		return
@@ -104,11 +108,12 @@ func validateGeneratedGo(t *testing.T, res *wit.Resolve, origin string) {
 		return
 	}
 
-	pkgs, err := Go(res,
+	opts = append([]Option{
 		GeneratedBy("test"),
 		PackageRoot(pkgPath),
 		Versioned(true),
-	)
+	}, opts...)
+	pkgs, err := Go(res, opts...)
 	if err != nil {
 		t.Error(err)
 		return
@@ -210,19 +215,226 @@ func validateGeneratedGo(t *testing.T, res *wit.Resolve, origin string) {
 	}
 }
 
+// hasResources reports whether the testdata at path has resources with methods.
+func hasResources(path string) bool {
+	return strings.Contains(path, "resource") || strings.Contains(path, "/wasi/")
+}
+
+// representativeTestdata are the testdata fixtures validated with each option set
+// other than the default. Every fixture is validated with every option set
+// if the -all-options flag is set.
+var representativeTestdata = []string{
+	"codegen/flags.wit.json",
+	"codegen/lists.wit.json",
+	"codegen/option-result.wit.json",
+	"codegen/records.wit.json",
+	"codegen/resources.wit.json",
+	"codegen/resources-in-aggregates.wit.json",
+	"codegen/values.wit.json",
+	"codegen/variants.wit.json",
+	"wasi/http-minimal.wit.json",
+}
+
+func isRepresentative(path string) bool {
+	path = filepath.ToSlash(strings.TrimPrefix(path, testdataPath+"/"))
+	return slices.Contains(representativeTestdata, path)
+}
+
+// testdataOptions are the option sets testdata fixtures are generated and validated with.
+// Every fixture is validated with the default options.
+var testdataOptions = []struct {
+	name   string
+	filter func(path string) bool
+	opts   []Option
+}{
+	{"default", nil, nil},
+	{"json", nil, []Option{JSON(true)}},
+	{"free-functions", hasResources, []Option{FreeFunctions(true)}},
+	{"resource-finalizers", hasResources, []Option{ResourceFinalizers(true)}},
+	{"managed-resources", hasResources, []Option{ManagedResources(true), FreeFunctions(true)}},
+	{"dynamic-values", nil, []Option{DynamicValues(true)}},
+	{"invoker", nil, []Option{Invoker(true)}},
+	{"stubs", nil, []Option{BuildTags("wasip2"), Stubs(true)}},
+	{"mock-imports", nil, []Option{MockImports(true)}},
+	{"debug", nil, []Option{Debug(true), MockImports(true)}},
+	{"constructors", nil, []Option{Constructors(true)}},
+	{"null-accessors", nil, []Option{NullAccessors(true)}},
+	{"binary-marshal", nil, []Option{BinaryMarshal(true)}},
+	{"naming-v2", nil, []Option{NamingScheme(NamingV2)}},
+}
+
 func TestGenerateTestdata(t *testing.T) {
 	if testing.Short() {
 		// t.Skip is not available in TinyGo, requires runtime.Goexit()
 		return
 	}
-	err := loadTestdata(func(path string, res *wit.Resolve) error {
-		t.Run(path, func(t *testing.T) {
-			origin := strings.TrimSuffix(strings.TrimPrefix(path, testdataPath), ".wit.json")
-			validateGeneratedGo(t, res, origin)
+	for _, tt := range testdataOptions {
+		t.Run(tt.name, func(t *testing.T) {
+			err := loadTestdata(func(path string, res *wit.Resolve) error {
+				if tt.filter != nil && !tt.filter(path) {
+					return nil
+				}
+				if tt.opts != nil && !*allOptions && !isRepresentative(path) {
+					return nil
+				}
+				t.Run(path, func(t *testing.T) {
+					origin := strings.TrimSuffix(strings.TrimPrefix(path, testdataPath), ".wit.json")
+					validateGeneratedGo(t, res, origin, tt.opts...)
+				})
+				return nil
+			})
+			if err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+// behaviorTests are the handwritten tests run against Go bindings generated for testdata fixtures.
+var behaviorTests = []struct {
+	name  string
+	path  string            // WIT JSON fixture, relative to testdata
+	tests map[string]string // Go test files relative to testdata, indexed by Go package path relative to the package root
+	opts  []Option
+}{
+	{
+		"values",
+		"codegen/values.wit.json",
+		map[string]string{"foo/foo/values": "codegen/values_test.go"},
+		[]Option{
+			JSON(true),
+			DynamicValues(true),
+			BinaryMarshal(true),
+			EqualMethods(true),
+			DeepCopy(true),
+			NullAccessors(true),
+			Exhaustive(true),
+			Constructors(true),
+			TypeInfo(true),
+			LayoutTests(true),
+		},
+	},
+	{
+		"imports",
+		"wasi/cli.wit.json",
+		map[string]string{
+			"wasi/random/random":    "wasi/random_test.go",
+			"wasi/io/streams":       "wasi/streams_test.go",
+			"wasi/filesystem/types": "wasi/types_test.go",
+		},
+		[]Option{
+			BuildTags("wasip2"),
+			Stubs(true),
+			CallHooks(true),
+			MockImports(true),
+			Debug(true),
+			StringCheck(StringCheckStrict),
+			JSON(true),
+			StructTags(&StructTagConfig{
+				Default: map[string]string{"yaml": "snake,omitempty"},
+				Fields: map[string]map[string]string{
+					"wasi:filesystem/types#descriptor-stat.size":             {"json": "size_bytes", "yaml": ""},
+					"wasi:filesystem/types@0.2.0#descriptor-stat.link-count": {"json": "-"},
+				},
+			}),
+		},
+	},
+	{
+		"resource-tables",
+		"codegen/resources.wit.json",
+		map[string]string{"my/resources/resources/exports": "codegen/resources_test.go"},
+		[]Option{BuildTags("wasip2"), Stubs(true), ResourceTables(true)},
+	},
+	{
+		"arena-params",
+		"codegen/many-arguments.wit.json",
+		map[string]string{"many/arguments/manyarg": "codegen/many-arguments_test.go"},
+		[]Option{BuildTags("wasip2"), Stubs(true), ArenaParams(true)},
+	},
+	{
+		"char-check",
+		"codegen/char.wit.json",
+		map[string]string{"foo/foo/chars": "codegen/char_test.go"},
+		[]Option{BuildTags("wasip2"), Stubs(true), CharCheck(true)},
+	},
+	{
+		"enum-check",
+		"codegen/simple-enum.wit.json",
+		map[string]string{"foo/foo/enums": "codegen/simple-enum_test.go"},
+		[]Option{BuildTags("wasip2"), Stubs(true), EnumCheck(true)},
+	},
+}
+
+// TestGenerateTestdataBehavior generates Go bindings for testdata fixtures, then runs the
+// handwritten tests in behaviorTests against the generated packages with go test,
+// to verify the behavior of the generated code.
+func TestGenerateTestdataBehavior(t *testing.T) {
+	if testing.Short() {
+		// t.Skip is not available in TinyGo, requires runtime.Goexit()
+		return
+	}
+	if !canGo() {
+		t.Log("skipping test: can't run go (TinyGo without fork?)")
+		return
+	}
+
+	for _, tt := range behaviorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := wit.LoadJSON(filepath.Join(testdataPath, tt.path))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			dir := filepath.Join(generatedPath, "behavior", tt.name)
+			err = os.MkdirAll(dir, fs.ModePerm)
+			if err != nil {
+				t.Fatal(err)
+			}
+			out, err := relpath.Abs(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			pkgPath, err := gen.PackagePath(out)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			pkgs, err := Go(res, append([]Option{GeneratedBy("test"), PackageRoot(pkgPath)}, tt.opts...)...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, pkg := range pkgs {
+				if !pkg.HasContent() {
+					continue
+				}
+				for _, file := range pkg.Files {
+					writeFile(t, out, pkgPath, file)
+				}
+			}
+
+			args := []string{"test"}
+			for rel, test := range tt.tests {
+				pkgDir := filepath.Join(out, filepath.FromSlash(rel))
+				if _, err := os.Stat(pkgDir); err != nil {
+					t.Fatalf("package %s not generated", rel)
+				}
+				src, err := os.ReadFile(filepath.Join(testdataPath, filepath.FromSlash(test)))
+				if err != nil {
+					t.Fatal(err)
+				}
+				err = os.WriteFile(filepath.Join(pkgDir, path.Base(test)), src, 0o644)
+				if err != nil {
+					t.Fatal(err)
+				}
+				args = append(args, "./"+rel)
+			}
+
+			cmd := exec.Command("go", args...)
+			cmd.Dir = out
+			b, err := cmd.CombinedOutput()
+			if err != nil {
+				t.Errorf("go %s: %v\n%s", strings.Join(args, " "), err, b)
+			}
 		})
-		return nil
-	})
-	if err != nil {
-		t.Error(err)
 	}
 }
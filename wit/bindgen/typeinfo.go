@@ -0,0 +1,183 @@
+package bindgen
+
+import (
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/bytecodealliance/wasm-tools-go/internal/stringio"
+	"github.com/bytecodealliance/wasm-tools-go/wit"
+)
+
+// addTypeInfo records type metadata for the Go type declaration decl of [wit.TypeDef] t.
+// Types without a corresponding cm.Kind, such as future and stream, are skipped.
+func (g *generator) addTypeInfo(decl *typeDecl, t *wit.TypeDef) {
+	if typeInfoKind(t) == "" {
+		return
+	}
+	pkg := decl.file.Package
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, d := range g.typeInfos[pkg] {
+		if d.decl == decl {
+			return
+		}
+	}
+	g.typeInfos[pkg] = append(g.typeInfos[pkg], declaredType{decl, t})
+}
+
+// defineTypeInfos generates the WITTypes table in each Go package with type declarations,
+// and registers it with cm.RegisterTypes when the package is initialized.
+func (g *generator) defineTypeInfos() {
+	for pkg, types := range g.typeInfos {
+		file := pkg.File(path.Base(pkg.Path) + ".types.go")
+		file.GeneratedBy = g.opts.generatedBy
+		cm := file.Import(g.opts.cmPackage)
+		table := file.GetName("WITTypes")
+
+		var b strings.Builder
+		stringio.Write(&b, "// ", table, " describes the WIT type of each Go type in this package, indexed by Go type name,\n")
+		b.WriteString("// for generic middleware such as logging, metrics, or serialization.\n")
+		stringio.Write(&b, "// It is registered with [", cm, ".RegisterTypes] when this package is initialized.\n")
+		stringio.Write(&b, "var ", table, " = map[string]*", cm, ".TypeInfo{\n")
+		for _, d := range types {
+			stringio.Write(&b, strconv.Quote(d.decl.name), ": {Name: ", strconv.Quote(g.moduleNames[d.t.Owner]+"#"+d.t.TypeName()),
+				", Kind: ", cm, ".", typeInfoKind(d.t))
+			if cases := typeInfoCases(d.t); len(cases) > 0 {
+				b.WriteString(", Cases: []string{")
+				for i, c := range cases {
+					if i > 0 {
+						b.WriteString(", ")
+					}
+					b.WriteString(strconv.Quote(c))
+				}
+				b.WriteString("}")
+			}
+			b.WriteString("},\n")
+		}
+		b.WriteString("}\n\n")
+		b.WriteString("func init() {\n")
+		stringio.Write(&b, cm, ".RegisterTypes(", strconv.Quote(pkg.Path), ", ", table, ")\n")
+		b.WriteString("}\n")
+
+		for _, d := range types {
+			b.WriteString(g.typeInfoMethod(cm, table, d))
+		}
+
+		file.WriteString(b.String())
+	}
+}
+
+// typeInfoMethod returns Go source for the WITType method of declared type d,
+// which implements cm.Describable. Resource types, and records with a field
+// that would collide with the method, have no WITType method.
+func (g *generator) typeInfoMethod(cm, table string, d declaredType) string {
+	switch kind := d.t.Kind.(type) {
+	case *wit.Resource, *wit.Own, *wit.Borrow:
+		return ""
+	case *wit.Record:
+		for _, f := range kind.Fields {
+			if g.fieldName(f.Name, true) == "WITType" {
+				return ""
+			}
+		}
+	}
+
+	var b strings.Builder
+	info := table + "[" + strconv.Quote(d.decl.name) + "]"
+	stringio.Write(&b, "\n// WITType implements [", cm, ".Describable], returning the WIT type of [", d.decl.name, "]")
+	switch d.t.Kind.(type) {
+	case *wit.Variant, *wit.Enum, *wit.Option, *wit.Result:
+		b.WriteString("\n// and the case of v.\n")
+	case *wit.Flags:
+		b.WriteString("\n// and the flags set in v.\n")
+	default:
+		b.WriteString(".\n")
+	}
+	stringio.Write(&b, "func (v ", d.decl.name, ") WITType() (*", cm, ".TypeInfo, uint64) {\n")
+	switch kind := d.t.Kind.(type) {
+	case *wit.Variant:
+		if kind.Enum() != nil {
+			stringio.Write(&b, "return ", info, ", uint64(v)\n")
+		} else {
+			stringio.Write(&b, "return ", info, ", uint64(v.Tag())\n")
+		}
+	case *wit.Enum, *wit.Flags:
+		stringio.Write(&b, "return ", info, ", uint64(v)\n")
+	case *wit.Option:
+		b.WriteString("if v.None() {\n")
+		stringio.Write(&b, "return ", info, ", 0\n")
+		b.WriteString("}\n")
+		stringio.Write(&b, "return ", info, ", 1\n")
+	case *wit.Result:
+		if kind.OK == nil && kind.Err == nil {
+			// cm.BoolResult
+			b.WriteString("if v {\n")
+		} else {
+			b.WriteString("if v.IsErr() {\n")
+		}
+		stringio.Write(&b, "return ", info, ", 1\n")
+		b.WriteString("}\n")
+		stringio.Write(&b, "return ", info, ", 0\n")
+	default:
+		stringio.Write(&b, "return ", info, ", 0\n")
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// typeInfoKind returns the name of the cm.Kind constant for [wit.TypeDef] t,
+// or an empty string if none.
+func typeInfoKind(t *wit.TypeDef) string {
+	switch kind := t.Kind.(type) {
+	case *wit.Record:
+		return "KindRecord"
+	case *wit.Tuple:
+		return "KindTuple"
+	case *wit.Variant:
+		return "KindVariant"
+	case *wit.Enum:
+		return "KindEnum"
+	case *wit.Option:
+		return "KindOption"
+	case *wit.Result:
+		return "KindResult"
+	case *wit.Flags:
+		return "KindFlags"
+	case *wit.List:
+		return "KindList"
+	case *wit.Resource, *wit.Own, *wit.Borrow:
+		return "KindHandle"
+	case wit.Primitive:
+		return "Kind" + strings.ToUpper(kind.WIT(nil, "")[:1]) + kind.WIT(nil, "")[1:]
+	}
+	return ""
+}
+
+// typeInfoCases returns the field, flag, or case names for [wit.TypeDef] t, in order.
+func typeInfoCases(t *wit.TypeDef) []string {
+	var cases []string
+	switch kind := t.Kind.(type) {
+	case *wit.Record:
+		for _, f := range kind.Fields {
+			cases = append(cases, f.Name)
+		}
+	case *wit.Flags:
+		for _, f := range kind.Flags {
+			cases = append(cases, f.Name)
+		}
+	case *wit.Variant:
+		for _, c := range kind.Cases {
+			cases = append(cases, c.Name)
+		}
+	case *wit.Enum:
+		for _, c := range kind.Cases {
+			cases = append(cases, c.Name)
+		}
+	case *wit.Option:
+		cases = []string{"none", "some"}
+	case *wit.Result:
+		cases = []string{"ok", "error"}
+	}
+	return cases
+}
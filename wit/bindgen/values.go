@@ -0,0 +1,428 @@
+package bindgen
+
+import (
+	"go/token"
+	"strconv"
+	"strings"
+
+	"github.com/bytecodealliance/wasm-tools-go/internal/go/gen"
+	"github.com/bytecodealliance/wasm-tools-go/internal/stringio"
+	"github.com/bytecodealliance/wasm-tools-go/wit"
+)
+
+// hasValueMethods returns true if ToValue and FromValue methods are generated for [wit.TypeDef] t
+// and each type it contains. Records with a field that would collide with either method,
+// and types that contain them, have no value methods.
+func (g *generator) hasValueMethods(t *wit.TypeDef) bool {
+	for _, dep := range wit.DependencyGraph(t).TypeDefs() {
+		if r, ok := dep.Kind.(*wit.Record); ok {
+			for _, f := range r.Fields {
+				switch g.fieldName(f.Name, true) {
+				case "ToValue", "FromValue":
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// valueMethods returns Go source for the ToValue and FromValue methods of named [wit.TypeDef] t,
+// which convert between Go values and dynamic cm.Value values.
+// Variants with associated types are handled in variantValueMethods, and resources
+// and handles are represented as cm.Value handles without methods.
+// Types without value methods are described in hasValueMethods.
+func (g *generator) valueMethods(file *gen.File, dir wit.Direction, t *wit.TypeDef, goName string) string {
+	if !g.hasValueMethods(t) {
+		return ""
+	}
+	switch kind := t.Kind.(type) {
+	case *wit.Resource, *wit.Own, *wit.Borrow, *wit.Future, *wit.Stream, *wit.Pointer:
+		return ""
+	case *wit.Variant:
+		if kind.Enum() == nil {
+			return "" // see variantValueMethods
+		}
+	}
+
+	var toValue, fromValue string
+	cm := file.Import(g.opts.cmPackage)
+
+	switch kind := t.Kind.(type) {
+
+	case *wit.Variant:
+		toValue, fromValue = g.enumValueBodies(file, kind.Enum(), goName, "Variant")
+
+	case *wit.Enum:
+		toValue, fromValue = g.enumValueBodies(file, kind, goName, "Enum")
+
+	case *wit.Record:
+		exported := token.IsExported(goName)
+		var b strings.Builder
+		stringio.Write(&b, "return ", cm, ".RecordValue(")
+		for i, f := range kind.Fields {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			stringio.Write(&b, cm, ".Field{Name: \"", f.Name, "\", Value: ", g.toValueFunc(file, dir, f.Type), "(self.", g.fieldName(f.Name, exported), ")}")
+		}
+		b.WriteString(")\n")
+		toValue = b.String()
+
+		b.Reset()
+		stringio.Write(&b, "if err := v.Expect(", cm, ".KindRecord); err != nil {\nreturn err\n}\n")
+		if len(kind.Fields) > 0 {
+			b.WriteString("var err error\n")
+		}
+		for _, f := range kind.Fields {
+			stringio.Write(&b, "if f, ok := v.Field(\"", f.Name, "\"); !ok {\n")
+			stringio.Write(&b, "return ", file.Import("errors"), ".New(\"record: missing field: ", f.Name, "\")\n")
+			stringio.Write(&b, "} else if self.", g.fieldName(f.Name, exported), ", err = ", g.fromValueFunc(file, dir, f.Type), "(f); err != nil {\n")
+			b.WriteString("return err\n}\n")
+		}
+		b.WriteString("return nil\n")
+		fromValue = b.String()
+
+	case *wit.Flags:
+		var b strings.Builder
+		b.WriteString("var names []string\n")
+		for i, f := range kind.Flags {
+			stringio.Write(&b, "if self&(1<<", strconv.Itoa(i), ") != 0 {\n")
+			stringio.Write(&b, "names = append(names, \"", f.Name, "\")\n}\n")
+		}
+		stringio.Write(&b, "return ", cm, ".FlagsValue(names...)\n")
+		toValue = b.String()
+
+		b.Reset()
+		stringio.Write(&b, "if err := v.Expect(", cm, ".KindFlags); err != nil {\nreturn err\n}\n")
+		b.WriteString("*self = 0\n")
+		b.WriteString("for _, name := range v.Flags() {\n")
+		b.WriteString("switch name {\n")
+		for i, f := range kind.Flags {
+			stringio.Write(&b, "case \"", f.Name, "\":\n")
+			stringio.Write(&b, "*self |= 1 << ", strconv.Itoa(i), "\n")
+		}
+		b.WriteString("default:\n")
+		stringio.Write(&b, "return ", file.Import("errors"), ".New(\"flags: unknown flag: \" + name)\n")
+		b.WriteString("}\n}\n")
+		b.WriteString("return nil\n")
+		fromValue = b.String()
+
+	default:
+		// Defined types with the same underlying type as a WIT list, option, result,
+		// tuple, or primitive type are converted to the underlying type.
+		rep := g.typeDefKindRep(file, dir, kind, "")
+		toValue = "return " + g.toValueKindFunc(file, dir, kind) + "(" + rep + "(self))\n"
+		fromValue = "x, err := " + g.fromValueKindFunc(file, dir, kind) + "(v)\n" +
+			"*self = " + goName + "(x)\n" +
+			"return err\n"
+	}
+
+	return g.valueMethodsSource(file, goName, toValue, fromValue)
+}
+
+func (g *generator) valueMethodsSource(file *gen.File, goName, toValue, fromValue string) string {
+	cm := file.Import(g.opts.cmPackage)
+	var b strings.Builder
+	stringio.Write(&b, "// ToValue returns a dynamic [", cm, ".Value] representation of self.\n")
+	stringio.Write(&b, "func (self ", goName, ") ToValue() ", cm, ".Value {\n")
+	b.WriteString(toValue)
+	b.WriteString("}\n\n")
+	stringio.Write(&b, "// FromValue decodes dynamic [", cm, ".Value] v into self.\n")
+	stringio.Write(&b, "func (self *", goName, ") FromValue(v ", cm, ".Value) error {\n")
+	b.WriteString(fromValue)
+	b.WriteString("}\n\n")
+	return b.String()
+}
+
+// enumValueBodies returns the bodies of the ToValue and FromValue methods for an enum,
+// or a variant without associated types, represented as a dynamic Value of kind.
+func (g *generator) enumValueBodies(file *gen.File, e *wit.Enum, goName, kind string) (toValue, fromValue string) {
+	cm := file.Import(g.opts.cmPackage)
+	toValue = "return " + cm + "." + kind + "Value(self.String())\n"
+
+	var b strings.Builder
+	stringio.Write(&b, "if err := v.Expect(", cm, ".Kind", kind, "); err != nil {\nreturn err\n}\n")
+	stringio.Write(&b, "for i := 0; i < ", strconv.Itoa(len(e.Cases)), "; i++ {\n")
+	stringio.Write(&b, "if ", goName, "(i).String() == v.Case() {\n")
+	stringio.Write(&b, "*self = ", goName, "(i)\n")
+	b.WriteString("return nil\n}\n}\n")
+	stringio.Write(&b, "return ", file.Import("errors"), ".New(\"", strings.ToLower(kind), ": unknown case: \" + v.Case())\n")
+	fromValue = b.String()
+	return toValue, fromValue
+}
+
+// variantValueMethods returns Go source for the ToValue and FromValue methods
+// of variant type goName.
+func (g *generator) variantValueMethods(file *gen.File, dir wit.Direction, v *wit.Variant, goName string, caseNames, constructorNames []string) string {
+	if !g.hasValueMethods(&wit.TypeDef{Kind: v}) {
+		return ""
+	}
+	cm := file.Import(g.opts.cmPackage)
+
+	var b strings.Builder
+	b.WriteString("switch self.Tag() {\n")
+	for i, c := range v.Cases {
+		stringio.Write(&b, "case ", strconv.Itoa(i), ":\n")
+		if c.Type == nil {
+			stringio.Write(&b, "return ", cm, ".VariantValue(\"", c.Name, "\")\n")
+		} else {
+			stringio.Write(&b, "return ", cm, ".VariantValue(\"", c.Name, "\", ", g.toValueFunc(file, dir, c.Type), "(*self.", caseNames[i], "()))\n")
+		}
+	}
+	b.WriteString("}\n")
+	stringio.Write(&b, "return ", cm, ".Value{}\n")
+	toValue := b.String()
+
+	b.Reset()
+	stringio.Write(&b, "if err := v.Expect(", cm, ".KindVariant); err != nil {\nreturn err\n}\n")
+	b.WriteString("payload, _ := v.Payload()\n")
+	b.WriteString("switch v.Case() {\n")
+	for i, c := range v.Cases {
+		stringio.Write(&b, "case \"", c.Name, "\":\n")
+		if c.Type == nil {
+			stringio.Write(&b, "*self = ", constructorNames[i], "()\n")
+			b.WriteString("return nil\n")
+		} else {
+			stringio.Write(&b, "x, err := ", g.fromValueFunc(file, dir, c.Type), "(payload)\n")
+			stringio.Write(&b, "*self = ", constructorNames[i], "(x)\n")
+			b.WriteString("return err\n")
+		}
+	}
+	b.WriteString("}\n")
+	stringio.Write(&b, "return ", file.Import("errors"), ".New(\"variant: unknown case: \" + v.Case())\n")
+	fromValue := b.String()
+
+	return g.valueMethodsSource(file, goName, toValue, fromValue)
+}
+
+// toValueFunc returns a Go expression of type func(T) cm.Value,
+// where T is the Go type of [wit.Type] t.
+func (g *generator) toValueFunc(file *gen.File, dir wit.Direction, t wit.Type) string {
+	cm := file.Import(g.opts.cmPackage)
+	switch t := t.(type) {
+	case *wit.TypeDef:
+		decl, ok := g.typeDecl(dir, t)
+		if !ok {
+			return g.toValueKindFunc(file, dir, t.Kind)
+		}
+		typ := file.RelativeName(decl.file.Package, decl.name)
+		switch t.Root().Kind.(type) {
+		case *wit.Resource, *wit.Own, *wit.Borrow:
+			return cm + ".HandleValue[" + typ + "]"
+		case *wit.Future, *wit.Stream:
+			return g.toValueKindFunc(file, dir, t.Root().Kind)
+		}
+		return typ + ".ToValue"
+	case wit.Primitive:
+		return cm + "." + primitiveValueName(t) + "Value"
+	}
+	return "nil"
+}
+
+// toValueKindFunc returns a Go expression of type func(T) cm.Value,
+// where T is the Go type of anonymous [wit.TypeDefKind] kind.
+func (g *generator) toValueKindFunc(file *gen.File, dir wit.Direction, kind wit.TypeDefKind) string {
+	cm := file.Import(g.opts.cmPackage)
+	rep := g.typeDefKindRep(file, dir, kind, "")
+	var b strings.Builder
+	switch kind := kind.(type) {
+	case wit.Type:
+		return g.toValueFunc(file, dir, kind)
+
+	case *wit.Own, *wit.Borrow:
+		return cm + ".HandleValue[" + rep + "]"
+
+	case *wit.List:
+		stringio.Write(&b, "func(v ", rep, ") ", cm, ".Value {\n")
+		stringio.Write(&b, "elems := make([]", cm, ".Value, 0, v.Len())\n")
+		b.WriteString("for _, e := range v.Slice() {\n")
+		stringio.Write(&b, "elems = append(elems, ", g.toValueFunc(file, dir, kind.Type), "(e))\n")
+		b.WriteString("}\n")
+		stringio.Write(&b, "return ", cm, ".ListValue(elems...)\n")
+		b.WriteString("}")
+
+	case *wit.Option:
+		stringio.Write(&b, "func(v ", rep, ") ", cm, ".Value {\n")
+		b.WriteString("if some := v.Some(); some != nil {\n")
+		stringio.Write(&b, "return ", cm, ".SomeValue(", g.toValueFunc(file, dir, kind.Type), "(*some))\n")
+		b.WriteString("}\n")
+		stringio.Write(&b, "return ", cm, ".NoneValue()\n")
+		b.WriteString("}")
+
+	case *wit.Result:
+		stringio.Write(&b, "func(v ", rep, ") ", cm, ".Value {\n")
+		if kind.OK == nil && kind.Err == nil {
+			stringio.Write(&b, "if v {\nreturn ", cm, ".ErrValue()\n}\n")
+			stringio.Write(&b, "return ", cm, ".OKValue()\n")
+		} else {
+			if kind.Err == nil {
+				stringio.Write(&b, "if v.IsErr() {\nreturn ", cm, ".ErrValue()\n}\n")
+			} else {
+				stringio.Write(&b, "if err := v.Err(); err != nil {\nreturn ", cm, ".ErrValue(", g.toValueFunc(file, dir, kind.Err), "(*err))\n}\n")
+			}
+			if kind.OK == nil {
+				stringio.Write(&b, "return ", cm, ".OKValue()\n")
+			} else {
+				stringio.Write(&b, "return ", cm, ".OKValue(", g.toValueFunc(file, dir, kind.OK), "(*v.OK()))\n")
+			}
+		}
+		b.WriteString("}")
+
+	case *wit.Tuple:
+		stringio.Write(&b, "func(v ", rep, ") ", cm, ".Value {\n")
+		stringio.Write(&b, "return ", cm, ".TupleValue(")
+		for i, typ := range kind.Types {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			stringio.Write(&b, g.toValueFunc(file, dir, typ), "(", tupleElem(kind, "v", i), ")")
+		}
+		b.WriteString(")\n")
+		b.WriteString("}")
+
+	default: // future, stream
+		stringio.Write(&b, "func(", rep, ") ", cm, ".Value {\nreturn ", cm, ".Value{}\n}")
+	}
+	return b.String()
+}
+
+// fromValueFunc returns a Go expression of type func(cm.Value) (T, error),
+// where T is the Go type of [wit.Type] t.
+func (g *generator) fromValueFunc(file *gen.File, dir wit.Direction, t wit.Type) string {
+	cm := file.Import(g.opts.cmPackage)
+	switch t := t.(type) {
+	case *wit.TypeDef:
+		decl, ok := g.typeDecl(dir, t)
+		if !ok {
+			return g.fromValueKindFunc(file, dir, t.Kind)
+		}
+		typ := file.RelativeName(decl.file.Package, decl.name)
+		switch t.Root().Kind.(type) {
+		case *wit.Resource, *wit.Own, *wit.Borrow:
+			return cm + ".HandleFromValue[" + typ + "]"
+		case *wit.Future, *wit.Stream:
+			return g.fromValueKindFunc(file, dir, t.Root().Kind)
+		}
+		var b strings.Builder
+		stringio.Write(&b, "func(v ", cm, ".Value) (", typ, ", error) {\n")
+		stringio.Write(&b, "var x ", typ, "\n")
+		b.WriteString("err := x.FromValue(v)\n")
+		b.WriteString("return x, err\n")
+		b.WriteString("}")
+		return b.String()
+	case wit.Primitive:
+		return cm + ".FromValue[" + g.primitiveRep(t) + "]"
+	}
+	return "nil"
+}
+
+// fromValueKindFunc returns a Go expression of type func(cm.Value) (T, error),
+// where T is the Go type of anonymous [wit.TypeDefKind] kind.
+func (g *generator) fromValueKindFunc(file *gen.File, dir wit.Direction, kind wit.TypeDefKind) string {
+	cm := file.Import(g.opts.cmPackage)
+	rep := g.typeDefKindRep(file, dir, kind, "")
+	var b strings.Builder
+	switch kind := kind.(type) {
+	case wit.Type:
+		return g.fromValueFunc(file, dir, kind)
+
+	case *wit.Own, *wit.Borrow:
+		return cm + ".HandleFromValue[" + rep + "]"
+
+	case *wit.List:
+		stringio.Write(&b, "func(v ", cm, ".Value) (", rep, ", error) {\n")
+		stringio.Write(&b, "if err := v.Expect(", cm, ".KindList); err != nil {\nreturn ", rep, "{}, err\n}\n")
+		stringio.Write(&b, "s := make([]", g.typeRep(file, dir, kind.Type), ", v.Len())\n")
+		b.WriteString("for i := range s {\n")
+		b.WriteString("var err error\n")
+		stringio.Write(&b, "if s[i], err = ", g.fromValueFunc(file, dir, kind.Type), "(v.Index(i)); err != nil {\n")
+		stringio.Write(&b, "return ", rep, "{}, err\n")
+		b.WriteString("}\n}\n")
+		stringio.Write(&b, "return ", cm, ".ToList(s), nil\n")
+		b.WriteString("}")
+
+	case *wit.Option:
+		stringio.Write(&b, "func(v ", cm, ".Value) (", rep, ", error) {\n")
+		stringio.Write(&b, "if err := v.Expect(", cm, ".KindOption); err != nil {\nreturn ", rep, "{}, err\n}\n")
+		b.WriteString("payload, ok := v.Payload()\n")
+		stringio.Write(&b, "if !ok {\nreturn ", rep, "{}, nil\n}\n")
+		stringio.Write(&b, "x, err := ", g.fromValueFunc(file, dir, kind.Type), "(payload)\n")
+		stringio.Write(&b, "return ", cm, ".Some(x), err\n")
+		b.WriteString("}")
+
+	case *wit.Result:
+		stringio.Write(&b, "func(v ", cm, ".Value) (", rep, ", error) {\n")
+		if kind.OK == nil && kind.Err == nil {
+			stringio.Write(&b, "if err := v.Expect(", cm, ".KindResult); err != nil {\nreturn false, err\n}\n")
+			stringio.Write(&b, "return v.Case() == \"err\", nil\n")
+		} else {
+			stringio.Write(&b, "var r ", rep, "\n")
+			stringio.Write(&b, "if err := v.Expect(", cm, ".KindResult); err != nil {\nreturn r, err\n}\n")
+			b.WriteString("payload, _ := v.Payload()\n")
+			b.WriteString("if v.Case() == \"err\" {\n")
+			if kind.Err == nil {
+				stringio.Write(&b, "return ", cm, ".Err[", rep, "](struct{}{}), nil\n")
+			} else {
+				stringio.Write(&b, "x, err := ", g.fromValueFunc(file, dir, kind.Err), "(payload)\n")
+				stringio.Write(&b, "return ", cm, ".Err[", rep, "](x), err\n")
+			}
+			b.WriteString("}\n")
+			if kind.OK == nil {
+				stringio.Write(&b, "return ", cm, ".OK[", rep, "](struct{}{}), nil\n")
+			} else {
+				stringio.Write(&b, "x, err := ", g.fromValueFunc(file, dir, kind.OK), "(payload)\n")
+				stringio.Write(&b, "return ", cm, ".OK[", rep, "](x), err\n")
+			}
+		}
+		b.WriteString("}")
+
+	case *wit.Tuple:
+		stringio.Write(&b, "func(v ", cm, ".Value) (", rep, ", error) {\n")
+		stringio.Write(&b, "var t ", rep, "\n")
+		stringio.Write(&b, "if err := v.Expect(", cm, ".KindTuple); err != nil {\nreturn t, err\n}\n")
+		n := strconv.Itoa(len(kind.Types))
+		stringio.Write(&b, "if v.Len() != ", n, " {\n")
+		stringio.Write(&b, "return t, ", file.Import("errors"), ".New(\"tuple: expected ", n, " elements\")\n")
+		b.WriteString("}\n")
+		if len(kind.Types) > 0 {
+			b.WriteString("var err error\n")
+		}
+		for i, typ := range kind.Types {
+			stringio.Write(&b, "if ", tupleElem(kind, "t", i), ", err = ", g.fromValueFunc(file, dir, typ), "(v.Index(", strconv.Itoa(i), ")); err != nil {\n")
+			b.WriteString("return t, err\n")
+			b.WriteString("}\n")
+		}
+		b.WriteString("return t, nil\n")
+		b.WriteString("}")
+
+	default: // future, stream
+		stringio.Write(&b, "func(", cm, ".Value) (", rep, ", error) {\n")
+		stringio.Write(&b, "return nil, ", file.Import("errors"), ".New(\"unsupported type: ", kind.WIT(nil, ""), "\")\n")
+		b.WriteString("}")
+	}
+	return b.String()
+}
+
+// tupleElem returns a Go expression for the i'th element of tuple value v.
+// Tuples with a single element type are represented as Go arrays.
+func tupleElem(t *wit.Tuple, v string, i int) string {
+	if t.Type() != nil {
+		return v + "[" + strconv.Itoa(i) + "]"
+	}
+	return v + ".F" + strconv.Itoa(i)
+}
+
+// primitiveValueName returns the name of the [wit.Primitive] p as used in
+// the cm.Value constructors, e.g. "U8" for cm.U8Value.
+func primitiveValueName(p wit.Primitive) string {
+	switch p.(type) {
+	case wit.Bool:
+		return "Bool"
+	case wit.Char:
+		return "Char"
+	case wit.String:
+		return "String"
+	}
+	return strings.ToUpper(p.WIT(nil, ""))
+}
@@ -0,0 +1,307 @@
+package bindgen
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/bytecodealliance/wasm-tools-go/internal/codec"
+	"github.com/bytecodealliance/wasm-tools-go/internal/go/gen"
+	"github.com/bytecodealliance/wasm-tools-go/wit"
+)
+
+// defineOrigins records the WIT world, interface, type, or function that each Go package
+// and declaration was generated from in [gen.Package] Origins, for reporting by [Verify].
+func (g *generator) defineOrigins() {
+	for owner, pkg := range g.witPackages {
+		if _, ok := pkg.Origins[""]; !ok {
+			pkg.Origins[""] = origin(owner, g.moduleNames[owner])
+		}
+	}
+	for _, dir := range []wit.Direction{wit.Imported, wit.Exported} {
+		for t, decl := range g.types[dir] {
+			if t.Name != nil {
+				decl.file.Package.Origins[decl.name] = origin(t, g.moduleNames[t.Owner]+"#"+*t.Name)
+			}
+		}
+		for f, decl := range g.functions[dir] {
+			o := origin(f, g.moduleNames[decl.owner]+"#"+f.Name)
+			for _, fn := range []*function{&decl.goFunc, &decl.wasmFunc} {
+				if fn.file == nil {
+					continue
+				}
+				name := fn.name
+				if t, ok := fn.receiver.typ.(*wit.TypeDef); ok {
+					if td, ok := g.typeDecl(dir, t); ok {
+						name = td.name + "." + name
+					}
+				}
+				fn.file.Package.Origins[name] = o
+			}
+		}
+	}
+}
+
+// origin returns a description of WIT node with qualified name, e.g. `enum "wasi:io/error#error"`.
+func origin(node wit.Node, name string) string {
+	return node.WITKind() + " " + strconv.Quote(name)
+}
+
+// VerifyError is returned by [Verify] if generated Go packages do not compile.
+type VerifyError struct {
+	Diagnostics []Diagnostic
+}
+
+// Error implements the error interface, listing each diagnostic on its own line.
+func (e *VerifyError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "generated Go does not compile: %d error(s)", len(e.Diagnostics))
+	for _, d := range e.Diagnostics {
+		b.WriteString("\n\t")
+		b.WriteString(d.String())
+	}
+	return b.String()
+}
+
+// Diagnostic describes an error in generated Go code, such as an unresolved identifier,
+// a duplicate declaration, or an import cycle, with the WIT item that generated it.
+type Diagnostic struct {
+	// Pos is the position of the error in a generated file, if known.
+	Pos token.Position
+
+	// Package is the Go package path of the generated package.
+	Package string
+
+	// Decl is the name of the package-scoped Go declaration that contains the error, if known.
+	// Methods are named "Type.Method".
+	Decl string
+
+	// Origin describes the WIT item that Decl or Package was generated from, if known,
+	// e.g. `enum "wasi:io/error#error"` or `interface "wasi:io/streams@0.2.0"`.
+	Origin string
+
+	// Msg is the error message reported by the Go type checker or go command.
+	Msg string
+}
+
+// String returns a description of d, e.g. "streams/streams.wit.go:12:3: undefined: X
+// (in OutputStream.Write, generated from method "wasi:io/streams#[method]output-stream.write")".
+func (d *Diagnostic) String() string {
+	var b strings.Builder
+	if d.Pos.IsValid() {
+		b.WriteString(d.Pos.String())
+	} else {
+		b.WriteString(d.Package)
+	}
+	b.WriteString(": ")
+	b.WriteString(d.Msg)
+	switch {
+	case d.Decl != "" && d.Origin != "":
+		fmt.Fprintf(&b, " (in %s, generated from %s)", d.Decl, d.Origin)
+	case d.Decl != "":
+		fmt.Fprintf(&b, " (in %s)", d.Decl)
+	case d.Origin != "":
+		fmt.Fprintf(&b, " (generated from %s)", d.Origin)
+	}
+	return b.String()
+}
+
+// Verify type-checks Go packages pkgs with the go command and go/types as if they were written
+// to directory dir, with Go package root, e.g. the output directory and [PackageRoot] passed to
+// [Go]. Files are not written to dir. Packages may include a go.mod file, otherwise dir must
+// be in a Go module that can import the Component Model utility package.
+// The go command runs with the Go environment of dir, including GOFLAGS, go.work workspaces,
+// and vendored dependencies. See [gen.LoadBuildEnv].
+//
+// It returns a [*VerifyError] if the packages do not compile, describing each error with
+// the WIT item it was generated from, or any other error that prevents type-checking.
+func Verify(pkgs []*gen.Package, dir, root string) error {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	env, err := gen.LoadBuildEnv(ctx, abs)
+	if err != nil {
+		if !hasGoMod(pkgs) {
+			return err
+		}
+		// The go command cannot report the environment of a module whose go.mod file is overlaid.
+		env = &gen.BuildEnv{Dir: abs, Env: os.Environ()}
+	}
+	w := &gen.Writer{Root: abs, PackageRoot: root}
+	cfg := env.PackagesConfig(ctx, packages.NeedName|packages.NeedFiles|packages.NeedImports|packages.NeedTypes|packages.NeedSyntax, "")
+	cfg.Fset = token.NewFileSet()
+	cfg.Overlay = make(map[string][]byte)
+	pkgMap := make(map[string]*gen.Package)
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			// Assembly files are omitted, as the go command cannot assemble files in overlay directories.
+			if !file.HasContent() || strings.HasSuffix(file.Name, ".s") {
+				continue
+			}
+			rel, err := w.Rel(file)
+			if err != nil {
+				return err
+			}
+			content, err := file.Bytes()
+			if err != nil && content == nil {
+				return err
+			}
+			cfg.Overlay[filepath.Join(abs, filepath.FromSlash(rel))] = content
+			if file.IsGo() {
+				pkgMap[pkg.Path] = pkg
+			}
+		}
+	}
+	if len(pkgMap) == 0 {
+		return nil
+	}
+
+	goPackages, err := packages.Load(cfg, codec.SortedKeys(pkgMap)...)
+	if err != nil {
+		return err
+	}
+	var verr VerifyError
+	for _, goPkg := range goPackages {
+		pkg := pkgMap[goPkg.PkgPath]
+		if pkg == nil {
+			continue
+		}
+		for _, err := range goPkg.Errors {
+			// The go command reports compiler output that duplicates type errors.
+			if err.Kind == packages.ListError && strings.HasPrefix(err.Msg, "# ") {
+				continue
+			}
+			d := Diagnostic{
+				Pos:     parsePosition(err.Pos),
+				Package: pkg.Path,
+				Msg:     err.Msg,
+			}
+			d.Decl = declAt(cfg.Fset, goPkg.Syntax, d.Pos)
+			d.Origin = pkg.Origins[d.Decl]
+			if recv, _, ok := strings.Cut(d.Decl, "."); ok && d.Origin == "" {
+				// Methods such as String are generated for the WIT type of their receiver.
+				d.Origin = pkg.Origins[recv]
+			}
+			if d.Origin == "" {
+				d.Origin = pkg.Origins[""]
+			}
+			if rel, err := filepath.Rel(abs, d.Pos.Filename); err == nil && d.Pos.Filename != "" {
+				d.Pos.Filename = filepath.Join(dir, rel)
+			}
+			verr.Diagnostics = append(verr.Diagnostics, d)
+		}
+	}
+	if len(verr.Diagnostics) > 0 {
+		return &verr
+	}
+	return nil
+}
+
+// hasGoMod returns true if pkgs include a go.mod file.
+func hasGoMod(pkgs []*gen.Package) bool {
+	for _, pkg := range pkgs {
+		if f := pkg.Files["go.mod"]; f != nil && f.HasContent() {
+			return true
+		}
+	}
+	return false
+}
+
+// parsePosition parses position s, in the form "file:line:col", "file:line", or "file".
+// It returns the zero [token.Position] if s is empty or "-".
+func parsePosition(s string) token.Position {
+	var pos token.Position
+	if s == "" || s == "-" {
+		return pos
+	}
+	pos.Filename = s
+	for _, p := range []*int{&pos.Column, &pos.Line} {
+		i := strings.LastIndexByte(pos.Filename, ':')
+		if i < 0 {
+			break
+		}
+		n, err := strconv.Atoi(pos.Filename[i+1:])
+		if err != nil {
+			break
+		}
+		*p = n
+		pos.Filename = pos.Filename[:i]
+	}
+	if pos.Line == 0 {
+		// "file:line" was parsed as a column.
+		pos.Line, pos.Column = pos.Column, 0
+	}
+	return pos
+}
+
+// declAt returns the name of the package-scoped declaration in files at pos,
+// or an empty string if none. Methods are named "Type.Method".
+func declAt(fset *token.FileSet, files []*ast.File, pos token.Position) string {
+	if pos.Line == 0 {
+		return ""
+	}
+	for _, file := range files {
+		if fset.Position(file.Pos()).Filename != pos.Filename {
+			continue
+		}
+		for _, decl := range file.Decls {
+			if fset.Position(decl.Pos()).Line > pos.Line || fset.Position(decl.End()).Line < pos.Line {
+				continue
+			}
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				if decl.Recv == nil || len(decl.Recv.List) == 0 {
+					return decl.Name.Name
+				}
+				return receiverName(decl.Recv.List[0].Type) + "." + decl.Name.Name
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					if fset.Position(spec.Pos()).Line > pos.Line || fset.Position(spec.End()).Line < pos.Line {
+						continue
+					}
+					switch spec := spec.(type) {
+					case *ast.TypeSpec:
+						return spec.Name.Name
+					case *ast.ValueSpec:
+						for _, name := range spec.Names {
+							if name.Name != "_" {
+								return name.Name
+							}
+						}
+					}
+				}
+			}
+			return ""
+		}
+	}
+	return ""
+}
+
+// receiverName returns the name of the type of method receiver expr, e.g. "T" for *T or T[A].
+func receiverName(expr ast.Expr) string {
+	for {
+		switch x := expr.(type) {
+		case *ast.StarExpr:
+			expr = x.X
+		case *ast.IndexExpr:
+			expr = x.X
+		case *ast.IndexListExpr:
+			expr = x.X
+		case *ast.ParenExpr:
+			expr = x.X
+		case *ast.Ident:
+			return x.Name
+		default:
+			return ""
+		}
+	}
+}
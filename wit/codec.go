@@ -34,6 +34,8 @@ func (res *Resolve) ResolveCodec(v any) codec.Codec {
 	// Allocation required
 	case **Function:
 		return codec.Must(v)
+	case **Include:
+		return codec.Must(v)
 
 	// Enums
 	case *FunctionKind:
@@ -117,12 +119,42 @@ func (c *worldCodec) DecodeField(dec codec.Decoder, name string) error {
 		return dec.Decode(&w.Imports)
 	case "exports":
 		return dec.Decode(&w.Exports)
+	case "includes":
+		return codec.DecodeSlice(dec, &w.Includes)
 	case "package":
 		return dec.Decode(&w.Package)
 	case "stability":
 		return dec.Decode(&w.Stability)
 	case "docs":
 		return dec.Decode(&w.Docs)
+	case "position":
+		return dec.Decode(&w.Pos)
+	}
+	return nil
+}
+
+// DecodeField implements the [codec.FieldDecoder] interface
+// to decode a struct or JSON object.
+func (inc *Include) DecodeField(dec codec.Decoder, name string) error {
+	switch name {
+	case "world":
+		return dec.Decode(&inc.World)
+	case "names":
+		return codec.DecodeSlice(dec, &inc.Names)
+	case "stability":
+		return dec.Decode(&inc.Stability)
+	}
+	return nil
+}
+
+// DecodeField implements the [codec.FieldDecoder] interface
+// to decode a struct or JSON object.
+func (n *IncludeName) DecodeField(dec codec.Decoder, name string) error {
+	switch name {
+	case "name":
+		return dec.Decode(&n.Name)
+	case "as":
+		return dec.Decode(&n.As)
 	}
 	return nil
 }
@@ -153,6 +185,8 @@ func (c *interfaceCodec) DecodeField(dec codec.Decoder, name string) error {
 		return dec.Decode(&i.Stability)
 	case "docs":
 		return dec.Decode(&i.Docs)
+	case "position":
+		return dec.Decode(&i.Pos)
 	}
 	return nil
 }
@@ -181,6 +215,8 @@ func (c *typeDefCodec) DecodeField(dec codec.Decoder, name string) error {
 		return dec.Decode(&t.Stability)
 	case "docs":
 		return dec.Decode(&t.Docs)
+	case "position":
+		return dec.Decode(&t.Pos)
 	}
 	return nil
 }
@@ -252,6 +288,20 @@ func (d *Docs) DecodeField(dec codec.Decoder, name string) error {
 	return nil
 }
 
+// DecodeField implements the [codec.FieldDecoder] interface
+// to decode a struct or JSON object.
+func (p *Position) DecodeField(dec codec.Decoder, name string) error {
+	switch name {
+	case "file":
+		return dec.Decode(&p.Filename)
+	case "line":
+		return dec.Decode(&p.Line)
+	case "column":
+		return dec.Decode(&p.Column)
+	}
+	return nil
+}
+
 // worldItemCodec translates typed WorldItem references into a WorldItem,
 // currently either an Interface or a TypeDef.
 type worldItemCodec struct {
@@ -596,6 +646,8 @@ func (f *Function) DecodeField(dec codec.Decoder, name string) error {
 		return dec.Decode(&f.Stability)
 	case "docs":
 		return dec.Decode(&f.Docs)
+	case "position":
+		return dec.Decode(&f.Pos)
 	}
 	return nil
 }
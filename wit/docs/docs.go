@@ -0,0 +1,178 @@
+// Package docs renders documentation for WIT packages as Markdown or HTML,
+// with tables of the types, functions, imports, and exports of each interface and world,
+// their WIT definitions, and their doc comments.
+package docs
+
+import (
+	"strings"
+
+	"github.com/bytecodealliance/wasm-tools-go/wit"
+	"github.com/bytecodealliance/wasm-tools-go/wit/ordered"
+)
+
+// document is the documentation for a single WIT package.
+type document struct {
+	Name       string
+	Docs       string
+	Interfaces []section
+	Worlds     []section
+}
+
+// section is the documentation for a WIT interface or world.
+type section struct {
+	ID   string
+	Kind string
+	Name string
+	Docs string
+
+	// Tables of items, such as types and functions, or imports and exports.
+	Tables []table
+}
+
+// table is a titled list of documented items.
+type table struct {
+	Title string
+	Items []item
+}
+
+// item is the documentation for a single WIT type, function, or world import or export.
+type item struct {
+	ID   string
+	Name string
+	Kind string
+	Docs string
+	WIT  string // WIT definition, if any
+}
+
+// Summary returns the first paragraph of the docs of i, on a single line.
+func (i *item) Summary() string {
+	return summary(i.Docs)
+}
+
+func newDocument(pkg *wit.Package) *document {
+	doc := &document{
+		Name: pkg.Name.String(),
+		Docs: pkg.Docs.Contents,
+	}
+	pkg.Interfaces.All()(func(name string, i *wit.Interface) bool {
+		doc.Interfaces = append(doc.Interfaces, newInterfaceSection(doc.Name, name, i))
+		return true
+	})
+	pkg.Worlds.All()(func(name string, w *wit.World) bool {
+		doc.Worlds = append(doc.Worlds, newWorldSection(doc.Name, w))
+		return true
+	})
+	return doc
+}
+
+func newInterfaceSection(pkgName, name string, i *wit.Interface) section {
+	s := section{
+		ID:   id(pkgName, name),
+		Kind: "interface",
+		Name: name,
+		Docs: i.Docs.Contents,
+	}
+	types := table{Title: "Types"}
+	i.TypeDefs.All()(func(name string, t *wit.TypeDef) bool {
+		types.Items = append(types.Items, typeItem(s.ID, name, t))
+		return true
+	})
+	funcs := table{Title: "Functions"}
+	i.Functions.All()(func(_ string, f *wit.Function) bool {
+		funcs.Items = append(funcs.Items, functionItem(s.ID, f))
+		return true
+	})
+	for _, t := range []table{types, funcs} {
+		if len(t.Items) > 0 {
+			s.Tables = append(s.Tables, t)
+		}
+	}
+	return s
+}
+
+func newWorldSection(pkgName string, w *wit.World) section {
+	s := section{
+		ID:   id(pkgName, w.Name),
+		Kind: "world",
+		Name: w.Name,
+		Docs: w.Docs.Contents,
+	}
+	imports := table{Title: "Imports", Items: worldItems(s.ID, &w.Imports)}
+	exports := table{Title: "Exports", Items: worldItems(s.ID, &w.Exports)}
+	for _, t := range []table{imports, exports} {
+		if len(t.Items) > 0 {
+			s.Tables = append(s.Tables, t)
+		}
+	}
+	return s
+}
+
+func worldItems(parentID string, m *ordered.Map[string, wit.WorldItem]) []item {
+	var items []item
+	m.All()(func(name string, v wit.WorldItem) bool {
+		switch v := v.(type) {
+		case *wit.InterfaceRef:
+			i := item{
+				ID:   id(parentID, name),
+				Name: name,
+				Kind: "interface",
+				Docs: v.Interface.Docs.Contents,
+			}
+			if v.Interface.Name != nil && v.Interface.Package != nil {
+				id := v.Interface.Package.Name
+				id.Extension = *v.Interface.Name
+				i.Name = id.String()
+			}
+			items = append(items, i)
+		case *wit.TypeDef:
+			items = append(items, typeItem(parentID, name, v))
+		case *wit.Function:
+			items = append(items, functionItem(parentID, v))
+		}
+		return true
+	})
+	return items
+}
+
+func typeItem(parentID, name string, t *wit.TypeDef) item {
+	return item{
+		ID:   id(parentID, name),
+		Name: name,
+		Kind: t.WITKind(),
+		Docs: t.Docs.Contents,
+		WIT:  t.Kind.WIT(t, name),
+	}
+}
+
+func functionItem(parentID string, f *wit.Function) item {
+	name := f.Name
+	if t := f.Type(); t != nil {
+		name = t.TypeName() + "." + f.BaseName()
+	}
+	return item{
+		ID:   id(parentID, name),
+		Name: name,
+		Kind: f.WITKind(),
+		Docs: f.Docs.Contents,
+		WIT:  f.WIT(nil, ""),
+	}
+}
+
+// id returns a fragment identifier for name in the scope of parent.
+// Characters other than ASCII letters, digits, '-', '.', and '_' are replaced with '-',
+// so the identifier can be used in a URL without escaping.
+func id(parent, name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '.', r == '_':
+			return r
+		}
+		return '-'
+	}, parent+"/"+name)
+}
+
+// summary returns the first paragraph of docs, with lines joined by spaces.
+func summary(docs string) string {
+	para, _, _ := strings.Cut(strings.TrimSpace(docs), "\n\n")
+	return strings.Join(strings.Fields(para), " ")
+}
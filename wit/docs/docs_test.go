@@ -0,0 +1,89 @@
+package docs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bytecodealliance/wasm-tools-go/wit"
+)
+
+const testdataPath = "../../testdata"
+
+func loadPackage(t *testing.T, path, name string) *wit.Package {
+	res, err := wit.LoadJSON(testdataPath + path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, pkg := range res.Packages {
+		if pkg.Name.String() == name {
+			return pkg
+		}
+	}
+	t.Fatalf("package %s not found", name)
+	return nil
+}
+
+func TestMarkdown(t *testing.T) {
+	pkg := loadPackage(t, "/wasi/cli.wit.json", "wasi:io@0.2.0")
+	got := Markdown(pkg)
+	for _, want := range []string{
+		"# Package `wasi:io@0.2.0`\n",
+		"\n## Interface `streams`\n\nWASI I/O is an I/O abstraction API",
+		"\n### Types\n\n| Name | Kind | Description |\n| ---- | ---- | ----------- |\n| `error` | type alias |  |\n",
+		"| `stream-error` | variant | An error for input-stream and output-stream operations. |\n",
+		"\n#### `pollable`\n\n```wit\nuse poll.{pollable};\n```\n",
+		"| `input-stream.read` | method | Perform a non-blocking read from the stream. |\n",
+		"\n#### `poll`\n\nPoll for completion on a set of pollables.\n",
+		"```wit\npoll: func(in: list<borrow<pollable>>) -> list<u32>;\n```\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Markdown does not contain %q", want)
+		}
+	}
+
+	pkg = loadPackage(t, "/wasi/cli.wit.json", "wasi:cli@0.2.0")
+	got = Markdown(pkg)
+	for _, want := range []string{
+		"\n## World `command`\n",
+		"\n### Imports\n\n| Name | Kind | Description |\n",
+		"| `wasi:io/streams@0.2.0` | interface | WASI I/O is an I/O abstraction API which is currently focused on providing stream types. |\n",
+		"\n### Exports\n\n| Name | Kind | Description |\n| ---- | ---- | ----------- |\n| `wasi:cli/run@0.2.0` | interface |  |\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Markdown does not contain %q", want)
+		}
+	}
+}
+
+func TestHTML(t *testing.T) {
+	pkg := loadPackage(t, "/wasi/cli.wit.json", "wasi:io@0.2.0")
+	got := HTML(pkg)
+	for _, want := range []string{
+		"<title>wasi:io@0.2.0</title>",
+		`<h2 id="wasi-io-0.2.0-streams">Interface <code>streams</code></h2>`,
+		`<tr><td><a href="#wasi-io-0.2.0-streams-stream-error"><code>stream-error</code></a></td><td>variant</td>`,
+		`<h4 id="wasi-io-0.2.0-poll-poll"><code>poll</code></h4>`,
+		"<pre><code>poll: func(in: list&lt;borrow&lt;pollable&gt;&gt;) -&gt; list&lt;u32&gt;;</code></pre>",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("HTML does not contain %q", want)
+		}
+	}
+}
+
+func TestSummary(t *testing.T) {
+	tests := []struct {
+		docs string
+		want string
+	}{
+		{"", ""},
+		{"One line.", "One line."},
+		{"First\nparagraph.\n\nSecond paragraph.", "First paragraph."},
+		{"\n  Indented\n\ttext.  \n", "Indented text."},
+	}
+	for _, tt := range tests {
+		if got := summary(tt.docs); got != tt.want {
+			t.Errorf("summary(%q): %q, expected %q", tt.docs, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package docs
+
+import (
+	"html/template"
+	"strings"
+
+	"github.com/bytecodealliance/wasm-tools-go/wit"
+)
+
+// HTML returns a standalone HTML document with documentation for pkgs, with the same structure
+// as [Markdown]. Names in tables link to their definitions. WIT doc comments are rendered as
+// preformatted text, as Markdown is not converted to HTML.
+func HTML(pkgs ...*wit.Package) string {
+	var docs []*document
+	var names []string
+	for _, pkg := range pkgs {
+		docs = append(docs, newDocument(pkg))
+		names = append(names, pkg.Name.String())
+	}
+	var b strings.Builder
+	err := htmlTemplate.Execute(&b, struct {
+		Title     string
+		Documents []*document
+	}{strings.Join(names, ", "), docs})
+	if err != nil {
+		panic("BUG: " + err.Error()) // should never reach here
+	}
+	return b.String()
+}
+
+var htmlTemplate = template.Must(template.New("docs").Funcs(template.FuncMap{
+	"title": titleCase,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; max-width: 60em; margin: 0 auto; padding: 1em; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 0.25em 0.5em; text-align: left; vertical-align: top; }
+pre { background: #f6f8fa; padding: 0.5em; overflow-x: auto; }
+pre.docs { background: none; padding: 0; white-space: pre-wrap; font-family: inherit; }
+</style>
+</head>
+<body>
+{{- range .Documents}}
+<h1>Package <code>{{.Name}}</code></h1>
+{{- template "doc" .Docs}}
+{{- range .Interfaces}}{{template "section" .}}{{end}}
+{{- range .Worlds}}{{template "section" .}}{{end}}
+{{- end}}
+</body>
+</html>
+{{define "section"}}
+<h2 id="{{.ID}}">{{title .Kind}} <code>{{.Name}}</code></h2>
+{{- template "doc" .Docs}}
+{{- range .Tables}}
+<h3>{{.Title}}</h3>
+<table>
+<tr><th>Name</th><th>Kind</th><th>Description</th></tr>
+{{- range .Items}}
+<tr><td>{{if .WIT}}<a href="#{{.ID}}"><code>{{.Name}}</code></a>{{else}}<code>{{.Name}}</code>{{end}}</td><td>{{.Kind}}</td><td>{{.Summary}}</td></tr>
+{{- end}}
+</table>
+{{- range .Items}}{{if .WIT}}
+<h4 id="{{.ID}}"><code>{{.Name}}</code></h4>
+{{- template "doc" .Docs}}
+<pre><code>{{.WIT}}</code></pre>
+{{- end}}{{end}}
+{{- end}}
+{{- end}}
+{{define "doc"}}{{with .}}
+<pre class="docs">{{.}}</pre>
+{{- end}}{{end}}
+`))
@@ -0,0 +1,72 @@
+package docs
+
+import (
+	"strings"
+
+	"github.com/bytecodealliance/wasm-tools-go/internal/stringio"
+	"github.com/bytecodealliance/wasm-tools-go/wit"
+)
+
+// Markdown returns Markdown documentation for pkgs. Each package is a top-level heading,
+// followed by a section for each interface and world in the package. WIT doc comments
+// are Markdown, and are included unmodified.
+func Markdown(pkgs ...*wit.Package) string {
+	var b strings.Builder
+	for i, pkg := range pkgs {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		writeMarkdown(&b, newDocument(pkg))
+	}
+	return b.String()
+}
+
+func writeMarkdown(b *strings.Builder, doc *document) {
+	stringio.Write(b, "# Package `", doc.Name, "`\n")
+	writeMarkdownDocs(b, doc.Docs)
+	for _, s := range doc.Interfaces {
+		writeMarkdownSection(b, &s)
+	}
+	for _, s := range doc.Worlds {
+		writeMarkdownSection(b, &s)
+	}
+}
+
+func writeMarkdownSection(b *strings.Builder, s *section) {
+	stringio.Write(b, "\n## ", titleCase(s.Kind), " `", s.Name, "`\n")
+	writeMarkdownDocs(b, s.Docs)
+	for _, t := range s.Tables {
+		stringio.Write(b, "\n### ", t.Title, "\n\n")
+		b.WriteString("| Name | Kind | Description |\n")
+		b.WriteString("| ---- | ---- | ----------- |\n")
+		for _, i := range t.Items {
+			stringio.Write(b, "| `", i.Name, "` | ", i.Kind, " | ", escapeTableCell(i.Summary()), " |\n")
+		}
+		for _, i := range t.Items {
+			if i.WIT == "" {
+				continue
+			}
+			stringio.Write(b, "\n#### `", i.Name, "`\n")
+			writeMarkdownDocs(b, i.Docs)
+			stringio.Write(b, "\n```wit\n", i.WIT, "\n```\n")
+		}
+	}
+}
+
+func writeMarkdownDocs(b *strings.Builder, docs string) {
+	docs = strings.TrimSpace(docs)
+	if docs != "" {
+		stringio.Write(b, "\n", docs, "\n")
+	}
+}
+
+func escapeTableCell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+func titleCase(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,570 @@
+package extract
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/bytecodealliance/wasm-tools-go/internal/wasm"
+	"github.com/bytecodealliance/wasm-tools-go/wit"
+)
+
+// Sorts of items in a component, as encoded in the component binary format.
+const (
+	sortCore      = 0x00
+	sortFunc      = 0x01
+	sortValue     = 0x02
+	sortType      = 0x03
+	sortComponent = 0x04
+	sortInstance  = 0x05
+)
+
+// scope holds the index spaces of a component or a component or instance type.
+//
+// Entries in the type index space are one of: a [wit.Type] (a primitive type or
+// a named or resource *[wit.TypeDef]), a *valType, *funcType, *componentType, or *instanceType.
+// Entries in other index spaces are a *funcType, *instance, *componentType, or *nested.
+// Entries for items that are irrelevant to WIT, such as core functions, are nil.
+type scope struct {
+	parent     *scope
+	types      []any
+	funcs      []any
+	instances  []any
+	components []any
+	imports    []extern
+	exports    []extern
+}
+
+// extern is a named import or export of a component or instance.
+type extern struct {
+	name string
+	sort byte
+	item any
+}
+
+// valType is a defined value type, such as a record or list, which is converted
+// to a [wit.TypeDef] when referenced. See [decoder.typ].
+type valType struct {
+	code  byte
+	names []string // record fields, variant cases, flags, or enum cases
+	types []any    // record field, variant case, or element types (nil if absent)
+}
+
+// funcType is a component function type.
+type funcType struct {
+	params  []param
+	results []param
+}
+
+type param struct {
+	name string
+	typ  any
+}
+
+// componentType is a component type, which is the type of a world or a component.
+type componentType struct {
+	imports []extern
+	exports []extern
+}
+
+// instanceType is an instance type, which is the type of an interface.
+type instanceType struct {
+	exports []extern
+}
+
+// instance is an instance of an interface, imported or exported by a component.
+type instance struct {
+	exports []extern
+	iface   *wit.Interface // set when the instance is imported or exported
+}
+
+// nested is a component nested within a component, which is decoded when instantiated.
+type nested struct {
+	data   []byte
+	parent *scope
+}
+
+// add adds item to the index space for sort in s.
+func (s *scope) add(sort byte, item any) {
+	switch sort {
+	case sortFunc:
+		s.funcs = append(s.funcs, item)
+	case sortType:
+		s.types = append(s.types, item)
+	case sortComponent:
+		s.components = append(s.components, item)
+	case sortInstance:
+		s.instances = append(s.instances, item)
+	}
+}
+
+// item returns the item at index idx in the index space for sort in s.
+func (s *scope) item(r *wasm.Reader, sort byte, idx uint32) any {
+	var space []any
+	switch sort {
+	case sortFunc:
+		space = s.funcs
+	case sortType:
+		space = s.types
+	case sortComponent:
+		space = s.components
+	case sortInstance:
+		space = s.instances
+	default:
+		return nil
+	}
+	if int(idx) >= len(space) {
+		r.Failf("%s index %d out of range", sortName(sort), idx)
+		return nil
+	}
+	return space[idx]
+}
+
+// readComponent reads the sections of the component binary b into a new scope.
+// Imports named in args are bound to the corresponding item, rather than declared.
+func (d *decoder) readComponent(b []byte, parent *scope, args map[string]any) (*scope, error) {
+	h, sections, err := wasm.ReadSections(b)
+	if err != nil {
+		return nil, err
+	}
+	if !h.IsComponent() {
+		return nil, fmt.Errorf("expected a WebAssembly component, got layer %d", h.Layer)
+	}
+	s := &scope{parent: parent}
+	for _, sec := range sections {
+		r := wasm.NewReader(sec.Data)
+		switch sec.ID {
+		case wasm.SectionComponent:
+			s.components = append(s.components, &nested{data: sec.Data, parent: s})
+			continue
+		case wasm.SectionInstance:
+			d.readVec(r, func() { d.readInstance(r, s) })
+		case wasm.SectionAlias:
+			d.readVec(r, func() { d.readAlias(r, s) })
+		case wasm.SectionType:
+			d.readVec(r, func() { s.types = append(s.types, d.readDefType(r, s)) })
+		case wasm.SectionCanon:
+			d.readVec(r, func() { d.readCanon(r, s) })
+		case wasm.SectionImport:
+			d.readVec(r, func() {
+				name := readExternName(r)
+				sort, desc := d.readExternDesc(r, s)
+				item, ok := args[name]
+				if !ok {
+					item = d.imported(name, sort, d.declare(name, sort, desc))
+				}
+				s.add(sort, item)
+				s.imports = append(s.imports, extern{name, sort, item})
+			})
+		case wasm.SectionExport:
+			d.readVec(r, func() { d.readExport(r, s) })
+		default:
+			// Custom, core, start, and value sections do not describe WIT.
+			continue
+		}
+		if err := r.Err(); err != nil {
+			return nil, fmt.Errorf("section %d: %w", sec.ID, err)
+		}
+		if r.Len() != 0 {
+			return nil, fmt.Errorf("section %d: %d unexpected trailing bytes", sec.ID, r.Len())
+		}
+		if d.err != nil {
+			return nil, d.err
+		}
+	}
+	return s, nil
+}
+
+// readVec reads a vector of items with f, stopping at the first error.
+func (d *decoder) readVec(r *wasm.Reader, f func()) {
+	n := r.U32()
+	for i := uint32(0); i < n && r.Err() == nil && d.err == nil; i++ {
+		f()
+	}
+}
+
+func (d *decoder) readInstance(r *wasm.Reader, s *scope) {
+	switch b := r.Byte(); b {
+	case 0x00: // instantiate
+		c := s.item(r, sortComponent, r.U32())
+		args := make(map[string]any)
+		d.readVec(r, func() {
+			name := r.Name()
+			sort, idx := readSortIdx(r)
+			args[name] = s.item(r, sort, idx)
+		})
+		if r.Err() != nil {
+			return
+		}
+		inst := &instance{}
+		switch c := c.(type) {
+		case *nested:
+			sub, err := d.readComponent(c.data, c.parent, args)
+			if err != nil {
+				d.fail(fmt.Errorf("nested component: %w", err))
+				return
+			}
+			inst.exports = sub.exports
+		case *componentType:
+			inst.exports = slices.Clone(c.exports)
+		}
+		s.instances = append(s.instances, inst)
+	case 0x01: // inline exports
+		inst := &instance{}
+		d.readVec(r, func() {
+			name := readExternName(r)
+			sort, idx := readSortIdx(r)
+			inst.exports = append(inst.exports, extern{name, sort, s.item(r, sort, idx)})
+		})
+		s.instances = append(s.instances, inst)
+	default:
+		r.Failf("unknown instance 0x%02x", b)
+	}
+}
+
+func (d *decoder) readAlias(r *wasm.Reader, s *scope) {
+	sort := readSort(r)
+	switch b := r.Byte(); b {
+	case 0x00: // export of an instance
+		idx := r.U32()
+		name := r.Name()
+		if sort == sortCore {
+			return
+		}
+		inst, _ := s.item(r, sortInstance, idx).(*instance)
+		if inst == nil {
+			s.add(sort, nil)
+			return
+		}
+		i := slices.IndexFunc(inst.exports, func(e extern) bool { return e.name == name })
+		if i < 0 {
+			r.Failf("instance %d has no export %q", idx, name)
+			return
+		}
+		s.add(sort, inst.exports[i].item)
+	case 0x01: // export of a core instance
+		r.U32()
+		r.Name()
+	case 0x02: // outer
+		ct := r.U32()
+		idx := r.U32()
+		outer := s
+		for i := uint32(0); i < ct && outer != nil; i++ {
+			outer = outer.parent
+		}
+		if outer == nil {
+			r.Failf("outer alias count %d out of range", ct)
+			return
+		}
+		s.add(sort, outer.item(r, sort, idx))
+	default:
+		r.Failf("unknown alias target 0x%02x", b)
+	}
+}
+
+func (d *decoder) readCanon(r *wasm.Reader, s *scope) {
+	switch b := r.Byte(); b {
+	case 0x00: // lift
+		r.Byte()
+		r.U32()
+		readCanonOpts(r)
+		ft := s.item(r, sortType, r.U32())
+		s.funcs = append(s.funcs, ft)
+	case 0x01: // lower
+		r.Byte()
+		r.U32()
+		readCanonOpts(r)
+	case 0x02, 0x03, 0x04, 0x07: // resource.new, resource.drop, resource.rep, resource.drop async
+		r.U32()
+	default:
+		r.Failf("unsupported canonical function 0x%02x", b)
+	}
+}
+
+func readCanonOpts(r *wasm.Reader) {
+	n := r.U32()
+	for i := uint32(0); i < n && r.Err() == nil; i++ {
+		switch b := r.Byte(); b {
+		case 0x00, 0x01, 0x02, 0x06: // string encodings, async
+		case 0x03, 0x04, 0x05, 0x07: // memory, realloc, post-return, callback
+			r.U32()
+		default:
+			r.Failf("unknown canonical option 0x%02x", b)
+		}
+	}
+}
+
+func (d *decoder) readExport(r *wasm.Reader, s *scope) {
+	name := readExternName(r)
+	sort, idx := readSortIdx(r)
+	item := s.item(r, sort, idx)
+	if r.Byte() == 0x01 {
+		// The type ascription does not change the identity of the exported item.
+		d.readExternDesc(r, s)
+	}
+	if r.Err() != nil || sort == sortCore {
+		return
+	}
+	item = d.export(name, sort, item)
+	s.add(sort, item)
+	s.exports = append(s.exports, extern{name, sort, item})
+}
+
+// readDefType reads a type definition into an entry in the type index space of s.
+func (d *decoder) readDefType(r *wasm.Reader, s *scope) any {
+	b := r.Byte()
+	switch b {
+	case 0x40, 0x43: // function, async function
+		ft := &funcType{params: readParams(r, s)}
+		switch c := r.Byte(); c {
+		case 0x00:
+			ft.results = []param{{typ: readValType(r, s)}}
+		case 0x01:
+			ft.results = readParams(r, s)
+		default:
+			r.Failf("unknown result list 0x%02x", c)
+		}
+		return ft
+	case 0x41: // component
+		child := &scope{parent: s}
+		d.readVec(r, func() { d.readDecl(r, child, true) })
+		return &componentType{imports: child.imports, exports: child.exports}
+	case 0x42: // instance
+		child := &scope{parent: s}
+		d.readVec(r, func() { d.readDecl(r, child, false) })
+		return &instanceType{exports: child.exports}
+	case 0x3f, 0x3e: // resource, async resource
+		if rep := r.Byte(); rep != 0x7f {
+			r.Failf("unknown resource representation 0x%02x", rep)
+		}
+		if r.Byte() == 0x01 {
+			r.U32() // destructor
+		}
+		if b == 0x3e && r.Byte() == 0x01 {
+			r.U32() // callback
+		}
+		return &wit.TypeDef{Kind: &wit.Resource{}}
+	}
+	if t := primitive(b); t != nil {
+		return t
+	}
+	return readValTypeDef(r, s, b)
+}
+
+// readDecl reads a declaration in a component or instance type into scope s.
+func (d *decoder) readDecl(r *wasm.Reader, s *scope, component bool) {
+	switch b := r.Byte(); b {
+	case 0x00:
+		r.Failf("unsupported core type declaration")
+	case 0x01:
+		s.types = append(s.types, d.readDefType(r, s))
+	case 0x02:
+		d.readAlias(r, s)
+	case 0x03:
+		if !component {
+			r.Failf("import declaration in instance type")
+			return
+		}
+		name := readExternName(r)
+		sort, desc := d.readExternDesc(r, s)
+		item := d.imported(name, sort, d.declare(name, sort, desc))
+		s.add(sort, item)
+		s.imports = append(s.imports, extern{name, sort, item})
+	case 0x04:
+		name := readExternName(r)
+		sort, desc := d.readExternDesc(r, s)
+		item := d.export(name, sort, d.declare(name, sort, desc))
+		s.add(sort, item)
+		s.exports = append(s.exports, extern{name, sort, item})
+	default:
+		r.Failf("unknown declaration 0x%02x", b)
+	}
+}
+
+// bound is the type bound of an imported or exported type.
+type bound struct {
+	eq any // the type an imported or exported type is equal to, or nil for a resource
+}
+
+// readExternDesc reads an extern descriptor, returning its sort and a description of
+// the imported or exported item: a *funcType, *componentType, *instanceType, or bound.
+func (d *decoder) readExternDesc(r *wasm.Reader, s *scope) (byte, any) {
+	switch b := r.Byte(); b {
+	case 0x00: // core module
+		r.Byte()
+		r.U32()
+		return sortCore, nil
+	case 0x01:
+		return sortFunc, s.item(r, sortType, r.U32())
+	case 0x02: // value
+		if r.Byte() == 0x00 {
+			r.U32()
+		} else {
+			readValType(r, s)
+		}
+		return sortValue, nil
+	case 0x03:
+		switch c := r.Byte(); c {
+		case 0x00:
+			return sortType, bound{eq: s.item(r, sortType, r.U32())}
+		case 0x01:
+			return sortType, bound{}
+		default:
+			r.Failf("unknown type bound 0x%02x", c)
+		}
+	case 0x04:
+		return sortComponent, s.item(r, sortType, r.U32())
+	case 0x05:
+		return sortInstance, s.item(r, sortType, r.U32())
+	default:
+		r.Failf("unknown extern descriptor 0x%02x", b)
+	}
+	return sortCore, nil
+}
+
+func readSort(r *wasm.Reader) byte {
+	sort := r.Byte()
+	if sort == sortCore {
+		r.Byte()
+	}
+	return sort
+}
+
+func readSortIdx(r *wasm.Reader) (byte, uint32) {
+	sort := readSort(r)
+	return sort, r.U32()
+}
+
+func readExternName(r *wasm.Reader) string {
+	if b := r.Byte(); b != 0x00 && b != 0x01 {
+		r.Failf("unknown extern name 0x%02x", b)
+		return ""
+	}
+	return r.Name()
+}
+
+func readParams(r *wasm.Reader, s *scope) []param {
+	var params []param
+	n := r.U32()
+	for i := uint32(0); i < n && r.Err() == nil; i++ {
+		name := r.Name()
+		params = append(params, param{name, readValType(r, s)})
+	}
+	return params
+}
+
+// readValType reads a value type, which is either a primitive type or an index
+// into the type index space of s.
+func readValType(r *wasm.Reader, s *scope) any {
+	if t := primitive(r.Peek()); t != nil {
+		r.Byte()
+		return t
+	}
+	idx := r.S33()
+	if idx < 0 {
+		r.Failf("unknown value type %d", idx)
+		return nil
+	}
+	return s.item(r, sortType, uint32(idx))
+}
+
+// readOptionalValType reads an optional value type, returning nil if absent.
+func readOptionalValType(r *wasm.Reader, s *scope) any {
+	if r.Byte() == 0x01 {
+		return readValType(r, s)
+	}
+	return nil
+}
+
+// readValTypeDef reads a defined value type with type code b.
+func readValTypeDef(r *wasm.Reader, s *scope, b byte) any {
+	v := &valType{code: b}
+	switch b {
+	case 0x72: // record
+		for _, p := range readParams(r, s) {
+			v.names = append(v.names, p.name)
+			v.types = append(v.types, p.typ)
+		}
+	case 0x71: // variant
+		n := r.U32()
+		for i := uint32(0); i < n && r.Err() == nil; i++ {
+			v.names = append(v.names, r.Name())
+			v.types = append(v.types, readOptionalValType(r, s))
+			if r.Byte() == 0x01 {
+				r.U32() // refines
+			}
+		}
+	case 0x70, 0x6b: // list, option
+		v.types = []any{readValType(r, s)}
+	case 0x6f: // tuple
+		n := r.U32()
+		for i := uint32(0); i < n && r.Err() == nil; i++ {
+			v.types = append(v.types, readValType(r, s))
+		}
+	case 0x6e, 0x6d: // flags, enum
+		n := r.U32()
+		for i := uint32(0); i < n && r.Err() == nil; i++ {
+			v.names = append(v.names, r.Name())
+		}
+	case 0x6a: // result
+		v.types = []any{readOptionalValType(r, s), readOptionalValType(r, s)}
+	case 0x69, 0x68: // own, borrow
+		v.types = []any{s.item(r, sortType, r.U32())}
+	case 0x66, 0x65: // stream, future
+		v.types = []any{readOptionalValType(r, s)}
+	default:
+		r.Failf("unsupported type 0x%02x", b)
+		return nil
+	}
+	return v
+}
+
+// primitive returns the primitive [wit.Type] for type code b, or nil if b is not a primitive type.
+func primitive(b byte) wit.Type {
+	switch b {
+	case 0x7f:
+		return wit.Bool{}
+	case 0x7e:
+		return wit.S8{}
+	case 0x7d:
+		return wit.U8{}
+	case 0x7c:
+		return wit.S16{}
+	case 0x7b:
+		return wit.U16{}
+	case 0x7a:
+		return wit.S32{}
+	case 0x79:
+		return wit.U32{}
+	case 0x78:
+		return wit.S64{}
+	case 0x77:
+		return wit.U64{}
+	case 0x76:
+		return wit.F32{}
+	case 0x75:
+		return wit.F64{}
+	case 0x74:
+		return wit.Char{}
+	case 0x73:
+		return wit.String{}
+	}
+	return nil
+}
+
+func sortName(sort byte) string {
+	switch sort {
+	case sortCore:
+		return "core"
+	case sortFunc:
+		return "func"
+	case sortValue:
+		return "value"
+	case sortType:
+		return "type"
+	case sortComponent:
+		return "component"
+	case sortInstance:
+		return "instance"
+	}
+	return "unknown"
+}
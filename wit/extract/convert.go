@@ -0,0 +1,368 @@
+package extract
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bytecodealliance/wasm-tools-go/wit"
+)
+
+// declare returns a new item of sort described by desc, imported or exported as name.
+func (d *decoder) declare(name string, sort byte, desc any) any {
+	switch sort {
+	case sortFunc:
+		if _, ok := desc.(*funcType); !ok {
+			d.fail(fmt.Errorf("func %s: type is not a function type", name))
+		}
+		return desc
+	case sortType:
+		b, _ := desc.(bound)
+		if b.eq == nil {
+			return &wit.TypeDef{Name: &name, Kind: &wit.Resource{}}
+		}
+		return d.namedType(name, b.eq)
+	case sortInstance:
+		t, ok := desc.(*instanceType)
+		if !ok {
+			d.fail(fmt.Errorf("instance %s: type is not an instance type", name))
+			return nil
+		}
+		return &instance{exports: append([]extern(nil), t.exports...)}
+	case sortComponent:
+		if _, ok := desc.(*componentType); !ok {
+			d.fail(fmt.Errorf("component %s: type is not a component type", name))
+		}
+		return desc
+	}
+	return nil
+}
+
+// imported is called when item of sort is imported as name.
+// Instances imported with a qualified interface name define or extend an [wit.Interface].
+func (d *decoder) imported(name string, sort byte, item any) any {
+	if inst, ok := item.(*instance); ok && sort == sortInstance && isQualified(name) {
+		d.resolveInterface(name, inst)
+	}
+	return item
+}
+
+// export is called when item of sort is exported as name, returning the exported item.
+// Exported value types are named, instances exported with a qualified interface name
+// define or extend an [wit.Interface], and component types exported with a qualified
+// world name define a [wit.World].
+func (d *decoder) export(name string, sort byte, item any) any {
+	switch sort {
+	case sortType:
+		switch item.(type) {
+		case wit.Type, *valType:
+			return d.namedType(name, item)
+		}
+	case sortInstance:
+		if inst, ok := item.(*instance); ok && isQualified(name) {
+			d.resolveInterface(name, inst)
+		}
+	case sortComponent:
+		if ct, ok := item.(*componentType); ok && isQualified(name) {
+			d.defineWorld(name, ct)
+		}
+	}
+	return item
+}
+
+// namedType returns a named [wit.TypeDef] for type entry target.
+// If target is an unowned TypeDef with the same name, or an anonymous resource, it is returned.
+// Otherwise, the returned TypeDef is new, and its Kind is set when decoding is finished,
+// after any types have been replaced while merging interfaces.
+func (d *decoder) namedType(name string, target any) *wit.TypeDef {
+	if t, ok := target.(*wit.TypeDef); ok && t.Owner == nil {
+		if t.Name != nil && *t.Name == name {
+			return t
+		}
+		if _, ok := t.Kind.(*wit.Resource); ok && t.Name == nil {
+			t.Name = &name
+			return t
+		}
+	}
+	switch target.(type) {
+	case wit.Type, *valType:
+	default:
+		d.fail(fmt.Errorf("type %s is not a value type", name))
+		return nil
+	}
+	t := &wit.TypeDef{Name: &name}
+	d.pendingTypes = append(d.pendingTypes, pendingType{t, target})
+	return t
+}
+
+// resolveInterface defines or extends the [wit.Interface] with qualified name
+// with the exports of inst. A foreign interface may be declared more than once,
+// with a subset of its types or functions each time.
+func (d *decoder) resolveInterface(name string, inst *instance) {
+	iface := d.interfaces[name]
+	if iface == nil {
+		id, err := wit.ParseIdent(name)
+		if err != nil {
+			d.fail(fmt.Errorf("interface %s: %w", name, err))
+			return
+		}
+		iface = &wit.Interface{Name: &id.Extension, Package: d.pkg(id)}
+		iface.Package.Interfaces.Set(id.Extension, iface)
+		d.interfaces[name] = iface
+		d.res.Interfaces = append(d.res.Interfaces, iface)
+	}
+	d.addToInterface(iface, inst)
+}
+
+// addToInterface adds the types and functions exported by inst to iface,
+// if not already present. Types already present replace the corresponding types of inst.
+func (d *decoder) addToInterface(iface *wit.Interface, inst *instance) {
+	inst.iface = iface
+	for i, e := range inst.exports {
+		if e.sort != sortType {
+			continue
+		}
+		if t := iface.TypeDefs.Get(e.name); t != nil {
+			if u, ok := e.item.(*wit.TypeDef); ok && u != t {
+				d.replaced[u] = t
+			}
+			inst.exports[i].item = t
+			continue
+		}
+		t := d.namedType(e.name, e.item)
+		if t == nil {
+			return
+		}
+		t.Owner = iface
+		iface.TypeDefs.Set(e.name, t)
+		inst.exports[i].item = t
+	}
+	for _, e := range inst.exports {
+		switch e.sort {
+		case sortType:
+		case sortFunc:
+			if iface.Functions.Get(e.name) == nil {
+				iface.Functions.Set(e.name, d.function(e.name, e.item, iface.TypeDefs.Get))
+			}
+		default:
+			d.fail(fmt.Errorf("unsupported %s export %s in interface", sortName(e.sort), e.name))
+		}
+	}
+}
+
+// defineWorld defines a [wit.World] with qualified name from component type ct.
+func (d *decoder) defineWorld(name string, ct *componentType) {
+	id, err := wit.ParseIdent(name)
+	if err != nil {
+		d.fail(fmt.Errorf("world %s: %w", name, err))
+		return
+	}
+	pkg := d.pkg(id)
+	w := d.newWorld(pkg, id.Extension, ct.imports, ct.exports)
+	pkg.Worlds.Set(w.Name, w)
+}
+
+// newWorld returns a new [wit.World] in pkg with imports and exports.
+func (d *decoder) newWorld(pkg *wit.Package, name string, imports, exports []extern) *wit.World {
+	w := &wit.World{Name: name, Package: pkg}
+	d.res.Worlds = append(d.res.Worlds, w)
+
+	// Types are declared first, so resource functions can refer to them.
+	types := make(map[string]*wit.TypeDef)
+	for _, e := range imports {
+		if e.sort == sortType {
+			t := d.namedType(e.name, e.item)
+			if t == nil {
+				return w
+			}
+			t.Owner = w
+			types[e.name] = t
+		}
+	}
+	lookup := func(name string) *wit.TypeDef { return types[name] }
+
+	item := func(e extern, export bool) (string, wit.WorldItem) {
+		switch e.sort {
+		case sortInstance:
+			inst, _ := e.item.(*instance)
+			if inst == nil {
+				break
+			}
+			if inst.iface == nil {
+				// An inline interface
+				iface := &wit.Interface{Package: pkg}
+				d.res.Interfaces = append(d.res.Interfaces, iface)
+				d.addToInterface(iface, inst)
+				return e.name, &wit.InterfaceRef{Interface: iface}
+			}
+			for i, iface := range d.res.Interfaces {
+				if iface == inst.iface {
+					return fmt.Sprintf("interface-%d", i), &wit.InterfaceRef{Interface: iface}
+				}
+			}
+		case sortFunc:
+			return e.name, d.function(e.name, e.item, lookup)
+		case sortType:
+			if !export {
+				return e.name, types[e.name]
+			}
+		}
+		d.fail(fmt.Errorf("world %s: unsupported %s %s", name, sortName(e.sort), e.name))
+		return "", nil
+	}
+	for _, e := range imports {
+		if key, item := item(e, false); item != nil {
+			w.Imports.Set(key, item)
+		}
+	}
+	for _, e := range exports {
+		if key, item := item(e, true); item != nil {
+			w.Exports.Set(key, item)
+		}
+	}
+	return w
+}
+
+// function returns a new [wit.Function] with name and function type ft.
+// Resource types of constructors, methods, and static functions are found with lookup.
+// The parameters and results of the Function are set when decoding is finished.
+func (d *decoder) function(name string, ft any, lookup func(string) *wit.TypeDef) *wit.Function {
+	f := &wit.Function{Name: name, Kind: &wit.Freestanding{}}
+	resource := func(prefix string) *wit.TypeDef {
+		rname, _, _ := strings.Cut(strings.TrimPrefix(name, prefix), ".")
+		t := lookup(rname)
+		if t == nil {
+			d.fail(fmt.Errorf("function %s: unknown resource %s", name, rname))
+		}
+		return t
+	}
+	switch {
+	case strings.HasPrefix(name, "[constructor]"):
+		f.Kind = &wit.Constructor{Type: resource("[constructor]")}
+	case strings.HasPrefix(name, "[method]"):
+		f.Kind = &wit.Method{Type: resource("[method]")}
+	case strings.HasPrefix(name, "[static]"):
+		f.Kind = &wit.Static{Type: resource("[static]")}
+	}
+	t, ok := ft.(*funcType)
+	if !ok {
+		d.fail(fmt.Errorf("function %s: type is not a function type", name))
+		return f
+	}
+	d.pendingFuncs = append(d.pendingFuncs, pendingFunc{f, t})
+	return f
+}
+
+// params converts params into [wit.Param] values.
+func (d *decoder) params(params []param) []wit.Param {
+	var out []wit.Param
+	for _, p := range params {
+		out = append(out, wit.Param{Name: p.name, Type: d.typ(p.typ)})
+	}
+	return out
+}
+
+// typ returns the [wit.Type] for type entry t.
+// Anonymous value types are converted to a [wit.TypeDef] once.
+func (d *decoder) typ(t any) wit.Type {
+	switch t := t.(type) {
+	case *wit.TypeDef:
+		return d.replace(t)
+	case *valType:
+		if td := d.types[t]; td != nil {
+			return td
+		}
+		td := &wit.TypeDef{Kind: d.kind(t)}
+		d.types[t] = td
+		return td
+	case wit.Type:
+		return t
+	}
+	d.fail(fmt.Errorf("%T is not a value type", t))
+	return nil
+}
+
+// optionalType returns the [wit.Type] for type entry t, or nil if t is nil.
+func (d *decoder) optionalType(t any) wit.Type {
+	if t == nil {
+		return nil
+	}
+	return d.typ(t)
+}
+
+// replace returns the [wit.TypeDef] that replaces t, or t if none.
+func (d *decoder) replace(t *wit.TypeDef) *wit.TypeDef {
+	for d.replaced[t] != nil {
+		t = d.replaced[t]
+	}
+	return t
+}
+
+// kindOf returns the [wit.TypeDefKind] of a named type equal to type entry t.
+func (d *decoder) kindOf(t any) wit.TypeDefKind {
+	if v, ok := t.(*valType); ok {
+		return d.kind(v)
+	}
+	return d.typ(t)
+}
+
+// kind converts defined value type v into a [wit.TypeDefKind].
+func (d *decoder) kind(v *valType) wit.TypeDefKind {
+	switch v.code {
+	case 0x72:
+		r := &wit.Record{}
+		for i, name := range v.names {
+			r.Fields = append(r.Fields, wit.Field{Name: name, Type: d.typ(v.types[i])})
+		}
+		return r
+	case 0x71:
+		variant := &wit.Variant{}
+		for i, name := range v.names {
+			variant.Cases = append(variant.Cases, wit.Case{Name: name, Type: d.optionalType(v.types[i])})
+		}
+		return variant
+	case 0x70:
+		return &wit.List{Type: d.typ(v.types[0])}
+	case 0x6f:
+		t := &wit.Tuple{}
+		for _, u := range v.types {
+			t.Types = append(t.Types, d.typ(u))
+		}
+		return t
+	case 0x6e:
+		f := &wit.Flags{}
+		for _, name := range v.names {
+			f.Flags = append(f.Flags, wit.Flag{Name: name})
+		}
+		return f
+	case 0x6d:
+		e := &wit.Enum{}
+		for _, name := range v.names {
+			e.Cases = append(e.Cases, wit.EnumCase{Name: name})
+		}
+		return e
+	case 0x6b:
+		return &wit.Option{Type: d.typ(v.types[0])}
+	case 0x6a:
+		return &wit.Result{OK: d.optionalType(v.types[0]), Err: d.optionalType(v.types[1])}
+	case 0x69, 0x68:
+		t, ok := v.types[0].(*wit.TypeDef)
+		if !ok {
+			d.fail(fmt.Errorf("handle to %T, expected a resource", v.types[0]))
+			return nil
+		}
+		if v.code == 0x69 {
+			return &wit.Own{Type: d.replace(t)}
+		}
+		return &wit.Borrow{Type: d.replace(t)}
+	case 0x66:
+		return &wit.Stream{Element: d.optionalType(v.types[0])}
+	case 0x65:
+		return &wit.Future{Type: d.optionalType(v.types[0])}
+	}
+	return nil
+}
+
+// isQualified returns true if name is a qualified interface or world name, e.g. wasi:io/streams.
+func isQualified(name string) bool {
+	return strings.Contains(name, ":") && strings.Contains(name, "/")
+}
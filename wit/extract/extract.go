@@ -0,0 +1,232 @@
+// Package extract decodes [WIT] from WebAssembly binaries, without [wasm-tools].
+//
+// It decodes WIT packages encoded as WebAssembly components, such as those published
+// to OCI registries or created with wasm-tools component wit --wasm, the WIT world of a
+// WebAssembly component, and the WIT metadata embedded in the component-type custom
+// sections of core WebAssembly modules.
+//
+// The binary encoding of WIT does not include documentation or @since and @unstable
+// attributes, so the decoded [wit.Resolve] does not include them.
+//
+// [WIT]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/WIT.md
+// [wasm-tools]: https://crates.io/crates/wasm-tools
+package extract
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/bytecodealliance/wasm-tools-go/internal/wasm"
+	"github.com/bytecodealliance/wasm-tools-go/wit"
+)
+
+// Load reads a WebAssembly binary from path and decodes it with [Decode].
+// If path is "" or "-", it reads from os.Stdin.
+func Load(path string) (*wit.Resolve, error) {
+	var b []byte
+	var err error
+	if path == "" || path == "-" {
+		b, err = io.ReadAll(os.Stdin)
+	} else {
+		b, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return Decode(b)
+}
+
+// Decode decodes the WIT in WebAssembly binary b into a [wit.Resolve].
+//
+// If b is a component that encodes one or more WIT packages, Decode returns the packages.
+// If b is any other component, Decode returns the world of the component, which is
+// named root:component/root, like the world reported by wasm-tools component wit.
+// If b is a core module, Decode returns the worlds in its component-type custom sections.
+//
+// The returned Resolve is normalized and valid. See [wit.Resolve.Normalize] and [wit.Resolve.Validate].
+func Decode(b []byte) (*wit.Resolve, error) {
+	h, sections, err := wasm.ReadSections(b)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &decoder{
+		res:        &wit.Resolve{},
+		packages:   make(map[string]*wit.Package),
+		interfaces: make(map[string]*wit.Interface),
+		types:      make(map[*valType]*wit.TypeDef),
+		replaced:   make(map[*wit.TypeDef]*wit.TypeDef),
+	}
+
+	if h.IsComponent() {
+		s, err := d.readComponent(b, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		if !isPackage(s) {
+			pkg := d.pkg(wit.Ident{Namespace: "root", Package: "component"})
+			w := d.newWorld(pkg, "root", s.imports, s.exports)
+			pkg.Worlds.Set(w.Name, w)
+		}
+	} else {
+		var found bool
+		for _, sec := range sections {
+			if sec.ID != wasm.SectionCustom {
+				continue
+			}
+			name, payload, err := wasm.CustomSection(sec.Data)
+			if err != nil {
+				return nil, err
+			}
+			if !strings.HasPrefix(name, "component-type") {
+				continue
+			}
+			found = true
+			_, err = d.readComponent(payload, nil, nil)
+			if err != nil {
+				return nil, fmt.Errorf("custom section %s: %w", name, err)
+			}
+		}
+		if !found {
+			return nil, errors.New("WebAssembly module has no component-type custom section")
+		}
+	}
+
+	return d.finish()
+}
+
+// isPackage returns true if the top-level component scope s encodes WIT packages,
+// which are exported as component types.
+func isPackage(s *scope) bool {
+	if len(s.imports) != 0 || len(s.exports) == 0 {
+		return false
+	}
+	for _, e := range s.exports {
+		if _, ok := e.item.(*componentType); !ok || e.sort != sortType {
+			return false
+		}
+	}
+	return true
+}
+
+type decoder struct {
+	res          *wit.Resolve
+	packages     map[string]*wit.Package   // indexed by package name
+	interfaces   map[string]*wit.Interface // indexed by qualified interface name
+	types        map[*valType]*wit.TypeDef // anonymous types
+	replaced     map[*wit.TypeDef]*wit.TypeDef
+	pendingTypes []pendingType
+	pendingFuncs []pendingFunc
+	err          error
+}
+
+// pendingType is a named [wit.TypeDef] whose Kind is set from type entry target.
+type pendingType struct {
+	t      *wit.TypeDef
+	target any
+}
+
+// pendingFunc is a [wit.Function] whose parameters and results are set from a function type.
+type pendingFunc struct {
+	f  *wit.Function
+	ft *funcType
+}
+
+func (d *decoder) fail(err error) {
+	if d.err == nil {
+		d.err = err
+	}
+}
+
+// pkg returns the [wit.Package] for the package in id, creating it if necessary.
+func (d *decoder) pkg(id wit.Ident) *wit.Package {
+	id.Extension = ""
+	name := id.String()
+	pkg := d.packages[name]
+	if pkg == nil {
+		pkg = &wit.Package{Name: id}
+		d.packages[name] = pkg
+		d.res.Packages = append(d.res.Packages, pkg)
+	}
+	return pkg
+}
+
+// finish converts the pending types and functions, then sorts and validates the Resolve.
+func (d *decoder) finish() (*wit.Resolve, error) {
+	for _, p := range d.pendingTypes {
+		p.t.Kind = d.kindOf(p.target)
+	}
+	for _, p := range d.pendingFuncs {
+		p.f.Params = d.params(p.ft.params)
+		p.f.Results = d.params(p.ft.results)
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	d.sortPackages()
+
+	// Named types are listed in the order they are declared, followed by any
+	// anonymous types. Normalize sorts them topologically and drops duplicates.
+	for _, i := range d.res.Interfaces {
+		i.TypeDefs.All()(func(_ string, t *wit.TypeDef) bool {
+			d.res.TypeDefs = append(d.res.TypeDefs, t)
+			return true
+		})
+	}
+	for _, w := range d.res.Worlds {
+		w.Imports.All()(func(_ string, item wit.WorldItem) bool {
+			if t, ok := item.(*wit.TypeDef); ok {
+				d.res.TypeDefs = append(d.res.TypeDefs, t)
+			}
+			return true
+		})
+	}
+	d.res.Normalize()
+
+	err := d.res.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return d.res, nil
+}
+
+// sortPackages sorts the packages in the Resolve so each package follows
+// the packages it depends on, otherwise preserving the order of packages.
+func (d *decoder) sortPackages() {
+	deps := make(map[*wit.Package][]*wit.Package)
+	addDeps := func(pkg *wit.Package, node wit.Node) {
+		for _, i := range wit.DependencyGraph(node).Interfaces() {
+			if i.Package != nil && i.Package != pkg {
+				deps[pkg] = append(deps[pkg], i.Package)
+			}
+		}
+	}
+	for _, i := range d.res.Interfaces {
+		addDeps(i.Package, i)
+	}
+	for _, w := range d.res.Worlds {
+		addDeps(w.Package, w)
+	}
+
+	var sorted []*wit.Package
+	seen := make(map[*wit.Package]bool)
+	var visit func(pkg *wit.Package)
+	visit = func(pkg *wit.Package) {
+		if seen[pkg] {
+			return
+		}
+		seen[pkg] = true
+		for _, dep := range deps[pkg] {
+			visit(dep)
+		}
+		sorted = append(sorted, pkg)
+	}
+	for _, pkg := range d.res.Packages {
+		visit(pkg)
+	}
+	d.res.Packages = sorted
+}
@@ -0,0 +1,321 @@
+package extract
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bytecodealliance/wasm-tools-go/wit"
+)
+
+// Helpers to hand-assemble WebAssembly binaries.
+
+func cat(parts ...[]byte) []byte {
+	var b []byte
+	for _, p := range parts {
+		b = append(b, p...)
+	}
+	return b
+}
+
+func u32(n int) []byte {
+	var b []byte
+	for {
+		c := byte(n & 0x7f)
+		n >>= 7
+		if n != 0 {
+			c |= 0x80
+		}
+		b = append(b, c)
+		if n == 0 {
+			return b
+		}
+	}
+}
+
+func str(s string) []byte {
+	return cat(u32(len(s)), []byte(s))
+}
+
+func vec(items ...[]byte) []byte {
+	return cat(u32(len(items)), cat(items...))
+}
+
+func section(id byte, contents ...[]byte) []byte {
+	b := cat(contents...)
+	return cat([]byte{id}, u32(len(b)), b)
+}
+
+func component(sections ...[]byte) []byte {
+	return cat([]byte("\x00asm\x0d\x00\x01\x00"), cat(sections...))
+}
+
+func module(sections ...[]byte) []byte {
+	return cat([]byte("\x00asm\x01\x00\x00\x00"), cat(sections...))
+}
+
+// Type and declaration encodings.
+
+func b(bytes ...byte) []byte { return bytes }
+
+func exportDecl(name string, desc ...byte) []byte {
+	return cat(b(0x04, 0x00), str(name), desc)
+}
+
+func importDecl(name string, desc ...byte) []byte {
+	return cat(b(0x03, 0x00), str(name), desc)
+}
+
+func typeDecl(t ...[]byte) []byte {
+	return cat(b(0x01), cat(t...))
+}
+
+func field(name string, t byte) []byte {
+	return cat(str(name), b(t))
+}
+
+// examplePackage encodes:
+//
+//	package example:foo@0.1.0;
+//
+//	interface types {
+//		resource r {
+//			constructor();
+//			get: func() -> u32;
+//		}
+//		record point { x: s32, y: s32 }
+//		enum color { red, green }
+//		make: func(p: point) -> list<point>;
+//	}
+//
+//	world app {
+//		use types.{color};
+//		import types;
+//		export run: func(c: color) -> result<string>;
+//	}
+var examplePackage = component(
+	section(7, vec(cat(b(0x41), vec(
+		typeDecl(b(0x42), vec(
+			exportDecl("r", 0x03, 0x01),                                // type 0
+			typeDecl(b(0x72), vec(field("x", 0x7a), field("y", 0x7a))), // type 1
+			exportDecl("point", 0x03, 0x00, 1),                         // type 2
+			typeDecl(b(0x6d), vec(str("red"), str("green"))),           // type 3
+			exportDecl("color", 0x03, 0x00, 3),                         // type 4
+			typeDecl(b(0x69, 0)),                                       // type 5: own<r>
+			typeDecl(b(0x40), vec(), b(0x00, 5)),                       // type 6
+			exportDecl("[constructor]r", 0x01, 6),                      //
+			typeDecl(b(0x68, 0)),                                       // type 7: borrow<r>
+			typeDecl(b(0x40), vec(field("self", 7)), b(0x00, 0x79)),    // type 8
+			exportDecl("[method]r.get", 0x01, 8),                       //
+			typeDecl(b(0x70, 2)),                                       // type 9: list<point>
+			typeDecl(b(0x40), vec(field("p", 2)), b(0x00, 9)),          // type 10
+			exportDecl("make", 0x01, 10),                               //
+		)),
+		exportDecl("example:foo/types@0.1.0", 0x05, 0),
+	)))),
+	section(11, vec(cat(b(0x00), str("types"), b(0x03, 0, 0x00)))),
+	section(7, vec(cat(b(0x41), vec(
+		typeDecl(b(0x41), vec(
+			typeDecl(b(0x42), vec( // type 0
+				typeDecl(b(0x6d), vec(str("red"), str("green"))),
+				exportDecl("color", 0x03, 0x00, 0),
+			)),
+			importDecl("example:foo/types@0.1.0", 0x05, 0),
+			cat(b(0x02, 0x03, 0x00, 0), str("color")),         // type 1: alias export 0 "color"
+			importDecl("color", 0x03, 0x00, 1),                // type 2
+			typeDecl(b(0x6a, 0x01, 0x73, 0x00)),               // type 3: result<string>
+			typeDecl(b(0x40), vec(field("c", 2)), b(0x00, 3)), // type 4
+			exportDecl("run", 0x01, 4),
+		)),
+		exportDecl("example:foo/app@0.1.0", 0x04, 0),
+	)))),
+	section(11, vec(cat(b(0x00), str("app"), b(0x03, 1, 0x00)))),
+)
+
+const exampleWIT = `package example:foo@0.1.0;
+
+interface types {
+	resource r {
+		constructor();
+		get: func() -> u32;
+	}
+	record point { x: s32, y: s32 }
+	enum color { red, green }
+	make: func(p: point) -> list<point>;
+}
+
+world app {
+	import types;
+	use types.{color};
+	export run: func(c: color) -> result<string>;
+}
+`
+
+func TestDecodePackage(t *testing.T) {
+	res, err := Decode(examplePackage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := res.WIT(nil, ""), exampleWIT; got != want {
+		t.Errorf("WIT:\n%s\nexpected:\n%s", got, want)
+	}
+
+	face := res.Interfaces[0]
+	r := face.TypeDefs.Get("r")
+	get := face.Functions.Get("[method]r.get")
+	if got, want := get.Type(), wit.Type(r); got != want {
+		t.Errorf("method type: %v, expected %v", got, want)
+	}
+	color := face.TypeDefs.Get("color")
+	if got, want := res.Worlds[0].Imports.Get("color").(*wit.TypeDef).Kind, wit.TypeDefKind(color); got != want {
+		t.Errorf("world type color: %v, expected alias of %v", got, want)
+	}
+}
+
+func TestDecodeModule(t *testing.T) {
+	payload := examplePackage
+	custom := section(0, str("component-type:app"), payload)
+	res, err := Decode(module(custom))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Worlds) != 1 || res.Worlds[0].Name != "app" {
+		t.Errorf("Decode: %d worlds, expected world app", len(res.Worlds))
+	}
+
+	_, err = Decode(module())
+	if err == nil {
+		t.Error("Decode: expected error for module without component-type custom section")
+	}
+}
+
+func TestDecodeComponent(t *testing.T) {
+	// A component that imports example:log/logger@1.0.0 with log: func(msg: string),
+	// and exports greet: func(name: string) -> string, lifted from a core function.
+	bin := component(
+		section(7, vec(
+			cat(b(0x42), vec( // type 0
+				typeDecl(b(0x40), vec(field("msg", 0x73)), b(0x01, 0x00)),
+				exportDecl("log", 0x01, 0),
+			)),
+			cat(b(0x40), vec(field("name", 0x73)), b(0x00, 0x73)), // type 1
+		)),
+		section(10, vec(cat(b(0x00), str("example:log/logger@1.0.0"), b(0x05, 0)))),
+		section(6, vec(cat(b(0x01, 0x00, 0), str("log")))),             // func 0
+		section(8, vec(b(0x00, 0x00, 0, 0x01, 0x03, 0, 1))),            // func 1
+		section(11, vec(cat(b(0x00), str("greet"), b(0x01, 1, 0x00)))), // func 2
+	)
+	res, err := Decode(bin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const want = `package example:log@1.0.0;
+
+interface logger {
+	log: func(msg: string);
+}
+
+package root:component {
+	world root {
+		import example:log/logger@1.0.0;
+		export greet: func(name: string) -> string;
+	}
+}
+`
+	if got := res.WIT(nil, ""); got != want {
+		t.Errorf("WIT:\n%s\nexpected:\n%s", got, want)
+	}
+}
+
+func TestDecodeForeignInterface(t *testing.T) {
+	// Interface example:cli/stdin uses input-stream from wasi:io/streams@0.2.0,
+	// which is declared again with more types and functions by world example:cli/command.
+	bin := component(
+		section(7, vec(cat(b(0x41), vec(
+			typeDecl(b(0x42), vec( // type 0
+				exportDecl("input-stream", 0x03, 0x01),
+			)),
+			importDecl("wasi:io/streams@0.2.0", 0x05, 0),
+			cat(b(0x02, 0x03, 0x00, 0), str("input-stream")), // type 1
+			typeDecl(b(0x42), vec( // type 2
+				cat(b(0x02, 0x03, 0x02, 1, 1)),            // type 0: alias outer 1 1
+				exportDecl("input-stream", 0x03, 0x00, 0), // type 1
+				typeDecl(b(0x69, 1)),                      // type 2
+				typeDecl(b(0x40), vec(), b(0x00, 2)),      // type 3
+				exportDecl("get-stdin", 0x01, 3),
+			)),
+			exportDecl("example:cli/stdin", 0x05, 2),
+		)))),
+		section(11, vec(cat(b(0x00), str("stdin"), b(0x03, 0, 0x00)))),
+		section(7, vec(cat(b(0x41), vec(
+			typeDecl(b(0x41), vec(
+				typeDecl(b(0x42), vec( // type 0
+					exportDecl("input-stream", 0x03, 0x01),                  // type 0
+					exportDecl("output-stream", 0x03, 0x01),                 // type 1
+					typeDecl(b(0x68, 0)),                                    // type 2
+					typeDecl(b(0x40), vec(field("self", 2)), b(0x00, 0x79)), // type 3
+					exportDecl("[method]input-stream.read", 0x01, 3),
+				)),
+				importDecl("wasi:io/streams@0.2.0", 0x05, 0),
+			)),
+			exportDecl("example:cli/command", 0x04, 0),
+		)))),
+		section(11, vec(cat(b(0x00), str("command"), b(0x03, 1, 0x00)))),
+	)
+	res, err := Decode(bin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(res.Packages), 2; got != want {
+		t.Fatalf("Decode: %d packages, expected %d", got, want)
+	}
+	if got, want := res.Packages[0].Name.String(), "wasi:io@0.2.0"; got != want {
+		t.Errorf("Packages[0]: %s, expected %s", got, want)
+	}
+	streams := res.Packages[0].Interfaces.Get("streams")
+	if got, want := streams.TypeDefs.Len(), 2; got != want {
+		t.Errorf("streams: %d types, expected %d", got, want)
+	}
+	input := streams.TypeDefs.Get("input-stream")
+	read := streams.Functions.Get("[method]input-stream.read")
+	if read == nil {
+		t.Fatal("streams: missing method [method]input-stream.read")
+	}
+	if got := wit.KindOf[*wit.Borrow](read.Params[0].Type); got == nil || got.Type != input {
+		t.Errorf("[method]input-stream.read: self is not borrow<input-stream>")
+	}
+	stdin := res.Packages[1].Interfaces.Get("stdin")
+	if got := stdin.TypeDefs.Get("input-stream").Kind; got != input {
+		t.Errorf("stdin: input-stream is %v, expected alias of wasi:io/streams input-stream", got)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		b    []byte
+		want string
+	}{
+		{"not wasm", []byte("package foo:bar;"), "not a WebAssembly binary"},
+		{"truncated", examplePackage[:len(examplePackage)-3], "exceeds remaining data"},
+		{"unknown type", component(section(7, vec(b(0x20)))), "unsupported type 0x20"},
+		{"type index", component(section(7, vec(b(0x70, 3)))), "type index 3 out of range"},
+		{"unknown resource", component(section(7, vec(cat(b(0x41), vec(
+			typeDecl(b(0x42), vec(
+				typeDecl(b(0x40), vec(), b(0x01, 0x00)),
+				exportDecl("[method]r.f", 0x01, 0),
+			)),
+			exportDecl("example:foo/bar", 0x05, 0),
+		))))), "unknown resource r"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decode(tt.b)
+			if err == nil {
+				t.Fatalf("Decode: expected error")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Decode: %v, expected error containing %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package wit
+
+import (
+	"github.com/bytecodealliance/wasm-tools-go/wit/ordered"
+)
+
+// FindPackage returns the [Package] in r identified by ident, such as "wasi:cli@0.2.0",
+// or nil if not found. Any extension in ident, such as "/command" in "wasi:cli/command@0.2.0", is ignored.
+// If ident has no version, the package with the highest version is returned.
+func (r *Resolve) FindPackage(ident string) *Package {
+	id, err := ParseIdent(ident)
+	if err != nil {
+		return nil
+	}
+	var found *Package
+	for _, p := range r.Packages {
+		if p.Name.Namespace != id.Namespace || p.Name.Package != id.Package {
+			continue
+		}
+		if id.Version != nil {
+			if p.Name.Version != nil && p.Name.Version.Equal(*id.Version) {
+				return p
+			}
+			continue
+		}
+		if found == nil || higherVersion(p.Name.Version, found.Name.Version) {
+			found = p
+		}
+	}
+	return found
+}
+
+// FindWorld returns the first [World] in r that matches pattern, or nil if not found.
+// See [World.Match] for the forms pattern may take.
+func (r *Resolve) FindWorld(pattern string) *World {
+	for _, w := range r.Worlds {
+		if w.Match(pattern) {
+			return w
+		}
+	}
+	return nil
+}
+
+// FindInterface returns the first named [Interface] in r that matches pattern, or nil if not found.
+// See [Interface.Match] for the forms pattern may take.
+func (r *Resolve) FindInterface(pattern string) *Interface {
+	for _, i := range r.Interfaces {
+		if i.Match(pattern) {
+			return i
+		}
+	}
+	return nil
+}
+
+// FindWorld returns the [World] in p named name, such as "command", or nil if not found.
+func (p *Package) FindWorld(name string) *World {
+	return p.Worlds.Get(name)
+}
+
+// FindInterface returns the [Interface] in p named name, such as "environment", or nil if not found.
+func (p *Package) FindInterface(name string) *Interface {
+	return p.Interfaces.Get(name)
+}
+
+// Match returns true if [Interface] i is named or identified by pattern,
+// which may be its name, such as "environment", or its fully-qualified identifier,
+// such as "wasi:cli/environment@0.2.0", with or without a version.
+// An anonymous interface, such as one defined inline in a world, does not match any pattern.
+func (i *Interface) Match(pattern string) bool {
+	if i.Name == nil {
+		return false
+	}
+	if pattern == *i.Name {
+		return true
+	}
+	if i.Package == nil {
+		return false
+	}
+	id := i.Package.Name
+	id.Extension = *i.Name
+	if pattern == id.String() {
+		return true
+	}
+	id.Version = nil
+	return pattern == id.String()
+}
+
+// FindFunction returns the [Function] in i named name, or nil if not found.
+// Methods and static functions of resources are named like "[method]resource.name"
+// and "[static]resource.name".
+func (i *Interface) FindFunction(name string) *Function {
+	return i.Functions.Get(name)
+}
+
+// FindTypeDef returns the [TypeDef] in i named name, or nil if not found.
+func (i *Interface) FindTypeDef(name string) *TypeDef {
+	return i.TypeDefs.Get(name)
+}
+
+// FindImport returns the [WorldItem] imported by w named name, or nil if not found.
+// A named interface import may be found by its name or fully-qualified identifier,
+// with or without a version. See [Interface.Match].
+func (w *World) FindImport(name string) WorldItem {
+	return findWorldItem(&w.Imports, name)
+}
+
+// FindExport returns the [WorldItem] exported by w named name, or nil if not found.
+// A named interface export may be found by its name or fully-qualified identifier,
+// with or without a version. See [Interface.Match].
+func (w *World) FindExport(name string) WorldItem {
+	return findWorldItem(&w.Exports, name)
+}
+
+func findWorldItem(items *ordered.Map[string, WorldItem], name string) WorldItem {
+	if v, ok := items.GetOK(name); ok {
+		if ref, ok := v.(*InterfaceRef); !ok || ref.Interface.Name == nil {
+			return v
+		}
+	}
+	var found WorldItem
+	items.All()(func(_ string, v WorldItem) bool {
+		if ref, ok := v.(*InterfaceRef); ok && ref.Interface.Match(name) {
+			found = ref
+			return false
+		}
+		return true
+	})
+	return found
+}
@@ -0,0 +1,134 @@
+package wit
+
+import (
+	"testing"
+)
+
+func TestResolveFindPackage(t *testing.T) {
+	var r Resolve
+	for _, s := range []string{"wasi:io@0.2.0", "wasi:io@0.2.1", "wasi:io@0.10.0", "wasi:io@0.3.0-rc", "foo:bar"} {
+		id, err := ParseIdent(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.Packages = append(r.Packages, &Package{Name: id})
+	}
+	tests := []struct {
+		ident string
+		want  string
+	}{
+		{"wasi:io@0.2.1", "wasi:io@0.2.1"},
+		{"wasi:io/streams@0.2.0", "wasi:io@0.2.0"},
+		{"wasi:io", "wasi:io@0.10.0"},
+		{"wasi:io/streams", "wasi:io@0.10.0"},
+		{"foo:bar", "foo:bar"},
+		{"wasi:io@0.4.0", ""},
+		{"foo:bar@1.0.0", ""},
+		{"wasi:clocks", ""},
+		{"wasi", ""},
+	}
+	for _, tt := range tests {
+		var got string
+		if p := r.FindPackage(tt.ident); p != nil {
+			got = p.Name.String()
+		}
+		if got != tt.want {
+			t.Errorf("FindPackage(%q): %q, expected %q", tt.ident, got, tt.want)
+		}
+	}
+}
+
+func TestFind(t *testing.T) {
+	res, err := LoadJSON(testdataPath + "/wasi/cli.wit.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := res.FindPackage("wasi:cli@0.2.0")
+	if p == nil {
+		t.Fatal("FindPackage: wasi:cli@0.2.0 not found")
+	}
+	if w := p.FindWorld("command"); w == nil || w != res.FindWorld("wasi:cli/command") {
+		t.Errorf("FindWorld(\"command\"): %v, expected world wasi:cli/command", w)
+	}
+	if w := p.FindWorld("nonexistent"); w != nil {
+		t.Errorf("FindWorld(\"nonexistent\"): %v, expected nil", w)
+	}
+
+	i := p.FindInterface("environment")
+	if i == nil {
+		t.Fatal("FindInterface(\"environment\"): not found")
+	}
+	for _, pattern := range []string{"environment", "wasi:cli/environment", "wasi:cli/environment@0.2.0"} {
+		if !i.Match(pattern) {
+			t.Errorf("Match(%q): false, expected true", pattern)
+		}
+		if got := res.FindInterface(pattern); got != i {
+			t.Errorf("Resolve.FindInterface(%q): %v, expected %v", pattern, got, i)
+		}
+	}
+	for _, pattern := range []string{"wasi:cli/environment@0.2.1", "wasi:io/environment", "exit"} {
+		if i.Match(pattern) {
+			t.Errorf("Match(%q): true, expected false", pattern)
+		}
+	}
+
+	if f := i.FindFunction("get-environment"); f == nil || f.Name != "get-environment" {
+		t.Errorf("FindFunction(\"get-environment\"): %v, expected function get-environment", f)
+	}
+	if f := i.FindFunction("nonexistent"); f != nil {
+		t.Errorf("FindFunction(\"nonexistent\"): %v, expected nil", f)
+	}
+	streams := res.FindInterface("wasi:io/streams@0.2.0")
+	if streams == nil {
+		t.Fatal("FindInterface(\"wasi:io/streams@0.2.0\"): not found")
+	}
+	if td := streams.FindTypeDef("input-stream"); td == nil || td.Name == nil || *td.Name != "input-stream" {
+		t.Errorf("FindTypeDef(\"input-stream\"): %v, expected type input-stream", td)
+	}
+	if f := streams.FindFunction("[method]input-stream.read"); f == nil {
+		t.Error("FindFunction(\"[method]input-stream.read\"): not found")
+	}
+
+	w := res.FindWorld("wasi:cli/command@0.2.0")
+	for _, name := range []string{"environment", "wasi:cli/environment", "wasi:cli/environment@0.2.0"} {
+		ref, ok := w.FindImport(name).(*InterfaceRef)
+		if !ok || ref.Interface != i {
+			t.Errorf("FindImport(%q): %v, expected interface wasi:cli/environment", name, w.FindImport(name))
+		}
+	}
+	if item := w.FindExport("wasi:cli/run"); item == nil {
+		t.Error("FindExport(\"wasi:cli/run\"): not found")
+	}
+	if item := w.FindExport("wasi:cli/environment"); item != nil {
+		t.Errorf("FindExport(\"wasi:cli/environment\"): %v, expected nil", item)
+	}
+}
+
+func TestWorldFindImportExport(t *testing.T) {
+	err := loadTestdata(func(path string, res *Resolve) error {
+		t.Run(path, func(t *testing.T) {
+			for _, w := range res.Worlds {
+				check := func(motion string, find func(string) WorldItem) func(string, WorldItem) bool {
+					return func(name string, v WorldItem) bool {
+						if ref, ok := v.(*InterfaceRef); ok && ref.Interface.Name != nil && ref.Interface.Package != nil {
+							id := ref.Interface.Package.Name
+							id.Extension = *ref.Interface.Name
+							name = id.String()
+						}
+						if got := find(name); got != v {
+							t.Errorf("world %s: %s %q: got %v, expected %v", w.Name, motion, name, got, v)
+						}
+						return true
+					}
+				}
+				w.Imports.All()(check("import", w.FindImport))
+				w.Exports.All()(check("export", w.FindExport))
+			}
+		})
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
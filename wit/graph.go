@@ -0,0 +1,128 @@
+package wit
+
+// Graph is a directed graph of WIT items, where each edge points from an item
+// to an item it references. Nodes are each a [*World], [*Interface], or [*TypeDef].
+// See [DependencyGraph].
+type Graph struct {
+	// Nodes are the items in the graph, ordered so that each node follows
+	// the nodes it references, except where references are cyclic.
+	Nodes []Node
+
+	// Edges map each node to the nodes it directly references, in order.
+	Edges map[Node][]Node
+}
+
+// DependencyGraph returns the [Graph] of items transitively referenced by root,
+// which must be a [*World], [*Interface], or [*TypeDef], including root.
+//
+// A [World] references the interfaces it imports or exports, its own types,
+// and the types used by its functions. An [Interface] references its types,
+// the types used by its functions, and any other interface that owns a type it references.
+// A [TypeDef] references the types that comprise it, such as record fields or variant cases.
+func DependencyGraph(root Node) *Graph {
+	g := &Graph{Edges: make(map[Node][]Node)}
+	g.visit(root)
+	return g
+}
+
+// Contains returns true if node is in g.
+func (g *Graph) Contains(node Node) bool {
+	_, ok := g.Edges[node]
+	return ok
+}
+
+// TypeDefs returns the [TypeDef] nodes in g, in order.
+func (g *Graph) TypeDefs() []*TypeDef {
+	var types []*TypeDef
+	for _, node := range g.Nodes {
+		if t, ok := node.(*TypeDef); ok {
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
+// Interfaces returns the [Interface] nodes in g, in order.
+func (g *Graph) Interfaces() []*Interface {
+	var faces []*Interface
+	for _, node := range g.Nodes {
+		if i, ok := node.(*Interface); ok {
+			faces = append(faces, i)
+		}
+	}
+	return faces
+}
+
+func (g *Graph) visit(node Node) {
+	if node == nil || g.Contains(node) {
+		return
+	}
+	// Mark node as visited before visiting its references, to terminate cycles.
+	g.Edges[node] = nil
+
+	var refs []Node
+	add := func(n Node) {
+		for _, r := range refs {
+			if r == n {
+				return
+			}
+		}
+		refs = append(refs, n)
+	}
+	addType := func(t Type) {
+		if td, ok := t.(*TypeDef); ok && td != nil {
+			add(td)
+		}
+	}
+	addFunction := func(f *Function) {
+		for _, p := range f.Params {
+			addType(p.Type)
+		}
+		for _, r := range f.Results {
+			addType(r.Type)
+		}
+	}
+
+	switch node := node.(type) {
+	case *World:
+		node.AllImportsAndExports()(func(_ string, item WorldItem) bool {
+			switch item := item.(type) {
+			case *InterfaceRef:
+				if item.Interface != nil {
+					add(item.Interface)
+				}
+			case *TypeDef:
+				add(item)
+			case *Function:
+				addFunction(item)
+			}
+			return true
+		})
+
+	case *Interface:
+		node.TypeDefs.All()(func(_ string, t *TypeDef) bool {
+			add(t)
+			// Types referenced from other interfaces, e.g. via use
+			if i, ok := t.Root().Owner.(*Interface); ok && i != node {
+				add(i)
+			}
+			return true
+		})
+		node.Functions.All()(func(_ string, f *Function) bool {
+			addFunction(f)
+			return true
+		})
+
+	case *TypeDef:
+		mapTypes(node, func(t Type) Type {
+			addType(t)
+			return t
+		})
+	}
+
+	g.Edges[node] = refs
+	for _, r := range refs {
+		g.visit(r)
+	}
+	g.Nodes = append(g.Nodes, node)
+}
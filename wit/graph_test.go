@@ -0,0 +1,32 @@
+package wit
+
+import "testing"
+
+func TestDependencyGraphTypeDef(t *testing.T) {
+	name := func(s string) *string { return &s }
+	bytes := &TypeDef{Kind: &List{Type: U8{}}}
+	point := &TypeDef{Name: name("point"), Kind: &Record{Fields: []Field{{Name: "x", Type: U32{}}, {Name: "data", Type: bytes}}}}
+	alias := &TypeDef{Name: name("pt"), Kind: point}
+	result := &TypeDef{Kind: &Result{OK: alias}}
+	unused := &TypeDef{Name: name("unused"), Kind: &Enum{Cases: []EnumCase{{Name: "a"}}}}
+
+	g := DependencyGraph(result)
+	want := []Node{bytes, point, alias, result}
+	if got := g.Nodes; len(got) != len(want) {
+		t.Fatalf("Nodes: %d nodes, expected %d", len(got), len(want))
+	}
+	for i := range want {
+		if got := g.Nodes[i]; got != want[i] {
+			t.Errorf("Nodes[%d]: %v, expected %v", i, got, want[i])
+		}
+	}
+	if g.Contains(unused) {
+		t.Error("Contains(unused): true, expected false")
+	}
+	if got, want := len(g.TypeDefs()), 4; got != want {
+		t.Errorf("TypeDefs(): %d types, expected %d", got, want)
+	}
+	if got, want := len(g.Edges[point]), 1; got != want {
+		t.Errorf("Edges[point]: %d edges, expected %d", got, want)
+	}
+}
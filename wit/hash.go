@@ -0,0 +1,176 @@
+package wit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/bytecodealliance/wasm-tools-go/wit/ordered"
+)
+
+// HashOptions configures the content hashed by [Resolve.HashWith].
+// The zero value hashes the semantic content of a [Resolve], ignoring documentation
+// and the order of declarations.
+type HashOptions struct {
+	// Docs includes documentation in the hash.
+	Docs bool
+
+	// Order includes the order in which packages, interfaces, worlds, types, functions,
+	// and world imports and exports are declared. The order of record fields, function
+	// parameters, and other ordered parts of a type or function is always included.
+	Order bool
+}
+
+// Hash returns a canonical content hash of [Resolve] r with the default [HashOptions],
+// as a digest string, e.g. "sha256:9f86d081...". Resolves with the same packages, interfaces,
+// worlds, types, and functions have the same hash, regardless of their documentation or
+// the order in which they were declared or decoded, so the hash can fingerprint
+// the version of a WIT interface in logs and metrics, or key a cache.
+//
+// The hash is derived from the [WIT] text format of each declaration, so it may change
+// between releases of this package if the text format changes.
+//
+// [WIT]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/WIT.md
+func (r *Resolve) Hash() string {
+	return r.HashWith(HashOptions{})
+}
+
+// HashWith returns a canonical content hash of [Resolve] r with opts.
+// See [Resolve.Hash] for more information.
+func (r *Resolve) HashWith(opts HashOptions) string {
+	h := &hasher{opts: opts}
+	var packages []string
+	for _, p := range r.Packages {
+		packages = append(packages, h.pkg(p))
+	}
+	sum := sha256.New()
+	h.write(sum, "resolve", packages)
+	return "sha256:" + hex.EncodeToString(sum.Sum(nil))
+}
+
+// hasher renders the canonical text of WIT declarations for [Resolve.HashWith].
+// Each declaration is rendered as a header followed by its length-prefixed children,
+// sorted unless opts.Order is set, so the text of one declaration cannot be confused
+// with the text of another.
+type hasher struct {
+	opts HashOptions
+
+	// noStability omits @since, @unstable, and @deprecated attributes,
+	// which may differ between otherwise identical versions of a package.
+	noStability bool
+}
+
+func (h *hasher) pkg(p *Package) string {
+	var items []string
+	p.Interfaces.All()(func(name string, i *Interface) bool {
+		items = append(items, h.iface(i, name, p))
+		return true
+	})
+	p.Worlds.All()(func(name string, w *World) bool {
+		items = append(items, h.world(w, name))
+		return true
+	})
+	return h.block("package "+p.Name.String(), &p.Docs, nil, items)
+}
+
+func (h *hasher) iface(i *Interface, name string, ctx Node) string {
+	var items []string
+	i.TypeDefs.All()(func(name string, t *TypeDef) bool {
+		items = append(items, h.text(t.WIT(i, name)))
+		return true
+	})
+	i.Functions.All()(func(name string, f *Function) bool {
+		if f.IsFreestanding() {
+			items = append(items, h.text(f.WIT(i, name)))
+		}
+		return true
+	})
+	header := "interface " + escape(name)
+	if _, ok := ctx.(*Package); !ok {
+		header = escape(name) + ": interface"
+	}
+	return h.block(header, &i.Docs, i.Stability, items)
+}
+
+func (h *hasher) world(w *World, name string) string {
+	var items []string
+	for _, inc := range w.Includes {
+		items = append(items, h.text(inc.WIT(w, "")))
+	}
+	for _, m := range []struct {
+		motion string
+		items  *ordered.Map[string, WorldItem]
+		ctx    Node
+	}{
+		{"import", &w.Imports, worldImport{w}},
+		{"export", &w.Exports, worldExport{w}},
+	} {
+		m.items.All()(func(name string, item WorldItem) bool {
+			switch item := item.(type) {
+			case *InterfaceRef:
+				if item.Interface.Name == nil {
+					items = append(items, h.block(m.motion, nil, item.Stability, []string{h.iface(item.Interface, name, m.ctx)}))
+					return true
+				}
+			case *Function:
+				if !item.IsFreestanding() {
+					return true
+				}
+			}
+			items = append(items, h.text(item.WIT(m.ctx, name)))
+			return true
+		})
+	}
+	return h.block("world "+escape(name), &w.Docs, w.Stability, items)
+}
+
+// block returns the canonical text of a declaration with header, docs, stability, and items.
+func (h *hasher) block(header string, docs *Docs, stability Stability, items []string) string {
+	var b strings.Builder
+	b.WriteString(header)
+	b.WriteRune('\n')
+	if docs != nil && h.opts.Docs {
+		fmt.Fprintf(&b, "docs %q\n", docs.Contents)
+	}
+	if stability != nil && !h.noStability {
+		b.WriteString(stability.WIT(nil, ""))
+		b.WriteRune('\n')
+	}
+	h.write(&b, "items", items)
+	return b.String()
+}
+
+// write writes items to w with their count and lengths, sorted unless h.opts.Order is set.
+func (h *hasher) write(w io.Writer, label string, items []string) {
+	if !h.opts.Order {
+		items = slices.Clone(items)
+		slices.Sort(items)
+	}
+	fmt.Fprintf(w, "%s %d\n", label, len(items))
+	for _, item := range items {
+		fmt.Fprintf(w, "%d\n%s\n", len(item), item)
+	}
+}
+
+// text returns WIT text s with normalized whitespace, without documentation unless
+// h.opts.Docs is set, and without stability attributes if h.noStability is set. Normalizing whitespace makes the text of a declaration independent
+// of line wrapping, e.g. of a record that is rendered on multiple lines only if it has docs.
+func (h *hasher) text(s string) string {
+	lines := strings.Split(s, "\n")
+	if !h.opts.Docs {
+		lines = slices.DeleteFunc(lines, func(line string) bool {
+			return strings.HasPrefix(strings.TrimSpace(line), DocPrefix)
+		})
+	}
+	if h.noStability {
+		lines = slices.DeleteFunc(lines, func(line string) bool {
+			line = strings.TrimSpace(line)
+			return strings.HasPrefix(line, "@since(") || strings.HasPrefix(line, "@unstable(") ||
+				strings.HasPrefix(line, "@deprecated(")
+		})
+	}
+	return strings.ReplaceAll(strings.Join(strings.Fields(strings.Join(lines, "\n")), " "), ", }", " }")
+}
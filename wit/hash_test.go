@@ -0,0 +1,106 @@
+package wit
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/bytecodealliance/wasm-tools-go/wit/ordered"
+)
+
+func TestHash(t *testing.T) {
+	err := loadTestdata(func(path string, res *Resolve) error {
+		t.Run(path, func(t *testing.T) {
+			hash := res.Hash()
+			if !strings.HasPrefix(hash, "sha256:") || len(hash) != len("sha256:")+64 {
+				t.Fatalf("Hash(): %q, expected SHA-256 digest", hash)
+			}
+
+			withDocs := res.HashWith(HashOptions{Docs: true})
+			inOrder := res.HashWith(HashOptions{Order: true})
+
+			// Reverse the order of packages and their contents.
+			slices.Reverse(res.Packages)
+			for _, p := range res.Packages {
+				reverse(&p.Interfaces)
+				reverse(&p.Worlds)
+			}
+			for _, i := range res.Interfaces {
+				reverse(&i.TypeDefs)
+				reverse(&i.Functions)
+			}
+			for _, w := range res.Worlds {
+				reverse(&w.Imports)
+				reverse(&w.Exports)
+			}
+			if got := res.Hash(); got != hash {
+				t.Errorf("Hash(): %s after reordering, expected %s", got, hash)
+			}
+			if got := res.HashWith(HashOptions{Order: true}); got == inOrder && len(res.Packages) > 1 {
+				t.Errorf("HashWith(Order): %s unchanged after reordering %d packages", got, len(res.Packages))
+			}
+
+			hasDocs := strings.Contains(res.WIT(nil, ""), DocPrefix)
+			res.StripDocs()
+			if got := res.Hash(); got != hash {
+				t.Errorf("Hash(): %s after StripDocs, expected %s", got, hash)
+			}
+			if got := res.HashWith(HashOptions{Docs: true}); (got != withDocs) != hasDocs {
+				t.Errorf("HashWith(Docs): changed after StripDocs: %t, expected %t", got != withDocs, hasDocs)
+			}
+		})
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestHashChanges(t *testing.T) {
+	res, err := LoadJSON(testdataPath + "/codegen/mixed-versions.wit.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := res.Hash()
+	tests := []struct {
+		name   string
+		modify func(res *Resolve)
+	}{
+		{"package version", func(res *Resolve) { res.Packages[1].Name.Version.Patch = 1 }},
+		{"type", func(res *Resolve) { res.TypeDefs[0].Kind = U64{} }},
+		{"field order", func(res *Resolve) {
+			r := res.TypeDefs[6].Kind.(*Record)
+			r.Fields[0], r.Fields[1] = r.Fields[1], r.Fields[0]
+		}},
+		{"world import", func(res *Resolve) { res.Worlds[0].Imports.Delete("interface-2") }},
+		{"interface name", func(res *Resolve) { *res.Interfaces[2].Name = "wall-clock" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := LoadJSON(testdataPath + "/codegen/mixed-versions.wit.json")
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.modify(res)
+			if got := res.Hash(); got == hash {
+				t.Errorf("Hash(): unchanged after modifying %s", tt.name)
+			}
+		})
+	}
+}
+
+// reverse reverses the order of the entries in m.
+func reverse[K comparable, V any](m *ordered.Map[K, V]) {
+	var keys []K
+	var values []V
+	m.All()(func(k K, v V) bool {
+		keys = append(keys, k)
+		values = append(values, v)
+		return true
+	})
+	var r ordered.Map[K, V]
+	for i := len(keys) - 1; i >= 0; i-- {
+		r.Set(keys[i], values[i])
+	}
+	*m = r
+}
@@ -0,0 +1,166 @@
+package wit
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bytecodealliance/wasm-tools-go/wit/ordered"
+)
+
+// An Include represents a WIT [include] statement in a [World], which adds the imports
+// and exports of another World. Includes are resolved by [Resolve.ResolveIncludes].
+// JSON produced by wasm-tools has includes already resolved, but hand-authored JSON
+// may declare them in the "includes" field of a world.
+// It implements the [Node] interface.
+//
+// [include]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/WIT.md#union-of-worlds-with-include
+type Include struct {
+	World     *World        // the included World
+	Names     []IncludeName // items of World renamed with "with { a as b }"
+	Stability Stability     // WIT @since or @unstable (nil if unknown)
+}
+
+// IncludeName renames an import or export of an included [World].
+type IncludeName struct {
+	Name string // the name of the item in the included World
+	As   string // the name of the item in the including World
+}
+
+// WITKind returns the WIT kind.
+func (*Include) WITKind() string { return "include" }
+
+// WIT returns the [WIT] text format for [Include] inc.
+// The included World is named relative to the [World] ctx, if any.
+//
+// [WIT]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/WIT.md
+func (inc *Include) WIT(ctx Node, _ string) string {
+	var b strings.Builder
+	if inc.Stability != nil {
+		b.WriteString(inc.Stability.WIT(ctx, ""))
+		b.WriteRune('\n')
+	}
+	b.WriteString("include ")
+	var p *Package
+	if w, ok := ctx.(*World); ok {
+		p = w.Package
+	}
+	b.WriteString(escape(relativeName(inc.World, p)))
+	if len(inc.Names) > 0 {
+		b.WriteString(" with { ")
+		for i, n := range inc.Names {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(escape(n.Name))
+			b.WriteString(" as ")
+			b.WriteString(escape(n.As))
+		}
+		b.WriteString(" }")
+	}
+	b.WriteRune(';')
+	return b.String()
+}
+
+// ResolveIncludes resolves the [Include] statements of each [World] in [Resolve] r,
+// adding the imports and exports of each included World to the including World,
+// then removing the includes. Included worlds are resolved first, so includes are transitive.
+//
+// As in wasm-tools, an interface imported or exported by more than one World is added once,
+// and any other item is added by name, which must not conflict with an existing item
+// unless renamed by [IncludeName]. Types and functions are shared, not copied, so a [TypeDef]
+// added to a World is still owned by the World that declared it.
+// The [Stability] of includes is ignored.
+//
+// ResolveIncludes returns an error if an include cycle is found, an included World is nil,
+// or an item name conflicts.
+func (r *Resolve) ResolveIncludes() error {
+	const (
+		visiting = 1
+		resolved = 2
+	)
+	state := make(map[*World]int)
+	var resolve func(w *World) error
+	resolve = func(w *World) error {
+		switch state[w] {
+		case visiting:
+			return fmt.Errorf("world %s: include cycle", worldPath(w))
+		case resolved:
+			return nil
+		}
+		state[w] = visiting
+		for _, inc := range w.Includes {
+			if inc.World == nil {
+				return fmt.Errorf("world %s: include of nil World", worldPath(w))
+			}
+			err := resolve(inc.World)
+			if err != nil {
+				return err
+			}
+			err = w.include(inc)
+			if err != nil {
+				return err
+			}
+		}
+		w.Includes = nil
+		state[w] = resolved
+		return nil
+	}
+	for _, w := range r.Worlds {
+		err := resolve(w)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// include adds the imports and exports of inc.World to w.
+func (w *World) include(inc *Include) error {
+	rename := make(map[string]string)
+	for _, n := range inc.Names {
+		rename[n.Name] = n.As
+	}
+	for _, items := range []struct {
+		from, to *ordered.Map[string, WorldItem]
+		motion   string
+	}{
+		{&inc.World.Imports, &w.Imports, "import"},
+		{&inc.World.Exports, &w.Exports, "export"},
+	} {
+		var err error
+		items.from.All()(func(name string, item WorldItem) bool {
+			if ref, ok := item.(*InterfaceRef); ok && ref.Interface.Name != nil {
+				// Named interfaces are identified by the interface, not their name in a World.
+				var found bool
+				items.to.All()(func(_ string, item WorldItem) bool {
+					if r, ok := item.(*InterfaceRef); ok && r.Interface == ref.Interface {
+						found = true
+					}
+					return !found
+				})
+				if found {
+					return true
+				}
+				if _, ok := items.to.GetOK(name); ok {
+					name = ref.Interface.Package.Name.String() + "/" + *ref.Interface.Name
+				}
+				items.to.Set(name, &InterfaceRef{Interface: ref.Interface, Stability: ref.Stability})
+				return true
+			}
+			if as, ok := rename[name]; ok {
+				name = as
+			}
+			if _, ok := items.to.GetOK(name); ok {
+				err = fmt.Errorf("world %s: %s of %q from included world %s shadows a previous %s",
+					worldPath(w), items.motion, name, worldPath(inc.World), items.motion)
+				return false
+			}
+			items.to.Set(name, item)
+			return true
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
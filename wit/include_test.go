@@ -0,0 +1,163 @@
+package wit
+
+import (
+	"strings"
+	"testing"
+)
+
+// includeJSON is hand-authored WIT JSON with unresolved includes:
+//
+//	package foo:bar;
+//
+//	interface i {
+//		h: func();
+//	}
+//
+//	world base {
+//		import i;
+//		import f: func() -> u32;
+//		export run: func();
+//	}
+//
+//	world app {
+//		include base with { f as g };
+//		import f: func();
+//	}
+//
+//	world all {
+//		include app;
+//		import i;
+//	}
+const includeJSON = `{
+	"worlds": [
+		{
+			"name": "base",
+			"imports": {
+				"interface-0": {"interface": {"id": 0}},
+				"f": {"function": {"name": "f", "kind": "freestanding", "params": [], "results": [{"type": "u32"}]}}
+			},
+			"exports": {
+				"run": {"function": {"name": "run", "kind": "freestanding", "params": [], "results": []}}
+			},
+			"package": 0
+		},
+		{
+			"name": "app",
+			"imports": {
+				"f": {"function": {"name": "f", "kind": "freestanding", "params": [], "results": []}}
+			},
+			"exports": {},
+			"includes": [{"world": 0, "names": [{"name": "f", "as": "g"}]}],
+			"package": 0
+		},
+		{
+			"name": "all",
+			"imports": {
+				"interface-0": {"interface": {"id": 0}}
+			},
+			"exports": {},
+			"includes": [{"world": 1}],
+			"package": 0
+		}
+	],
+	"interfaces": [
+		{
+			"name": "i",
+			"types": {},
+			"functions": {
+				"h": {"name": "h", "kind": "freestanding", "params": [], "results": []}
+			},
+			"package": 0
+		}
+	],
+	"types": [],
+	"packages": [
+		{
+			"name": "foo:bar",
+			"interfaces": {"i": 0},
+			"worlds": {"base": 0, "app": 1, "all": 2}
+		}
+	]
+}`
+
+func TestResolveIncludes(t *testing.T) {
+	res, err := DecodeJSON(strings.NewReader(includeJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	base, app, all := res.Worlds[0], res.Worlds[1], res.Worlds[2]
+	if len(app.Includes) != 1 || app.Includes[0].World != base {
+		t.Fatalf("app.Includes: %v, expected include of world base", app.Includes)
+	}
+
+	want := "include base with { f as g };"
+	if got := app.WIT(nil, ""); !strings.Contains(got, want) {
+		t.Errorf("WIT(): expected %q:\n%s", want, got)
+	}
+
+	err = res.ResolveIncludes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, w := range res.Worlds {
+		if len(w.Includes) != 0 {
+			t.Errorf("world %s: %d unresolved includes", w.Name, len(w.Includes))
+		}
+	}
+
+	if got, want := app.Imports.Get("g"), base.Imports.Get("f"); got != want {
+		t.Errorf("app import g: %v, expected base import f", got)
+	}
+	if got, want := app.Exports.Get("run"), base.Exports.Get("run"); got != want {
+		t.Errorf("app export run: %v, expected base export run", got)
+	}
+	if !app.HasInterface(res.Interfaces[0]) {
+		t.Error("app: expected import of interface i")
+	}
+
+	// Includes are transitive, and interfaces are added once.
+	var n int
+	all.AllInterfaces()(func(string, *Interface) bool {
+		n++
+		return true
+	})
+	if n != 1 {
+		t.Errorf("all: %d interfaces, expected 1", n)
+	}
+	for _, name := range []string{"f", "g"} {
+		if _, ok := all.Imports.GetOK(name); !ok {
+			t.Errorf("all: missing import %s", name)
+		}
+	}
+
+	err = res.Validate()
+	if err != nil {
+		t.Errorf("Validate(): %v", err)
+	}
+}
+
+func TestResolveIncludesErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(res *Resolve)
+	}{
+		{"shadow", func(res *Resolve) { res.Worlds[1].Includes[0].Names = nil }},
+		{"cycle", func(res *Resolve) {
+			res.Worlds[0].Includes = []*Include{{World: res.Worlds[2]}}
+		}},
+		{"nil", func(res *Resolve) { res.Worlds[1].Includes[0].World = nil }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := DecodeJSON(strings.NewReader(includeJSON))
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.modify(res)
+			err = res.ResolveIncludes()
+			if err == nil {
+				t.Error("ResolveIncludes(): expected error")
+			}
+		})
+	}
+}
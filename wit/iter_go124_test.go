@@ -0,0 +1,43 @@
+//go:build go1.24
+
+package wit
+
+import (
+	"iter"
+	"slices"
+	"testing"
+)
+
+func TestIterSeq(t *testing.T) {
+	res, err := LoadJSON(testdataPath + "/wasi/cli.wit.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Iterators returned by this package are iter.Seq and iter.Seq2 values.
+	var funcs iter.Seq[*Function] = res.AllFunctions()
+	all := slices.Collect(funcs)
+	if len(all) == 0 {
+		t.Fatal("AllFunctions: no functions")
+	}
+
+	var n int
+	for f := range res.AllFunctions() {
+		if f != all[n] {
+			t.Errorf("AllFunctions: function %d: %s, expected %s", n, f.Name, all[n].Name)
+		}
+		n++
+	}
+	if got, want := n, len(all); got != want {
+		t.Errorf("AllFunctions: %d functions, expected %d", got, want)
+	}
+
+	for _, w := range res.Worlds {
+		var items iter.Seq2[string, WorldItem] = w.AllImportsAndExports()
+		for name, item := range items {
+			if name == "" || item == nil {
+				t.Errorf("world %s: AllImportsAndExports: unexpected item %q: %v", w.Name, name, item)
+			}
+		}
+	}
+}
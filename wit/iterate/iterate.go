@@ -1,22 +1,13 @@
-package iterate
-
-// Seq is an iterator over sequences of individual values.
-// When called as seq(yield), seq calls yield(v) for each value v in the sequence,
-// stopping early if yield returns false.
-//
-// TODO: delete this once [GOEXPERIMENT=rangefunc] lands.
+// Package iterate contains helpers for iterators over sequences of values.
 //
-// [GOEXPERIMENT=rangefunc]: https://go.dev/wiki/RangefuncExperiment
-type Seq[V any] func(yield func(V) bool)
-
-// Seq2 is an iterator over sequences of pairs of values, most commonly key-value pairs.
-// When called as seq(yield), seq calls yield(k, v) for each pair (k, v) in the sequence,
-// stopping early if yield returns false.
-//
-// TODO: delete this once [GOEXPERIMENT=rangefunc] lands.
+// [Seq] and [Seq2] are aliases for [iter.Seq] and [iter.Seq2] in Go 1.24 or later,
+// so iterators returned by package wit can be passed to functions that accept iterators,
+// such as [slices.Collect]. In Go 1.23 or later, they can be used in range-over-func loops:
 //
-// [GOEXPERIMENT=rangefunc]: https://go.dev/wiki/RangefuncExperiment
-type Seq2[K, V any] func(yield func(K, V) bool)
+//	for f := range res.AllFunctions() {
+//		// ...
+//	}
+package iterate
 
 // Done wraps yield and calls done when yield returns false.
 func Done[V any](yield func(V) bool, done func()) func(V) bool {
@@ -0,0 +1,13 @@
+//go:build go1.24
+
+package iterate
+
+import "iter"
+
+// Seq is an iterator over sequences of individual values.
+// See [iter.Seq] for more information.
+type Seq[V any] = iter.Seq[V]
+
+// Seq2 is an iterator over sequences of pairs of values, most commonly key-value pairs.
+// See [iter.Seq2] for more information.
+type Seq2[K, V any] = iter.Seq2[K, V]
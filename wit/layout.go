@@ -0,0 +1,147 @@
+package wit
+
+import (
+	"path"
+	"strings"
+)
+
+// Layout specifies how [Resolve.WITFiles] lays out WIT packages in files.
+type Layout int
+
+const (
+	// LayoutMultiPackage writes a single file in the format of [Resolve.WIT]:
+	// the first package in single-package form (package foo:bar;),
+	// followed by each other package in braced form (package foo:baz { ... }).
+	LayoutMultiPackage Layout = iota
+
+	// LayoutNested writes a single file with every package in braced, nested form.
+	LayoutNested
+
+	// LayoutFiles writes one file per package in single-package form.
+	LayoutFiles
+
+	// LayoutDeps writes the root package to a file in the top-level directory,
+	// and each other package to its own directory in deps/, the layout
+	// used by WIT directories with dependencies, such as those in WASI repositories.
+	LayoutDeps
+)
+
+// String returns the name of layout l, as accepted by [ParseLayout].
+func (l Layout) String() string {
+	switch l {
+	case LayoutMultiPackage:
+		return "multi"
+	case LayoutNested:
+		return "nested"
+	case LayoutFiles:
+		return "files"
+	case LayoutDeps:
+		return "deps"
+	}
+	return "unknown"
+}
+
+// ParseLayout parses a [Layout] by name: multi, nested, files, or deps.
+func ParseLayout(s string) (Layout, bool) {
+	for _, l := range []Layout{LayoutMultiPackage, LayoutNested, LayoutFiles, LayoutDeps} {
+		if s == l.String() {
+			return l, true
+		}
+	}
+	return 0, false
+}
+
+// WriteOption configures how [Resolve.WITFiles] and [Resolve.WriteWIT] write WIT.
+type WriteOption func(*writeOptions)
+
+type writeOptions struct {
+	layout Layout
+	root   *Package
+}
+
+// WithLayout returns a [WriteOption] that lays out WIT packages in files with l.
+// The default is [LayoutMultiPackage].
+func WithLayout(l Layout) WriteOption {
+	return func(opts *writeOptions) {
+		opts.layout = l
+	}
+}
+
+// WithRoot returns a [WriteOption] that sets the root package, which is written
+// to the top-level directory by [LayoutDeps] and names the file of single-file layouts.
+// The default is the package of the world passed to [Resolve.WITFiles], if any,
+// otherwise the last package in [Resolve].Packages, which depends on the others.
+func WithRoot(p *Package) WriteOption {
+	return func(opts *writeOptions) {
+		opts.root = p
+	}
+}
+
+// WITFiles returns the [WIT] text format for [Resolve] r as a map of slash-separated
+// file paths to file contents, laid out according to opts.
+// If ctx is a [World], only the world and the interfaces it references are written,
+// and packages without any of them are omitted.
+//
+// File names are derived from package names, such as wasi-io@0.2.0.wit for wasi:io@0.2.0.
+// With [LayoutDeps], each dependency is written to deps/wasi-io@0.2.0/package.wit.
+//
+// [WIT]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/WIT.md
+func (r *Resolve) WITFiles(ctx Node, opts ...WriteOption) map[string]string {
+	var o writeOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	root := o.root
+	if root == nil {
+		if w, ok := ctx.(*World); ok && w != nil {
+			root = w.Package
+		} else if len(r.Packages) > 0 {
+			root = r.Packages[len(r.Packages)-1]
+		}
+	}
+	if root == nil {
+		return nil
+	}
+
+	files := make(map[string]string)
+	switch o.layout {
+	case LayoutMultiPackage:
+		files[packageFileName(root)] = r.WIT(ctx, "")
+
+	case LayoutNested:
+		files[packageFileName(root)] = witString(func(b *witWriter) { r.writeWIT(b, ctx, true) })
+
+	case LayoutFiles, LayoutDeps:
+		for _, p := range r.Packages {
+			if p != root && !p.hasContent(ctx) {
+				continue
+			}
+			name := packageFileName(p)
+			if o.layout == LayoutDeps && p != root {
+				name = path.Join("deps", strings.TrimSuffix(name, ".wit"), "package.wit")
+			}
+			files[name] = p.WIT(ctx, "")
+		}
+	}
+	return files
+}
+
+// packageFileName returns the WIT file name for [Package] p, such as wasi-io@0.2.0.wit.
+func packageFileName(p *Package) string {
+	return strings.NewReplacer(":", "-", "/", "-").Replace(p.Name.String()) + ".wit"
+}
+
+// hasContent returns true if [Package] p has any interface or world
+// that would be written in context ctx. See [Package.WIT].
+func (p *Package) hasContent(ctx Node) bool {
+	w, ok := ctx.(*World)
+	if !ok || w == nil {
+		return p.Interfaces.Len() > 0 || p.Worlds.Len() > 0
+	}
+	found := false
+	p.Interfaces.All()(func(_ string, face *Interface) bool {
+		found = w.HasInterface(face)
+		return !found
+	})
+	return found || w.Package == p
+}
@@ -0,0 +1,80 @@
+package wit
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestResolveWITFiles(t *testing.T) {
+	res, err := LoadJSON(testdataPath + "/wasi/cli.wit.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := res.FindPackage("wasi:cli")
+	if got := res.Packages[len(res.Packages)-1]; got != root {
+		t.Fatalf("last package: %s, expected wasi:cli", got.Name.String())
+	}
+
+	tests := []struct {
+		layout Layout
+		ctx    Node
+		want   []string
+	}{
+		{LayoutMultiPackage, nil, []string{"wasi-cli@0.2.0.wit"}},
+		{LayoutNested, nil, []string{"wasi-cli@0.2.0.wit"}},
+		{LayoutFiles, nil, []string{"wasi-cli@0.2.0.wit", "wasi-clocks@0.2.0.wit", "wasi-filesystem@0.2.0.wit", "wasi-io@0.2.0.wit", "wasi-random@0.2.0.wit", "wasi-sockets@0.2.0.wit"}},
+		{LayoutDeps, nil, []string{"deps/wasi-clocks@0.2.0/package.wit", "deps/wasi-filesystem@0.2.0/package.wit", "deps/wasi-io@0.2.0/package.wit", "deps/wasi-random@0.2.0/package.wit", "deps/wasi-sockets@0.2.0/package.wit", "wasi-cli@0.2.0.wit"}},
+		{LayoutDeps, res.FindWorld("wasi:io/imports"), []string{"wasi-io@0.2.0.wit"}},
+		{LayoutFiles, (*World)(nil), []string{"wasi-cli@0.2.0.wit", "wasi-clocks@0.2.0.wit", "wasi-filesystem@0.2.0.wit", "wasi-io@0.2.0.wit", "wasi-random@0.2.0.wit", "wasi-sockets@0.2.0.wit"}},
+	}
+	for _, tt := range tests {
+		files := res.WITFiles(tt.ctx, WithLayout(tt.layout))
+		var got []string
+		for name := range files {
+			got = append(got, name)
+		}
+		slices.Sort(got)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("WITFiles(%s): %v, expected %v", tt.layout, got, tt.want)
+		}
+	}
+
+	if got, want := res.WITFiles(nil)["wasi-cli@0.2.0.wit"], res.WIT(nil, ""); got != want {
+		t.Error("WITFiles(multi): content differs from Resolve.WIT")
+	}
+
+	nested := res.WITFiles(nil, WithLayout(LayoutNested))["wasi-cli@0.2.0.wit"]
+	for _, line := range strings.Split(nested, "\n") {
+		if strings.HasPrefix(line, "package ") && !strings.HasSuffix(line, " {") {
+			t.Errorf("WITFiles(nested): unbraced package declaration: %s", line)
+		}
+	}
+	if n := strings.Count(nested, "\npackage ") + 1; n != len(res.Packages) {
+		t.Errorf("WITFiles(nested): %d packages, expected %d", n, len(res.Packages))
+	}
+
+	for name, content := range res.WITFiles(nil, WithLayout(LayoutDeps)) {
+		if !strings.HasPrefix(content, "package wasi:") || strings.Contains(content, " {\n\tinterface") {
+			t.Errorf("WITFiles(deps): %s: expected a single package in single-package form", name)
+		}
+	}
+
+	io := res.FindPackage("wasi:io")
+	files := res.WITFiles(nil, WithLayout(LayoutDeps), WithRoot(io))
+	if _, ok := files["wasi-io@0.2.0.wit"]; !ok {
+		t.Error("WITFiles(deps) with root wasi:io: wasi-io@0.2.0.wit not written")
+	}
+	if _, ok := files["deps/wasi-cli@0.2.0/package.wit"]; !ok {
+		t.Error("WITFiles(deps) with root wasi:io: deps/wasi-cli@0.2.0/package.wit not written")
+	}
+
+	for _, l := range []Layout{LayoutMultiPackage, LayoutNested, LayoutFiles, LayoutDeps} {
+		if got, ok := ParseLayout(l.String()); !ok || got != l {
+			t.Errorf("ParseLayout(%q): %v, %t, expected %v, true", l.String(), got, ok, l)
+		}
+	}
+	if _, ok := ParseLayout("split"); ok {
+		t.Error("ParseLayout(\"split\"): expected false")
+	}
+}
@@ -0,0 +1,203 @@
+package wit
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/bytecodealliance/wasm-tools-go/wit/ordered"
+)
+
+// Merge merges [Resolve] a and b into a single Resolve, for example to generate
+// one consistent set of bindings from several independently loaded WIT JSON files.
+//
+// Packages are identified by name and version, e.g. wasi:io@0.2.0. If a package is
+// present in both a and b, the package, its worlds and interfaces, and their types in a are
+// used, and every reference to their counterparts in b is rewritten to refer to them.
+// A package present in both must declare the same worlds and interfaces, with the same
+// types and functions, otherwise Merge returns an error.
+// The merged Resolve is normalized with [Resolve.Normalize].
+//
+// The nodes of a and b are reused rather than copied, so a and b should not be used after
+// calling Merge.
+func Merge(a, b *Resolve) (*Resolve, error) {
+	m := &merger{
+		worlds:     make(map[*World]*World),
+		interfaces: make(map[*Interface]*Interface),
+		types:      make(map[*TypeDef]*TypeDef),
+	}
+
+	packages := make(map[string]*Package)
+	for _, p := range a.Packages {
+		packages[p.Name.String()] = p
+	}
+	r := &Resolve{Packages: slices.Clone(a.Packages)}
+	for _, p := range b.Packages {
+		if ap, ok := packages[p.Name.String()]; ok {
+			err := m.mergePackage(ap, p)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		r.Packages = append(r.Packages, p)
+	}
+
+	r.Worlds = slices.Clone(a.Worlds)
+	for _, w := range b.Worlds {
+		if m.worlds[w] == nil {
+			r.Worlds = append(r.Worlds, w)
+		}
+	}
+	r.Interfaces = slices.Clone(a.Interfaces)
+	for _, i := range b.Interfaces {
+		if m.interfaces[i] == nil {
+			r.Interfaces = append(r.Interfaces, i)
+		}
+	}
+	r.TypeDefs = slices.Clone(a.TypeDefs)
+	for _, t := range b.TypeDefs {
+		if m.types[t] == nil {
+			r.TypeDefs = append(r.TypeDefs, t)
+		}
+	}
+
+	// Rewrite references from the remaining nodes of b.
+	b = &Resolve{
+		Worlds:     r.Worlds[len(a.Worlds):],
+		Interfaces: r.Interfaces[len(a.Interfaces):],
+		TypeDefs:   r.TypeDefs[len(a.TypeDefs):],
+	}
+	seen := make(map[*TypeDef]bool)
+	var replace func(t Type) Type
+	replace = func(t Type) Type {
+		td, ok := t.(*TypeDef)
+		if !ok {
+			return t
+		}
+		if u := m.types[td]; u != nil {
+			return u
+		}
+		if !seen[td] {
+			seen[td] = true
+			mapTypes(td, replace)
+		}
+		return td
+	}
+	for _, t := range b.TypeDefs {
+		replace(t)
+	}
+	b.allTypes(replace)
+	for _, w := range b.Worlds {
+		w.AllImportsAndExports()(func(_ string, item WorldItem) bool {
+			if ref, ok := item.(*InterfaceRef); ok && m.interfaces[ref.Interface] != nil {
+				ref.Interface = m.interfaces[ref.Interface]
+			}
+			return true
+		})
+	}
+
+	r.Normalize()
+	return r, nil
+}
+
+// merger maps the worlds, interfaces, and types of one [Resolve] to their
+// counterparts in another.
+type merger struct {
+	worlds     map[*World]*World
+	interfaces map[*Interface]*Interface
+	types      map[*TypeDef]*TypeDef
+}
+
+func (m *merger) mergePackage(a, b *Package) error {
+	if !sameKeys(&a.Interfaces, &b.Interfaces) || !sameKeys(&a.Worlds, &b.Worlds) {
+		return fmt.Errorf("conflicting definitions of package %s", a.Name.String())
+	}
+	var err error
+	b.Interfaces.All()(func(name string, i *Interface) bool {
+		err = m.mergeInterface(a.Interfaces.Get(name), i)
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+	b.Worlds.All()(func(name string, w *World) bool {
+		err = m.mergeWorld(a.Worlds.Get(name), w)
+		return err == nil
+	})
+	return err
+}
+
+func (m *merger) mergeWorld(a, b *World) error {
+	m.worlds[b] = a
+	for _, items := range [][2]*ordered.Map[string, WorldItem]{{&a.Imports, &b.Imports}, {&a.Exports, &b.Exports}} {
+		aItems := worldItems(items[0])
+		bItems := worldItems(items[1])
+		if len(aItems) != len(bItems) {
+			return fmt.Errorf("conflicting definitions of world %s/%s", a.Package.Name.String(), a.Name)
+		}
+		for key, item := range bItems {
+			aItem, ok := aItems[key]
+			if !ok {
+				return fmt.Errorf("conflicting definitions of world %s/%s", a.Package.Name.String(), a.Name)
+			}
+			switch item := item.(type) {
+			case *InterfaceRef:
+				if ar, ok := aItem.(*InterfaceRef); ok && item.Interface.Name == nil {
+					err := m.mergeInterface(ar.Interface, item.Interface)
+					if err != nil {
+						return err
+					}
+				}
+			case *TypeDef:
+				if at, ok := aItem.(*TypeDef); ok {
+					m.types[item] = at
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// worldItems returns the imports or exports of a [World], keyed by name.
+// Named interfaces are keyed by their package and interface name, e.g. wasi:io@0.2.0/streams,
+// as their key in a World depends on the order of interfaces in a WIT JSON file.
+func worldItems(items *ordered.Map[string, WorldItem]) map[string]WorldItem {
+	m := make(map[string]WorldItem, items.Len())
+	items.All()(func(name string, item WorldItem) bool {
+		if ref, ok := item.(*InterfaceRef); ok && ref.Interface.Name != nil {
+			name = ref.Interface.Package.Name.String() + "/" + *ref.Interface.Name
+		}
+		m[name] = item
+		return true
+	})
+	return m
+}
+
+func (m *merger) mergeInterface(a, b *Interface) error {
+	if !sameKeys(&a.TypeDefs, &b.TypeDefs) || !sameKeys(&a.Functions, &b.Functions) {
+		name := "anonymous interface"
+		if a.Name != nil {
+			name = "interface " + a.Package.Name.String() + "/" + *a.Name
+		}
+		return fmt.Errorf("conflicting definitions of %s", name)
+	}
+	m.interfaces[b] = a
+	b.TypeDefs.All()(func(name string, t *TypeDef) bool {
+		m.types[t] = a.TypeDefs.Get(name)
+		return true
+	})
+	return nil
+}
+
+// sameKeys reports whether ordered maps a and b have the same set of keys.
+func sameKeys[V any](a, b *ordered.Map[string, V]) bool {
+	if a.Len() != b.Len() {
+		return false
+	}
+	same := true
+	b.All()(func(k string, _ V) bool {
+		_, same = a.GetOK(k)
+		return same
+	})
+	return same
+}
@@ -0,0 +1,115 @@
+package wit
+
+import (
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	a, err := LoadJSON(testdataPath + "/wasi/cli.wit.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := LoadJSON(testdataPath + "/wasi/http.wit.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := Merge(a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = res.Validate()
+	if err != nil {
+		t.Errorf("Validate(): %v", err)
+	}
+
+	packages := make(map[string]bool)
+	for _, p := range res.Packages {
+		name := p.Name.String()
+		if packages[name] {
+			t.Errorf("Merge(): duplicate package %s", name)
+		}
+		packages[name] = true
+	}
+	for _, name := range []string{"wasi:cli@0.2.0", "wasi:http@0.2.0", "wasi:io@0.2.0"} {
+		if !packages[name] {
+			t.Errorf("Merge(): missing package %s", name)
+		}
+	}
+
+	worlds := make(map[*World]bool)
+	for _, w := range res.Worlds {
+		worlds[w] = true
+	}
+	interfaces := make(map[*Interface]bool)
+	for _, i := range res.Interfaces {
+		interfaces[i] = true
+	}
+	for _, w := range res.Worlds {
+		w.AllImportsAndExports()(func(name string, item WorldItem) bool {
+			if ref, ok := item.(*InterfaceRef); ok && !interfaces[ref.Interface] {
+				t.Errorf("world %s item %s: interface not in merged Resolve", w.Name, name)
+			}
+			return true
+		})
+	}
+	for _, td := range res.TypeDefs {
+		switch owner := td.Owner.(type) {
+		case *World:
+			if !worlds[owner] {
+				t.Errorf("type %s: owner world %s not in merged Resolve", td.TypeName(), owner.Name)
+			}
+		case *Interface:
+			if !interfaces[owner] {
+				t.Errorf("type %s: owner interface not in merged Resolve", td.TypeName())
+			}
+		}
+	}
+}
+
+func TestMergeIdentical(t *testing.T) {
+	path := testdataPath + "/wasi/cli.wit.json"
+	a, err := LoadJSON(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := LoadJSON(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := LoadJSON(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want.Normalize()
+	res, err := Merge(a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(res.Interfaces), len(want.Interfaces); got != want {
+		t.Errorf("Merge(): %d interfaces, expected %d", got, want)
+	}
+	if got, want := res.WIT(nil, ""), want.WIT(nil, ""); got != want {
+		t.Errorf("Merge(): WIT differs from input:\n%s", got)
+	}
+}
+
+func TestMergeConflict(t *testing.T) {
+	path := testdataPath + "/wasi/cli.wit.json"
+	a, err := LoadJSON(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := LoadJSON(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, i := range b.Interfaces {
+		if i.Name != nil && *i.Name == "environment" {
+			i.Functions.Delete("get-arguments")
+		}
+	}
+	_, err = Merge(a, b)
+	if err == nil {
+		t.Errorf("Merge(): expected error for conflicting interface definitions")
+	}
+}
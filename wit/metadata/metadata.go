@@ -0,0 +1,418 @@
+// Package metadata encodes a [WIT] world as the component-type metadata embedded in core
+// WebAssembly modules, without [wasm-tools].
+//
+// A core module with component-type metadata can be converted into a WebAssembly component
+// with wasm-tools component new, equivalent to embedding the world with wasm-tools component embed.
+//
+// [WIT]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/WIT.md
+// [wasm-tools]: https://crates.io/crates/wasm-tools
+package metadata
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bytecodealliance/wasm-tools-go/internal/wasm"
+	"github.com/bytecodealliance/wasm-tools-go/wit"
+)
+
+// encodingVersion is the version of the wit-component-encoding custom section.
+const encodingVersion = 0x04
+
+// encodingUTF8 is the wit-component-encoding value for UTF-8 strings, used by Go.
+const encodingUTF8 = 0x00
+
+// customSectionPrefix is the name prefix of custom sections with component-type metadata.
+const customSectionPrefix = "component-type"
+
+// Embed returns a copy of core WebAssembly module with the component-type metadata
+// for [wit.World] w, replacing any existing component-type custom sections.
+func Embed(module []byte, w *wit.World) ([]byte, error) {
+	h, sections, err := wasm.ReadSections(module)
+	if err != nil {
+		return nil, err
+	}
+	if h.IsComponent() {
+		return nil, errors.New("cannot embed metadata in a WebAssembly component")
+	}
+	payload, err := Encode(w)
+	if err != nil {
+		return nil, err
+	}
+
+	b := wasm.AppendHeader(make([]byte, 0, len(module)+len(payload)), h)
+	for _, s := range sections {
+		if s.ID == wasm.SectionCustom {
+			name, _, err := wasm.CustomSection(s.Data)
+			if err != nil {
+				return nil, err
+			}
+			if strings.HasPrefix(name, customSectionPrefix) {
+				continue
+			}
+		}
+		b = wasm.AppendSection(b, s.ID, s.Data)
+	}
+	return wasm.AppendCustomSection(b, customSectionPrefix+":"+w.Name, payload), nil
+}
+
+// Encode encodes [wit.World] w as a WebAssembly component, in the format of the payload
+// of a component-type custom section.
+func Encode(w *wit.World) ([]byte, error) {
+	if w.Package == nil {
+		return nil, fmt.Errorf("world %s has no package", w.Name)
+	}
+	e := &encoder{instances: make(map[*wit.Interface]uint32)}
+	world := e.world(w)
+	if e.err != nil {
+		return nil, fmt.Errorf("world %s: %w", w.Name, e.err)
+	}
+
+	// The world component type is exported from an outer component type with its
+	// qualified name, which is exported from the component with its plain name.
+	id := w.Package.Name
+	id.Extension = w.Name
+	outer := newScope(nil, nil)
+	outer.decl(0x01, world...)
+	outer.export(id.String(), 0x04, 0)
+
+	b := wasm.AppendHeader(nil, wasm.Header{Version: 0x0d, Layer: wasm.LayerComponent})
+	b = wasm.AppendCustomSection(b, "wit-component-encoding", []byte{encodingVersion, encodingUTF8})
+	b = wasm.AppendSection(b, wasm.SectionType, vec(1, outer.componentType()))
+	var export []byte
+	export = append(export, 0x00)
+	export = wasm.AppendName(export, w.Name)
+	export = append(export, 0x03, 0x00, 0x00) // type 0, without type ascription
+	b = wasm.AppendSection(b, wasm.SectionExport, vec(1, export))
+	return b, nil
+}
+
+type encoder struct {
+	root       *scope
+	instances  map[*wit.Interface]uint32 // imported or exported interfaces
+	nInstances uint32
+	err        error
+}
+
+func (e *encoder) fail(err error) {
+	if e.err == nil {
+		e.err = err
+	}
+}
+
+// world returns the encoded component type of w.
+func (e *encoder) world(w *wit.World) []byte {
+	e.root = newScope(nil, w)
+	w.Imports.All()(func(name string, item wit.WorldItem) bool {
+		e.item(name, item, false)
+		return e.err == nil
+	})
+	w.Exports.All()(func(name string, item wit.WorldItem) bool {
+		e.item(name, item, true)
+		return e.err == nil
+	})
+	return e.root.componentType()
+}
+
+// item declares world item as an import or export of the world.
+func (e *encoder) item(name string, item wit.WorldItem, export bool) {
+	s := e.root
+	var sort byte
+	var idx uint32
+	switch item := item.(type) {
+	case *wit.InterfaceRef:
+		i := item.Interface
+		if i.Name != nil {
+			if i.Package == nil {
+				e.fail(fmt.Errorf("interface %s has no package", *i.Name))
+				return
+			}
+			id := i.Package.Name
+			id.Extension = *i.Name
+			name = id.String()
+		}
+		sort, idx = 0x05, e.instanceType(i)
+		if _, ok := e.instances[i]; !ok {
+			e.instances[i] = e.nInstances
+		}
+		e.nInstances++
+	case *wit.Function:
+		sort, idx = 0x01, s.funcType(e, item)
+	case *wit.TypeDef:
+		s.typeIndex(e, item)
+		return
+	default:
+		e.fail(fmt.Errorf("unsupported world item %T", item))
+		return
+	}
+	if export {
+		s.export(name, sort, idx)
+	} else {
+		s.imp(name, sort, idx)
+	}
+}
+
+// instanceType declares the instance type of interface i in the root scope, returning its type index.
+func (e *encoder) instanceType(i *wit.Interface) uint32 {
+	s := newScope(e.root, i)
+	i.TypeDefs.All()(func(_ string, t *wit.TypeDef) bool {
+		s.typeIndex(e, t)
+		return e.err == nil
+	})
+	i.Functions.All()(func(name string, f *wit.Function) bool {
+		s.export(name, 0x01, s.funcType(e, f))
+		return e.err == nil
+	})
+	b := append([]byte{0x42}, vec(s.n, s.decls)...)
+	return e.root.defType(b)
+}
+
+// scope is a component or instance type under construction.
+type scope struct {
+	parent *scope
+	owner  wit.TypeOwner // the owner of named types defined in this scope
+	decls  []byte
+	n      int // number of declarations
+	nTypes uint32
+	types  map[*wit.TypeDef]uint32
+}
+
+func newScope(parent *scope, owner wit.TypeOwner) *scope {
+	return &scope{parent: parent, owner: owner, types: make(map[*wit.TypeDef]uint32)}
+}
+
+func (s *scope) componentType() []byte {
+	return append([]byte{0x41}, vec(s.n, s.decls)...)
+}
+
+func (s *scope) decl(code byte, b ...byte) {
+	s.decls = append(s.decls, code)
+	s.decls = append(s.decls, b...)
+	s.n++
+}
+
+// defType declares type definition b, returning its type index.
+func (s *scope) defType(b []byte) uint32 {
+	s.decl(0x01, b...)
+	s.nTypes++
+	return s.nTypes - 1
+}
+
+// export declares an export of sort and type index idx,
+// returning the type index of exported types.
+func (s *scope) export(name string, sort byte, idx uint32) uint32 {
+	return s.extern(0x04, name, sort, idx)
+}
+
+// imp declares an import of sort and type index idx,
+// returning the type index of imported types.
+func (s *scope) imp(name string, sort byte, idx uint32) uint32 {
+	return s.extern(0x03, name, sort, idx)
+}
+
+func (s *scope) extern(code byte, name string, sort byte, idx uint32) uint32 {
+	b := wasm.AppendName([]byte{0x00}, name)
+	b = append(b, sort)
+	if sort == 0x03 {
+		b = append(b, 0x00) // eq
+	}
+	s.decl(code, wasm.AppendU32(b, idx)...)
+	if sort == 0x03 {
+		s.nTypes++
+	}
+	return s.nTypes - 1
+}
+
+// resource declares a resource type named name, returning its type index.
+func (s *scope) resource(name string) uint32 {
+	code := byte(0x04)
+	if s.parent == nil {
+		code = 0x03
+	}
+	b := wasm.AppendName([]byte{0x00}, name)
+	s.decl(code, append(b, 0x03, 0x01)...) // sub resource
+	s.nTypes++
+	return s.nTypes - 1
+}
+
+// typeIndex returns the type index of t in s, declaring it if necessary.
+// Named types are exported from instance types, or imported into the world component type.
+// Named types owned by another interface are aliased from its instance.
+func (s *scope) typeIndex(e *encoder, t *wit.TypeDef) uint32 {
+	if idx, ok := s.types[t]; ok {
+		return idx
+	}
+	var idx uint32
+	switch {
+	case t.Name != nil && t.Owner != s.owner:
+		if s.parent != nil {
+			outer := s.parent.typeIndex(e, t)
+			b := append([]byte{0x03, 0x02, 0x01}, wasm.AppendU32(nil, outer)...)
+			s.decl(0x02, b...) // alias outer 1 outer (type)
+		} else {
+			i, ok := t.Owner.(*wit.Interface)
+			inst, found := e.instances[i]
+			if !ok || !found {
+				e.fail(fmt.Errorf("type %s: owner is not imported or exported before use", *t.Name))
+				return 0
+			}
+			b := append([]byte{0x03, 0x00}, wasm.AppendU32(nil, inst)...)
+			s.decl(0x02, wasm.AppendName(b, *t.Name)...) // alias export inst name (type)
+		}
+		s.nTypes++
+		idx = s.nTypes - 1
+	case t.Name != nil:
+		if _, ok := t.Kind.(*wit.Resource); ok {
+			idx = s.resource(*t.Name)
+			break
+		}
+		idx = s.kindIndex(e, t.Kind)
+		if s.parent == nil {
+			idx = s.imp(*t.Name, 0x03, idx)
+		} else {
+			idx = s.export(*t.Name, 0x03, idx)
+		}
+	default:
+		idx = s.kindIndex(e, t.Kind)
+	}
+	s.types[t] = idx
+	return idx
+}
+
+// kindIndex returns the type index of type definition kind in s.
+func (s *scope) kindIndex(e *encoder, kind wit.TypeDefKind) uint32 {
+	if t, ok := kind.(*wit.TypeDef); ok {
+		return s.typeIndex(e, t)
+	}
+	var b []byte
+	if t, ok := kind.(wit.Type); ok {
+		b = s.valType(e, t)
+	} else {
+		b = s.valTypeDef(e, kind)
+	}
+	return s.defType(b)
+}
+
+// valTypeDef returns the encoded value type definition of kind.
+func (s *scope) valTypeDef(e *encoder, kind wit.TypeDefKind) []byte {
+	var b []byte
+	switch kind := kind.(type) {
+	case *wit.Record:
+		b = wasm.AppendU32(append(b, 0x72), uint32(len(kind.Fields)))
+		for _, f := range kind.Fields {
+			b = wasm.AppendName(b, f.Name)
+			b = append(b, s.valType(e, f.Type)...)
+		}
+	case *wit.Variant:
+		b = wasm.AppendU32(append(b, 0x71), uint32(len(kind.Cases)))
+		for _, c := range kind.Cases {
+			b = wasm.AppendName(b, c.Name)
+			b = append(b, s.optionalValType(e, c.Type)...)
+			b = append(b, 0x00) // no refines
+		}
+	case *wit.List:
+		b = append(append(b, 0x70), s.valType(e, kind.Type)...)
+	case *wit.Tuple:
+		b = wasm.AppendU32(append(b, 0x6f), uint32(len(kind.Types)))
+		for _, t := range kind.Types {
+			b = append(b, s.valType(e, t)...)
+		}
+	case *wit.Flags:
+		b = wasm.AppendU32(append(b, 0x6e), uint32(len(kind.Flags)))
+		for _, f := range kind.Flags {
+			b = wasm.AppendName(b, f.Name)
+		}
+	case *wit.Enum:
+		b = wasm.AppendU32(append(b, 0x6d), uint32(len(kind.Cases)))
+		for _, c := range kind.Cases {
+			b = wasm.AppendName(b, c.Name)
+		}
+	case *wit.Option:
+		b = append(append(b, 0x6b), s.valType(e, kind.Type)...)
+	case *wit.Result:
+		b = append(append(b, 0x6a), s.optionalValType(e, kind.OK)...)
+		b = append(b, s.optionalValType(e, kind.Err)...)
+	case *wit.Own:
+		b = wasm.AppendU32(append(b, 0x69), s.typeIndex(e, kind.Type))
+	case *wit.Borrow:
+		b = wasm.AppendU32(append(b, 0x68), s.typeIndex(e, kind.Type))
+	case *wit.Future:
+		b = append(append(b, 0x65), s.optionalValType(e, kind.Type)...)
+	case *wit.Stream:
+		b = append(append(b, 0x66), s.optionalValType(e, kind.Element)...)
+	default:
+		e.fail(fmt.Errorf("unsupported type %T", kind))
+	}
+	return b
+}
+
+// valType returns the encoding of t in value type position:
+// a primitive type code, or the index of a type definition.
+func (s *scope) valType(e *encoder, t wit.Type) []byte {
+	switch t := t.(type) {
+	case *wit.TypeDef:
+		return wasm.AppendS33(nil, int64(s.typeIndex(e, t)))
+	case wit.Bool:
+		return []byte{0x7f}
+	case wit.S8:
+		return []byte{0x7e}
+	case wit.U8:
+		return []byte{0x7d}
+	case wit.S16:
+		return []byte{0x7c}
+	case wit.U16:
+		return []byte{0x7b}
+	case wit.S32:
+		return []byte{0x7a}
+	case wit.U32:
+		return []byte{0x79}
+	case wit.S64:
+		return []byte{0x78}
+	case wit.U64:
+		return []byte{0x77}
+	case wit.F32:
+		return []byte{0x76}
+	case wit.F64:
+		return []byte{0x75}
+	case wit.Char:
+		return []byte{0x74}
+	case wit.String:
+		return []byte{0x73}
+	}
+	e.fail(fmt.Errorf("unsupported type %T", t))
+	return []byte{0x00}
+}
+
+func (s *scope) optionalValType(e *encoder, t wit.Type) []byte {
+	if t == nil {
+		return []byte{0x00}
+	}
+	return append([]byte{0x01}, s.valType(e, t)...)
+}
+
+// funcType declares the function type of f, returning its type index.
+func (s *scope) funcType(e *encoder, f *wit.Function) uint32 {
+	b := s.params(e, []byte{0x40}, f.Params...)
+	if len(f.Results) == 1 && f.Results[0].Name == "" {
+		b = append(append(b, 0x00), s.valType(e, f.Results[0].Type)...)
+	} else {
+		b = s.params(e, append(b, 0x01), f.Results...)
+	}
+	return s.defType(b)
+}
+
+// params appends the encoding of a vector of named params to b.
+func (s *scope) params(e *encoder, b []byte, params ...wit.Param) []byte {
+	b = wasm.AppendU32(b, uint32(len(params)))
+	for _, p := range params {
+		b = wasm.AppendName(b, p.Name)
+		b = append(b, s.valType(e, p.Type)...)
+	}
+	return b
+}
+
+// vec returns the encoding of a vector of n encoded items b.
+func vec(n int, b []byte) []byte {
+	return append(wasm.AppendU32(nil, uint32(n)), b...)
+}
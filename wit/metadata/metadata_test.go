@@ -0,0 +1,178 @@
+package metadata
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bytecodealliance/wasm-tools-go/internal/relpath"
+	"github.com/bytecodealliance/wasm-tools-go/internal/wasm"
+	"github.com/bytecodealliance/wasm-tools-go/wit"
+	"github.com/bytecodealliance/wasm-tools-go/wit/extract"
+)
+
+const testdataPath = "../../testdata"
+
+func loadTestdata(f func(path string, res *wit.Resolve) error) error {
+	return relpath.Walk(testdataPath, func(path string) error {
+		res, err := wit.LoadJSON(path)
+		if err != nil {
+			return err
+		}
+		return f(path, res)
+	}, "*.wit.json")
+}
+
+// stripDocs removes documentation and feature gates from res,
+// which are not part of the binary encoding.
+func stripDocs(res *wit.Resolve) {
+	for _, w := range res.Worlds {
+		w.Docs, w.Stability = wit.Docs{}, nil
+		w.AllImportsAndExports()(func(_ string, item wit.WorldItem) bool {
+			if ref, ok := item.(*wit.InterfaceRef); ok {
+				ref.Stability = nil
+			}
+			return true
+		})
+	}
+	for _, i := range res.Interfaces {
+		i.Docs, i.Stability = wit.Docs{}, nil
+	}
+	for _, t := range res.TypeDefs {
+		t.Docs, t.Stability = wit.Docs{}, nil
+		switch kind := t.Kind.(type) {
+		case *wit.Record:
+			for i := range kind.Fields {
+				kind.Fields[i].Docs = wit.Docs{}
+			}
+		case *wit.Variant:
+			for i := range kind.Cases {
+				kind.Cases[i].Docs = wit.Docs{}
+			}
+		case *wit.Enum:
+			for i := range kind.Cases {
+				kind.Cases[i].Docs = wit.Docs{}
+			}
+		case *wit.Flags:
+			for i := range kind.Flags {
+				kind.Flags[i].Docs = wit.Docs{}
+			}
+		}
+	}
+	res.AllFunctions()(func(f *wit.Function) bool {
+		f.Docs, f.Stability = wit.Docs{}, nil
+		return true
+	})
+}
+
+// interfaceName returns the qualified name of i, or name if i is anonymous.
+func interfaceName(name string, i *wit.Interface) string {
+	if i.Name == nil {
+		return name
+	}
+	id := i.Package.Name
+	id.Extension = *i.Name
+	return id.String()
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	err := loadTestdata(func(path string, res *wit.Resolve) error {
+		stripDocs(res)
+		for _, w := range res.Worlds {
+			t.Run(path+"#"+w.Name, func(t *testing.T) {
+				b, err := Encode(w)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got, err := extract.Decode(b)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var gw *wit.World
+				for _, w2 := range got.Worlds {
+					if w2.Name == w.Name && w2.Package.Name.String() == w.Package.Name.String() {
+						gw = w2
+					}
+				}
+				if gw == nil {
+					t.Fatalf("Decode: world %s not found", w.Name)
+				}
+				if got, want := gw.WIT(nil, ""), w.WIT(nil, ""); got != want {
+					t.Errorf("world WIT:\n%s\nexpected:\n%s", got, want)
+				}
+				want := make(map[string]string)
+				w.AllInterfaces()(func(name string, i *wit.Interface) bool {
+					want[interfaceName(name, i)] = i.WIT(nil, "")
+					return true
+				})
+				gw.AllInterfaces()(func(name string, i *wit.Interface) bool {
+					name = interfaceName(name, i)
+					if got := i.WIT(nil, ""); got != want[name] {
+						t.Errorf("interface %s WIT:\n%s\nexpected:\n%s", name, got, want[name])
+					}
+					return true
+				})
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestEmbed(t *testing.T) {
+	res, err := wit.LoadJSON(testdataPath + "/wasi/cli.wit.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var w *wit.World
+	for _, w2 := range res.Worlds {
+		if w2.Match("wasi:cli/command") {
+			w = w2
+		}
+	}
+
+	module := wasm.AppendHeader(nil, wasm.Header{Version: 1, Layer: wasm.LayerModule})
+	module = wasm.AppendSection(module, wasm.SectionCoreType, []byte{0x00})
+	module = wasm.AppendCustomSection(module, "component-type:old", []byte{0xff})
+	module = wasm.AppendCustomSection(module, "producers", []byte{0x00})
+
+	b, err := Embed(module, w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, sections, err := wasm.ReadSections(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, s := range sections {
+		if s.ID != wasm.SectionCustom {
+			names = append(names, "")
+			continue
+		}
+		name, _, _ := wasm.CustomSection(s.Data)
+		names = append(names, name)
+	}
+	if got, want := strings.Join(names, ","), ",producers,component-type:command"; got != want {
+		t.Errorf("Embed: sections %s, expected %s", got, want)
+	}
+
+	got, err := extract.Decode(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Worlds) != 1 || !got.Worlds[0].Match("wasi:cli/command@0.2.0") {
+		t.Errorf("Decode: expected world wasi:cli/command@0.2.0")
+	}
+
+	_, err = Embed(b[:len(b)-1], w)
+	if err == nil {
+		t.Error("Embed: expected error for truncated module")
+	}
+	c, _ := Encode(w)
+	_, err = Embed(c, w)
+	if err == nil {
+		t.Error("Embed: expected error for component")
+	}
+}
@@ -0,0 +1,235 @@
+package wit
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Normalize rewrites [Resolve] r into a canonical form, which is useful after merging
+// or building a Resolve from multiple sources. It:
+//
+//   - de-duplicates structurally identical anonymous [TypeDef] values, such as two
+//     instances of list<u8>, replacing every reference with a single TypeDef,
+//   - adds any TypeDef referenced by r that is missing from r.TypeDefs,
+//   - drops any TypeDef not referenced by a [World] or [Interface] in r, and
+//   - sorts r.TypeDefs topologically, so each TypeDef follows the types it references.
+//
+// Named types are never de-duplicated, and the relative order of independent types is preserved.
+// The resulting invariants are checked by [Resolve.Validate].
+func (r *Resolve) Normalize() {
+	// Sort topologically, including any missing types, so each anonymous type is
+	// canonicalized after the types it references.
+	var sorted []*TypeDef
+	seen := make(map[*TypeDef]bool)
+	var visit func(t *TypeDef)
+	visit = func(t *TypeDef) {
+		if t == nil || seen[t] {
+			return
+		}
+		seen[t] = true
+		mapTypes(t, func(u Type) Type {
+			if td, ok := u.(*TypeDef); ok {
+				visit(td)
+			}
+			return u
+		})
+		sorted = append(sorted, t)
+	}
+	for _, t := range r.TypeDefs {
+		visit(t)
+	}
+	r.allTypes(func(u Type) Type {
+		if td, ok := u.(*TypeDef); ok {
+			visit(td)
+		}
+		return u
+	})
+
+	// De-duplicate anonymous types.
+	canon := make(map[*TypeDef]*TypeDef)
+	keys := make(map[string]*TypeDef)
+	replace := func(u Type) Type {
+		if td, ok := u.(*TypeDef); ok && canon[td] != nil {
+			return canon[td]
+		}
+		return u
+	}
+	for _, t := range sorted {
+		mapTypes(t, replace)
+		if t.Name != nil {
+			continue
+		}
+		key := typeKey(t)
+		if c, ok := keys[key]; ok {
+			canon[t] = c
+		} else {
+			keys[key] = t
+		}
+	}
+	if len(canon) > 0 {
+		r.allTypes(replace)
+	}
+
+	// Drop unreferenced types.
+	reachable := make(map[*TypeDef]bool)
+	for _, w := range r.Worlds {
+		for _, t := range DependencyGraph(w).TypeDefs() {
+			reachable[t] = true
+		}
+	}
+	for _, i := range r.Interfaces {
+		for _, t := range DependencyGraph(i).TypeDefs() {
+			reachable[t] = true
+		}
+	}
+
+	r.TypeDefs = r.TypeDefs[:0]
+	for _, t := range sorted {
+		if canon[t] == nil && reachable[t] {
+			r.TypeDefs = append(r.TypeDefs, t)
+		}
+	}
+}
+
+// allTypes calls f for each [Type] referenced by a [Function], [World], or [Interface] in r.
+// Types referenced by functions are replaced with the result of f if it differs.
+// It does not descend into the types themselves. See [mapTypes].
+func (r *Resolve) allTypes(f func(Type) Type) {
+	m := func(p *Type) {
+		if *p != nil {
+			if u := f(*p); u != *p {
+				*p = u
+			}
+		}
+	}
+	r.AllFunctions()(func(fn *Function) bool {
+		for i := range fn.Params {
+			m(&fn.Params[i].Type)
+		}
+		for i := range fn.Results {
+			m(&fn.Results[i].Type)
+		}
+		switch kind := fn.Kind.(type) {
+		case *Method:
+			m(&kind.Type)
+		case *Static:
+			m(&kind.Type)
+		case *Constructor:
+			m(&kind.Type)
+		}
+		return true
+	})
+	for _, w := range r.Worlds {
+		w.AllImportsAndExports()(func(_ string, item WorldItem) bool {
+			if t, ok := item.(*TypeDef); ok {
+				f(t)
+			}
+			return true
+		})
+	}
+	for _, i := range r.Interfaces {
+		i.TypeDefs.All()(func(_ string, t *TypeDef) bool {
+			f(t)
+			return true
+		})
+	}
+}
+
+// mapTypes calls f for each [Type] that comprises [TypeDef] t, such as record fields
+// or variant cases, and replaces the type with the result of f if it differs.
+// Optional types that are nil are skipped.
+func mapTypes(t *TypeDef, f func(Type) Type) {
+	m := func(p *Type) {
+		if *p != nil {
+			if u := f(*p); u != *p {
+				*p = u
+			}
+		}
+	}
+	handle := func(p **TypeDef) {
+		if *p != nil {
+			if u, ok := f(*p).(*TypeDef); ok && u != *p {
+				*p = u
+			}
+		}
+	}
+	switch kind := t.Kind.(type) {
+	case *TypeDef:
+		if u := f(kind); u != Type(kind) {
+			t.Kind = u
+		}
+	case *Pointer:
+		m(&kind.Type)
+	case *Record:
+		for i := range kind.Fields {
+			m(&kind.Fields[i].Type)
+		}
+	case *Tuple:
+		for i := range kind.Types {
+			m(&kind.Types[i])
+		}
+	case *Variant:
+		for i := range kind.Cases {
+			m(&kind.Cases[i].Type)
+		}
+	case *Option:
+		m(&kind.Type)
+	case *Result:
+		m(&kind.OK)
+		m(&kind.Err)
+	case *List:
+		m(&kind.Type)
+	case *Own:
+		handle(&kind.Type)
+	case *Borrow:
+		handle(&kind.Type)
+	case *Future:
+		m(&kind.Type)
+	case *Stream:
+		m(&kind.Element)
+		m(&kind.End)
+	}
+}
+
+// typeKey returns a string that uniquely identifies the structure of [TypeDef] t,
+// where each [TypeDef] that comprises t is identified by its address.
+func typeKey(t *TypeDef) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%T(", t.Kind)
+	switch kind := t.Kind.(type) {
+	case *Record:
+		for _, f := range kind.Fields {
+			b.WriteString(f.Name + " ")
+		}
+	case *Variant:
+		for _, c := range kind.Cases {
+			fmt.Fprintf(&b, "%s:%t ", c.Name, c.Type != nil)
+		}
+	case *Enum:
+		for _, c := range kind.Cases {
+			b.WriteString(c.Name + " ")
+		}
+	case *Flags:
+		for _, f := range kind.Flags {
+			b.WriteString(f.Name + " ")
+		}
+	case *Result:
+		// Distinguish result<T> from result<_, T>.
+		fmt.Fprintf(&b, "%t %t ", kind.OK != nil, kind.Err != nil)
+	case *Stream:
+		fmt.Fprintf(&b, "%t %t ", kind.Element != nil, kind.End != nil)
+	case *Resource:
+		// Each resource is unique.
+		fmt.Fprintf(&b, "%p", t)
+	}
+	mapTypes(t, func(u Type) Type {
+		if td, ok := u.(*TypeDef); ok {
+			fmt.Fprintf(&b, "%p ", td)
+		} else {
+			fmt.Fprintf(&b, "%T ", u)
+		}
+		return u
+	})
+	b.WriteString(")")
+	return b.String()
+}
@@ -0,0 +1,47 @@
+package wit
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	name := func(s string) *string { return &s }
+	pkg := &Package{Name: Ident{Namespace: "foo", Package: "bar"}}
+	face := &Interface{Name: name("i"), Package: pkg}
+	u8s1 := &TypeDef{Kind: &List{Type: U8{}}}
+	u8s2 := &TypeDef{Kind: &List{Type: U8{}}}
+	option1 := &TypeDef{Kind: &Option{Type: u8s1}}
+	option2 := &TypeDef{Kind: &Option{Type: u8s2}}
+	strs := &TypeDef{Kind: &List{Type: String{}}}
+	orphan := &TypeDef{Kind: &List{Type: U16{}}}
+	blob := &TypeDef{Name: name("blob"), Kind: &Record{Fields: []Field{{Name: "data", Type: option2}}}, Owner: face}
+	face.TypeDefs.Set("blob", blob)
+	face.Functions.Set("f", &Function{
+		Name:    "f",
+		Kind:    &Freestanding{},
+		Params:  []Param{{Name: "a", Type: option1}, {Name: "b", Type: blob}},
+		Results: []Param{{Type: strs}},
+	})
+
+	res := &Resolve{
+		Packages:   []*Package{pkg},
+		Interfaces: []*Interface{face},
+		// Out of order, with duplicates, an orphan, and without strs.
+		TypeDefs: []*TypeDef{blob, option2, u8s2, orphan, option1, u8s1},
+	}
+	res.Normalize()
+
+	want := []*TypeDef{u8s2, option2, blob, strs}
+	if !slices.Equal(res.TypeDefs, want) {
+		t.Errorf("TypeDefs: %v, expected %v", res.TypeDefs, want)
+	}
+	f := face.Functions.Get("f")
+	if got, want := f.Params[0].Type, Type(option2); got != want {
+		t.Errorf("f.Params[0].Type: %p, expected %p", got, want)
+	}
+	err := res.Validate()
+	if err != nil {
+		t.Errorf("Validate(): %v", err)
+	}
+}
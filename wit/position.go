@@ -0,0 +1,40 @@
+package wit
+
+import (
+	"strconv"
+)
+
+// Position describes a location in a WIT source file.
+// It is decoded from the optional "position" field of worlds, interfaces, types,
+// and functions in JSON, if the tool that produced the JSON emits it.
+// The zero value is an unknown position.
+type Position struct {
+	Filename string // WIT file name, if any
+	Line     int    // line number, starting at 1 (0 if unknown)
+	Column   int    // column number in bytes, starting at 1 (0 if unknown)
+}
+
+// IsValid reports whether [Position] p is known.
+func (p Position) IsValid() bool {
+	return p.Line > 0
+}
+
+// String returns a description of [Position] p in one of the forms
+// file:line:column, file:line, line:column, line, or file.
+// It returns "-" if p is unknown and has no file name.
+func (p Position) String() string {
+	s := p.Filename
+	if p.IsValid() {
+		if s != "" {
+			s += ":"
+		}
+		s += strconv.Itoa(p.Line)
+		if p.Column > 0 {
+			s += ":" + strconv.Itoa(p.Column)
+		}
+	}
+	if s == "" {
+		s = "-"
+	}
+	return s
+}
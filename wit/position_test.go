@@ -0,0 +1,103 @@
+package wit
+
+import (
+	"strings"
+	"testing"
+)
+
+// positionJSON is hand-authored WIT JSON with source positions:
+//
+//	package foo:bar;
+//
+//	interface i {
+//		type t = u32;
+//		f: func();
+//	}
+//
+//	world w {
+//		import i;
+//	}
+const positionJSON = `{
+	"worlds": [
+		{
+			"name": "w",
+			"imports": {
+				"interface-0": {"interface": {"id": 0}}
+			},
+			"exports": {},
+			"package": 0,
+			"position": {"file": "foo.wit", "line": 8, "column": 1}
+		}
+	],
+	"interfaces": [
+		{
+			"name": "i",
+			"types": {"t": 0},
+			"functions": {
+				"f": {"name": "f", "kind": "freestanding", "params": [], "results": [], "position": {"file": "foo.wit", "line": 5, "column": 2}}
+			},
+			"package": 0,
+			"position": {"file": "foo.wit", "line": 3, "column": 1}
+		}
+	],
+	"types": [
+		{"name": "t", "kind": {"type": "u32"}, "owner": {"interface": 0}, "position": {"file": "foo.wit", "line": 4, "column": 2}}
+	],
+	"packages": [
+		{
+			"name": "foo:bar",
+			"interfaces": {"i": 0},
+			"worlds": {"w": 0}
+		}
+	]
+}`
+
+func TestDecodePosition(t *testing.T) {
+	res, err := DecodeJSON(strings.NewReader(positionJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	i := res.Interfaces[0]
+	tests := []struct {
+		node string
+		pos  Position
+		want string
+	}{
+		{"world w", res.Worlds[0].Pos, "foo.wit:8:1"},
+		{"interface i", i.Pos, "foo.wit:3:1"},
+		{"type t", i.TypeDefs.Get("t").Pos, "foo.wit:4:2"},
+		{"function f", i.Functions.Get("f").Pos, "foo.wit:5:2"},
+	}
+	for _, tt := range tests {
+		if got := tt.pos.String(); got != tt.want {
+			t.Errorf("%s: Pos: %q, expected %q", tt.node, got, tt.want)
+		}
+	}
+
+	res, err = DecodeJSON(strings.NewReader(includeJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pos := res.Worlds[0].Pos; pos.IsValid() {
+		t.Errorf("world %s: Pos: %v, expected unknown position", res.Worlds[0].Name, pos)
+	}
+}
+
+func TestPositionString(t *testing.T) {
+	tests := []struct {
+		pos  Position
+		want string
+	}{
+		{Position{}, "-"},
+		{Position{Filename: "foo.wit"}, "foo.wit"},
+		{Position{Line: 3}, "3"},
+		{Position{Line: 3, Column: 5}, "3:5"},
+		{Position{Filename: "foo.wit", Line: 3}, "foo.wit:3"},
+		{Position{Filename: "foo.wit", Line: 3, Column: 5}, "foo.wit:3:5"},
+	}
+	for _, tt := range tests {
+		if got := tt.pos.String(); got != tt.want {
+			t.Errorf("%#v.String(): %q, expected %q", tt.pos, got, tt.want)
+		}
+	}
+}
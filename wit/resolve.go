@@ -6,6 +6,7 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"unsafe"
 
 	"github.com/bytecodealliance/wasm-tools-go/wit/iterate"
@@ -57,9 +58,11 @@ type World struct {
 	Name      string
 	Imports   ordered.Map[string, WorldItem]
 	Exports   ordered.Map[string, WorldItem]
-	Package   *Package  // the Package this World belongs to (must be non-nil)
-	Stability Stability // WIT @since or @unstable (nil if unknown)
+	Includes  []*Include // unresolved include statements, see [Resolve.ResolveIncludes]
+	Package   *Package   // the Package this World belongs to (must be non-nil)
+	Stability Stability  // WIT @since or @unstable (nil if unknown)
 	Docs      Docs
+	Pos       Position // position in WIT source (zero if unknown)
 }
 
 // WITPackage returns the [Package] this [World] belongs to.
@@ -93,6 +96,7 @@ func (w *World) HasInterface(i *Interface) bool {
 }
 
 // AllInterfaces returns a [sequence] that yields each [Interface] in a [World].
+// An Interface both imported and exported by w is yielded once for each name.
 // The sequence stops if yield returns false.
 //
 // [sequence]: https://github.com/golang/go/issues/61897
@@ -122,6 +126,38 @@ func (w *World) AllFunctions() iterate.Seq[*Function] {
 	}
 }
 
+// AllFunctionsDeep returns a [sequence] that yields each [Function] in a [World],
+// followed by each Function in each [Interface] imported or exported by the World.
+// Unlike [World.AllFunctions], it describes every function a component built from w
+// would import or export. Each Function is yielded at most once.
+// The sequence stops if yield returns false.
+//
+// [sequence]: https://github.com/golang/go/issues/61897
+func (w *World) AllFunctionsDeep() iterate.Seq[*Function] {
+	return func(yield func(*Function) bool) {
+		var done bool
+		yield = iterate.Done(iterate.Once(yield), func() { done = true })
+		seen := make(map[*Function]bool)
+		f := func(f *Function) bool {
+			if seen[f] {
+				return true
+			}
+			seen[f] = true
+			return yield(f)
+		}
+		w.AllFunctions()(f)
+		faces := make(map[*Interface]bool)
+		w.AllInterfaces()(func(_ string, i *Interface) bool {
+			if done || i == nil || faces[i] {
+				return !done
+			}
+			faces[i] = true
+			i.AllFunctions()(f)
+			return !done
+		})
+	}
+}
+
 // AllImportsAndExports returns a [sequence] that yields each [WorldItem] in a [World].
 // The sequence stops if yield returns false.
 //
@@ -178,6 +214,7 @@ type Interface struct {
 	Package   *Package  // the Package this Interface belongs to
 	Stability Stability // WIT @since or @unstable (nil if unknown)
 	Docs      Docs
+	Pos       Position // position in WIT source (zero if unknown)
 }
 
 // WITPackage returns the [Package] this [Interface] belongs to.
@@ -208,6 +245,17 @@ type TypeDef struct {
 	Owner     TypeOwner
 	Stability Stability // WIT @since or @unstable (nil if unknown)
 	Docs      Docs
+	Pos       Position // position in WIT source (zero if unknown)
+
+	abi atomic.Pointer[typeDefABI] // cached ABI of Kind
+}
+
+// typeDefABI is the cached size, alignment, and flat representation of a [TypeDefKind].
+type typeDefABI struct {
+	kind  TypeDefKind
+	size  uintptr
+	align uintptr
+	flat  []Type
 }
 
 // TypeName returns the [WIT] type name for t.
@@ -295,19 +343,39 @@ func (t *TypeDef) Methods() []*Function {
 
 // Size returns the byte size for values of type t.
 func (t *TypeDef) Size() uintptr {
-	return t.Kind.Size()
+	return t.cachedABI().size
 }
 
 // Align returns the byte alignment for values of type t.
 func (t *TypeDef) Align() uintptr {
-	return t.Kind.Align()
+	return t.cachedABI().align
 }
 
 // Flat returns the [flattened] ABI representation of t.
+// The returned slice is shared by subsequent calls, and must not be modified.
 //
 // [flattened]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md#flattening
 func (t *TypeDef) Flat() []Type {
-	return t.Kind.Flat()
+	return t.cachedABI().flat
+}
+
+// cachedABI returns the size, alignment, and flat representation of t.Kind, which are
+// computed on first use, and again if t.Kind is replaced. Types referenced by t.Kind
+// must not be changed to types with a different ABI after first use.
+// It is safe to call cachedABI concurrently.
+func (t *TypeDef) cachedABI() *typeDefABI {
+	if abi := t.abi.Load(); abi != nil && abi.kind == t.Kind {
+		return abi
+	}
+	flat := t.Kind.Flat()
+	abi := &typeDefABI{
+		kind:  t.Kind,
+		size:  t.Kind.Size(),
+		align: t.Kind.Align(),
+		flat:  flat[:len(flat):len(flat)], // appending to flat must copy
+	}
+	t.abi.Store(abi)
+	return abi
 }
 
 func (t *TypeDef) hasPointer() bool  { return HasPointer(t.Kind) }
@@ -391,7 +459,7 @@ func (r *Record) Size() uintptr {
 		s = Align(s, f.Type.Align())
 		s += f.Type.Size()
 	}
-	return s
+	return Align(s, r.Align())
 }
 
 // Align returns the [ABI byte alignment] for [Record] r.
@@ -1327,6 +1395,7 @@ type Function struct {
 	Results   []Param   // a function can have a single anonymous result, or > 1 named results
 	Stability Stability // WIT @since or @unstable (nil if unknown)
 	Docs      Docs
+	Pos       Position // position in WIT source (zero if unknown)
 }
 
 // BaseName returns the base name of [Function] f.
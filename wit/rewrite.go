@@ -0,0 +1,59 @@
+package wit
+
+// RewriteDocs replaces the documentation of each [Package], [World], [Interface], [TypeDef],
+// and [Function] in [Resolve] r, and each [Field], [Flag], [Case], and [EnumCase] of a TypeDef,
+// with the result of calling rewrite with the node and its documentation text.
+// Returning an empty string removes the documentation. Nodes without documentation are skipped.
+//
+// RewriteDocs can be used to remove internal links or confidential text from WIT before
+// generating code or serializing it to WIT text. rewrite is called once for each node.
+func (r *Resolve) RewriteDocs(rewrite func(node Node, docs string) string) {
+	seen := make(map[*Docs]bool)
+	update := func(node Node, d *Docs) {
+		if seen[d] || d.Contents == "" {
+			return
+		}
+		seen[d] = true
+		d.Contents = rewrite(node, d.Contents)
+	}
+
+	for _, p := range r.Packages {
+		update(p, &p.Docs)
+	}
+	for _, w := range r.Worlds {
+		update(w, &w.Docs)
+	}
+	for _, i := range r.Interfaces {
+		update(i, &i.Docs)
+	}
+	r.AllFunctions()(func(f *Function) bool {
+		update(f, &f.Docs)
+		return true
+	})
+	for _, t := range r.TypeDefs {
+		update(t, &t.Docs)
+		switch kind := t.Kind.(type) {
+		case *Record:
+			for i := range kind.Fields {
+				update(&kind.Fields[i], &kind.Fields[i].Docs)
+			}
+		case *Flags:
+			for i := range kind.Flags {
+				update(&kind.Flags[i], &kind.Flags[i].Docs)
+			}
+		case *Variant:
+			for i := range kind.Cases {
+				update(&kind.Cases[i], &kind.Cases[i].Docs)
+			}
+		case *Enum:
+			for i := range kind.Cases {
+				update(&kind.Cases[i], &kind.Cases[i].Docs)
+			}
+		}
+	}
+}
+
+// StripDocs removes all documentation from [Resolve] r. See [Resolve.RewriteDocs].
+func (r *Resolve) StripDocs() {
+	r.RewriteDocs(func(Node, string) string { return "" })
+}
@@ -0,0 +1,49 @@
+package wit
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStripDocs(t *testing.T) {
+	err := loadTestdata(func(path string, res *Resolve) error {
+		t.Run(path, func(t *testing.T) {
+			res.StripDocs()
+			data := res.WIT(nil, "")
+			if strings.Contains(data, "///") {
+				t.Errorf("WIT(): found documentation after StripDocs:\n%s", data)
+			}
+		})
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRewriteDocs(t *testing.T) {
+	res, err := LoadJSON(testdataPath + "/wit-parser/comments.wit.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := make(map[Node]int)
+	res.RewriteDocs(func(node Node, docs string) string {
+		seen[node]++
+		if docs == "" {
+			t.Errorf("RewriteDocs: called with empty docs for %v", node)
+		}
+		return "rewritten"
+	})
+	if len(seen) == 0 {
+		t.Fatal("RewriteDocs: rewrite not called")
+	}
+	for node, n := range seen {
+		if n != 1 {
+			t.Errorf("RewriteDocs: rewrite called %d times for %v, expected 1", n, node)
+		}
+	}
+	data := res.WIT(nil, "")
+	if !strings.Contains(data, "/// rewritten") {
+		t.Errorf("WIT(): expected rewritten documentation:\n%s", data)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"slices"
 	"strings"
 	"sync"
 	"testing"
@@ -541,3 +542,139 @@ func TestHandlesAreResources(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+// TestValidate verifies that all Resolve values in the test data are valid.
+func TestValidate(t *testing.T) {
+	err := loadTestdata(func(path string, res *Resolve) error {
+		t.Run(path, func(t *testing.T) {
+			err := res.Validate()
+			if err != nil {
+				t.Errorf("(*Resolve).Validate(): %v", err)
+			}
+		})
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+// TestWorldAllFunctionsDeep validates that [World.AllFunctionsDeep] yields each function
+// in a world and its interfaces exactly once.
+func TestWorldAllFunctionsDeep(t *testing.T) {
+	err := loadTestdata(func(path string, res *Resolve) error {
+		t.Run(path, func(t *testing.T) {
+			for _, w := range res.Worlds {
+				seen := make(map[*Function]int)
+				w.AllFunctionsDeep()(func(f *Function) bool {
+					seen[f]++
+					return true
+				})
+				for f, n := range seen {
+					if n != 1 {
+						t.Errorf("world %s: function %s yielded %d times, expected 1", w.Name, f.Name, n)
+					}
+				}
+				check := func(f *Function) bool {
+					if seen[f] == 0 {
+						t.Errorf("world %s: function %s not yielded", w.Name, f.Name)
+					}
+					return true
+				}
+				w.AllFunctions()(check)
+				w.AllInterfaces()(func(_ string, i *Interface) bool {
+					i.AllFunctions()(check)
+					return true
+				})
+				if len(seen) > 1 {
+					var n int
+					w.AllFunctionsDeep()(func(f *Function) bool {
+						n++
+						return false
+					})
+					if got, want := n, 1; got != want {
+						t.Errorf("world %s: yielded %d functions after stop, expected %d", w.Name, got, want)
+					}
+				}
+			}
+		})
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+// TestDependencyGraph validates that the [DependencyGraph] of each world in the test data
+// contains its interfaces and function types, and orders nodes after their references.
+func TestDependencyGraph(t *testing.T) {
+	err := loadTestdata(func(path string, res *Resolve) error {
+		t.Run(path, func(t *testing.T) {
+			for _, w := range res.Worlds {
+				g := DependencyGraph(w)
+				if got, want := g.Nodes[len(g.Nodes)-1], Node(w); got != want {
+					t.Errorf("world %s: last node is %T, expected world", w.Name, got)
+				}
+				w.AllInterfaces()(func(name string, i *Interface) bool {
+					if !g.Contains(i) {
+						t.Errorf("world %s: graph does not contain interface %s", w.Name, name)
+					}
+					return true
+				})
+				w.AllFunctionsDeep()(func(f *Function) bool {
+					for _, p := range append(f.Params, f.Results...) {
+						if td, ok := p.Type.(*TypeDef); ok && !g.Contains(td) {
+							t.Errorf("world %s: graph does not contain type of %s in function %s", w.Name, p.Name, f.Name)
+						}
+					}
+					return true
+				})
+				index := make(map[Node]int)
+				for i, node := range g.Nodes {
+					index[node] = i
+				}
+				if got, want := len(index), len(g.Edges); got != want {
+					t.Errorf("world %s: %d nodes, %d edges", w.Name, got, want)
+				}
+				for node, refs := range g.Edges {
+					for _, ref := range refs {
+						if index[ref] >= index[node] {
+							t.Errorf("world %s: node %d (%T) precedes its reference %d (%T)", w.Name, index[node], node, index[ref], ref)
+						}
+					}
+				}
+			}
+		})
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+// TestNormalizeTestdata verifies that [Resolve.Normalize] preserves the WIT representation
+// of each Resolve in the test data, produces a valid Resolve, and is idempotent.
+func TestNormalizeTestdata(t *testing.T) {
+	err := loadTestdata(func(path string, res *Resolve) error {
+		t.Run(path, func(t *testing.T) {
+			want := res.WIT(nil, "")
+			res.Normalize()
+			if got := res.WIT(nil, ""); got != want {
+				t.Errorf("(*Resolve).Normalize() changed WIT output")
+			}
+			err := res.Validate()
+			if err != nil {
+				t.Errorf("(*Resolve).Validate(): %v", err)
+			}
+			types := slices.Clone(res.TypeDefs)
+			res.Normalize()
+			if !slices.Equal(res.TypeDefs, types) {
+				t.Errorf("(*Resolve).Normalize() is not idempotent")
+			}
+		})
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
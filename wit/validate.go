@@ -0,0 +1,263 @@
+package wit
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ValidationError represents a violation of a [Resolve] invariant found by [Resolve.Validate].
+type ValidationError struct {
+	// Path identifies the offending WIT item, e.g. "wasi:io/streams@0.2.0#input-stream".
+	// Items without a WIT name are identified by their position in the [Resolve],
+	// e.g. "TypeDefs[12]".
+	Path string
+
+	// Message describes the violation.
+	Message string
+
+	// Pos is the position of the offending WIT item in WIT source, if known.
+	Pos Position
+}
+
+// Error implements the [error] interface.
+func (e *ValidationError) Error() string {
+	if e.Pos.IsValid() {
+		return e.Pos.String() + ": " + e.Path + ": " + e.Message
+	}
+	return e.Path + ": " + e.Message
+}
+
+// Validate checks the structural invariants of [Resolve] r that are relied upon by
+// this package and by code generators, such as non-nil Package fields, handles
+// that point to resources, constructors that return own<T>, functions that do not
+// return borrowed handles, and TypeDefs that are listed once and sorted topologically.
+//
+// Validate returns nil if r is valid. Otherwise it returns an error that wraps
+// one or more *[ValidationError] values, which can be retrieved with [errors.As]
+// or by unwrapping the error with an Unwrap() []error method.
+func (r *Resolve) Validate() error {
+	v := validator{names: make(map[Node]string)}
+	v.validate(r)
+	return errors.Join(v.errs...)
+}
+
+type validator struct {
+	names map[Node]string
+	errs  []error
+}
+
+func (v *validator) errorf(path, format string, args ...any) {
+	v.errorfAt(Position{}, path, format, args...)
+}
+
+func (v *validator) errorfAt(pos Position, path, format string, args ...any) {
+	v.errs = append(v.errs, &ValidationError{Path: path, Message: fmt.Sprintf(format, args...), Pos: pos})
+}
+
+func (v *validator) validate(r *Resolve) {
+	for i, pkg := range r.Packages {
+		if pkg == nil {
+			v.errorf(fmt.Sprintf("Packages[%d]", i), "nil Package")
+		}
+	}
+
+	for i, w := range r.Worlds {
+		if w == nil {
+			v.errorf(fmt.Sprintf("Worlds[%d]", i), "nil World")
+			continue
+		}
+		v.names[w] = worldPath(w)
+		if w.Package == nil {
+			v.errorfAt(w.Pos, v.names[w], "World has nil Package")
+		}
+		w.AllInterfaces()(func(name string, i *Interface) bool {
+			if i != nil && i.Name == nil {
+				v.names[i] = v.names[w] + "#" + name
+			}
+			return true
+		})
+		w.Exports.All()(func(name string, item WorldItem) bool {
+			if t, ok := item.(*TypeDef); ok {
+				v.errorfAt(t.Pos, v.names[w], "TypeDef %q in World exports", name)
+			}
+			return true
+		})
+	}
+
+	for i, face := range r.Interfaces {
+		path := fmt.Sprintf("Interfaces[%d]", i)
+		if face == nil {
+			v.errorf(path, "nil Interface")
+			continue
+		}
+		if face.Name != nil {
+			path = interfacePath(face)
+		} else if name, ok := v.names[face]; ok {
+			path = name
+		}
+		v.names[face] = path
+		if face.Package == nil {
+			v.errorfAt(face.Pos, path, "Interface has nil Package")
+		}
+	}
+
+	for i, t := range r.TypeDefs {
+		if t == nil {
+			v.errorf(fmt.Sprintf("TypeDefs[%d]", i), "nil TypeDef")
+			continue
+		}
+		v.validateTypeDef(fmt.Sprintf("TypeDefs[%d]", i), t)
+	}
+	v.validateTypeDefOrder(r.TypeDefs)
+
+	for _, w := range r.Worlds {
+		if w != nil {
+			v.validateFunctions(w, w.AllFunctions())
+		}
+	}
+	for _, face := range r.Interfaces {
+		if face != nil {
+			v.validateFunctions(face, face.AllFunctions())
+		}
+	}
+}
+
+func (v *validator) typeDefPath(path string, t *TypeDef) string {
+	if t.Name != nil {
+		if name, ok := v.names[t.Owner]; ok {
+			return name + "#" + *t.Name
+		}
+		return *t.Name
+	}
+	return path
+}
+
+func (v *validator) validateTypeDef(path string, t *TypeDef) {
+	path = v.typeDefPath(path, t)
+
+	switch kind := t.Kind.(type) {
+	case nil:
+		v.errorfAt(t.Pos, path, "TypeDef has nil Kind")
+		return
+
+	case *Own:
+		v.validateHandle(t.Pos, path, "own", kind.Type)
+
+	case *Borrow:
+		v.validateHandle(t.Pos, path, "borrow", kind.Type)
+
+	default:
+		d := Despecialize(kind)
+		if d == kind {
+			break
+		}
+		if got, want := kind.Size(), d.Size(); got != want {
+			v.errorfAt(t.Pos, path, "size %d does not match despecialized size %d", got, want)
+		}
+		if got, want := kind.Align(), d.Align(); got != want {
+			v.errorfAt(t.Pos, path, "alignment %d does not match despecialized alignment %d", got, want)
+		}
+	}
+}
+
+// validateTypeDefOrder checks that each TypeDef is listed once, and follows the
+// TypeDefs it references, which must also be listed. See [Resolve.Normalize].
+func (v *validator) validateTypeDefOrder(typeDefs []*TypeDef) {
+	listed := make(map[*TypeDef]bool, len(typeDefs))
+	for _, t := range typeDefs {
+		listed[t] = true
+	}
+	pos := make(map[*TypeDef]int, len(typeDefs))
+	for i, t := range typeDefs {
+		if t == nil {
+			continue
+		}
+		path := v.typeDefPath(fmt.Sprintf("TypeDefs[%d]", i), t)
+		if j, ok := pos[t]; ok {
+			v.errorfAt(t.Pos, path, "TypeDef is listed more than once, first at TypeDefs[%d]", j)
+			continue
+		}
+		mapTypes(t, func(u Type) Type {
+			td, ok := u.(*TypeDef)
+			if !ok || td == nil {
+				return u
+			}
+			if _, ok := pos[td]; ok {
+				return u
+			}
+			if listed[td] {
+				v.errorfAt(t.Pos, path, "TypeDef references %s, which is not sorted before it", td.WIT(nil, ""))
+			} else {
+				v.errorfAt(t.Pos, path, "TypeDef references %s, which is not in Resolve", td.WIT(nil, ""))
+			}
+			return u
+		})
+		pos[t] = i
+	}
+}
+
+func (v *validator) validateHandle(pos Position, path, handle string, t *TypeDef) {
+	if t == nil {
+		v.errorfAt(pos, path, "%s handle has nil type", handle)
+		return
+	}
+	if _, ok := t.Root().Kind.(*Resource); !ok {
+		v.errorfAt(pos, path, "%s handle to non-resource type %s", handle, t.Root().WIT(nil, ""))
+	}
+}
+
+func (v *validator) validateFunctions(owner TypeOwner, seq func(yield func(*Function) bool)) {
+	seq(func(f *Function) bool {
+		path := v.names[owner] + "#" + f.Name
+		if f.ReturnsBorrow() {
+			v.errorfAt(f.Pos, path, "function returns a borrowed handle")
+		}
+		switch kind := f.Kind.(type) {
+		case nil:
+			v.errorfAt(f.Pos, path, "Function has nil Kind")
+
+		case *Constructor:
+			if kind.Type == nil {
+				v.errorfAt(f.Pos, path, "constructor has nil type")
+				break
+			}
+			if len(f.Results) != 1 {
+				v.errorfAt(f.Pos, path, "constructor has %d results, expected 1", len(f.Results))
+				break
+			}
+			own := KindOf[*Own](f.Results[0].Type)
+			if own == nil || own.Type != kind.Type {
+				v.errorfAt(f.Pos, path, "constructor result is not own<%s>", kind.Type.TypeName())
+			}
+
+		case *Method:
+			if !f.IsMethod() {
+				v.errorfAt(f.Pos, path, "method does not have a self parameter of its type")
+			}
+
+		case *Static:
+			if kind.Type == nil {
+				v.errorfAt(f.Pos, path, "static function has nil type")
+			}
+		}
+		return true
+	})
+}
+
+func worldPath(w *World) string {
+	if w.Package == nil {
+		return w.Name
+	}
+	id := w.Package.Name
+	id.Extension = w.Name
+	return id.String()
+}
+
+func interfacePath(i *Interface) string {
+	if i.Package == nil {
+		return *i.Name
+	}
+	id := i.Package.Name
+	id.Extension = *i.Name
+	return id.String()
+}
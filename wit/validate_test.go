@@ -0,0 +1,178 @@
+package wit
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateErrors(t *testing.T) {
+	pkg := &Package{Name: Ident{Namespace: "foo", Package: "bar"}}
+	face := &Interface{Name: ptr("i"), Package: pkg}
+	res := &TypeDef{Name: ptr("r"), Kind: &Resource{}, Owner: face}
+	rec := &TypeDef{Name: ptr("rec"), Kind: &Record{}, Owner: face}
+
+	tests := []struct {
+		name    string
+		res     func() *Resolve
+		path    string
+		message string
+	}{
+		{
+			"valid",
+			func() *Resolve {
+				return &Resolve{Packages: []*Package{pkg}, Interfaces: []*Interface{face}, TypeDefs: []*TypeDef{res, rec}}
+			},
+			"",
+			"",
+		},
+		{
+			"nil package",
+			func() *Resolve {
+				return &Resolve{Packages: []*Package{nil}}
+			},
+			"Packages[0]",
+			"nil Package",
+		},
+		{
+			"world without package",
+			func() *Resolve {
+				return &Resolve{Worlds: []*World{{Name: "w"}}}
+			},
+			"w",
+			"World has nil Package",
+		},
+		{
+			"interface without package",
+			func() *Resolve {
+				return &Resolve{Interfaces: []*Interface{{Name: ptr("i")}}}
+			},
+			"i",
+			"Interface has nil Package",
+		},
+		{
+			"handle to non-resource",
+			func() *Resolve {
+				own := &TypeDef{Name: ptr("o"), Kind: &Own{Type: rec}, Owner: face}
+				return &Resolve{Interfaces: []*Interface{face}, TypeDefs: []*TypeDef{rec, own}}
+			},
+			"foo:bar/i#o",
+			"own handle to non-resource type rec",
+		},
+		{
+			"constructor returns resource",
+			func() *Resolve {
+				face := &Interface{Name: ptr("j"), Package: pkg}
+				face.Functions.Set("[constructor]r", &Function{
+					Name:    "[constructor]r",
+					Kind:    &Constructor{Type: res},
+					Results: []Param{{Type: rec}},
+				})
+				return &Resolve{Interfaces: []*Interface{face}}
+			},
+			"foo:bar/j#[constructor]r",
+			"constructor result is not own<r>",
+		},
+		{
+			"function returns borrow",
+			func() *Resolve {
+				face := &Interface{Name: ptr("j"), Package: pkg}
+				face.Functions.Set("f", &Function{
+					Name:    "f",
+					Kind:    &Freestanding{},
+					Results: []Param{{Type: &TypeDef{Kind: &Borrow{Type: res}}}},
+				})
+				return &Resolve{Interfaces: []*Interface{face}}
+			},
+			"foo:bar/j#f",
+			"function returns a borrowed handle",
+		},
+		{
+			"method without self",
+			func() *Resolve {
+				face := &Interface{Name: ptr("j"), Package: pkg}
+				face.Functions.Set("[method]r.m", &Function{
+					Name: "[method]r.m",
+					Kind: &Method{Type: res},
+				})
+				return &Resolve{Interfaces: []*Interface{face}}
+			},
+			"foo:bar/j#[method]r.m",
+			"method does not have a self parameter of its type",
+		},
+		{
+			"duplicate type",
+			func() *Resolve {
+				return &Resolve{Interfaces: []*Interface{face}, TypeDefs: []*TypeDef{res, rec, res}}
+			},
+			"foo:bar/i#r",
+			"TypeDef is listed more than once, first at TypeDefs[0]",
+		},
+		{
+			"type not sorted",
+			func() *Resolve {
+				list := &TypeDef{Kind: &List{Type: rec}}
+				return &Resolve{Interfaces: []*Interface{face}, TypeDefs: []*TypeDef{list, rec}}
+			},
+			"TypeDefs[0]",
+			"TypeDef references rec, which is not sorted before it",
+		},
+		{
+			"type not in resolve",
+			func() *Resolve {
+				list := &TypeDef{Kind: &List{Type: rec}}
+				return &Resolve{Interfaces: []*Interface{face}, TypeDefs: []*TypeDef{list}}
+			},
+			"TypeDefs[0]",
+			"TypeDef references rec, which is not in Resolve",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.res().Validate()
+			if tt.path == "" {
+				if err != nil {
+					t.Errorf("Validate(): %v, expected nil", err)
+				}
+				return
+			}
+			var verr *ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("Validate(): %v, expected *ValidationError", err)
+			}
+			if got, want := verr.Path, tt.path; got != want {
+				t.Errorf("Path: %q, expected %q", got, want)
+			}
+			if got, want := verr.Message, tt.message; got != want {
+				t.Errorf("Message: %q, expected %q", got, want)
+			}
+		})
+	}
+}
+
+func TestValidationErrorPosition(t *testing.T) {
+	pkg := &Package{Name: Ident{Namespace: "foo", Package: "bar"}}
+	face := &Interface{Name: ptr("j"), Package: pkg}
+	pos := Position{Filename: "bar.wit", Line: 4, Column: 2}
+	face.Functions.Set("[method]r.m", &Function{
+		Name: "[method]r.m",
+		Kind: &Method{Type: &TypeDef{Name: ptr("r"), Kind: &Resource{}, Owner: face}},
+		Pos:  pos,
+	})
+	err := (&Resolve{Interfaces: []*Interface{face}}).Validate()
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("Validate(): %v, expected *ValidationError", err)
+	}
+	if verr.Pos != pos {
+		t.Errorf("Pos: %v, expected %v", verr.Pos, pos)
+	}
+	want := "bar.wit:4:2: foo:bar/j#[method]r.m: method does not have a self parameter of its type"
+	if got := verr.Error(); got != want {
+		t.Errorf("Error(): %q, expected %q", got, want)
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,402 @@
+package wit
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/bytecodealliance/wasm-tools-go/wit/ordered"
+	"github.com/coreos/go-semver/semver"
+)
+
+// VersionConstraint is a [SemVer] constraint on the version of a WIT [Package], such as "^0.2".
+// It is a list of comparators separated by commas or spaces, all of which must match,
+// e.g. ">=0.2.0, <0.2.2" or ">=0.2.0 <0.2.2".
+// Each comparator is an operator followed by a version, which may omit its minor and patch numbers:
+//
+//   - "^0.2.1" matches versions compatible with 0.2.1: at least 0.2.1, less than 0.3.0.
+//     The left-most non-zero number may not change, so "^1.2" matches 1.x from 1.2.0.
+//   - "~0.2.1" matches patch versions from 0.2.1: at least 0.2.1, less than 0.3.0.
+//   - "=0.2.1" or "0.2.1" matches version 0.2.1 exactly. "0.2" matches any 0.2.x version.
+//   - ">", ">=", "<", and "<=" compare versions, e.g. "<0.3".
+//   - "*" matches any version.
+//
+// As in [Cargo], a pre-release version such as 0.3.0-rc matches only if a comparator
+// names a pre-release of the same major, minor, and patch version.
+//
+// [SemVer]: https://semver.org/
+// [Cargo]: https://doc.rust-lang.org/cargo/reference/specifying-dependencies.html
+type VersionConstraint struct {
+	text        string
+	comparators []comparator
+}
+
+type comparator struct {
+	op      string         // "=", ">", ">=", "<", "<=", "^", "~", or "*"
+	version semver.Version // omitted numbers are zero
+	parts   int            // count of numbers specified in version, 1 to 3
+}
+
+// ParseVersionConstraint parses a [VersionConstraint], such as "^0.2" or ">=0.2.0, <0.3.0".
+func ParseVersionConstraint(s string) (*VersionConstraint, error) {
+	c := &VersionConstraint{text: strings.TrimSpace(s)}
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("invalid version constraint %q: missing version", s)
+	}
+	for i := 0; i < len(fields); i++ {
+		field := fields[i]
+		if strings.Trim(field, "=<>^~") == "" && i+1 < len(fields) {
+			// Operator separated from its version by a space, e.g. ">= 0.2.0"
+			i++
+			field += fields[i]
+		}
+		cmp, err := parseComparator(field)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version constraint %q: %w", s, err)
+		}
+		c.comparators = append(c.comparators, cmp)
+	}
+	return c, nil
+}
+
+func parseComparator(s string) (comparator, error) {
+	var cmp comparator
+	if s == "*" {
+		cmp.op = "*"
+		return cmp, nil
+	}
+	cmp.op = "="
+	for _, op := range []string{">=", "<=", ">", "<", "=", "^", "~"} {
+		if rest, ok := strings.CutPrefix(s, op); ok {
+			cmp.op = op
+			s = rest
+			break
+		}
+	}
+	if s == "" {
+		return cmp, errors.New("missing version")
+	}
+	core := s
+	if i := strings.IndexAny(s, "-+"); i >= 0 {
+		core = s[:i]
+	}
+	cmp.parts = strings.Count(core, ".") + 1
+	if cmp.parts == 3 {
+		v, err := semver.NewVersion(s)
+		if err != nil {
+			return cmp, err
+		}
+		cmp.version = *v
+		return cmp, nil
+	}
+	if cmp.parts > 3 || core != s {
+		return cmp, fmt.Errorf("invalid version %q", s)
+	}
+	var n [3]int64
+	for i, num := range strings.Split(core, ".") {
+		var err error
+		n[i], err = strconv.ParseInt(num, 10, 64)
+		if err != nil || n[i] < 0 || (len(num) > 1 && num[0] == '0') {
+			return cmp, fmt.Errorf("invalid version %q", s)
+		}
+	}
+	cmp.version = semver.Version{Major: n[0], Minor: n[1], Patch: n[2]}
+	return cmp, nil
+}
+
+// String returns the text of c, as it was parsed.
+func (c *VersionConstraint) String() string {
+	return c.text
+}
+
+// Match returns true if version v satisfies c. A nil version never matches.
+func (c *VersionConstraint) Match(v *semver.Version) bool {
+	if v == nil {
+		return false
+	}
+	for _, cmp := range c.comparators {
+		if !cmp.match(*v) {
+			return false
+		}
+	}
+	if v.PreRelease == "" {
+		return true
+	}
+	for _, cmp := range c.comparators {
+		if cmp.version.PreRelease != "" && cmp.version.Major == v.Major &&
+			cmp.version.Minor == v.Minor && cmp.version.Patch == v.Patch {
+			return true
+		}
+	}
+	return false
+}
+
+func (cmp comparator) match(v semver.Version) bool {
+	switch cmp.op {
+	case "*":
+		return true
+	case "=":
+		if cmp.parts == 3 {
+			return v.Equal(cmp.version)
+		}
+		return !v.LessThan(cmp.version) && v.LessThan(cmp.next(cmp.parts))
+	case ">":
+		if cmp.parts < 3 {
+			return !v.LessThan(cmp.next(cmp.parts))
+		}
+		return cmp.version.LessThan(v)
+	case ">=":
+		return !v.LessThan(cmp.version)
+	case "<":
+		return v.LessThan(cmp.version)
+	case "<=":
+		if cmp.parts < 3 {
+			return v.LessThan(cmp.next(cmp.parts))
+		}
+		return !cmp.version.LessThan(v)
+	case "^":
+		n := 1
+		if cmp.version.Major == 0 && cmp.parts > 1 {
+			n = 2
+			if cmp.version.Minor == 0 && cmp.parts > 2 {
+				n = 3
+			}
+		}
+		return !v.LessThan(cmp.version) && v.LessThan(cmp.next(n))
+	case "~":
+		n := 2
+		if cmp.parts == 1 {
+			n = 1
+		}
+		return !v.LessThan(cmp.version) && v.LessThan(cmp.next(n))
+	}
+	return false
+}
+
+// next returns the lowest version greater than every version whose
+// first n numbers are equal to those of cmp.version.
+func (cmp comparator) next(n int) semver.Version {
+	v := cmp.version
+	switch n {
+	case 1:
+		return semver.Version{Major: v.Major + 1}
+	case 2:
+		return semver.Version{Major: v.Major, Minor: v.Minor + 1}
+	default:
+		return semver.Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1}
+	}
+}
+
+// higherVersion returns true if version a is higher than version b,
+// where an unversioned package is lower than any version.
+func higherVersion(a, b *semver.Version) bool {
+	if a == nil {
+		return false
+	}
+	return b == nil || b.LessThan(*a)
+}
+
+// SelectPackage returns the highest version of the [Package] in r that matches spec,
+// a package name with an optional [VersionConstraint], such as "wasi:io@^0.2"
+// or "wasi:io@>=0.2.0, <0.2.2". A spec without a constraint matches any version.
+// It returns an error if spec is invalid or no version of the package matches.
+func (r *Resolve) SelectPackage(spec string) (*Package, error) {
+	id, c, err := parsePackageSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+	var found *Package
+	for _, p := range r.Packages {
+		if p.Name.Namespace != id.Namespace || p.Name.Package != id.Package {
+			continue
+		}
+		if c != nil && !c.Match(p.Name.Version) {
+			continue
+		}
+		if found == nil || higherVersion(p.Name.Version, found.Name.Version) {
+			found = p
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("no version of package %s matches %q", id.String(), spec)
+	}
+	return found, nil
+}
+
+// parsePackageSpec parses a package name with an optional [VersionConstraint],
+// returning a nil constraint if spec has none.
+func parsePackageSpec(spec string) (Ident, *VersionConstraint, error) {
+	name, constraint, hasConstraint := strings.Cut(strings.TrimSpace(spec), "@")
+	id, err := ParseIdent(name)
+	if err == nil {
+		err = id.Validate()
+	}
+	if err == nil && id.Extension != "" {
+		err = fmt.Errorf("unexpected extension %q", id.Extension)
+	}
+	if err != nil {
+		return id, nil, fmt.Errorf("invalid package %q: %w", spec, err)
+	}
+	if !hasConstraint {
+		return id, nil, nil
+	}
+	c, err := ParseVersionConstraint(constraint)
+	return id, c, err
+}
+
+// SelectVersions selects a version of each package in r named by specs, such as "wasi:io@^0.2",
+// and removes other versions of the package from r, so code generated from r does not contain
+// a duplicate of each interface for each version. See [Resolve.SelectPackage].
+//
+// Worlds defined by other versions of a selected package are removed. Each interface defined
+// by another version that is identical to the interface with the same name in the selected version,
+// ignoring documentation and @since, @unstable, and @deprecated attributes, is removed,
+// and every reference to it or its types is replaced with a reference to the selected interface.
+// Other interfaces of other versions are removed unless they are still referenced by a world or
+// interface in r. A package version left without interfaces or worlds is removed.
+//
+// Selection repeats until no more interfaces can be removed, as replacing references may make
+// interfaces identical, e.g. those of wasi:cli@0.2.0 and @0.2.1 once their references to
+// wasi:io@0.2.0 and @0.2.1 are replaced. The types of r are normalized with [Resolve.Normalize].
+func (r *Resolve) SelectVersions(specs ...string) error {
+	var selected []*Package
+	for _, spec := range specs {
+		p, err := r.SelectPackage(spec)
+		if err != nil {
+			return err
+		}
+		selected = append(selected, p)
+	}
+	for {
+		n := len(r.Packages) + len(r.Interfaces) + len(r.Worlds)
+		for _, p := range selected {
+			r.selectVersion(p)
+		}
+		if len(r.Packages)+len(r.Interfaces)+len(r.Worlds) == n {
+			break
+		}
+	}
+	r.Normalize()
+	return nil
+}
+
+// selectVersion removes other versions of [Package] p from r. See [Resolve.SelectVersions].
+func (r *Resolve) selectVersion(p *Package) {
+	others := make(map[*Package]bool)
+	for _, q := range r.Packages {
+		if q != p && q.Name.Namespace == p.Name.Namespace && q.Name.Package == p.Name.Package {
+			others[q] = true
+		}
+	}
+	if len(others) == 0 {
+		return
+	}
+
+	// Map identical interfaces and their types to those of p.
+	h := &hasher{noStability: true}
+	faces := make(map[*Interface]*Interface)
+	types := make(map[*TypeDef]*TypeDef)
+	for q := range others {
+		q.Interfaces.All()(func(name string, i *Interface) bool {
+			j := p.Interfaces.Get(name)
+			if j == nil || h.iface(i, name, q) != h.iface(j, name, p) {
+				return true
+			}
+			faces[i] = j
+			i.TypeDefs.All()(func(name string, t *TypeDef) bool {
+				if u := j.TypeDefs.Get(name); u != nil {
+					types[t] = u
+				}
+				return true
+			})
+			return true
+		})
+	}
+
+	// Replace references.
+	replace := func(t Type) Type {
+		if td, ok := t.(*TypeDef); ok && types[td] != nil {
+			return types[td]
+		}
+		return t
+	}
+	for _, t := range r.TypeDefs {
+		mapTypes(t, replace)
+	}
+	r.allTypes(func(t Type) Type {
+		if td, ok := t.(*TypeDef); ok {
+			mapTypes(td, replace)
+		}
+		return replace(t)
+	})
+	r.Worlds = slices.DeleteFunc(r.Worlds, func(w *World) bool {
+		return others[w.Package]
+	})
+	for _, w := range r.Worlds {
+		for _, items := range []*ordered.Map[string, WorldItem]{&w.Imports, &w.Exports} {
+			var dups []string
+			seen := make(map[*Interface]bool)
+			items.All()(func(name string, item WorldItem) bool {
+				if ref, ok := item.(*InterfaceRef); ok {
+					if j := faces[ref.Interface]; j != nil {
+						ref.Interface = j
+					}
+					if seen[ref.Interface] {
+						dups = append(dups, name)
+					}
+					seen[ref.Interface] = true
+				}
+				return true
+			})
+			for _, name := range dups {
+				items.Delete(name)
+			}
+		}
+	}
+
+	// Remove merged interfaces and the worlds and unreferenced interfaces of other versions.
+	reachable := make(map[Node]bool)
+	visit := func(root Node) {
+		for _, node := range DependencyGraph(root).Nodes {
+			reachable[node] = true
+			if t, ok := node.(*TypeDef); ok && t.Owner != nil {
+				reachable[t.Owner] = true
+			}
+		}
+	}
+	for _, w := range r.Worlds {
+		visit(w)
+	}
+	for _, i := range r.Interfaces {
+		if !others[i.Package] {
+			visit(i)
+		}
+	}
+	removed := func(i *Interface) bool {
+		return others[i.Package] && (faces[i] != nil || !reachable[i])
+	}
+	r.Interfaces = slices.DeleteFunc(r.Interfaces, removed)
+	r.Packages = slices.DeleteFunc(r.Packages, func(q *Package) bool {
+		if !others[q] {
+			return false
+		}
+		var names []string
+		q.Interfaces.All()(func(name string, i *Interface) bool {
+			if removed(i) {
+				names = append(names, name)
+			}
+			return true
+		})
+		for _, name := range names {
+			q.Interfaces.Delete(name)
+		}
+		q.Worlds = ordered.Map[string, *World]{}
+		return q.Interfaces.Len() == 0
+	})
+}
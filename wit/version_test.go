@@ -0,0 +1,215 @@
+package wit
+
+import (
+	"testing"
+
+	"github.com/coreos/go-semver/semver"
+)
+
+func TestVersionConstraint(t *testing.T) {
+	tests := []struct {
+		constraint string
+		match      []string
+		noMatch    []string
+	}{
+		{"*", []string{"0.0.1", "0.2.0", "1.0.0"}, []string{"0.3.0-rc"}},
+		{"0.2.1", []string{"0.2.1"}, []string{"0.2.0", "0.2.2", "0.2.1-rc"}},
+		{"=0.2", []string{"0.2.0", "0.2.9"}, []string{"0.1.9", "0.3.0"}},
+		{"^0.2", []string{"0.2.0", "0.2.1"}, []string{"0.1.0", "0.3.0", "1.2.0"}},
+		{"^0.2.1", []string{"0.2.1", "0.2.9"}, []string{"0.2.0", "0.3.0"}},
+		{"^0.0.3", []string{"0.0.3"}, []string{"0.0.4", "0.1.0"}},
+		{"^0", []string{"0.0.1", "0.9.9"}, []string{"1.0.0"}},
+		{"^1.2", []string{"1.2.0", "1.9.0"}, []string{"1.1.9", "2.0.0"}},
+		{"~0.2.1", []string{"0.2.1", "0.2.5"}, []string{"0.2.0", "0.3.0"}},
+		{"~1", []string{"1.0.0", "1.9.0"}, []string{"2.0.0"}},
+		{">0.2", []string{"0.3.0", "1.0.0"}, []string{"0.2.9"}},
+		{">0.2.0", []string{"0.2.1"}, []string{"0.2.0"}},
+		{"<=0.2", []string{"0.2.9", "0.1.0"}, []string{"0.3.0"}},
+		{">=0.2.0, <0.2.2", []string{"0.2.0", "0.2.1"}, []string{"0.1.0", "0.2.2"}},
+		{">= 0.2.0 < 0.2.2", []string{"0.2.0", "0.2.1"}, []string{"0.1.0", "0.2.2"}},
+		{"^0.3.0-rc", []string{"0.3.0-rc", "0.3.0", "0.3.1"}, []string{"0.3.1-rc", "0.2.0"}},
+	}
+	for _, tt := range tests {
+		c, err := ParseVersionConstraint(tt.constraint)
+		if err != nil {
+			t.Errorf("ParseVersionConstraint(%q): %v", tt.constraint, err)
+			continue
+		}
+		for _, v := range tt.match {
+			if !c.Match(semver.New(v)) {
+				t.Errorf("%q.Match(%s): false, expected true", tt.constraint, v)
+			}
+		}
+		for _, v := range tt.noMatch {
+			if c.Match(semver.New(v)) {
+				t.Errorf("%q.Match(%s): true, expected false", tt.constraint, v)
+			}
+		}
+		if c.Match(nil) {
+			t.Errorf("%q.Match(nil): true, expected false", tt.constraint)
+		}
+	}
+
+	for _, s := range []string{"", "^", "0.2.x", "01.2", "0.2.0.1", "0.2-rc", ", ", ">=0.2.0 <"} {
+		if _, err := ParseVersionConstraint(s); err == nil {
+			t.Errorf("ParseVersionConstraint(%q): expected error", s)
+		}
+	}
+}
+
+func TestResolveSelectPackage(t *testing.T) {
+	res := loadVersions(t)
+	tests := []struct {
+		spec string
+		want string
+	}{
+		{"wasi:io", "wasi:io@0.2.1"},
+		{"wasi:io@^0.2", "wasi:io@0.2.1"},
+		{"wasi:io@0.2.0", "wasi:io@0.2.0"},
+		{"wasi:io@<0.2.1", "wasi:io@0.2.0"},
+		{"wasi:io@^0.3", ""},
+		{"wasi:nonexistent", ""},
+		{"wasi:io/streams@0.2.0", ""},
+		{"wasi", ""},
+	}
+	for _, tt := range tests {
+		var got string
+		p, err := res.SelectPackage(tt.spec)
+		if p != nil {
+			got = p.Name.String()
+		}
+		if got != tt.want {
+			t.Errorf("SelectPackage(%q): %q, expected %q", tt.spec, got, tt.want)
+		}
+		if (err != nil) != (tt.want == "") {
+			t.Errorf("SelectPackage(%q): error %v", tt.spec, err)
+		}
+	}
+}
+
+func TestResolveSelectVersions(t *testing.T) {
+	orig, err := LoadJSON(testdataPath + "/wasi/cli.wit.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("all", func(t *testing.T) {
+		res := loadVersions(t)
+		var specs []string
+		for _, p := range orig.Packages {
+			specs = append(specs, p.Name.UnversionedString()+"@^0.2")
+		}
+		if err := res.SelectVersions(specs...); err != nil {
+			t.Fatal(err)
+		}
+		if err := res.Validate(); err != nil {
+			t.Fatal(err)
+		}
+		for _, p := range res.Packages {
+			if p.Name.Version.String() != "0.2.1" {
+				t.Errorf("package %s not removed", p.Name.String())
+			}
+		}
+		if len(res.Packages) != len(orig.Packages) || len(res.Interfaces) != len(orig.Interfaces) || len(res.Worlds) != len(orig.Worlds) {
+			t.Errorf("SelectVersions: %d packages, %d interfaces, %d worlds, expected %d, %d, %d",
+				len(res.Packages), len(res.Interfaces), len(res.Worlds),
+				len(orig.Packages), len(orig.Interfaces), len(orig.Worlds))
+		}
+	})
+
+	t.Run("dependency", func(t *testing.T) {
+		res := loadVersions(t)
+		if err := res.SelectVersions("wasi:io@^0.2"); err != nil {
+			t.Fatal(err)
+		}
+		if err := res.Validate(); err != nil {
+			t.Fatal(err)
+		}
+		if p := res.FindPackage("wasi:io@0.2.0"); p != nil {
+			t.Errorf("package %s not removed", p.Name.String())
+		}
+		if res.FindWorld("wasi:io/imports@0.2.0") != nil {
+			t.Error("world wasi:io/imports@0.2.0 not removed")
+		}
+		w := res.FindWorld("wasi:cli/command@0.2.0")
+		if w == nil {
+			t.Fatal("world wasi:cli/command@0.2.0 removed")
+		}
+		if ref, ok := w.FindImport("wasi:io/streams").(*InterfaceRef); !ok || !ref.Interface.Match("wasi:io/streams@0.2.1") {
+			t.Errorf("world %s: import of wasi:io/streams not replaced", w.Name)
+		}
+		assertNoReferences(t, res, "0.2.0", "wasi:io")
+	})
+
+	t.Run("different", func(t *testing.T) {
+		res := loadVersions(t)
+		streams := res.FindInterface("wasi:io/streams@0.2.0")
+		streams.Functions.Delete("[method]output-stream.splice")
+		if err := res.SelectVersions("wasi:io@^0.2"); err != nil {
+			t.Fatal(err)
+		}
+		if err := res.Validate(); err != nil {
+			t.Fatal(err)
+		}
+		p := res.FindPackage("wasi:io@0.2.0")
+		if p == nil {
+			t.Fatal("package wasi:io@0.2.0 removed, expected interface streams to remain")
+		}
+		if got := p.Interfaces.Len(); got != 1 || p.FindInterface("streams") != streams {
+			t.Errorf("package wasi:io@0.2.0 has %d interfaces, expected only streams", got)
+		}
+		if res.FindInterface("wasi:io/error@0.2.0") != nil {
+			t.Error("interface wasi:io/error@0.2.0 not removed")
+		}
+	})
+}
+
+// loadVersions returns a [Resolve] with versions 0.2.0 and 0.2.1 of the WASI packages
+// in testdata/wasi/cli.wit.json, which are otherwise identical.
+func loadVersions(t *testing.T) *Resolve {
+	t.Helper()
+	res, err := LoadJSON(testdataPath + "/wasi/cli.wit.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	next, err := LoadJSON(testdataPath + "/wasi/cli.wit.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range next.Packages {
+		p.Name.Version = semver.New("0.2.1")
+	}
+	res.Packages = append(res.Packages, next.Packages...)
+	res.Interfaces = append(res.Interfaces, next.Interfaces...)
+	res.Worlds = append(res.Worlds, next.Worlds...)
+	res.TypeDefs = append(res.TypeDefs, next.TypeDefs...)
+	return res
+}
+
+// assertNoReferences checks that no world, interface, or type in res
+// references a package named name with the given version.
+func assertNoReferences(t *testing.T, res *Resolve, version, name string) {
+	t.Helper()
+	check := func(node Node) {
+		for _, n := range DependencyGraph(node).Nodes {
+			var p *Package
+			switch n := n.(type) {
+			case *Interface:
+				p = n.Package
+			case *TypeDef:
+				if i, ok := n.Owner.(*Interface); ok {
+					p = i.Package
+				}
+			}
+			if p != nil && p.Name.UnversionedString() == name && p.Name.Version.String() == version {
+				t.Errorf("%T references %s@%s", node, name, version)
+			}
+		}
+	}
+	for _, w := range res.Worlds {
+		check(w)
+	}
+	for _, i := range res.Interfaces {
+		check(i)
+	}
+}
@@ -51,31 +51,11 @@ func unwrap(s string) string {
 func (*Resolve) WITKind() string { return "resolve" }
 
 // WIT returns the [WIT] text format for [Resolve] r.
+// See [Resolve.WITFiles] to write packages in other layouts.
 //
 // [WIT]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/WIT.md
 func (r *Resolve) WIT(ctx Node, _ string) string {
-	packages := slices.Clone(r.Packages)
-	slices.SortFunc(packages, func(a, b *Package) int {
-		return strings.Compare(a.Name.String(), b.Name.String())
-	})
-	var b strings.Builder
-	var hasContent bool
-	for i, p := range packages {
-		var name string
-		if i != 0 {
-			// Write subsequent packages with explicit name, which renders the package WIT with nested braces.
-			name = p.Name.WIT(p, "")
-		}
-		wit := p.WIT(ctx, name)
-		if wit != "" {
-			if hasContent {
-				b.WriteString("\n")
-			}
-			hasContent = true
-			b.WriteString(wit)
-		}
-	}
-	return b.String()
+	return witString(func(b *witWriter) { r.writeWIT(b, ctx, false) })
 }
 
 // WITKind returns the WIT kind.
@@ -177,10 +157,13 @@ func (*World) WITKind() string { return "world" }
 //
 // [WIT]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/WIT.md
 func (w *World) WIT(ctx Node, name string) string {
+	return witString(func(b *witWriter) { w.writeWIT(b, ctx, name) })
+}
+
+func (w *World) writeWIT(b *witWriter, ctx Node, name string) {
 	if name == "" {
 		name = w.Name
 	}
-	var b strings.Builder
 	b.WriteString(w.Docs.WIT(ctx, ""))
 	if w.Stability != nil {
 		b.WriteString(w.Stability.WIT(ctx, ""))
@@ -190,6 +173,14 @@ func (w *World) WIT(ctx Node, name string) string {
 	b.WriteString(escape(name)) // TODO: compare to w.Name?
 	b.WriteString(" {")
 	n := 0
+	for _, inc := range w.Includes {
+		if n == 0 {
+			b.WriteRune('\n')
+		}
+		b.WriteIndented(inc.WIT(w, ""))
+		b.WriteRune('\n')
+		n++
+	}
 	w.Imports.All()(func(name string, i WorldItem) bool {
 		if f, ok := i.(*Function); ok {
 			if !f.IsFreestanding() {
@@ -199,8 +190,9 @@ func (w *World) WIT(ctx Node, name string) string {
 		if n == 0 {
 			b.WriteRune('\n')
 		}
-		// b.WriteString(indent(w.itemWIT("import", name, i)))
-		b.WriteString(indent(i.WIT(worldImport{w}, name)))
+		b.depth++
+		writeWorldItem(b, worldImport{w}, name, i)
+		b.depth--
 		b.WriteRune('\n')
 		n++
 		return true
@@ -209,14 +201,14 @@ func (w *World) WIT(ctx Node, name string) string {
 		if n == 0 {
 			b.WriteRune('\n')
 		}
-		// b.WriteString(indent(w.itemWIT("export", name, i)))
-		b.WriteString(indent(i.WIT(worldExport{w}, name)))
+		b.depth++
+		writeWorldItem(b, worldExport{w}, name, i)
+		b.depth--
 		b.WriteRune('\n')
 		n++
 		return true
 	})
 	b.WriteRune('}')
-	return b.String()
 }
 
 type (
@@ -224,6 +216,19 @@ type (
 	worldExport struct{ *World }
 )
 
+// writeWorldItem writes the WIT text format for [WorldItem] i to b,
+// writing interfaces and type definitions in place.
+func writeWorldItem(b *witWriter, ctx Node, name string, i WorldItem) {
+	switch i := i.(type) {
+	case *InterfaceRef:
+		i.Interface.writeItemWIT(b, ctx, name, i.Stability)
+	case *TypeDef:
+		i.writeWIT(b, ctx, name)
+	default:
+		b.WriteString(i.WIT(ctx, name))
+	}
+}
+
 func (w *World) itemWIT(motion, name string, v WorldItem) string {
 	switch v := v.(type) {
 	case *InterfaceRef, *Function:
@@ -241,10 +246,7 @@ func (*InterfaceRef) WITKind() string { return "interface ref" }
 //
 // [WIT]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/WIT.md
 func (ref *InterfaceRef) WIT(ctx Node, name string) string {
-	if ref.Stability == nil {
-		return ref.Interface.WIT(ctx, name)
-	}
-	return ref.Stability.WIT(ctx, "") + "\n" + ref.Interface.WIT(ctx, name)
+	return witString(func(b *witWriter) { ref.Interface.writeItemWIT(b, ctx, name, ref.Stability) })
 }
 
 // WITKind returns the WIT kind.
@@ -254,12 +256,17 @@ func (*Interface) WITKind() string { return "interface" }
 //
 // [WIT]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/WIT.md
 func (i *Interface) WIT(ctx Node, name string) string {
+	return witString(func(b *witWriter) { i.writeItemWIT(b, ctx, name, nil) })
+}
+
+// writeItemWIT writes the WIT text format for [Interface] i to b.
+// If i is imported or exported by a world, stability is the stability of the world item,
+// which is emitted after any docs.
+func (i *Interface) writeItemWIT(b *witWriter, ctx Node, name string, stability Stability) {
 	if i.Name != nil && name == "" {
 		name = *i.Name
 	}
 
-	var b strings.Builder
-
 	switch ctx := ctx.(type) {
 	case *Package:
 		b.WriteString(i.Docs.WIT(ctx, ""))
@@ -271,27 +278,30 @@ func (i *Interface) WIT(ctx Node, name string) string {
 		b.WriteString(escape(name))
 		b.WriteRune(' ')
 
-	case worldImport:
-		rname := relativeName(i, ctx.Package)
-		if rname != "" {
-			return "import " + escape(rname) + ";"
+	case worldImport, worldExport:
+		var w *World
+		motion := "import "
+		switch ctx := ctx.(type) {
+		case worldImport:
+			w = ctx.World
+		case worldExport:
+			w, motion = ctx.World, "export "
 		}
-
-		// Otherwise, this is an inline interface decl.
-		b.WriteString(i.Docs.WIT(ctx, ""))
-		b.WriteString("import ")
-		b.WriteString(escape(name))
-		b.WriteString(": interface ")
-
-	case worldExport:
-		rname := relativeName(i, ctx.Package)
+		rname := relativeName(i, w.Package)
+		if rname == "" {
+			// Otherwise, this is an inline interface decl.
+			b.WriteString(i.Docs.WIT(ctx, ""))
+		}
+		if stability != nil {
+			b.WriteString(stability.WIT(ctx, ""))
+			b.WriteRune('\n')
+		}
+		b.WriteString(motion)
 		if rname != "" {
-			return "export " + escape(rname) + ";"
+			b.WriteString(escape(rname))
+			b.WriteRune(';')
+			return
 		}
-
-		// Otherwise, this is an inline interface decl.
-		b.WriteString(i.Docs.WIT(ctx, ""))
-		b.WriteString("export ")
 		b.WriteString(escape(name))
 		b.WriteString(": interface ")
 	}
@@ -307,7 +317,9 @@ func (i *Interface) WIT(ctx Node, name string) string {
 		if n == 0 || td.Docs.Contents != "" {
 			b.WriteRune('\n')
 		}
-		b.WriteString(indent(td.WIT(i, name)))
+		b.depth++
+		td.writeWIT(b, i, name)
+		b.depth--
 		b.WriteRune('\n')
 		n++
 		return true
@@ -321,7 +333,9 @@ func (i *Interface) WIT(ctx Node, name string) string {
 		if n == 0 || td.Docs.Contents != "" {
 			b.WriteRune('\n')
 		}
-		b.WriteString(indent(td.WIT(i, name)))
+		b.depth++
+		td.writeWIT(b, i, name)
+		b.depth--
 		b.WriteRune('\n')
 		n++
 		return true
@@ -335,14 +349,13 @@ func (i *Interface) WIT(ctx Node, name string) string {
 		if n == 0 || f.Docs.Contents != "" {
 			b.WriteRune('\n')
 		}
-		b.WriteString(indent(f.WIT(i, name)))
+		b.WriteIndented(f.WIT(i, name))
 		b.WriteRune('\n')
 		n++
 		return true
 	})
 
 	b.WriteRune('}')
-	return b.String()
 }
 
 // WITKind returns the [WIT] kind.
@@ -374,54 +387,69 @@ func (t *TypeDef) WIT(ctx Node, name string) string {
 		return fmt.Sprintf("use %s.{%s};", ownerName, escape(name))
 
 	case worldImport, worldExport, *Interface:
-		var b strings.Builder
-		b.WriteString(t.Docs.WIT(ctx, ""))
-		if t.Stability != nil {
-			b.WriteString(t.Stability.WIT(ctx, ""))
+		return witString(func(b *witWriter) { t.writeWIT(b, ctx, name) })
+	}
+	if name != "" {
+		return escape(name)
+	}
+	return t.Kind.WIT(ctx, name)
+}
+
+// writeWIT writes the WIT text format for [TypeDef] t to b.
+// Declarations in a world or interface are written in place,
+// otherwise the result of [TypeDef.WIT] is written.
+func (t *TypeDef) writeWIT(b *witWriter, ctx Node, name string) {
+	switch ctx.(type) {
+	case worldImport, worldExport, *Interface:
+	default:
+		b.WriteString(t.WIT(ctx, name))
+		return
+	}
+	if t.Name != nil && name == "" {
+		name = *t.Name
+	}
+	b.WriteString(t.Docs.WIT(ctx, ""))
+	if t.Stability != nil {
+		b.WriteString(t.Stability.WIT(ctx, ""))
+		b.WriteRune('\n')
+	}
+	kind := t.Kind.WIT(t, name)
+	b.WriteString(kind)
+	constructor := t.Constructor()
+	methods := t.Methods()
+	statics := t.StaticFunctions()
+	if constructor != nil || len(methods) > 0 || len(statics) > 0 {
+		b.WriteString(" {\n")
+		n := 0
+		if constructor != nil {
+			b.WriteIndented(constructor.WIT(t, "constructor"))
 			b.WriteRune('\n')
+			n++
 		}
-		b.WriteString(t.Kind.WIT(t, name))
-		constructor := t.Constructor()
-		methods := t.Methods()
-		statics := t.StaticFunctions()
-		if constructor != nil || len(methods) > 0 || len(statics) > 0 {
-			b.WriteString(" {\n")
-			n := 0
-			if constructor != nil {
-				b.WriteString(indent(constructor.WIT(t, "constructor")))
+		slices.SortFunc(methods, functionCompare)
+		for _, f := range methods {
+			if f.Docs.Contents != "" {
 				b.WriteRune('\n')
-				n++
 			}
-			slices.SortFunc(methods, functionCompare)
-			for _, f := range methods {
-				if f.Docs.Contents != "" {
-					b.WriteRune('\n')
-				}
-				b.WriteString(indent(f.WIT(t, "")))
-				b.WriteRune('\n')
-				n++
-			}
-			slices.SortFunc(statics, functionCompare)
-			for _, f := range statics {
-				if f.Docs.Contents != "" {
-					b.WriteRune('\n')
-				}
-				b.WriteString(indent(f.WIT(t, "")))
+			b.WriteIndented(f.WIT(t, ""))
+			b.WriteRune('\n')
+			n++
+		}
+		slices.SortFunc(statics, functionCompare)
+		for _, f := range statics {
+			if f.Docs.Contents != "" {
 				b.WriteRune('\n')
-				n++
 			}
-			b.WriteRune('}')
-		}
-		s := b.String()
-		if s[len(s)-1] != '}' && s[len(s)-1] != ';' {
-			b.WriteRune(';')
+			b.WriteIndented(f.WIT(t, ""))
+			b.WriteRune('\n')
+			n++
 		}
-		return b.String()
+		b.WriteRune('}')
+		return
 	}
-	if name != "" {
-		return escape(name)
+	if !strings.HasSuffix(kind, "}") && !strings.HasSuffix(kind, ";") {
+		b.WriteRune(';')
 	}
-	return t.Kind.WIT(ctx, name)
 }
 
 func functionCompare(a, b *Function) int {
@@ -1045,12 +1073,21 @@ func (*Package) WITKind() string { return "package" }
 //
 // [WIT]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/WIT.md
 func (p *Package) WIT(ctx Node, name string) string {
+	// Return empty string in multi-package mode if package has no contents
+	if name != "" && !p.hasContent(ctx) {
+		return ""
+	}
+	return witString(func(b *witWriter) { p.writeWIT(b, ctx, name) })
+}
+
+// writeWIT writes the WIT text format of [Package] p to b.
+// Specify name to write braced, multi-package form.
+func (p *Package) writeWIT(b *witWriter, ctx Node, name string) {
 	var filter *World
 	if w, ok := ctx.(*World); ok {
 		filter = w
 	}
 	multi := name != ""
-	var b strings.Builder
 	b.WriteString(p.Docs.WIT(ctx, ""))
 	b.WriteString("package ")
 	b.WriteString(p.Name.WIT(p, ""))
@@ -1059,19 +1096,16 @@ func (p *Package) WIT(ctx Node, name string) string {
 	} else {
 		b.WriteString(";\n")
 	}
-	i := 0
+	if multi {
+		b.depth++
+	}
 	p.Interfaces.All()(func(name string, face *Interface) bool {
 		if filter != nil && !filter.HasInterface(face) {
 			return true
 		}
 		b.WriteRune('\n')
-		if multi {
-			b.WriteString(indent(face.WIT(p, name)))
-		} else {
-			b.WriteString(face.WIT(p, name))
-		}
+		face.writeItemWIT(b, p, name, nil)
 		b.WriteRune('\n')
-		i++
 		return true
 	})
 	p.Worlds.All()(func(name string, w *World) bool {
@@ -1079,23 +1113,14 @@ func (p *Package) WIT(ctx Node, name string) string {
 			return true
 		}
 		b.WriteRune('\n')
-		if multi {
-			b.WriteString(indent(w.WIT(p, name)))
-		} else {
-			b.WriteString(w.WIT(p, name))
-		}
+		w.writeWIT(b, p, name)
 		b.WriteRune('\n')
-		i++
 		return true
 	})
 	if multi {
+		b.depth--
 		b.WriteString("}\n")
 	}
-	// Return empty string in multi-package mode if package has no contents
-	if multi && i == 0 {
-		return ""
-	}
-	return b.String()
 }
 
 // WITKind returns the WIT kind.
@@ -0,0 +1,133 @@
+package wit
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// WriteWIT writes the [WIT] text format for [Resolve] r to w, laid out according to opts,
+// returning the number of bytes written and the first error encountered, if any.
+// If ctx is a [World], only the world and the interfaces it references are written.
+// It writes the same text as [Resolve.WIT] or, with [LayoutNested], every package in
+// braced form, without building the text in memory. Layouts that write more than one file,
+// such as [LayoutFiles], are not supported; use [Resolve.WITFiles] instead.
+//
+// [WIT]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/WIT.md
+func (r *Resolve) WriteWIT(w io.Writer, ctx Node, opts ...WriteOption) (int64, error) {
+	var o writeOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.layout != LayoutMultiPackage && o.layout != LayoutNested {
+		return 0, fmt.Errorf("wit: cannot write layout %s to a single writer", o.layout)
+	}
+	cw := &countWriter{w: w}
+	bw := bufio.NewWriter(cw)
+	b := newWITWriter(bw)
+	r.writeWIT(b, ctx, o.layout == LayoutNested)
+	if b.err == nil {
+		b.err = bw.Flush()
+	}
+	return cw.n, b.err
+}
+
+// countWriter counts the bytes written to w.
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
+
+// writeWIT writes the WIT text format for r to b, with each package sorted by name.
+// If nested is false, the first package is written in single-package form,
+// otherwise every package is written in braced form.
+func (r *Resolve) writeWIT(b *witWriter, ctx Node, nested bool) {
+	packages := slices.Clone(r.Packages)
+	slices.SortFunc(packages, func(a, b *Package) int {
+		return strings.Compare(a.Name.String(), b.Name.String())
+	})
+	var hasContent bool
+	for i, p := range packages {
+		var name string
+		if i != 0 || nested {
+			// Write subsequent packages with explicit name, which renders the package WIT with nested braces.
+			name = p.Name.WIT(p, "")
+			if !p.hasContent(ctx) {
+				continue
+			}
+		}
+		if hasContent {
+			b.WriteString("\n")
+		}
+		hasContent = true
+		p.writeWIT(b, ctx, name)
+	}
+}
+
+// witWriter writes WIT text to an [io.Writer]. Each non-empty line is indented
+// with one tab per level of depth, so nested declarations are written in place
+// rather than built as strings and indented by their parent.
+type witWriter struct {
+	w     io.Writer
+	depth int
+	bol   bool // at the beginning of a line
+	err   error
+}
+
+func newWITWriter(w io.Writer) *witWriter {
+	return &witWriter{w: w, bol: true}
+}
+
+// witString returns the WIT text written by write.
+func witString(write func(b *witWriter)) string {
+	var sb strings.Builder
+	write(newWITWriter(&sb))
+	return sb.String()
+}
+
+const tabs = "\t\t\t\t\t\t\t\t"
+
+// WriteString writes s to b, indenting each non-empty line that begins in s.
+// Errors are recorded in b, and subsequent writes are ignored.
+func (b *witWriter) WriteString(s string) {
+	for len(s) > 0 && b.err == nil {
+		if b.bol && s[0] != '\n' {
+			for d := b.depth; d > 0 && b.err == nil; d -= len(tabs) {
+				b.write(tabs[:min(d, len(tabs))])
+			}
+			b.bol = false
+		}
+		i := strings.IndexByte(s, '\n')
+		if i < 0 {
+			b.write(s)
+			return
+		}
+		b.write(s[:i+1])
+		b.bol = true
+		s = s[i+1:]
+	}
+}
+
+// WriteRune writes r to b. See [witWriter.WriteString].
+func (b *witWriter) WriteRune(r rune) {
+	b.WriteString(string(r))
+}
+
+// WriteIndented writes s to b one level deeper than the current depth.
+func (b *witWriter) WriteIndented(s string) {
+	b.depth++
+	b.WriteString(s)
+	b.depth--
+}
+
+func (b *witWriter) write(s string) {
+	_, b.err = io.WriteString(b.w, s)
+}
@@ -0,0 +1,112 @@
+package wit
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestResolveWriteWIT(t *testing.T) {
+	err := loadTestdata(func(path string, res *Resolve) error {
+		t.Run(path, func(t *testing.T) {
+			var b strings.Builder
+			n, err := res.WriteWIT(&b, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := res.WIT(nil, "")
+			if got := b.String(); got != want {
+				t.Errorf("WriteWIT did not match WIT:\n%s\nexpected:\n%s", got, want)
+			}
+			if n != int64(b.Len()) {
+				t.Errorf("WriteWIT: wrote %d bytes, returned %d", b.Len(), n)
+			}
+		})
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestResolveWriteWITLayout(t *testing.T) {
+	res, err := LoadJSON(testdataPath + "/wasi/cli.wit.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := res.FindWorld("wasi:io/imports")
+	for _, ctx := range []Node{nil, w} {
+		var b strings.Builder
+		_, err := res.WriteWIT(&b, ctx, WithLayout(LayoutNested))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := res.WITFiles(ctx, WithLayout(LayoutNested))["wasi-cli@0.2.0.wit"]
+		if ctx == w {
+			want = res.WITFiles(ctx, WithLayout(LayoutNested))["wasi-io@0.2.0.wit"]
+		}
+		if got := b.String(); got != want {
+			t.Errorf("WriteWIT(%v, LayoutNested) did not match WITFiles:\n%s\nexpected:\n%s", ctx, got, want)
+		}
+	}
+
+	for _, l := range []Layout{LayoutFiles, LayoutDeps} {
+		_, err := res.WriteWIT(io.Discard, nil, WithLayout(l))
+		if err == nil {
+			t.Errorf("WriteWIT(%s): expected error", l)
+		}
+	}
+}
+
+type errWriter struct{ n int }
+
+func (w *errWriter) Write(p []byte) (int, error) {
+	if w.n < len(p) {
+		n := w.n
+		w.n = 0
+		return n, io.ErrShortWrite
+	}
+	w.n -= len(p)
+	return len(p), nil
+}
+
+func TestResolveWriteWITError(t *testing.T) {
+	res, err := LoadJSON(testdataPath + "/wasi/cli.wit.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := res.WriteWIT(&errWriter{n: 100}, nil)
+	if err != io.ErrShortWrite {
+		t.Errorf("WriteWIT: err == %v, expected %v", err, io.ErrShortWrite)
+	}
+	if n > 100 {
+		t.Errorf("WriteWIT: n == %d, expected <= 100", n)
+	}
+}
+
+func BenchmarkResolveWIT(b *testing.B) {
+	var resolves []*Resolve
+	err := loadTestdata(func(path string, res *Resolve) error {
+		resolves = append(resolves, res)
+		return nil
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Run("WIT", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, res := range resolves {
+				_ = res.WIT(nil, "")
+			}
+		}
+	})
+	b.Run("WriteWIT", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, res := range resolves {
+				_, _ = res.WriteWIT(io.Discard, nil)
+			}
+		}
+	})
+}
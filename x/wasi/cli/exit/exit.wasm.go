@@ -0,0 +1,9 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+
+package exit
+
+// This file contains wasmimport and wasmexport declarations for "wasi:cli@0.2.0".
+
+//go:wasmimport wasi:cli/exit@0.2.0 exit
+//go:noescape
+func wasmimport_Exit(status0 uint32)
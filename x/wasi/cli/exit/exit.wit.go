@@ -0,0 +1,29 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+
+// Package exit represents the imported interface "wasi:cli/exit@0.2.0".
+//
+// See https://github.com/WebAssembly/wasi-cli/blob/v0.2.0/wit/exit.wit for the upstream
+// documentation.
+package exit
+
+import (
+	"github.com/bytecodealliance/wasm-tools-go/cm"
+)
+
+// Exit represents the imported function "exit".
+//
+// Exit the current instance and any linked instances.
+//
+// See https://github.com/WebAssembly/wasi-cli/blob/v0.2.0/wit/exit.wit for the upstream documentation.
+//
+//	exit: func(status: result)
+//
+//go:nosplit
+func Exit(status cm.BoolResult) {
+	status0 := cm.BoolToU32(status)
+	wasmimport_Exit((uint32)(status0))
+	return
+}
+
+// This package requires API level 1 or later of package cm.
+const _ uint = cm.APILevel - 1
@@ -0,0 +1,24 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+
+package run
+
+import (
+	"github.com/bytecodealliance/wasm-tools-go/cm"
+)
+
+// ExportsInstance represents the caller-defined exports from "wasi:cli/run@0.2.0".
+// Tests can construct isolated instances. The exported functions in this package
+// call the default instance, [Exports].
+type ExportsInstance struct {
+	// Run represents the caller-defined, exported function "run".
+	//
+	// Run the program.
+	//
+	// See https://github.com/WebAssembly/wasi-cli/blob/v0.2.0/wit/run.wit for the upstream documentation.
+	//
+	//	run: func() -> result
+	Run func() (result cm.BoolResult)
+}
+
+// Exports is the default [ExportsInstance], called by the exported functions in this package.
+var Exports ExportsInstance
@@ -0,0 +1,20 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+
+package run
+
+import (
+	"github.com/bytecodealliance/wasm-tools-go/cm"
+)
+
+// This file contains wasmimport and wasmexport declarations for "wasi:cli@0.2.0".
+
+//go:wasmexport wasi:cli/run@0.2.0#run
+//export wasi:cli/run@0.2.0#run
+func wasmexport_Run() (result0 uint32) {
+	result := Exports.Run()
+	result0 = cm.BoolToU32(result)
+	return
+}
+
+// This package requires API level 1 or later of package cm.
+const _ uint = cm.APILevel - 1
@@ -0,0 +1,9 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+
+package stderr
+
+// This file contains wasmimport and wasmexport declarations for "wasi:cli@0.2.0".
+
+//go:wasmimport wasi:cli/stderr@0.2.0 get-stderr
+//go:noescape
+func wasmimport_GetStderr() (result0 uint32)
@@ -0,0 +1,33 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+
+// Package stdin represents the imported interface "wasi:cli/stdin@0.2.0".
+//
+// See https://github.com/WebAssembly/wasi-cli/blob/v0.2.0/wit/stdin.wit for the upstream
+// documentation.
+package stdin
+
+import (
+	"github.com/bytecodealliance/wasm-tools-go/cm"
+	"github.com/bytecodealliance/wasm-tools-go/x/wasi/io/streams"
+)
+
+// InputStream represents the imported type alias "wasi:cli/stdin@0.2.0#input-stream".
+//
+// See [github.com/bytecodealliance/wasm-tools-go/x/wasi/io/streams.InputStream] for more information.
+type InputStream = streams.InputStream
+
+// GetStdin represents the imported function "get-stdin".
+//
+// See https://github.com/WebAssembly/wasi-cli/blob/v0.2.0/wit/stdin.wit for the upstream documentation.
+//
+//	get-stdin: func() -> input-stream
+//
+//go:nosplit
+func GetStdin() (result InputStream) {
+	result0 := wasmimport_GetStdin()
+	result = cm.Reinterpret[InputStream]((uint32)(result0))
+	return
+}
+
+// This package requires API level 1 or later of package cm.
+const _ uint = cm.APILevel - 1
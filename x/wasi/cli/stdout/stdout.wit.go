@@ -0,0 +1,33 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+
+// Package stdout represents the imported interface "wasi:cli/stdout@0.2.0".
+//
+// See https://github.com/WebAssembly/wasi-cli/blob/v0.2.0/wit/stdout.wit for the
+// upstream documentation.
+package stdout
+
+import (
+	"github.com/bytecodealliance/wasm-tools-go/cm"
+	"github.com/bytecodealliance/wasm-tools-go/x/wasi/io/streams"
+)
+
+// OutputStream represents the imported type alias "wasi:cli/stdout@0.2.0#output-stream".
+//
+// See [github.com/bytecodealliance/wasm-tools-go/x/wasi/io/streams.OutputStream] for more information.
+type OutputStream = streams.OutputStream
+
+// GetStdout represents the imported function "get-stdout".
+//
+// See https://github.com/WebAssembly/wasi-cli/blob/v0.2.0/wit/stdout.wit for the upstream documentation.
+//
+//	get-stdout: func() -> output-stream
+//
+//go:nosplit
+func GetStdout() (result OutputStream) {
+	result0 := wasmimport_GetStdout()
+	result = cm.Reinterpret[OutputStream]((uint32)(result0))
+	return
+}
+
+// This package requires API level 1 or later of package cm.
+const _ uint = cm.APILevel - 1
@@ -0,0 +1,42 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+
+// Package terminalinput represents the imported interface "wasi:cli/terminal-input@0.2.0".
+//
+// Terminal input.
+//
+// In the future, this may include functions for disabling echoing,
+// disabling input buffering so that keyboard events are sent through
+// immediately, querying supported features, and so on.
+//
+// See https://github.com/WebAssembly/wasi-cli/blob/v0.2.0/wit/terminal-input.wit
+// for the upstream documentation.
+package terminalinput
+
+import (
+	"github.com/bytecodealliance/wasm-tools-go/cm"
+)
+
+// TerminalInput represents the imported resource "wasi:cli/terminal-input@0.2.0#terminal-input".
+//
+// The input side of a terminal.
+//
+// See https://github.com/WebAssembly/wasi-cli/blob/v0.2.0/wit/terminal-input.wit for the upstream documentation.
+//
+//	resource terminal-input
+type TerminalInput cm.Resource
+
+// ResourceDrop represents the imported resource-drop for resource "terminal-input".
+//
+// Drops a resource handle.
+//
+// See https://github.com/WebAssembly/wasi-cli/blob/v0.2.0/wit/terminal-input.wit for the upstream documentation.
+//
+//go:nosplit
+func (self TerminalInput) ResourceDrop() {
+	self0 := cm.Reinterpret[uint32](self)
+	wasmimport_TerminalInputResourceDrop((uint32)(self0))
+	return
+}
+
+// This package requires API level 1 or later of package cm.
+const _ uint = cm.APILevel - 1
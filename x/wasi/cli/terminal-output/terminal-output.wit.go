@@ -0,0 +1,42 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+
+// Package terminaloutput represents the imported interface "wasi:cli/terminal-output@0.2.0".
+//
+// Terminal output.
+//
+// In the future, this may include functions for querying the terminal
+// size, being notified of terminal size changes, querying supported
+// features, and so on.
+//
+// See https://github.com/WebAssembly/wasi-cli/blob/v0.2.0/wit/terminal-output.wit
+// for the upstream documentation.
+package terminaloutput
+
+import (
+	"github.com/bytecodealliance/wasm-tools-go/cm"
+)
+
+// TerminalOutput represents the imported resource "wasi:cli/terminal-output@0.2.0#terminal-output".
+//
+// The output side of a terminal.
+//
+// See https://github.com/WebAssembly/wasi-cli/blob/v0.2.0/wit/terminal-output.wit for the upstream documentation.
+//
+//	resource terminal-output
+type TerminalOutput cm.Resource
+
+// ResourceDrop represents the imported resource-drop for resource "terminal-output".
+//
+// Drops a resource handle.
+//
+// See https://github.com/WebAssembly/wasi-cli/blob/v0.2.0/wit/terminal-output.wit for the upstream documentation.
+//
+//go:nosplit
+func (self TerminalOutput) ResourceDrop() {
+	self0 := cm.Reinterpret[uint32](self)
+	wasmimport_TerminalOutputResourceDrop((uint32)(self0))
+	return
+}
+
+// This package requires API level 1 or later of package cm.
+const _ uint = cm.APILevel - 1
@@ -0,0 +1,38 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+
+// Package terminalstderr represents the imported interface "wasi:cli/terminal-stderr@0.2.0".
+//
+// An interface providing an optional `terminal-output` for stderr as a
+// link-time authority.
+//
+// See https://github.com/WebAssembly/wasi-cli/blob/v0.2.0/wit/terminal-stderr.wit
+// for the upstream documentation.
+package terminalstderr
+
+import (
+	"github.com/bytecodealliance/wasm-tools-go/cm"
+	terminaloutput "github.com/bytecodealliance/wasm-tools-go/x/wasi/cli/terminal-output"
+)
+
+// TerminalOutput represents the imported type alias "wasi:cli/terminal-stderr@0.2.0#terminal-output".
+//
+// See [github.com/bytecodealliance/wasm-tools-go/x/wasi/cli/terminal-output.TerminalOutput] for more information.
+type TerminalOutput = terminaloutput.TerminalOutput
+
+// GetTerminalStderr represents the imported function "get-terminal-stderr".
+//
+// If stderr is connected to a terminal, return a `terminal-output` handle
+// allowing further interaction with it.
+//
+// See https://github.com/WebAssembly/wasi-cli/blob/v0.2.0/wit/terminal-stderr.wit for the upstream documentation.
+//
+//	get-terminal-stderr: func() -> option<terminal-output>
+//
+//go:nosplit
+func GetTerminalStderr() (result cm.Option[TerminalOutput]) {
+	wasmimport_GetTerminalStderr(&result)
+	return
+}
+
+// This package requires API level 1 or later of package cm.
+const _ uint = cm.APILevel - 1
@@ -0,0 +1,38 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+
+// Package terminalstdin represents the imported interface "wasi:cli/terminal-stdin@0.2.0".
+//
+// An interface providing an optional `terminal-input` for stdin as a
+// link-time authority.
+//
+// See https://github.com/WebAssembly/wasi-cli/blob/v0.2.0/wit/terminal-stdin.wit
+// for the upstream documentation.
+package terminalstdin
+
+import (
+	"github.com/bytecodealliance/wasm-tools-go/cm"
+	terminalinput "github.com/bytecodealliance/wasm-tools-go/x/wasi/cli/terminal-input"
+)
+
+// TerminalInput represents the imported type alias "wasi:cli/terminal-stdin@0.2.0#terminal-input".
+//
+// See [github.com/bytecodealliance/wasm-tools-go/x/wasi/cli/terminal-input.TerminalInput] for more information.
+type TerminalInput = terminalinput.TerminalInput
+
+// GetTerminalStdin represents the imported function "get-terminal-stdin".
+//
+// If stdin is connected to a terminal, return a `terminal-input` handle
+// allowing further interaction with it.
+//
+// See https://github.com/WebAssembly/wasi-cli/blob/v0.2.0/wit/terminal-stdin.wit for the upstream documentation.
+//
+//	get-terminal-stdin: func() -> option<terminal-input>
+//
+//go:nosplit
+func GetTerminalStdin() (result cm.Option[TerminalInput]) {
+	wasmimport_GetTerminalStdin(&result)
+	return
+}
+
+// This package requires API level 1 or later of package cm.
+const _ uint = cm.APILevel - 1
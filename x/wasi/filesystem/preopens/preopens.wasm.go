@@ -0,0 +1,16 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+
+package preopens
+
+import (
+	"github.com/bytecodealliance/wasm-tools-go/cm"
+)
+
+// This file contains wasmimport and wasmexport declarations for "wasi:filesystem@0.2.0".
+
+//go:wasmimport wasi:filesystem/preopens@0.2.0 get-directories
+//go:noescape
+func wasmimport_GetDirectories(result *cm.List[cm.Tuple[Descriptor, string]])
+
+// This package requires API level 1 or later of package cm.
+const _ uint = cm.APILevel - 1
@@ -0,0 +1,31 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+
+// Package preopens represents the imported interface "wasi:filesystem/preopens@0.2.0".
+//
+// See https://github.com/WebAssembly/wasi-filesystem/blob/v0.2.0/wit/preopens.wit
+// for the upstream documentation.
+package preopens
+
+import (
+	"github.com/bytecodealliance/wasm-tools-go/cm"
+	"github.com/bytecodealliance/wasm-tools-go/x/wasi/filesystem/types"
+)
+
+// Descriptor represents the imported type alias "wasi:filesystem/preopens@0.2.0#descriptor".
+//
+// See [github.com/bytecodealliance/wasm-tools-go/x/wasi/filesystem/types.Descriptor] for more information.
+type Descriptor = types.Descriptor
+
+// GetDirectories represents the imported function "get-directories".
+//
+// Return the set of preopened directories, and their path.
+//
+// See https://github.com/WebAssembly/wasi-filesystem/blob/v0.2.0/wit/preopens.wit for the upstream documentation.
+//
+//	get-directories: func() -> list<tuple<descriptor, string>>
+//
+//go:nosplit
+func GetDirectories() (result cm.List[cm.Tuple[Descriptor, string]]) {
+	wasmimport_GetDirectories(&result)
+	return
+}
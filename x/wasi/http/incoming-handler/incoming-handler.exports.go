@@ -0,0 +1,29 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+
+package incominghandler
+
+// ExportsInstance represents the caller-defined exports from "wasi:http/incoming-handler@0.2.0".
+// Tests can construct isolated instances. The exported functions in this package
+// call the default instance, [Exports].
+type ExportsInstance struct {
+	// Handle represents the caller-defined, exported function "handle".
+	//
+	// This function is invoked with an incoming HTTP Request, and a resource
+	// `response-outparam` which provides the capability to reply with an HTTP
+	// Response. The response is sent by calling the `response-outparam.set`
+	// method, which allows execution to continue after the response has been
+	// sent. This enables both streaming to the response body, and performing other
+	// work.
+	//
+	// The implementor of this function must write a response to the
+	// `response-outparam` before returning, or else the caller will respond
+	// with an error on its behalf.
+	//
+	// See https://github.com/WebAssembly/wasi-http/blob/v0.2.0/wit/incoming-handler.wit for the upstream documentation.
+	//
+	//	handle: func(request: incoming-request, response-out: response-outparam)
+	Handle func(request IncomingRequest, responseOut ResponseOutparam)
+}
+
+// Exports is the default [ExportsInstance], called by the exported functions in this package.
+var Exports ExportsInstance
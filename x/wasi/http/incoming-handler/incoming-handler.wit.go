@@ -0,0 +1,24 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+
+// Package incominghandler represents the exported interface "wasi:http/incoming-handler@0.2.0".
+//
+// This interface defines a handler of incoming HTTP Requests. It should
+// be exported by components which can respond to HTTP Requests.
+//
+// See https://github.com/WebAssembly/wasi-http/blob/v0.2.0/wit/incoming-handler.wit
+// for the upstream documentation.
+package incominghandler
+
+import (
+	"github.com/bytecodealliance/wasm-tools-go/x/wasi/http/types"
+)
+
+// IncomingRequest represents the exported type alias "wasi:http/incoming-handler@0.2.0#incoming-request".
+//
+// See [github.com/bytecodealliance/wasm-tools-go/x/wasi/http/types.IncomingRequest] for more information.
+type IncomingRequest = types.IncomingRequest
+
+// ResponseOutparam represents the exported type alias "wasi:http/incoming-handler@0.2.0#response-outparam".
+//
+// See [github.com/bytecodealliance/wasm-tools-go/x/wasi/http/types.ResponseOutparam] for more information.
+type ResponseOutparam = types.ResponseOutparam
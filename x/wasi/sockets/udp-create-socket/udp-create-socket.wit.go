@@ -0,0 +1,76 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+
+// Package udpcreatesocket represents the imported interface "wasi:sockets/udp-create-socket@0.2.0".
+//
+// See https://github.com/WebAssembly/wasi-sockets/blob/v0.2.0/wit/udp-create-socket.wit
+// for the upstream documentation.
+package udpcreatesocket
+
+import (
+	"github.com/bytecodealliance/wasm-tools-go/cm"
+	"github.com/bytecodealliance/wasm-tools-go/x/wasi/sockets/network"
+	"github.com/bytecodealliance/wasm-tools-go/x/wasi/sockets/udp"
+)
+
+// Network represents the imported type alias "wasi:sockets/udp-create-socket@0.2.0#network".
+//
+// See [github.com/bytecodealliance/wasm-tools-go/x/wasi/sockets/network.Network] for more information.
+type Network = network.Network
+
+// ErrorCode represents the type alias "wasi:sockets/udp-create-socket@0.2.0#error-code".
+//
+// See [github.com/bytecodealliance/wasm-tools-go/x/wasi/sockets/network.ErrorCode] for more information.
+type ErrorCode = network.ErrorCode
+
+// IPAddressFamily represents the type alias "wasi:sockets/udp-create-socket@0.2.0#ip-address-family".
+//
+// See [github.com/bytecodealliance/wasm-tools-go/x/wasi/sockets/network.IPAddressFamily] for more information.
+type IPAddressFamily = network.IPAddressFamily
+
+// UDPSocket represents the imported type alias "wasi:sockets/udp-create-socket@0.2.0#udp-socket".
+//
+// See [github.com/bytecodealliance/wasm-tools-go/x/wasi/sockets/udp.UDPSocket] for more information.
+type UDPSocket = udp.UDPSocket
+
+// CreateUDPSocket represents the imported function "create-udp-socket".
+//
+// Create a new UDP socket.
+//
+// Similar to `socket(AF_INET or AF_INET6, SOCK_DGRAM, IPPROTO_UDP)` in POSIX.
+// On IPv6 sockets, IPV6_V6ONLY is enabled by default and can't be configured otherwise.
+//
+// This function does not require a network capability handle. This is considered
+// to be safe because
+// at time of creation, the socket is not bound to any `network` yet. Up to the moment
+// `bind` is called,
+// the socket is effectively an in-memory configuration object, unable to communicate
+// with the outside world.
+//
+// All sockets are non-blocking. Use the wasi-poll interface to block on asynchronous
+// operations.
+//
+// # Typical errors
+// - `not-supported`:     The specified `address-family` is not supported. (EAFNOSUPPORT)
+// - `new-socket-limit`:  The new socket resource could not be created because of
+// a system limit. (EMFILE, ENFILE)
+//
+// # References:
+// - <https://pubs.opengroup.org/onlinepubs/9699919799/functions/socket.html>
+// - <https://man7.org/linux/man-pages/man2/socket.2.html>
+// - <https://learn.microsoft.com/en-us/windows/win32/api/winsock2/nf-winsock2-wsasocketw>
+// - <https://man.freebsd.org/cgi/man.cgi?query=socket&sektion=2>
+//
+// See https://github.com/WebAssembly/wasi-sockets/blob/v0.2.0/wit/udp-create-socket.wit for the upstream documentation.
+//
+//	create-udp-socket: func(address-family: ip-address-family) -> result<udp-socket,
+//	error-code>
+//
+//go:nosplit
+func CreateUDPSocket(addressFamily IPAddressFamily) (result cm.Result[UDPSocket, UDPSocket, ErrorCode]) {
+	addressFamily0 := (uint32)(addressFamily)
+	wasmimport_CreateUDPSocket((uint32)(addressFamily0), &result)
+	return
+}
+
+// This package requires API level 1 or later of package cm.
+const _ uint = cm.APILevel - 1
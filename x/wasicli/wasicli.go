@@ -0,0 +1,189 @@
+// Package wasicli provides functions like those of package [os] for command components,
+// built on "wasi:cli/environment", "wasi:cli/exit", and the standard streams of "wasi:cli":
+//
+//	func main() {
+//		if len(wasicli.Args()) < 2 {
+//			fmt.Fprintln(wasicli.Stderr, "usage: greet name")
+//			wasicli.Exit(2)
+//		}
+//		fmt.Fprintf(wasicli.Stdout, "Hello, %s!\n", wasicli.Args()[1])
+//	}
+//
+// The environment and arguments of a component instance do not change,
+// so they are read from the host once, when first used.
+package wasicli
+
+import (
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/bytecodealliance/wasm-tools-go/cm"
+	"github.com/bytecodealliance/wasm-tools-go/x/wasi/cli/environment"
+	"github.com/bytecodealliance/wasm-tools-go/x/wasi/cli/exit"
+	"github.com/bytecodealliance/wasm-tools-go/x/wasi/cli/stderr"
+	"github.com/bytecodealliance/wasm-tools-go/x/wasi/cli/stdin"
+	"github.com/bytecodealliance/wasm-tools-go/x/wasi/cli/stdout"
+	"github.com/bytecodealliance/wasm-tools-go/x/wasi/io/streams"
+)
+
+var env struct {
+	once sync.Once
+	vars [][2]string
+	args []string
+}
+
+func loadEnv() {
+	env.once.Do(func() {
+		env.vars = environment.GetEnvironment().Slice()
+		env.args = environment.GetArguments().Slice()
+	})
+}
+
+// Args returns the command-line arguments, starting with the program name, if any.
+func Args() []string {
+	loadEnv()
+	return append([]string(nil), env.args...)
+}
+
+// Environ returns a copy of the environment variables, in the form "key=value".
+func Environ() []string {
+	loadEnv()
+	return environ(env.vars)
+}
+
+// Getenv returns the value of the environment variable named by key,
+// or the empty string if it is not present.
+func Getenv(key string) string {
+	v, _ := LookupEnv(key)
+	return v
+}
+
+// LookupEnv returns the value of the environment variable named by key,
+// and whether it is present.
+func LookupEnv(key string) (string, bool) {
+	loadEnv()
+	return lookupEnv(env.vars, key)
+}
+
+// InitialCWD returns the initial working directory of the program, if the host provides one.
+func InitialCWD() (string, bool) {
+	cwd := environment.InitialCWD()
+	if cwd.None() {
+		return "", false
+	}
+	return cwd.Value(), true
+}
+
+// environ returns vars in the form "key=value".
+func environ(vars [][2]string) []string {
+	out := make([]string, len(vars))
+	for i, kv := range vars {
+		out[i] = kv[0] + "=" + kv[1]
+	}
+	return out
+}
+
+// lookupEnv returns the value of the first variable in vars named key.
+func lookupEnv(vars [][2]string, key string) (string, bool) {
+	for _, kv := range vars {
+		if kv[0] == key {
+			return kv[1], true
+		}
+	}
+	return "", false
+}
+
+// Exit exits the component instance with code. It does not return.
+// "wasi:cli/exit@0.2.0" reports only success or failure, so every non-zero code is a failure.
+func Exit(code int) {
+	exit.Exit(exitStatus(code))
+	panic("wasicli: exit returned")
+}
+
+// exitStatus returns the wasi:cli/exit status for code: an error for a non-zero code.
+func exitStatus(code int) cm.BoolResult {
+	return cm.BoolResult(code != 0)
+}
+
+// Stdin reads from the stream returned by "wasi:cli/stdin".
+var Stdin io.Reader = &inputStream{get: stdin.GetStdin}
+
+// Stdout writes to the stream returned by "wasi:cli/stdout".
+var Stdout io.Writer = &outputStream{get: stdout.GetStdout}
+
+// Stderr writes to the stream returned by "wasi:cli/stderr".
+var Stderr io.Writer = &outputStream{get: stderr.GetStderr}
+
+// maxWrite is the maximum number of bytes written with a single blocking-write-and-flush.
+const maxWrite = 4096
+
+// outputStream is a blocking [io.Writer] for a standard output stream,
+// which is retrieved from the host when first written. It is safe for concurrent use.
+type outputStream struct {
+	mu     sync.Mutex
+	get    func() streams.OutputStream
+	stream streams.OutputStream
+}
+
+// Write implements [io.Writer], blocking until p is written and flushed.
+func (w *outputStream) Write(p []byte) (n int, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.stream == 0 {
+		w.stream = w.get()
+	}
+	for len(p) > 0 {
+		chunk := p[:min(len(p), maxWrite)]
+		result := w.stream.BlockingWriteAndFlush(cm.ToList(chunk))
+		if e := result.Err(); e != nil {
+			return n, streamError(e)
+		}
+		n += len(chunk)
+		p = p[len(chunk):]
+	}
+	return n, nil
+}
+
+// inputStream is a blocking [io.Reader] for the standard input stream,
+// which is retrieved from the host when first read. It is safe for concurrent use.
+type inputStream struct {
+	mu     sync.Mutex
+	get    func() streams.InputStream
+	stream streams.InputStream
+}
+
+// Read implements [io.Reader], blocking until at least one byte is read.
+// It returns [io.EOF] when the stream is closed.
+func (r *inputStream) Read(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.stream == 0 {
+		r.stream = r.get()
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	for {
+		result := r.stream.BlockingRead(uint64(len(p)))
+		if e := result.Err(); e != nil {
+			if e.Closed() {
+				return 0, io.EOF
+			}
+			return 0, streamError(e)
+		}
+		if data := result.OK().Slice(); len(data) > 0 {
+			return copy(p, data), nil
+		}
+	}
+}
+
+// streamError returns an error for a failed [streams.StreamError] e.
+func streamError(e *streams.StreamError) error {
+	if e.Closed() {
+		return io.ErrClosedPipe
+	}
+	ioErr := e.LastOperationFailed()
+	defer ioErr.ResourceDrop()
+	return errors.New("wasicli: " + ioErr.ToDebugString())
+}
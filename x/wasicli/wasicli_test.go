@@ -0,0 +1,45 @@
+package wasicli
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestEnviron(t *testing.T) {
+	vars := [][2]string{{"HOME", "/home/gopher"}, {"EMPTY", ""}, {"HOME", "/root"}}
+	want := []string{"HOME=/home/gopher", "EMPTY=", "HOME=/root"}
+	if got := environ(vars); !slices.Equal(got, want) {
+		t.Errorf("environ(): %q, expected %q", got, want)
+	}
+	if got := environ(nil); len(got) != 0 {
+		t.Errorf("environ(nil): %q, expected empty", got)
+	}
+}
+
+func TestLookupEnv(t *testing.T) {
+	vars := [][2]string{{"HOME", "/home/gopher"}, {"EMPTY", ""}, {"HOME", "/root"}}
+	tests := []struct {
+		key   string
+		value string
+		ok    bool
+	}{
+		{"HOME", "/home/gopher", true},
+		{"EMPTY", "", true},
+		{"PATH", "", false},
+		{"home", "", false},
+	}
+	for _, tt := range tests {
+		value, ok := lookupEnv(vars, tt.key)
+		if value != tt.value || ok != tt.ok {
+			t.Errorf("lookupEnv(%q): %q, %t, expected %q, %t", tt.key, value, ok, tt.value, tt.ok)
+		}
+	}
+}
+
+func TestExitStatus(t *testing.T) {
+	for code, want := range map[int]bool{0: false, 1: true, 2: true, -1: true} {
+		if got := bool(exitStatus(code)); got != want {
+			t.Errorf("exitStatus(%d): %t, expected %t", code, got, want)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package wasihttp
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bytecodealliance/wasm-tools-go/cm"
+	monotonicclock "github.com/bytecodealliance/wasm-tools-go/x/wasi/clocks/monotonic-clock"
+	outgoinghandler "github.com/bytecodealliance/wasm-tools-go/x/wasi/http/outgoing-handler"
+	"github.com/bytecodealliance/wasm-tools-go/x/wasi/http/types"
+)
+
+// Transport implements [http.RoundTripper] with the imported "wasi:http/outgoing-handler".
+// The host manages connections. A request is canceled when its context is done,
+// while waiting for the response or reading or writing its body.
+type Transport struct {
+	// ConnectTimeout is the timeout for the initial connect to the HTTP server, if non-zero.
+	ConnectTimeout time.Duration
+
+	// FirstByteTimeout is the timeout for receiving the first byte of the response, if non-zero.
+	FirstByteTimeout time.Duration
+
+	// BetweenBytesTimeout is the timeout for receiving subsequent chunks of bytes
+	// in the response body stream, if non-zero.
+	BetweenBytesTimeout time.Duration
+}
+
+// DefaultTransport is the default [Transport], used by [DefaultClient].
+var DefaultTransport = &Transport{}
+
+// DefaultClient is an [http.Client] that sends requests with [DefaultTransport].
+var DefaultClient = &http.Client{Transport: DefaultTransport}
+
+// RoundTrip implements [http.RoundTripper], sending req with "wasi:http/outgoing-handler"
+// and blocking until the response headers are received. The request body, if any,
+// is written before waiting for the response.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	request, body, err := newOutgoingRequest(req)
+	if err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+
+	handle := outgoinghandler.Handle(request, t.requestOptions())
+	if code := handle.Err(); code != nil {
+		if body != 0 {
+			body.ResourceDrop()
+		}
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, &Error{Code: *code}
+	}
+	future := *handle.OK()
+	defer future.ResourceDrop()
+
+	if body != 0 {
+		err = writeBody(req.Context(), body, req.Body, req.Trailer)
+		if err != nil {
+			return nil, err
+		}
+	} else if req.Body != nil {
+		req.Body.Close()
+	}
+
+	if err := wait(req.Context(), future.Subscribe); err != nil {
+		return nil, err
+	}
+	get := future.Get()
+	if get.None() {
+		return nil, errors.New("wasihttp: response not ready")
+	}
+	result := get.Some().OK()
+	if result == nil {
+		return nil, errors.New("wasihttp: response already taken")
+	}
+	if code := result.Err(); code != nil {
+		return nil, &Error{Code: *code}
+	}
+	return newClientResponse(req, *result.OK())
+}
+
+// requestOptions returns the [types.RequestOptions] for the timeouts of t, if any.
+func (t *Transport) requestOptions() cm.Option[types.RequestOptions] {
+	if t.ConnectTimeout == 0 && t.FirstByteTimeout == 0 && t.BetweenBytesTimeout == 0 {
+		return cm.None[types.RequestOptions]()
+	}
+	options := types.NewRequestOptions()
+	for _, o := range []struct {
+		d   time.Duration
+		set func(cm.Option[monotonicclock.Duration]) cm.BoolResult
+	}{
+		{t.ConnectTimeout, options.SetConnectTimeout},
+		{t.FirstByteTimeout, options.SetFirstByteTimeout},
+		{t.BetweenBytesTimeout, options.SetBetweenBytesTimeout},
+	} {
+		if o.d > 0 {
+			// Hosts may not support every timeout, so errors are ignored.
+			o.set(cm.Some(monotonicclock.Duration(o.d.Nanoseconds())))
+		}
+	}
+	return cm.Some(options)
+}
+
+// newOutgoingRequest returns a [types.OutgoingRequest] for req, and its body,
+// or zero if req has no body.
+func newOutgoingRequest(req *http.Request) (types.OutgoingRequest, types.OutgoingBody, error) {
+	if req.URL == nil {
+		return 0, 0, errors.New("wasihttp: nil Request.URL")
+	}
+	headers, err := toFields(req.Header)
+	if err != nil {
+		return 0, 0, err
+	}
+	request := types.NewOutgoingRequest(headers)
+	authority := req.Host
+	if authority == "" {
+		authority = req.URL.Host
+	}
+	for _, set := range []struct {
+		name   string
+		failed cm.BoolResult
+	}{
+		{"method", request.SetMethod(toMethod(req.Method))},
+		{"scheme", request.SetScheme(toScheme(req.URL.Scheme))},
+		{"authority", request.SetAuthority(cm.Some(authority))},
+		{"path", request.SetPathWithQuery(cm.Some(req.URL.RequestURI()))},
+	} {
+		if set.failed {
+			request.ResourceDrop()
+			return 0, 0, fmt.Errorf("wasihttp: invalid request %s", set.name)
+		}
+	}
+
+	if req.Body == nil || req.Body == http.NoBody {
+		return request, 0, nil
+	}
+	body := request.Body()
+	if body.IsErr() {
+		request.ResourceDrop()
+		return 0, 0, errors.New("wasihttp: request body already taken")
+	}
+	return request, *body.OK(), nil
+}
+
+// newClientResponse returns an [http.Response] for req and [types.IncomingResponse] response.
+// Closing the response body drops response.
+func newClientResponse(req *http.Request, response types.IncomingResponse) (*http.Response, error) {
+	header := make(http.Header)
+	fromFields(header, response.Headers())
+	trailer := make(http.Header)
+	body, err := newIncomingBody(req.Context(), response.Consume(), trailer, response.ResourceDrop)
+	if err != nil {
+		response.ResourceDrop()
+		return nil, err
+	}
+	status := int(response.Status())
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          body,
+		ContentLength: contentLength(header),
+		Trailer:       trailer,
+		Request:       req,
+	}, nil
+}
@@ -0,0 +1,222 @@
+package wasihttp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/bytecodealliance/wasm-tools-go/cm"
+	incominghandler "github.com/bytecodealliance/wasm-tools-go/x/wasi/http/incoming-handler"
+	"github.com/bytecodealliance/wasm-tools-go/x/wasi/http/types"
+)
+
+// Handle sets the exported function "handle" of "wasi:http/incoming-handler" to serve
+// incoming HTTP requests with h. Handle is typically called from an init function.
+//
+// The response is sent when h first writes to the body, flushes with [http.Flusher],
+// or returns. Trailers are sent as in package [net/http], by setting headers with the
+// [http.TrailerPrefix] or declaring them in the "Trailer" header before the response is sent.
+func Handle(h http.Handler) {
+	incominghandler.Exports.Handle = func(request types.IncomingRequest, responseOut types.ResponseOutparam) {
+		serve(h, request, responseOut)
+	}
+}
+
+// serve serves [types.IncomingRequest] request with h, and sends its response to out.
+func serve(h http.Handler, request types.IncomingRequest, out types.ResponseOutparam) {
+	r, err := newServerRequest(request)
+	if err != nil {
+		request.ResourceDrop()
+		types.ResponseOutparamSet(out, cm.Err[cm.Result[types.ErrorCodeShape, types.OutgoingResponse, types.ErrorCode]](errorCode(err)))
+		return
+	}
+	defer r.Body.Close()
+	w := &responseWriter{out: out, header: make(http.Header)}
+	h.ServeHTTP(w, r)
+	w.finish()
+}
+
+// newServerRequest returns an [http.Request] for [types.IncomingRequest] request.
+// Closing the request body drops request.
+func newServerRequest(request types.IncomingRequest) (*http.Request, error) {
+	pathWithQuery := request.PathWithQuery().ValueOr("/")
+	u, err := url.ParseRequestURI(pathWithQuery)
+	if err != nil {
+		return nil, err
+	}
+	if scheme := request.Scheme(); !scheme.None() {
+		u.Scheme = fromScheme(scheme.Value())
+	}
+	u.Host = request.Authority().Value()
+
+	header := make(http.Header)
+	fromFields(header, request.Headers())
+	trailer := make(http.Header)
+	body, err := newIncomingBody(context.Background(), request.Consume(), trailer, request.ResourceDrop)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &http.Request{
+		Method:        fromMethod(request.Method()),
+		URL:           u,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          body,
+		ContentLength: contentLength(header),
+		Host:          u.Host,
+		RequestURI:    pathWithQuery,
+		Trailer:       trailer,
+	}
+	return r, nil
+}
+
+// responseWriter implements [http.ResponseWriter] and [http.Flusher] for a [types.ResponseOutparam].
+type responseWriter struct {
+	out    types.ResponseOutparam
+	header http.Header
+	status int
+
+	sent   bool // whether the response was sent to out
+	body   types.OutgoingBody
+	stream *outputStream
+	err    error // sticky error, returned by Write
+}
+
+// Header implements [http.ResponseWriter].
+func (w *responseWriter) Header() http.Header {
+	return w.header
+}
+
+// WriteHeader implements [http.ResponseWriter]. Informational (1xx) status codes are ignored.
+func (w *responseWriter) WriteHeader(code int) {
+	if w.status != 0 || (code >= 100 && code <= 199) {
+		return
+	}
+	w.status = code
+}
+
+// Write implements [http.ResponseWriter], sending the response if not yet sent.
+func (w *responseWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	if !w.sent && w.header.Get("Content-Type") == "" && w.header.Get("Content-Encoding") == "" && len(p) > 0 {
+		w.header.Set("Content-Type", http.DetectContentType(p))
+	}
+	w.send()
+	if w.err != nil {
+		return 0, w.err
+	}
+	if !bodyAllowed(w.status) {
+		return 0, http.ErrBodyNotAllowed
+	}
+	n, err := w.stream.Write(p)
+	if err != nil {
+		w.err = err
+	}
+	return n, err
+}
+
+// Flush implements [http.Flusher], sending the response if not yet sent.
+func (w *responseWriter) Flush() {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	w.send()
+	if w.err == nil {
+		w.err = w.stream.Flush()
+	}
+}
+
+// send sends the response headers and status code, once.
+func (w *responseWriter) send() {
+	if w.sent {
+		return
+	}
+	w.sent = true
+	err := w.sendResponse()
+	if err != nil {
+		w.err = err
+	}
+}
+
+func (w *responseWriter) sendResponse() error {
+	headers, err := toFields(w.header)
+	if err != nil {
+		w.sendError(err)
+		return err
+	}
+	response := types.NewOutgoingResponse(headers)
+	if response.SetStatusCode(types.StatusCode(w.status)) {
+		response.ResourceDrop()
+		err = fmt.Errorf("wasihttp: invalid status code %d", w.status)
+		w.sendError(err)
+		return err
+	}
+	body := response.Body()
+	if body.IsErr() {
+		response.ResourceDrop()
+		err = errors.New("wasihttp: response body already taken")
+		w.sendError(err)
+		return err
+	}
+	w.body = *body.OK()
+	types.ResponseOutparamSet(w.out, cm.OK[cm.Result[types.ErrorCodeShape, types.OutgoingResponse, types.ErrorCode]](response))
+	stream := w.body.Write()
+	if stream.IsErr() {
+		return errors.New("wasihttp: response body stream already taken")
+	}
+	w.stream = &outputStream{ctx: context.Background(), stream: *stream.OK()}
+	return nil
+}
+
+// sendError sends an internal error instead of a response.
+func (w *responseWriter) sendError(err error) {
+	types.ResponseOutparamSet(w.out, cm.Err[cm.Result[types.ErrorCodeShape, types.OutgoingResponse, types.ErrorCode]](errorCode(err)))
+}
+
+// finish sends the response if not yet sent, and finishes its body with trailers, if any.
+func (w *responseWriter) finish() {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	w.send()
+	if w.stream == nil {
+		if w.body != 0 {
+			w.body.ResourceDrop()
+		}
+		return
+	}
+	w.stream.stream.ResourceDrop()
+	finishBody(w.body, w.trailer())
+}
+
+// trailer returns the trailers set with the [http.TrailerPrefix] or declared in the Trailer header.
+func (w *responseWriter) trailer() http.Header {
+	trailer := make(http.Header)
+	for key, values := range w.header {
+		if name, ok := strings.CutPrefix(key, http.TrailerPrefix); ok {
+			trailer[http.CanonicalHeaderKey(name)] = values
+		}
+	}
+	for _, v := range w.header.Values("Trailer") {
+		for _, name := range strings.Split(v, ",") {
+			name = http.CanonicalHeaderKey(strings.TrimSpace(name))
+			if values := w.header.Values(name); name != "" && len(values) > 0 {
+				trailer[name] = values
+			}
+		}
+	}
+	return trailer
+}
+
+// bodyAllowed reports whether a response with status code may have a body.
+func bodyAllowed(status int) bool {
+	return status != http.StatusNoContent && status != http.StatusNotModified
+}
@@ -0,0 +1,235 @@
+package wasihttp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/bytecodealliance/wasm-tools-go/cm"
+	"github.com/bytecodealliance/wasm-tools-go/x/wasi/http/types"
+	"github.com/bytecodealliance/wasm-tools-go/x/wasi/io/streams"
+	"github.com/bytecodealliance/wasm-tools-go/x/wasipoll"
+)
+
+// maxWrite is the size of the buffer used to copy a request body to an output stream.
+const maxWrite = 4096
+
+// streamError returns an error for [streams.StreamError] e, or closed if the stream is closed.
+func streamError(e *streams.StreamError, closed error) error {
+	if e.Closed() {
+		return closed
+	}
+	ioErr := e.LastOperationFailed()
+	defer ioErr.ResourceDrop()
+	if code := types.HTTPErrorCode(*ioErr); !code.None() {
+		return &Error{Code: code.Value()}
+	}
+	return errors.New("wasihttp: " + ioErr.ToDebugString())
+}
+
+// wait blocks until the stream subscribed to by subscribe is ready or ctx is done.
+func wait(ctx context.Context, subscribe func() streams.Pollable) error {
+	pollable := subscribe()
+	defer pollable.ResourceDrop()
+	return wasipoll.Wait(ctx, pollable)
+}
+
+// outputStream implements [io.Writer] for a [streams.OutputStream].
+type outputStream struct {
+	ctx    context.Context
+	stream streams.OutputStream
+}
+
+// Write implements [io.Writer], blocking until p is written and flushed or s.ctx is done.
+func (s *outputStream) Write(p []byte) (n int, err error) {
+	for len(p) > 0 {
+		check := s.stream.CheckWrite()
+		if e := check.Err(); e != nil {
+			return n, streamError(e, io.ErrClosedPipe)
+		}
+		permit := *check.OK()
+		if permit == 0 {
+			if err := wait(s.ctx, s.stream.Subscribe); err != nil {
+				return n, err
+			}
+			continue
+		}
+		chunk := p[:min(uint64(len(p)), permit)]
+		result := s.stream.Write(cm.ToList(chunk))
+		if e := result.Err(); e != nil {
+			return n, streamError(e, io.ErrClosedPipe)
+		}
+		n += len(chunk)
+		p = p[len(chunk):]
+	}
+	return n, s.Flush()
+}
+
+// Flush blocks until previous writes are flushed or s.ctx is done.
+func (s *outputStream) Flush() error {
+	result := s.stream.Flush()
+	if e := result.Err(); e != nil {
+		return streamError(e, io.ErrClosedPipe)
+	}
+	// The stream permits writes when the flush is complete.
+	for {
+		check := s.stream.CheckWrite()
+		if e := check.Err(); e != nil {
+			return streamError(e, io.ErrClosedPipe)
+		}
+		if *check.OK() > 0 {
+			return nil
+		}
+		if err := wait(s.ctx, s.stream.Subscribe); err != nil {
+			return err
+		}
+	}
+}
+
+// incomingBody implements [io.ReadCloser] for a [types.IncomingBody] of a request or response.
+type incomingBody struct {
+	ctx    context.Context // reads are canceled when ctx is done
+	body   types.IncomingBody
+	stream streams.InputStream
+
+	// trailer, if non-nil, receives the trailers of body after it is read to EOF.
+	trailer http.Header
+
+	// parent, if non-nil, drops the request or response of body after body is closed.
+	parent func()
+
+	err    error // sticky error, returned by Read after EOF or an error
+	closed bool
+}
+
+// newIncomingBody returns an [incomingBody] for the body of a request or response
+// returned by consume, or an error if it was already consumed.
+func newIncomingBody(ctx context.Context, consume cm.Result[types.IncomingBody, types.IncomingBody, struct{}], trailer http.Header, parent func()) (*incomingBody, error) {
+	body := consume.OK()
+	if body == nil {
+		return nil, errors.New("wasihttp: body already consumed")
+	}
+	stream := body.Stream()
+	if stream.IsErr() {
+		body.ResourceDrop()
+		return nil, errors.New("wasihttp: body stream already taken")
+	}
+	return &incomingBody{ctx: ctx, body: *body, stream: *stream.OK(), trailer: trailer, parent: parent}, nil
+}
+
+// Read implements [io.Reader], blocking until at least one byte is read or b.ctx is done.
+// It returns [io.EOF] after reading the trailers, if any, at the end of the body.
+func (b *incomingBody) Read(p []byte) (int, error) {
+	if b.err != nil {
+		return 0, b.err
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	for {
+		result := b.stream.Read(uint64(len(p)))
+		if e := result.Err(); e != nil {
+			b.err = streamError(e, io.EOF)
+			if b.err == io.EOF {
+				if err := b.finish(true); err != nil {
+					b.err = err
+				}
+			}
+			return 0, b.err
+		}
+		if data := result.OK().Slice(); len(data) > 0 {
+			return copy(p, data), nil
+		}
+		if err := wait(b.ctx, b.stream.Subscribe); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// Close implements [io.Closer], dropping the body and its stream without reading trailers.
+func (b *incomingBody) Close() error {
+	if b.closed {
+		return nil
+	}
+	if b.err == nil {
+		b.err = http.ErrBodyReadAfterClose
+	}
+	return b.finish(false)
+}
+
+// finish drops the stream and body, reading the trailers into b.trailer if read is true.
+func (b *incomingBody) finish(read bool) error {
+	if b.closed {
+		return nil
+	}
+	b.closed = true
+	b.stream.ResourceDrop()
+	future := types.IncomingBodyFinish(b.body)
+	defer func() {
+		future.ResourceDrop()
+		if b.parent != nil {
+			b.parent()
+		}
+	}()
+	if !read || b.trailer == nil {
+		return nil
+	}
+
+	if err := wait(b.ctx, future.Subscribe); err != nil {
+		return err
+	}
+	get := future.Get()
+	if get.None() {
+		return nil
+	}
+	result := get.Some().OK()
+	if result == nil {
+		return errors.New("wasihttp: trailers already read")
+	}
+	if code := result.Err(); code != nil {
+		return &Error{Code: *code}
+	}
+	if trailers := result.OK(); !trailers.None() {
+		fromFields(b.trailer, trailers.Value())
+	}
+	return nil
+}
+
+// writeBody writes r to [types.OutgoingBody] body and finishes it with the trailers in trailer,
+// if any, or returns ctx.Err() if ctx is done first. It closes r.
+func writeBody(ctx context.Context, body types.OutgoingBody, r io.ReadCloser, trailer http.Header) error {
+	stream := body.Write()
+	if stream.IsErr() {
+		r.Close()
+		body.ResourceDrop()
+		return errors.New("wasihttp: body stream already taken")
+	}
+	w := &outputStream{ctx: ctx, stream: *stream.OK()}
+	_, err := io.CopyBuffer(w, r, make([]byte, maxWrite))
+	w.stream.ResourceDrop()
+	r.Close()
+	if err != nil {
+		body.ResourceDrop()
+		return err
+	}
+	return finishBody(body, trailer)
+}
+
+// finishBody finishes [types.OutgoingBody] body with the trailers in trailer, if any.
+func finishBody(body types.OutgoingBody, trailer http.Header) error {
+	trailers := cm.None[types.Fields]()
+	if len(trailer) > 0 {
+		fields, err := toFields(trailer)
+		if err != nil {
+			body.ResourceDrop()
+			return err
+		}
+		trailers = cm.Some(fields)
+	}
+	result := types.OutgoingBodyFinish(body, trailers)
+	if code := result.Err(); code != nil {
+		return &Error{Code: *code}
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+// Package wasihttp adapts the WASI HTTP interfaces to package [net/http].
+//
+// [Handle] serves incoming HTTP requests exported by a component with "wasi:http/incoming-handler"
+// with an [http.Handler], and [Transport] implements [http.RoundTripper] with the imported
+// "wasi:http/outgoing-handler", so existing Go HTTP servers and clients can run as components:
+//
+//	func init() {
+//		wasihttp.Handle(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+//			fmt.Fprintf(w, "Hello, %s!\n", r.URL.Query().Get("name"))
+//		}))
+//	}
+//
+// Both are built on the Go bindings for "wasi:http/types@0.2.0" in package [types].
+package wasihttp
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/bytecodealliance/wasm-tools-go/cm"
+	"github.com/bytecodealliance/wasm-tools-go/x/wasi/http/types"
+)
+
+// Error is an error reported by the WASI HTTP host, such as a DNS error or a timeout.
+type Error struct {
+	Code types.ErrorCode
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	msg := "wasihttp: " + e.Code.String()
+	if info := e.Code.InternalError(); info != nil && !info.None() {
+		msg += ": " + info.Value()
+	}
+	return msg
+}
+
+// errorCode returns an [types.ErrorCode] describing err,
+// or an internal error if err is not an [*Error].
+func errorCode(err error) types.ErrorCode {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return types.ErrorCodeInternalError(cm.Some(err.Error()))
+}
+
+// toFields returns new [types.Fields] with the entries of h,
+// except for trailers with the [http.TrailerPrefix].
+func toFields(h http.Header) (types.Fields, error) {
+	var entries []cm.Tuple[types.FieldKey, types.FieldValue]
+	for key, values := range h {
+		if strings.HasPrefix(key, http.TrailerPrefix) {
+			continue
+		}
+		for _, v := range values {
+			entries = append(entries, cm.Tuple[types.FieldKey, types.FieldValue]{
+				F0: types.FieldKey(key),
+				F1: types.FieldValue(cm.ToList([]byte(v))),
+			})
+		}
+	}
+	result := types.FieldsFromList(cm.ToList(entries))
+	if err := result.Err(); err != nil {
+		return 0, fmt.Errorf("wasihttp: header error: %s", err)
+	}
+	return *result.OK(), nil
+}
+
+// fromFields adds the entries of [types.Fields] f to h, and drops f.
+func fromFields(h http.Header, f types.Fields) {
+	entries := f.Entries()
+	for _, e := range entries.Slice() {
+		h.Add(string(e.F0), string(cm.List[uint8](e.F1).Slice()))
+	}
+	f.ResourceDrop()
+}
+
+// toMethod returns the [types.Method] for HTTP method s. An empty string is GET.
+func toMethod(s string) types.Method {
+	switch s {
+	case "", http.MethodGet:
+		return types.MethodGet()
+	case http.MethodHead:
+		return types.MethodHead()
+	case http.MethodPost:
+		return types.MethodPost()
+	case http.MethodPut:
+		return types.MethodPut()
+	case http.MethodDelete:
+		return types.MethodDelete()
+	case http.MethodConnect:
+		return types.MethodConnect()
+	case http.MethodOptions:
+		return types.MethodOptions()
+	case http.MethodTrace:
+		return types.MethodTrace()
+	case http.MethodPatch:
+		return types.MethodPatch()
+	}
+	return types.MethodOther(s)
+}
+
+// fromMethod returns the HTTP method for [types.Method] m.
+func fromMethod(m types.Method) string {
+	switch {
+	case m.Get():
+		return http.MethodGet
+	case m.Head():
+		return http.MethodHead
+	case m.Post():
+		return http.MethodPost
+	case m.Put():
+		return http.MethodPut
+	case m.Delete():
+		return http.MethodDelete
+	case m.Connect():
+		return http.MethodConnect
+	case m.Options():
+		return http.MethodOptions
+	case m.Trace():
+		return http.MethodTrace
+	case m.Patch():
+		return http.MethodPatch
+	case m.Other() != nil:
+		return *m.Other()
+	}
+	return ""
+}
+
+// toScheme returns the [types.Scheme] for URL scheme s, or None if s is empty.
+func toScheme(s string) cm.Option[types.Scheme] {
+	switch strings.ToLower(s) {
+	case "":
+		return cm.None[types.Scheme]()
+	case "http":
+		return cm.Some(types.SchemeHTTP())
+	case "https":
+		return cm.Some(types.SchemeHTTPS())
+	}
+	return cm.Some(types.SchemeOther(s))
+}
+
+// fromScheme returns the URL scheme for [types.Scheme] s.
+func fromScheme(s types.Scheme) string {
+	switch {
+	case s.HTTP():
+		return "http"
+	case s.HTTPS():
+		return "https"
+	case s.Other() != nil:
+		return *s.Other()
+	}
+	return ""
+}
+
+// contentLength returns the value of the Content-Length header in h, or -1 if unknown.
+func contentLength(h http.Header) int64 {
+	n, err := strconv.ParseInt(h.Get("Content-Length"), 10, 64)
+	if err != nil || n < 0 {
+		return -1
+	}
+	return n
+}
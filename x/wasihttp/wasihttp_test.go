@@ -0,0 +1,127 @@
+package wasihttp
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/bytecodealliance/wasm-tools-go/cm"
+	"github.com/bytecodealliance/wasm-tools-go/x/wasi/http/types"
+)
+
+func TestMethod(t *testing.T) {
+	methods := []string{
+		http.MethodGet,
+		http.MethodHead,
+		http.MethodPost,
+		http.MethodPut,
+		http.MethodDelete,
+		http.MethodConnect,
+		http.MethodOptions,
+		http.MethodTrace,
+		http.MethodPatch,
+		"PROPFIND",
+	}
+	for _, method := range methods {
+		if got := fromMethod(toMethod(method)); got != method {
+			t.Errorf("fromMethod(toMethod(%q)): %q", method, got)
+		}
+	}
+	if got := fromMethod(toMethod("")); got != http.MethodGet {
+		t.Errorf("fromMethod(toMethod(\"\")): %q, expected %q", got, http.MethodGet)
+	}
+	m := toMethod("PROPFIND")
+	if m.Other() == nil {
+		t.Errorf("toMethod(\"PROPFIND\"): %s, expected other", m)
+	}
+}
+
+func TestScheme(t *testing.T) {
+	tests := []struct {
+		scheme string
+		want   string
+	}{
+		{"http", "http"},
+		{"HTTPS", "https"},
+		{"ws", "ws"},
+	}
+	for _, tt := range tests {
+		s := toScheme(tt.scheme)
+		if s.None() {
+			t.Errorf("toScheme(%q): none", tt.scheme)
+			continue
+		}
+		if got := fromScheme(s.Value()); got != tt.want {
+			t.Errorf("fromScheme(toScheme(%q)): %q, expected %q", tt.scheme, got, tt.want)
+		}
+	}
+	if s := toScheme(""); !s.None() {
+		t.Errorf("toScheme(\"\"): %v, expected none", s.Value())
+	}
+}
+
+func TestContentLength(t *testing.T) {
+	tests := []struct {
+		value string
+		want  int64
+	}{
+		{"", -1},
+		{"0", 0},
+		{"1234", 1234},
+		{"-1", -1},
+		{"abc", -1},
+	}
+	for _, tt := range tests {
+		h := http.Header{"Content-Length": {tt.value}}
+		if got := contentLength(h); got != tt.want {
+			t.Errorf("contentLength(%q): %d, expected %d", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestError(t *testing.T) {
+	tests := []struct {
+		code types.ErrorCode
+		want string
+	}{
+		{types.ErrorCodeDNSTimeout(), "wasihttp: DNS-timeout"},
+		{types.ErrorCodeInternalError(cm.Some("oops")), "wasihttp: internal-error: oops"},
+		{types.ErrorCodeInternalError(cm.None[string]()), "wasihttp: internal-error"},
+	}
+	for _, tt := range tests {
+		err := &Error{Code: tt.code}
+		if got := err.Error(); got != tt.want {
+			t.Errorf("Error(): %q, expected %q", got, tt.want)
+		}
+		if code := errorCode(err); code.Tag() != tt.code.Tag() {
+			t.Errorf("errorCode(%v): %s, expected %s", err, code, tt.code)
+		}
+	}
+	code := errorCode(http.ErrBodyNotAllowed)
+	if info := code.InternalError(); info == nil || info.Value() != http.ErrBodyNotAllowed.Error() {
+		t.Errorf("errorCode(%v): %s, expected internal-error", http.ErrBodyNotAllowed, code)
+	}
+}
+
+func TestResponseWriterTrailer(t *testing.T) {
+	w := &responseWriter{header: make(http.Header)}
+	w.WriteHeader(http.StatusContinue)
+	w.WriteHeader(http.StatusTeapot)
+	w.WriteHeader(http.StatusOK)
+	if w.status != http.StatusTeapot {
+		t.Errorf("status: %d, expected %d", w.status, http.StatusTeapot)
+	}
+
+	w.Header().Set("Trailer", "X-Checksum, X-Missing")
+	w.Header().Set("X-Checksum", "abc")
+	w.Header().Set(http.TrailerPrefix+"X-Elapsed", "10ms")
+	trailer := w.trailer()
+	want := http.Header{"X-Checksum": {"abc"}, "X-Elapsed": {"10ms"}}
+	if len(trailer) != len(want) {
+		t.Errorf("trailer(): %v, expected %v", trailer, want)
+	}
+	for key := range want {
+		if trailer.Get(key) != want.Get(key) {
+			t.Errorf("trailer(): %s: %q, expected %q", key, trailer.Get(key), want.Get(key))
+		}
+	}
+}
@@ -0,0 +1,274 @@
+package wasinet
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"time"
+
+	"github.com/bytecodealliance/wasm-tools-go/cm"
+	"github.com/bytecodealliance/wasm-tools-go/x/wasi/io/poll"
+	"github.com/bytecodealliance/wasm-tools-go/x/wasi/sockets/network"
+	"github.com/bytecodealliance/wasm-tools-go/x/wasi/sockets/tcp"
+	tcpcreatesocket "github.com/bytecodealliance/wasm-tools-go/x/wasi/sockets/tcp-create-socket"
+	"github.com/bytecodealliance/wasm-tools-go/x/wasipoll"
+)
+
+// Dialer contains options for connecting to an address, like [net.Dialer].
+// The zero value connects with the network returned by "wasi:sockets/instance-network".
+type Dialer struct {
+	// Timeout is the maximum amount of time a dial will wait for a connection
+	// to complete, including name resolution, if non-zero.
+	Timeout time.Duration
+
+	// Deadline is the absolute point in time after which dials fail, if non-zero.
+	Deadline time.Time
+
+	// LocalAddr is the local address to use when dialing, if non-nil.
+	// It must be a [*net.TCPAddr].
+	LocalAddr net.Addr
+
+	// KeepAlive is the keep-alive period of the connection, if positive.
+	// If negative, keep-alive messages are disabled.
+	// If zero, the host default is used.
+	KeepAlive time.Duration
+
+	// Network is the wasi:sockets network used to dial.
+	// If zero, the instance network is used.
+	Network network.Network
+
+	// Resolver is used to look up host names, if non-nil.
+	// If nil, host names are looked up on Network.
+	Resolver *Resolver
+}
+
+// Dial connects to address on the named network using the zero [Dialer].
+func Dial(network, address string) (net.Conn, error) {
+	var d Dialer
+	return d.Dial(network, address)
+}
+
+// DialTimeout acts like [Dial] but takes a timeout.
+func DialTimeout(network, address string, timeout time.Duration) (net.Conn, error) {
+	d := Dialer{Timeout: timeout}
+	return d.Dial(network, address)
+}
+
+// Dial connects to address on the named network.
+// See [Dialer.DialContext].
+func (d *Dialer) Dial(network, address string) (net.Conn, error) {
+	return d.DialContext(context.Background(), network, address)
+}
+
+// DialContext connects to address on the named network using the provided context.
+// The network must be "tcp", "tcp4" or "tcp6". The address has the form "host:port",
+// where a host name is resolved and each of its addresses is tried in turn until one succeeds.
+// The connection is a [*TCPConn].
+func (d *Dialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	if d.Timeout != 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.Timeout)
+		defer cancel()
+	}
+	if !d.Deadline.IsZero() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, d.Deadline)
+		defer cancel()
+	}
+
+	var local netip.AddrPort
+	if d.LocalAddr != nil {
+		addr, ok := d.LocalAddr.(*net.TCPAddr)
+		if !ok {
+			return nil, &net.OpError{Op: "dial", Net: network, Source: d.LocalAddr, Err: &net.AddrError{Err: "mismatched local address type", Addr: d.LocalAddr.String()}}
+		}
+		local = addr.AddrPort()
+	}
+
+	n := networkOrInstance(d.Network)
+	r := d.Resolver
+	if r == nil {
+		r = &Resolver{Network: n}
+	}
+	addrs, err := resolve(ctx, r, network, address, false)
+	if err != nil {
+		return nil, &net.OpError{Op: "dial", Net: network, Source: d.LocalAddr, Err: err}
+	}
+	var first error
+	for _, addr := range addrs {
+		c, err := d.dial(ctx, n, local, addr)
+		if err == nil {
+			return c, nil
+		}
+		if first == nil {
+			first = err
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, &net.OpError{Op: "dial", Net: network, Source: d.LocalAddr, Addr: net.TCPAddrFromAddrPort(addrs[0]), Err: first}
+}
+
+// dial connects a new socket to addr on network n, binding it to local if valid.
+func (d *Dialer) dial(ctx context.Context, n network.Network, local, addr netip.AddrPort) (*TCPConn, error) {
+	socket, err := createSocket(addressFamily(addr))
+	if err != nil {
+		return nil, err
+	}
+	if local.IsValid() {
+		if err := bind(ctx, socket, n, local); err != nil {
+			socket.ResourceDrop()
+			return nil, err
+		}
+	}
+	if result := socket.StartConnect(n, toSocketAddress(addr)); result.IsErr() {
+		socket.ResourceDrop()
+		return nil, &Error{Code: *result.Err()}
+	}
+	var result cm.Result[tcp.TupleInputStreamOutputStreamShape, cm.Tuple[tcp.InputStream, tcp.OutputStream], network.ErrorCode]
+	for {
+		result = socket.FinishConnect()
+		code := result.Err()
+		if code == nil {
+			break
+		}
+		var err error = &Error{Code: *code}
+		if *code == network.ErrorCodeWouldBlock {
+			err = wait(ctx, socket.Subscribe)
+		}
+		if err != nil {
+			socket.ResourceDrop()
+			return nil, err
+		}
+	}
+	c := newTCPConn(socket, result.OK().F0, result.OK().F1)
+	if d.KeepAlive != 0 {
+		// Hosts may not support keep-alive, so errors are ignored.
+		c.SetKeepAlive(d.KeepAlive > 0)
+		if d.KeepAlive > 0 {
+			c.SetKeepAlivePeriod(d.KeepAlive)
+		}
+	}
+	return c, nil
+}
+
+// ListenConfig contains options for listening to an address, like [net.ListenConfig].
+// The zero value listens with the network returned by "wasi:sockets/instance-network".
+type ListenConfig struct {
+	// KeepAlive is the keep-alive period of accepted connections, if positive.
+	// If negative, keep-alive messages are disabled.
+	// If zero, the host default is used.
+	KeepAlive time.Duration
+
+	// Backlog is the size of the queue of pending connections, if positive.
+	Backlog int
+
+	// Network is the wasi:sockets network used to listen.
+	// If zero, the instance network is used.
+	Network network.Network
+}
+
+// Listen announces on the local network address using the zero [ListenConfig].
+func Listen(network, address string) (net.Listener, error) {
+	var lc ListenConfig
+	return lc.Listen(context.Background(), network, address)
+}
+
+// Listen announces on the local network address. The network must be "tcp", "tcp4" or "tcp6".
+// An empty host listens on the unspecified IPv4 address, or IPv6 address for "tcp6",
+// and port 0 chooses a free port, reported by the Addr method of the listener.
+// The listener is a [*TCPListener].
+func (lc *ListenConfig) Listen(ctx context.Context, network, address string) (net.Listener, error) {
+	n := networkOrInstance(lc.Network)
+	addrs, err := resolve(ctx, &Resolver{Network: n}, network, address, true)
+	if err != nil {
+		return nil, &net.OpError{Op: "listen", Net: network, Err: err}
+	}
+	addr := addrs[0]
+	l, err := lc.listen(ctx, n, addr)
+	if err != nil {
+		return nil, &net.OpError{Op: "listen", Net: network, Addr: net.TCPAddrFromAddrPort(addr), Err: err}
+	}
+	return l, nil
+}
+
+func (lc *ListenConfig) listen(ctx context.Context, n network.Network, addr netip.AddrPort) (*TCPListener, error) {
+	socket, err := createSocket(addressFamily(addr))
+	if err != nil {
+		return nil, err
+	}
+	err = lc.configure(socket)
+	if err == nil {
+		err = bind(ctx, socket, n, addr)
+	}
+	if err == nil {
+		err = finish(ctx, socket, socket.StartListen(), socket.FinishListen)
+	}
+	if err != nil {
+		socket.ResourceDrop()
+		return nil, err
+	}
+	return newTCPListener(socket, addr), nil
+}
+
+// configure sets the options of lc on socket, which accepted connections inherit.
+// Hosts may not support keep-alive, so its errors are ignored.
+func (lc *ListenConfig) configure(socket tcp.TCPSocket) error {
+	if lc.KeepAlive != 0 {
+		socket.SetKeepAliveEnabled(lc.KeepAlive > 0)
+		if lc.KeepAlive > 0 {
+			socket.SetKeepAliveIdleTime(tcp.Duration(lc.KeepAlive))
+			socket.SetKeepAliveInterval(tcp.Duration(lc.KeepAlive))
+		}
+	}
+	if lc.Backlog > 0 {
+		if result := socket.SetListenBacklogSize(uint64(lc.Backlog)); result.IsErr() {
+			return &Error{Code: *result.Err()}
+		}
+	}
+	return nil
+}
+
+// createSocket returns a new TCP socket for family.
+func createSocket(family network.IPAddressFamily) (tcp.TCPSocket, error) {
+	result := tcpcreatesocket.CreateTCPSocket(family)
+	if code := result.Err(); code != nil {
+		return 0, &Error{Code: *code}
+	}
+	return *result.OK(), nil
+}
+
+// bind binds socket to addr on network n.
+func bind(ctx context.Context, socket tcp.TCPSocket, n network.Network, addr netip.AddrPort) error {
+	return finish(ctx, socket, socket.StartBind(n, toSocketAddress(addr)), socket.FinishBind)
+}
+
+// finish completes a two-phase socket operation, such as bind or listen:
+// if the start result succeeded, it calls finishOp until it no longer returns would-block,
+// waiting for socket to be ready in between, or until ctx is done.
+func finish(ctx context.Context, socket tcp.TCPSocket, start cm.Result[network.ErrorCode, struct{}, network.ErrorCode], finishOp func() cm.Result[network.ErrorCode, struct{}, network.ErrorCode]) error {
+	if code := start.Err(); code != nil {
+		return &Error{Code: *code}
+	}
+	for {
+		result := finishOp()
+		code := result.Err()
+		if code == nil {
+			return nil
+		}
+		if *code != network.ErrorCodeWouldBlock {
+			return &Error{Code: *code}
+		}
+		if err := wait(ctx, socket.Subscribe); err != nil {
+			return err
+		}
+	}
+}
+
+// wait blocks until the pollable returned by subscribe is ready or ctx is done.
+func wait(ctx context.Context, subscribe func() poll.Pollable) error {
+	pollable := subscribe()
+	defer pollable.ResourceDrop()
+	return wasipoll.Wait(ctx, pollable)
+}
@@ -0,0 +1,144 @@
+package wasinet
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/bytecodealliance/wasm-tools-go/x/wasi/io/poll"
+	"github.com/bytecodealliance/wasm-tools-go/x/wasipoll"
+)
+
+// netFD is the state shared by the operations of a connection or listener:
+// its read and write deadlines, and a count of operations in progress.
+// Resources are dropped when the netFD is closed and no operation is in progress,
+// so a pollable is never dropped after the stream or socket it belongs to.
+type netFD struct {
+	mu      sync.Mutex
+	refs    int
+	closed  bool
+	destroy func() // drops the resources of the connection or listener
+
+	rd, wd deadline
+}
+
+// deadline is a read or write deadline. Waits for the deadline use ctx,
+// which is canceled when the deadline is changed or the netFD is closed.
+type deadline struct {
+	t      time.Time
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// acquire starts an operation, returning [net.ErrClosed] if fd is closed.
+func (fd *netFD) acquire() error {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+	if fd.closed {
+		return net.ErrClosed
+	}
+	fd.refs++
+	return nil
+}
+
+// release ends an operation started by acquire.
+func (fd *netFD) release() {
+	fd.mu.Lock()
+	fd.refs--
+	destroy := fd.closed && fd.refs == 0
+	fd.mu.Unlock()
+	if destroy {
+		fd.destroy()
+	}
+}
+
+// close closes fd, interrupting operations in progress.
+// It returns [net.ErrClosed] if fd was already closed.
+func (fd *netFD) close() error {
+	fd.mu.Lock()
+	if fd.closed {
+		fd.mu.Unlock()
+		return net.ErrClosed
+	}
+	fd.closed = true
+	fd.rd.reset(time.Time{})
+	fd.wd.reset(time.Time{})
+	destroy := fd.refs == 0
+	fd.mu.Unlock()
+	if destroy {
+		fd.destroy()
+	}
+	return nil
+}
+
+// setDeadline sets the deadline of d to t. Operations waiting for d continue with the new deadline.
+// A zero t means operations do not time out.
+func (fd *netFD) setDeadline(d *deadline, t time.Time) error {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+	if fd.closed {
+		return net.ErrClosed
+	}
+	d.reset(t)
+	return nil
+}
+
+// reset sets the deadline of d to t, canceling waits for the previous deadline.
+func (d *deadline) reset(t time.Time) {
+	if d.cancel != nil {
+		d.cancel()
+	}
+	d.t, d.ctx, d.cancel = t, nil, nil
+}
+
+// context returns the context for a wait for d, or an error if fd is closed
+// or the deadline has passed.
+func (fd *netFD) context(d *deadline) (context.Context, error) {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+	if fd.closed {
+		return nil, net.ErrClosed
+	}
+	if !d.t.IsZero() && !time.Now().Before(d.t) {
+		return nil, os.ErrDeadlineExceeded
+	}
+	if d.ctx == nil {
+		if d.t.IsZero() {
+			d.ctx, d.cancel = context.WithCancel(context.Background())
+		} else {
+			d.ctx, d.cancel = context.WithDeadline(context.Background(), d.t)
+		}
+	}
+	return d.ctx, nil
+}
+
+// check returns an error if fd is closed or the deadline d has passed.
+func (fd *netFD) check(d *deadline) error {
+	_, err := fd.context(d)
+	return err
+}
+
+// wait blocks until the pollable returned by subscribe is ready, the deadline d passes,
+// or fd is closed. It returns [os.ErrDeadlineExceeded] or [net.ErrClosed], respectively,
+// for the latter.
+func (fd *netFD) wait(d *deadline, subscribe func() poll.Pollable) error {
+	for {
+		ctx, err := fd.context(d)
+		if err != nil {
+			return err
+		}
+		pollable := subscribe()
+		err = wasipoll.Wait(ctx, pollable)
+		pollable.ResourceDrop()
+		if err == nil {
+			return nil
+		}
+		if errors.Is(err, context.DeadlineExceeded) {
+			return os.ErrDeadlineExceeded
+		}
+		// The deadline was changed or fd was closed: check again.
+	}
+}
@@ -0,0 +1,181 @@
+package wasinet
+
+import (
+	"context"
+	"net"
+	"net/netip"
+
+	ipnamelookup "github.com/bytecodealliance/wasm-tools-go/x/wasi/sockets/ip-name-lookup"
+	"github.com/bytecodealliance/wasm-tools-go/x/wasi/sockets/network"
+	"github.com/bytecodealliance/wasm-tools-go/x/wasipoll"
+)
+
+// DefaultResolver is the [Resolver] used by [LookupHost], [LookupIP],
+// and [Dialer] values without a Resolver.
+var DefaultResolver = &Resolver{}
+
+// Resolver looks up host names using "wasi:sockets/ip-name-lookup", like [net.Resolver].
+// The pure Go resolver in package net cannot dial a DNS server from a WASI 0.2 component,
+// so a [Dialer] resolves host names with its Resolver instead.
+// Waiting for addresses is canceled when the context is done, see [wasipoll.Wait].
+// Errors are reported as [*net.DNSError].
+//
+// The zero value is ready to use, and resolves names using the network
+// returned by "wasi:sockets/instance-network".
+type Resolver struct {
+	// Network is the wasi:sockets network used for name resolution.
+	// If zero, the instance network is used.
+	Network network.Network
+}
+
+// LookupHost looks up host using [DefaultResolver], returning a slice of its addresses.
+func LookupHost(host string) ([]string, error) {
+	return DefaultResolver.LookupHost(context.Background(), host)
+}
+
+// LookupIP looks up host using [DefaultResolver], returning a slice of its IPv4 and IPv6 addresses.
+func LookupIP(host string) ([]net.IP, error) {
+	return DefaultResolver.LookupIP(context.Background(), "ip", host)
+}
+
+// LookupHost looks up the given host, returning a slice of its addresses.
+func (r *Resolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	addrs, err := r.lookup(ctx, "ip", host)
+	if err != nil {
+		return nil, err
+	}
+	hosts := make([]string, len(addrs))
+	for i, addr := range addrs {
+		hosts[i] = addr.String()
+	}
+	return hosts, nil
+}
+
+// LookupIPAddr looks up host, returning a slice of its IPv4 and IPv6 addresses.
+func (r *Resolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	addrs, err := r.lookup(ctx, "ip", host)
+	if err != nil {
+		return nil, err
+	}
+	ips := make([]net.IPAddr, len(addrs))
+	for i, addr := range addrs {
+		ips[i] = net.IPAddr{IP: addr.AsSlice()}
+	}
+	return ips, nil
+}
+
+// LookupIP looks up host for the given network, returning a slice of its IP addresses.
+// The network must be one of "ip", "ip4" or "ip6".
+func (r *Resolver) LookupIP(ctx context.Context, network, host string) ([]net.IP, error) {
+	addrs, err := r.LookupNetIP(ctx, network, host)
+	if err != nil {
+		return nil, err
+	}
+	ips := make([]net.IP, len(addrs))
+	for i, addr := range addrs {
+		ips[i] = addr.AsSlice()
+	}
+	return ips, nil
+}
+
+// LookupNetIP looks up host for the given network, returning a slice of its IP addresses.
+// The network must be one of "ip", "ip4" or "ip6".
+func (r *Resolver) LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error) {
+	switch network {
+	case "ip", "ip4", "ip6":
+	default:
+		return nil, net.UnknownNetworkError(network)
+	}
+	return r.lookup(ctx, network, host)
+}
+
+func (r *Resolver) lookup(ctx context.Context, ipFamily, host string) ([]netip.Addr, error) {
+	if host == "" {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+
+	var n network.Network
+	if r != nil {
+		n = r.Network
+	}
+	res := ipnamelookup.ResolveAddresses(networkOrInstance(n), host)
+	if res.IsErr() {
+		return nil, dnsError(*res.Err(), host)
+	}
+	stream := *res.OK()
+	defer stream.ResourceDrop()
+
+	var addrs []netip.Addr
+	for {
+		res := stream.ResolveNextAddress()
+		if err := res.Err(); err != nil {
+			if *err != network.ErrorCodeWouldBlock {
+				return nil, dnsError(*err, host)
+			}
+			p := stream.Subscribe()
+			err := wasipoll.Wait(ctx, p)
+			p.ResourceDrop()
+			if err != nil {
+				return nil, contextError(err, host)
+			}
+			continue
+		}
+		opt := res.OK()
+		if opt.None() {
+			break
+		}
+		addr := netipAddr(opt.Value())
+		if matchFamily(ipFamily, addr) {
+			addrs = append(addrs, addr)
+		}
+	}
+
+	if len(addrs) == 0 {
+		return nil, &net.DNSError{Err: "no suitable address found", Name: host, IsNotFound: true}
+	}
+	return addrs, nil
+}
+
+// netipAddr converts a wasi:sockets ip-address into a [netip.Addr].
+func netipAddr(addr network.IPAddress) netip.Addr {
+	if ip := addr.IPv4(); ip != nil {
+		return netip.AddrFrom4([4]byte(*ip))
+	}
+	var b [16]byte
+	for i, v := range *addr.IPv6() {
+		b[i*2] = byte(v >> 8)
+		b[i*2+1] = byte(v)
+	}
+	return netip.AddrFrom16(b)
+}
+
+// dnsError translates a wasi:sockets error-code into a [net.DNSError].
+func dnsError(code network.ErrorCode, name string) *net.DNSError {
+	err := &net.DNSError{Err: code.String(), Name: name}
+	switch code {
+	case network.ErrorCodeNameUnresolvable:
+		err.Err = "no such host"
+		err.IsNotFound = true
+	case network.ErrorCodeTemporaryResolverFailure:
+		err.Err = "temporary failure in name resolution"
+		err.IsTemporary = true
+	case network.ErrorCodePermanentResolverFailure:
+		err.Err = "permanent failure in name resolution"
+	case network.ErrorCodeInvalidArgument:
+		err.Err = "invalid domain name"
+	case network.ErrorCodeTimeout:
+		err.Err = "i/o timeout"
+		err.IsTimeout = true
+		err.IsTemporary = true
+	}
+	return err
+}
+
+// contextError translates a [context.Context] error into a [net.DNSError].
+func contextError(err error, name string) *net.DNSError {
+	return &net.DNSError{
+		Err:       err.Error(),
+		Name:      name,
+		IsTimeout: err == context.DeadlineExceeded,
+	}
+}
@@ -0,0 +1,98 @@
+package wasinet
+
+import (
+	"context"
+	"net/netip"
+	"testing"
+
+	"github.com/bytecodealliance/wasm-tools-go/x/wasi/sockets/network"
+)
+
+func TestNetIPAddr(t *testing.T) {
+	tests := []struct {
+		addr network.IPAddress
+		want string
+	}{
+		{network.IPAddressIPv4(network.IPv4Address{127, 0, 0, 1}), "127.0.0.1"},
+		{network.IPAddressIPv4(network.IPv4Address{192, 168, 1, 254}), "192.168.1.254"},
+		{network.IPAddressIPv6(network.IPv6Address{0, 0, 0, 0, 0, 0, 0, 1}), "::1"},
+		{network.IPAddressIPv6(network.IPv6Address{0x2001, 0xdb8, 0, 0, 0, 0, 0xcafe, 0xf00d}), "2001:db8::cafe:f00d"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got, want := netipAddr(tt.addr), netip.MustParseAddr(tt.want); got != want {
+				t.Errorf("netipAddr(): %v, expected %v", got, want)
+			}
+		})
+	}
+}
+
+func TestMatchFamily(t *testing.T) {
+	v4 := netip.MustParseAddr("10.0.0.1")
+	v6 := netip.MustParseAddr("fe80::1")
+	tests := []struct {
+		family string
+		addr   netip.Addr
+		want   bool
+	}{
+		{"ip", v4, true},
+		{"ip", v6, true},
+		{"ip4", v4, true},
+		{"ip4", v6, false},
+		{"ip6", v4, false},
+		{"ip6", v6, true},
+	}
+	for _, tt := range tests {
+		if got, want := matchFamily(tt.family, tt.addr), tt.want; got != want {
+			t.Errorf("matchFamily(%q, %v): %t, expected %t", tt.family, tt.addr, got, want)
+		}
+	}
+}
+
+func TestDNSError(t *testing.T) {
+	tests := []struct {
+		code        network.ErrorCode
+		err         string
+		isNotFound  bool
+		isTemporary bool
+		isTimeout   bool
+	}{
+		{network.ErrorCodeNameUnresolvable, "no such host", true, false, false},
+		{network.ErrorCodeTemporaryResolverFailure, "temporary failure in name resolution", false, true, false},
+		{network.ErrorCodePermanentResolverFailure, "permanent failure in name resolution", false, false, false},
+		{network.ErrorCodeInvalidArgument, "invalid domain name", false, false, false},
+		{network.ErrorCodeTimeout, "i/o timeout", false, true, true},
+		{network.ErrorCodeAccessDenied, "access-denied", false, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code.String(), func(t *testing.T) {
+			err := dnsError(tt.code, "example.com")
+			if got, want := err.Err, tt.err; got != want {
+				t.Errorf("Err: %q, expected %q", got, want)
+			}
+			if got, want := err.Name, "example.com"; got != want {
+				t.Errorf("Name: %q, expected %q", got, want)
+			}
+			if got, want := err.IsNotFound, tt.isNotFound; got != want {
+				t.Errorf("IsNotFound: %t, expected %t", got, want)
+			}
+			if got, want := err.IsTemporary, tt.isTemporary; got != want {
+				t.Errorf("IsTemporary: %t, expected %t", got, want)
+			}
+			if got, want := err.IsTimeout, tt.isTimeout; got != want {
+				t.Errorf("IsTimeout: %t, expected %t", got, want)
+			}
+		})
+	}
+}
+
+func TestContextError(t *testing.T) {
+	err := contextError(context.DeadlineExceeded, "example.com")
+	if !err.Timeout() {
+		t.Errorf("Timeout(): false, expected true")
+	}
+	err = contextError(context.Canceled, "example.com")
+	if err.Timeout() {
+		t.Errorf("Timeout(): true, expected false")
+	}
+}
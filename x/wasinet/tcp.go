@@ -0,0 +1,313 @@
+package wasinet
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/netip"
+	"time"
+
+	"github.com/bytecodealliance/wasm-tools-go/cm"
+	"github.com/bytecodealliance/wasm-tools-go/x/wasi/io/streams"
+	"github.com/bytecodealliance/wasm-tools-go/x/wasi/sockets/network"
+	"github.com/bytecodealliance/wasm-tools-go/x/wasi/sockets/tcp"
+)
+
+// TCPConn implements [net.Conn] for a connected "wasi:sockets/tcp" socket.
+// It is safe for concurrent use, and Close interrupts blocked reads and writes.
+type TCPConn struct {
+	fd     netFD
+	socket tcp.TCPSocket
+	in     streams.InputStream
+	out    streams.OutputStream
+	laddr  *net.TCPAddr
+	raddr  *net.TCPAddr
+}
+
+// newTCPConn returns a [TCPConn] for a connected socket and its streams.
+func newTCPConn(socket tcp.TCPSocket, in streams.InputStream, out streams.OutputStream) *TCPConn {
+	c := &TCPConn{socket: socket, in: in, out: out}
+	if addr := socket.LocalAddress(); addr.IsOK() {
+		c.laddr = net.TCPAddrFromAddrPort(fromSocketAddress(*addr.OK()))
+	}
+	if addr := socket.RemoteAddress(); addr.IsOK() {
+		c.raddr = net.TCPAddrFromAddrPort(fromSocketAddress(*addr.OK()))
+	}
+	c.fd.destroy = func() {
+		// Child resources must be dropped before the socket.
+		c.in.ResourceDrop()
+		c.out.ResourceDrop()
+		c.socket.ResourceDrop()
+	}
+	return c
+}
+
+// Read implements [net.Conn], blocking until at least one byte is read.
+// It returns [io.EOF] when the remote end has shut down the connection.
+func (c *TCPConn) Read(p []byte) (int, error) {
+	if err := c.fd.acquire(); err != nil {
+		return 0, c.opError("read", err)
+	}
+	defer c.fd.release()
+	for {
+		if err := c.fd.check(&c.fd.rd); err != nil {
+			return 0, c.opError("read", err)
+		}
+		if len(p) == 0 {
+			return 0, nil
+		}
+		result := c.in.Read(uint64(len(p)))
+		if e := result.Err(); e != nil {
+			if e.Closed() {
+				return 0, io.EOF
+			}
+			return 0, c.opError("read", streamError(e))
+		}
+		if data := result.OK().Slice(); len(data) > 0 {
+			return copy(p, data), nil
+		}
+		if err := c.fd.wait(&c.fd.rd, c.in.Subscribe); err != nil {
+			return 0, c.opError("read", err)
+		}
+	}
+}
+
+// Write implements [net.Conn], blocking until all of p is written and flushed.
+func (c *TCPConn) Write(p []byte) (int, error) {
+	if err := c.fd.acquire(); err != nil {
+		return 0, c.opError("write", err)
+	}
+	defer c.fd.release()
+	n, err := c.write(p)
+	if err != nil {
+		return n, c.opError("write", err)
+	}
+	return n, nil
+}
+
+func (c *TCPConn) write(p []byte) (n int, err error) {
+	for len(p) > 0 {
+		permit, err := c.checkWrite()
+		if err != nil {
+			return n, err
+		}
+		chunk := p[:min(uint64(len(p)), permit)]
+		result := c.out.Write(cm.ToList(chunk))
+		if e := result.Err(); e != nil {
+			return n, streamError(e)
+		}
+		n += len(chunk)
+		p = p[len(chunk):]
+	}
+	if result := c.out.Flush(); result.IsErr() {
+		return n, streamError(result.Err())
+	}
+	// The stream permits writes when the flush is complete.
+	_, err = c.checkWrite()
+	return n, err
+}
+
+// checkWrite blocks until the output stream permits writing, returning the number of bytes permitted.
+func (c *TCPConn) checkWrite() (uint64, error) {
+	for {
+		if err := c.fd.check(&c.fd.wd); err != nil {
+			return 0, err
+		}
+		check := c.out.CheckWrite()
+		if e := check.Err(); e != nil {
+			return 0, streamError(e)
+		}
+		if permit := *check.OK(); permit > 0 {
+			return permit, nil
+		}
+		if err := c.fd.wait(&c.fd.wd, c.out.Subscribe); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// Close implements [net.Conn], closing the connection and dropping its socket
+// once reads and writes in progress return.
+func (c *TCPConn) Close() error {
+	if err := c.fd.close(); err != nil {
+		return c.opError("close", err)
+	}
+	return nil
+}
+
+// CloseRead shuts down the reading side of the connection.
+func (c *TCPConn) CloseRead() error {
+	return c.shutdown(tcp.ShutdownTypeReceive)
+}
+
+// CloseWrite shuts down the writing side of the connection.
+func (c *TCPConn) CloseWrite() error {
+	return c.shutdown(tcp.ShutdownTypeSend)
+}
+
+func (c *TCPConn) shutdown(how tcp.ShutdownType) error {
+	return c.control("close", func() cm.Result[network.ErrorCode, struct{}, network.ErrorCode] {
+		return c.socket.Shutdown(how)
+	})
+}
+
+// LocalAddr implements [net.Conn], returning a [*net.TCPAddr].
+func (c *TCPConn) LocalAddr() net.Addr {
+	if c.laddr == nil {
+		return nil
+	}
+	return c.laddr
+}
+
+// RemoteAddr implements [net.Conn], returning a [*net.TCPAddr].
+func (c *TCPConn) RemoteAddr() net.Addr {
+	if c.raddr == nil {
+		return nil
+	}
+	return c.raddr
+}
+
+// SetDeadline implements [net.Conn], setting the read and write deadlines.
+func (c *TCPConn) SetDeadline(t time.Time) error {
+	if err := c.SetReadDeadline(t); err != nil {
+		return err
+	}
+	return c.SetWriteDeadline(t)
+}
+
+// SetReadDeadline implements [net.Conn]. Reads blocked when the deadline is changed
+// continue with the new deadline.
+func (c *TCPConn) SetReadDeadline(t time.Time) error {
+	if err := c.fd.setDeadline(&c.fd.rd, t); err != nil {
+		return c.opError("set", err)
+	}
+	return nil
+}
+
+// SetWriteDeadline implements [net.Conn]. Writes blocked when the deadline is changed
+// continue with the new deadline.
+func (c *TCPConn) SetWriteDeadline(t time.Time) error {
+	if err := c.fd.setDeadline(&c.fd.wd, t); err != nil {
+		return c.opError("set", err)
+	}
+	return nil
+}
+
+// SetKeepAlive enables or disables TCP keep-alive messages on the connection.
+func (c *TCPConn) SetKeepAlive(keepalive bool) error {
+	return c.control("set", func() cm.Result[network.ErrorCode, struct{}, network.ErrorCode] {
+		return c.socket.SetKeepAliveEnabled(keepalive)
+	})
+}
+
+// SetKeepAlivePeriod sets the idle time and interval between keep-alive messages.
+func (c *TCPConn) SetKeepAlivePeriod(d time.Duration) error {
+	return c.control("set", func() cm.Result[network.ErrorCode, struct{}, network.ErrorCode] {
+		if result := c.socket.SetKeepAliveIdleTime(tcp.Duration(d)); result.IsErr() {
+			return result
+		}
+		return c.socket.SetKeepAliveInterval(tcp.Duration(d))
+	})
+}
+
+// control calls f, which operates on the socket of c, unless c is closed.
+func (c *TCPConn) control(op string, f func() cm.Result[network.ErrorCode, struct{}, network.ErrorCode]) error {
+	if err := c.fd.acquire(); err != nil {
+		return c.opError(op, err)
+	}
+	defer c.fd.release()
+	if result := f(); result.IsErr() {
+		return c.opError(op, &Error{Code: *result.Err()})
+	}
+	return nil
+}
+
+// opError wraps err in a [net.OpError] for operation op on c.
+func (c *TCPConn) opError(op string, err error) error {
+	return &net.OpError{Op: op, Net: "tcp", Source: c.LocalAddr(), Addr: c.RemoteAddr(), Err: err}
+}
+
+// streamError returns an error for a failed [streams.StreamError] e.
+func streamError(e *streams.StreamError) error {
+	if e.Closed() {
+		return io.ErrClosedPipe
+	}
+	ioErr := e.LastOperationFailed()
+	defer ioErr.ResourceDrop()
+	return errors.New("wasinet: " + ioErr.ToDebugString())
+}
+
+// TCPListener implements [net.Listener] for a listening "wasi:sockets/tcp" socket.
+type TCPListener struct {
+	fd     netFD
+	socket tcp.TCPSocket
+	addr   *net.TCPAddr
+}
+
+// newTCPListener returns a [TCPListener] for a listening socket.
+func newTCPListener(socket tcp.TCPSocket, addr netip.AddrPort) *TCPListener {
+	l := &TCPListener{socket: socket, addr: net.TCPAddrFromAddrPort(addr)}
+	if local := socket.LocalAddress(); local.IsOK() {
+		l.addr = net.TCPAddrFromAddrPort(fromSocketAddress(*local.OK()))
+	}
+	l.fd.destroy = socket.ResourceDrop
+	return l
+}
+
+// Accept implements [net.Listener], blocking until a connection is accepted.
+// The connection is a [*TCPConn].
+func (l *TCPListener) Accept() (net.Conn, error) {
+	return l.AcceptTCP()
+}
+
+// AcceptTCP blocks until a connection is accepted, and returns it.
+func (l *TCPListener) AcceptTCP() (*TCPConn, error) {
+	if err := l.fd.acquire(); err != nil {
+		return nil, l.opError("accept", err)
+	}
+	defer l.fd.release()
+	for {
+		if err := l.fd.check(&l.fd.rd); err != nil {
+			return nil, l.opError("accept", err)
+		}
+		result := l.socket.Accept()
+		if code := result.Err(); code != nil {
+			if *code != network.ErrorCodeWouldBlock {
+				return nil, l.opError("accept", &Error{Code: *code})
+			}
+			if err := l.fd.wait(&l.fd.rd, l.socket.Subscribe); err != nil {
+				return nil, l.opError("accept", err)
+			}
+			continue
+		}
+		accepted := result.OK()
+		return newTCPConn(accepted.F0, accepted.F1, accepted.F2), nil
+	}
+}
+
+// Close implements [net.Listener], closing the listener and dropping its socket
+// once an Accept in progress returns.
+func (l *TCPListener) Close() error {
+	if err := l.fd.close(); err != nil {
+		return l.opError("close", err)
+	}
+	return nil
+}
+
+// Addr implements [net.Listener], returning the [*net.TCPAddr] the listener is bound to.
+func (l *TCPListener) Addr() net.Addr {
+	return l.addr
+}
+
+// SetDeadline sets the deadline for Accept. A zero t means Accept does not time out.
+func (l *TCPListener) SetDeadline(t time.Time) error {
+	if err := l.fd.setDeadline(&l.fd.rd, t); err != nil {
+		return l.opError("set", err)
+	}
+	return nil
+}
+
+// opError wraps err in a [net.OpError] for operation op on l.
+func (l *TCPListener) opError(op string, err error) error {
+	return &net.OpError{Op: op, Net: "tcp", Addr: l.addr, Err: err}
+}
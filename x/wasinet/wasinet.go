@@ -0,0 +1,237 @@
+// Package wasinet adapts "wasi:sockets" to the interfaces of package [net],
+// so existing Go networking code can run as a WASI 0.2 component:
+//
+//	conn, err := wasinet.Dial("tcp", "example.com:80")
+//	if err != nil {
+//		return err
+//	}
+//	defer conn.Close()
+//	conn.SetDeadline(time.Now().Add(5 * time.Second))
+//	fmt.Fprint(conn, "GET / HTTP/1.0\r\nHost: example.com\r\n\r\n")
+//
+// [TCPConn] implements [net.Conn], [TCPListener] implements [net.Listener],
+// and [Dialer] and [ListenConfig] mirror their counterparts in package net.
+// Deadlines are implemented with WASI pollables, see [wasipoll.Wait].
+// Host names are resolved with [Resolver], using "wasi:sockets/ip-name-lookup".
+//
+// Only TCP networks ("tcp", "tcp4" and "tcp6") are supported.
+package wasinet
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"strconv"
+	"sync"
+	"syscall"
+
+	instancenetwork "github.com/bytecodealliance/wasm-tools-go/x/wasi/sockets/instance-network"
+	"github.com/bytecodealliance/wasm-tools-go/x/wasi/sockets/network"
+)
+
+// Error is an error-code returned by a "wasi:sockets" operation.
+// It unwraps to the equivalent [syscall.Errno], if any, so conditions such as
+// a refused connection can be tested with [errors.Is] and [syscall.ECONNREFUSED].
+type Error struct {
+	Code network.ErrorCode
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	return "wasinet: " + e.Code.String()
+}
+
+// Timeout reports whether the operation timed out.
+func (e *Error) Timeout() bool {
+	return e.Code == network.ErrorCodeTimeout
+}
+
+// Temporary reports whether the operation may succeed if retried.
+func (e *Error) Temporary() bool {
+	return e.Code == network.ErrorCodeTimeout || e.Code == network.ErrorCodeWouldBlock
+}
+
+// Unwrap returns the [syscall.Errno] equivalent to e.Code, or nil if there is none.
+func (e *Error) Unwrap() error {
+	if errno, ok := errnos[e.Code]; ok {
+		return errno
+	}
+	return nil
+}
+
+var errnos = map[network.ErrorCode]syscall.Errno{
+	network.ErrorCodeAccessDenied:       syscall.EACCES,
+	network.ErrorCodeNotSupported:       syscall.ENOTSUP,
+	network.ErrorCodeInvalidArgument:    syscall.EINVAL,
+	network.ErrorCodeOutOfMemory:        syscall.ENOMEM,
+	network.ErrorCodeTimeout:            syscall.ETIMEDOUT,
+	network.ErrorCodeWouldBlock:         syscall.EAGAIN,
+	network.ErrorCodeNewSocketLimit:     syscall.EMFILE,
+	network.ErrorCodeAddressNotBindable: syscall.EADDRNOTAVAIL,
+	network.ErrorCodeAddressInUse:       syscall.EADDRINUSE,
+	network.ErrorCodeRemoteUnreachable:  syscall.EHOSTUNREACH,
+	network.ErrorCodeConnectionRefused:  syscall.ECONNREFUSED,
+	network.ErrorCodeConnectionReset:    syscall.ECONNRESET,
+	network.ErrorCodeConnectionAborted:  syscall.ECONNABORTED,
+}
+
+var instanceNetwork struct {
+	once    sync.Once
+	network network.Network
+}
+
+// networkOrInstance returns n, or the network returned by "wasi:sockets/instance-network" if n is zero.
+func networkOrInstance(n network.Network) network.Network {
+	if n != 0 {
+		return n
+	}
+	instanceNetwork.once.Do(func() {
+		instanceNetwork.network = instancenetwork.InstanceNetwork()
+	})
+	return instanceNetwork.network
+}
+
+// family returns the IP network ("ip", "ip4" or "ip6") for TCP network name,
+// or an error if the network is not supported.
+func family(name string) (string, error) {
+	switch name {
+	case "tcp":
+		return "ip", nil
+	case "tcp4":
+		return "ip4", nil
+	case "tcp6":
+		return "ip6", nil
+	}
+	return "", net.UnknownNetworkError(name)
+}
+
+// resolve returns the addresses of host:port address on TCP network name.
+// An empty host is the unspecified address if passive is true (to listen),
+// or the loopback address otherwise (to dial). Host names are resolved with r.
+func resolve(ctx context.Context, r *Resolver, name, address string, passive bool) ([]netip.AddrPort, error) {
+	ipFamily, err := family(name)
+	if err != nil {
+		return nil, err
+	}
+	host, service, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	port, ok := parsePort(service)
+	if !ok {
+		return nil, &net.AddrError{Err: "invalid port", Addr: address}
+	}
+
+	var addrs []netip.Addr
+	switch {
+	case host == "":
+		addrs = []netip.Addr{defaultAddr(ipFamily, passive)}
+	default:
+		if addr, err := netip.ParseAddr(host); err == nil {
+			addrs = []netip.Addr{addr}
+			break
+		}
+		addrs, err = r.LookupNetIP(ctx, ipFamily, host)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var out []netip.AddrPort
+	for _, addr := range addrs {
+		// WASI rejects IPv4-mapped IPv6 addresses.
+		addr = addr.Unmap()
+		if matchFamily(ipFamily, addr) {
+			out = append(out, netip.AddrPortFrom(addr, port))
+		}
+	}
+	if len(out) == 0 {
+		return nil, &net.AddrError{Err: "no suitable address found", Addr: address}
+	}
+	return out, nil
+}
+
+// parsePort parses a decimal port number. An empty port is port 0.
+// Service names are not supported.
+func parsePort(service string) (uint16, bool) {
+	if service == "" {
+		return 0, true
+	}
+	port, err := strconv.ParseUint(service, 10, 16)
+	return uint16(port), err == nil
+}
+
+// defaultAddr returns the address for an empty host in ipFamily.
+// Network "ip" defaults to IPv4, as WASI hosts may not support dual-stack sockets.
+func defaultAddr(ipFamily string, passive bool) netip.Addr {
+	switch {
+	case ipFamily == "ip6" && passive:
+		return netip.IPv6Unspecified()
+	case ipFamily == "ip6":
+		return netip.IPv6Loopback()
+	case passive:
+		return netip.IPv4Unspecified()
+	}
+	return netip.AddrFrom4([4]byte{127, 0, 0, 1})
+}
+
+// matchFamily reports whether addr belongs to ipFamily "ip", "ip4", or "ip6".
+func matchFamily(ipFamily string, addr netip.Addr) bool {
+	switch ipFamily {
+	case "ip4":
+		return addr.Is4()
+	case "ip6":
+		return addr.Is6()
+	}
+	return true
+}
+
+// addressFamily returns the wasi:sockets address family of addr.
+func addressFamily(addr netip.AddrPort) network.IPAddressFamily {
+	if addr.Addr().Is4() {
+		return network.IPAddressFamilyIPv4
+	}
+	return network.IPAddressFamilyIPv6
+}
+
+// toSocketAddress converts addr into a wasi:sockets ip-socket-address.
+// A numeric IPv6 zone is used as the scope ID.
+func toSocketAddress(addr netip.AddrPort) network.IPSocketAddress {
+	ip := addr.Addr()
+	if ip.Is4() {
+		return network.IPSocketAddressIPv4(network.IPv4SocketAddress{
+			Port:    addr.Port(),
+			Address: network.IPv4Address(ip.As4()),
+		})
+	}
+	b := ip.As16()
+	var a network.IPv6Address
+	for i := range a {
+		a[i] = uint16(b[i*2])<<8 | uint16(b[i*2+1])
+	}
+	scope, _ := strconv.ParseUint(ip.Zone(), 10, 32)
+	return network.IPSocketAddressIPv6(network.IPv6SocketAddress{
+		Port:    addr.Port(),
+		Address: a,
+		ScopeID: uint32(scope),
+	})
+}
+
+// fromSocketAddress converts a wasi:sockets ip-socket-address into a [netip.AddrPort].
+// A non-zero scope ID is used as the IPv6 zone.
+func fromSocketAddress(addr network.IPSocketAddress) netip.AddrPort {
+	if v4 := addr.IPv4(); v4 != nil {
+		return netip.AddrPortFrom(netip.AddrFrom4([4]byte(v4.Address)), v4.Port)
+	}
+	v6 := addr.IPv6()
+	var b [16]byte
+	for i, v := range v6.Address {
+		b[i*2] = byte(v >> 8)
+		b[i*2+1] = byte(v)
+	}
+	ip := netip.AddrFrom16(b)
+	if v6.ScopeID != 0 {
+		ip = ip.WithZone(strconv.FormatUint(uint64(v6.ScopeID), 10))
+	}
+	return netip.AddrPortFrom(ip, v6.Port)
+}
@@ -0,0 +1,186 @@
+package wasinet
+
+import (
+	"errors"
+	"net"
+	"net/netip"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/bytecodealliance/wasm-tools-go/x/wasi/sockets/network"
+)
+
+func TestSocketAddress(t *testing.T) {
+	tests := []string{
+		"127.0.0.1:80",
+		"192.168.1.254:65535",
+		"[::1]:8080",
+		"[2001:db8::cafe:f00d]:443",
+		"[fe80::1%3]:22",
+	}
+	for _, tt := range tests {
+		t.Run(tt, func(t *testing.T) {
+			addr := netip.MustParseAddrPort(tt)
+			sa := toSocketAddress(addr)
+			if addr.Addr().Is4() != (sa.IPv4() != nil) {
+				t.Errorf("toSocketAddress(): %v, expected IPv4 %t", sa, addr.Addr().Is4())
+			}
+			if got := fromSocketAddress(sa); got != addr {
+				t.Errorf("fromSocketAddress(): %v, expected %v", got, addr)
+			}
+		})
+	}
+
+	sa := toSocketAddress(netip.MustParseAddrPort("[2001:db8::1]:443"))
+	want := network.IPv6Address{0x2001, 0xdb8, 0, 0, 0, 0, 0, 1}
+	if got := sa.IPv6().Address; got != want {
+		t.Errorf("toSocketAddress(): %v, expected %v", got, want)
+	}
+}
+
+func TestFamily(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"tcp", "ip"},
+		{"tcp4", "ip4"},
+		{"tcp6", "ip6"},
+	}
+	for _, tt := range tests {
+		got, err := family(tt.name)
+		if err != nil || got != tt.want {
+			t.Errorf("family(%q): %q, %v, expected %q", tt.name, got, err, tt.want)
+		}
+	}
+	var unknown net.UnknownNetworkError
+	if _, err := family("udp"); !errors.As(err, &unknown) {
+		t.Errorf("family(%q): %v, expected %T", "udp", err, unknown)
+	}
+}
+
+func TestParsePort(t *testing.T) {
+	tests := []struct {
+		service string
+		port    uint16
+		ok      bool
+	}{
+		{"", 0, true},
+		{"0", 0, true},
+		{"80", 80, true},
+		{"65535", 65535, true},
+		{"65536", 0, false},
+		{"http", 0, false},
+		{"-1", 0, false},
+	}
+	for _, tt := range tests {
+		port, ok := parsePort(tt.service)
+		if ok != tt.ok || (ok && port != tt.port) {
+			t.Errorf("parsePort(%q): %d, %t, expected %d, %t", tt.service, port, ok, tt.port, tt.ok)
+		}
+	}
+}
+
+func TestDefaultAddr(t *testing.T) {
+	tests := []struct {
+		family  string
+		passive bool
+		want    string
+	}{
+		{"ip", true, "0.0.0.0"},
+		{"ip", false, "127.0.0.1"},
+		{"ip4", true, "0.0.0.0"},
+		{"ip4", false, "127.0.0.1"},
+		{"ip6", true, "::"},
+		{"ip6", false, "::1"},
+	}
+	for _, tt := range tests {
+		if got, want := defaultAddr(tt.family, tt.passive), netip.MustParseAddr(tt.want); got != want {
+			t.Errorf("defaultAddr(%q, %t): %v, expected %v", tt.family, tt.passive, got, want)
+		}
+	}
+}
+
+func TestError(t *testing.T) {
+	var err error = &Error{Code: network.ErrorCodeConnectionRefused}
+	if got, want := err.Error(), "wasinet: connection-refused"; got != want {
+		t.Errorf("Error(): %q, expected %q", got, want)
+	}
+	if !errors.Is(err, syscall.ECONNREFUSED) {
+		t.Errorf("errors.Is(%v, ECONNREFUSED): false, expected true", err)
+	}
+	var netErr net.Error
+	if !errors.As(err, &netErr) || netErr.Timeout() {
+		t.Errorf("%v: expected net.Error without timeout", err)
+	}
+	err = &Error{Code: network.ErrorCodeTimeout}
+	if !errors.As(err, &netErr) || !netErr.Timeout() {
+		t.Errorf("%v: expected net.Error with timeout", err)
+	}
+	if err := (&Error{Code: network.ErrorCodeUnknown}).Unwrap(); err != nil {
+		t.Errorf("Unwrap(): %v, expected nil", err)
+	}
+}
+
+func TestNetFD(t *testing.T) {
+	destroyed := 0
+	fd := &netFD{destroy: func() { destroyed++ }}
+
+	if err := fd.check(&fd.rd); err != nil {
+		t.Fatalf("check(): %v, expected nil", err)
+	}
+	if err := fd.setDeadline(&fd.rd, time.Now().Add(-time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if err := fd.check(&fd.rd); !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Errorf("check(): %v, expected %v", err, os.ErrDeadlineExceeded)
+	}
+	if err := fd.check(&fd.wd); err != nil {
+		t.Errorf("check(): %v, expected nil for write deadline", err)
+	}
+
+	// Changing a deadline cancels the context of a wait for the previous deadline.
+	fd.setDeadline(&fd.wd, time.Now().Add(time.Hour))
+	ctx, err := fd.context(&fd.wd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fd.setDeadline(&fd.wd, time.Time{})
+	if ctx.Err() == nil {
+		t.Error("setDeadline(): context not canceled")
+	}
+
+	// Resources are dropped when the last operation returns after close.
+	if err := fd.acquire(); err != nil {
+		t.Fatal(err)
+	}
+	ctx, _ = fd.context(&fd.wd)
+	if err := fd.close(); err != nil {
+		t.Fatal(err)
+	}
+	if ctx.Err() == nil {
+		t.Error("close(): context not canceled")
+	}
+	if destroyed != 0 {
+		t.Errorf("close(): destroyed with an operation in progress")
+	}
+	if err := fd.check(&fd.wd); !errors.Is(err, net.ErrClosed) {
+		t.Errorf("check(): %v, expected %v", err, net.ErrClosed)
+	}
+	fd.release()
+	if destroyed != 1 {
+		t.Errorf("release(): destroyed %d times, expected 1", destroyed)
+	}
+
+	if err := fd.acquire(); !errors.Is(err, net.ErrClosed) {
+		t.Errorf("acquire(): %v, expected %v", err, net.ErrClosed)
+	}
+	if err := fd.close(); !errors.Is(err, net.ErrClosed) {
+		t.Errorf("close(): %v, expected %v", err, net.ErrClosed)
+	}
+	if destroyed != 1 {
+		t.Errorf("close(): destroyed %d times, expected 1", destroyed)
+	}
+}
@@ -0,0 +1,26 @@
+//go:build !wasm
+
+package wasipoll
+
+import (
+	"time"
+
+	"github.com/bytecodealliance/wasm-tools-go/x/wasi/io/poll"
+)
+
+var sys system = unsupported{}
+
+// unsupported implements system on architectures other than WebAssembly, which cannot import WASI.
+type unsupported struct{}
+
+func (unsupported) poll([]poll.Pollable) []uint32 {
+	panic("wasipoll: WASI is not supported on this architecture")
+}
+
+func (unsupported) subscribeDuration(time.Duration) poll.Pollable {
+	panic("wasipoll: WASI is not supported on this architecture")
+}
+
+func (unsupported) drop(poll.Pollable) {
+	panic("wasipoll: WASI is not supported on this architecture")
+}
@@ -0,0 +1,28 @@
+//go:build wasm
+
+package wasipoll
+
+import (
+	"time"
+
+	"github.com/bytecodealliance/wasm-tools-go/cm"
+	monotonicclock "github.com/bytecodealliance/wasm-tools-go/x/wasi/clocks/monotonic-clock"
+	"github.com/bytecodealliance/wasm-tools-go/x/wasi/io/poll"
+)
+
+var sys system = wasi{}
+
+// wasi implements system with "wasi:io/poll" and "wasi:clocks/monotonic-clock".
+type wasi struct{}
+
+func (wasi) poll(pollables []poll.Pollable) []uint32 {
+	return poll.Poll(cm.ToList(pollables)).Slice()
+}
+
+func (wasi) subscribeDuration(d time.Duration) poll.Pollable {
+	return monotonicclock.SubscribeDuration(monotonicclock.Duration(d.Nanoseconds()))
+}
+
+func (wasi) drop(pollable poll.Pollable) {
+	pollable.ResourceDrop()
+}
@@ -0,0 +1,161 @@
+// Package wasipoll bridges WASI pollables and [context.Context] cancellation.
+//
+// A WASI [poll.Pollable] blocks the whole program while it is polled, so other goroutines
+// cannot run to cancel a context. [Wait] and [Poll] wait for pollables in intervals of at
+// most [PollInterval], running other goroutines and checking the context between intervals,
+// and return early when the context is done. A context deadline is waited for with a
+// "wasi:clocks/monotonic-clock" timer, without polling:
+//
+//	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+//	defer cancel()
+//	pollable := stream.Subscribe()
+//	defer pollable.ResourceDrop()
+//	if err := wasipoll.Wait(ctx, pollable); err != nil {
+//		return err // context.DeadlineExceeded
+//	}
+//
+// A [Loop] multiplexes many pollables, calling a function as each is ready.
+package wasipoll
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"time"
+
+	"github.com/bytecodealliance/wasm-tools-go/x/wasi/io/poll"
+)
+
+// PollInterval is the maximum time that [Wait] and [Poll] block the program before
+// running other goroutines and checking if the context is done. It is not used for
+// contexts that cannot be canceled, which wait for their pollables without interruption.
+var PollInterval = 10 * time.Millisecond
+
+// Wait blocks until pollable is ready or ctx is done.
+// It returns ctx.Err() if ctx is done before pollable is ready.
+func Wait(ctx context.Context, pollable poll.Pollable) error {
+	_, err := Poll(ctx, pollable)
+	return err
+}
+
+// Poll blocks until at least one of pollables is ready or ctx is done.
+// It returns the indexes of the ready pollables, in increasing order,
+// or ctx.Err() if ctx is done before any pollable is ready.
+// Poll does not drop pollables.
+func Poll(ctx context.Context, pollables ...poll.Pollable) ([]int, error) {
+	if len(pollables) == 0 {
+		return nil, errors.New("wasipoll: no pollables")
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if ctx.Done() == nil {
+		return indexes(sys.poll(pollables), len(pollables)), nil
+	}
+
+	deadline, hasDeadline := ctx.Deadline()
+	list := make([]poll.Pollable, len(pollables)+1)
+	copy(list, pollables)
+	for {
+		timeout := PollInterval
+		if hasDeadline {
+			timeout = min(timeout, max(time.Until(deadline), 0))
+		}
+		timer := sys.subscribeDuration(timeout)
+		list[len(pollables)] = timer
+		ready := indexes(sys.poll(list), len(pollables))
+		sys.drop(timer)
+		if len(ready) > 0 {
+			return ready, nil
+		}
+
+		// Run other goroutines, which may cancel ctx.
+		runtime.Gosched()
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if hasDeadline && !time.Now().Before(deadline) {
+			return nil, context.DeadlineExceeded
+		}
+	}
+}
+
+// indexes returns the indexes in ready that are less than n, sorted and without duplicates.
+func indexes(ready []uint32, n int) []int {
+	seen := make([]bool, n)
+	for _, i := range ready {
+		if int(i) < n {
+			seen[i] = true
+		}
+	}
+	var out []int
+	for i, ok := range seen {
+		if ok {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+// Loop multiplexes pollables, calling a function when each is ready.
+// The zero value is an empty Loop ready to use. A Loop is not safe for concurrent use.
+type Loop struct {
+	pending []pending
+}
+
+type pending struct {
+	pollable poll.Pollable
+	f        func()
+}
+
+// Add adds pollable to l, to call f once from [Loop.Run] when pollable is ready.
+// Functions called by Run may add pollables, for example to wait for a stream again.
+// The caller remains responsible for dropping pollable after f is called.
+func (l *Loop) Add(pollable poll.Pollable, f func()) {
+	l.pending = append(l.pending, pending{pollable, f})
+}
+
+// Len returns the number of pollables in l that are waiting to be ready.
+func (l *Loop) Len() int {
+	return len(l.pending)
+}
+
+// Run polls the pollables in l, calling the function for each ready pollable,
+// in the order they were added, until no pollables remain or ctx is done.
+// It returns ctx.Err() if ctx is done, leaving the remaining pollables in l.
+func (l *Loop) Run(ctx context.Context) error {
+	for len(l.pending) > 0 {
+		pollables := make([]poll.Pollable, len(l.pending))
+		for i, p := range l.pending {
+			pollables[i] = p.pollable
+		}
+		ready, err := Poll(ctx, pollables...)
+		if err != nil {
+			return err
+		}
+
+		// Remove ready pollables before calling their functions, which may add pollables.
+		var calls []func()
+		remaining := make([]pending, 0, len(l.pending)-len(ready))
+		for i, p := range l.pending {
+			if len(ready) > 0 && ready[0] == i {
+				calls = append(calls, p.f)
+				ready = ready[1:]
+				continue
+			}
+			remaining = append(remaining, p)
+		}
+		l.pending = remaining
+		for _, f := range calls {
+			f()
+		}
+	}
+	return nil
+}
+
+// system is the WASI host functions used by this package, replaced in tests.
+type system interface {
+	poll(pollables []poll.Pollable) []uint32
+	subscribeDuration(d time.Duration) poll.Pollable
+	drop(pollable poll.Pollable)
+}
@@ -0,0 +1,208 @@
+package wasipoll
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/bytecodealliance/wasm-tools-go/x/wasi/io/poll"
+)
+
+// fake implements system. Pollables in ready are ready. Timers are ready immediately,
+// so each call to poll with a timer is one interval of Poll.
+type fake struct {
+	ready   map[poll.Pollable]bool
+	timers  map[poll.Pollable]time.Duration
+	next    poll.Pollable
+	polls   int
+	onPoll  func(n int) // called before each poll, with the number of previous polls
+	dropped int
+}
+
+func newFake() *fake {
+	return &fake{
+		ready:  make(map[poll.Pollable]bool),
+		timers: make(map[poll.Pollable]time.Duration),
+		next:   1000,
+	}
+}
+
+func (f *fake) poll(pollables []poll.Pollable) []uint32 {
+	if f.onPoll != nil {
+		f.onPoll(f.polls)
+	}
+	f.polls++
+	var ready []uint32
+	for i, p := range pollables {
+		if _, ok := f.timers[p]; ok || f.ready[p] {
+			ready = append(ready, uint32(i))
+		}
+	}
+	if len(ready) == 0 {
+		panic("poll: no pollable ready, would block forever")
+	}
+	return ready
+}
+
+func (f *fake) subscribeDuration(d time.Duration) poll.Pollable {
+	f.next++
+	f.timers[f.next] = d
+	return f.next
+}
+
+func (f *fake) drop(p poll.Pollable) {
+	delete(f.timers, p)
+	f.dropped++
+}
+
+func setFake(t *testing.T) *fake {
+	f := newFake()
+	prev := sys
+	sys = f
+	t.Cleanup(func() { sys = prev })
+	return f
+}
+
+func TestPoll(t *testing.T) {
+	f := setFake(t)
+	f.ready[2] = true
+	f.ready[4] = true
+
+	// A context that cannot be canceled polls without a timer.
+	ready, err := Poll(context.Background(), 1, 2, 3, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(ready, []int{1, 3}) {
+		t.Errorf("Poll(): %v, expected [1 3]", ready)
+	}
+	if f.dropped != 0 {
+		t.Errorf("Poll(): dropped %d timers, expected 0", f.dropped)
+	}
+
+	// A context that can be canceled polls with a timer, which is not reported.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ready, err = Poll(ctx, 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(ready, []int{1}) {
+		t.Errorf("Poll(): %v, expected [1]", ready)
+	}
+	if f.dropped != 1 || len(f.timers) != 0 {
+		t.Errorf("Poll(): dropped %d of %d timers, expected 1", f.dropped, f.dropped+len(f.timers))
+	}
+
+	_, err = Poll(ctx)
+	if err == nil {
+		t.Error("Poll(): expected error with no pollables")
+	}
+}
+
+func TestWaitCanceled(t *testing.T) {
+	f := setFake(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	f.onPoll = func(n int) {
+		if n == 2 {
+			// Cancel from another goroutine, which runs between intervals.
+			go cancel()
+		}
+	}
+	err := Wait(ctx, 1)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Wait(): %v, expected %v", err, context.Canceled)
+	}
+	if f.polls < 3 {
+		t.Errorf("Wait(): %d polls, expected at least 3", f.polls)
+	}
+	if len(f.timers) != 0 {
+		t.Errorf("Wait(): %d timers not dropped", len(f.timers))
+	}
+
+	f.polls = 0
+	err = Wait(ctx, 1)
+	if !errors.Is(err, context.Canceled) || f.polls != 0 {
+		t.Errorf("Wait(): %v after %d polls, expected %v without polling", err, f.polls, context.Canceled)
+	}
+}
+
+func TestWaitDeadline(t *testing.T) {
+	f := setFake(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	var durations []time.Duration
+	f.onPoll = func(n int) {
+		for _, d := range f.timers {
+			durations = append(durations, d)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	err := Wait(ctx, 1)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait(): %v, expected %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("Wait(): returned after %s, expected at least 20ms", elapsed)
+	}
+	for _, d := range durations {
+		if d > PollInterval || d > 20*time.Millisecond {
+			t.Errorf("Wait(): timer of %s, expected at most %s", d, PollInterval)
+		}
+	}
+}
+
+func TestLoop(t *testing.T) {
+	f := setFake(t)
+	f.ready[1] = true
+	f.ready[3] = true
+
+	var l Loop
+	var calls []string
+	l.Add(1, func() {
+		calls = append(calls, "1")
+		// Wait again for a pollable that is ready later.
+		l.Add(2, func() { calls = append(calls, "2") })
+		f.ready[2] = true
+	})
+	l.Add(4, func() { calls = append(calls, "4") })
+	l.Add(3, func() { calls = append(calls, "3") })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	f.onPoll = func(n int) {
+		if n == 3 {
+			cancel()
+		}
+	}
+	err := l.Run(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Run(): %v, expected %v", err, context.Canceled)
+	}
+	if want := []string{"1", "3", "2"}; !slices.Equal(calls, want) {
+		t.Errorf("Run(): called %v, expected %v", calls, want)
+	}
+	if l.Len() != 1 {
+		t.Errorf("Len(): %d, expected 1", l.Len())
+	}
+
+	f.ready[4] = true
+	err = l.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"1", "3", "2", "4"}; !slices.Equal(calls, want) {
+		t.Errorf("Run(): called %v, expected %v", calls, want)
+	}
+	if l.Len() != 0 {
+		t.Errorf("Len(): %d, expected 0", l.Len())
+	}
+}
+
+func TestIndexes(t *testing.T) {
+	if got := indexes([]uint32{3, 0, 3, 1}, 3); !slices.Equal(got, []int{0, 1}) {
+		t.Errorf("indexes(): %v, expected [0 1]", got)
+	}
+}
@@ -0,0 +1,11 @@
+// Package install calls [wasirand.Install] when imported, so [crypto/rand.Reader]
+// reads from "wasi:random/random" in components without further setup:
+//
+//	import _ "github.com/bytecodealliance/wasm-tools-go/x/wasirand/install"
+package install
+
+import "github.com/bytecodealliance/wasm-tools-go/x/wasirand"
+
+func init() {
+	wasirand.Install()
+}
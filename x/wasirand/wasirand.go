@@ -0,0 +1,98 @@
+// Package wasirand adapts "wasi:random" to the interfaces of packages [crypto/rand] and [math/rand].
+//
+// [Reader] reads cryptographically secure random bytes from "wasi:random/random",
+// and [NewSource] returns a pseudo-random [rand.Source64] seeded by "wasi:random/insecure-seed":
+//
+//	key := make([]byte, 32)
+//	if _, err := io.ReadFull(wasirand.Reader, key); err != nil {
+//		return err
+//	}
+//	r := rand.New(wasirand.NewSource())
+//	fmt.Println(r.Intn(100))
+//
+// [Install] makes [crypto/rand.Reader] read from "wasi:random/random".
+// Import package [github.com/bytecodealliance/wasm-tools-go/x/wasirand/install]
+// for its side effect to call Install when a component is initialized.
+package wasirand
+
+import (
+	cryptorand "crypto/rand"
+	"io"
+	"math/rand"
+	randv2 "math/rand/v2"
+
+	insecureseed "github.com/bytecodealliance/wasm-tools-go/x/wasi/random/insecure-seed"
+	"github.com/bytecodealliance/wasm-tools-go/x/wasi/random/random"
+)
+
+// Reader is a cryptographically secure random number generator reading from "wasi:random/random".
+// It is safe for concurrent use, and its Read method never returns an error.
+var Reader io.Reader = reader{}
+
+// Install sets [crypto/rand.Reader] to [Reader].
+//
+// The top-level functions of packages [math/rand] and [math/rand/v2] are seeded by the
+// Go runtime, and are not affected; use [NewSource] for a source seeded by the host.
+func Install() {
+	cryptorand.Reader = Reader
+}
+
+// maxRead is the maximum number of bytes requested from the host in a single call.
+const maxRead = 64 << 10
+
+type reader struct{}
+
+// Read implements [io.Reader], filling p with random bytes.
+func (reader) Read(p []byte) (int, error) {
+	return fill(p, func(n uint64) []byte {
+		return random.GetRandomBytes(n).Slice()
+	}), nil
+}
+
+// fill fills p with bytes returned by get, in chunks of at most maxRead bytes.
+func fill(p []byte, get func(n uint64) []byte) int {
+	n := 0
+	for n < len(p) {
+		n += copy(p[n:], get(uint64(min(len(p)-n, maxRead))))
+	}
+	return n
+}
+
+// Source is a pseudo-random [rand.Source64], which also implements [randv2.Source].
+// It is not cryptographically secure, and, like the sources of package [math/rand],
+// it is not safe for concurrent use.
+type Source struct {
+	pcg randv2.PCG
+}
+
+var (
+	_ rand.Source64 = &Source{}
+	_ randv2.Source = &Source{}
+)
+
+// NewSource returns a new [Source] seeded with the 128 bits returned by "wasi:random/insecure-seed",
+// which differ for each component instance.
+func NewSource() *Source {
+	return newSource(insecureseed.InsecureSeed())
+}
+
+func newSource(seed [2]uint64) *Source {
+	s := &Source{}
+	s.pcg.Seed(seed[0], seed[1])
+	return s
+}
+
+// Uint64 returns a pseudo-random 64-bit value.
+func (s *Source) Uint64() uint64 {
+	return s.pcg.Uint64()
+}
+
+// Int63 returns a non-negative pseudo-random 63-bit integer.
+func (s *Source) Int63() int64 {
+	return int64(s.pcg.Uint64() >> 1)
+}
+
+// Seed reseeds s with seed, so it returns a deterministic sequence of values.
+func (s *Source) Seed(seed int64) {
+	s.pcg.Seed(uint64(seed), 0)
+}
@@ -0,0 +1,63 @@
+package wasirand
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestFill(t *testing.T) {
+	var requests []uint64
+	get := func(n uint64) []byte {
+		requests = append(requests, n)
+		return bytes.Repeat([]byte{byte(len(requests))}, int(n))
+	}
+	p := make([]byte, maxRead*2+10)
+	if n := fill(p, get); n != len(p) {
+		t.Errorf("fill(): %d, expected %d", n, len(p))
+	}
+	if len(requests) != 3 || requests[0] != maxRead || requests[1] != maxRead || requests[2] != 10 {
+		t.Errorf("fill(): requested %v, expected [%d %d 10]", requests, maxRead, maxRead)
+	}
+	if p[0] != 1 || p[maxRead] != 2 || p[len(p)-1] != 3 {
+		t.Errorf("fill(): bytes not copied in order")
+	}
+
+	// Hosts may return fewer bytes than requested.
+	requests = nil
+	short := func(n uint64) []byte {
+		requests = append(requests, n)
+		return make([]byte, min(n, 3))
+	}
+	if n := fill(make([]byte, 10), short); n != 10 || len(requests) != 4 {
+		t.Errorf("fill(): %d after %d requests, expected 10 after 4", n, len(requests))
+	}
+}
+
+func TestSource(t *testing.T) {
+	a := newSource([2]uint64{1, 2})
+	b := newSource([2]uint64{1, 2})
+	c := newSource([2]uint64{3, 4})
+	same := true
+	for i := 0; i < 100; i++ {
+		x, y, z := a.Uint64(), b.Uint64(), c.Uint64()
+		if x != y {
+			t.Fatalf("Uint64(): %d != %d with the same seed", x, y)
+		}
+		same = same && x == z
+	}
+	if same {
+		t.Error("Uint64(): same values with different seeds")
+	}
+
+	for i := 0; i < 100; i++ {
+		if v := a.Int63(); v < 0 {
+			t.Fatalf("Int63(): %d, expected non-negative", v)
+		}
+	}
+
+	a.Seed(42)
+	b.Seed(42)
+	if x, y := a.Uint64(), b.Uint64(); x != y {
+		t.Errorf("Seed(): %d != %d after reseeding", x, y)
+	}
+}
@@ -0,0 +1,172 @@
+// Package wasitime adapts "wasi:clocks/wall-clock" and "wasi:clocks/monotonic-clock"
+// to the types of package [time], so programs need not convert [wallclock.DateTime]
+// records and raw nanosecond counts:
+//
+//	start := wasitime.Monotonic()
+//	t := wasitime.NewTimer(100 * time.Millisecond)
+//	defer t.Stop()
+//	if err := t.Wait(ctx); err != nil {
+//		return err
+//	}
+//	fmt.Println(wasitime.Now().Format(time.RFC3339), start.Elapsed())
+//
+// Timers are WASI pollables, so they can be multiplexed with other pollables
+// with [wasipoll.Loop] or [wasipoll.Poll].
+package wasitime
+
+import (
+	"context"
+	"time"
+
+	monotonicclock "github.com/bytecodealliance/wasm-tools-go/x/wasi/clocks/monotonic-clock"
+	wallclock "github.com/bytecodealliance/wasm-tools-go/x/wasi/clocks/wall-clock"
+	"github.com/bytecodealliance/wasm-tools-go/x/wasi/io/poll"
+	"github.com/bytecodealliance/wasm-tools-go/x/wasipoll"
+)
+
+// Now returns the current time of the WASI wall clock.
+// The wall clock is not monotonic: use [Monotonic] to measure elapsed time.
+func Now() time.Time {
+	return Time(wallclock.Now())
+}
+
+// Resolution returns the resolution of the WASI wall clock.
+func Resolution() time.Duration {
+	return Duration(wallclock.Resolution())
+}
+
+// Since returns the time elapsed since t, according to the WASI wall clock.
+func Since(t time.Time) time.Duration {
+	return Now().Sub(t)
+}
+
+// Until returns the duration until t, according to the WASI wall clock.
+func Until(t time.Time) time.Duration {
+	return t.Sub(Now())
+}
+
+// Time returns the [time.Time] for [wallclock.DateTime] dt, in the local time zone.
+func Time(dt wallclock.DateTime) time.Time {
+	return time.Unix(int64(dt.Seconds), int64(dt.Nanoseconds))
+}
+
+// DateTime returns the [wallclock.DateTime] for t. Times before the Unix epoch,
+// which cannot be represented, return the zero DateTime.
+func DateTime(t time.Time) wallclock.DateTime {
+	if t.Unix() < 0 {
+		return wallclock.DateTime{}
+	}
+	return wallclock.DateTime{Seconds: uint64(t.Unix()), Nanoseconds: uint32(t.Nanosecond())}
+}
+
+// Duration returns the [time.Duration] for [wallclock.DateTime] dt
+// interpreted as a duration, such as the result of [wallclock.Resolution].
+func Duration(dt wallclock.DateTime) time.Duration {
+	return time.Duration(dt.Seconds)*time.Second + time.Duration(dt.Nanoseconds)
+}
+
+// Instant is an instant of the WASI monotonic clock, in nanoseconds since an unspecified
+// starting point. Instants are only meaningful relative to other instants.
+type Instant monotonicclock.Instant
+
+// Monotonic returns the current instant of the WASI monotonic clock.
+func Monotonic() Instant {
+	return Instant(monotonicclock.Now())
+}
+
+// MonotonicResolution returns the resolution of the WASI monotonic clock.
+func MonotonicResolution() time.Duration {
+	return time.Duration(monotonicclock.Resolution())
+}
+
+// Add returns the instant i+d. Instants before the starting point of the clock are clamped to 0.
+func (i Instant) Add(d time.Duration) Instant {
+	if d < 0 && uint64(-d) > uint64(i) {
+		return 0
+	}
+	return i + Instant(d)
+}
+
+// Sub returns the duration i-j.
+func (i Instant) Sub(j Instant) time.Duration {
+	return time.Duration(i - j)
+}
+
+// Elapsed returns the duration elapsed since i, according to the WASI monotonic clock.
+func (i Instant) Elapsed() time.Duration {
+	return Monotonic().Sub(i)
+}
+
+// Sleep blocks the program for at least duration d.
+// Unlike [time.Sleep], it blocks all goroutines.
+func Sleep(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	pollable := monotonicclock.SubscribeDuration(monotonicclock.Duration(d))
+	pollable.Block()
+	pollable.ResourceDrop()
+}
+
+// SleepContext waits for at least duration d, or until ctx is done,
+// returning ctx.Err(). See [wasipoll.Wait].
+func SleepContext(ctx context.Context, d time.Duration) error {
+	t := NewTimer(d)
+	defer t.Stop()
+	return t.Wait(ctx)
+}
+
+// Timer is a single event at an instant of the WASI monotonic clock.
+// Its [Timer.Pollable] is ready when the timer expires.
+// Stop the timer to release its pollable.
+type Timer struct {
+	pollable poll.Pollable
+	deadline Instant
+}
+
+// NewTimer returns a [Timer] that expires after duration d.
+func NewTimer(d time.Duration) *Timer {
+	t := &Timer{}
+	t.Reset(d)
+	return t
+}
+
+// Deadline returns the instant at which t expires.
+func (t *Timer) Deadline() Instant {
+	return t.deadline
+}
+
+// Pollable returns the pollable of t, which is ready when t expires.
+// It is dropped by [Timer.Stop] or [Timer.Reset].
+func (t *Timer) Pollable() poll.Pollable {
+	return t.pollable
+}
+
+// Ready returns true if t has expired.
+func (t *Timer) Ready() bool {
+	return t.pollable != 0 && t.pollable.Ready()
+}
+
+// Wait blocks until t expires or ctx is done, returning ctx.Err(). See [wasipoll.Wait].
+func (t *Timer) Wait(ctx context.Context) error {
+	if t.pollable == 0 {
+		return nil
+	}
+	return wasipoll.Wait(ctx, t.pollable)
+}
+
+// Reset changes t to expire after duration d, replacing its pollable.
+func (t *Timer) Reset(d time.Duration) {
+	t.Stop()
+	t.deadline = Monotonic().Add(d)
+	t.pollable = monotonicclock.SubscribeInstant(monotonicclock.Instant(t.deadline))
+}
+
+// Stop drops the pollable of t. A stopped timer is never ready, and Wait returns immediately.
+// Stop may be called more than once.
+func (t *Timer) Stop() {
+	if t.pollable != 0 {
+		t.pollable.ResourceDrop()
+		t.pollable = 0
+	}
+}
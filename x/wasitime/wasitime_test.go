@@ -0,0 +1,57 @@
+package wasitime
+
+import (
+	"testing"
+	"time"
+
+	wallclock "github.com/bytecodealliance/wasm-tools-go/x/wasi/clocks/wall-clock"
+)
+
+func TestDateTime(t *testing.T) {
+	tests := []struct {
+		t    time.Time
+		want wallclock.DateTime
+	}{
+		{time.Unix(0, 0), wallclock.DateTime{}},
+		{time.Unix(1700000000, 123456789), wallclock.DateTime{Seconds: 1700000000, Nanoseconds: 123456789}},
+		{time.Date(2024, 2, 29, 12, 0, 0, 5, time.UTC), wallclock.DateTime{Seconds: 1709208000, Nanoseconds: 5}},
+	}
+	for _, tt := range tests {
+		got := DateTime(tt.t)
+		if got.Seconds != tt.want.Seconds || got.Nanoseconds != tt.want.Nanoseconds {
+			t.Errorf("DateTime(%v): %+v, expected %+v", tt.t, got, tt.want)
+		}
+		if tm := Time(got); !tm.Equal(tt.t) {
+			t.Errorf("Time(%+v): %v, expected %v", got, tm, tt.t)
+		}
+	}
+	if got := DateTime(time.Unix(-1, 0)); got.Seconds != 0 || got.Nanoseconds != 0 {
+		t.Errorf("DateTime(before epoch): %+v, expected zero", got)
+	}
+}
+
+func TestDuration(t *testing.T) {
+	dt := wallclock.DateTime{Seconds: 2, Nanoseconds: 500}
+	if got, want := Duration(dt), 2*time.Second+500*time.Nanosecond; got != want {
+		t.Errorf("Duration(%+v): %v, expected %v", dt, got, want)
+	}
+}
+
+func TestInstant(t *testing.T) {
+	i := Instant(10 * time.Second)
+	if got := i.Add(time.Second); got != Instant(11*time.Second) {
+		t.Errorf("Add(1s): %d", got)
+	}
+	if got := i.Add(-time.Second); got != Instant(9*time.Second) {
+		t.Errorf("Add(-1s): %d", got)
+	}
+	if got := i.Add(-time.Minute); got != 0 {
+		t.Errorf("Add(-1m): %d, expected 0", got)
+	}
+	if got := i.Sub(Instant(4 * time.Second)); got != 6*time.Second {
+		t.Errorf("Sub(): %v, expected 6s", got)
+	}
+	if got := Instant(time.Second).Sub(i); got != -9*time.Second {
+		t.Errorf("Sub(): %v, expected -9s", got)
+	}
+}